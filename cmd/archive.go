@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	clog "github.com/charmbracelet/log"
+	"github.com/jmcampanini/grove-cli/internal/pathutil"
+	"github.com/jmcampanini/grove-cli/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// archiveRefPrefix is the ref namespace that keeps the commits of archived branches reachable.
+const archiveRefPrefix = "refs/grove/archive/"
+
+var archiveTableHeaders = []string{"BRANCH", "COMMIT", "PATH", "ARCHIVED"}
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive <name>",
+	Short: "Remove a worktree and its branch, keeping a copy to restore",
+	Long: `Archive removes a worktree you are done with, without losing its work. The branch's
+commit is saved under refs/grove/archive/<branch>, the archive is recorded in grove's state,
+and then the worktree and its branch are removed. The worktree is resolved the same way as
+grove open.
+
+A worktree with modified or untracked files is not archived, since those are not part of
+the branch: commit or remove them first.
+
+grove archive restore brings an archived worktree back at its old path, with its branch,
+upstream, and pin. grove archive list shows the archives.
+
+Example:
+  grove archive add-auth
+  grove archive list
+  grove archive restore add-auth`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeFirstArg(requirements{NeedsRepo: true}, completeWorktreeNames),
+	RunE:              withDeps(requirements{Mutating: true, NeedsRepo: true}, runArchive),
+}
+
+var archiveListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List archived worktrees",
+	Args:  cobra.NoArgs,
+	RunE:  withDeps(requirements{NeedsRepo: true}, runArchiveList),
+}
+
+var archiveRestoreCmd = &cobra.Command{
+	Use:   "restore <branch>",
+	Short: "Bring back an archived worktree",
+	Long: `Restore recreates an archived worktree at its old path, recreates its branch at the
+archived commit, sets its upstream again if the remote branch still exists, and forgets
+the archive.
+
+Example:
+  grove archive restore add-auth`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeFirstArg(requirements{NeedsRepo: true}, completeArchives),
+	RunE:              withDeps(requirements{Mutating: true, NeedsRepo: true}, runArchiveRestore),
+}
+
+func init() {
+	archiveCmd.AddCommand(archiveListCmd)
+	archiveCmd.AddCommand(archiveRestoreCmd)
+	rootCmd.AddCommand(archiveCmd)
+}
+
+func runArchive(cmd *cobra.Command, args []string, deps *Deps) error {
+	unlock, err := lockWorktrees(deps)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	wt, err := resolveWorktree(deps, args[0])
+	if err != nil {
+		return err
+	}
+	if wt.IsMain {
+		return errors.New("the main worktree cannot be archived")
+	}
+	if pathutil.Equal(wt.AbsolutePath, deps.WorktreeRoot) {
+		return errors.New("cannot archive the current worktree; run grove archive from another worktree")
+	}
+	branch, ok := wt.Ref.FullBranch()
+	if !ok || wt.BranchMissing {
+		return fmt.Errorf("%s has no branch to archive", wt.AbsolutePath)
+	}
+
+	st, err := deps.State.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	if _, exists := st.Archives[branch.Name]; exists {
+		return fmt.Errorf("%s is already archived; restore it with grove archive restore %s first", branch.Name, branch.Name)
+	}
+	sha, err := deps.Git.ResolveRef(deps.Ctx, branch.Name)
+	if err != nil {
+		return err
+	}
+
+	ref := archiveRefPrefix + branch.Name
+	if err := deps.Git.UpdateRef(deps.Ctx, ref, sha); err != nil {
+		return err
+	}
+	if err := deps.Git.RemoveWorktree(deps.Ctx, wt.AbsolutePath, false); err != nil {
+		if refErr := deps.Git.DeleteRef(deps.Ctx, ref); refErr != nil {
+			clog.Default().Warn("failed to delete archive ref", "ref", ref, "error", refErr)
+		}
+		return err
+	}
+
+	st.SetArchive(branch.Name, state.Archive{
+		ArchivedAt: deps.Clock().UTC(),
+		Path:       wt.AbsolutePath,
+		SHA:        sha,
+		Upstream:   branch.UpstreamName,
+		Worktree:   st.Get(wt.AbsolutePath),
+	})
+	st.Set(wt.AbsolutePath, state.Worktree{})
+	if err := deps.State.Save(st); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	// the archive ref keeps the commits, so the branch can go even if it is not merged
+	if err := deps.Git.DeleteBranch(deps.Ctx, branch.Name, true); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(cmd.OutOrStdout(), "Archived %s (%s) from %s\n", branch.Name, shortSHASafe(sha, 7), wt.AbsolutePath)
+	return err
+}
+
+func runArchiveList(cmd *cobra.Command, _ []string, deps *Deps) error {
+	st, err := deps.State.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	if len(st.Archives) == 0 {
+		_, err := fmt.Fprintln(cmd.ErrOrStderr(), "No archived worktrees")
+		return err
+	}
+
+	names := make([]string, 0, len(st.Archives))
+	for name := range st.Archives {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	now := deps.Clock()
+	rows := make([][]string, 0, len(names))
+	for _, name := range names {
+		a := st.Archives[name]
+		rows = append(rows, []string{name, shortSHASafe(a.SHA, 7), a.Path, formatRelativeTime(a.ArchivedAt, now)})
+	}
+	_, err = fmt.Fprint(cmd.OutOrStdout(), renderTable(archiveTableHeaders, rows, useColor(cmd.OutOrStdout())))
+	return err
+}
+
+func runArchiveRestore(cmd *cobra.Command, args []string, deps *Deps) error {
+	unlock, err := lockWorktrees(deps)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	name := args[0]
+	st, err := deps.State.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	a, ok := st.Archives[name]
+	if !ok {
+		return fmt.Errorf("no archive named %q; run 'grove archive list' to see archives", name)
+	}
+
+	ref := archiveRefPrefix + name
+	if err := deps.Git.CreateWorktreeForNewBranchFromRef(deps.Ctx, name, a.Path, ref); err != nil {
+		return err
+	}
+	if a.Upstream != "" {
+		if err := deps.Git.SetBranchUpstream(deps.Ctx, name, a.Upstream); err != nil {
+			clog.Default().Warn("failed to restore upstream", "branch", name, "upstream", a.Upstream, "error", err)
+		}
+	}
+
+	st.RemoveArchive(name)
+	entry := a.Worktree
+	entry.Conflict = state.Conflict{}
+	st.Set(a.Path, entry)
+	if err := deps.State.Save(st); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	if err := deps.Git.DeleteRef(deps.Ctx, ref); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), a.Path)
+	return err
+}
+
+// completeArchives returns the names of the archived branches, described by their old path.
+func completeArchives(deps *Deps) ([]string, error) {
+	st, err := deps.State.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state: %w", err)
+	}
+	candidates := make([]string, 0, len(st.Archives))
+	for name, a := range st.Archives {
+		candidates = append(candidates, name+"\t"+a.Path)
+	}
+	slices.Sort(candidates)
+	return candidates, nil
+}
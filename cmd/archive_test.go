@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/git/fake"
+	"github.com/jmcampanini/grove-cli/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newArchiveTestGit() *fake.Git {
+	return newTestGit().
+		AddBranch("feature/auth", git.NewCommit("bbb2222", "Auth", testNow, "user")).
+		AddWorktree("/ws/auth", "feature/auth").
+		AddRemoteRef("origin", "feature/auth", git.NewCommit("bbb2222", "Auth", testNow, "user")).
+		SetUpstream("feature/auth", "origin/feature/auth", 0, 0)
+}
+
+func TestRunArchive_RoundTrip(t *testing.T) {
+	g := newArchiveTestGit()
+	deps := newTestDeps(g)
+	require.NoError(t, updateWorktreeState(deps, "/ws/auth", func(entry *state.Worktree) {
+		entry.Origin = state.OriginCreate
+		entry.Pinned = true
+	}))
+	cmd, out := newTestCommand()
+
+	require.NoError(t, runArchive(cmd, []string{"auth"}, deps))
+
+	assert.Equal(t, "Archived feature/auth (bbb2222) from /ws/auth\n", out.String())
+	worktrees, err := g.ListWorktrees(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, worktrees, 1)
+	exists, err := g.BranchExists(t.Context(), "feature/auth", false)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	sha, err := g.ResolveRef(t.Context(), "refs/grove/archive/feature/auth")
+	require.NoError(t, err)
+	assert.Equal(t, "bbb2222", sha)
+	st, err := deps.State.Load()
+	require.NoError(t, err)
+	assert.Equal(t, state.Archive{
+		ArchivedAt: testNow,
+		Path:       "/ws/auth",
+		SHA:        "bbb2222",
+		Upstream:   "origin/feature/auth",
+		Worktree:   state.Worktree{Origin: state.OriginCreate, Pinned: true},
+	}, st.Archives["feature/auth"])
+	assert.NotContains(t, st.Worktrees, "/ws/auth")
+
+	cmd, out = newTestCommand()
+	require.NoError(t, runArchiveRestore(cmd, []string{"feature/auth"}, deps))
+
+	assert.Equal(t, "/ws/auth\n", out.String())
+	branches, err := g.ListLocalBranches(t.Context())
+	require.NoError(t, err)
+	require.Len(t, branches, 2)
+	assert.Equal(t, "feature/auth", branches[0].Name)
+	assert.Equal(t, "/ws/auth", branches[0].WorktreeAbsolutePath)
+	assert.Equal(t, "origin/feature/auth", branches[0].UpstreamName)
+	_, err = g.ResolveRef(t.Context(), "refs/grove/archive/feature/auth")
+	assert.Error(t, err, "the archive ref is deleted")
+	st, err = deps.State.Load()
+	require.NoError(t, err)
+	assert.Empty(t, st.Archives)
+	assert.True(t, st.IsPinned("/ws/auth"))
+}
+
+func TestRunArchive_Refused(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		setup   func(g *fake.Git, deps *Deps)
+		wantErr string
+	}{
+		{
+			name:    "main worktree",
+			target:  "/ws/main",
+			wantErr: "the main worktree cannot be archived",
+		},
+		{
+			name:    "current worktree",
+			target:  "auth",
+			setup:   func(_ *fake.Git, deps *Deps) { deps.WorktreeRoot = "/ws/auth" },
+			wantErr: "cannot archive the current worktree",
+		},
+		{
+			name:    "modified or untracked files",
+			target:  "auth",
+			setup:   func(g *fake.Git, _ *Deps) { g.SetDirty("/ws/auth") },
+			wantErr: "contains modified or untracked files",
+		},
+		{
+			name:   "already archived",
+			target: "auth",
+			setup: func(_ *fake.Git, deps *Deps) {
+				st := state.New()
+				st.SetArchive("feature/auth", state.Archive{Path: "/ws/old-auth", SHA: "aaa1111"})
+				require.NoError(t, deps.State.Save(st))
+			},
+			wantErr: "feature/auth is already archived",
+		},
+		{
+			name:    "unknown worktree",
+			target:  "missing",
+			wantErr: `no worktree matches "missing"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newArchiveTestGit()
+			deps := newTestDeps(g)
+			if tt.setup != nil {
+				tt.setup(g, deps)
+			}
+			cmd, _ := newTestCommand()
+
+			err := runArchive(cmd, []string{tt.target}, deps)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			worktrees, err := g.ListWorktrees(t.Context())
+			require.NoError(t, err)
+			assert.Len(t, worktrees, 2, "the worktree is kept")
+			_, err = g.ResolveRef(t.Context(), "refs/grove/archive/feature/auth")
+			assert.Error(t, err, "no archive ref is left behind")
+		})
+	}
+}
+
+func TestRunArchiveList(t *testing.T) {
+	deps := newTestDeps(newTestGit())
+	cmd, out := newTestCommand()
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+
+	require.NoError(t, runArchiveList(cmd, nil, deps))
+	assert.Empty(t, out.String())
+	assert.Equal(t, "No archived worktrees\n", stderr.String())
+
+	st := state.New()
+	st.SetArchive("fix/login", state.Archive{ArchivedAt: testNow.Add(-2 * time.Hour), Path: "/ws/login", SHA: "ccc3333def"})
+	st.SetArchive("feature/auth", state.Archive{ArchivedAt: testNow.Add(-72 * time.Hour), Path: "/ws/auth", SHA: "bbb2222def"})
+	require.NoError(t, deps.State.Save(st))
+
+	require.NoError(t, runArchiveList(cmd, nil, deps))
+	assert.Equal(t, `BRANCH        COMMIT   PATH       ARCHIVED
+feature/auth  bbb2222  /ws/auth   3d ago
+fix/login     ccc3333  /ws/login  2h ago
+`, out.String())
+}
+
+func TestRunArchiveRestore_Unknown(t *testing.T) {
+	deps := newTestDeps(newTestGit())
+	cmd, _ := newTestCommand()
+
+	err := runArchiveRestore(cmd, []string{"feature/auth"}, deps)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `no archive named "feature/auth"`)
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jmcampanini/grove-cli/internal/naming"
+	"github.com/spf13/cobra"
+)
+
+var auditNamesCmd = &cobra.Command{
+	Use:   "audit-names",
+	Short: "Report branches that would get the same worktree name",
+	Long: `Audit-names runs every local and remote branch through the worktree naming grove create
+uses and reports the branches that would get the same worktree name under the current
+config: [worktree] new_prefix and strip_branch_prefix, and the [slugify] settings. Branches
+whose name slugifies to nothing are reported too. Nothing is created.
+
+Try other settings without editing the config using --set:
+  grove audit-names --set slugify.max_length=40 --set slugify.hash_length=6
+
+Exits with an error if any names collide.`,
+	Args: cobra.NoArgs,
+	RunE: withDeps(requirements{NeedsRepo: true}, runAuditNames),
+}
+
+func init() {
+	rootCmd.AddCommand(auditNamesCmd)
+}
+
+func runAuditNames(cmd *cobra.Command, _ []string, deps *Deps) error {
+	branches, err := listAllBranchNames(deps)
+	if err != nil {
+		return err
+	}
+
+	namer, err := newWorktreeNamer(deps)
+	if err != nil {
+		return err
+	}
+	collisions := worktreeNameCollisions(namer, branches)
+	if len(collisions) == 0 {
+		_, err := fmt.Fprintf(cmd.OutOrStdout(), "No collisions among %d branches\n", len(branches))
+		return err
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	for _, name := range slices.Sorted(maps.Keys(collisions)) {
+		label := name
+		if label == "" {
+			label = "(empty)"
+		}
+		if _, err := fmt.Fprintf(w, "%s %s\t%s\n", checkFail.symbol(), label, strings.Join(collisions[name], ", ")); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return fmt.Errorf("%d worktree name(s) collide among %d branches", len(collisions), len(branches))
+}
+
+// listAllBranchNames returns the names of the local branches and of every remote's branches
+// (without the remote name), sorted and without duplicates.
+func listAllBranchNames(deps *Deps) ([]string, error) {
+	locals, err := deps.Git.ListLocalBranches(deps.Ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list local branches: %w", err)
+	}
+	names := map[string]bool{}
+	for _, b := range locals {
+		names[b.Name] = true
+	}
+
+	remotes, err := deps.Git.ListRemotes(deps.Ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remotes: %w", err)
+	}
+	for _, remote := range remotes {
+		branches, err := deps.Git.ListRemoteBranches(deps.Ctx, remote)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list branches of %s: %w", remote, err)
+		}
+		for _, b := range branches {
+			names[b.Name] = true
+		}
+	}
+	return slices.Sorted(maps.Keys(names)), nil
+}
+
+// worktreeNameCollisions returns the worktree names that more than one branch maps to, with those branches
+// in order. The empty name is reported for any branch, since such a branch cannot get a worktree at all.
+func worktreeNameCollisions(namer *naming.WorktreeNamer, branches []string) map[string][]string {
+	byName := map[string][]string{}
+	for _, branch := range branches {
+		name := namer.Generate(branch)
+		byName[name] = append(byName[name], branch)
+	}
+	for name, names := range byName {
+		if len(names) < 2 && name != "" {
+			delete(byName, name)
+		}
+	}
+	return byName
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/config"
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunAuditNames(t *testing.T) {
+	commit := git.NewCommit("bbb2222", "Work", testNow, "user")
+
+	tests := []struct {
+		name       string
+		slugify    func(cfg *config.SlugifyConfig)
+		wantErr    string
+		wantOutput string
+	}{
+		{
+			name: "separators collide",
+			wantOutput: "✗ (empty)    ---\n" +
+				"✗ fix-login  fix-login, fix/login, fix_login\n",
+			wantErr: "2 worktree name(s) collide among 7 branches",
+		},
+		{
+			name: "truncation collides without a hash",
+			slugify: func(cfg *config.SlugifyConfig) {
+				cfg.HashLength = 0
+				cfg.MaxLength = 8
+			},
+			// feature/ is stripped by the default [worktree] strip_branch_prefix
+			wantOutput: "✗ (empty)   ---\n" +
+				"✗ fix-log-  fix-login, fix/login, fix_login\n" +
+				"✗ long-na-  feature/long-name-one, feature/long-name-two\n",
+			wantErr: "3 worktree name(s) collide among 7 branches",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGit().
+				AddBranch("fix/login", commit).
+				AddBranch("fix_login", commit).
+				AddBranch("---", commit).
+				AddBranch("feature/long-name-one", commit).
+				AddRemoteRef("origin", "fix-login", commit).
+				AddRemoteRef("origin", "feature/long-name-two", commit).
+				AddRemoteRef("origin", "main", commit)
+			deps := newTestDeps(g)
+			deps.Config.Worktree.NewPrefix = ""
+			if tt.slugify != nil {
+				tt.slugify(&deps.Config.Slugify)
+			}
+			cmd, out := newTestCommand()
+
+			err := runAuditNames(cmd, nil, deps)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Equal(t, tt.wantOutput, out.String())
+		})
+	}
+}
+
+func TestRunAuditNames_NoCollisions(t *testing.T) {
+	g := newTestGit().AddBranch("feature/add-auth", git.NewCommit("bbb2222", "Work", testNow, "user"))
+	cmd, out := newTestCommand()
+
+	require.NoError(t, runAuditNames(cmd, nil, newTestDeps(g)))
+
+	assert.Equal(t, "No collisions among 2 branches\n", out.String())
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// browserCommand returns the command line that opens url in the user's browser on goos:
+// $BROWSER when set, as gh does, and otherwise the platform's URL opener.
+func browserCommand(goos, url string) []string {
+	if browser := strings.TrimSpace(os.Getenv("BROWSER")); browser != "" {
+		return append(strings.Fields(browser), url)
+	}
+	switch goos {
+	case "darwin":
+		return []string{"open", url}
+	case "windows":
+		return []string{"rundll32", "url.dll,FileProtocolHandler", url}
+	default:
+		return []string{"xdg-open", url}
+	}
+}
+
+// openBrowser opens url in the user's browser.
+func openBrowser(deps *Deps, url string) error {
+	argv := browserCommand(runtime.GOOS, url)
+	if err := deps.Exec(argv[0], argv[1:]...); err != nil {
+		return fmt.Errorf("failed to open %s with %s: %w", url, argv[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBrowserCommand(t *testing.T) {
+	const url = "https://github.com/acme/webapp/pull/42"
+
+	tests := []struct {
+		name    string
+		browser string
+		goos    string
+		want    []string
+	}{
+		{name: "macOS", goos: "darwin", want: []string{"open", url}},
+		{name: "linux", goos: "linux", want: []string{"xdg-open", url}},
+		{name: "windows", goos: "windows", want: []string{"rundll32", "url.dll,FileProtocolHandler", url}},
+		{name: "BROWSER wins", browser: "firefox --new-tab", goos: "darwin", want: []string{"firefox", "--new-tab", url}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BROWSER", tt.browser)
+
+			assert.Equal(t, tt.want, browserCommand(tt.goos, url))
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	clog "github.com/charmbracelet/log"
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// staleLockAge is how old an index.lock must be before grove assumes the git process that took it crashed.
+const staleLockAge = time.Hour
+
+var (
+	checkAllFlag bool
+	checkFixFlag bool
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check [<worktree>|--all]",
+	Short: "Check the health of worktrees",
+	Long: `Check verifies a worktree, or every worktree with --all:
+
+  gitdir      the worktree's .git link leads to the repository
+  branch      the checked-out branch still exists
+  upstream    the branch's upstream tracking branch still exists
+  lock        no index.lock was left behind by a crashed git process
+  submodules  all submodules are initialized
+
+Each problem is reported with a suggested fix. With --fix, grove applies the safe repairs:
+it runs git worktree repair for broken links, removes index.lock files older than an hour,
+and initializes submodules. Problems that could lose work are only reported.
+
+Exits with an error if any check fails after fixes are applied.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeFirstArg(requirements{NeedsRepo: true}, completeWorktreeNames),
+	RunE:              withDeps(requirements{EmitsEvents: true, Mutating: true, NeedsRepo: true, Summary: true}, runCheck),
+}
+
+func init() {
+	checkCmd.Flags().BoolVar(&checkAllFlag, "all", false, "Check every worktree")
+	checkCmd.Flags().BoolVar(&checkFixFlag, "fix", false, "Apply safe repairs")
+	rootCmd.AddCommand(checkCmd)
+}
+
+// worktreeReport holds the checks run on one worktree.
+type worktreeReport struct {
+	Checks   []doctorCheck
+	Fixed    int
+	Worktree git.Worktree
+}
+
+// status returns the worst status among the report's checks.
+func (r worktreeReport) status() checkStatus {
+	worst := checkPass
+	for _, c := range r.Checks {
+		worst = max(worst, c.Status)
+	}
+	return worst
+}
+
+func runCheck(cmd *cobra.Command, args []string, deps *Deps) error {
+	if checkAllFlag == (len(args) == 1) {
+		return errors.New("specify a worktree or --all")
+	}
+
+	var worktrees []git.Worktree
+	if checkAllFlag {
+		all, err := deps.Git.ListWorktrees(deps.Ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list worktrees: %w", err)
+		}
+		worktrees = all
+	} else {
+		wt, err := resolveWorktree(deps, args[0])
+		if err != nil {
+			return err
+		}
+		worktrees = []git.Worktree{wt}
+	}
+
+	if err := deps.Events.Started(len(worktrees)); err != nil {
+		return err
+	}
+
+	var reports []worktreeReport
+	failed, warned, fixed := 0, 0, 0
+	for _, wt := range worktrees {
+		r := checkWorktree(deps, wt, checkFixFlag)
+		switch r.status() {
+		case checkFail:
+			failed++
+		case checkWarn:
+			warned++
+		}
+		fixed += r.Fixed
+		if err := reportItem(deps, wt.AbsolutePath, r.status().String(), describeProblems(r.Checks)); err != nil {
+			return err
+		}
+		reports = append(reports, r)
+	}
+
+	if deps.Events == nil {
+		if err := printWorktreeReports(cmd, reports); err != nil {
+			return err
+		}
+		summary := fmt.Sprintf("Checked %d worktree(s): %d failed, %d with warnings, %d problem(s) fixed", len(reports), failed, warned, fixed)
+		if _, err := fmt.Fprintln(cmd.OutOrStdout(), summary); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d worktree(s) failed checks", failed)
+	}
+	return nil
+}
+
+// describeProblems joins the details of the checks that did not pass, or returns "healthy".
+func describeProblems(checks []doctorCheck) string {
+	var problems []string
+	for _, c := range checks {
+		if c.Status != checkPass {
+			problems = append(problems, c.Name+": "+c.Detail)
+		}
+	}
+	if len(problems) == 0 {
+		return "healthy"
+	}
+	return strings.Join(problems, "; ")
+}
+
+func printWorktreeReports(cmd *cobra.Command, reports []worktreeReport) error {
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	for _, r := range reports {
+		if _, err := fmt.Fprintf(w, "%s %s\n", r.status().symbol(), r.Worktree.AbsolutePath); err != nil {
+			return err
+		}
+		for _, c := range r.Checks {
+			if _, err := fmt.Fprintf(w, "  %s\t%s\t%s\n", c.Status.symbol(), c.Name, c.Detail); err != nil {
+				return err
+			}
+			if c.Fix != "" {
+				if _, err := fmt.Fprintf(w, "  \t\tfix: %s\n", c.Fix); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return w.Flush()
+}
+
+// checkWorktree runs every health check on a worktree, repairing what it safely can when fix is set.
+// Checks that need a working .git link are skipped when the link is broken.
+func checkWorktree(deps *Deps, wt git.Worktree, fix bool) worktreeReport {
+	r := worktreeReport{Worktree: wt}
+	if wt.IsBare {
+		r.Checks = append(r.Checks, doctorCheck{Detail: "bare repository; nothing to check", Name: "gitdir"})
+		return r
+	}
+	add := func(c doctorCheck, wasFixed bool) {
+		r.Checks = append(r.Checks, c)
+		if wasFixed {
+			r.Fixed++
+		}
+	}
+
+	link, gitDir, wasFixed := checkWorktreeLink(deps, wt, fix)
+	add(link, wasFixed)
+	add(checkWorktreeBranch(wt), false)
+	add(checkWorktreeUpstream(deps, wt), false)
+	if link.Status == checkFail {
+		return r
+	}
+	c, wasFixed := checkWorktreeLock(deps, gitDir, fix)
+	add(c, wasFixed)
+	c, wasFixed = checkWorktreeSubmodules(deps, wt, fix)
+	add(c, wasFixed)
+	return r
+}
+
+// checkWorktreeLink checks that the worktree's .git link is intact and returns its git dir.
+func checkWorktreeLink(deps *Deps, wt git.Worktree, fix bool) (doctorCheck, string, bool) {
+	c := doctorCheck{Name: "gitdir"}
+	if wt.Prunable != "" {
+		c.Status, c.Detail, c.Fix = checkFail, wt.Prunable, "if the directory is gone for good, run git worktree prune"
+		return c, "", false
+	}
+
+	gitDir, err := deps.Git.GetWorktreeGitDir(deps.Ctx, wt.AbsolutePath)
+	if err == nil {
+		c.Detail = gitDir
+		return c, gitDir, false
+	}
+	if !fix {
+		c.Status, c.Detail, c.Fix = checkFail, "the .git link is broken", "run grove check --fix (git worktree repair)"
+		return c, "", false
+	}
+	if err := deps.Git.RepairWorktree(deps.Ctx, wt.AbsolutePath); err != nil {
+		c.Status, c.Detail, c.Fix = checkFail, err.Error(), "run git worktree repair from the main worktree"
+		return c, "", false
+	}
+	if gitDir, err = deps.Git.GetWorktreeGitDir(deps.Ctx, wt.AbsolutePath); err != nil {
+		c.Status, c.Detail = checkFail, "still broken after git worktree repair"
+		return c, "", false
+	}
+	c.Detail = "repaired the .git link"
+	return c, gitDir, true
+}
+
+func checkWorktreeBranch(wt git.Worktree) doctorCheck {
+	c := doctorCheck{Name: "branch"}
+	branch, ok := wt.Ref.FullBranch()
+	switch {
+	case !ok:
+		c.Detail = "detached HEAD"
+	case wt.BranchMissing:
+		c.Status = checkFail
+		c.Detail = fmt.Sprintf("branch %s does not exist", branch.Name)
+		c.Fix = fmt.Sprintf("commit to recreate it, or check out another branch in %s", wt.AbsolutePath)
+	default:
+		c.Detail = branch.Name
+	}
+	return c
+}
+
+func checkWorktreeUpstream(deps *Deps, wt git.Worktree) doctorCheck {
+	c := doctorCheck{Name: "upstream"}
+	branch, ok := wt.Ref.FullBranch()
+	if !ok || branch.UpstreamName == "" {
+		c.Detail = "none"
+		return c
+	}
+	if _, err := deps.Git.ResolveRef(deps.Ctx, branch.UpstreamName); err != nil {
+		c.Status = checkWarn
+		c.Detail = fmt.Sprintf("%s no longer exists", branch.UpstreamName)
+		c.Fix = fmt.Sprintf("push the branch again, or run git branch --unset-upstream %s", branch.Name)
+		return c
+	}
+	c.Detail = branch.UpstreamName
+	return c
+}
+
+// checkWorktreeLock looks for an index.lock in the worktree's git dir. A recent lock may belong to a running
+// git process and is only reported; one older than staleLockAge is removed with fix.
+func checkWorktreeLock(deps *Deps, gitDir string, fix bool) (doctorCheck, bool) {
+	c := doctorCheck{Name: "lock"}
+	lockPath := filepath.Join(gitDir, "index.lock")
+	info, err := os.Stat(lockPath)
+	if errors.Is(err, os.ErrNotExist) {
+		c.Detail = "no index.lock"
+		return c, false
+	}
+	if err != nil {
+		c.Status, c.Detail = checkFail, err.Error()
+		return c, false
+	}
+
+	age := deps.Clock().Sub(info.ModTime()).Round(time.Second)
+	if age < staleLockAge {
+		c.Status = checkWarn
+		c.Detail = fmt.Sprintf("index.lock is %s old; a git process may still be running", age)
+		return c, false
+	}
+	if !fix {
+		c.Status = checkFail
+		c.Detail = fmt.Sprintf("stale index.lock from %s ago", age)
+		c.Fix = "run grove check --fix to remove " + lockPath
+		return c, false
+	}
+	if dryRunFlag {
+		clog.Default().Info("Would remove stale lock", "path", lockPath)
+	} else if err := os.Remove(lockPath); err != nil {
+		c.Status, c.Detail = checkFail, err.Error()
+		return c, false
+	}
+	c.Detail = "removed stale index.lock"
+	return c, true
+}
+
+func checkWorktreeSubmodules(deps *Deps, wt git.Worktree, fix bool) (doctorCheck, bool) {
+	c := doctorCheck{Name: "submodules"}
+	missing, err := deps.Git.ListUninitializedSubmodules(deps.Ctx, wt.AbsolutePath)
+	if err != nil {
+		c.Status, c.Detail = checkFail, err.Error()
+		return c, false
+	}
+	if len(missing) == 0 {
+		c.Detail = "initialized"
+		return c, false
+	}
+	if !fix {
+		c.Status = checkFail
+		c.Detail = "not initialized: " + strings.Join(missing, ", ")
+		c.Fix = "run grove check --fix (git submodule update --init)"
+		return c, false
+	}
+	if err := deps.Git.InitSubmodules(deps.Ctx, wt.AbsolutePath); err != nil {
+		c.Status, c.Detail = checkFail, err.Error()
+		return c, false
+	}
+	c.Detail = "initialized " + strings.Join(missing, ", ")
+	return c, true
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/git/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		all        bool
+		fix        bool
+		setup      func(g *fake.Git)
+		wantErr    string
+		wantOutput []string
+	}{
+		{
+			name:       "healthy worktree",
+			args:       []string{"feature"},
+			wantOutput: []string{"✓ /ws/feature", "/ws/main/.git/worktrees/feature", "origin/main", "Checked 1 worktree(s): 0 failed, 0 with warnings, 0 problem(s) fixed"},
+		},
+		{
+			name:       "all worktrees",
+			all:        true,
+			wantOutput: []string{"✓ /ws/main", "✓ /ws/feature", "Checked 2 worktree(s)"},
+		},
+		{
+			name:    "neither worktree nor --all",
+			wantErr: "specify a worktree or --all",
+		},
+		{
+			name:    "both worktree and --all",
+			args:    []string{"feature"},
+			all:     true,
+			wantErr: "specify a worktree or --all",
+		},
+		{
+			name:       "broken link",
+			args:       []string{"feature"},
+			setup:      func(g *fake.Git) { g.SetGitLinkBroken("/ws/feature") },
+			wantErr:    "1 worktree(s) failed checks",
+			wantOutput: []string{"✗ /ws/feature", "the .git link is broken", "fix: run grove check --fix"},
+		},
+		{
+			name:       "broken link fixed",
+			args:       []string{"feature"},
+			fix:        true,
+			setup:      func(g *fake.Git) { g.SetGitLinkBroken("/ws/feature") },
+			wantOutput: []string{"repaired the .git link", "0 failed, 0 with warnings, 1 problem(s) fixed"},
+		},
+		{
+			name:       "prunable worktree",
+			args:       []string{"feature"},
+			fix:        true,
+			setup:      func(g *fake.Git) { g.SetPrunable("/ws/feature", "gitdir file points to non-existent location") },
+			wantErr:    "1 worktree(s) failed checks",
+			wantOutput: []string{"gitdir file points to non-existent location", "fix: if the directory is gone for good, run git worktree prune"},
+		},
+		{
+			name:       "missing branch",
+			args:       []string{"feature"},
+			setup:      func(g *fake.Git) { g.DeleteBranchRef("feature") },
+			wantErr:    "1 worktree(s) failed checks",
+			wantOutput: []string{"branch feature does not exist"},
+		},
+		{
+			name:       "upstream gone",
+			args:       []string{"feature"},
+			setup:      func(g *fake.Git) { g.SetUpstream("feature", "origin/feature", 0, 0) },
+			wantOutput: []string{"! /ws/feature", "origin/feature no longer exists", "0 failed, 1 with warnings"},
+		},
+		{
+			name:       "uninitialized submodules",
+			args:       []string{"feature"},
+			setup:      func(g *fake.Git) { g.SetUninitializedSubmodules("/ws/feature", "vendor/lib", "docs/theme") },
+			wantErr:    "1 worktree(s) failed checks",
+			wantOutput: []string{"not initialized: vendor/lib, docs/theme"},
+		},
+		{
+			name:       "uninitialized submodules fixed",
+			args:       []string{"feature"},
+			fix:        true,
+			setup:      func(g *fake.Git) { g.SetUninitializedSubmodules("/ws/feature", "vendor/lib") },
+			wantOutput: []string{"initialized vendor/lib", "1 problem(s) fixed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkAllFlag, checkFixFlag = tt.all, tt.fix
+			t.Cleanup(func() { checkAllFlag, checkFixFlag = false, false })
+
+			g := newTestGit().
+				AddRemoteRef("origin", "main", git.NewCommit("abc1234def5678", "Initial", testNow, "user")).
+				AddBranch("feature", git.NewCommit("bbb2222", "Feature", testNow, "user")).
+				SetUpstream("feature", "origin/main", 0, 0).
+				AddWorktree("/ws/feature", "feature")
+			if tt.setup != nil {
+				tt.setup(g)
+			}
+			deps := newTestDeps(g)
+			cmd, out := newTestCommand()
+
+			err := runCheck(cmd, tt.args, deps)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			for _, want := range tt.wantOutput {
+				assert.Contains(t, out.String(), want)
+			}
+		})
+	}
+}
+
+func TestCheckWorktreeLock(t *testing.T) {
+	tests := []struct {
+		name       string
+		lockAge    time.Duration // 0 = no lock
+		fix        bool
+		wantStatus checkStatus
+		wantDetail string
+		wantFixed  bool
+		wantLock   bool
+	}{
+		{name: "no lock", wantStatus: checkPass, wantDetail: "no index.lock"},
+		{name: "recent lock", lockAge: time.Minute, fix: true, wantStatus: checkWarn, wantDetail: "a git process may still be running", wantLock: true},
+		{name: "stale lock", lockAge: 2 * time.Hour, wantStatus: checkFail, wantDetail: "stale index.lock from 2h0m0s ago", wantLock: true},
+		{name: "stale lock removed", lockAge: 2 * time.Hour, fix: true, wantStatus: checkPass, wantDetail: "removed stale index.lock", wantFixed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gitDir := t.TempDir()
+			lockPath := filepath.Join(gitDir, "index.lock")
+			if tt.lockAge > 0 {
+				require.NoError(t, os.WriteFile(lockPath, nil, 0o644))
+				modTime := testNow.Add(-tt.lockAge)
+				require.NoError(t, os.Chtimes(lockPath, modTime, modTime))
+			}
+			deps := newTestDeps(newTestGit())
+
+			c, fixed := checkWorktreeLock(deps, gitDir, tt.fix)
+
+			assert.Equal(t, tt.wantStatus, c.Status)
+			assert.Contains(t, c.Detail, tt.wantDetail)
+			assert.Equal(t, tt.wantFixed, fixed)
+			_, err := os.Stat(lockPath)
+			assert.Equal(t, tt.wantLock, err == nil)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	clog "github.com/charmbracelet/log"
+	"github.com/jmcampanini/grove-cli/internal/pathutil"
+	"github.com/spf13/cobra"
+)
+
+var cleanYesFlag bool
+
+var cleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove orphaned worktree directories from the workspace",
+	Long: `Clean finds directories left in the workspace that look like worktrees but are not
+registered with git, such as what remains after a failed create or a worktree whose
+administrative files were removed. They get in the way of new worktrees with the same name.
+
+Clean first prunes the worktrees whose directories are gone (git worktree prune), then
+lists every directory in the worktree directory (the [worktree] root, or the main worktree's
+parent) whose name starts with [worktree] new_prefix and that is not a worktree.
+
+Clean never deletes branches, so commits made in an orphaned worktree stay reachable from
+its branch; only files that were never committed are lost with the directory. Commands that
+do delete branches keep the commits found nowhere else: grove pr cleanup keeps a branch with
+commits that are not in the merged pull request, grove pr create --update never resets one,
+and grove orphans --delete --force lists them and asks first (see grove orphans --help).
+
+Grove asks before deleting the orphans when run in a terminal. Use --yes to delete them
+without asking; otherwise they are only listed.
+
+With --summary-file, each orphan is an item with status "removed", or "kept" when it was
+only listed.`,
+	Args: cobra.NoArgs,
+	RunE: withDeps(requirements{Mutating: true, NeedsRepo: true, Notifies: true, Summary: true}, runClean),
+}
+
+func init() {
+	cleanCmd.Flags().BoolVarP(&cleanYesFlag, "yes", "y", false, "Delete the orphaned directories without asking")
+	rootCmd.AddCommand(cleanCmd)
+}
+
+func runClean(cmd *cobra.Command, _ []string, deps *Deps) error {
+	if err := deps.Git.PruneWorktrees(deps.Ctx); err != nil {
+		return err
+	}
+	orphans, err := listOrphanedDirs(deps)
+	if err != nil {
+		return err
+	}
+	if len(orphans) == 0 {
+		_, err := fmt.Fprintln(cmd.ErrOrStderr(), "No orphaned directories")
+		return err
+	}
+
+	for _, dir := range orphans {
+		if _, err := fmt.Fprintln(cmd.OutOrStdout(), dir); err != nil {
+			return err
+		}
+	}
+
+	remove := cleanYesFlag
+	if !remove {
+		if !isTerminal(cmd.InOrStdin()) {
+			keepOrphans(deps, orphans, "not confirmed")
+			_, err := fmt.Fprintln(cmd.ErrOrStderr(), "Run grove clean --yes to delete them")
+			return err
+		}
+		remove, err = confirm(cmd.InOrStdin(), cmd.ErrOrStderr(), fmt.Sprintf("Delete %d orphaned director(ies)?", len(orphans)))
+		if err != nil || !remove {
+			keepOrphans(deps, orphans, "not confirmed")
+			return err
+		}
+	}
+
+	for _, dir := range orphans {
+		if dryRunFlag {
+			clog.Default().Info("Would remove orphaned directory", "path", dir)
+			deps.Summary.Add(dir, "kept", "dry run")
+			continue
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", dir, err)
+		}
+		deps.Summary.Add(dir, "removed", "")
+	}
+	_, err = fmt.Fprintf(cmd.ErrOrStderr(), "Removed %d orphaned director(ies)\n", len(orphans))
+	return err
+}
+
+// keepOrphans records in the summary that the orphans were left in place.
+func keepOrphans(deps *Deps, orphans []string, reason string) {
+	for _, dir := range orphans {
+		deps.Summary.Add(dir, "kept", reason)
+	}
+}
+
+// listOrphanedDirs returns the directories in the worktree directory whose name has the [worktree] new_prefix
+// but that are not registered as worktrees, sorted by name. Symlinks are never reported.
+func listOrphanedDirs(deps *Deps) ([]string, error) {
+	prefix := deps.Config.Worktree.NewPrefix
+	if prefix == "" && deps.Config.Worktree.Root == "" {
+		// the workspace is shared with the main worktree and anything else the user keeps there
+		return nil, errors.New("clean needs [worktree] new_prefix or root to tell worktree directories apart")
+	}
+
+	parentDir, err := worktreeParentDir(deps)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(parentDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", parentDir, err)
+	}
+
+	worktrees, err := deps.Git.ListWorktrees(deps.Ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	registered := map[string]bool{pathutil.Canonical(deps.MainWorktreePath): true}
+	for _, wt := range worktrees {
+		registered[pathutil.Canonical(wt.AbsolutePath)] = true
+	}
+
+	var orphans []string
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+		path := filepath.Join(parentDir, entry.Name())
+		if !registered[pathutil.Canonical(path)] {
+			orphans = append(orphans, path)
+		}
+	}
+	return orphans, nil
+}
+
+// confirm asks a yes/no question on out and reads the answer from in. Anything but y or yes is a no.
+func confirm(in io.Reader, out io.Writer, question string) (bool, error) {
+	if _, err := fmt.Fprintf(out, "%s [y/N] ", question); err != nil {
+		return false, err
+	}
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("failed to read answer: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
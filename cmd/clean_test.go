@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/git/fake"
+	"github.com/jmcampanini/grove-cli/internal/summary"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCleanTestDeps returns deps for a workspace on disk holding the main worktree, a worktree, a worktree whose
+// administrative files are stale, an orphaned directory, and entries clean must leave alone.
+func newCleanTestDeps(t *testing.T) (*Deps, string) {
+	t.Helper()
+	workspace := t.TempDir()
+	for _, dir := range []string{"main", "wt-auth", "wt-stale", "wt-orphan", "notes"} {
+		require.NoError(t, os.Mkdir(filepath.Join(workspace, dir), 0o755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "wt-file"), nil, 0o644))
+	require.NoError(t, os.Symlink(filepath.Join(workspace, "notes"), filepath.Join(workspace, "wt-link")))
+
+	commit := git.NewCommit("bbb2222", "Work", testNow, "user")
+	g := fake.New(filepath.Join(workspace, "main"), git.NewCommit("abc1234", "Initial", testNow, "user")).
+		AddBranch("feature/auth", commit).
+		AddBranch("feature/stale", commit).
+		AddWorktree(filepath.Join(workspace, "wt-auth"), "feature/auth").
+		AddWorktree(filepath.Join(workspace, "wt-stale"), "feature/stale").
+		SetPrunable(filepath.Join(workspace, "wt-stale"), "gitdir file points to non-existent location")
+	deps := newTestDeps(g)
+	deps.MainWorktreePath = filepath.Join(workspace, "main")
+	return deps, workspace
+}
+
+func TestRunClean(t *testing.T) {
+	cleanYesFlag = true
+	t.Cleanup(func() { cleanYesFlag = false })
+	deps, workspace := newCleanTestDeps(t)
+	cmd, out := newTestCommand()
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+
+	require.NoError(t, runClean(cmd, nil, deps))
+
+	assert.Equal(t, filepath.Join(workspace, "wt-orphan")+"\n"+filepath.Join(workspace, "wt-stale")+"\n", out.String())
+	assert.Equal(t, "Removed 2 orphaned director(ies)\n", stderr.String())
+	entries, err := os.ReadDir(workspace)
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.Equal(t, []string{"main", "notes", "wt-auth", "wt-file", "wt-link"}, names)
+}
+
+func TestRunClean_Summary(t *testing.T) {
+	tests := []struct {
+		name   string
+		dryRun bool
+		yes    bool
+		want   []summary.Item
+	}{
+		{
+			name: "removed",
+			yes:  true,
+			want: []summary.Item{{Name: "wt-orphan", Status: "removed"}, {Name: "wt-stale", Status: "removed"}},
+		},
+		{
+			name:   "dry run",
+			dryRun: true,
+			yes:    true,
+			want:   []summary.Item{{Message: "dry run", Name: "wt-orphan", Status: "kept"}, {Message: "dry run", Name: "wt-stale", Status: "kept"}},
+		},
+		{
+			name: "not confirmed",
+			want: []summary.Item{{Message: "not confirmed", Name: "wt-orphan", Status: "kept"}, {Message: "not confirmed", Name: "wt-stale", Status: "kept"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanYesFlag, dryRunFlag = tt.yes, tt.dryRun
+			t.Cleanup(func() { cleanYesFlag, dryRunFlag = false, false })
+			deps, workspace := newCleanTestDeps(t)
+			deps.Summary = summary.NewRecorder()
+			cmd, _ := newTestCommand()
+			cmd.SetIn(strings.NewReader("y\n"))
+			cmd.SetErr(&bytes.Buffer{})
+
+			require.NoError(t, runClean(cmd, nil, deps))
+
+			got := deps.Summary.Summary("grove clean", nil).Items
+			for i := range got {
+				got[i].Name = strings.TrimPrefix(got[i].Name, workspace+string(filepath.Separator))
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRunClean_NotATerminal(t *testing.T) {
+	deps, workspace := newCleanTestDeps(t)
+	cmd, out := newTestCommand()
+	cmd.SetIn(strings.NewReader("y\n"))
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+
+	require.NoError(t, runClean(cmd, nil, deps))
+
+	assert.Contains(t, out.String(), filepath.Join(workspace, "wt-orphan"))
+	assert.Equal(t, "Run grove clean --yes to delete them\n", stderr.String())
+	assert.DirExists(t, filepath.Join(workspace, "wt-orphan"))
+}
+
+func TestRunClean_NoPrefix(t *testing.T) {
+	deps, _ := newCleanTestDeps(t)
+	deps.Config.Worktree.NewPrefix = ""
+	cmd, _ := newTestCommand()
+
+	err := runClean(cmd, nil, deps)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "new_prefix or root")
+}
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		answer string
+		want   bool
+	}{
+		{answer: "y\n", want: true},
+		{answer: " YES \n", want: true},
+		{answer: "yes", want: true},
+		{answer: "n\n", want: false},
+		{answer: "\n", want: false},
+		{answer: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.answer, func(t *testing.T) {
+			var out bytes.Buffer
+
+			got, err := confirm(strings.NewReader(tt.answer), &out, "Delete?")
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, "Delete? [y/N] ", out.String())
+		})
+	}
+}
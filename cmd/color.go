@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/muesli/termenv"
+	"golang.org/x/term"
+)
+
+// defaultTerminalWidth is the width assumed when output is neither a terminal nor an fzf preview pane.
+const defaultTerminalWidth = 80
+
+// useColor reports whether output to w should be styled: not with --no-color, not when NO_COLOR is set,
+// and not when w is not a terminal (CLICOLOR_FORCE overrides the last). fzf preview panes are not
+// terminals but render colors, so grove run from an fzf --preview command is styled too.
+func useColor(w io.Writer) bool {
+	if noColorFlag || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if os.Getenv("FZF_PREVIEW_COLUMNS") != "" {
+		return true
+	}
+	return termenv.NewOutput(w).EnvColorProfile() != termenv.Ascii
+}
+
+// terminalWidth returns the number of columns available to output written to w: the fzf preview pane's width
+// when grove runs from an fzf --preview command, the terminal's width, or defaultTerminalWidth.
+func terminalWidth(w io.Writer) int {
+	if columns, err := strconv.Atoi(os.Getenv("FZF_PREVIEW_COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	if f, ok := w.(*os.File); ok {
+		if width, _, err := term.GetSize(int(f.Fd())); err == nil && width > 0 {
+			return width
+		}
+	}
+	return defaultTerminalWidth
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUseColor(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		noColor bool
+		want    bool
+	}{
+		{name: "not a terminal", want: false},
+		{name: "forced", env: map[string]string{"CLICOLOR_FORCE": "1"}, want: true},
+		{name: "forced but NO_COLOR", env: map[string]string{"CLICOLOR_FORCE": "1", "NO_COLOR": "1"}, want: false},
+		{name: "forced but --no-color", env: map[string]string{"CLICOLOR_FORCE": "1"}, noColor: true, want: false},
+		{name: "fzf preview", env: map[string]string{"FZF_PREVIEW_COLUMNS": "80"}, want: true},
+		{name: "fzf preview but NO_COLOR", env: map[string]string{"FZF_PREVIEW_COLUMNS": "80", "NO_COLOR": "1"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", "")
+			t.Setenv("CLICOLOR_FORCE", "")
+			t.Setenv("FZF_PREVIEW_COLUMNS", "")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			noColorFlag = tt.noColor
+			t.Cleanup(func() { noColorFlag = false })
+
+			assert.Equal(t, tt.want, useColor(&bytes.Buffer{}))
+		})
+	}
+}
+
+func TestTerminalWidth(t *testing.T) {
+	t.Setenv("FZF_PREVIEW_COLUMNS", "")
+	assert.Equal(t, defaultTerminalWidth, terminalWidth(&bytes.Buffer{}))
+
+	t.Setenv("FZF_PREVIEW_COLUMNS", "62")
+	assert.Equal(t, 62, terminalWidth(&bytes.Buffer{}))
+}
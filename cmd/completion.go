@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	clog "github.com/charmbracelet/log"
+	"github.com/jmcampanini/grove-cli/internal/github"
+	"github.com/spf13/cobra"
+)
+
+// prCompletionTTL is how long the pull requests fetched for completion are reused,
+// so pressing TAB repeatedly does not call gh every time.
+const prCompletionTTL = time.Minute
+
+// prCompletionCacheFile is the file in the grove state directory that caches pull requests for completion.
+const prCompletionCacheFile = "completion-prs.json"
+
+// completer returns the completion candidates for a command, as "<value>" or "<value>\t<description>".
+type completer func(deps *Deps) ([]string, error)
+
+// completeFirstArg completes a command's first argument with complete; later arguments get no completions.
+func completeFirstArg(req requirements, complete completer) cobra.CompletionFunc {
+	return func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return runCompleter(req, toComplete, complete)
+	}
+}
+
+// completeFlag completes a flag's value with complete.
+func completeFlag(req requirements, complete completer) cobra.CompletionFunc {
+	return func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return runCompleter(req, toComplete, complete)
+	}
+}
+
+// runCompleter builds Deps and returns the candidates starting with toComplete. Completion never fails loudly:
+// outside a repository, or when git or gh fails, it offers nothing and logs the reason to cobra's debug log.
+func runCompleter(req requirements, toComplete string, complete completer) ([]string, cobra.ShellCompDirective) {
+	deps, err := newDeps(context.Background(), req)
+	var candidates []string
+	if err == nil {
+		candidates, err = complete(deps)
+	}
+	if err != nil {
+		cobra.CompDebugln("grove: "+err.Error(), true)
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var matches []string
+	for _, c := range candidates {
+		value, _, _ := strings.Cut(c, "\t")
+		if strings.HasPrefix(value, toComplete) {
+			matches = append(matches, c)
+		}
+	}
+	return matches, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeWorktreeNames returns the names of the worktrees, described by their branch or path.
+func completeWorktreeNames(deps *Deps) ([]string, error) {
+	worktrees, err := deps.Git.ListWorktrees(deps.Ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	namer, err := newWorktreeNamer(deps)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []string
+	for _, wt := range worktrees {
+		if wt.IsBare {
+			continue
+		}
+		description := worktreeBranchName(wt)
+		if description == "" {
+			description = wt.AbsolutePath
+		}
+		candidates = append(candidates, worktreeName(wt, namer)+"\t"+description)
+	}
+	return candidates, nil
+}
+
+// completeRefs returns the local branches, remote branches, and tags, for flags that take a base ref.
+func completeRefs(deps *Deps) ([]string, error) {
+	var candidates []string
+
+	locals, err := deps.Git.ListLocalBranches(deps.Ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list local branches: %w", err)
+	}
+	for _, b := range locals {
+		candidates = append(candidates, b.Name+"\tlocal branch")
+	}
+
+	remotes, err := deps.Git.ListRemotes(deps.Ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remotes: %w", err)
+	}
+	for _, remote := range remotes {
+		branches, err := deps.Git.ListRemoteBranches(deps.Ctx, remote)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list branches of %s: %w", remote, err)
+		}
+		for _, b := range branches {
+			candidates = append(candidates, b.RemoteName+"/"+b.Name+"\tremote branch")
+		}
+	}
+
+	tags, err := deps.Git.ListTags(deps.Ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	for _, tag := range tags {
+		candidates = append(candidates, tag.Name+"\ttag")
+	}
+	return candidates, nil
+}
+
+// completePRNumbers returns the open pull requests' numbers, described by their titles,
+// from the cache in the grove state directory while it is fresh.
+func completePRNumbers(deps *Deps) ([]string, error) {
+	commonDir, err := deps.Git.GetCommonDir(deps.Ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get git common dir: %w", err)
+	}
+	return cachedPRCompletions(deps, filepath.Join(commonDir, "grove", prCompletionCacheFile))
+}
+
+// prCompletionCache is the content of prCompletionCacheFile.
+type prCompletionCache struct {
+	Candidates []string  `json:"candidates"` // "<number>\t<title>"
+	FetchedAt  time.Time `json:"fetched_at"`
+}
+
+// cachedPRCompletions returns the pull request candidates cached at path if they were fetched less than
+// prCompletionTTL ago, and otherwise fetches and caches them. Failing to write the cache is not an error.
+func cachedPRCompletions(deps *Deps, path string) ([]string, error) {
+	now := deps.Clock()
+	if data, err := os.ReadFile(path); err == nil {
+		var cache prCompletionCache
+		if err := json.Unmarshal(data, &cache); err == nil && !now.Before(cache.FetchedAt) && now.Sub(cache.FetchedAt) < prCompletionTTL {
+			return cache.Candidates, nil
+		}
+	}
+
+	query := github.PRQuery{Qualifiers: deps.Config.PR.DefaultFilters, State: github.PRStateOpen}
+	prs, err := deps.GitHub.ListPullRequests(deps.Ctx, query, deps.Config.PR.ListLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+	candidates := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		candidates = append(candidates, strconv.Itoa(pr.Number)+"\t"+singleLine(pr.Title))
+	}
+
+	data, err := json.Marshal(prCompletionCache{Candidates: candidates, FetchedAt: now})
+	if err == nil {
+		if err = os.MkdirAll(filepath.Dir(path), 0o755); err == nil {
+			err = os.WriteFile(path, data, 0o644)
+		}
+	}
+	if err != nil {
+		clog.Default().Debug("failed to cache pull requests for completion", "path", path, "error", err)
+	}
+	return candidates, nil
+}
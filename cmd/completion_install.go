@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	clog "github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+)
+
+var completionInstallDestFlag string
+
+// addCompletionInstallCmd adds install to cobra's default completion command. Cobra only creates that command
+// once the command tree is complete, so this runs from Execute rather than init.
+func addCompletionInstallCmd(root *cobra.Command) {
+	root.InitDefaultCompletionCmd()
+	for _, c := range root.Commands() {
+		if c.Name() == "completion" {
+			c.AddCommand(newCompletionInstallCmd())
+			return
+		}
+	}
+}
+
+func newCompletionInstallCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "install [<shell>]",
+		Short: "Install the completion script where your shell loads it",
+		Long: `Install writes grove's completion script for bash, zsh, or fish to the directory that
+shell loads completions from, then prints anything left to add to your shell config.
+The shell defaults to the one in $SHELL.
+
+  bash  $BASH_COMPLETION_USER_DIR/completions/grove, or
+        $XDG_DATA_HOME/bash-completion/completions/grove (loaded by bash-completion)
+  zsh   $XDG_DATA_HOME/zsh/site-functions/_grove (add the directory to fpath)
+  fish  $XDG_CONFIG_HOME/fish/completions/grove.fish (loaded by fish)
+
+$XDG_DATA_HOME defaults to ~/.local/share and $XDG_CONFIG_HOME to ~/.config. Use --dest to
+write the script to another file, or into another directory.
+
+Not needed with grove shell-init, which registers completion itself.`,
+		Args:      cobra.MaximumNArgs(1),
+		ValidArgs: []string{"bash", "fish", "zsh"},
+		RunE:      runCompletionInstall,
+	}
+	cmd.Flags().StringVar(&completionInstallDestFlag, "dest", "", "Write the script to this file or directory")
+	return cmd
+}
+
+func runCompletionInstall(cmd *cobra.Command, args []string) error {
+	shellName := filepath.Base(os.Getenv("SHELL"))
+	if len(args) == 1 {
+		shellName = args[0]
+	}
+
+	var script bytes.Buffer
+	var err error
+	switch shellName {
+	case "bash":
+		err = cmd.Root().GenBashCompletionV2(&script, true)
+	case "zsh":
+		err = cmd.Root().GenZshCompletion(&script)
+	case "fish":
+		err = cmd.Root().GenFishCompletion(&script, true)
+	default:
+		return fmt.Errorf("unsupported shell: %q (supported: bash, zsh, fish)", shellName)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to generate %s completion: %w", shellName, err)
+	}
+
+	path := completionInstallDestFlag
+	if path == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to get user home directory: %w", err)
+		}
+		path = completionInstallPath(shellName, homeDir)
+	} else if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, completionFileName(shellName))
+	}
+
+	if dryRunFlag {
+		clog.Default().Info("Would write completion script", "path", path)
+	} else {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, script.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("failed to write completion script: %w", err)
+		}
+	}
+
+	_, err = fmt.Fprintf(cmd.OutOrStdout(), "Installed %s completion to %s\n%s",
+		shellName, path, completionRCHint(shellName, path, completionInstallDestFlag == ""))
+	return err
+}
+
+// completionFileName returns the name the shell expects a completion file for grove to have.
+func completionFileName(shellName string) string {
+	switch shellName {
+	case "zsh":
+		return "_grove"
+	case "fish":
+		return "grove.fish"
+	default:
+		return "grove"
+	}
+}
+
+// completionInstallPath returns the per-user file the shell loads grove's completion from.
+func completionInstallPath(shellName, homeDir string) string {
+	dataHome := envOr("XDG_DATA_HOME", filepath.Join(homeDir, ".local", "share"))
+	switch shellName {
+	case "zsh":
+		return filepath.Join(dataHome, "zsh", "site-functions", completionFileName(shellName))
+	case "fish":
+		configHome := envOr("XDG_CONFIG_HOME", filepath.Join(homeDir, ".config"))
+		return filepath.Join(configHome, "fish", "completions", completionFileName(shellName))
+	default:
+		dir := envOr("BASH_COMPLETION_USER_DIR", filepath.Join(dataHome, "bash-completion"))
+		return filepath.Join(dir, "completions", completionFileName(shellName))
+	}
+}
+
+// completionRCHint returns what to add to the shell's config so it loads the script at path. Bash (with
+// bash-completion) and fish load a script from the default location on their own; zsh always needs fpath set.
+func completionRCHint(shellName, path string, defaultPath bool) string {
+	var b strings.Builder
+	switch shellName {
+	case "zsh":
+		b.WriteString("Add to ~/.zshrc, before compinit runs:\n")
+		fmt.Fprintf(&b, "  fpath=(%s $fpath)\n", filepath.Dir(path))
+		b.WriteString("  autoload -Uz compinit && compinit\n")
+	case "fish":
+		if defaultPath {
+			b.WriteString("fish loads it in new shells.\n")
+		} else {
+			fmt.Fprintf(&b, "Add to ~/.config/fish/config.fish:\n  source %s\n", path)
+		}
+	default:
+		if defaultPath {
+			b.WriteString("bash-completion loads it in new shells. Without bash-completion, add to ~/.bashrc:\n")
+		} else {
+			b.WriteString("Add to ~/.bashrc:\n")
+		}
+		fmt.Fprintf(&b, "  source %s\n", path)
+	}
+	return b.String()
+}
+
+// envOr returns the environment variable key, or fallback when it is unset or empty.
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddCompletionInstallCmd(t *testing.T) {
+	root := &cobra.Command{Use: "grove"}
+	root.AddCommand(&cobra.Command{Use: "list", Run: func(*cobra.Command, []string) {}})
+
+	addCompletionInstallCmd(root)
+
+	install, _, err := root.Find([]string{"completion", "install"})
+	require.NoError(t, err)
+	assert.Equal(t, "install", install.Name())
+	zsh, _, err := root.Find([]string{"completion", "zsh"})
+	require.NoError(t, err)
+	assert.Equal(t, "zsh", zsh.Name())
+}
+
+func TestCompletionInstallPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		shell    string
+		wantPath string
+	}{
+		{name: "bash", shell: "bash", wantPath: "/home/me/.local/share/bash-completion/completions/grove"},
+		{
+			name:     "bash with BASH_COMPLETION_USER_DIR",
+			env:      map[string]string{"BASH_COMPLETION_USER_DIR": "/opt/bc", "XDG_DATA_HOME": "/data"},
+			shell:    "bash",
+			wantPath: "/opt/bc/completions/grove",
+		},
+		{name: "bash with XDG_DATA_HOME", env: map[string]string{"XDG_DATA_HOME": "/data"}, shell: "bash", wantPath: "/data/bash-completion/completions/grove"},
+		{name: "zsh", shell: "zsh", wantPath: "/home/me/.local/share/zsh/site-functions/_grove"},
+		{name: "fish", shell: "fish", wantPath: "/home/me/.config/fish/completions/grove.fish"},
+		{name: "fish with XDG_CONFIG_HOME", env: map[string]string{"XDG_CONFIG_HOME": "/conf"}, shell: "fish", wantPath: "/conf/fish/completions/grove.fish"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"BASH_COMPLETION_USER_DIR", "XDG_CONFIG_HOME", "XDG_DATA_HOME"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			assert.Equal(t, tt.wantPath, completionInstallPath(tt.shell, "/home/me"))
+		})
+	}
+}
+
+func TestRunCompletionInstall(t *testing.T) {
+	dest := t.TempDir()
+	completionInstallDestFlag = dest
+	t.Cleanup(func() { completionInstallDestFlag = "" })
+	t.Setenv("SHELL", "/bin/zsh")
+	root := &cobra.Command{Use: "grove"}
+	cmd, out := newTestCommand()
+	root.AddCommand(cmd)
+
+	require.NoError(t, runCompletionInstall(cmd, nil))
+
+	path := filepath.Join(dest, "_grove")
+	script, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(script), "#compdef grove")
+	assert.Equal(t, "Installed zsh completion to "+path+"\n"+
+		"Add to ~/.zshrc, before compinit runs:\n"+
+		"  fpath=("+dest+" $fpath)\n"+
+		"  autoload -Uz compinit && compinit\n", out.String())
+}
+
+func TestRunCompletionInstall_UnsupportedShell(t *testing.T) {
+	cmd, _ := newTestCommand()
+
+	err := runCompletionInstall(cmd, []string{"tcsh"})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unsupported shell: "tcsh"`)
+}
+
+func TestCompletionRCHint(t *testing.T) {
+	assert.Equal(t, "fish loads it in new shells.\n", completionRCHint("fish", "/c/grove.fish", true))
+	assert.Equal(t, "Add to ~/.config/fish/config.fish:\n  source /c/grove.fish\n", completionRCHint("fish", "/c/grove.fish", false))
+	assert.Equal(t, "bash-completion loads it in new shells. Without bash-completion, add to ~/.bashrc:\n  source /c/grove\n",
+		completionRCHint("bash", "/c/grove", true))
+	assert.Equal(t, "Add to ~/.bashrc:\n  source /c/grove\n", completionRCHint("bash", "/c/grove", false))
+}
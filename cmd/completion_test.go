@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/github"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompleteWorktreeNames(t *testing.T) {
+	g := newTestGit().
+		AddBranch("feature/add-auth", git.NewCommit("bbb2222", "Work", testNow, "user")).
+		AddWorktree("/ws/wt-add-auth", "feature/add-auth").
+		AddDetachedWorktree("/ws/scratch", git.NewCommit("ccc3333", "Scratch", testNow, "user"))
+
+	got, err := completeWorktreeNames(newTestDeps(g))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"main\tmain", "add-auth\tfeature/add-auth", "scratch\t/ws/scratch"}, got)
+}
+
+func TestCompleteRefs(t *testing.T) {
+	g := newTestGit().
+		AddBranch("feature/add-auth", git.NewCommit("bbb2222", "Work", testNow, "user")).
+		AddRemoteRef("origin", "main", git.NewCommit("abc1234def5678", "Initial", testNow, "user")).
+		AddRemoteRef("origin", "pull/12/head", git.NewCommit("ddd4444", "PR", testNow, "user")).
+		AddTag(git.NewTag("v1.0.0", git.NewCommit("ccc3333", "Release", testNow, "user"), "", "", "", testNow))
+
+	got, err := completeRefs(newTestDeps(g))
+
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"feature/add-auth\tlocal branch",
+		"main\tlocal branch",
+		"origin/main\tremote branch",
+		"v1.0.0\ttag",
+	}, got)
+}
+
+func TestCachedPRCompletions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "grove", prCompletionCacheFile)
+	gh := &stubGitHub{prs: []github.PullRequest{{Number: 12, Title: "Add auth\nwith details"}}}
+	deps := newTestDeps(newTestGit())
+	deps.GitHub = gh
+	now := testNow
+	deps.Clock = func() time.Time { return now }
+
+	got, err := cachedPRCompletions(deps, path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"12\tAdd auth with details"}, got)
+
+	// within the TTL the cache is used, even though gh would now report another pull request
+	gh.prs = []github.PullRequest{{Number: 13, Title: "Fix bug"}}
+	now = testNow.Add(prCompletionTTL - time.Second)
+	got, err = cachedPRCompletions(deps, path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"12\tAdd auth with details"}, got)
+
+	now = testNow.Add(prCompletionTTL)
+	got, err = cachedPRCompletions(deps, path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"13\tFix bug"}, got)
+	assert.Equal(t, github.PRStateOpen, gh.query.State)
+}
+
+func TestRunCompleter(t *testing.T) {
+	demoFlag = true
+	t.Cleanup(func() { demoFlag = false })
+	complete := func(*Deps) ([]string, error) {
+		return []string{"add-auth\tfeature/add-auth", "add-billing", "main\tmain"}, nil
+	}
+
+	got, directive := runCompleter(requirements{NeedsRepo: true}, "add-", complete)
+
+	assert.Equal(t, []string{"add-auth\tfeature/add-auth", "add-billing"}, got)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+}
+
+func TestCompleteFirstArg_LaterArgs(t *testing.T) {
+	complete := completeFirstArg(requirements{}, func(*Deps) ([]string, error) {
+		t.Fatal("only the first argument is completed")
+		return nil, nil
+	})
+
+	got, directive := complete(nil, []string{"add-auth"}, "")
+
+	assert.Empty(t, got)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+}
@@ -3,11 +3,8 @@ package cmd
 import (
 	"bytes"
 	"fmt"
-	"os"
 
 	"github.com/BurntSushi/toml"
-	"github.com/jmcampanini/grove-cli/internal/config"
-	"github.com/jmcampanini/grove-cli/internal/git"
 	"github.com/spf13/cobra"
 )
 
@@ -19,54 +16,32 @@ var configCmd = &cobra.Command{
 This outputs the merged configuration (defaults with any user overrides applied).
 The output can be redirected to a file to create a new configuration:
 
-  grove config > grove.toml`,
+  grove config > grove.toml
+
+Every key can also be overridden with an environment variable named GROVE_ followed by
+the key in upper case with dots replaced by underscores. Environment variables win over
+all config files; --set wins over everything. Lists are comma-separated.
+
+  GROVE_GIT_TIMEOUT=30s                 -> git.timeout
+  GROVE_WORKTREE_NEW_PREFIX=tmp-        -> worktree.new_prefix
+  GROVE_WORKTREE_STRIP_BRANCH_PREFIX=fix/,feature/
+
+To see which file or variable set each value, use grove config show.`,
 	Args: cobra.NoArgs,
-	RunE: runConfig,
+	RunE: withDeps(requirements{}, runConfig),
 }
 
 func init() {
 	rootCmd.AddCommand(configCmd)
 }
 
-func runConfig(cmd *cobra.Command, _ []string) error {
-	cwd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
-	}
-
-	gitClient := git.New(false, cwd, config.DefaultConfig().Git.Timeout)
-
-	worktreeRoot, err := gitClient.GetWorktreeRoot()
-	if err != nil {
-		return fmt.Errorf("git error: %w", err)
-	}
-	if worktreeRoot == "" {
-		return fmt.Errorf("grove must be run inside a git repository")
-	}
-
-	mainWorktreePath, err := gitClient.GetMainWorktreePath()
-	if err != nil {
-		return fmt.Errorf("failed to get main worktree path: %w", err)
-	}
-
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("failed to get user home directory: %w", err)
-	}
-
-	configPaths := config.ConfigPaths(cwd, worktreeRoot, mainWorktreePath, homeDir)
-	loader := config.NewDefaultLoader()
-	loadResult, err := loader.Load(configPaths)
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
-	}
-
+func runConfig(cmd *cobra.Command, _ []string, deps *Deps) error {
 	var buf bytes.Buffer
 	encoder := toml.NewEncoder(&buf)
-	if err := encoder.Encode(loadResult.Config); err != nil {
+	if err := encoder.Encode(deps.Config); err != nil {
 		return fmt.Errorf("failed to encode config: %w", err)
 	}
 
-	_, err = fmt.Fprint(cmd.OutOrStdout(), buf.String())
+	_, err := fmt.Fprint(cmd.OutOrStdout(), buf.String())
 	return err
 }
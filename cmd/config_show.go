@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jmcampanini/grove-cli/internal/config"
+	"github.com/jmcampanini/grove-cli/internal/jsonout"
+	"github.com/spf13/cobra"
+)
+
+var configShowJSONFlag bool
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the effective config and where each value came from",
+	Long: `Show prints every config key with its effective value and the source that set it:
+"default", the path of the grove.toml file that last set it, "env GROVE_...", or "--set".
+
+With --json, prints grove's JSON envelope (see grove --help) whose data is an array of
+{"key", "source", "value"} objects; config files with unknown keys are reported as warnings.
+
+Example:
+  grove config show
+  grove config show --json | jq '.data[] | select(.source != "default")'`,
+	Args: cobra.NoArgs,
+	RunE: withDeps(requirements{}, runConfigShow),
+}
+
+func init() {
+	configShowCmd.Flags().BoolVar(&configShowJSONFlag, "json", false, "Output as JSON")
+	configCmd.AddCommand(configShowCmd)
+}
+
+// configEntry is a single config key with its effective value and provenance.
+type configEntry struct {
+	Key    string `json:"key"`
+	Source string `json:"source"`
+	Value  any    `json:"value"`
+}
+
+func runConfigShow(cmd *cobra.Command, _ []string, deps *Deps) error {
+	entries, err := configEntries(deps)
+	if err != nil {
+		return err
+	}
+
+	if configShowJSONFlag {
+		return jsonout.Write(cmd.OutOrStdout(), entries, deps.Warnings, deps.Clock())
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	for _, entry := range entries {
+		if _, err := fmt.Fprintf(w, "%s = %s\t# %s\n", entry.Key, formatConfigValue(entry.Value), entry.Source); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// configEntries returns every config key, sorted, with its value and source.
+// Durations are reported in Go syntax (e.g., "5s") so JSON output matches the TOML format.
+func configEntries(deps *Deps) ([]configEntry, error) {
+	keys := config.Keys()
+	entries := make([]configEntry, 0, len(keys))
+	for _, key := range keys {
+		value, err := deps.Config.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		if d, ok := value.(time.Duration); ok {
+			value = d.String()
+		}
+		source, ok := deps.ConfigSources[key]
+		if !ok {
+			source = config.SourceDefault
+		}
+		entries = append(entries, configEntry{Key: key, Source: source, Value: value})
+	}
+	return entries, nil
+}
+
+// formatConfigValue formats a config value using TOML literal syntax.
+func formatConfigValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case []string:
+		quoted := make([]string, len(v))
+		for i, s := range v {
+			quoted[i] = strconv.Quote(s)
+		}
+		return "[" + strings.Join(quoted, ", ") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
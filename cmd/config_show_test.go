@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jmcampanini/grove-cli/internal/jsonout"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunConfigShow(t *testing.T) {
+	buildDeps := func() *Deps {
+		deps := newTestDeps(newTestGit())
+		deps.Config.Git.Timeout = 30 * time.Second
+		deps.Config.Worktree.StripBranchPrefix = []string{"fix/", "feature/"}
+		deps.ConfigSources = map[string]string{
+			"git.timeout":                  "/home/user/.config/grove/grove.toml",
+			"worktree.strip_branch_prefix": configSourceSetFlag,
+		}
+		deps.Clock = func() time.Time { return testNow }
+		deps.Warnings = []jsonout.Warning{{Code: "unknown-config-keys", Message: "unknown config keys: ui.colour", Subject: "/ws/main/grove.toml"}}
+		return deps
+	}
+
+	t.Run("text", func(t *testing.T) {
+		cmd, out := newTestCommand()
+
+		require.NoError(t, runConfigShow(cmd, nil, buildDeps()))
+
+		assert.Regexp(t, `(?m)^git\.timeout = "30s" +# /home/user/\.config/grove/grove\.toml$`, out.String())
+		assert.Regexp(t, `(?m)^worktree\.strip_branch_prefix = \["fix/", "feature/"\] +# --set$`, out.String())
+		assert.Regexp(t, `(?m)^slugify\.max_length = 50 +# default$`, out.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		configShowJSONFlag = true
+		t.Cleanup(func() { configShowJSONFlag = false })
+		cmd, out := newTestCommand()
+
+		require.NoError(t, runConfigShow(cmd, nil, buildDeps()))
+
+		var envelope struct {
+			Data     []configEntry     `json:"data"`
+			Meta     jsonout.Meta      `json:"meta"`
+			Warnings []jsonout.Warning `json:"warnings"`
+		}
+		require.NoError(t, json.Unmarshal(out.Bytes(), &envelope))
+		assert.Equal(t, jsonout.Meta{GeneratedAt: testNow.UTC(), Version: jsonout.Version}, envelope.Meta)
+		assert.Equal(t, buildDeps().Warnings, envelope.Warnings)
+		entries := envelope.Data
+		assert.Contains(t, entries, configEntry{Key: "git.timeout", Source: "/home/user/.config/grove/grove.toml", Value: "30s"})
+		assert.Contains(t, entries, configEntry{Key: "slugify.max_length", Source: "default", Value: float64(50)})
+		assert.Contains(t, entries, configEntry{Key: "worktree.strip_branch_prefix", Source: "--set", Value: []any{"fix/", "feature/"}})
+	})
+}
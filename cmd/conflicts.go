@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jmcampanini/grove-cli/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var conflictsResolveFlag bool
+
+var conflictsCmd = &cobra.Command{
+	Use:   "conflicts [<name>]",
+	Short: "List and resolve rebase conflicts left by batch updates",
+	Long: `Conflicts lists the worktrees whose rebase stopped on conflicts during a batch update
+(grove pr sync --rebase). Those rebases were aborted so the batch could go on, and each
+worktree was left as it was.
+
+With --resolve, grove retries each recorded rebase in turn. When it stops on conflicts,
+git mergetool opens your configured merge tool (git config merge.tool) on the conflicting
+files, then the rebase continues, opening your editor for commit messages as usual. This
+repeats for every commit that conflicts until the rebase completes and the conflict is
+forgotten. Give a worktree name to resolve just that one.
+
+If the merge tool exits with an error, or the rebase stops for another reason, grove leaves
+the rebase in progress and stops; finish it with git and rerun grove conflicts --resolve.
+
+Example:
+  grove pr sync --all --rebase
+  grove conflicts
+  grove conflicts --resolve`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeFirstArg(requirements{NeedsRepo: true}, completeWorktreeNames),
+	RunE:              withDeps(requirements{Mutating: true, NeedsRepo: true}, runConflicts),
+}
+
+func init() {
+	conflictsCmd.Flags().BoolVar(&conflictsResolveFlag, "resolve", false, "Retry each conflicted rebase, opening the merge tool on its conflicts")
+	rootCmd.AddCommand(conflictsCmd)
+}
+
+// conflictedWorktree is a worktree with a recorded rebase conflict.
+type conflictedWorktree struct {
+	Conflict state.Conflict
+	Path     string
+}
+
+func runConflicts(cmd *cobra.Command, args []string, deps *Deps) error {
+	if len(args) == 1 && !conflictsResolveFlag {
+		return errors.New("a worktree name requires --resolve")
+	}
+
+	conflicted, err := listConflictedWorktrees(deps)
+	if err != nil {
+		return err
+	}
+	if len(args) == 1 {
+		wt, err := resolveWorktree(deps, args[0])
+		if err != nil {
+			return err
+		}
+		conflicted = slices.DeleteFunc(conflicted, func(c conflictedWorktree) bool {
+			return c.Path != wt.AbsolutePath
+		})
+		if len(conflicted) == 0 {
+			return fmt.Errorf("%s has no recorded conflict", wt.AbsolutePath)
+		}
+	}
+	if len(conflicted) == 0 {
+		_, err := fmt.Fprintln(cmd.ErrOrStderr(), "No conflicts")
+		return err
+	}
+
+	if !conflictsResolveFlag {
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		for _, c := range conflicted {
+			if _, err := fmt.Fprintf(w, "%s\tonto %s\t%s\n", c.Path, shortSHASafe(c.Conflict.Onto, 7), strings.Join(c.Conflict.Files, ", ")); err != nil {
+				return err
+			}
+		}
+		return w.Flush()
+	}
+
+	for _, c := range conflicted {
+		if err := resolveConflict(deps, c); err != nil {
+			return err
+		}
+		setWorktreeConflict(deps, c.Path, state.Conflict{})
+		if _, err := fmt.Fprintf(cmd.OutOrStdout(), "Resolved %s (rebased onto %s)\n", c.Path, shortSHASafe(c.Conflict.Onto, 7)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listConflictedWorktrees returns the existing worktrees with a recorded rebase conflict, sorted by path.
+func listConflictedWorktrees(deps *Deps) ([]conflictedWorktree, error) {
+	worktrees, err := deps.Git.ListWorktrees(deps.Ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	st, err := loadWorktreeState(deps, worktrees)
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicted []conflictedWorktree
+	for path, entry := range st.Worktrees {
+		if !entry.Conflict.IsZero() {
+			conflicted = append(conflicted, conflictedWorktree{Conflict: entry.Conflict, Path: path})
+		}
+	}
+	slices.SortFunc(conflicted, func(a, b conflictedWorktree) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return conflicted, nil
+}
+
+// resolveConflict retries the worktree's rebase, or picks up one already in progress, and runs the merge tool
+// and git rebase --continue for each commit that stops on conflicts until the rebase completes.
+func resolveConflict(deps *Deps, c conflictedWorktree) error {
+	inProgress, err := deps.Git.IsRebaseInProgress(deps.Ctx, c.Path)
+	if err != nil {
+		return err
+	}
+	if !inProgress {
+		err := runGitIn(deps, c.Path, "rebase", c.Conflict.Onto)
+		if err == nil {
+			return nil
+		}
+		if !gitExited(err) {
+			return err
+		}
+		// a rebase that refused to start (e.g., uncommitted changes) leaves nothing to resolve
+		started, stateErr := deps.Git.IsRebaseInProgress(deps.Ctx, c.Path)
+		if stateErr != nil {
+			return stateErr
+		}
+		if !started {
+			return fmt.Errorf("failed to rebase %s onto %s: %w", c.Path, shortSHASafe(c.Conflict.Onto, 7), err)
+		}
+	}
+
+	for {
+		inProgress, err := deps.Git.IsRebaseInProgress(deps.Ctx, c.Path)
+		if err != nil {
+			return err
+		}
+		if !inProgress {
+			return nil
+		}
+		files, err := deps.Git.ListConflictedFiles(deps.Ctx, c.Path)
+		if err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			return fmt.Errorf("the rebase in %s stopped without conflicts; finish it with git rebase --continue, then rerun grove conflicts --resolve", c.Path)
+		}
+		if err := runGitIn(deps, c.Path, "mergetool"); err != nil {
+			return fmt.Errorf("the merge tool did not resolve the conflicts in %s; the rebase is still in progress: %w", c.Path, err)
+		}
+		// a failed continue usually means the next commit conflicts too; the loop checks again
+		if err := runGitIn(deps, c.Path, "rebase", "--continue"); err != nil && !gitExited(err) {
+			return err
+		}
+	}
+}
+
+// runGitIn runs git attached to the terminal in the worktree at path.
+// A non-zero exit is returned as is (see gitExited), other failures are wrapped.
+func runGitIn(deps *Deps, path string, args ...string) error {
+	err := deps.Exec("git", append([]string{"-C", path}, args...)...)
+	if err != nil && !gitExited(err) {
+		return fmt.Errorf("failed to run git: %w", err)
+	}
+	return err
+}
+
+// gitExited reports whether err is git exiting with a non-zero status, rather than failing to run.
+func gitExited(err error) bool {
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr)
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/git/fake"
+	"github.com/jmcampanini/grove-cli/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunConflicts(t *testing.T) {
+	// gitFails is what Exec returns when git exits with a non-zero status
+	gitFails := func() error { return exec.Command("sh", "-c", "exit 1").Run() }
+
+	tests := []struct {
+		name    string
+		args    []string
+		resolve bool
+		// git simulates the commands run in the worktree against the fake; nil succeeds without changes
+		git          func(g *fake.Git, args []string) error
+		wantCalls    []string
+		wantErr      string
+		wantOutput   string
+		wantResolved bool
+	}{
+		{
+			name:       "list",
+			wantOutput: "/ws/pr-12  onto ccc3333  api.go, api_test.go\n",
+		},
+		{
+			name:    "name without resolve",
+			args:    []string{"pr-12"},
+			wantErr: "a worktree name requires --resolve",
+		},
+		{
+			name:         "rebase succeeds on retry",
+			resolve:      true,
+			wantCalls:    []string{"rebase ccc3333"},
+			wantOutput:   "Resolved /ws/pr-12 (rebased onto ccc3333)\n",
+			wantResolved: true,
+		},
+		{
+			name:    "merge tool for each conflicting commit",
+			args:    []string{"pr-12"},
+			resolve: true,
+			git: func() func(g *fake.Git, args []string) error {
+				continues := 0
+				return func(g *fake.Git, args []string) error {
+					switch strings.Join(args, " ") {
+					case "rebase ccc3333":
+						g.SetRebaseInProgress("/ws/pr-12", true, "api.go")
+						return gitFails()
+					case "mergetool":
+						g.SetRebaseInProgress("/ws/pr-12", true)
+					case "rebase --continue":
+						continues++
+						if continues == 1 {
+							g.SetRebaseInProgress("/ws/pr-12", true, "api_test.go")
+							return gitFails()
+						}
+						g.SetRebaseInProgress("/ws/pr-12", false)
+					}
+					return nil
+				}
+			}(),
+			wantCalls:    []string{"rebase ccc3333", "mergetool", "rebase --continue", "mergetool", "rebase --continue"},
+			wantOutput:   "Resolved /ws/pr-12 (rebased onto ccc3333)\n",
+			wantResolved: true,
+		},
+		{
+			name:    "merge tool fails",
+			resolve: true,
+			git: func(g *fake.Git, args []string) error {
+				if args[0] == "rebase" {
+					g.SetRebaseInProgress("/ws/pr-12", true, "api.go")
+				}
+				return gitFails()
+			},
+			wantCalls: []string{"rebase ccc3333", "mergetool"},
+			wantErr:   "the merge tool did not resolve the conflicts in /ws/pr-12; the rebase is still in progress",
+		},
+		{
+			name:    "rebase stops without conflicts",
+			resolve: true,
+			git: func(g *fake.Git, args []string) error {
+				g.SetRebaseInProgress("/ws/pr-12", true)
+				return gitFails()
+			},
+			wantCalls: []string{"rebase ccc3333"},
+			wantErr:   "the rebase in /ws/pr-12 stopped without conflicts",
+		},
+		{
+			name:      "rebase refuses to start",
+			resolve:   true,
+			git:       func(*fake.Git, []string) error { return gitFails() },
+			wantCalls: []string{"rebase ccc3333"},
+			wantErr:   "failed to rebase /ws/pr-12 onto ccc3333",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conflictsResolveFlag = tt.resolve
+			t.Cleanup(func() { conflictsResolveFlag = false })
+
+			g := newTestGit().
+				AddBranch("pr-12", git.NewCommit("aaa1111", "Head", testNow, "user")).
+				AddBranch("pr-13", git.NewCommit("bbb2222", "Head", testNow, "user")).
+				AddWorktree("/ws/pr-12", "pr-12").
+				AddWorktree("/ws/pr-13", "pr-13")
+			deps := newTestDeps(g)
+			st := state.New()
+			st.Set("/ws/pr-12", state.Worktree{
+				Conflict: state.Conflict{At: testNow, Files: []string{"api.go", "api_test.go"}, Onto: "ccc3333"},
+				Origin:   state.OriginPR,
+				PRNumber: 12,
+			})
+			st.Set("/ws/pr-13", state.Worktree{Origin: state.OriginPR, PRNumber: 13})
+			require.NoError(t, deps.State.Save(st))
+			var calls []string
+			deps.Exec = func(name string, args ...string) error {
+				require.Equal(t, []string{"git", "-C", "/ws/pr-12"}, append([]string{name}, args[:2]...))
+				calls = append(calls, strings.Join(args[2:], " "))
+				if tt.git == nil {
+					return nil
+				}
+				return tt.git(g, args[2:])
+			}
+			cmd, out := newTestCommand()
+
+			err := runConflicts(cmd, tt.args, deps)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantOutput, out.String())
+			assert.Equal(t, tt.wantCalls, calls)
+			st, err = deps.State.Load()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantResolved, st.Get("/ws/pr-12").Conflict.IsZero())
+		})
+	}
+}
+
+func TestRunConflicts_None(t *testing.T) {
+	cmd, out := newTestCommand()
+
+	require.NoError(t, runConflicts(cmd, nil, newTestDeps(newTestGit())))
+
+	assert.Empty(t, out.String())
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/jmcampanini/grove-cli/internal/cookbook"
+	"github.com/jmcampanini/grove-cli/internal/naming"
+	"github.com/spf13/cobra"
+)
+
+var cookbookCmd = &cobra.Command{
+	Use:   "cookbook [topic]",
+	Short: "Show workflow recipes using this repository's settings",
+	Long: `Cookbook prints curated, runnable workflow recipes.
+
+Examples are filled in with the current repository's workspace path and configured
+prefixes, so they can be copied and pasted as-is. Without a topic, lists the topics.
+
+Example:
+  grove cookbook
+  grove cookbook pr-review`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: cookbook.Names(),
+	RunE:      withDeps(requirements{}, runCookbook),
+}
+
+func init() {
+	rootCmd.AddCommand(cookbookCmd)
+}
+
+func runCookbook(cmd *cobra.Command, args []string, deps *Deps) error {
+	if len(args) == 0 {
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		for _, recipe := range cookbook.Recipes() {
+			if _, err := fmt.Fprintf(w, "%s\t%s\n", recipe.Name, recipe.Title); err != nil {
+				return err
+			}
+		}
+		return w.Flush()
+	}
+
+	out, err := cookbook.Render(args[0], cookbookData(deps))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(cmd.OutOrStdout(), out)
+	return err
+}
+
+// cookbookData describes the current repository for recipes, with placeholders outside a repository.
+func cookbookData(deps *Deps) cookbook.Data {
+	cfg := deps.Config
+	data := cookbook.Data{
+		BranchPrefix:      cfg.Branch.NewPrefix,
+		MainWorktreePath:  "<workspace>/main",
+		PRWorktreeExample: "pr-123",
+		WorkspacePath:     "<workspace>",
+		WorktreePrefix:    cfg.Worktree.NewPrefix,
+	}
+	if deps.MainWorktreePath != "" {
+		data.MainWorktreePath = deps.MainWorktreePath
+		data.WorkspacePath = filepath.Dir(deps.MainWorktreePath)
+	}
+	if namer, err := naming.NewPRWorktreeNamer(cfg.PR, cfg.Slugify); err == nil {
+		if name, err := namer.WorktreeName(naming.PRTemplateData{AuthorLogin: "octocat", BranchName: "some-branch", Number: 123, Title: "Some change"}); err == nil {
+			data.PRWorktreeExample = name
+		}
+	}
+	return data
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCookbook(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		mainPath     string
+		wantContains []string
+		wantErr      string
+	}{
+		{
+			name:         "lists topics",
+			mainPath:     "/ws/main",
+			wantContains: []string{"fzf-switcher ", "pr-review ", "sparse-worktrees "},
+		},
+		{
+			name:         "uses repository paths and config",
+			args:         []string{"pr-review"},
+			mainPath:     "/ws/main",
+			wantContains: []string{"git -C /ws/main worktree remove /ws/pr-123"},
+		},
+		{
+			name:         "placeholders outside a repository",
+			args:         []string{"sparse-worktrees"},
+			wantContains: []string{"<workspace>/wt-update-api-auth", "feature/update-api-auth"},
+		},
+		{
+			name:    "unknown topic",
+			args:    []string{"nope"},
+			wantErr: `unknown cookbook topic "nope"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := newTestDeps(newTestGit())
+			deps.MainWorktreePath = tt.mainPath
+			cmd, out := newTestCommand()
+
+			err := runCookbook(cmd, tt.args, deps)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			for _, want := range tt.wantContains {
+				assert.Contains(t, out.String(), want)
+			}
+		})
+	}
+}
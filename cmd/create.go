@@ -3,95 +3,367 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/jmcampanini/grove-cli/internal/config"
-	"github.com/jmcampanini/grove-cli/internal/git"
 	"github.com/jmcampanini/grove-cli/internal/naming"
+	"github.com/jmcampanini/grove-cli/internal/state"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	baseDefaultFlag bool
+	baseFlag        string
+	fromRemoteFlag  string
 )
 
 var createCmd = &cobra.Command{
-	Use:   "create <phrase>",
+	Use:   "create [<phrase>]",
 	Short: "Create a new branch and worktree",
 	Long: `Create creates a new git branch and worktree from a descriptive phrase.
 
-The new branch is created from the current HEAD (the commit you're currently on).
+The new branch is created from the current HEAD (the commit you're currently on),
+or from --base: a branch, remote branch, tag, or SHA. A remote branch such as
+origin/main is fetched first so the new branch starts from the latest commit.
+--base-default uses the remote's default branch (origin/HEAD), fetched first.
+
+To change where new branches start by default, set [branch] base in the config
+to "head", "default", or a ref.
 The phrase is converted to a branch name using the configured slugify rules
 and prefix. A worktree is then created with the configured worktree naming.
+Teams with a branch naming convention can set [branch] template instead of the prefix,
+with the slugified phrase, the git user name as a slug, and today's date:
+
+  [branch]
+  template = "{{.User}}/{{.Date}}/{{.Slug}}"
+
+A template that uses {{.User}} needs git config user.name to be set.
+
+Worktree directories are named [worktree] new_prefix followed by the branch name's slug,
+with the first matching strip_branch_prefix removed, or by [worktree] template, with the
+prefix, the slug, the full branch name, and a short hash of the branch name:
+
+  [worktree]
+  template = "{{.Prefix}}{{.Slug}}-{{.Short}}"
+
+Worktrees are created next to the main worktree, or under [worktree] root when set.
+The root may start with ~ and use {{.RepoName}}, the repository name from the default
+remote's URL (or the main worktree's directory name when there is no remote):
+
+  [worktree]
+  root = "~/worktrees/{{.RepoName}}"
+
+When the worktree's directory already exists, create fails. Set [worktree] on_collision
+to "suffix" to number the new worktree instead (wt-add-auth-2, wt-add-auth-3, ...).
+
+Only one grove creates a worktree in a repository at a time: while another grove create
+or grove pr create runs, create waits up to 10 seconds for it to finish, then fails.
+
+Without a phrase, grove reads it from stdin when stdin is piped, and otherwise opens
+your editor ($VISUAL or $EDITOR, else vi). The first line is the phrase; the lines after
+it are stored as the branch description (git config branch.<name>.description, as set by
+git branch --edit-description). In the editor, lines starting with # are ignored and an
+empty message aborts the create.
+
+With --from-remote, no phrase is needed: the remote branch is fetched, a local
+branch with the same name is created to track it, and a worktree is created for it.
 
 Example:
   grove create "add user authentication"
   grove create "fix bug in login"
+  grove create --base origin/main "hotfix for release"
+  grove create --base-default "start from the latest main"
+  grove create --from-remote origin/some-branch
+  grove create                       # write the phrase and a description in your editor
+  printf 'fix login\n\nSessions expire too early.\n' | grove create
 
 Note: The create command takes a single quoted string argument. The shell wrapper
 function (grc) can handle passing arbitrary phrases by quoting the arguments.`,
-	Args: cobra.ExactArgs(1),
-	RunE: runCreate,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if fromRemoteFlag != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.MaximumNArgs(1)(cmd, args)
+	},
+	RunE: withDeps(requirements{Mutating: true, NeedsRepo: true}, func(cmd *cobra.Command, args []string, deps *Deps) error {
+		if fromRemoteFlag != "" {
+			return runCreateFromRemote(cmd, fromRemoteFlag, deps)
+		}
+		return runCreate(cmd, args, deps)
+	}),
 }
 
 func init() {
+	createCmd.Flags().StringVar(&fromRemoteFlag, "from-remote", "", "Create a tracking branch and worktree for a remote branch (e.g., origin/some-branch)")
+	createCmd.Flags().StringVar(&baseFlag, "base", "", "Create the branch from this ref instead of HEAD (branch, remote branch, tag, or SHA)")
+	createCmd.Flags().BoolVar(&baseDefaultFlag, "base-default", false, "Create the branch from the freshly fetched remote default branch")
+	createCmd.MarkFlagsMutuallyExclusive("from-remote", "base", "base-default")
+	_ = createCmd.RegisterFlagCompletionFunc("base", completeFlag(requirements{NeedsRepo: true}, completeRefs))
 	rootCmd.AddCommand(createCmd)
 }
 
-func runCreate(cmd *cobra.Command, args []string) error {
-	phrase := args[0]
+func runCreate(cmd *cobra.Command, args []string, deps *Deps) error {
+	var phrase, description string
+	if len(args) == 1 {
+		phrase = args[0]
+	} else {
+		var err error
+		phrase, description, err = readCreateMessage(cmd, deps)
+		if err != nil {
+			return err
+		}
+	}
 
 	if strings.TrimSpace(phrase) == "" {
 		return errors.New("phrase cannot be empty")
 	}
 
-	cwd, err := os.Getwd()
+	cfg := deps.Config
+
+	branchName, err := generateBranchName(deps, phrase)
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+		return err
 	}
 
-	homeDir, err := os.UserHomeDir()
+	if branchName == "" || branchName == cfg.Branch.NewPrefix {
+		return fmt.Errorf(`phrase %q produces an empty branch name after slugification
+
+Please provide a phrase with at least one alphanumeric character.
+Examples:
+  grove create "add user auth"
+  grove create "fix-bug-123"`, phrase)
+	}
+
+	base, err := resolveBaseRef(deps)
 	if err != nil {
-		return fmt.Errorf("failed to get user home directory: %w", err)
+		return err
 	}
 
-	gitClient := git.New(false, cwd, config.DefaultConfig().Git.Timeout)
+	return createBranchWorktree(cmd, deps, branchName, base, description)
+}
 
-	worktreeRoot, err := gitClient.GetWorktreeRoot()
+// generateBranchName names a new branch from a phrase with the [branch] template, or else new_prefix.
+// It returns "" when the phrase has nothing to slugify.
+func generateBranchName(deps *Deps, phrase string) (string, error) {
+	gen, err := naming.NewBranchNameGenerator(deps.Config.Branch, deps.Config.Slugify)
 	if err != nil {
-		return fmt.Errorf("git error: %w", err)
+		return "", fmt.Errorf("invalid config: %w", err)
 	}
-	if worktreeRoot == "" {
-		return errors.New("grove must be run inside a git repository")
+	var userName string
+	if gen.UsesUser() {
+		if userName, err = deps.Git.GetUserName(deps.Ctx); err != nil {
+			return "", err
+		}
 	}
+	return gen.Generate(phrase, userName, deps.Clock())
+}
 
-	mainWorktreePath, err := gitClient.GetMainWorktreePath()
+// newWorktreeNamer returns the namer for worktree directories, failing on an invalid [worktree] template.
+func newWorktreeNamer(deps *Deps) (*naming.WorktreeNamer, error) {
+	namer, err := naming.NewWorktreeNamer(deps.Config.Worktree, deps.Config.Slugify)
 	if err != nil {
-		return fmt.Errorf("failed to get main worktree path: %w", err)
+		return nil, fmt.Errorf("invalid config: %w", err)
 	}
+	return namer, nil
+}
+
+// createMessageTemplate is the initial content of the file grove create opens in the editor.
+const createMessageTemplate = `
+# Describe the new branch. The first line is the phrase the branch and worktree are
+# named from; the lines after it are stored as the branch description.
+# Lines starting with '#' are ignored, and an empty message aborts the create.
+`
 
-	configPaths := config.ConfigPaths(cwd, worktreeRoot, mainWorktreePath, homeDir)
-	loader := config.NewDefaultLoader()
-	loadResult, err := loader.Load(configPaths)
+// readCreateMessage reads the phrase and branch description for grove create without a phrase argument:
+// from stdin when it is not a terminal, and otherwise from a file edited in the user's editor.
+func readCreateMessage(cmd *cobra.Command, deps *Deps) (phrase, description string, err error) {
+	in := cmd.InOrStdin()
+	if !isTerminal(in) {
+		data, err := io.ReadAll(in)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read the phrase from stdin: %w", err)
+		}
+		phrase, description = parseCreateMessage(string(data), false)
+		return phrase, description, nil
+	}
+	return editCreateMessage(deps)
+}
+
+// editCreateMessage opens createMessageTemplate in the user's editor and parses what they saved.
+func editCreateMessage(deps *Deps) (phrase, description string, err error) {
+	f, err := os.CreateTemp("", "grove-create-*.txt")
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return "", "", fmt.Errorf("failed to create the message file: %w", err)
+	}
+	path := f.Name()
+	defer func() { _ = os.Remove(path) }()
+	_, err = f.WriteString(createMessageTemplate)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to write the message file: %w", err)
 	}
-	cfg := loadResult.Config
 
-	// recreate the git client using the config timeout
-	gitClient = git.New(false, cwd, cfg.Git.Timeout)
+	// run through the shell, as git does, so the editor may include arguments (e.g., "code --wait")
+	editor := preferredEditor()
+	if err := deps.Exec("sh", "-c", editor+` "$@"`, editor, path); err != nil {
+		return "", "", fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read the message file: %w", err)
+	}
+	phrase, description = parseCreateMessage(string(data), true)
+	return phrase, description, nil
+}
 
-	branchGen := naming.NewBranchNameGenerator(cfg.Branch, cfg.Slugify)
-	branchName := branchGen.Generate(phrase)
+// parseCreateMessage splits a create message into its first non-blank line, the phrase, and the rest,
+// the description. With stripComments, lines starting with # are dropped first.
+func parseCreateMessage(text string, stripComments bool) (phrase, description string) {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if stripComments && strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			return strings.TrimSpace(line), strings.TrimSpace(strings.Join(lines[i+1:], "\n"))
+		}
+	}
+	return "", ""
+}
 
-	if branchName == "" || branchName == cfg.Branch.NewPrefix {
-		return fmt.Errorf(`phrase %q produces an empty branch name after slugification
+// preferredEditor returns the user's editor: $VISUAL, then $EDITOR, then vi.
+func preferredEditor() string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(name)); editor != "" {
+			return editor
+		}
+	}
+	return "vi"
+}
 
-Please provide a phrase with at least one alphanumeric character.
-Examples:
-  grove create "add user auth"
-  grove create "fix-bug-123"`, phrase)
+// isTerminal reports whether r is a terminal, rather than a pipe, a file, or another character device
+// such as /dev/null.
+func isTerminal(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// resolveBaseRef returns the ref a new branch starts from, or "" for HEAD.
+// --base and --base-default take precedence over the [branch] base config.
+// Remote refs are fetched first, and the ref is checked to name a commit.
+func resolveBaseRef(deps *Deps) (string, error) {
+	base := deps.Config.Branch.Base
+	switch {
+	case baseFlag != "":
+		base = baseFlag
+	case baseDefaultFlag:
+		base = config.BranchBaseDefault
+	}
+
+	switch base {
+	case config.BranchBaseHead:
+		return "", nil
+	case config.BranchBaseDefault:
+		remote, err := deps.Git.GetDefaultRemote(deps.Ctx, "origin")
+		if err != nil {
+			return "", fmt.Errorf("failed to get default remote: %w", err)
+		}
+		defaultBranch, err := deps.Git.ResolveRepoDefaultBranch(deps.Ctx, remote)
+		if err != nil {
+			return "", fmt.Errorf("failed to get default branch of %s: %w", remote, err)
+		}
+		if defaultBranch == "" {
+			return "", fmt.Errorf("could not detect the default branch of %s; to set it: git remote set-head %s <branch>", remote, remote)
+		}
+		base = remote + "/" + defaultBranch
+	}
+
+	if err := prepareBaseRef(deps, base); err != nil {
+		return "", err
+	}
+	return base, nil
+}
+
+// prepareBaseRef checks that base names a commit, fetching it first when it is a branch on a configured remote.
+func prepareBaseRef(deps *Deps, base string) error {
+	remoteName, branchName, ok := strings.Cut(base, "/")
+	if ok && remoteName != "" && branchName != "" {
+		remotes, err := deps.Git.ListRemotes(deps.Ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list remotes: %w", err)
+		}
+		if slices.Contains(remotes, remoteName) {
+			trackingRef := "refs/remotes/" + remoteName + "/" + branchName
+			// forced, so a base branch that was force-pushed is still fetched
+			if err := deps.Git.FetchRemoteBranch(deps.Ctx, remoteName, "+"+branchName, trackingRef); err != nil {
+				return fmt.Errorf("failed to fetch %s: %w", base, err)
+			}
+		}
 	}
 
-	exists, err := gitClient.BranchExists(branchName, false)
+	if _, err := deps.Git.ResolveRef(deps.Ctx, base); err != nil {
+		return fmt.Errorf("invalid base: %w", err)
+	}
+	return nil
+}
+
+// runCreateFromRemote fetches a remote branch and creates a local tracking branch and worktree for it.
+func runCreateFromRemote(cmd *cobra.Command, remoteBranch string, deps *Deps) error {
+	remoteName, branchName, err := splitRemoteBranch(deps, remoteBranch)
+	if err != nil {
+		return err
+	}
+
+	trackingRef := "refs/remotes/" + remoteName + "/" + branchName
+	// forced, like git fetch's own refspec, so a branch that was force-pushed is still fetched
+	if err := deps.Git.FetchRemoteBranch(deps.Ctx, remoteName, "+"+branchName, trackingRef); err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", remoteBranch, err)
+	}
+
+	// git sets up tracking automatically when the start point is a remote-tracking branch
+	return createBranchWorktree(cmd, deps, branchName, remoteName+"/"+branchName, "")
+}
+
+// splitRemoteBranch splits "origin/some/branch" into its configured remote and branch name.
+func splitRemoteBranch(deps *Deps, remoteBranch string) (remoteName, branchName string, err error) {
+	remoteName, branchName, ok := strings.Cut(remoteBranch, "/")
+	if !ok || remoteName == "" || branchName == "" {
+		return "", "", fmt.Errorf("invalid remote branch %q; expected <remote>/<branch>", remoteBranch)
+	}
+
+	remotes, err := deps.Git.ListRemotes(deps.Ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to list remotes: %w", err)
+	}
+	if !slices.Contains(remotes, remoteName) {
+		return "", "", fmt.Errorf("remote %q does not exist (configured remotes: %s)", remoteName, strings.Join(remotes, ", "))
+	}
+
+	return remoteName, branchName, nil
+}
+
+// createBranchWorktree creates a new branch from baseRef (HEAD if empty) with a worktree
+// named by the configured worktree naming, sets the branch description unless it is empty,
+// and prints the worktree path.
+func createBranchWorktree(cmd *cobra.Command, deps *Deps, branchName, baseRef, description string) error {
+	gitClient := deps.Git
+
+	unlock, err := lockWorktrees(deps)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	exists, err := gitClient.BranchExists(deps.Ctx, branchName, false)
 	if err != nil {
 		return fmt.Errorf("failed to check if branch exists: %w", err)
 	}
@@ -99,22 +371,31 @@ Examples:
 		return fmt.Errorf("branch %q already exists; to use it: git worktree add <path> %s", branchName, branchName)
 	}
 
-	worktreeNamer := naming.NewWorktreeNamer(cfg.Worktree, cfg.Slugify)
+	worktreeNamer, err := newWorktreeNamer(deps)
+	if err != nil {
+		return err
+	}
 	worktreeName := worktreeNamer.Generate(branchName)
 
-	workspacePath, err := gitClient.GetWorkspacePath()
+	parentDir, err := worktreeParentDir(deps)
 	if err != nil {
-		return fmt.Errorf("failed to get workspace path: %w", err)
+		return err
 	}
-	worktreePath := filepath.Join(workspacePath, worktreeName)
-
-	if _, err := os.Stat(worktreePath); err == nil {
-		return fmt.Errorf("worktree path %q already exists; to remove it: git worktree remove %s", worktreePath, worktreeName)
+	worktreePath, err := newWorktreePath(deps, parentDir, worktreeName)
+	if err != nil {
+		return err
 	}
 
-	if err := gitClient.CreateWorktreeForNewBranchFromRef(branchName, worktreePath, ""); err != nil {
+	if err := gitClient.CreateWorktreeForNewBranchFromRef(deps.Ctx, branchName, worktreePath, baseRef); err != nil {
 		return fmt.Errorf("failed to create branch and worktree: %w", err)
 	}
+	recordCreatedWorktree(deps, worktreePath, state.OriginCreate, 0)
+
+	if description != "" {
+		if err := gitClient.SetBranchDescription(deps.Ctx, branchName, description); err != nil {
+			return fmt.Errorf("failed to set the description of %s: %w", branchName, err)
+		}
+	}
 
 	_, err = fmt.Fprintln(cmd.OutOrStdout(), worktreePath)
 	return err
@@ -0,0 +1,523 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmcampanini/grove-cli/internal/config"
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCreate(t *testing.T) {
+	tests := []struct {
+		name           string
+		phrase         string
+		existingBranch string
+		wantBranch     string
+		wantPath       string
+		wantErr        string
+	}{
+		{
+			name:       "creates branch and worktree from phrase",
+			phrase:     "Add User Auth",
+			wantBranch: "feature/add-user-auth",
+			wantPath:   "/ws/wt-add-user-auth",
+		},
+		{
+			name:    "empty phrase",
+			phrase:  "   ",
+			wantErr: "phrase cannot be empty",
+		},
+		{
+			name:    "phrase without alphanumerics",
+			phrase:  "!!!",
+			wantErr: "produces an empty branch name",
+		},
+		{
+			name:           "existing branch",
+			phrase:         "add user auth",
+			existingBranch: "feature/add-user-auth",
+			wantErr:        `branch "feature/add-user-auth" already exists`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGit()
+			if tt.existingBranch != "" {
+				g.AddBranch(tt.existingBranch, git.NewCommit("bbb2222", "Existing", testNow, "user"))
+			}
+			cmd, out := newTestCommand()
+			deps := newTestDeps(g)
+
+			err := runCreate(cmd, []string{tt.phrase}, deps)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPath+"\n", out.String())
+			assertWorktreeOnBranch(t, g, tt.wantPath, tt.wantBranch)
+			assertWorktreeRecorded(t, deps, tt.wantPath, state.Worktree{CreatedAt: testNow, Origin: state.OriginCreate})
+		})
+	}
+}
+
+func TestRunCreate_BranchTemplate(t *testing.T) {
+	g := newTestGit().SetUserName("Jane Doe")
+	deps := newTestDeps(g)
+	deps.Config.Branch.Template = "{{.User}}/{{.Date}}/{{.Slug}}"
+	cmd, out := newTestCommand()
+
+	require.NoError(t, runCreate(cmd, []string{"Add User Auth"}, deps))
+
+	assert.Equal(t, "/ws/wt-jane-doe-2024-06-01-add-user-auth\n", out.String())
+	assertWorktreeOnBranch(t, g, "/ws/wt-jane-doe-2024-06-01-add-user-auth", "jane-doe/2024-06-01/add-user-auth")
+
+	deps.Config.Branch.Template = "{{.Author}}/{{.Slug}}"
+	err := runCreate(cmd, []string{"fix login"}, deps)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid branch.template")
+
+	g.SetUserName("")
+	deps.Config.Branch.Template = "{{.User}}/{{.Slug}}"
+	err = runCreate(cmd, []string{"fix login"}, deps)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `set it with git config user.name "Your Name"`)
+}
+
+func TestRunCreate_WorktreeTemplate(t *testing.T) {
+	g := newTestGit()
+	deps := newTestDeps(g)
+	deps.Config.Worktree.Template = "{{.Prefix}}{{.Slug}}-{{.Short}}"
+	cmd, out := newTestCommand()
+
+	require.NoError(t, runCreate(cmd, []string{"add auth"}, deps))
+
+	assert.Regexp(t, `^/ws/wt-add-auth-[0-9a-z]{6}\n$`, out.String())
+	wt, err := resolveWorktree(deps, "feature/add-auth")
+	require.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(out.String()), wt.AbsolutePath)
+}
+
+func TestRunCreate_Collision(t *testing.T) {
+	tests := []struct {
+		name        string
+		onCollision string
+		taken       []string
+		onDisk      string
+		wantPath    string
+		wantErr     string
+	}{
+		{
+			name:        "error",
+			onCollision: config.CollisionError,
+			taken:       []string{"/ws/wt-add-auth"},
+			wantErr:     `worktree path "/ws/wt-add-auth" already exists`,
+		},
+		{
+			name:        "suffix",
+			onCollision: config.CollisionSuffix,
+			taken:       []string{"/ws/wt-add-auth"},
+			wantPath:    "/ws/wt-add-auth-2",
+		},
+		{
+			name:        "suffix skips taken numbers",
+			onCollision: config.CollisionSuffix,
+			taken:       []string{"/ws/wt-add-auth", "/ws/wt-add-auth-2"},
+			wantPath:    "/ws/wt-add-auth-3",
+		},
+		{
+			name:        "directory that is not a worktree",
+			onCollision: config.CollisionSuffix,
+			onDisk:      "wt-add-auth",
+			wantPath:    "wt-add-auth-2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGit()
+			for i, path := range tt.taken {
+				branch := fmt.Sprintf("other-%d", i)
+				g.AddBranch(branch, git.NewCommit("bbb2222", "Other", testNow, "user")).AddWorktree(path, branch)
+			}
+			deps := newTestDeps(g)
+			deps.Config.Worktree.OnCollision = tt.onCollision
+			wantPath := tt.wantPath
+			if tt.onDisk != "" {
+				deps.Config.Worktree.Root = t.TempDir()
+				require.NoError(t, os.Mkdir(filepath.Join(deps.Config.Worktree.Root, tt.onDisk), 0o755))
+				wantPath = filepath.Join(deps.Config.Worktree.Root, tt.wantPath)
+			}
+			cmd, out := newTestCommand()
+
+			err := runCreate(cmd, []string{"add auth"}, deps)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, wantPath+"\n", out.String())
+			assertWorktreeOnBranch(t, g, wantPath, "feature/add-auth")
+		})
+	}
+}
+
+func TestRunCreate_InvalidLocation(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(cfg *config.Config)
+		wantErr string
+	}{
+		{
+			name:    "root inside a worktree",
+			modify:  func(cfg *config.Config) { cfg.Worktree.Root = "/ws/main/trees" },
+			wantErr: `cannot create a worktree in "/ws/main/trees", which is inside the worktree "/ws/main"`,
+		},
+		{
+			name: "name with a path separator",
+			modify: func(cfg *config.Config) {
+				cfg.Slugify.ReplaceNonAlphanum = false
+				cfg.Worktree.StripBranchPrefix = nil
+			},
+			wantErr: `worktree name "wt-feature/add" is not a single directory name`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGit()
+			deps := newTestDeps(g)
+			tt.modify(&deps.Config)
+			cmd, _ := newTestCommand()
+
+			err := runCreate(cmd, []string{"add"}, deps)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			worktrees, err := g.ListWorktrees(t.Context())
+			require.NoError(t, err)
+			assert.Len(t, worktrees, 1, "no worktree is created")
+		})
+	}
+}
+
+func TestRunCreate_Lock(t *testing.T) {
+	deps := newTestDeps(newTestGit())
+	deps.LockDir = t.TempDir()
+	cmd, out := newTestCommand()
+
+	require.NoError(t, runCreate(cmd, []string{"add auth"}, deps))
+
+	assert.Equal(t, "/ws/wt-add-auth\n", out.String())
+	assert.NoFileExists(t, filepath.Join(deps.LockDir, worktreeLockFile), "the lock is released")
+}
+
+func TestRunCreate_LockHeld(t *testing.T) {
+	worktreeLockTimeout = time.Millisecond
+	t.Cleanup(func() { worktreeLockTimeout = 10 * time.Second })
+	g := newTestGit()
+	deps := newTestDeps(g)
+	deps.LockDir = t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(deps.LockDir, worktreeLockFile), []byte("4242\n"), 0o644))
+	cmd, _ := newTestCommand()
+
+	err := runCreate(cmd, []string{"add auth"}, deps)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "another grove operation is in progress (process 4242)")
+	exists, err := g.BranchExists(t.Context(), "feature/add-auth", false)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestRunCreate_Base(t *testing.T) {
+	tests := []struct {
+		name       string
+		base       string
+		wantCommit string
+		wantErr    string
+	}{
+		{name: "local branch", base: "release", wantCommit: "bbb2222"},
+		{name: "tag", base: "v1.0.0", wantCommit: "ccc3333"},
+		{name: "sha", base: "bbb2", wantCommit: "bbb2222"},
+		{name: "remote branch", base: "origin/main", wantCommit: "ddd4444"},
+		{name: "remote branch missing on remote", base: "origin/nope", wantErr: "failed to fetch origin/nope"},
+		{name: "unknown ref", base: "nope", wantErr: `invalid base: ref "nope" does not name a commit`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseFlag = tt.base
+			t.Cleanup(func() { baseFlag = "" })
+
+			g := newTestGit().
+				AddBranch("release", git.NewCommit("bbb2222", "Release", testNow, "user")).
+				AddTag(git.NewTag("v1.0.0", git.NewCommit("ccc3333", "Tagged", testNow, "user"), "", "", "", testNow)).
+				AddRemoteRef("origin", "main", git.NewCommit("ddd4444", "Remote main", testNow, "user"))
+			cmd, _ := newTestCommand()
+
+			err := runCreate(cmd, []string{"hotfix"}, newTestDeps(g))
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			sha, err := g.ResolveRef(t.Context(), "feature/hotfix")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCommit, sha)
+		})
+	}
+}
+
+func TestRunCreate_Root(t *testing.T) {
+	tests := []struct {
+		name      string
+		root      string
+		mainPath  string
+		remoteURL string
+		wantPath  string
+	}{
+		{name: "absolute", root: "/wt", wantPath: "/wt/wt-hotfix"},
+		{name: "repo name from origin", root: "/wt/{{.RepoName}}", remoteURL: "git@github.com:acme/widgets.git", wantPath: "/wt/widgets/wt-hotfix"},
+		{name: "repo name from main worktree", root: "/wt/{{.RepoName}}", wantPath: "/wt/main/wt-hotfix"},
+		{name: "repo name from bare repository", root: "/wt/{{.RepoName}}", mainPath: "/ws/widgets.git", wantPath: "/wt/widgets/wt-hotfix"},
+		{name: "repo name from hidden bare repository", root: "/wt/{{.RepoName}}", mainPath: "/ws/widgets/.bare", wantPath: "/wt/widgets/wt-hotfix"},
+		{name: "home directory", root: "~/worktrees", wantPath: "/home/me/worktrees/wt-hotfix"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", "/home/me")
+			g := newTestGit()
+			if tt.remoteURL != "" {
+				g.SetRemoteURL("origin", tt.remoteURL)
+			}
+			deps := newTestDeps(g)
+			deps.Config.Worktree.Root = tt.root
+			if tt.mainPath != "" {
+				deps.MainWorktreePath = tt.mainPath
+			}
+			cmd, out := newTestCommand()
+
+			err := runCreate(cmd, []string{"hotfix"}, deps)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPath+"\n", out.String())
+			assertWorktreeOnBranch(t, g, tt.wantPath, "feature/hotfix")
+		})
+	}
+}
+
+func TestResolveBaseRef(t *testing.T) {
+	tests := []struct {
+		name        string
+		configBase  string
+		baseFlag    string
+		baseDefault bool
+		remoteHead  string
+		detected    string
+		want        string
+		wantErr     string
+	}{
+		{name: "config head", configBase: "head", want: ""},
+		{name: "config default", configBase: "default", remoteHead: "main", want: "origin/main"},
+		{name: "config ref", configBase: "release", want: "release"},
+		{name: "--base overrides config", configBase: "default", baseFlag: "release", want: "release"},
+		{name: "--base-default overrides config", configBase: "release", baseDefault: true, remoteHead: "main", want: "origin/main"},
+		{name: "remote default branch detected", configBase: "default", detected: "main", want: "origin/main"},
+		{name: "remote default branch unknown", configBase: "default", wantErr: "could not detect the default branch of origin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseFlag, baseDefaultFlag = tt.baseFlag, tt.baseDefault
+			t.Cleanup(func() { baseFlag, baseDefaultFlag = "", false })
+
+			g := newTestGit().
+				AddBranch("release", git.NewCommit("bbb2222", "Release", testNow, "user")).
+				AddRemoteRef("origin", "main", git.NewCommit("ddd4444", "Remote main", testNow, "user"))
+			if tt.remoteHead != "" {
+				g.SetRemoteHead("origin", tt.remoteHead)
+			}
+			g.SetRemoteDefaultBranch("origin", tt.detected)
+			deps := newTestDeps(g)
+			deps.Config.Branch.Base = tt.configBase
+
+			got, err := resolveBaseRef(deps)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// assertWorktreeRecorded asserts that the state entry for path matches want.
+func assertWorktreeRecorded(t *testing.T, deps *Deps, path string, want state.Worktree) {
+	t.Helper()
+	st, err := deps.State.Load()
+	require.NoError(t, err)
+	assert.Equal(t, want, st.Get(path))
+}
+
+// assertWorktreeOnBranch asserts that a worktree exists at path with branch checked out.
+func assertWorktreeOnBranch(t *testing.T, g git.Git, path, branchName string) {
+	t.Helper()
+	worktrees, err := g.ListWorktrees(t.Context())
+	require.NoError(t, err)
+	for _, wt := range worktrees {
+		if wt.AbsolutePath == path {
+			branch, ok := wt.Ref.FullBranch()
+			require.True(t, ok, "worktree %s is not on a branch", path)
+			assert.Equal(t, branchName, branch.Name)
+			return
+		}
+	}
+	t.Errorf("no worktree at %s", path)
+}
+
+func TestRunCreateFromRemote(t *testing.T) {
+	tests := []struct {
+		name         string
+		remoteBranch string
+		wantBranch   string
+		wantPath     string
+		wantErr      string
+	}{
+		{
+			name:         "creates tracking branch and worktree",
+			remoteBranch: "origin/fix/login-bug",
+			wantBranch:   "fix/login-bug",
+			wantPath:     "/ws/wt-fix-login-bug",
+		},
+		{
+			name:         "configured prefix is stripped from worktree name",
+			remoteBranch: "origin/feature/search",
+			wantBranch:   "feature/search",
+			wantPath:     "/ws/wt-search",
+		},
+		{
+			name:         "missing remote name",
+			remoteBranch: "some-branch",
+			wantErr:      "expected <remote>/<branch>",
+		},
+		{
+			name:         "unknown remote",
+			remoteBranch: "upstream/main",
+			wantErr:      `remote "upstream" does not exist`,
+		},
+		{
+			name:         "branch missing on remote",
+			remoteBranch: "origin/nope",
+			wantErr:      "failed to fetch origin/nope",
+		},
+		{
+			name:         "local branch already exists",
+			remoteBranch: "origin/main",
+			wantErr:      `branch "main" already exists`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGit().
+				AddRemoteRef("origin", "main", git.NewCommit("abc1234", "Initial", testNow, "user")).
+				AddRemoteRef("origin", "fix/login-bug", git.NewCommit("bbb2222", "Fix login", testNow, "user")).
+				AddRemoteRef("origin", "feature/search", git.NewCommit("ccc3333", "Search", testNow, "user"))
+			cmd, out := newTestCommand()
+
+			err := runCreateFromRemote(cmd, tt.remoteBranch, newTestDeps(g))
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPath+"\n", out.String())
+			assertWorktreeOnBranch(t, g, tt.wantPath, tt.wantBranch)
+		})
+	}
+}
+
+func TestRunCreate_Stdin(t *testing.T) {
+	g := newTestGit()
+	cmd, out := newTestCommand()
+	cmd.SetIn(strings.NewReader("\nAdd User Auth\n\nSessions expire too early.\n# not a comment\n"))
+
+	require.NoError(t, runCreate(cmd, nil, newTestDeps(g)))
+
+	assert.Equal(t, "/ws/wt-add-user-auth\n", out.String())
+	description, err := g.GetBranchDescription(t.Context(), "feature/add-user-auth")
+	require.NoError(t, err)
+	assert.Equal(t, "Sessions expire too early.\n# not a comment", description)
+}
+
+func TestRunCreate_StdinEmpty(t *testing.T) {
+	cmd, _ := newTestCommand()
+	cmd.SetIn(strings.NewReader("\n  \n"))
+
+	err := runCreate(cmd, nil, newTestDeps(newTestGit()))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "phrase cannot be empty")
+}
+
+func TestRunCreate_StdinDevNull(t *testing.T) {
+	devNull, err := os.Open(os.DevNull)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = devNull.Close() })
+	deps := newTestDeps(newTestGit())
+	deps.Exec = func(name string, args ...string) error {
+		t.Errorf("the editor was opened: %s %v", name, args)
+		return nil
+	}
+	cmd, _ := newTestCommand()
+	cmd.SetIn(devNull)
+
+	err = runCreate(cmd, nil, deps)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "phrase cannot be empty", "/dev/null is read as an empty stdin, not taken for a terminal")
+}
+
+func TestEditCreateMessage(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "code --wait")
+	deps := newTestDeps(newTestGit())
+	var calls [][]string
+	deps.Exec = func(name string, args ...string) error {
+		calls = append(calls, append([]string{name}, args...))
+		path := args[len(args)-1]
+		template, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return os.WriteFile(path, append([]byte("fix login\nSessions expire too early.\n"), template...), 0o644)
+	}
+
+	phrase, description, err := editCreateMessage(deps)
+
+	require.NoError(t, err)
+	assert.Equal(t, "fix login", phrase)
+	assert.Equal(t, "Sessions expire too early.", description)
+	require.Len(t, calls, 1)
+	assert.Equal(t, []string{"sh", "-c", `code --wait "$@"`, "code --wait"}, calls[0][:4])
+}
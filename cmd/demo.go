@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jmcampanini/grove-cli/internal/clipboard"
+	"github.com/jmcampanini/grove-cli/internal/config"
+	"github.com/jmcampanini/grove-cli/internal/fsutil"
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/git/fake"
+	"github.com/jmcampanini/grove-cli/internal/github"
+	"github.com/jmcampanini/grove-cli/internal/state"
+)
+
+var demoFlag bool
+
+const demoMainWorktreePath = "/demo/acme/webapp/main"
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&demoFlag, "demo", false, "Run against an in-memory demo repository instead of the current directory")
+}
+
+// errDemoGitHub is returned by GitHub operations in demo mode.
+var errDemoGitHub = errors.New("pull requests are not available in --demo mode")
+
+// demoGitHub is the GitHub provider used in demo mode; every operation fails with errDemoGitHub.
+type demoGitHub struct{}
+
+var _ github.GitHub = demoGitHub{}
+
+func (demoGitHub) AuthStatus(context.Context) error { return errDemoGitHub }
+
+func (demoGitHub) CreatePullRequest(context.Context, github.NewPullRequest) (string, error) {
+	return "", errDemoGitHub
+}
+
+func (demoGitHub) GetPullRequest(context.Context, int) (github.PullRequest, error) {
+	return github.PullRequest{}, errDemoGitHub
+}
+
+func (demoGitHub) GetPullRequestByBranch(context.Context, string) (*github.PullRequest, error) {
+	return nil, errDemoGitHub
+}
+
+func (demoGitHub) GetPullRequestDiff(context.Context, int) (string, error) { return "", errDemoGitHub }
+
+func (demoGitHub) GetRepository(context.Context) (github.Repository, error) {
+	return github.Repository{}, errDemoGitHub
+}
+
+func (demoGitHub) ListPullRequestFiles(context.Context, int) ([]string, error) {
+	return nil, errDemoGitHub
+}
+
+func (demoGitHub) ListPullRequests(context.Context, github.PRQuery, int) ([]github.PullRequest, error) {
+	return nil, errDemoGitHub
+}
+
+func (demoGitHub) WritePullRequestDiff(context.Context, int, io.Writer) error { return errDemoGitHub }
+
+// newDemoDeps returns Deps backed by an in-memory repository with sample branches, tags, and worktrees.
+// Mutating commands update the in-memory model, so nothing on disk is touched.
+func newDemoDeps(ctx context.Context) *Deps {
+	now := time.Now()
+	commit := func(sha, subject string, age time.Duration, author string) git.Commit {
+		return git.NewCommit(sha, subject, now.Add(-age), author)
+	}
+
+	release := commit("9c1d2e3f4a5b6c7d", "Release 2.3.0", 72*time.Hour, "Dana")
+	g := fake.New(demoMainWorktreePath, commit("1a2b3c4d5e6f7a8b", "Merge pull request #41 from acme/search", 2*time.Hour, "Dana")).
+		AddRemoteRef("origin", "main", commit("1a2b3c4d5e6f7a8b", "Merge pull request #41 from acme/search", 2*time.Hour, "Dana")).
+		SetRemoteHead("origin", "main").
+		SetUpstream("main", "origin/main", 0, 0).
+		AddBranch("feature/add-user-auth", commit("2b3c4d5e6f7a8b9c", "Add login form", 30*time.Minute, "Alex")).
+		SetUpstream("feature/add-user-auth", "origin/feature/add-user-auth", 2, 0).
+		AddWorktree("/demo/acme/webapp/wt-add-user-auth", "feature/add-user-auth").
+		AddBranch("feature/fix-flaky-tests", commit("3c4d5e6f7a8b9c0d", "Retry network tests", 26*time.Hour, "Sam")).
+		AddWorktree("/demo/acme/webapp/wt-fix-flaky-tests", "feature/fix-flaky-tests").
+		AddBranch("spike", commit("4d5e6f7a8b9c0d1e", "Try new router", 400*time.Hour, "Alex")).
+		AddWorktree("/demo/acme/webapp/spike", "spike").
+		AddTag(git.NewTag("v2.3.0", release, "Release 2.3.0", "Dana", "dana@example.com", release.CommittedOn)).
+		AddDetachedWorktree("/demo/acme/webapp/wt-v2-3-0", release)
+
+	demoState := state.New()
+	demoState.Set("/demo/acme/webapp/spike", state.Worktree{Pinned: true})
+	stateStore := state.NewMemoryStore()
+	_ = stateStore.Save(demoState)
+
+	return &Deps{
+		Clipboard:        clipboard.Read,
+		Clock:            time.Now,
+		Config:           config.DefaultConfig(),
+		ConfigSources:    map[string]string{},
+		Ctx:              ctx,
+		Cwd:              demoMainWorktreePath,
+		Exec:             demoExec,
+		FS:               fsutil.OS{},
+		Git:              g,
+		GitHub:           demoGitHub{},
+		MainWorktreePath: demoMainWorktreePath,
+		State:            stateStore,
+		WorktreeRoot:     demoMainWorktreePath,
+	}
+}
+
+// demoExec reports the external command instead of running it, since demo worktree paths do not exist.
+func demoExec(name string, args ...string) error {
+	_, err := fmt.Fprintf(os.Stderr, "demo: would run %s\n", strings.Join(append([]string{name}, args...), " "))
+	return err
+}
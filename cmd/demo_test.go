@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDemoDeps(t *testing.T) {
+	deps := newDemoDeps(t.Context())
+
+	cmd, out := newTestCommand()
+	require.NoError(t, runList(cmd, nil, deps))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, demoMainWorktreePath, lines[0], "main worktree is listed first")
+	assert.Greater(t, len(lines), 1, "demo repository has linked worktrees")
+
+	cmd, out = newTestCommand()
+	require.NoError(t, runCreate(cmd, []string{"try demo"}, deps))
+	assert.Equal(t, "/demo/acme/webapp/wt-try-demo\n", out.String())
+}
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	clog "github.com/charmbracelet/log"
+	"github.com/jmcampanini/grove-cli/internal/clipboard"
+	"github.com/jmcampanini/grove-cli/internal/config"
+	"github.com/jmcampanini/grove-cli/internal/events"
+	"github.com/jmcampanini/grove-cli/internal/fsutil"
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/github"
+	"github.com/jmcampanini/grove-cli/internal/jsonout"
+	"github.com/jmcampanini/grove-cli/internal/pathutil"
+	"github.com/jmcampanini/grove-cli/internal/state"
+	"github.com/jmcampanini/grove-cli/internal/summary"
+)
+
+// errNotInRepo is returned by commands that need a repository when grove is run outside one.
+var errNotInRepo = errors.New("grove must be run inside a git repository")
+
+// Deps holds the collaborators shared by grove commands.
+// Commands receive a Deps instead of constructing clients themselves so they can be unit tested.
+type Deps struct {
+	Clipboard        func() (string, error)
+	Clock            func() time.Time // the current time; use it instead of time.Now so tests can fix the time
+	Config           config.Config
+	ConfigPaths      []string          // config files that were loaded, lowest priority first
+	ConfigSources    map[string]string // config key -> file path, env variable, or "--set"; absent keys are defaults
+	Ctx              context.Context   // passed to every git and gh call; cancelled on interrupt
+	Cwd              string
+	Events           *events.Emitter // nil unless --json-events
+	Exec             func(name string, args ...string) error
+	FS               fsutil.FS
+	Git              git.Git
+	GitHub           github.GitHub
+	LockDir          string // where locks between grove processes are taken; "" disables locking (demo, --dry-run)
+	MainWorktreePath string
+	OpenGit          func(dir string) git.Git         // a git client for the repository at dir, e.g. one grove init cloned; nil in demo mode
+	OpenRepo         func(path string) (*Deps, error) // builds Deps for another repository, for --all-repos; nil in demo mode
+	State            state.Store
+	Summary          *summary.Recorder // nil unless --summary-file
+	Warnings         []jsonout.Warning // non-fatal issues met while building Deps, reported in JSON output
+	WorktreeRoot     string
+}
+
+// newDeps builds Deps for the current working directory using the real git and gh CLIs.
+// It enforces the command's requirements and loads the merged config.
+// With --demo, an in-memory demo repository is used instead.
+func newDeps(ctx context.Context, req requirements) (*Deps, error) {
+	if demoFlag {
+		deps := newDemoDeps(ctx)
+		if err := applySetFlags(&deps.Config, deps.ConfigSources); err != nil {
+			return nil, err
+		}
+		return deps, nil
+	}
+
+	if req.NeedsProvider {
+		if err := github.Available(); err != nil {
+			return nil, err
+		}
+	}
+
+	cwd, err := resolveCwd(cwdFlag)
+	if err != nil {
+		return nil, err
+	}
+	return newDepsIn(ctx, req, cwd)
+}
+
+// newDepsIn builds Deps for the repository containing cwd, as newDeps does for the current directory.
+func newDepsIn(ctx context.Context, req requirements, cwd string) (*Deps, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user home directory: %w", err)
+	}
+
+	defaults := config.DefaultConfig().Git
+	gitClient := git.New(logger, timings, false, cwd, defaults.Timeout, defaults.NetworkTimeout, 0)
+
+	worktreeRoot, err := gitClient.GetWorktreeRoot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("git error: %w", err)
+	}
+	if worktreeRoot == "" && req.NeedsRepo {
+		return nil, errNotInRepo
+	}
+
+	fs := fsutil.OS{}
+	var mainWorktreePath, lockDir string
+	var stateStore state.Store = state.NewMemoryStore()
+	if worktreeRoot != "" {
+		mainWorktreePath, err = gitClient.GetMainWorktreePath(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get main worktree path: %w", err)
+		}
+		commonDir, err := gitClient.GetCommonDir(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get git common dir: %w", err)
+		}
+		lockDir = filepath.Join(commonDir, "grove")
+		stateStore = state.NewFileStore(lockDir).WithFS(fs)
+	}
+	execFn := execAttached
+	if dryRunFlag {
+		lockDir = ""
+		stateStore = dryRunStore{stateStore}
+		execFn = dryRunExec
+	}
+
+	configPaths := config.ConfigPaths(cwd, worktreeRoot, mainWorktreePath, homeDir)
+	var warnings []jsonout.Warning
+	loadResult, err := config.NewLoader(fs).Load(configPaths)
+	if err != nil {
+		if !req.AllowInvalidConfig {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+		warnings = append(warnings, jsonout.Warning{Code: "invalid-config", Message: "using the default config: " + err.Error()})
+		loadResult = config.LoadResult{Config: config.DefaultConfig(), Sources: map[string]string{}}
+	}
+	for _, path := range loadResult.SourcePaths {
+		if keys := loadResult.UnknownKeys[path]; len(keys) > 0 {
+			warnings = append(warnings, jsonout.Warning{Code: "unknown-config-keys", Message: "unknown config keys: " + strings.Join(keys, ", "), Subject: path})
+		}
+	}
+	cfg := loadResult.Config
+	if err := applySetFlags(&cfg, loadResult.Sources); err != nil && !req.AllowInvalidConfig {
+		return nil, err
+	}
+
+	return &Deps{
+		Clipboard:     clipboard.Read,
+		Clock:         time.Now,
+		Config:        cfg,
+		ConfigPaths:   loadResult.SourcePaths,
+		ConfigSources: loadResult.Sources,
+		Ctx:           ctx,
+		Cwd:           cwd,
+		Exec:          execFn,
+		FS:            fs,
+		// recreate the git client using the config timeout; read-only commands never mutate the repo
+		Git:              git.New(logger, timings, dryRunFlag || !req.Mutating, cwd, cfg.Git.Timeout, cfg.Git.NetworkTimeout, cfg.Git.Retries),
+		GitHub:           github.New(logger, timings, cwd, cfg.Git.NetworkTimeout, cfg.GitHub.Retries),
+		LockDir:          lockDir,
+		MainWorktreePath: mainWorktreePath,
+		OpenGit: func(dir string) git.Git {
+			return git.New(logger, timings, dryRunFlag || !req.Mutating, dir, cfg.Git.Timeout, cfg.Git.NetworkTimeout, cfg.Git.Retries)
+		},
+		OpenRepo: func(path string) (*Deps, error) {
+			repoCwd, err := resolveCwd(path)
+			if err != nil {
+				return nil, err
+			}
+			repoReq := req
+			repoReq.NeedsRepo = true
+			return newDepsIn(ctx, repoReq, repoCwd)
+		},
+		State:        stateStore,
+		Warnings:     warnings,
+		WorktreeRoot: worktreeRoot,
+	}, nil
+}
+
+// resolveCwd returns the absolute directory grove operates in: dir if given (--cwd), otherwise the process directory.
+// Symlinks are resolved so the directory compares equal to the worktree paths git reports.
+func resolveCwd(dir string) (string, error) {
+	if dir == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("failed to get current directory: %w", err)
+		}
+		return pathutil.Normalize(cwd), nil
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("invalid --cwd %q: %w", dir, err)
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return "", fmt.Errorf("invalid --cwd %q: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("invalid --cwd %q: not a directory", dir)
+	}
+	return pathutil.Normalize(abs), nil
+}
+
+// configSourceSetFlag is the provenance recorded for keys overridden with --set.
+const configSourceSetFlag = "--set"
+
+// applySetFlags applies --set overrides on top of the loaded config and records them in sources.
+func applySetFlags(cfg *config.Config, sources map[string]string) error {
+	keys, err := cfg.ApplyOverrides(setFlags)
+	if err != nil {
+		return fmt.Errorf("invalid --set: %w", err)
+	}
+	for _, key := range keys {
+		sources[key] = configSourceSetFlag
+	}
+	return nil
+}
+
+// dryRunStore reads state normally but only logs writes, for --dry-run.
+type dryRunStore struct {
+	state.Store
+}
+
+func (dryRunStore) Save(st state.State) error {
+	clog.Default().WithPrefix("state").Info("Would save state", "worktrees", len(st.Worktrees))
+	return nil
+}
+
+// dryRunExec logs an external command instead of running it, for --dry-run.
+func dryRunExec(name string, args ...string) error {
+	clog.Default().Info("Would run command", "cmd", name, "args", args)
+	return nil
+}
+
+// execAttached runs an external program attached to grove's stdin, stdout, and stderr.
+func execAttached(name string, args ...string) error {
+	c := exec.Command(name, args...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
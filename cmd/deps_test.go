@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jmcampanini/grove-cli/internal/config"
+	"github.com/jmcampanini/grove-cli/internal/fsutil"
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/git/fake"
+	"github.com/jmcampanini/grove-cli/internal/github"
+	"github.com/jmcampanini/grove-cli/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// testNow is the fixed time returned by the clock in test Deps.
+var testNow = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// stubGitHub implements github.GitHub for tests. Methods that are not overridden panic via the nil embedded interface.
+type stubGitHub struct {
+	github.GitHub
+	authErr error
+	created []github.NewPullRequest // pull requests passed to CreatePullRequest
+	diff    string
+	files   []string
+	limit   int // last limit passed to ListPullRequests
+	prs     []github.PullRequest
+	query   github.PRQuery // last query passed to ListPullRequests
+	repo    github.Repository
+}
+
+func (s *stubGitHub) AuthStatus(context.Context) error { return s.authErr }
+
+func (s *stubGitHub) CreatePullRequest(_ context.Context, pr github.NewPullRequest) (string, error) {
+	s.created = append(s.created, pr)
+	return fmt.Sprintf("https://github.com/o/r/pull/%d", 100+len(s.created)), nil
+}
+
+func (s *stubGitHub) GetPullRequest(_ context.Context, prNum int) (github.PullRequest, error) {
+	for _, pr := range s.prs {
+		if pr.Number == prNum {
+			return pr, nil
+		}
+	}
+	return github.PullRequest{}, fmt.Errorf("pull request #%d not found", prNum)
+}
+
+func (s *stubGitHub) GetPullRequestByBranch(_ context.Context, branchName string) (*github.PullRequest, error) {
+	for _, pr := range s.prs {
+		if pr.BranchName == branchName {
+			return &pr, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *stubGitHub) GetPullRequestDiff(context.Context, int) (string, error) { return s.diff, nil }
+
+// WritePullRequestDiff writes the diff with a trailing newline, as gh does.
+func (s *stubGitHub) WritePullRequestDiff(_ context.Context, _ int, w io.Writer) error {
+	_, err := fmt.Fprintln(w, s.diff)
+	return err
+}
+
+func (s *stubGitHub) GetRepository(context.Context) (github.Repository, error) { return s.repo, nil }
+
+func (s *stubGitHub) ListPullRequestFiles(context.Context, int) ([]string, error) {
+	return s.files, nil
+}
+
+func (s *stubGitHub) ListPullRequests(_ context.Context, query github.PRQuery, limit int) ([]github.PullRequest, error) {
+	s.limit = limit
+	s.query = query
+	return s.prs, nil
+}
+
+// newTestGit creates a fake repository whose main worktree is /ws/main.
+func newTestGit() *fake.Git {
+	return fake.New("/ws/main", git.NewCommit("abc1234def5678", "Initial", testNow, "user"))
+}
+
+// newTestDeps creates Deps backed by the given git client, default config, and a fixed clock.
+func newTestDeps(g git.Git) *Deps {
+	return &Deps{
+		Clock:            func() time.Time { return testNow },
+		Config:           config.DefaultConfig(),
+		ConfigSources:    map[string]string{},
+		Ctx:              context.Background(),
+		Cwd:              "/ws/main",
+		FS:               fsutil.NewMemory(),
+		Git:              g,
+		MainWorktreePath: "/ws/main",
+		State:            state.NewMemoryStore(),
+		WorktreeRoot:     "/ws/main",
+	}
+}
+
+// newTestCommand returns a cobra command whose stdout is captured in the returned buffer.
+func newTestCommand() (*cobra.Command, *bytes.Buffer) {
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	return cmd, &out
+}
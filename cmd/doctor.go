@@ -0,0 +1,341 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jmcampanini/grove-cli/internal/config"
+	"github.com/jmcampanini/grove-cli/internal/github"
+	"github.com/spf13/cobra"
+)
+
+// minGitVersion is the oldest git that supports every worktree command grove uses (git worktree move).
+var minGitVersion = [2]int{2, 17}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that grove's environment is set up correctly",
+	Long: `Doctor checks the tools and files grove depends on and prints a pass, warning,
+or failure for each, with a suggested fix:
+
+  git        git is installed and new enough for worktree commands
+  gh         the GitHub CLI is installed and authenticated (needed for grove pr)
+  config     grove.toml files parse and the merged config is valid
+  workspace  the directory that holds the worktrees is writable
+  worktrees  no worktrees have stale administrative files (git worktree prune candidates)
+  worktree config
+             core.worktree and core.bare are not shared by every worktree, and whether
+             extensions.worktreeConfig keeps git config --worktree writes per worktree
+
+Exits with an error if any check fails.
+
+With --enable-worktree-config, doctor turns on extensions.worktreeConfig before the
+checks run, first moving core.worktree and core.bare=true out of the shared config
+into the main worktree's config.worktree, as git-worktree(1) recommends. Nothing in
+git's config changes without the flag.
+
+With --json-events, each check is reported as an item-completed event whose status
+is "pass", "warn", or "fail".`,
+	Args: cobra.NoArgs,
+	// mutating only with --enable-worktree-config; the checks themselves never change the repository
+	RunE: withDeps(requirements{AllowInvalidConfig: true, EmitsEvents: true, Mutating: true}, runDoctor),
+}
+
+var doctorEnableWorktreeConfigFlag bool
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorEnableWorktreeConfigFlag, "enable-worktree-config", false, "Turn on extensions.worktreeConfig, moving worktree-specific settings out of the shared config")
+	rootCmd.AddCommand(doctorCmd)
+}
+
+type checkStatus int
+
+const (
+	checkPass checkStatus = iota
+	checkWarn
+	checkFail
+)
+
+func (s checkStatus) String() string {
+	switch s {
+	case checkWarn:
+		return "warn"
+	case checkFail:
+		return "fail"
+	default:
+		return "pass"
+	}
+}
+
+// symbol returns the mark shown for the status in text output.
+func (s checkStatus) symbol() string {
+	switch s {
+	case checkWarn:
+		return "!"
+	case checkFail:
+		return "✗"
+	default:
+		return "✓"
+	}
+}
+
+// doctorCheck is the outcome of a single diagnostic.
+type doctorCheck struct {
+	Detail string
+	Fix    string // how to resolve a warning or failure
+	Name   string
+	Status checkStatus
+}
+
+func runDoctor(cmd *cobra.Command, _ []string, deps *Deps) error {
+	if doctorEnableWorktreeConfigFlag {
+		if deps.MainWorktreePath == "" {
+			return errors.New("--enable-worktree-config must be run inside a git repository")
+		}
+		if err := deps.Git.EnableWorktreeConfig(deps.Ctx); err != nil {
+			return err
+		}
+	}
+
+	checkFuncs := []func(*Deps) doctorCheck{
+		checkGit,
+		checkGitHub,
+		checkConfig,
+		checkWorkspace,
+		checkWorktrees,
+		checkWorktreeConfig,
+	}
+	if err := deps.Events.Started(len(checkFuncs)); err != nil {
+		return err
+	}
+
+	var checks []doctorCheck
+	failed := 0
+	for _, check := range checkFuncs {
+		c := check(deps)
+		if err := reportItem(deps, c.Name, c.Status.String(), c.Detail); err != nil {
+			return err
+		}
+		if c.Status == checkFail {
+			failed++
+		}
+		checks = append(checks, c)
+	}
+
+	if deps.Events == nil {
+		if err := printDoctorChecks(cmd, checks); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d doctor check(s) failed", failed)
+	}
+	return nil
+}
+
+func printDoctorChecks(cmd *cobra.Command, checks []doctorCheck) error {
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	for _, c := range checks {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", c.Status.symbol(), c.Name, c.Detail); err != nil {
+			return err
+		}
+		if c.Fix != "" {
+			if _, err := fmt.Fprintf(w, "\t\tfix: %s\n", c.Fix); err != nil {
+				return err
+			}
+		}
+	}
+	return w.Flush()
+}
+
+func checkGit(deps *Deps) doctorCheck {
+	c := doctorCheck{Name: "git"}
+	version, err := deps.Git.GetVersion(deps.Ctx)
+	if err != nil {
+		c.Status, c.Detail, c.Fix = checkFail, err.Error(), "install git from https://git-scm.com"
+		return c
+	}
+	if !versionAtLeast(version, minGitVersion) {
+		c.Status = checkFail
+		c.Detail = fmt.Sprintf("git %s is too old for worktree commands", version)
+		c.Fix = fmt.Sprintf("upgrade git to %d.%d or newer", minGitVersion[0], minGitVersion[1])
+		return c
+	}
+	c.Detail = "git " + version
+	return c
+}
+
+// versionAtLeast reports whether a git version like "2.39.3" or "2.41.0.windows.1" is at least minimum (major, minor).
+func versionAtLeast(version string, minimum [2]int) bool {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return false
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return false
+	}
+	return major > minimum[0] || (major == minimum[0] && minor >= minimum[1])
+}
+
+// checkGitHub warns rather than fails: only grove pr commands need gh.
+func checkGitHub(deps *Deps) doctorCheck {
+	c := doctorCheck{Name: "gh"}
+	if err := github.Available(); err != nil {
+		c.Status, c.Detail, c.Fix = checkWarn, "not installed; grove pr commands are unavailable", "install it from https://cli.github.com"
+		return c
+	}
+	if err := deps.GitHub.AuthStatus(deps.Ctx); err != nil {
+		c.Status, c.Detail, c.Fix = checkWarn, "not authenticated", "run gh auth login"
+		return c
+	}
+	c.Detail = "installed and authenticated"
+	return c
+}
+
+func checkConfig(deps *Deps) doctorCheck {
+	c := doctorCheck{Name: "config"}
+	homeDir, _ := os.UserHomeDir()
+	paths := config.ConfigPaths(deps.Cwd, deps.WorktreeRoot, deps.MainWorktreePath, homeDir)
+
+	result, err := config.NewLoader(deps.FS).Load(paths)
+	if err == nil {
+		cfg := result.Config
+		if _, setErr := cfg.ApplyOverrides(setFlags); setErr != nil {
+			err = fmt.Errorf("invalid --set: %w", setErr)
+		}
+	}
+	if err != nil {
+		c.Status, c.Detail, c.Fix = checkFail, err.Error(), "fix the reported key, or run grove config show once it loads"
+		return c
+	}
+
+	if len(result.SourcePaths) == 0 {
+		c.Detail = "no grove.toml files; using defaults"
+		return c
+	}
+	c.Detail = "loaded " + strings.Join(result.SourcePaths, ", ")
+	return c
+}
+
+func checkWorkspace(deps *Deps) doctorCheck {
+	c := doctorCheck{Name: "workspace"}
+	if deps.MainWorktreePath == "" {
+		c.Status, c.Detail = checkWarn, "not inside a git repository; skipped"
+		return c
+	}
+
+	parentDir, err := worktreeParentDir(deps)
+	if err != nil {
+		c.Status, c.Detail = checkFail, err.Error()
+		if deps.Config.Worktree.Root != "" {
+			c.Fix = "fix worktree.root in your grove.toml"
+		}
+		return c
+	}
+	// git creates a missing [worktree] root along with the first worktree, so check where it would be created
+	dir := parentDir
+	for deps.Config.Worktree.Root != "" {
+		if _, err := os.Stat(dir); !errors.Is(err, os.ErrNotExist) || dir == filepath.Dir(dir) {
+			break
+		}
+		dir = filepath.Dir(dir)
+	}
+	if err := checkWritable(dir); err != nil {
+		c.Status = checkFail
+		c.Detail = err.Error()
+		c.Fix = fmt.Sprintf("grove creates worktrees in %s; make it writable", parentDir)
+		return c
+	}
+	if dir != parentDir {
+		c.Detail = fmt.Sprintf("%s will be created; %s is writable", parentDir, dir)
+		return c
+	}
+	c.Detail = parentDir + " is writable"
+	return c
+}
+
+// checkWritable reports whether new files can be created in dir by creating and removing a temp file.
+func checkWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".grove-doctor-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Remove(name)
+}
+
+func checkWorktrees(deps *Deps) doctorCheck {
+	c := doctorCheck{Name: "worktrees"}
+	if deps.MainWorktreePath == "" {
+		c.Status, c.Detail = checkWarn, "not inside a git repository; skipped"
+		return c
+	}
+
+	worktrees, err := deps.Git.ListWorktrees(deps.Ctx)
+	if err != nil {
+		c.Status, c.Detail = checkFail, err.Error()
+		return c
+	}
+
+	var stale []string
+	for _, wt := range worktrees {
+		if wt.Prunable != "" {
+			stale = append(stale, fmt.Sprintf("%s (%s)", wt.AbsolutePath, wt.Prunable))
+		}
+	}
+	if len(stale) > 0 {
+		c.Status = checkWarn
+		c.Detail = fmt.Sprintf("%d stale: %s", len(stale), strings.Join(stale, ", "))
+		c.Fix = "run git worktree prune"
+		return c
+	}
+	c.Detail = fmt.Sprintf("%d worktree(s), none stale", len(worktrees))
+	return c
+}
+
+// checkWorktreeConfig looks for settings in the shared git config that only make sense for one worktree.
+// A shared core.worktree points every linked worktree at the same directory, and with extensions.worktreeConfig
+// on, a shared core.bare=true makes git treat linked worktrees as bare.
+func checkWorktreeConfig(deps *Deps) doctorCheck {
+	c := doctorCheck{Name: "worktree config"}
+	if deps.MainWorktreePath == "" {
+		c.Status, c.Detail = checkWarn, "not inside a git repository; skipped"
+		return c
+	}
+
+	state, err := deps.Git.GetWorktreeConfigState(deps.Ctx)
+	if err != nil {
+		c.Status, c.Detail = checkFail, err.Error()
+		return c
+	}
+	fix := "run grove doctor --enable-worktree-config to move it into the main worktree's config.worktree"
+	switch {
+	case state.SharedWorktree != "":
+		c.Status = checkWarn
+		c.Detail = fmt.Sprintf("core.worktree=%s is in the shared config, so every worktree uses it", state.SharedWorktree)
+		c.Fix = fix
+	case state.Enabled && state.SharedBare:
+		c.Status = checkFail
+		c.Detail = "core.bare=true is in the shared config while extensions.worktreeConfig is on, so linked worktrees look bare"
+		c.Fix = fix
+	case state.Enabled:
+		c.Detail = "extensions.worktreeConfig is on; git config --worktree stays in each worktree"
+	default:
+		c.Detail = "extensions.worktreeConfig is off; every worktree shares one git config"
+	}
+	return c
+}
@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/events"
+	"github.com/jmcampanini/grove-cli/internal/fsutil"
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/git/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{version: "2.17.0", want: true},
+		{version: "2.43.0", want: true},
+		{version: "3.0.0", want: true},
+		{version: "2.41.0.windows.1", want: true},
+		{version: "2.16.6", want: false},
+		{version: "1.9.5", want: false},
+		{version: "2", want: false},
+		{version: "unknown", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.want, versionAtLeast(tt.version, [2]int{2, 17}))
+		})
+	}
+}
+
+func TestCheckGit(t *testing.T) {
+	tests := []struct {
+		name       string
+		version    string
+		wantStatus checkStatus
+		wantDetail string
+	}{
+		{name: "supported", version: "2.45.0", wantStatus: checkPass, wantDetail: "git 2.45.0"},
+		{name: "too old", version: "2.7.4", wantStatus: checkFail, wantDetail: "git 2.7.4 is too old for worktree commands"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := newTestDeps(newTestGit().SetVersion(tt.version))
+
+			got := checkGit(deps)
+
+			assert.Equal(t, tt.wantStatus, got.Status)
+			assert.Equal(t, tt.wantDetail, got.Detail)
+		})
+	}
+}
+
+func TestCheckGitHub(t *testing.T) {
+	withGh := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(withGh, "gh"), []byte("#!/bin/sh\n"), 0o755))
+
+	tests := []struct {
+		name       string
+		path       string
+		authErr    error
+		wantStatus checkStatus
+		wantDetail string
+	}{
+		{name: "authenticated", path: withGh, wantStatus: checkPass, wantDetail: "installed and authenticated"},
+		{name: "not authenticated", path: withGh, authErr: errors.New("not logged in"), wantStatus: checkWarn, wantDetail: "not authenticated"},
+		{name: "not installed", path: t.TempDir(), wantStatus: checkWarn, wantDetail: "not installed; grove pr commands are unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PATH", tt.path)
+			deps := newTestDeps(newTestGit())
+			deps.GitHub = &stubGitHub{authErr: tt.authErr}
+
+			got := checkGitHub(deps)
+
+			assert.Equal(t, tt.wantStatus, got.Status)
+			assert.Equal(t, tt.wantDetail, got.Detail)
+		})
+	}
+}
+
+func TestCheckConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		wantStatus checkStatus
+		wantDetail string
+	}{
+		{name: "no config files", wantStatus: checkPass, wantDetail: "no grove.toml files; using defaults"},
+		{name: "valid file", content: "[branch]\nnew_prefix = \"jm/\"\n", wantStatus: checkPass, wantDetail: "loaded "},
+		{name: "unparseable file", content: "[branch\n", wantStatus: checkFail, wantDetail: "failed to parse"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Setenv("XDG_CONFIG_HOME", dir)
+			t.Setenv("HOME", dir)
+			if tt.content != "" {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "grove.toml"), []byte(tt.content), 0o644))
+			}
+			deps := newTestDeps(newTestGit())
+			deps.Cwd, deps.MainWorktreePath, deps.WorktreeRoot = dir, dir, dir
+			deps.FS = fsutil.OS{}
+
+			got := checkConfig(deps)
+
+			assert.Equal(t, tt.wantStatus, got.Status)
+			assert.Contains(t, got.Detail, tt.wantDetail)
+		})
+	}
+}
+
+func TestCheckWorkspace(t *testing.T) {
+	workspace := t.TempDir()
+
+	tests := []struct {
+		name       string
+		mainPath   string
+		root       string
+		wantStatus checkStatus
+		wantDetail string
+	}{
+		{name: "writable", mainPath: filepath.Join(workspace, "main"), wantStatus: checkPass},
+		{name: "missing", mainPath: filepath.Join(workspace, "missing", "main"), wantStatus: checkFail},
+		{name: "outside a repository", wantStatus: checkWarn},
+		{
+			name:       "root created with the first worktree",
+			mainPath:   filepath.Join(workspace, "main"),
+			root:       filepath.Join(workspace, "worktrees", "{{.RepoName}}"),
+			wantStatus: checkPass,
+			wantDetail: filepath.Join(workspace, "worktrees", "main") + " will be created; " + workspace + " is writable",
+		},
+		{
+			name:       "invalid root",
+			mainPath:   filepath.Join(workspace, "main"),
+			root:       "worktrees",
+			wantStatus: checkFail,
+			wantDetail: "worktree.root must be an absolute path",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := newTestDeps(fake.New(tt.mainPath, git.NewCommit("abc1234", "Initial", testNow, "user")))
+			deps.MainWorktreePath = tt.mainPath
+			deps.Config.Worktree.Root = tt.root
+
+			got := checkWorkspace(deps)
+
+			assert.Equal(t, tt.wantStatus, got.Status, got.Detail)
+			assert.Contains(t, got.Detail, tt.wantDetail)
+		})
+	}
+}
+
+func TestCheckWorktrees(t *testing.T) {
+	tests := []struct {
+		name       string
+		prunable   string
+		wantStatus checkStatus
+		wantDetail string
+	}{
+		{name: "none stale", wantStatus: checkPass, wantDetail: "2 worktree(s), none stale"},
+		{
+			name:       "stale worktree",
+			prunable:   "gitdir file points to non-existent location",
+			wantStatus: checkWarn,
+			wantDetail: "1 stale: /ws/feature (gitdir file points to non-existent location)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGit().
+				AddBranch("feature", git.NewCommit("bbb2222", "Feature", testNow, "user")).
+				AddWorktree("/ws/feature", "feature").
+				SetPrunable("/ws/feature", tt.prunable)
+
+			got := checkWorktrees(newTestDeps(g))
+
+			assert.Equal(t, tt.wantStatus, got.Status)
+			assert.Equal(t, tt.wantDetail, got.Detail)
+		})
+	}
+}
+
+func TestCheckWorktreeConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		state      git.WorktreeConfigState
+		wantStatus checkStatus
+		wantDetail string
+	}{
+		{name: "off", wantStatus: checkPass, wantDetail: "extensions.worktreeConfig is off; every worktree shares one git config"},
+		{
+			name:       "on",
+			state:      git.WorktreeConfigState{Enabled: true},
+			wantStatus: checkPass,
+			wantDetail: "extensions.worktreeConfig is on; git config --worktree stays in each worktree",
+		},
+		{
+			name:       "shared core.worktree",
+			state:      git.WorktreeConfigState{SharedWorktree: "/ws/main"},
+			wantStatus: checkWarn,
+			wantDetail: "core.worktree=/ws/main is in the shared config, so every worktree uses it",
+		},
+		{
+			name:       "shared core.bare with the extension on",
+			state:      git.WorktreeConfigState{Enabled: true, SharedBare: true},
+			wantStatus: checkFail,
+			wantDetail: "core.bare=true is in the shared config while extensions.worktreeConfig is on, so linked worktrees look bare",
+		},
+		{
+			name:       "shared core.bare with the extension off",
+			state:      git.WorktreeConfigState{SharedBare: true},
+			wantStatus: checkPass,
+			wantDetail: "extensions.worktreeConfig is off; every worktree shares one git config",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkWorktreeConfig(newTestDeps(newTestGit().SetWorktreeConfigState(tt.state)))
+
+			assert.Equal(t, tt.wantStatus, got.Status)
+			assert.Equal(t, tt.wantDetail, got.Detail)
+		})
+	}
+}
+
+func TestRunDoctor_EnableWorktreeConfig(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	doctorEnableWorktreeConfigFlag = true
+	t.Cleanup(func() { doctorEnableWorktreeConfigFlag = false })
+	mainPath := filepath.Join(t.TempDir(), "main")
+	g := fake.New(mainPath, git.NewCommit("abc1234", "Initial", testNow, "user")).
+		SetWorktreeConfigState(git.WorktreeConfigState{SharedWorktree: mainPath})
+	deps := newTestDeps(g)
+	deps.MainWorktreePath = mainPath
+	cmd, out := newTestCommand()
+
+	require.NoError(t, runDoctor(cmd, nil, deps))
+
+	state, err := g.GetWorktreeConfigState(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, git.WorktreeConfigState{Enabled: true}, state)
+	assert.Contains(t, out.String(), "extensions.worktreeConfig is on")
+}
+
+func TestRunDoctor_FailedCheck(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	deps := newTestDeps(newTestGit().SetVersion("2.7.4"))
+	deps.MainWorktreePath = ""
+	cmd, out := newTestCommand()
+
+	err := runDoctor(cmd, nil, deps)
+
+	require.Error(t, err)
+	assert.Equal(t, "1 doctor check(s) failed", err.Error())
+	assert.Contains(t, out.String(), "✗  git")
+	assert.Contains(t, out.String(), "fix: upgrade git to 2.17 or newer")
+}
+
+func TestRunDoctor_JSONEvents(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	deps := newTestDeps(newTestGit())
+	deps.MainWorktreePath = ""
+	cmd, out := newTestCommand()
+	deps.Events = events.New(out, "grove doctor", deps.Clock)
+
+	require.NoError(t, runDoctor(cmd, nil, deps))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 7)
+	assert.Contains(t, lines[0], `"total":6,"type":"started"`)
+	assert.Contains(t, lines[1], `"item":"git","message":"git 2.45.0","status":"pass"`)
+	assert.Contains(t, lines[2], `"item":"gh"`)
+	assert.Contains(t, lines[2], `"status":"warn"`)
+	assert.NotContains(t, out.String(), "✓")
+}
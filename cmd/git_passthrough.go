@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+)
+
+var gitCmd = &cobra.Command{
+	Use:   "git <name> -- <git args>...",
+	Short: "Run a git command in a worktree",
+	Long: `Git runs a git command inside a worktree without changing directory.
+
+The worktree is resolved the same way as grove open: by path, directory name, display
+name, or branch name (a unique prefix is enough). Everything after -- is passed to git
+unchanged, attached to the terminal, and grove exits with git's exit status.
+
+Example:
+  grove git add-auth -- log --oneline -5
+  grove git main -- status --short`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if dash := cmd.ArgsLenAtDash(); dash != -1 && dash != 1 {
+			return errors.New("specify one worktree before --")
+		}
+		if len(args) < 2 {
+			return errors.New("specify a worktree and the git command to run, e.g. grove git main -- status")
+		}
+		return nil
+	},
+	ValidArgsFunction: completeFirstArg(requirements{NeedsRepo: true}, completeWorktreeNames),
+	RunE:              withDeps(requirements{NeedsRepo: true}, runGitPassthrough),
+}
+
+func init() {
+	rootCmd.AddCommand(gitCmd)
+}
+
+func runGitPassthrough(cmd *cobra.Command, args []string, deps *Deps) error {
+	wt, err := resolveWorktree(deps, args[0])
+	if err != nil {
+		return err
+	}
+
+	gitArgs := append([]string{"-C", wt.AbsolutePath}, args[1:]...)
+	if err := deps.Exec("git", gitArgs...); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			// git already reported the problem on stderr
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+			return &ExitError{Code: exitErr.ExitCode()}
+		}
+		return fmt.Errorf("failed to run git: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunGitPassthrough(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		exec     func(name string, args ...string) error
+		wantCall []string
+		wantErr  string
+		wantCode int
+	}{
+		{
+			name:     "runs git in the worktree",
+			args:     []string{"add-auth", "log", "--oneline", "-5"},
+			wantCall: []string{"git", "-C", "/ws/wt-add-auth", "log", "--oneline", "-5"},
+		},
+		{
+			name:     "main worktree by branch",
+			args:     []string{"main", "status"},
+			wantCall: []string{"git", "-C", "/ws/main", "status"},
+		},
+		{
+			name:     "exit status passed through",
+			args:     []string{"add-auth", "diff", "--quiet"},
+			exec:     func(string, ...string) error { return exec.Command("sh", "-c", "exit 3").Run() },
+			wantCode: 3,
+		},
+		{
+			name:    "unknown worktree",
+			args:    []string{"nope", "status"},
+			wantErr: "no worktree matches",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGit().
+				AddBranch("feature/add-auth", git.NewCommit("bbb2222", "Work", testNow, "user")).
+				AddWorktree("/ws/wt-add-auth", "feature/add-auth")
+			deps := newTestDeps(g)
+			var calls [][]string
+			deps.Exec = recordExec(&calls)
+			if tt.exec != nil {
+				deps.Exec = tt.exec
+			}
+			cmd, _ := newTestCommand()
+
+			err := runGitPassthrough(cmd, tt.args, deps)
+
+			switch {
+			case tt.wantErr != "":
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.Empty(t, calls)
+			case tt.wantCode != 0:
+				var exitErr *ExitError
+				require.ErrorAs(t, err, &exitErr)
+				assert.Equal(t, tt.wantCode, exitErr.Code)
+				assert.True(t, cmd.SilenceErrors)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, [][]string{tt.wantCall}, calls)
+			}
+		})
+	}
+}
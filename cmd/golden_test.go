@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files in testdata/golden")
+
+func TestMain(m *testing.M) {
+	// render without ANSI sequences so golden files are stable regardless of the terminal
+	lipgloss.SetColorProfile(termenv.Ascii)
+	os.Exit(m.Run())
+}
+
+// assertGolden compares got with testdata/golden/<name>.golden.
+// Run `go test ./cmd -update` to rewrite the golden files after an intended rendering change.
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name+".golden")
+
+	if *updateGolden {
+		require.NoError(t, os.WriteFile(path, []byte(got), 0644))
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "missing golden file; run go test ./cmd -update")
+	assert.Equal(t, string(want), got, "output differs from %s", path)
+}
@@ -1,46 +1,190 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 
-	"github.com/jmcampanini/grove-cli/internal/shell"
+	clog "github.com/charmbracelet/log"
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/naming"
+	"github.com/jmcampanini/grove-cli/internal/state"
 	"github.com/spf13/cobra"
 )
 
+var initBareDirFlag string
+
 var initCmd = &cobra.Command{
-	Use:   "init <shell>",
-	Short: "Generate shell integration functions",
-	Long: `Init outputs shell functions for integration with your shell.
-
-Add to your shell config:
-  Fish:  grove init fish | source
-  Zsh:   eval "$(grove init zsh)"
-  Bash:  eval "$(grove init bash)"`,
-	Args:      cobra.ExactArgs(1),
-	ValidArgs: []string{"fish", "zsh", "bash"},
-	RunE:      runInit,
+	Use:   "init <repo-url> [<dir>]",
+	Short: "Clone a repository into a new workspace around a bare repository",
+	Long: `Init sets up a workspace for a repository: it clones the repository bare into <dir>/.bare,
+creates a worktree for the default branch next to it, and writes a starter grove.toml in
+<dir>. The worktree's path is printed, followed by the next steps.
+
+  <dir>/
+  ├── .bare/       # bare repository
+  ├── grove.toml   # starter config for the workspace
+  └── main/        # worktree for the default branch
+
+<dir> defaults to the repository name from the URL, in the current directory. It must not
+exist yet or be empty. Use --bare-dir to name the bare repository differently, e.g.
+repo.git. An existing grove.toml in <dir> is kept.
+
+The default branch tracks origin, and origin/HEAD points at it, so grove create --base-default
+and grove sync work right away. Its worktree is recorded as created by grove, and pinned, so
+grove list counts it as managed and grove prune never removes it.
+
+Before grove shell-init, this command printed the shell integration; grove init <shell>
+still does, but is deprecated.
+
+Example:
+  grove init git@github.com:acme/widgets.git
+  grove init https://github.com/acme/widgets ~/code/acme/widgets
+  grove init --bare-dir widgets.git git@github.com:acme/widgets.git`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 1 && slices.Contains(shellInitCmd.ValidArgs, args[0]) {
+			clog.Default().Warn("grove init <shell> is deprecated, use grove shell-init instead")
+			return runShellInit(cmd, args)
+		}
+		return withDeps(requirements{Mutating: true}, runInit)(cmd, args)
+	},
 }
 
 func init() {
+	initCmd.Flags().StringVar(&initBareDirFlag, "bare-dir", ".bare", "The name of the bare repository's directory in the workspace")
 	rootCmd.AddCommand(initCmd)
 }
 
-func runInit(cmd *cobra.Command, args []string) error {
-	shellName := args[0]
-	gen := shell.NewFunctionGenerator()
+// initConfig is the grove.toml grove init writes into a new workspace. Every key is commented out, so the
+// defaults apply until it is edited.
+const initConfig = `# grove config for this workspace. Run grove config to see every key and its current value.
+
+[branch]
+# new_prefix = "feature/"   # prefix for branches grove create names
+# base = "head"             # where new branches start: "head", "default", or a ref
+
+[worktree]
+# new_prefix = "wt-"                  # prefix for worktree directories
+# strip_branch_prefix = ["feature/"]  # removed from branch names when naming worktrees
+`
+
+func runInit(cmd *cobra.Command, args []string, deps *Deps) error {
+	url := args[0]
+	if err := validateBareDirName(initBareDirFlag); err != nil {
+		return err
+	}
+	dir, err := initWorkspaceDir(url, args[1:], deps.Cwd)
+	if err != nil {
+		return err
+	}
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return fmt.Errorf("%s already exists and is not empty", dir)
+	}
+	if deps.OpenGit == nil {
+		return errors.New("grove init is not available in --demo mode")
+	}
+
+	bareDir := filepath.Join(dir, initBareDirFlag)
+	var progress func(line string)
+	if p := fetchProgress(cmd, deps); p != nil {
+		progress = func(line string) { p("origin", line) }
+	}
+	if !dryRunFlag {
+		if err := deps.FS.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+	}
+	if err := deps.Git.CloneBare(deps.Ctx, url, bareDir, progress); err != nil {
+		return err
+	}
+	if dryRunFlag {
+		// nothing was cloned, so there is no repository to create the worktree in
+		clog.Default().Info("Would create the default branch's worktree and grove.toml", "dir", dir)
+		return nil
+	}
+
+	repo := deps.OpenGit(bareDir)
+	if _, err := repo.FetchRemote(deps.Ctx, "origin", nil); err != nil {
+		return err
+	}
+	branch, err := repo.GetCurrentBranch(deps.Ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get default branch: %w", err)
+	}
+	if branch == "HEAD" {
+		return fmt.Errorf("%s has no default branch to check out", url)
+	}
+	if err := repo.SetRepoDefaultBranch(deps.Ctx, "origin", branch); err != nil {
+		return fmt.Errorf("failed to set origin/HEAD: %w", err)
+	}
+	if err := repo.SetBranchUpstream(deps.Ctx, branch, "origin/"+branch); err != nil {
+		return fmt.Errorf("failed to set upstream of %s: %w", branch, err)
+	}
+	worktreePath := filepath.Join(dir, strings.ReplaceAll(branch, "/", "-"))
+	if err := repo.CreateWorktreeForExistingBranch(deps.Ctx, branch, worktreePath); err != nil {
+		return err
+	}
+	recordInitWorktree(deps, repo, worktreePath)
 
-	var output string
-	switch shellName {
-	case "fish":
-		output = gen.GenerateFish()
-	case "zsh":
-		output = gen.GenerateZsh()
-	case "bash":
-		output = gen.GenerateBash()
-	default:
-		return fmt.Errorf("unsupported shell: %s (supported: fish, zsh, bash)", shellName)
+	configPath := filepath.Join(dir, "grove.toml")
+	if !deps.FS.Exists(configPath) {
+		if err := deps.FS.WriteFile(configPath, []byte(initConfig), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", configPath, err)
+		}
 	}
 
-	_, err := fmt.Fprint(cmd.OutOrStdout(), output)
+	if _, err := fmt.Fprintf(cmd.ErrOrStderr(), `Initialized %s with %s checked out.
+
+Next steps:
+  cd %s
+  grove create "<phrase>"     # start a branch in a new worktree
+  grove shell-init --help     # set up grc and grs for your shell
+Edit %s to configure grove for this workspace.
+`, dir, branch, worktreePath, configPath); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), worktreePath)
 	return err
 }
+
+// recordInitWorktree records in the new repository's state that grove created the default branch's worktree,
+// pinned so that grove prune never removes it. The worktree already exists at this point, so a failure is
+// logged rather than returned.
+func recordInitWorktree(deps *Deps, repo git.Git, path string) {
+	commonDir, err := repo.GetCommonDir(deps.Ctx)
+	if err == nil {
+		st := state.New()
+		st.Set(path, state.Worktree{CreatedAt: deps.Clock().UTC(), Origin: state.OriginInit, Pinned: true})
+		err = state.NewFileStore(filepath.Join(commonDir, "grove")).WithFS(deps.FS).Save(st)
+	}
+	if err != nil {
+		clog.Default().WithPrefix("state").Warn("failed to record worktree", "path", path, "error", err)
+	}
+}
+
+// initWorkspaceDir returns the absolute workspace directory for grove init: the dir argument if given,
+// otherwise the repository name from url, relative to cwd.
+func initWorkspaceDir(url string, dirArg []string, cwd string) (string, error) {
+	var dir string
+	if len(dirArg) == 1 {
+		dir = dirArg[0]
+	} else if dir = naming.RepoNameFromURL(url); dir == "" || dir == "." || dir == ".." {
+		return "", fmt.Errorf("cannot name a directory after %q; pass the directory as well", url)
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(cwd, dir)
+	}
+	return filepath.Clean(dir), nil
+}
+
+// validateBareDirName checks that --bare-dir names a directory directly in the workspace.
+func validateBareDirName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid --bare-dir %q: must be a directory name, e.g. .bare or repo.git", name)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/git/fake"
+	"github.com/jmcampanini/grove-cli/internal/shell"
+	"github.com/jmcampanini/grove-cli/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunInit(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		bareDir      string
+		existing     string // grove.toml already in the workspace
+		wantBareDir  string
+		wantConfig   string
+		wantErr      string
+		wantWorktree string
+	}{
+		{
+			name:         "directory named after the repository",
+			args:         []string{"git@github.com:acme/widgets.git"},
+			wantBareDir:  "/ws/widgets/.bare",
+			wantConfig:   initConfig,
+			wantWorktree: "/ws/widgets/main",
+		},
+		{
+			name:         "relative directory",
+			args:         []string{"https://github.com/acme/widgets", "acme/w"},
+			wantBareDir:  "/ws/acme/w/.bare",
+			wantConfig:   initConfig,
+			wantWorktree: "/ws/acme/w/main",
+		},
+		{
+			name:         "absolute directory and --bare-dir",
+			args:         []string{"https://github.com/acme/widgets", "/code/widgets"},
+			bareDir:      "widgets.git",
+			wantBareDir:  "/code/widgets/widgets.git",
+			wantConfig:   initConfig,
+			wantWorktree: "/code/widgets/main",
+		},
+		{
+			name:         "existing grove.toml is kept",
+			args:         []string{"git@github.com:acme/widgets.git"},
+			existing:     "[branch]\nnew_prefix = \"jd/\"\n",
+			wantBareDir:  "/ws/widgets/.bare",
+			wantConfig:   "[branch]\nnew_prefix = \"jd/\"\n",
+			wantWorktree: "/ws/widgets/main",
+		},
+		{
+			name:    "URL without a name",
+			args:    []string{"git@github.com:"},
+			wantErr: "pass the directory as well",
+		},
+		{
+			name:    "URL named after a relative directory",
+			args:    []string{"../.."},
+			wantErr: "pass the directory as well",
+		},
+		{
+			name:    "--bare-dir with a path",
+			args:    []string{"git@github.com:acme/widgets.git"},
+			bareDir: "repos/widgets.git",
+			wantErr: `invalid --bare-dir "repos/widgets.git"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initBareDirFlag = ".bare"
+			if tt.bareDir != "" {
+				initBareDirFlag = tt.bareDir
+			}
+			t.Cleanup(func() { initBareDirFlag = ".bare" })
+			deps, cloner, repo := newInitTestDeps(t, tt.wantBareDir)
+			if tt.existing != "" {
+				require.NoError(t, deps.FS.MkdirAll("/ws/widgets", 0o755))
+				require.NoError(t, deps.FS.WriteFile("/ws/widgets/grove.toml", []byte(tt.existing), 0o644))
+			}
+			cmd, out := newTestCommand()
+			var stderr bytes.Buffer
+			cmd.SetErr(&stderr)
+
+			err := runInit(cmd, tt.args, deps)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantWorktree+"\n", out.String())
+			assert.Contains(t, stderr.String(), "cd "+tt.wantWorktree)
+			url, ok := cloner.Cloned(tt.wantBareDir)
+			require.True(t, ok, "the repository is cloned")
+			assert.Equal(t, tt.args[0], url)
+
+			worktrees, err := repo.ListWorktrees(t.Context())
+			require.NoError(t, err)
+			paths := make([]string, 0, len(worktrees))
+			for _, wt := range worktrees {
+				paths = append(paths, wt.AbsolutePath)
+			}
+			assert.Equal(t, []string{tt.wantBareDir, tt.wantWorktree}, paths)
+			branches, err := repo.ListLocalBranches(t.Context())
+			require.NoError(t, err)
+			require.Len(t, branches, 1)
+			assert.Equal(t, "origin/main", branches[0].UpstreamName)
+			defaultBranch, err := repo.GetRepoDefaultBranch(t.Context(), "origin")
+			require.NoError(t, err)
+			assert.Equal(t, "main", defaultBranch)
+
+			config, err := deps.FS.ReadFile(filepath.Join(filepath.Dir(tt.wantWorktree), "grove.toml"))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantConfig, string(config))
+
+			st, err := state.NewFileStore(filepath.Join(tt.wantBareDir, "grove")).WithFS(deps.FS).Load()
+			require.NoError(t, err)
+			entry := st.Get(tt.wantWorktree)
+			assert.Equal(t, state.OriginInit, entry.Origin, "the worktree is recorded as grove's")
+			assert.True(t, entry.Pinned)
+		})
+	}
+}
+
+func TestRunInit_NonEmptyDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), nil, 0o644))
+	deps, cloner, _ := newInitTestDeps(t, filepath.Join(dir, ".bare"))
+	cmd, _ := newTestCommand()
+
+	err := runInit(cmd, []string{"git@github.com:acme/widgets.git", dir}, deps)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not empty")
+	_, ok := cloner.Cloned(filepath.Join(dir, ".bare"))
+	assert.False(t, ok, "nothing is cloned")
+}
+
+func TestRunInit_DryRun(t *testing.T) {
+	dryRunFlag = true
+	t.Cleanup(func() { dryRunFlag = false })
+	deps, cloner, repo := newInitTestDeps(t, "/ws/widgets/.bare")
+	cmd, out := newTestCommand()
+
+	require.NoError(t, runInit(cmd, []string{"git@github.com:acme/widgets.git"}, deps))
+
+	assert.Empty(t, out.String())
+	_, ok := cloner.Cloned("/ws/widgets/.bare")
+	assert.True(t, ok, "the dry-run git client logs the clone")
+	worktrees, err := repo.ListWorktrees(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, worktrees, 1, "no worktree is created")
+	assert.False(t, deps.FS.Exists("/ws/widgets/grove.toml"))
+}
+
+func TestInitCmd_ShellName(t *testing.T) {
+	cmd, out := newTestCommand()
+	cmd.SetErr(&bytes.Buffer{})
+
+	require.NoError(t, initCmd.RunE(cmd, []string{"zsh"}))
+
+	assert.Equal(t, shell.NewFunctionGenerator().GenerateZsh(), out.String())
+}
+
+// newInitTestDeps returns Deps for grove init run in /ws, the git client that clones, and the bare repository
+// at bareDir that OpenGit returns, whose origin has a main branch.
+func newInitTestDeps(t *testing.T, bareDir string) (*Deps, *fake.Git, *fake.Git) {
+	t.Helper()
+	initial := git.NewCommit("abc1234def5678", "Initial", testNow, "user")
+	cloner := newTestGit()
+	repo := fake.New(bareDir, initial).SetBare().AddRemoteRef("origin", "main", initial)
+	deps := newTestDeps(cloner)
+	deps.Cwd = "/ws"
+	deps.OpenGit = func(dir string) git.Git {
+		assert.Equal(t, bareDir, dir)
+		return repo
+	}
+	return deps, cloner, repo
+}
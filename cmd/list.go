@@ -1,18 +1,43 @@
 package cmd
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
-	"os"
+	"io/fs"
+	"path"
 	"path/filepath"
-	"sort"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 
-	"github.com/jmcampanini/grove-cli/internal/config"
+	clog "github.com/charmbracelet/log"
 	"github.com/jmcampanini/grove-cli/internal/git"
 	"github.com/jmcampanini/grove-cli/internal/naming"
+	"github.com/jmcampanini/grove-cli/internal/porcelain"
+	"github.com/jmcampanini/grove-cli/internal/state"
 	"github.com/spf13/cobra"
 )
 
-var fzfFlag bool
+var (
+	activityFlag    bool
+	allFlag         bool
+	dirtyOnlyFlag   bool
+	filterFlags     []string
+	foreignOnlyFlag bool
+	fzfFlag         bool
+	listSortFlag    string
+	managedOnlyFlag bool
+	sizeFlag        bool
+	staleFlag       bool
+)
+
+// sizeConcurrency is how many worktrees --size measures at once.
+const sizeConcurrency = 8
+
+// statusConcurrency is how many worktrees list checks for modified files at once.
+const statusConcurrency = 8
 
 var listCmd = &cobra.Command{
 	Use:   "list",
@@ -24,58 +49,195 @@ By default, outputs one absolute path per line to stdout.
 With --fzf, outputs tab-separated format suitable for fzf integration:
   <path>\t<display>
 
+Pinned worktrees (see grove pin) end their display with a 📌 marker.
+
+Worktrees with modified, staged, or untracked files end their --fzf display with a *
+marker; --dirty-only lists only them, like --filter dirty. The worktrees are checked
+several at a time, as git status in each can take a moment in a large repository.
+
+A worktree is stale when its branch's upstream was deleted on the remote, typically after
+its pull request was merged (run grove sync or git fetch --prune to notice). Stale worktrees
+end their --fzf display with a ⚠ marker and are reported on stderr otherwise; --stale lists
+only them.
+
+With --activity, each worktree also shows when it was last worked in, e.g. "active 2h ago":
+the most recent change to a tracked file or git action (commit, checkout, staging) in it,
+which can be much more recent than its last commit. Plain output adds it as a second
+tab-separated column; --fzf adds it to the display.
+
+With --size, each worktree also shows how much disk space its directory uses, including
+ignored files such as build output and dependencies, measured for all worktrees at once.
+Plain output adds it as another column, --fzf adds it to the display, and a total is
+printed on stderr. Worktrees nested inside another are not counted in its size.
+
+In a bare repository layout, where a bare clone such as repo.git or .bare holds the
+repository and every worktree is linked to it, the bare repository is listed in the main
+worktree's place, with the --fzf display "bare [.bare]" and --porcelain type bare. It has no
+branch, so it does not match --filter or --dirty-only.
+
+--sort orders the linked worktrees; the main worktree always comes first, and ties are
+ordered by path:
+  path     by path (the default)
+  visited  most recently visited first, never visited last
+  branch   by branch name, worktrees not on a branch last
+  age      most recent commit first
+  ahead    most commits ahead of the upstream first
+  behind   most commits behind the upstream first
+
+Set [status] base to count commits ahead and behind against one ref, such as the remote's
+main branch, instead of each branch's upstream. --sort ahead and behind then use it, and
+--fzf adds e.g. "2 ahead, 5 behind origin/main" to the display of branches that differ:
+
+  [status]
+  base = "origin/main"
+
+A worktree is visited when grove open opens it or grove visit is run for it, which the grs
+shell function does after switching to it; worktrees grove created count as visited when
+they were created. With --sort visited, --fzf adds e.g. "visited 2h ago" to the display.
+
+--filter lists only the worktrees that match; given more than once, worktrees must match
+every filter:
+  branch=<glob>  the branch name matches the glob, e.g. branch=fix/*
+  dirty          has modified, staged, or untracked files
+  detached       is not on a branch (a detached commit or tag)
+  pr             was created from a pull request (grove pr checkout)
+
+A worktree is managed when grove recorded creating it (grove create, grove pr checkout,
+grove init). Any other linked worktree is foreign, even one named with grove's worktree
+prefix. --managed-only and --foreign-only list just one kind; the main worktree is neither
+and is left out.
+
+With --all-repos, grove lists the worktrees of every repository configured in
+[workspace] repos, one repository after another, and may be run from anywhere. Each
+repository uses its own config; --fzf displays start with the repository's name:
+
+  [workspace]
+  repos = ["~/code/api", "~/code/web"]
+
+Worktrees matching the [list] exclude globs are hidden unless --all is given.
+A pattern without a slash matches the directory name; otherwise it matches the
+absolute path, where ** matches any number of directories:
+
+  [list]
+  exclude = ["**/archive-*", "wt-tmp-*"]
+
+With --porcelain, outputs one worktree per line in grove's versioned tab-separated format
+(see grove --help), with the columns:
+  path type name sha main managed pinned stale activity repo size visited ahead behind
+type is branch, tag, detached, or bare; name is the branch or tag name; sha is the full commit
+SHA, empty for bare; activity is an RFC 3339 time with --activity; repo is the repository's
+name with --all-repos; size is in bytes with --size; visited is the RFC 3339 time of the last
+visit, if known; and ahead and behind count the branch's commits against [status] base or
+else its upstream, empty off a branch.
+
 Example with fzf:
   grove list --fzf | fzf --delimiter '\t' --with-nth 2 --accept-nth 1
 
 Or for older fzf versions:
   grove list --fzf | fzf --delimiter '\t' --with-nth 2 | cut -f1`,
 	Args: cobra.NoArgs,
-	RunE: runList,
+	RunE: withDeps(requirements{Porcelain: true}, runList),
 }
 
 func init() {
+	listCmd.Flags().BoolVar(&activityFlag, "activity", false, "Show when each worktree was last worked in")
+	listCmd.Flags().BoolVar(&allFlag, "all", false, "Include worktrees hidden by [list] exclude")
+	listCmd.Flags().BoolVar(&allReposFlag, "all-repos", false, "List the worktrees of every repository in [workspace] repos")
+	listCmd.Flags().BoolVar(&dirtyOnlyFlag, "dirty-only", false, "List only worktrees with modified, staged, or untracked files")
+	listCmd.Flags().StringArrayVar(&filterFlags, "filter", nil, "List only worktrees matching branch=<glob>, dirty, detached, or pr (repeatable)")
 	listCmd.Flags().BoolVar(&fzfFlag, "fzf", false, "Output in fzf-compatible format")
+	listCmd.Flags().BoolVar(&managedOnlyFlag, "managed-only", false, "List only worktrees grove manages")
+	listCmd.Flags().StringVar(&listSortFlag, "sort", listSortPath, "Order worktrees by path, visited, branch, age, ahead, or behind")
+	listCmd.Flags().BoolVar(&sizeFlag, "size", false, "Show each worktree's disk usage and the total")
+	listCmd.Flags().BoolVar(&foreignOnlyFlag, "foreign-only", false, "List only worktrees grove does not manage")
+	listCmd.Flags().BoolVar(&staleFlag, "stale", false, "List only worktrees whose upstream branch is gone")
+	listCmd.MarkFlagsMutuallyExclusive("managed-only", "foreign-only")
 	rootCmd.AddCommand(listCmd)
 }
 
-func runList(cmd *cobra.Command, _ []string) error {
-	cwd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
-	}
+// The orders --sort accepts.
+const (
+	listSortAge     = "age"
+	listSortAhead   = "ahead"
+	listSortBehind  = "behind"
+	listSortBranch  = "branch"
+	listSortPath    = "path"
+	listSortVisited = "visited"
+)
 
-	gitClient := git.New(false, cwd, config.DefaultConfig().Git.Timeout)
+var listSortOrders = []string{listSortPath, listSortVisited, listSortBranch, listSortAge, listSortAhead, listSortBehind}
 
-	worktreeRoot, err := gitClient.GetWorktreeRoot()
-	if err != nil {
-		return fmt.Errorf("git error: %w", err)
+// listFilter is what --filter asks of the listed worktrees; zero fields match every worktree.
+type listFilter struct {
+	Branch   string // glob the branch name must match
+	Detached bool
+	Dirty    bool
+	PR       bool
+}
+
+func runList(cmd *cobra.Command, _ []string, deps *Deps) error {
+	if porcelainFlag && fzfFlag {
+		return errors.New("--porcelain cannot be combined with --fzf")
 	}
-	if worktreeRoot == "" {
-		return fmt.Errorf("grove must be run inside a git repository")
+	if !slices.Contains(listSortOrders, listSortFlag) {
+		return fmt.Errorf("invalid --sort %q: must be one of %s", listSortFlag, strings.Join(listSortOrders, ", "))
 	}
-
-	mainWorktreePath, err := gitClient.GetMainWorktreePath()
+	filter, err := parseListFilters(filterFlags)
 	if err != nil {
-		return fmt.Errorf("failed to get main worktree path: %w", err)
+		return err
+	}
+	if dirtyOnlyFlag {
+		filter.Dirty = true
+	}
+	if !allReposFlag && deps.MainWorktreePath == "" {
+		return errNotInRepo
 	}
 
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("failed to get user home directory: %w", err)
+	var pw *porcelain.Writer
+	if porcelainFlag {
+		pw, err = porcelain.New(cmd.OutOrStdout(), "path", "type", "name", "sha", "main", "managed", "pinned", "stale", "activity", "repo", "size", "visited", "ahead", "behind")
+		if err != nil {
+			return err
+		}
 	}
 
-	configPaths := config.ConfigPaths(cwd, worktreeRoot, mainWorktreePath, homeDir)
-	loader := config.NewDefaultLoader()
-	loadResult, err := loader.Load(configPaths)
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+	var total *diskTotal
+	if sizeFlag {
+		total = &diskTotal{}
+	}
+	if allReposFlag {
+		err = forEachRepo(deps, func(repo workspaceRepo) error {
+			return listRepo(cmd, repo.Deps, repo.Name, filter, pw, total)
+		})
+	} else {
+		err = listRepo(cmd, deps, "", filter, pw, total)
+	}
+	if err != nil || total == nil || pw != nil {
+		return err
 	}
-	cfg := loadResult.Config
+	_, err = fmt.Fprintf(cmd.ErrOrStderr(), "Total: %s in %d %s\n", formatSize(total.Bytes), total.Worktrees, plural(total.Worktrees, "worktree", "worktrees"))
+	return err
+}
 
-	// Recreate the git client using the config timeout
-	gitClient = git.New(false, cwd, cfg.Git.Timeout)
+// diskTotal adds up the disk usage of the worktrees listed with --size.
+type diskTotal struct {
+	Bytes     int64
+	Worktrees int
+}
+
+// listedWorktree is a worktree that passed list's filters, with what list shows about it.
+type listedWorktree struct {
+	Entry    state.Worktree
+	Managed  bool
+	Worktree git.Worktree
+}
 
-	worktrees, err := gitClient.ListWorktrees()
+// listRepo lists the worktrees of one repository, as porcelain records when pw is not nil. With a
+// repoLabel (--all-repos), the --fzf display starts with it so worktrees of different repositories
+// can be told apart. Only the worktrees matching filter are listed. With --size, the listed worktrees'
+// disk usage is added to total.
+func listRepo(cmd *cobra.Command, deps *Deps, repoLabel string, filter listFilter, pw *porcelain.Writer, total *diskTotal) error {
+	worktrees, err := deps.Git.ListWorktrees(deps.Ctx)
 	if err != nil {
 		return fmt.Errorf("failed to list worktrees: %w", err)
 	}
@@ -83,26 +245,105 @@ func runList(cmd *cobra.Command, _ []string) error {
 	var mainWT *git.Worktree
 	var others []git.Worktree
 	for i := range worktrees {
-		if worktrees[i].AbsolutePath == mainWorktreePath {
+		switch {
+		case !allFlag && listExcluded(deps.Config.List.Exclude, worktrees[i].AbsolutePath):
+			continue
+		case worktrees[i].IsMain:
 			mainWT = &worktrees[i]
-		} else {
+		default:
 			others = append(others, worktrees[i])
 		}
 	}
 
-	namer := naming.NewWorktreeNamer(cfg.Worktree, cfg.Slugify)
+	st, err := loadWorktreeState(deps, worktrees)
+	if err != nil {
+		return err
+	}
+
+	namer, err := newWorktreeNamer(deps)
+	if err != nil {
+		return err
+	}
 
-	sort.Slice(others, func(i, j int) bool {
-		return others[i].AbsolutePath < others[j].AbsolutePath
+	var divergences map[string]branchDivergence
+	if deps.Config.Status.Base == "" || fzfFlag || pw != nil || listSortFlag == listSortAhead || listSortFlag == listSortBehind {
+		divergences = listDivergences(deps, worktrees)
+	}
+
+	slices.SortFunc(others, func(a, b git.Worktree) int {
+		if c := compareWorktrees(a, b, st, divergences, listSortFlag); c != 0 {
+			return c
+		}
+		return strings.Compare(a.AbsolutePath, b.AbsolutePath)
 	})
 
-	if mainWT != nil {
-		if err := outputWorktree(cmd, *mainWT, namer, fzfFlag); err != nil {
-			return err
+	var listed []listedWorktree
+	filtered := managedOnlyFlag || foreignOnlyFlag
+	// a bare repository is listed as the main worktree, but is no place to work in, so the picker leaves it out
+	pickable := mainWT == nil || !mainWT.IsBare || !fzfFlag || pw != nil
+	if mainWT != nil && pickable && !filtered && (!staleFlag || worktreeStale(*mainWT)) {
+		entry := st.Get(mainWT.AbsolutePath)
+		if filter.Match(*mainWT, entry) {
+			listed = append(listed, listedWorktree{Entry: entry, Worktree: *mainWT})
 		}
 	}
 	for _, wt := range others {
-		if err := outputWorktree(cmd, wt, namer, fzfFlag); err != nil {
+		entry := st.Get(wt.AbsolutePath)
+		managed := worktreeManaged(wt, entry)
+		if (managedOnlyFlag && !managed) || (foreignOnlyFlag && managed) || (staleFlag && !worktreeStale(wt)) {
+			continue
+		}
+		if !filter.Match(wt, entry) {
+			continue
+		}
+		listed = append(listed, listedWorktree{Entry: entry, Managed: managed, Worktree: wt})
+	}
+
+	var dirty map[string]bool
+	if filter.Dirty || (fzfFlag && pw == nil) {
+		dirty = worktreesDirty(deps, listed)
+		if filter.Dirty {
+			listed = slices.DeleteFunc(listed, func(l listedWorktree) bool { return !dirty[l.Worktree.AbsolutePath] })
+		}
+	}
+
+	var sizes map[string]int64
+	if total != nil {
+		sizes = worktreeSizes(worktrees, listed)
+		for _, size := range sizes {
+			total.Bytes += size
+		}
+		total.Worktrees += len(sizes)
+	}
+
+	for _, l := range listed {
+		size, measured := sizes[l.Worktree.AbsolutePath]
+		if pw != nil {
+			var sizeValue string
+			if measured {
+				sizeValue = strconv.FormatInt(size, 10)
+			}
+			if err := writeWorktreePorcelain(pw, deps, l.Worktree, l.Entry, l.Managed, repoLabel, sizeValue, divergences); err != nil {
+				return err
+			}
+			continue
+		}
+		var sizeLabel string
+		switch {
+		case measured:
+			sizeLabel = formatSize(size)
+		case total != nil && !l.Worktree.IsBare:
+			sizeLabel = "-"
+		}
+		labels := worktreeLabels{
+			Activity:   listActivity(deps, l.Worktree),
+			Dirty:      dirty[l.Worktree.AbsolutePath],
+			Divergence: listDivergenceLabel(deps, divergences, l.Worktree),
+			Repo:       repoLabel,
+			Size:       sizeLabel,
+			Visited:    listVisited(deps, l.Entry),
+		}
+		if err := outputWorktree(cmd, l.Worktree, namer, fzfFlag, l.Entry, l.Managed, labels); err != nil {
 			return err
 		}
 	}
@@ -110,18 +351,418 @@ func runList(cmd *cobra.Command, _ []string) error {
 	return nil
 }
 
-func outputWorktree(cmd *cobra.Command, wt git.Worktree, namer *naming.WorktreeNamer, fzf bool) error {
+// compareWorktrees compares two linked worktrees in the --sort order; 0 leaves them to be ordered by path.
+func compareWorktrees(a, b git.Worktree, st state.State, divergences map[string]branchDivergence, order string) int {
+	switch order {
+	case listSortVisited:
+		return st.Get(b.AbsolutePath).LastVisited().Compare(st.Get(a.AbsolutePath).LastVisited())
+	case listSortBranch:
+		aName, bName := worktreeBranchName(a), worktreeBranchName(b)
+		if (aName == "") != (bName == "") {
+			if aName == "" {
+				return 1
+			}
+			return -1
+		}
+		return strings.Compare(aName, bName)
+	case listSortAge:
+		return b.Ref.Commit().CommittedOn.Compare(a.Ref.Commit().CommittedOn)
+	case listSortAhead:
+		return cmp.Compare(divergences[b.AbsolutePath].Ahead, divergences[a.AbsolutePath].Ahead)
+	case listSortBehind:
+		return cmp.Compare(divergences[b.AbsolutePath].Behind, divergences[a.AbsolutePath].Behind)
+	default:
+		return 0
+	}
+}
+
+// branchDivergence counts the commits a worktree's branch is ahead of and behind the ref list compares it with.
+type branchDivergence struct {
+	Ahead  int
+	Behind int
+}
+
+// listDivergences returns, by path, how many commits each worktree's branch is ahead of and behind
+// [status] base, or its upstream when that is not set. Worktrees not on a branch are left out, as are
+// branches that cannot be compared with the base, e.g. because it was never fetched.
+func listDivergences(deps *Deps, worktrees []git.Worktree) map[string]branchDivergence {
+	base := deps.Config.Status.Base
+	divergences := make(map[string]branchDivergence, len(worktrees))
+	for _, wt := range worktrees {
+		if wt.Ref == nil {
+			continue
+		}
+		branch, ok := wt.Ref.FullBranch()
+		if !ok || wt.BranchMissing {
+			continue
+		}
+		if base == "" {
+			divergences[wt.AbsolutePath] = branchDivergence{Ahead: branch.Ahead, Behind: branch.Behind}
+			continue
+		}
+		ahead, behind, err := deps.Git.GetAheadBehind(deps.Ctx, branch.Name, base)
+		if err != nil {
+			clog.Default().Debug("failed to compare branch with status.base", "branch", branch.Name, "base", base, "error", err)
+			continue
+		}
+		divergences[wt.AbsolutePath] = branchDivergence{Ahead: ahead, Behind: behind}
+	}
+	return divergences
+}
+
+// listDivergenceLabel returns the worktree's "<n> ahead, <m> behind <base>" label for the --fzf display,
+// or "" when [status] base is not set or the branch is even with it.
+func listDivergenceLabel(deps *Deps, divergences map[string]branchDivergence, wt git.Worktree) string {
+	base := deps.Config.Status.Base
+	d := divergences[wt.AbsolutePath]
+	if base == "" || (d.Ahead == 0 && d.Behind == 0) {
+		return ""
+	}
+	var parts []string
+	if d.Ahead > 0 {
+		parts = append(parts, fmt.Sprintf("%d ahead", d.Ahead))
+	}
+	if d.Behind > 0 {
+		parts = append(parts, fmt.Sprintf("%d behind", d.Behind))
+	}
+	return strings.Join(parts, ", ") + " " + base
+}
+
+// parseListFilters parses the --filter values into one filter that all of them must match.
+func parseListFilters(values []string) (listFilter, error) {
+	var filter listFilter
+	for _, value := range values {
+		name, glob, hasGlob := strings.Cut(value, "=")
+		switch {
+		case name == "branch" && hasGlob:
+			if _, err := path.Match(glob, ""); err != nil || glob == "" {
+				return listFilter{}, fmt.Errorf("invalid --filter %q: %q is not a valid glob", value, glob)
+			}
+			filter.Branch = glob
+		case value == "detached":
+			filter.Detached = true
+		case value == "dirty":
+			filter.Dirty = true
+		case value == "pr":
+			filter.PR = true
+		default:
+			return listFilter{}, fmt.Errorf("invalid --filter %q: must be branch=<glob>, dirty, detached, or pr", value)
+		}
+	}
+	return filter, nil
+}
+
+// Match reports whether the worktree, with its state entry, matches the filter. The dirty filter is not
+// checked here: it runs git, so list checks the worktrees that match everything else at once, see worktreesDirty.
+func (f listFilter) Match(wt git.Worktree, entry state.Worktree) bool {
+	branch := worktreeBranchName(wt)
+	if f.Branch != "" {
+		if ok, _ := path.Match(f.Branch, branch); !ok || branch == "" {
+			return false
+		}
+	}
+	if f.Detached && (branch != "" || wt.IsBare) {
+		return false
+	}
+	return !f.PR || entry.Origin == state.OriginPR
+}
+
+// worktreesDirty checks which of the listed worktrees have modified, staged, or untracked files,
+// statusConcurrency at a time, and returns the dirty ones by path. A worktree whose status cannot be read,
+// e.g. because its directory is gone, is not dirty.
+func worktreesDirty(deps *Deps, listed []listedWorktree) map[string]bool {
+	results := make([]bool, len(listed))
+	sem := make(chan struct{}, statusConcurrency)
+	var wg sync.WaitGroup
+	for i, l := range listed {
+		if l.Worktree.IsBare {
+			continue // no working tree to be dirty
+		}
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			dirty, err := deps.Git.IsDirty(deps.Ctx, l.Worktree.AbsolutePath)
+			if err != nil {
+				clog.Default().Debug("failed to get worktree status", "path", l.Worktree.AbsolutePath, "error", err)
+			}
+			results[i] = dirty
+		})
+	}
+	wg.Wait()
+
+	dirty := make(map[string]bool, len(listed))
+	for i, l := range listed {
+		if results[i] {
+			dirty[l.Worktree.AbsolutePath] = true
+		}
+	}
+	return dirty
+}
+
+// worktreeSizes measures the disk usage of the listed worktrees, sizeConcurrency at a time, and returns it
+// by path. Other worktrees of the repository (all) nested inside a listed one are not counted in its size.
+// Worktrees that cannot be measured, e.g. because their directory is gone, are left out, as is a bare
+// repository, whose objects are not a worktree's files.
+func worktreeSizes(all []git.Worktree, listed []listedWorktree) map[string]int64 {
+	worktreePaths := make(map[string]bool, len(all))
+	for _, wt := range all {
+		worktreePaths[wt.AbsolutePath] = true
+	}
+	listed = slices.DeleteFunc(slices.Clone(listed), func(l listedWorktree) bool { return l.Worktree.IsBare })
+
+	sizes := make([]int64, len(listed))
+	errs := make([]error, len(listed))
+	sem := make(chan struct{}, sizeConcurrency)
+	var wg sync.WaitGroup
+	for i, l := range listed {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			sizes[i], errs[i] = dirSize(l.Worktree.AbsolutePath, worktreePaths)
+		})
+	}
+	wg.Wait()
+
+	measured := make(map[string]int64, len(listed))
+	for i, l := range listed {
+		if errs[i] != nil {
+			clog.Default().Debug("failed to measure worktree size", "path", l.Worktree.AbsolutePath, "error", errs[i])
+			continue
+		}
+		measured[l.Worktree.AbsolutePath] = sizes[i]
+	}
+	return measured
+}
+
+// dirSize returns the total size of the regular files under root, without following symlinks and
+// skipping the directories in skip. Entries that cannot be read are skipped; only an unreadable root fails.
+func dirSize(root string, skip map[string]bool) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil
+		}
+		if d.IsDir() && path != root && skip[path] {
+			return filepath.SkipDir
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// formatSize returns a byte count in binary units, e.g. "512 B", "1.5 KiB", or "3.2 GiB".
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	value, exp := float64(bytes)/unit, 0
+	for value >= unit && exp < 4 {
+		value /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", value, "KMGTP"[exp])
+}
+
+// listExcluded reports whether the worktree at absPath matches one of the [list] exclude patterns.
+func listExcluded(patterns []string, absPath string) bool {
+	slashPath := filepath.ToSlash(absPath)
+	for _, pattern := range patterns {
+		if !strings.Contains(pattern, "/") {
+			if ok, _ := path.Match(pattern, filepath.Base(absPath)); ok {
+				return true
+			}
+			continue
+		}
+		if matchGlobSegments(strings.Split(pattern, "/"), strings.Split(slashPath, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlobSegments matches path segments against pattern segments, where a "**" segment matches
+// zero or more path segments and any other segment is matched with path.Match.
+func matchGlobSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchGlobSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	ok, _ := path.Match(pattern[0], segments[0])
+	return ok && matchGlobSegments(pattern[1:], segments[1:])
+}
+
+// listActivity returns the worktree's "active <age>" label for --activity, or "" without it.
+// Worktrees whose activity cannot be read (e.g., their directory is gone) show "active -"; a bare repository,
+// which nobody works in, shows nothing.
+func listActivity(deps *Deps, wt git.Worktree) string {
+	if !activityFlag || wt.IsBare {
+		return ""
+	}
+	at, err := deps.Git.GetLastActivity(deps.Ctx, wt.AbsolutePath)
+	if err != nil {
+		clog.Default().Debug("failed to get last activity", "path", wt.AbsolutePath, "error", err)
+	}
+	return "active " + formatRelativeTime(at, deps.Clock())
+}
+
+// listVisited returns the worktree's "visited <age>" label for the --fzf display with --sort visited, or "".
+func listVisited(deps *Deps, entry state.Worktree) string {
+	if listSortFlag != listSortVisited {
+		return ""
+	}
+	at := entry.LastVisited()
+	if at.IsZero() {
+		return ""
+	}
+	return "visited " + formatRelativeTime(at, deps.Clock())
+}
+
+// worktreeStale reports whether the worktree's branch tracks an upstream that was deleted on the remote.
+func worktreeStale(wt git.Worktree) bool {
+	if wt.Ref == nil {
+		return false
+	}
+	branch, ok := wt.Ref.FullBranch()
+	return ok && branch.Gone
+}
+
+// writeWorktreePorcelain writes the worktree's --porcelain record; size is its disk usage in bytes, or "".
+func writeWorktreePorcelain(pw *porcelain.Writer, deps *Deps, wt git.Worktree, entry state.Worktree, managed bool, repoLabel, size string, divergences map[string]branchDivergence) error {
+	var refType, name, sha string
+	switch {
+	case wt.IsBare:
+		refType = "bare"
+	case wt.Ref.Type() == git.WorktreeRefTypeBranch:
+		branch, _ := wt.Ref.FullBranch()
+		refType, name = "branch", branch.Name
+	case wt.Ref.Type() == git.WorktreeRefTypeTag:
+		tag, _ := wt.Ref.FullTag()
+		refType, name = "tag", tag.Name
+	default:
+		refType = "detached"
+	}
+	if wt.Ref != nil {
+		sha = wt.Ref.Commit().SHA
+	}
+
+	var activity string
+	if activityFlag && !wt.IsBare {
+		at, err := deps.Git.GetLastActivity(deps.Ctx, wt.AbsolutePath)
+		if err != nil {
+			clog.Default().Debug("failed to get last activity", "path", wt.AbsolutePath, "error", err)
+		}
+		activity = porcelain.Time(at)
+	}
+
+	var visited string
+	if at := entry.LastVisited(); !at.IsZero() {
+		visited = porcelain.Time(at)
+	}
+
+	var ahead, behind string
+	if d, ok := divergences[wt.AbsolutePath]; ok {
+		ahead, behind = strconv.Itoa(d.Ahead), strconv.Itoa(d.Behind)
+	}
+
+	return pw.Row(
+		wt.AbsolutePath,
+		refType,
+		name,
+		sha,
+		porcelain.Bool(wt.IsMain),
+		porcelain.Bool(managed),
+		porcelain.Bool(entry.Pinned),
+		porcelain.Bool(worktreeStale(wt)),
+		activity,
+		repoLabel,
+		size,
+		visited,
+		ahead,
+		behind,
+	)
+}
+
+// worktreeLabels are the optional labels list shows about a worktree; empty labels are left out.
+type worktreeLabels struct {
+	Activity   string // "active <age>" with --activity
+	Dirty      bool   // has modified, staged, or untracked files, shown in the --fzf display only
+	Divergence string // "<n> ahead, <m> behind <base>" with [status] base, shown in the --fzf display only
+	Repo       string // the repository's name with --all-repos, shown in the --fzf display only
+	Size       string // disk usage with --size
+	Visited    string // "visited <age>" with --sort visited, shown in the --fzf display only
+}
+
+func outputWorktree(cmd *cobra.Command, wt git.Worktree, namer *naming.WorktreeNamer, fzf bool, entry state.Worktree, managed bool, labels worktreeLabels) error {
+	stale := worktreeStale(wt)
 	if fzf {
-		path, display := formatWorktree(wt, namer)
+		path, display := formatWorktree(wt, namer, managed)
+		if labels.Repo != "" {
+			display = labels.Repo + ": " + display
+		}
+		if labels.Dirty {
+			display += " *"
+		}
+		if entry.Pinned {
+			display += " 📌"
+		}
+		if stale {
+			display += " ⚠ upstream gone"
+		}
+		if labels.Divergence != "" {
+			display += " (" + labels.Divergence + ")"
+		}
+		if labels.Visited != "" {
+			display += " (" + labels.Visited + ")"
+		}
+		if labels.Activity != "" {
+			display += " (" + labels.Activity + ")"
+		}
+		if labels.Size != "" {
+			display += " (" + labels.Size + ")"
+		}
 		_, err := fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\n", path, display)
 		return err
 	}
-	_, err := fmt.Fprintln(cmd.OutOrStdout(), wt.AbsolutePath)
+	if stale && !staleFlag {
+		branch, _ := wt.Ref.FullBranch()
+		clog.Default().Warn("upstream branch is gone", "path", wt.AbsolutePath, "upstream", branch.UpstreamName)
+	}
+	columns := []string{wt.AbsolutePath}
+	for _, column := range []string{labels.Activity, labels.Size} {
+		if column != "" {
+			columns = append(columns, column)
+		}
+	}
+	_, err := fmt.Fprintln(cmd.OutOrStdout(), strings.Join(columns, "\t"))
 	return err
 }
 
-func formatWorktree(wt git.Worktree, namer *naming.WorktreeNamer) (path, display string) {
-	name := getDisplayName(namer, wt.AbsolutePath)
+func formatWorktree(wt git.Worktree, namer *naming.WorktreeNamer, managed bool) (path, display string) {
+	name := getDisplayName(namer, wt.AbsolutePath, managed)
+	if wt.IsBare {
+		return wt.AbsolutePath, "bare " + name
+	}
 
 	switch wt.Ref.Type() {
 	case git.WorktreeRefTypeBranch:
@@ -144,12 +785,16 @@ func formatWorktree(wt git.Worktree, namer *naming.WorktreeNamer) (path, display
 
 // getDisplayName returns the display name for a worktree.
 // If the basename has the configured prefix, strip it.
+// Worktrees grove created (managed) are shown as-is even without the prefix, e.g. PR worktrees.
 // Otherwise, wrap in brackets to indicate non-standard naming.
-func getDisplayName(namer *naming.WorktreeNamer, absPath string) string {
+func getDisplayName(namer *naming.WorktreeNamer, absPath string, managed bool) string {
 	basename := filepath.Base(absPath)
 	if namer.HasPrefix(basename) {
 		return namer.ExtractFromAbsolutePath(absPath)
 	}
+	if managed {
+		return basename
+	}
 	// Non-standard worktree name - mark with brackets
 	return "[" + basename + "]"
 }
@@ -1,28 +1,41 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/jmcampanini/grove-cli/internal/config"
 	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/git/fake"
 	"github.com/jmcampanini/grove-cli/internal/naming"
+	"github.com/jmcampanini/grove-cli/internal/state"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // testNamer creates a WorktreeNamer with the given prefix for testing.
 func testNamer(prefix string) *naming.WorktreeNamer {
-	return naming.NewWorktreeNamer(
+	namer, err := naming.NewWorktreeNamer(
 		config.WorktreeConfig{NewPrefix: prefix},
 		config.SlugifyConfig{},
 	)
+	if err != nil {
+		panic(err)
+	}
+	return namer
 }
 
 func TestGetDisplayName(t *testing.T) {
 	tests := []struct {
 		name     string
 		absPath  string
+		managed  bool
 		wtPrefix string
 		want     string
 	}{
@@ -56,6 +69,13 @@ func TestGetDisplayName(t *testing.T) {
 			wtPrefix: "wt-",
 			want:     "[wt_add-auth]",
 		},
+		{
+			name:     "managed worktree without prefix is not wrapped",
+			absPath:  "/workspace/pr-42",
+			wtPrefix: "wt-",
+			managed:  true,
+			want:     "pr-42",
+		},
 		{
 			name:     "nested path extracts basename",
 			absPath:  "/deep/nested/path/wt-feature",
@@ -67,7 +87,7 @@ func TestGetDisplayName(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			namer := testNamer(tt.wtPrefix)
-			got := getDisplayName(namer, tt.absPath)
+			got := getDisplayName(namer, tt.absPath, tt.managed)
 			assert.Equal(t, tt.want, got)
 		})
 	}
@@ -216,7 +236,7 @@ func TestFormatWorktree(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			namer := testNamer(tt.wtPrefix)
-			gotPath, gotDisplay := formatWorktree(tt.worktree, namer)
+			gotPath, gotDisplay := formatWorktree(tt.worktree, namer, false)
 			assert.Equal(t, tt.wantPath, gotPath)
 			assert.Equal(t, tt.wantDisplay, gotDisplay)
 		})
@@ -239,7 +259,7 @@ func TestFormatWorktreeTabSeparation(t *testing.T) {
 	}
 
 	namer := testNamer("wt-")
-	_, display := formatWorktree(worktree, namer)
+	_, display := formatWorktree(worktree, namer, false)
 
 	// Verify no tabs in display string
 	assert.NotContains(t, display, "\t", "display string should not contain tabs")
@@ -250,3 +270,837 @@ func TestFormatWorktreeTabSeparation(t *testing.T) {
 	// Verify proper spacing (single spaces between parts)
 	assert.NotContains(t, display, "  ", "display string should not have double spaces")
 }
+
+func TestRunList(t *testing.T) {
+	g := newTestGit().
+		AddBranch("feature/bug", git.NewCommit("aaa1111", "Bug", testNow, "user")).
+		AddBranch("feature/add-auth", git.NewCommit("def5678", "Auth", testNow, "user")).
+		AddWorktree("/ws/wt-bug", "feature/bug").
+		AddWorktree("/ws/wt-add-auth", "feature/add-auth")
+
+	tests := []struct {
+		name string
+		fzf  bool
+		want string
+	}{
+		{
+			name: "paths with main worktree first",
+			fzf:  false,
+			want: "/ws/main\n/ws/wt-add-auth\n/ws/wt-bug\n",
+		},
+		{
+			name: "fzf format",
+			fzf:  true,
+			want: "/ws/main\tlocal branch [main] main\n" +
+				"/ws/wt-add-auth\tlocal branch add-auth feature/add-auth\n" +
+				"/ws/wt-bug\tlocal branch bug feature/bug\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fzfFlag = tt.fzf
+			t.Cleanup(func() { fzfFlag = false })
+
+			cmd, out := newTestCommand()
+			err := runList(cmd, nil, newTestDeps(g))
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestRunList_ManagedFilters(t *testing.T) {
+	g := newTestGit().
+		AddBranch("feature/bug", git.NewCommit("aaa1111", "Bug", testNow, "user")).
+		AddBranch("pr-11", git.NewCommit("bbb2222", "PR", testNow, "user")).
+		AddBranch("scratch", git.NewCommit("ccc3333", "Scratch", testNow, "user")).
+		AddWorktree("/ws/wt-bug", "feature/bug").
+		AddWorktree("/ws/pr-11", "pr-11").
+		AddWorktree("/ws/scratch", "scratch")
+
+	tests := []struct {
+		name        string
+		managedOnly bool
+		foreignOnly bool
+		want        string
+	}{
+		{
+			name:        "managed only",
+			managedOnly: true,
+			want:        "/ws/pr-11\n",
+		},
+		{
+			name:        "foreign only, even with grove's prefix",
+			foreignOnly: true,
+			want:        "/ws/scratch\n/ws/wt-bug\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			managedOnlyFlag, foreignOnlyFlag = tt.managedOnly, tt.foreignOnly
+			t.Cleanup(func() { managedOnlyFlag, foreignOnlyFlag = false, false })
+
+			deps := newTestDeps(g)
+			st := state.New()
+			st.Set("/ws/pr-11", state.Worktree{Origin: state.OriginPR, PRNumber: 11})
+			require.NoError(t, deps.State.Save(st))
+
+			cmd, out := newTestCommand()
+			err := runList(cmd, nil, deps)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestRunList_Exclude(t *testing.T) {
+	g := newTestGit().
+		AddBranch("feature/bug", git.NewCommit("aaa1111", "Bug", testNow, "user")).
+		AddBranch("archive-2024", git.NewCommit("bbb2222", "Old", testNow, "user")).
+		AddBranch("tmp", git.NewCommit("ccc3333", "Tmp", testNow, "user")).
+		AddWorktree("/ws/wt-bug", "feature/bug").
+		AddWorktree("/ws/archive-2024", "archive-2024").
+		AddWorktree("/ws/wt-tmp-1", "tmp")
+
+	tests := []struct {
+		name    string
+		all     bool
+		exclude []string
+		want    string
+	}{
+		{
+			name: "no patterns",
+			want: "/ws/main\n/ws/archive-2024\n/ws/wt-bug\n/ws/wt-tmp-1\n",
+		},
+		{
+			name:    "patterns hide matches",
+			exclude: []string{"**/archive-*", "wt-tmp-*"},
+			want:    "/ws/main\n/ws/wt-bug\n",
+		},
+		{
+			name:    "all shows hidden worktrees",
+			all:     true,
+			exclude: []string{"**/archive-*", "wt-tmp-*"},
+			want:    "/ws/main\n/ws/archive-2024\n/ws/wt-bug\n/ws/wt-tmp-1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allFlag = tt.all
+			t.Cleanup(func() { allFlag = false })
+			deps := newTestDeps(g)
+			deps.Config.List.Exclude = tt.exclude
+
+			cmd, out := newTestCommand()
+			err := runList(cmd, nil, deps)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestRunList_Activity(t *testing.T) {
+	g := newTestGit().
+		AddBranch("feature/bug", git.NewCommit("aaa1111", "Bug", testNow, "user")).
+		AddWorktree("/ws/wt-bug", "feature/bug").
+		SetLastActivity("/ws/wt-bug", testNow.Add(-2*time.Hour)).
+		SetLastActivity("/ws/main", testNow.Add(-3*24*time.Hour))
+
+	tests := []struct {
+		name string
+		fzf  bool
+		want string
+	}{
+		{
+			name: "plain",
+			want: "/ws/main\tactive 3d ago\n/ws/wt-bug\tactive 2h ago\n",
+		},
+		{
+			name: "fzf",
+			fzf:  true,
+			want: "/ws/main\tlocal branch [main] main (active 3d ago)\n" +
+				"/ws/wt-bug\tlocal branch bug feature/bug (active 2h ago)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			activityFlag, fzfFlag = true, tt.fzf
+			t.Cleanup(func() { activityFlag, fzfFlag = false, false })
+
+			cmd, out := newTestCommand()
+			err := runList(cmd, nil, newTestDeps(g))
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestRunList_SortVisited(t *testing.T) {
+	commit := git.NewCommit("aaa1111", "Work", testNow, "user")
+	g := newTestGit().
+		AddBranch("feature/a", commit).
+		AddWorktree("/ws/wt-a", "feature/a").
+		AddBranch("feature/b", commit).
+		AddWorktree("/ws/wt-b", "feature/b").
+		AddBranch("feature/c", commit).
+		AddWorktree("/ws/wt-c", "feature/c").
+		AddBranch("feature/d", commit).
+		AddWorktree("/ws/wt-d", "feature/d")
+	deps := newTestDeps(g)
+	st := state.New()
+	st.Set("/ws/main", state.Worktree{VisitedAt: testNow})
+	st.Set("/ws/wt-b", state.Worktree{CreatedAt: testNow.Add(-72 * time.Hour)})
+	st.Set("/ws/wt-c", state.Worktree{CreatedAt: testNow.Add(-72 * time.Hour), VisitedAt: testNow.Add(-2 * time.Hour)})
+	require.NoError(t, deps.State.Save(st))
+
+	tests := []struct {
+		name    string
+		sort    string
+		fzf     bool
+		want    string
+		wantErr string
+	}{
+		{
+			name: "by path",
+			sort: listSortPath,
+			want: "/ws/main\n/ws/wt-a\n/ws/wt-b\n/ws/wt-c\n/ws/wt-d\n",
+		},
+		{
+			name: "by visit",
+			sort: listSortVisited,
+			want: "/ws/main\n/ws/wt-c\n/ws/wt-b\n/ws/wt-a\n/ws/wt-d\n",
+		},
+		{
+			name: "by visit with fzf",
+			sort: listSortVisited,
+			fzf:  true,
+			want: "/ws/main\tlocal branch [main] main (visited just now)\n" +
+				"/ws/wt-c\tlocal branch c feature/c (visited 2h ago)\n" +
+				"/ws/wt-b\tlocal branch b feature/b (visited 3d ago)\n" +
+				"/ws/wt-a\tlocal branch a feature/a\n" +
+				"/ws/wt-d\tlocal branch d feature/d\n",
+		},
+		{
+			name:    "unknown order",
+			sort:    "size",
+			wantErr: `invalid --sort "size"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listSortFlag, fzfFlag = tt.sort, tt.fzf
+			t.Cleanup(func() { listSortFlag, fzfFlag = listSortPath, false })
+
+			cmd, out := newTestCommand()
+			err := runList(cmd, nil, deps)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestRunList_SortAndFilter(t *testing.T) {
+	g := newTestGit().
+		AddBranch("feature/auth", git.NewCommit("aaa1111", "Auth", testNow.Add(-3*time.Hour), "user")).
+		SetUpstream("feature/auth", "origin/feature/auth", 1, 5).
+		AddWorktree("/ws/wt-auth", "feature/auth").
+		AddBranch("fix/login", git.NewCommit("bbb2222", "Login", testNow.Add(-time.Hour), "user")).
+		SetUpstream("fix/login", "origin/fix/login", 4, 0).
+		AddWorktree("/ws/wt-login", "fix/login").
+		AddBranch("bugfix", git.NewCommit("ccc3333", "Bug", testNow.Add(-2*time.Hour), "user")).
+		AddWorktree("/ws/pr-7", "bugfix").
+		AddDetachedWorktree("/ws/release", git.NewCommit("ddd4444", "Release", testNow.Add(-48*time.Hour), "user")).
+		SetDirty("/ws/wt-login").
+		SetDirty("/ws/release")
+	deps := newTestDeps(g)
+	st := state.New()
+	st.Set("/ws/pr-7", state.Worktree{Origin: state.OriginPR, PRNumber: 7})
+	require.NoError(t, deps.State.Save(st))
+
+	tests := []struct {
+		name    string
+		sort    string
+		filters []string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "by branch",
+			sort: listSortBranch,
+			want: "/ws/main\n/ws/pr-7\n/ws/wt-auth\n/ws/wt-login\n/ws/release\n",
+		},
+		{
+			name: "by age",
+			sort: listSortAge,
+			want: "/ws/main\n/ws/wt-login\n/ws/pr-7\n/ws/wt-auth\n/ws/release\n",
+		},
+		{
+			name: "by ahead",
+			sort: listSortAhead,
+			want: "/ws/main\n/ws/wt-login\n/ws/wt-auth\n/ws/pr-7\n/ws/release\n",
+		},
+		{
+			name: "by behind",
+			sort: listSortBehind,
+			want: "/ws/main\n/ws/wt-auth\n/ws/pr-7\n/ws/release\n/ws/wt-login\n",
+		},
+		{
+			name:    "branch glob",
+			filters: []string{"branch=f*/*"},
+			want:    "/ws/wt-auth\n/ws/wt-login\n",
+		},
+		{
+			name:    "dirty",
+			filters: []string{"dirty"},
+			want:    "/ws/release\n/ws/wt-login\n",
+		},
+		{
+			name:    "dirty and on a fix branch",
+			filters: []string{"dirty", "branch=fix/*"},
+			want:    "/ws/wt-login\n",
+		},
+		{
+			name:    "detached",
+			filters: []string{"detached"},
+			want:    "/ws/release\n",
+		},
+		{
+			name:    "pull requests",
+			filters: []string{"pr"},
+			want:    "/ws/pr-7\n",
+		},
+		{
+			name:    "unknown filter",
+			filters: []string{"stale"},
+			wantErr: `invalid --filter "stale": must be branch=<glob>, dirty, detached, or pr`,
+		},
+		{
+			name:    "invalid glob",
+			filters: []string{"branch=[fix"},
+			wantErr: `invalid --filter "branch=[fix"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listSortFlag, filterFlags = listSortPath, tt.filters
+			if tt.sort != "" {
+				listSortFlag = tt.sort
+			}
+			t.Cleanup(func() { listSortFlag, filterFlags = listSortPath, nil })
+
+			cmd, out := newTestCommand()
+			err := runList(cmd, nil, deps)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestRunList_Dirty(t *testing.T) {
+	commit := git.NewCommit("aaa1111", "Work", testNow, "user")
+	g := newTestGit().
+		AddBranch("feature/auth", commit).
+		AddWorktree("/ws/wt-auth", "feature/auth").
+		AddBranch("feature/login", commit).
+		AddWorktree("/ws/wt-login", "feature/login").
+		SetDirty("/ws/wt-login")
+	deps := newTestDeps(g)
+	st := state.New()
+	st.Set("/ws/wt-login", state.Worktree{Pinned: true})
+	require.NoError(t, deps.State.Save(st))
+
+	tests := []struct {
+		name      string
+		dirtyOnly bool
+		fzf       bool
+		want      string
+	}{
+		{
+			name: "fzf marks dirty worktrees",
+			fzf:  true,
+			want: "/ws/main\tlocal branch [main] main\n" +
+				"/ws/wt-auth\tlocal branch auth feature/auth\n" +
+				"/ws/wt-login\tlocal branch login feature/login * 📌\n",
+		},
+		{
+			name:      "dirty only",
+			dirtyOnly: true,
+			want:      "/ws/wt-login\n",
+		},
+		{
+			name:      "dirty only with fzf",
+			dirtyOnly: true,
+			fzf:       true,
+			want:      "/ws/wt-login\tlocal branch login feature/login * 📌\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dirtyOnlyFlag, fzfFlag = tt.dirtyOnly, tt.fzf
+			t.Cleanup(func() { dirtyOnlyFlag, fzfFlag = false, false })
+
+			cmd, out := newTestCommand()
+			require.NoError(t, runList(cmd, nil, deps))
+
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestRunList_Bare(t *testing.T) {
+	g := fake.New("/ws/.bare", git.NewCommit("abc1234def5678", "Initial", testNow, "user")).
+		SetBare().
+		AddWorktree("/ws/main", "main").
+		AddBranch("feature/bug", git.NewCommit("aaa1111", "Bug", testNow, "user")).
+		AddWorktree("/ws/wt-bug", "feature/bug").
+		SetDirty("/ws/wt-bug").
+		SetCurrentPath("/ws/main")
+	deps := newTestDeps(g)
+	deps.MainWorktreePath = "/ws/.bare"
+
+	tests := []struct {
+		name      string
+		activity  bool
+		dirtyOnly bool
+		fzf       bool
+		porcelain bool
+		size      bool
+		want      string
+	}{
+		{
+			name: "paths with the bare repository first",
+			want: "/ws/.bare\n/ws/main\n/ws/wt-bug\n",
+		},
+		{
+			name: "fzf leaves the bare repository out",
+			fzf:  true,
+			want: "/ws/main\tlocal branch [main] main\n" +
+				"/ws/wt-bug\tlocal branch bug feature/bug *\n",
+		},
+		{
+			name:      "porcelain",
+			porcelain: true,
+			want: "#v1\tpath\ttype\tname\tsha\tmain\tmanaged\tpinned\tstale\tactivity\trepo\tsize\tvisited\tahead\tbehind\n" +
+				"/ws/.bare\tbare\t\t\ttrue\tfalse\tfalse\tfalse\t\t\t\t\t\t\n" +
+				"/ws/main\tbranch\tmain\tabc1234def5678\tfalse\tfalse\tfalse\tfalse\t\t\t\t\t0\t0\n" +
+				"/ws/wt-bug\tbranch\tfeature/bug\taaa1111\tfalse\tfalse\tfalse\tfalse\t\t\t\t\t0\t0\n",
+		},
+		{
+			name:      "porcelain with activity",
+			activity:  true,
+			porcelain: true,
+			want: "#v1\tpath\ttype\tname\tsha\tmain\tmanaged\tpinned\tstale\tactivity\trepo\tsize\tvisited\tahead\tbehind\n" +
+				"/ws/.bare\tbare\t\t\ttrue\tfalse\tfalse\tfalse\t\t\t\t\t\t\n" +
+				"/ws/main\tbranch\tmain\tabc1234def5678\tfalse\tfalse\tfalse\tfalse\t\t\t\t\t0\t0\n" +
+				"/ws/wt-bug\tbranch\tfeature/bug\taaa1111\tfalse\tfalse\tfalse\tfalse\t\t\t\t\t0\t0\n",
+		},
+		{
+			name:     "activity",
+			activity: true,
+			want:     "/ws/.bare\n/ws/main\tactive -\n/ws/wt-bug\tactive -\n",
+		},
+		{
+			name: "size",
+			size: true,
+			want: "/ws/.bare\n/ws/main\t-\n/ws/wt-bug\t-\n",
+		},
+		{
+			name:      "dirty only",
+			dirtyOnly: true,
+			want:      "/ws/wt-bug\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			activityFlag, dirtyOnlyFlag, fzfFlag, porcelainFlag, sizeFlag = tt.activity, tt.dirtyOnly, tt.fzf, tt.porcelain, tt.size
+			t.Cleanup(func() {
+				activityFlag, dirtyOnlyFlag, fzfFlag, porcelainFlag, sizeFlag = false, false, false, false, false
+			})
+
+			cmd, out := newTestCommand()
+			cmd.SetErr(&bytes.Buffer{})
+			require.NoError(t, runList(cmd, nil, deps))
+
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestRunList_StatusBase(t *testing.T) {
+	g := newTestGit().
+		AddRemoteRef("origin", "main", git.NewCommit("eee5555", "Upstream", testNow, "user")).
+		AddBranch("feature/auth", git.NewCommit("aaa1111", "Auth", testNow, "user")).
+		SetUpstream("feature/auth", "origin/feature/auth", 9, 0).
+		SetAheadBehind("feature/auth", "origin/main", 2, 5).
+		AddWorktree("/ws/wt-auth", "feature/auth").
+		AddBranch("fix/login", git.NewCommit("bbb2222", "Login", testNow, "user")).
+		SetAheadBehind("fix/login", "origin/main", 3, 0).
+		AddWorktree("/ws/wt-login", "fix/login").
+		SetAheadBehind("main", "origin/main", 0, 1)
+	deps := newTestDeps(g)
+	deps.Config.Status.Base = "origin/main"
+
+	tests := []struct {
+		name      string
+		sort      string
+		fzf       bool
+		porcelain bool
+		want      string
+	}{
+		{
+			name: "by ahead",
+			sort: listSortAhead,
+			want: "/ws/main\n/ws/wt-login\n/ws/wt-auth\n",
+		},
+		{
+			name: "by behind",
+			sort: listSortBehind,
+			want: "/ws/main\n/ws/wt-auth\n/ws/wt-login\n",
+		},
+		{
+			name: "fzf",
+			sort: listSortPath,
+			fzf:  true,
+			want: "/ws/main\tlocal branch [main] main (1 behind origin/main)\n" +
+				"/ws/wt-auth\tlocal branch auth feature/auth (2 ahead, 5 behind origin/main)\n" +
+				"/ws/wt-login\tlocal branch login fix/login (3 ahead origin/main)\n",
+		},
+		{
+			name:      "porcelain",
+			sort:      listSortPath,
+			porcelain: true,
+			want: "#v1\tpath\ttype\tname\tsha\tmain\tmanaged\tpinned\tstale\tactivity\trepo\tsize\tvisited\tahead\tbehind\n" +
+				"/ws/main\tbranch\tmain\tabc1234def5678\ttrue\tfalse\tfalse\tfalse\t\t\t\t\t0\t1\n" +
+				"/ws/wt-auth\tbranch\tfeature/auth\taaa1111\tfalse\tfalse\tfalse\tfalse\t\t\t\t\t2\t5\n" +
+				"/ws/wt-login\tbranch\tfix/login\tbbb2222\tfalse\tfalse\tfalse\tfalse\t\t\t\t\t3\t0\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listSortFlag, fzfFlag, porcelainFlag = tt.sort, tt.fzf, tt.porcelain
+			t.Cleanup(func() { listSortFlag, fzfFlag, porcelainFlag = listSortPath, false, false })
+
+			cmd, out := newTestCommand()
+			require.NoError(t, runList(cmd, nil, deps))
+
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestRunList_Stale(t *testing.T) {
+	g := newTestGit().
+		AddBranch("feature/bug", git.NewCommit("aaa1111", "Bug", testNow, "user")).
+		AddBranch("feature/new", git.NewCommit("bbb2222", "New", testNow, "user")).
+		SetUpstream("feature/bug", "origin/feature/bug", 0, 0).
+		SetUpstreamGone("feature/bug").
+		SetUpstream("feature/new", "origin/feature/new", 1, 0).
+		AddWorktree("/ws/wt-bug", "feature/bug").
+		AddWorktree("/ws/wt-new", "feature/new")
+
+	tests := []struct {
+		name  string
+		fzf   bool
+		stale bool
+		want  string
+	}{
+		{
+			name: "marker in fzf display",
+			fzf:  true,
+			want: "/ws/main\tlocal branch [main] main\n" +
+				"/ws/wt-bug\tlocal branch bug feature/bug ⚠ upstream gone\n" +
+				"/ws/wt-new\tlocal branch new feature/new\n",
+		},
+		{
+			name:  "stale only",
+			stale: true,
+			want:  "/ws/wt-bug\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fzfFlag, staleFlag = tt.fzf, tt.stale
+			t.Cleanup(func() { fzfFlag, staleFlag = false, false })
+
+			cmd, out := newTestCommand()
+			err := runList(cmd, nil, newTestDeps(g))
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestRunList_AllRepos(t *testing.T) {
+	api := newTestRepoGit("/code/api").
+		AddBranch("feature/auth", git.NewCommit("bbb2222", "Auth", testNow, "user")).
+		AddWorktree("/code/api/wt-auth", "feature/auth")
+	web := newTestRepoGit("/code/web")
+	repos := map[string]*fake.Git{"/code/api": api, "/code/web": web}
+
+	tests := []struct {
+		name    string
+		fzf     bool
+		repos   []string
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "paths of every repository",
+			repos: []string{"/code/api", "/code/web"},
+			want:  "/code/api/main\n/code/api/wt-auth\n/code/web/main\n",
+		},
+		{
+			name:  "fzf display labelled with the repository",
+			fzf:   true,
+			repos: []string{"/code/web", "/code/api"},
+			want: "/code/web/main\tweb: local branch [main] main\n" +
+				"/code/api/main\tapi: local branch [main] main\n" +
+				"/code/api/wt-auth\tapi: local branch auth feature/auth\n",
+		},
+		{
+			name:    "missing repository",
+			repos:   []string{"/code/gone", "/code/web"},
+			want:    "/code/web/main\n",
+			wantErr: "1 of 2 repositories failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allReposFlag, fzfFlag = true, tt.fzf
+			t.Cleanup(func() { allReposFlag, fzfFlag = false, false })
+
+			cmd, out := newTestCommand()
+			err := runList(cmd, nil, newTestWorkspaceDeps(tt.repos, repos))
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestRunList_Porcelain(t *testing.T) {
+	g := newTestGit().
+		AddBranch("feature/bug", git.NewCommit("aaa1111", "Bug", testNow, "user")).
+		SetUpstream("feature/bug", "origin/feature/bug", 0, 0).
+		SetUpstreamGone("feature/bug").
+		AddWorktree("/ws/wt-bug", "feature/bug").
+		AddDetachedWorktree("/ws/release", git.NewCommit("ccc3333", "Release", testNow, "user"))
+	deps := newTestDeps(g)
+	st := state.New()
+	st.Set("/ws/wt-bug", state.Worktree{Origin: state.OriginCreate, Pinned: true})
+	require.NoError(t, deps.State.Save(st))
+
+	tests := []struct {
+		name    string
+		fzf     bool
+		want    string
+		wantErr string
+	}{
+		{
+			name: "records",
+			want: "#v1\tpath\ttype\tname\tsha\tmain\tmanaged\tpinned\tstale\tactivity\trepo\tsize\tvisited\tahead\tbehind\n" +
+				"/ws/main\tbranch\tmain\tabc1234def5678\ttrue\tfalse\tfalse\tfalse\t\t\t\t\t0\t0\n" +
+				"/ws/release\tdetached\t\tccc3333\tfalse\tfalse\tfalse\tfalse\t\t\t\t\t\t\n" +
+				"/ws/wt-bug\tbranch\tfeature/bug\taaa1111\tfalse\ttrue\ttrue\ttrue\t\t\t\t\t0\t0\n",
+		},
+		{
+			name:    "with fzf",
+			fzf:     true,
+			wantErr: "--porcelain cannot be combined with --fzf",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			porcelainFlag, fzfFlag = true, tt.fzf
+			t.Cleanup(func() { porcelainFlag, fzfFlag = false, false })
+
+			cmd, out := newTestCommand()
+			err := runList(cmd, nil, deps)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestRunList_Size(t *testing.T) {
+	dir := t.TempDir()
+	mainPath := filepath.Join(dir, "main")
+	nestedPath := filepath.Join(mainPath, "nested")
+	bugPath := filepath.Join(dir, "wt-bug")
+	require.NoError(t, os.MkdirAll(filepath.Join(mainPath, "src"), 0o755))
+	require.NoError(t, os.MkdirAll(nestedPath, 0o755))
+	require.NoError(t, os.MkdirAll(bugPath, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(mainPath, "src", "a.txt"), make([]byte, 1000), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(mainPath, "b.txt"), make([]byte, 500), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(nestedPath, "c.txt"), make([]byte, 3000), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(bugPath, "d.txt"), make([]byte, 2048), 0o644))
+	require.NoError(t, os.Symlink(filepath.Join(bugPath, "d.txt"), filepath.Join(mainPath, "link")))
+
+	commit := git.NewCommit("aaa1111", "Work", testNow, "user")
+	g := newTestRepoGit(dir).
+		AddBranch("feature/bug", commit).
+		AddWorktree(bugPath, "feature/bug").
+		AddBranch("feature/nested", commit).
+		AddWorktree(nestedPath, "feature/nested").
+		AddBranch("feature/gone", commit).
+		AddWorktree(filepath.Join(dir, "wt-gone"), "feature/gone")
+	deps := newTestDeps(g)
+	deps.MainWorktreePath, deps.WorktreeRoot, deps.Cwd = mainPath, mainPath, mainPath
+
+	tests := []struct {
+		name      string
+		fzf       bool
+		porcelain bool
+		want      string
+		wantTotal string
+	}{
+		{
+			name: "plain",
+			want: mainPath + "\t1.5 KiB\n" +
+				nestedPath + "\t2.9 KiB\n" +
+				bugPath + "\t2.0 KiB\n" +
+				filepath.Join(dir, "wt-gone") + "\t-\n",
+			wantTotal: "Total: 6.4 KiB in 3 worktrees\n",
+		},
+		{
+			name: "fzf",
+			fzf:  true,
+			want: mainPath + "\tlocal branch [main] main (1.5 KiB)\n" +
+				nestedPath + "\tlocal branch [nested] feature/nested (2.9 KiB)\n" +
+				bugPath + "\tlocal branch bug feature/bug (2.0 KiB)\n" +
+				filepath.Join(dir, "wt-gone") + "\tlocal branch gone feature/gone (-)\n",
+			wantTotal: "Total: 6.4 KiB in 3 worktrees\n",
+		},
+		{
+			name:      "porcelain",
+			porcelain: true,
+			want: "#v1\tpath\ttype\tname\tsha\tmain\tmanaged\tpinned\tstale\tactivity\trepo\tsize\tvisited\tahead\tbehind\n" +
+				mainPath + "\tbranch\tmain\tabc1234def5678\ttrue\tfalse\tfalse\tfalse\t\t\t1500\t\t0\t0\n" +
+				nestedPath + "\tbranch\tfeature/nested\taaa1111\tfalse\tfalse\tfalse\tfalse\t\t\t3000\t\t0\t0\n" +
+				bugPath + "\tbranch\tfeature/bug\taaa1111\tfalse\tfalse\tfalse\tfalse\t\t\t2048\t\t0\t0\n" +
+				filepath.Join(dir, "wt-gone") + "\tbranch\tfeature/gone\taaa1111\tfalse\tfalse\tfalse\tfalse\t\t\t\t\t0\t0\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sizeFlag, fzfFlag, porcelainFlag = true, tt.fzf, tt.porcelain
+			t.Cleanup(func() { sizeFlag, fzfFlag, porcelainFlag = false, false, false })
+
+			cmd, out := newTestCommand()
+			var errOut bytes.Buffer
+			cmd.SetErr(&errOut)
+			err := runList(cmd, nil, deps)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+			assert.Equal(t, tt.wantTotal, errOut.String())
+		})
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		bytes int64
+		want  string
+	}{
+		{bytes: 0, want: "0 B"},
+		{bytes: 1023, want: "1023 B"},
+		{bytes: 1536, want: "1.5 KiB"},
+		{bytes: 5 << 20, want: "5.0 MiB"},
+		{bytes: 3<<30 + 1<<29, want: "3.5 GiB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatSize(tt.bytes))
+		})
+	}
+}
+
+func TestRunList_NotInRepo(t *testing.T) {
+	cmd, _ := newTestCommand()
+	err := runList(cmd, nil, newTestWorkspaceDeps(nil, nil))
+
+	require.ErrorIs(t, err, errNotInRepo)
+}
+
+func TestListExcluded(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		path    string
+		want    bool
+	}{
+		{name: "directory name", pattern: "wt-tmp-*", path: "/ws/wt-tmp-1", want: true},
+		{name: "directory name only", pattern: "ws", path: "/ws/wt-tmp-1", want: false},
+		{name: "double star any depth", pattern: "**/archive-*", path: "/home/me/code/ws/archive-old", want: true},
+		{name: "double star in middle", pattern: "/home/**/wt-*", path: "/home/me/ws/wt-a", want: true},
+		{name: "double star no match", pattern: "**/archive-*", path: "/ws/wt-archive", want: false},
+		{name: "absolute path", pattern: "/ws/scratch", path: "/ws/scratch", want: true},
+		{name: "single star stays in one directory", pattern: "/*/wt-a", path: "/home/ws/wt-a", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, listExcluded([]string{tt.pattern}, tt.path))
+		})
+	}
+}
+
+// failingGit is a git.Git whose ListWorktrees always fails.
+type failingGit struct {
+	git.Git
+}
+
+func (failingGit) ListWorktrees(context.Context) ([]git.Worktree, error) {
+	return nil, errors.New("boom")
+}
+
+func TestRunList_GitError(t *testing.T) {
+	cmd, _ := newTestCommand()
+	err := runList(cmd, nil, newTestDeps(failingGit{}))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to list worktrees")
+}
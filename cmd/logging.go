@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	clog "github.com/charmbracelet/log"
+)
+
+// logFileEnv names a file grove appends its logs to instead of writing them to stderr.
+const logFileEnv = "GROVE_LOG_FILE"
+
+var (
+	logFormatFlag string
+	logLevelFlag  string
+)
+
+// logger is the logger grove's commands and its git and gh clients log to, built by configureLogging.
+// It is also made clog's default, so packages that log through clog.Default() share its settings.
+var logger = clog.Default()
+
+// logFile is the open GROVE_LOG_FILE, closed by Execute once the command has run.
+var logFile *os.File
+
+var (
+	logFormats = []string{"text", "json"}
+	logLevels  = []string{"debug", "info", "warn", "error"}
+)
+
+// configureLogging builds logger from --log-level (or --verbose), --log-format, and GROVE_LOG_FILE.
+func configureLogging() error {
+	level := logLevelFlag
+	if verboseFlag {
+		level = "debug"
+	}
+	w := io.Writer(os.Stderr)
+	if path := os.Getenv(logFileEnv); path != "" {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to open %s %s: %w", logFileEnv, path, err)
+		}
+		logFile, w = f, f
+	}
+
+	l, err := newLogger(w, level, logFormatFlag)
+	if err != nil {
+		return err
+	}
+	logger = l
+	clog.SetDefault(l)
+	return nil
+}
+
+// newLogger returns a logger writing to w at the named level, formatted as text or JSON.
+func newLogger(w io.Writer, level, format string) (*clog.Logger, error) {
+	lvl, err := clog.ParseLevel(level)
+	if err != nil || lvl == clog.FatalLevel {
+		return nil, fmt.Errorf("invalid --log-level %q (supported: %s)", level, strings.Join(logLevels, ", "))
+	}
+	var formatter clog.Formatter
+	switch format {
+	case "text":
+		formatter = clog.TextFormatter
+	case "json":
+		formatter = clog.JSONFormatter
+	default:
+		return nil, fmt.Errorf("invalid --log-format %q (supported: %s)", format, strings.Join(logFormats, ", "))
+	}
+	return clog.NewWithOptions(w, clog.Options{
+		Formatter:       formatter,
+		Level:           lvl,
+		ReportTimestamp: true,
+	}), nil
+}
+
+// closeLogFile closes GROVE_LOG_FILE, if it was opened.
+func closeLogFile() {
+	if logFile != nil {
+		_ = logFile.Close()
+		logFile = nil
+	}
+}
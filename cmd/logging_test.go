@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	clog "github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLogger(t *testing.T) {
+	tests := []struct {
+		name       string
+		level      string
+		format     string
+		wantErr    string
+		wantLogged []string // messages logged out of one debug, info, and warn message
+	}{
+		{name: "info text", level: "info", format: "text", wantLogged: []string{"info", "warn"}},
+		{name: "debug text", level: "debug", format: "text", wantLogged: []string{"debug", "info", "warn"}},
+		{name: "warn json", level: "warn", format: "json", wantLogged: []string{"warn"}},
+		{name: "error", level: "error", format: "text"},
+		{name: "unknown level", level: "trace", format: "text", wantErr: `invalid --log-level "trace" (supported: debug, info, warn, error)`},
+		{name: "fatal level", level: "fatal", format: "text", wantErr: `invalid --log-level "fatal"`},
+		{name: "unknown format", level: "info", format: "logfmt", wantErr: `invalid --log-format "logfmt" (supported: text, json)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			l, err := newLogger(&buf, tt.level, tt.format)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			l.Debug("debug")
+			l.Info("info")
+			l.Warn("warn")
+			var logged []string
+			for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+				if len(line) == 0 {
+					continue
+				}
+				if tt.format == "json" {
+					var entry map[string]any
+					require.NoError(t, json.Unmarshal(line, &entry), "each line is a JSON object")
+					logged = append(logged, entry["msg"].(string))
+				} else {
+					fields := bytes.Fields(line)
+					logged = append(logged, string(fields[len(fields)-1]))
+				}
+			}
+			assert.Equal(t, tt.wantLogged, logged)
+		})
+	}
+}
+
+func TestConfigureLogging_LogFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "grove.log")
+	require.NoError(t, os.WriteFile(path, []byte("earlier run\n"), 0o644))
+	t.Setenv(logFileEnv, path)
+	logLevelFlag, logFormatFlag = "info", "json"
+	saved := logger
+	t.Cleanup(func() {
+		closeLogFile()
+		logLevelFlag, logFormatFlag = "info", "text"
+		logger = saved
+		clog.SetDefault(saved)
+	})
+
+	require.NoError(t, configureLogging())
+	clog.Default().WithPrefix("git").Info("Fetching from remote", "remote", "origin")
+	closeLogFile()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+	require.Len(t, lines, 2, "the log is appended to")
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(lines[1], &entry))
+	assert.Equal(t, "Fetching from remote", entry["msg"])
+	assert.Equal(t, "git", entry["prefix"])
+	assert.Equal(t, "origin", entry["remote"])
+}
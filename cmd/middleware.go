@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	clog "github.com/charmbracelet/log"
+	"github.com/jmcampanini/grove-cli/internal/events"
+	"github.com/jmcampanini/grove-cli/internal/jsonout"
+	"github.com/jmcampanini/grove-cli/internal/summary"
+	"github.com/spf13/cobra"
+)
+
+// requirements declares the preconditions a command needs before it runs.
+type requirements struct {
+	AllowInvalidConfig bool // falls back to the default config when the config fails to load, so the command can report it
+	EmitsEvents        bool // supports --json-events
+	Mutating           bool // changes the repository; read-only commands get a git client that skips mutations
+	NeedsProvider      bool // requires the GitHub CLI (gh)
+	NeedsRepo          bool // must be run inside a git repository
+	Notifies           bool // notifies when it runs longer than [notifications] long_op_seconds
+	Porcelain          bool // supports --porcelain
+	Summary            bool // supports --summary-file
+}
+
+// runFunc is a command implementation that receives its dependencies.
+type runFunc func(cmd *cobra.Command, args []string, deps *Deps) error
+
+// withDeps adapts a runFunc into a cobra RunE that checks the command's requirements
+// and builds its Deps, so precondition errors are the same for every command.
+// With --json-events, the finished event is emitted here once the command returns, and with
+// --summary-file the summary is written here, also when the command fails. Long operations notify here too.
+func withDeps(req requirements, run runFunc) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if jsonEventsFlag && !req.EmitsEvents {
+			return fmt.Errorf("--json-events is not supported by %s", cmd.CommandPath())
+		}
+		if porcelainFlag && !req.Porcelain {
+			return fmt.Errorf("--porcelain is not supported by %s", cmd.CommandPath())
+		}
+		if porcelainFlag && jsonEventsFlag {
+			return errors.New("--porcelain cannot be combined with --json-events")
+		}
+		if summaryFileFlag != "" && !req.Summary {
+			return fmt.Errorf("--summary-file is not supported by %s", cmd.CommandPath())
+		}
+
+		deps, err := newDeps(commandContext(cmd), req)
+		if err != nil {
+			return writeSummaryFile(cmd, nil, err)
+		}
+		if jsonEventsFlag {
+			deps.Events = events.New(cmd.OutOrStdout(), cmd.CommandPath(), deps.Clock)
+		}
+		if summaryFileFlag != "" {
+			deps.Summary = summary.NewRecorder()
+		}
+
+		start := deps.Clock()
+		err = run(cmd, args, deps)
+		if req.Notifies {
+			notifyLongOperation(cmd, deps, deps.Clock().Sub(start), err)
+		}
+		if eventErr := deps.Events.Finished(err); eventErr != nil && err == nil {
+			err = eventErr
+		}
+		return writeSummaryFile(cmd, deps, err)
+	}
+}
+
+// writeSummaryFile writes the --summary-file summary of a command that returned err, if the flag is set,
+// and returns err. deps is nil when the command failed before its Deps were built.
+// A summary that cannot be written only fails a command that otherwise succeeded.
+func writeSummaryFile(cmd *cobra.Command, deps *Deps, err error) error {
+	if summaryFileFlag == "" {
+		return err
+	}
+	var recorder *summary.Recorder
+	var warnings []jsonout.Warning
+	now := time.Now()
+	if deps != nil {
+		recorder, warnings, now = deps.Summary, deps.Warnings, deps.Clock()
+	}
+	s := recorder.Summary(cmd.CommandPath(), err)
+	if writeErr := summary.WriteFile(summaryFileFlag, s, warnings, now); writeErr != nil {
+		if err == nil {
+			return writeErr
+		}
+		clog.Default().Error("failed to write summary file", "path", summaryFileFlag, "error", writeErr)
+	}
+	return err
+}
+
+// reportItem records that one unit of work finished: as an item-completed event with --json-events and
+// as a summary item with --summary-file.
+func reportItem(deps *Deps, item, status, message string) error {
+	deps.Summary.Add(item, status, message)
+	return deps.Events.ItemCompleted(item, status, message)
+}
+
+// commandContext returns the context the command was executed with, or a background context for commands
+// run without one, as in tests.
+func commandContext(cmd *cobra.Command) context.Context {
+	if cmd != nil && cmd.Context() != nil {
+		return cmd.Context()
+	}
+	return context.Background()
+}
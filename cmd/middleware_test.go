@@ -0,0 +1,411 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/state"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDeps_OutsideRepo(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test that runs git in short mode")
+	}
+
+	tests := []struct {
+		name    string
+		req     requirements
+		wantErr string
+	}{
+		{
+			name:    "repo required",
+			req:     requirements{NeedsRepo: true},
+			wantErr: "grove must be run inside a git repository",
+		},
+		{
+			name: "repo optional",
+			req:  requirements{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Chdir(dir)
+			t.Setenv("HOME", dir)
+			t.Setenv("XDG_CONFIG_HOME", dir)
+			t.Setenv("GIT_CEILING_DIRECTORIES", dir)
+
+			deps, err := newDeps(t.Context(), tt.req)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Empty(t, deps.WorktreeRoot)
+			assert.Empty(t, deps.MainWorktreePath)
+		})
+	}
+}
+
+func TestWithDeps_PassesArgsAndDeps(t *testing.T) {
+	demoFlag = true
+	t.Cleanup(func() { demoFlag = false })
+
+	var gotArgs []string
+	var gotDeps *Deps
+	runE := withDeps(requirements{NeedsProvider: true, NeedsRepo: true}, func(_ *cobra.Command, args []string, deps *Deps) error {
+		gotArgs, gotDeps = args, deps
+		return nil
+	})
+
+	require.NoError(t, runE(nil, []string{"a", "b"}))
+	assert.Equal(t, []string{"a", "b"}, gotArgs)
+	require.NotNil(t, gotDeps)
+	assert.Equal(t, demoMainWorktreePath, gotDeps.MainWorktreePath)
+}
+
+func TestWithDeps_Context(t *testing.T) {
+	demoFlag = true
+	t.Cleanup(func() { demoFlag = false })
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	cmd := &cobra.Command{}
+	cmd.SetContext(ctx)
+
+	var gotDeps *Deps
+	runE := withDeps(requirements{}, func(_ *cobra.Command, _ []string, deps *Deps) error {
+		gotDeps = deps
+		return nil
+	})
+
+	require.NoError(t, runE(cmd, nil))
+	require.ErrorIs(t, gotDeps.Ctx.Err(), context.Canceled)
+}
+
+func TestWithDeps_JSONEvents(t *testing.T) {
+	demoFlag = true
+	jsonEventsFlag = true
+	t.Cleanup(func() { demoFlag, jsonEventsFlag = false, false })
+
+	tests := []struct {
+		name       string
+		req        requirements
+		runErr     error
+		wantErr    string
+		wantEvents []string
+	}{
+		{
+			name:    "unsupported command",
+			req:     requirements{},
+			wantErr: "--json-events is not supported by grove",
+		},
+		{
+			name:       "emits finished",
+			req:        requirements{EmitsEvents: true},
+			wantEvents: []string{`"status":"ok","time":`},
+		},
+		{
+			name:       "emits finished with error",
+			req:        requirements{EmitsEvents: true},
+			runErr:     errors.New("boom"),
+			wantErr:    "boom",
+			wantEvents: []string{`"error":"boom","status":"error"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, out := newTestCommand()
+			cmd.Use = "grove"
+			runE := withDeps(tt.req, func(_ *cobra.Command, _ []string, deps *Deps) error {
+				require.NotNil(t, deps.Events)
+				return tt.runErr
+			})
+
+			err := runE(cmd, nil)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			for _, want := range tt.wantEvents {
+				assert.Contains(t, out.String(), want)
+			}
+			if tt.wantEvents == nil {
+				assert.Empty(t, out.String())
+			}
+		})
+	}
+}
+
+func TestWithDeps_Porcelain(t *testing.T) {
+	demoFlag, porcelainFlag = true, true
+	t.Cleanup(func() { demoFlag, porcelainFlag, jsonEventsFlag = false, false, false })
+
+	tests := []struct {
+		name       string
+		req        requirements
+		jsonEvents bool
+		wantErr    string
+	}{
+		{
+			name:    "unsupported command",
+			req:     requirements{},
+			wantErr: "--porcelain is not supported by grove",
+		},
+		{
+			name: "supported command",
+			req:  requirements{Porcelain: true},
+		},
+		{
+			name:       "with json events",
+			req:        requirements{EmitsEvents: true, Porcelain: true},
+			jsonEvents: true,
+			wantErr:    "--porcelain cannot be combined with --json-events",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonEventsFlag = tt.jsonEvents
+			cmd, _ := newTestCommand()
+			cmd.Use = "grove"
+			ran := false
+			runE := withDeps(tt.req, func(*cobra.Command, []string, *Deps) error {
+				ran = true
+				return nil
+			})
+
+			err := runE(cmd, nil)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.False(t, ran)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, ran)
+		})
+	}
+}
+
+func TestWithDeps_SummaryFile(t *testing.T) {
+	demoFlag = true
+	t.Cleanup(func() { demoFlag, summaryFileFlag = false, "" })
+
+	tests := []struct {
+		name        string
+		req         requirements
+		runErr      error
+		wantErr     string
+		wantSummary string
+	}{
+		{
+			name:    "unsupported command",
+			req:     requirements{},
+			wantErr: "--summary-file is not supported by grove",
+		},
+		{
+			name:        "writes items",
+			req:         requirements{Summary: true},
+			wantSummary: `{"command": "grove", "counts": {"pass": 1}, "items": [{"name": "origin", "status": "pass"}], "status": "ok"}`,
+		},
+		{
+			name:        "writes error",
+			req:         requirements{Summary: true},
+			runErr:      errors.New("boom"),
+			wantErr:     "boom",
+			wantSummary: `{"command": "grove", "counts": {"pass": 1}, "error": "boom", "items": [{"name": "origin", "status": "pass"}], "status": "error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summaryFileFlag = filepath.Join(t.TempDir(), "summary.json")
+			cmd, _ := newTestCommand()
+			cmd.Use = "grove"
+			runE := withDeps(tt.req, func(_ *cobra.Command, _ []string, deps *Deps) error {
+				require.NoError(t, reportItem(deps, "origin", "pass", ""))
+				return tt.runErr
+			})
+
+			err := runE(cmd, nil)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			if tt.wantSummary == "" {
+				assert.NoFileExists(t, summaryFileFlag)
+				return
+			}
+			var got struct {
+				Data json.RawMessage `json:"data"`
+			}
+			data, err := os.ReadFile(summaryFileFlag)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(data, &got))
+			assert.JSONEq(t, tt.wantSummary, string(got.Data))
+		})
+	}
+}
+
+func TestWithDeps_SummaryFile_DepsError(t *testing.T) {
+	summaryFileFlag = filepath.Join(t.TempDir(), "summary.json")
+	t.Cleanup(func() { summaryFileFlag, cwdFlag = "", "" })
+	cwdFlag = filepath.Join(t.TempDir(), "missing")
+	cmd, _ := newTestCommand()
+	cmd.Use = "grove"
+
+	err := withDeps(requirements{NeedsRepo: true, Summary: true}, func(*cobra.Command, []string, *Deps) error {
+		return nil
+	})(cmd, nil)
+
+	require.Error(t, err)
+	data, readErr := os.ReadFile(summaryFileFlag)
+	require.NoError(t, readErr)
+	assert.Contains(t, string(data), `"status": "error"`)
+}
+
+func TestNewDeps_SetFlags(t *testing.T) {
+	demoFlag = true
+	t.Cleanup(func() { demoFlag = false; setFlags = nil })
+
+	tests := []struct {
+		name    string
+		set     []string
+		wantErr string
+	}{
+		{name: "override applied", set: []string{"worktree.new_prefix=tmp-"}},
+		{name: "unknown key", set: []string{"worktree.nope=1"}, wantErr: `invalid --set: unknown config key "worktree.nope"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setFlags = tt.set
+
+			deps, err := newDeps(t.Context(), requirements{NeedsRepo: true})
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "tmp-", deps.Config.Worktree.NewPrefix)
+		})
+	}
+}
+
+func TestResolveCwd(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file.txt")
+	require.NoError(t, os.WriteFile(file, nil, 0o644))
+	link := filepath.Join(t.TempDir(), "link")
+	require.NoError(t, os.Symlink(dir, link))
+	t.Chdir(dir)
+
+	tests := []struct {
+		name    string
+		dir     string
+		want    string
+		wantErr string
+	}{
+		{name: "defaults to process directory", want: dir},
+		{name: "absolute", dir: dir, want: dir},
+		{name: "relative", dir: ".", want: dir},
+		{name: "symlink resolved", dir: link, want: dir},
+		{name: "missing", dir: filepath.Join(dir, "missing"), wantErr: "no such file or directory"},
+		{name: "file", dir: file, wantErr: "not a directory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveCwd(tt.dir)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, resolvePath(t, tt.want), got)
+		})
+	}
+}
+
+func TestNewDeps_CwdFlag(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test that runs git in short mode")
+	}
+
+	repo := t.TempDir()
+	out, err := exec.Command("git", "init", "-b", "main", repo).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	elsewhere := t.TempDir()
+	t.Chdir(elsewhere)
+	t.Setenv("HOME", elsewhere)
+	t.Setenv("XDG_CONFIG_HOME", elsewhere)
+	cwdFlag = repo
+	t.Cleanup(func() { cwdFlag = "" })
+
+	deps, err := newDeps(t.Context(), requirements{NeedsRepo: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, resolvePath(t, repo), resolvePath(t, deps.WorktreeRoot))
+}
+
+// resolvePath resolves symlinks so temp paths compare equal on macOS (/var -> /private/var).
+func resolvePath(t *testing.T, path string) string {
+	t.Helper()
+	resolved, err := filepath.EvalSymlinks(path)
+	require.NoError(t, err)
+	return resolved
+}
+
+func TestNewDeps_DryRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test that runs git in short mode")
+	}
+
+	repo := t.TempDir()
+	out, err := exec.Command("git", "init", "-b", "main", repo).CombinedOutput()
+	require.NoError(t, err, string(out))
+	out, err = exec.Command("git", "-C", repo, "-c", "user.name=Test", "-c", "user.email=test@example.com",
+		"commit", "--allow-empty", "-m", "initial").CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	t.Chdir(repo)
+	t.Setenv("HOME", repo)
+	t.Setenv("XDG_CONFIG_HOME", repo)
+	dryRunFlag = true
+	t.Cleanup(func() { dryRunFlag = false })
+
+	deps, err := newDeps(t.Context(), requirements{Mutating: true, NeedsRepo: true})
+	require.NoError(t, err)
+
+	worktreePath := filepath.Join(t.TempDir(), "wt-dry-run")
+	require.NoError(t, deps.Git.CreateWorktreeForNewBranch(t.Context(), "dry-run", worktreePath))
+	require.NoError(t, deps.State.Save(state.New()))
+	require.NoError(t, deps.Exec("false"))
+
+	_, err = os.Stat(worktreePath)
+	assert.True(t, os.IsNotExist(err), "worktree should not be created")
+	_, err = os.Stat(filepath.Join(repo, ".git", "grove", state.FileName))
+	assert.True(t, os.IsNotExist(err), "state should not be written")
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jmcampanini/grove-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var migrateFromFlag string
+
+var migrateDefaultBranchCmd = &cobra.Command{
+	Use:   "migrate-default-branch",
+	Short: "Follow a rename of the remote's default branch",
+	Long: `Migrate-default-branch catches the repository up after the remote's default branch was
+renamed, for example from master to main.
+
+It asks the default remote which branch its HEAD points at and, when that differs from
+the default branch recorded locally (git remote set-head), fetches the remote and:
+
+  - points the local remote HEAD at the new default branch
+  - renames the local old default branch, unless a branch with the new name exists
+  - switches every local branch tracking the old default branch to track the new one
+
+It then lists the worktrees whose branch is behind the new default branch and so needs
+rebasing, and warns if [branch] base names the old default branch.
+
+Use --from to name the old default branch when the local remote HEAD was already updated
+or was never set.
+
+Example:
+  grove migrate-default-branch
+  grove migrate-default-branch --from master`,
+	Args: cobra.NoArgs,
+	RunE: withDeps(requirements{Mutating: true, NeedsRepo: true}, runMigrateDefaultBranch),
+}
+
+func init() {
+	migrateDefaultBranchCmd.Flags().StringVar(&migrateFromFlag, "from", "", "The old default branch (default: the local remote HEAD)")
+	rootCmd.AddCommand(migrateDefaultBranchCmd)
+}
+
+func runMigrateDefaultBranch(cmd *cobra.Command, _ []string, deps *Deps) error {
+	remote, err := deps.Git.GetDefaultRemote(deps.Ctx, "origin")
+	if err != nil {
+		return fmt.Errorf("failed to get default remote: %w", err)
+	}
+	current, err := deps.Git.GetRemoteHEAD(deps.Ctx, remote)
+	if err != nil {
+		return err
+	}
+	if current == "" {
+		return fmt.Errorf("%s does not report a default branch", remote)
+	}
+	previous, err := deps.Git.GetRepoDefaultBranch(deps.Ctx, remote)
+	if err != nil {
+		return fmt.Errorf("failed to get default branch of %s: %w", remote, err)
+	}
+	old := migrateFromFlag
+	if old == "" {
+		old = previous
+	}
+	if old == "" {
+		return fmt.Errorf("the default branch of %s was never recorded locally; name the old one with --from", remote)
+	}
+	if old == current {
+		_, err := fmt.Fprintf(cmd.ErrOrStderr(), "The default branch of %s is still %s; nothing to migrate\n", remote, current)
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if _, err := fmt.Fprintf(out, "Default branch of %s: %s -> %s\n", remote, old, current); err != nil {
+		return err
+	}
+	if _, err := deps.Git.FetchRemote(deps.Ctx, remote, nil); err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", remote, err)
+	}
+	if previous != current {
+		if err := deps.Git.SetRepoDefaultBranch(deps.Ctx, remote, current); err != nil {
+			return err
+		}
+	}
+
+	oldUpstream, newUpstream := remote+"/"+old, remote+"/"+current
+	renamed, err := renameOldDefaultBranch(deps, old, current)
+	if err != nil {
+		return err
+	}
+	if renamed {
+		if _, err := fmt.Fprintf(out, "Renamed branch %s to %s\n", old, current); err != nil {
+			return err
+		}
+	}
+
+	branches, err := deps.Git.ListLocalBranches(deps.Ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list local branches: %w", err)
+	}
+	retargeted := map[string]bool{}
+	for _, b := range branches {
+		if b.UpstreamName != oldUpstream {
+			continue
+		}
+		if err := deps.Git.SetBranchUpstream(deps.Ctx, b.Name, newUpstream); err != nil {
+			return err
+		}
+		retargeted[b.Name] = true
+		if _, err := fmt.Fprintf(out, "Set upstream of %s to %s\n", b.Name, newUpstream); err != nil {
+			return err
+		}
+	}
+
+	if err := reportWorktreesToRebase(cmd, deps, retargeted, newUpstream); err != nil {
+		return err
+	}
+	return warnBranchBase(cmd, deps, old, oldUpstream)
+}
+
+// renameOldDefaultBranch renames the local branch named after the old default branch to the new name,
+// unless there is no such branch or one with the new name already exists.
+func renameOldDefaultBranch(deps *Deps, old, current string) (bool, error) {
+	oldExists, err := deps.Git.BranchExists(deps.Ctx, old, false)
+	if err != nil {
+		return false, err
+	}
+	currentExists, err := deps.Git.BranchExists(deps.Ctx, current, false)
+	if err != nil {
+		return false, err
+	}
+	if !oldExists || currentExists {
+		return false, nil
+	}
+	if err := deps.Git.RenameBranch(deps.Ctx, old, current); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// reportWorktreesToRebase lists the worktrees whose retargeted branch is behind its new upstream.
+func reportWorktreesToRebase(cmd *cobra.Command, deps *Deps, retargeted map[string]bool, upstream string) error {
+	branches, err := deps.Git.ListLocalBranches(deps.Ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list local branches: %w", err)
+	}
+	var lines []string
+	for _, b := range branches {
+		if retargeted[b.Name] && b.WorktreeAbsolutePath != "" && b.Behind > 0 {
+			lines = append(lines, fmt.Sprintf("  %s (%s, %d behind)", b.WorktreeAbsolutePath, b.Name, b.Behind))
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	out := cmd.OutOrStdout()
+	if _, err := fmt.Fprintf(out, "\nWorktrees to rebase onto %s (git pull --rebase):\n", upstream); err != nil {
+		return err
+	}
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(out, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// warnBranchBase warns when [branch] base still names the old default branch, since grove does not edit config files.
+func warnBranchBase(cmd *cobra.Command, deps *Deps, old, oldUpstream string) error {
+	base := deps.Config.Branch.Base
+	if base != old && base != oldUpstream && base != "refs/remotes/"+oldUpstream {
+		return nil
+	}
+	source, ok := deps.ConfigSources["branch.base"]
+	if !ok {
+		source = config.SourceDefault
+	}
+	_, err := fmt.Fprintf(cmd.ErrOrStderr(), "Warning: [branch] base is %q in %s; set it to %q to follow the rename\n",
+		base, source, config.BranchBaseDefault)
+	return err
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/git/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunMigrateDefaultBranch(t *testing.T) {
+	tests := []struct {
+		name          string
+		setup         func(g *fake.Git, deps *Deps)
+		from          string
+		wantErr       string
+		wantOutput    []string
+		wantNotOutput []string
+		wantStderr    string
+		wantBranches  map[string]string // branch -> upstream after migrating, for the branches listed
+	}{
+		{
+			name: "renamed on the remote",
+			wantOutput: []string{
+				"Default branch of origin: main -> trunk\n",
+				"Renamed branch main to trunk\n",
+				"Set upstream of feature/auth to origin/trunk\n",
+				"Set upstream of trunk to origin/trunk\n",
+				"Worktrees to rebase onto origin/trunk (git pull --rebase):\n  /ws/auth (feature/auth, 2 behind)\n",
+			},
+			wantNotOutput: []string{"fix/login"},
+			wantBranches:  map[string]string{"feature/auth": "origin/trunk", "fix/login": "origin/fix/login", "trunk": "origin/trunk"},
+		},
+		{
+			name: "new branch already exists locally",
+			setup: func(g *fake.Git, _ *Deps) {
+				g.AddBranch("trunk", git.NewCommit("abc1234def5678", "Initial", testNow, "user"))
+			},
+			wantNotOutput: []string{"Renamed branch"},
+			wantBranches:  map[string]string{"main": "origin/trunk", "trunk": ""},
+		},
+		{
+			name: "base names the old default branch",
+			setup: func(_ *fake.Git, deps *Deps) {
+				deps.Config.Branch.Base = "origin/main"
+				deps.ConfigSources["branch.base"] = "/ws/main/.grove.toml"
+			},
+			wantStderr: `Warning: [branch] base is "origin/main" in /ws/main/.grove.toml; set it to "default" to follow the rename`,
+		},
+		{
+			name:       "already migrated",
+			setup:      func(g *fake.Git, _ *Deps) { g.SetRemoteHead("origin", "trunk") },
+			wantStderr: "The default branch of origin is still trunk; nothing to migrate",
+		},
+		{
+			name:         "already migrated with --from",
+			setup:        func(g *fake.Git, _ *Deps) { g.SetRemoteHead("origin", "trunk") },
+			from:         "main",
+			wantOutput:   []string{"Default branch of origin: main -> trunk\n", "Renamed branch main to trunk\n"},
+			wantBranches: map[string]string{"trunk": "origin/trunk"},
+		},
+		{
+			name:    "never recorded",
+			setup:   func(g *fake.Git, _ *Deps) { g.SetRemoteHead("origin", "") },
+			wantErr: "name the old one with --from",
+		},
+		{
+			name:    "remote reports no default branch",
+			setup:   func(g *fake.Git, _ *Deps) { g.SetRemoteDefaultBranch("origin", "") },
+			wantErr: "origin does not report a default branch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrateFromFlag = tt.from
+			t.Cleanup(func() { migrateFromFlag = "" })
+			commit := git.NewCommit("abc1234def5678", "Initial", testNow, "user")
+			g := newTestGit().
+				AddBranch("feature/auth", git.NewCommit("bbb2222", "Auth", testNow, "user")).
+				AddWorktree("/ws/auth", "feature/auth").
+				AddBranch("fix/login", git.NewCommit("ccc3333", "Fix", testNow, "user")).
+				AddRemoteRef("origin", "main", commit).
+				AddRemoteRef("origin", "fix/login", git.NewCommit("ccc3333", "Fix", testNow, "user")).
+				SetRemoteHead("origin", "main").
+				SetUpstream("main", "origin/main", 0, 0).
+				SetUpstream("feature/auth", "origin/main", 1, 2).
+				SetUpstream("fix/login", "origin/fix/login", 0, 0).
+				PushRemoteRef("origin", "trunk", commit).
+				DeleteRemoteRef("origin", "main").
+				SetRemoteDefaultBranch("origin", "trunk")
+			deps := newTestDeps(g)
+			if tt.setup != nil {
+				tt.setup(g, deps)
+			}
+			cmd, out := newTestCommand()
+			var stderr bytes.Buffer
+			cmd.SetErr(&stderr)
+
+			err := runMigrateDefaultBranch(cmd, nil, deps)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			for _, want := range tt.wantOutput {
+				assert.Contains(t, out.String(), want)
+			}
+			for _, notWant := range tt.wantNotOutput {
+				assert.NotContains(t, out.String(), notWant)
+			}
+			assert.Contains(t, stderr.String(), tt.wantStderr)
+			branches, err := g.ListLocalBranches(t.Context())
+			require.NoError(t, err)
+			got := map[string]string{}
+			for _, b := range branches {
+				got[b.Name] = b.UpstreamName
+			}
+			for name, upstream := range tt.wantBranches {
+				assert.Contains(t, got, name)
+				assert.Equal(t, upstream, got[name], name)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var renameBranchFlag bool
+
+var moveCmd = &cobra.Command{
+	Use:   "move <name> <new-name>",
+	Short: "Rename a worktree directory",
+	Long: `Move renames a worktree's directory using git worktree move, keeping its branch checked out.
+
+The worktree is resolved the same way as grove open. The new name is converted with the
+configured slugify rules and worktree prefix, exactly as grove create would name it.
+
+With --rename-branch, the branch is renamed too: the new name becomes a branch name
+(slugify + branch prefix) and the worktree directory is named from that branch.
+
+The new worktree path is printed on success.
+
+Example:
+  grove move add-auth "add oauth login"
+  grove move add-auth "add oauth login" --rename-branch`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeFirstArg(requirements{NeedsRepo: true}, completeWorktreeNames),
+	RunE:              withDeps(requirements{Mutating: true, NeedsRepo: true}, runMove),
+}
+
+func init() {
+	moveCmd.Flags().BoolVar(&renameBranchFlag, "rename-branch", false, "Also rename the branch checked out in the worktree")
+	rootCmd.AddCommand(moveCmd)
+}
+
+func runMove(cmd *cobra.Command, args []string, deps *Deps) error {
+	newName := args[1]
+	if strings.TrimSpace(newName) == "" {
+		return errors.New("new name cannot be empty")
+	}
+
+	wt, err := resolveWorktree(deps, args[0])
+	if err != nil {
+		return err
+	}
+	if wt.IsMain {
+		return errors.New("the main worktree cannot be moved")
+	}
+
+	cfg := deps.Config
+	worktreeNamer, err := newWorktreeNamer(deps)
+	if err != nil {
+		return err
+	}
+
+	oldBranch := worktreeBranchName(wt)
+	var newBranch string
+	worktreeName := worktreeNamer.Generate(newName)
+	if renameBranchFlag {
+		if oldBranch == "" {
+			return fmt.Errorf("worktree %q is not on a branch; nothing to rename", wt.AbsolutePath)
+		}
+		newBranch, err = generateBranchName(deps, newName)
+		if err != nil {
+			return err
+		}
+		if newBranch == "" || newBranch == cfg.Branch.NewPrefix {
+			return fmt.Errorf("name %q produces an empty branch name after slugification", newName)
+		}
+		worktreeName = worktreeNamer.Generate(newBranch)
+	}
+	if worktreeName == "" {
+		return fmt.Errorf("name %q produces an empty worktree name after slugification", newName)
+	}
+
+	parentDir, err := worktreeParentDir(deps)
+	if err != nil {
+		return err
+	}
+	newPath := filepath.Join(parentDir, worktreeName)
+
+	if newBranch != "" && newBranch != oldBranch {
+		exists, err := deps.Git.BranchExists(deps.Ctx, newBranch, false)
+		if err != nil {
+			return fmt.Errorf("failed to check if branch exists: %w", err)
+		}
+		if exists {
+			return fmt.Errorf("branch %q already exists", newBranch)
+		}
+	}
+
+	if newPath != wt.AbsolutePath {
+		if _, err := os.Stat(newPath); err == nil {
+			return fmt.Errorf("worktree path %q already exists", newPath)
+		}
+		if err := deps.Git.MoveWorktree(deps.Ctx, wt.AbsolutePath, newPath); err != nil {
+			return fmt.Errorf("failed to move worktree: %w", err)
+		}
+		if err := moveWorktreeState(deps, wt.AbsolutePath, newPath); err != nil {
+			return err
+		}
+	} else if newBranch == "" || newBranch == oldBranch {
+		return fmt.Errorf("worktree is already named %q", worktreeName)
+	}
+
+	if newBranch != "" && newBranch != oldBranch {
+		if err := deps.Git.RenameBranch(deps.Ctx, oldBranch, newBranch); err != nil {
+			return fmt.Errorf("worktree moved to %s, but failed to rename branch: %w", newPath, err)
+		}
+	}
+
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), newPath)
+	return err
+}
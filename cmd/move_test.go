@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunMove(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		renameBranch bool
+		wantPath     string
+		wantBranch   string
+		wantPinned   bool
+		wantErr      string
+	}{
+		{
+			name:       "renames directory and keeps branch",
+			args:       []string{"add-auth", "Add OAuth Login"},
+			wantPath:   "/ws/wt-add-oauth-login",
+			wantBranch: "feature/add-auth",
+			wantPinned: true,
+		},
+		{
+			name:         "renames directory and branch",
+			args:         []string{"add-auth", "add oauth login"},
+			renameBranch: true,
+			wantPath:     "/ws/wt-add-oauth-login",
+			wantBranch:   "feature/add-oauth-login",
+			wantPinned:   true,
+		},
+		{
+			name:         "renames branch only when directory name is unchanged",
+			args:         []string{"wt-legacy", "legacy"},
+			renameBranch: true,
+			wantPath:     "/ws/wt-legacy",
+			wantBranch:   "feature/legacy",
+		},
+		{
+			name:         "new branch already exists",
+			args:         []string{"wt-legacy", "add auth"},
+			renameBranch: true,
+			wantErr:      `branch "feature/add-auth" already exists`,
+		},
+		{
+			name:    "main worktree",
+			args:    []string{"main", "primary"},
+			wantErr: "the main worktree cannot be moved",
+		},
+		{
+			name:    "same name",
+			args:    []string{"add-auth", "add auth"},
+			wantErr: `worktree is already named "wt-add-auth"`,
+		},
+		{
+			name:    "target taken by another worktree",
+			args:    []string{"add-auth", "legacy"},
+			wantErr: "failed to move worktree",
+		},
+		{
+			name:    "empty slug",
+			args:    []string{"add-auth", "!!!"},
+			wantErr: "produces an empty worktree name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commit := git.NewCommit("bbb2222", "Work", testNow, "user")
+			g := newTestGit().
+				AddBranch("feature/add-auth", commit).
+				AddBranch("legacy-work", commit).
+				AddWorktree("/ws/wt-add-auth", "feature/add-auth").
+				AddWorktree("/ws/wt-legacy", "legacy-work")
+			deps := newTestDeps(g)
+			st := state.New()
+			st.Set("/ws/wt-add-auth", state.Worktree{Pinned: true})
+			require.NoError(t, deps.State.Save(st))
+
+			renameBranchFlag = tt.renameBranch
+			t.Cleanup(func() { renameBranchFlag = false })
+
+			cmd, out := newTestCommand()
+			err := runMove(cmd, tt.args, deps)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPath+"\n", out.String())
+			assertWorktreeOnBranch(t, g, tt.wantPath, tt.wantBranch)
+
+			st, err = deps.State.Load()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPinned, st.IsPinned(tt.wantPath), "state should follow the moved worktree")
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+
+	clog "github.com/charmbracelet/log"
+	"github.com/jmcampanini/grove-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// terminalBell rings the terminal's bell when written to it.
+const terminalBell = "\a"
+
+// notifyCommand returns the command line that shows a desktop notification on goos,
+// or nil when grove knows no notifier for the platform.
+func notifyCommand(goos, title, message string) []string {
+	switch goos {
+	case "darwin":
+		return []string{"osascript", "-e", fmt.Sprintf("display notification %q with title %q", message, title)}
+	case "windows":
+		return nil
+	default:
+		return []string{"notify-send", title, message}
+	}
+}
+
+// notifyLongOperation tells the user that the command finished, or failed, when it ran for at least
+// [notifications] long_op_seconds. A desktop notification that cannot be shown falls back to the bell.
+func notifyLongOperation(cmd *cobra.Command, deps *Deps, elapsed time.Duration, runErr error) {
+	threshold := deps.Config.Notifications.LongOpSeconds
+	if threshold <= 0 || elapsed < time.Duration(threshold)*time.Second {
+		return
+	}
+
+	message := fmt.Sprintf("%s finished in %s", cmd.CommandPath(), elapsed.Round(time.Second))
+	if runErr != nil {
+		message = fmt.Sprintf("%s failed after %s", cmd.CommandPath(), elapsed.Round(time.Second))
+	}
+	if deps.Config.Notifications.Method == config.NotifyDesktop {
+		argv := notifyCommand(runtime.GOOS, "grove", message)
+		if argv != nil {
+			err := deps.Exec(argv[0], argv[1:]...)
+			if err == nil {
+				return
+			}
+			clog.Default().Debug("failed to show desktop notification", "command", argv[0], "error", err)
+		}
+	}
+	if _, err := fmt.Fprint(cmd.ErrOrStderr(), terminalBell); err != nil {
+		clog.Default().Debug("failed to ring the terminal bell", "error", err)
+	}
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/jmcampanini/grove-cli/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifyCommand(t *testing.T) {
+	tests := []struct {
+		goos string
+		want []string
+	}{
+		{goos: "darwin", want: []string{"osascript", "-e", `display notification "grove sync finished in 42s" with title "grove"`}},
+		{goos: "linux", want: []string{"notify-send", "grove", "grove sync finished in 42s"}},
+		{goos: "windows", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			assert.Equal(t, tt.want, notifyCommand(tt.goos, "grove", "grove sync finished in 42s"))
+		})
+	}
+}
+
+func TestNotifyLongOperation(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		method    string
+		elapsed   time.Duration
+		runErr    error
+		execErr   error
+		wantBell  bool
+		wantExec  bool
+	}{
+		{name: "disabled", method: config.NotifyBell, elapsed: time.Hour},
+		{name: "quick", threshold: 30, method: config.NotifyBell, elapsed: 29 * time.Second},
+		{name: "bell", threshold: 30, method: config.NotifyBell, elapsed: 42 * time.Second, wantBell: true},
+		{name: "desktop", threshold: 30, method: config.NotifyDesktop, elapsed: 42 * time.Second, wantExec: true},
+		{
+			name:      "desktop notifier fails",
+			threshold: 30,
+			method:    config.NotifyDesktop,
+			elapsed:   42 * time.Second,
+			execErr:   errors.New("notify-send: not found"),
+			wantBell:  true,
+			wantExec:  true,
+		},
+		{name: "failed command", threshold: 30, method: config.NotifyBell, elapsed: time.Minute, runErr: errors.New("boom"), wantBell: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := newTestDeps(newTestGit())
+			deps.Config.Notifications = config.NotificationsConfig{LongOpSeconds: tt.threshold, Method: tt.method}
+			var calls [][]string
+			deps.Exec = func(name string, args ...string) error {
+				calls = append(calls, append([]string{name}, args...))
+				return tt.execErr
+			}
+			cmd := &cobra.Command{Use: "sync"}
+			var stderr bytes.Buffer
+			cmd.SetErr(&stderr)
+
+			notifyLongOperation(cmd, deps, tt.elapsed, tt.runErr)
+
+			// desktop notifications fall back to the bell where grove knows no notifier
+			notifier := notifyCommand(runtime.GOOS, "grove", "") != nil
+			if tt.wantBell || (tt.wantExec && !notifier) {
+				assert.Equal(t, terminalBell, stderr.String())
+			} else {
+				assert.Empty(t, stderr.String())
+			}
+			if tt.wantExec && notifier {
+				require.Len(t, calls, 1)
+				assert.Contains(t, calls[0][len(calls[0])-1], "sync finished in 42s")
+			} else {
+				assert.Empty(t, calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/opener"
+	"github.com/spf13/cobra"
+)
+
+var openWithFlag string
+
+var openCmd = &cobra.Command{
+	Use:   "open <name>",
+	Short: "Open a worktree in an editor or terminal",
+	Long: `Open resolves a worktree by path, directory name, display name, or branch name
+(a unique prefix is enough) and launches the configured open command for it.
+
+The command comes from [open] command in the config, or --with. It is either a
+built-in preset or a command line using Go template fields:
+  {{.Path}}    absolute worktree path
+  {{.Name}}    worktree display name
+  {{.Branch}}  checked out branch, empty when detached
+
+Presets:
+  vscode     code {{.Path}}
+  jetbrains  idea {{.Path}}
+  tmux       tmux new-window -c {{.Path}} -n {{.Name}}
+
+Example:
+  grove open add-user-auth
+  grove open feature/add-user-auth --with tmux
+  grove open main --with "zed {{.Path}}"`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeFirstArg(requirements{NeedsRepo: true}, completeWorktreeNames),
+	RunE:              withDeps(requirements{NeedsRepo: true}, runOpen),
+}
+
+func init() {
+	openCmd.Flags().StringVar(&openWithFlag, "with", "", "Preset or command template to open with (overrides [open] command)")
+	rootCmd.AddCommand(openCmd)
+}
+
+func runOpen(_ *cobra.Command, args []string, deps *Deps) error {
+	wt, err := resolveWorktree(deps, args[0])
+	if err != nil {
+		return err
+	}
+	return openWorktree(deps, wt, openWithFlag)
+}
+
+// openWorktree launches the open command for a worktree and records the visit. A non-empty override replaces [open] command.
+func openWorktree(deps *Deps, wt git.Worktree, override string) error {
+	command := deps.Config.Open.Command
+	if override != "" {
+		command = override
+	}
+
+	namer, err := newWorktreeNamer(deps)
+	if err != nil {
+		return err
+	}
+	argv, err := opener.BuildCommand(command, opener.TemplateData{
+		Branch: worktreeBranchName(wt),
+		Name:   worktreeName(wt, namer),
+		Path:   wt.AbsolutePath,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := deps.Exec(argv[0], argv[1:]...); err != nil {
+		return fmt.Errorf("failed to run %s: %w", argv[0], err)
+	}
+	recordVisit(deps, wt.AbsolutePath)
+	return nil
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordExec returns an Exec func that records each command line it is asked to run.
+func recordExec(calls *[][]string) func(string, ...string) error {
+	return func(name string, args ...string) error {
+		*calls = append(*calls, append([]string{name}, args...))
+		return nil
+	}
+}
+
+func TestRunOpen(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		with     string
+		target   string
+		wantCall []string
+		wantErr  string
+	}{
+		{
+			name:     "configured preset",
+			command:  "vscode",
+			target:   "add-auth",
+			wantCall: []string{"code", "/ws/wt-add-auth"},
+		},
+		{
+			name:     "--with overrides config",
+			command:  "vscode",
+			with:     "tmux",
+			target:   "add-auth",
+			wantCall: []string{"tmux", "new-window", "-c", "/ws/wt-add-auth", "-n", "add-auth"},
+		},
+		{
+			name:     "custom template",
+			command:  "zed {{.Branch}}",
+			target:   "add-auth",
+			wantCall: []string{"zed", "feature/add-auth"},
+		},
+		{
+			name:    "no command configured",
+			target:  "add-auth",
+			wantErr: "no open command configured",
+		},
+		{
+			name:    "unknown worktree",
+			command: "vscode",
+			target:  "nope",
+			wantErr: "no worktree matches",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGit().
+				AddBranch("feature/add-auth", git.NewCommit("bbb2222", "Work", testNow, "user")).
+				AddWorktree("/ws/wt-add-auth", "feature/add-auth")
+			deps := newTestDeps(g)
+			deps.Config.Open.Command = tt.command
+			var calls [][]string
+			deps.Exec = recordExec(&calls)
+
+			openWithFlag = tt.with
+			t.Cleanup(func() { openWithFlag = "" })
+
+			err := runOpen(nil, []string{tt.target}, deps)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.Empty(t, calls)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, [][]string{tt.wantCall}, calls)
+			st, err := deps.State.Load()
+			require.NoError(t, err)
+			assert.Equal(t, testNow, st.Get("/ws/wt-add-auth").VisitedAt, "the visit is recorded")
+		})
+	}
+}
+
+func TestRunPROpen(t *testing.T) {
+	tests := []struct {
+		name         string
+		existingPath string
+		wantPath     string
+	}{
+		{
+			name:     "creates worktree before opening",
+			wantPath: "/ws/pr-7",
+		},
+		{
+			name:         "opens existing worktree",
+			existingPath: "/ws/review-auth",
+			wantPath:     "/ws/review-auth",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGit().AddRemoteRef("origin", "pull/7/head", git.NewCommit("ccc3333", "Auth", testNow, "alice"))
+			if tt.existingPath != "" {
+				g.AddBranch("add-auth", git.NewCommit("ccc3333", "Auth", testNow, "alice")).
+					AddWorktree(tt.existingPath, "add-auth")
+			}
+			deps := newTestDeps(g)
+			deps.Config.Open.Command = "vscode"
+			deps.GitHub = &stubGitHub{prs: []github.PullRequest{{BranchName: "add-auth", Number: 7}}}
+			var calls [][]string
+			deps.Exec = recordExec(&calls)
+
+			require.NoError(t, runPROpen(nil, []string{"7"}, deps))
+
+			assert.Equal(t, [][]string{{"code", tt.wantPath}}, calls)
+			assertWorktreeOnBranch(t, g, tt.wantPath, "add-auth")
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/state"
+	"github.com/jmcampanini/grove-cli/internal/textutil"
+	"github.com/spf13/cobra"
+)
+
+const orphanSubjectMaxLen = 60
+
+var orphanTableHeaders = []string{"BRANCH", "COMMIT", "SUBJECT", "AUTHOR", "COMMITTED"}
+
+var (
+	orphansCreateFlag         bool
+	orphansDeleteFlag         bool
+	orphansDiscardCommitsFlag bool
+	orphansForceFlag          bool
+	orphansYesFlag            bool
+)
+
+var orphansCmd = &cobra.Command{
+	Use:   "orphans [<branch>...]",
+	Short: "List branches that have no worktree and no upstream",
+	Long: `Orphans lists the local branches that are not checked out in any worktree and do not
+track a remote branch, with their last commit. They are typically left behind when a
+worktree is removed without its branch. With branch names, only those are listed.
+
+--create creates a worktree for each named branch, named by the configured worktree
+naming, and prints its path.
+
+--delete deletes the named branches, or every orphan when none are named. Grove asks
+before deleting when run in a terminal; use --yes to delete without asking. A branch
+with commits that are not merged into HEAD is kept, since deleting it would lose them;
+--force deletes it anyway. Before it does, grove lists the commits that no other branch,
+tag, or remote branch has, which would become unreachable, and asks again when run in a
+terminal; use --discard-commits to delete them without asking. Otherwise the branch is kept.
+
+Example:
+  grove orphans
+  grove orphans --create fix/login
+  grove orphans --delete --yes
+  grove orphans --delete --force old-experiment
+  grove orphans --delete --force --discard-commits --yes old-experiment`,
+	RunE: withDeps(requirements{Mutating: true, NeedsRepo: true}, runOrphans),
+}
+
+func init() {
+	orphansCmd.Flags().BoolVar(&orphansCreateFlag, "create", false, "Create a worktree for each named branch")
+	orphansCmd.Flags().BoolVar(&orphansDeleteFlag, "delete", false, "Delete the named branches, or every orphan")
+	orphansCmd.Flags().BoolVar(&orphansDiscardCommitsFlag, "discard-commits", false, "With --force, delete branches whose commits would become unreachable without asking")
+	orphansCmd.Flags().BoolVar(&orphansForceFlag, "force", false, "With --delete, also delete branches with unmerged commits")
+	orphansCmd.Flags().BoolVarP(&orphansYesFlag, "yes", "y", false, "With --delete, delete without asking")
+	rootCmd.AddCommand(orphansCmd)
+}
+
+func runOrphans(cmd *cobra.Command, args []string, deps *Deps) error {
+	if orphansCreateFlag && orphansDeleteFlag {
+		return errors.New("--create cannot be combined with --delete")
+	}
+	if orphansForceFlag && !orphansDeleteFlag {
+		return errors.New("--force requires --delete")
+	}
+	if orphansDiscardCommitsFlag && !orphansForceFlag {
+		return errors.New("--discard-commits requires --force")
+	}
+	if orphansCreateFlag && len(args) == 0 {
+		return errors.New("--create needs the branches to create worktrees for")
+	}
+
+	orphans, err := listOrphanBranches(deps, args)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case orphansCreateFlag:
+		for _, b := range orphans {
+			if err := createOrphanWorktree(cmd, deps, b.Name); err != nil {
+				return err
+			}
+		}
+		return nil
+	case orphansDeleteFlag:
+		return deleteOrphanBranches(cmd, deps, orphans)
+	}
+
+	if len(orphans) == 0 {
+		_, err := fmt.Fprintln(cmd.ErrOrStderr(), "No orphaned branches")
+		return err
+	}
+	now := deps.Clock()
+	rows := make([][]string, 0, len(orphans))
+	for _, b := range orphans {
+		c := b.Commit()
+		rows = append(rows, []string{
+			b.Name,
+			shortSHASafe(c.SHA, 7),
+			textutil.Truncate(singleLine(c.Subject), orphanSubjectMaxLen),
+			c.CommittedBy,
+			formatRelativeTime(c.CommittedOn, now),
+		})
+	}
+	if _, err := fmt.Fprint(cmd.OutOrStdout(), renderTable(orphanTableHeaders, rows, useColor(cmd.OutOrStdout()))); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(cmd.ErrOrStderr(), "Run grove orphans --create <branch> to check one out, or grove orphans --delete to delete them")
+	return err
+}
+
+// listOrphanBranches returns the local branches without a worktree or upstream, in name order.
+// With names, only those branches are returned, and each must be an orphan.
+func listOrphanBranches(deps *Deps, names []string) ([]git.LocalBranch, error) {
+	branches, err := deps.Git.ListLocalBranches(deps.Ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list local branches: %w", err)
+	}
+	orphans := map[string]git.LocalBranch{}
+	var order []string
+	for _, b := range branches {
+		if b.UpstreamName != "" || b.IsCheckedOut || b.WorktreeAbsolutePath != "" {
+			continue
+		}
+		orphans[b.Name] = b
+		order = append(order, b.Name)
+	}
+	slices.Sort(order)
+
+	if len(names) > 0 {
+		order = names
+	}
+	result := make([]git.LocalBranch, 0, len(order))
+	for _, name := range order {
+		b, ok := orphans[name]
+		if !ok {
+			return nil, fmt.Errorf("%q is not an orphaned branch (it has a worktree or an upstream, or does not exist)", name)
+		}
+		result = append(result, b)
+	}
+	return result, nil
+}
+
+// createOrphanWorktree creates a worktree for an existing branch, named by the configured worktree naming,
+// and prints its path.
+func createOrphanWorktree(cmd *cobra.Command, deps *Deps, branchName string) error {
+	unlock, err := lockWorktrees(deps)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	namer, err := newWorktreeNamer(deps)
+	if err != nil {
+		return err
+	}
+	worktreeName := namer.Generate(branchName)
+	parentDir, err := worktreeParentDir(deps)
+	if err != nil {
+		return err
+	}
+	worktreePath, err := newWorktreePath(deps, parentDir, worktreeName)
+	if err != nil {
+		return err
+	}
+
+	if err := deps.Git.CreateWorktreeForExistingBranch(deps.Ctx, branchName, worktreePath); err != nil {
+		return fmt.Errorf("failed to create worktree: %w", err)
+	}
+	recordCreatedWorktree(deps, worktreePath, state.OriginCreate, 0)
+
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), worktreePath)
+	return err
+}
+
+// deleteOrphanBranches deletes the orphans after confirmation, keeping those with unmerged commits unless
+// --force is given and losing their commits is confirmed. It fails if any branch was kept.
+func deleteOrphanBranches(cmd *cobra.Command, deps *Deps, orphans []git.LocalBranch) error {
+	if len(orphans) == 0 {
+		_, err := fmt.Fprintln(cmd.ErrOrStderr(), "No orphaned branches")
+		return err
+	}
+	names := make([]string, 0, len(orphans))
+	for _, b := range orphans {
+		names = append(names, b.Name)
+	}
+
+	if !orphansYesFlag {
+		if _, err := fmt.Fprintln(cmd.OutOrStdout(), strings.Join(names, "\n")); err != nil {
+			return err
+		}
+		if !isTerminal(cmd.InOrStdin()) {
+			_, err := fmt.Fprintln(cmd.ErrOrStderr(), "Run grove orphans --delete --yes to delete them")
+			return err
+		}
+		ok, err := confirm(cmd.InOrStdin(), cmd.ErrOrStderr(), fmt.Sprintf("Delete %d branch(es)?", len(names)))
+		if err != nil || !ok {
+			return err
+		}
+	}
+
+	deleted, kept := 0, 0
+	for _, name := range names {
+		err := deps.Git.DeleteBranch(deps.Ctx, name, false)
+		if errors.Is(err, git.ErrNotMerged) && orphansForceFlag {
+			discard, confirmErr := confirmDiscardCommits(cmd, deps, name)
+			if confirmErr != nil {
+				return confirmErr
+			}
+			if discard {
+				err = deps.Git.DeleteBranch(deps.Ctx, name, true)
+			}
+		}
+		if errors.Is(err, git.ErrNotMerged) {
+			kept++
+			hint := "use --force to delete it anyway"
+			if orphansForceFlag {
+				hint = "use --discard-commits to delete it and its commits"
+			}
+			if _, err := fmt.Fprintf(cmd.ErrOrStderr(), "Kept %s: it has unmerged commits; %s\n", name, hint); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		deleted++
+	}
+	if _, err := fmt.Fprintf(cmd.ErrOrStderr(), "Deleted %d branch(es)\n", deleted); err != nil {
+		return err
+	}
+	if kept > 0 {
+		return fmt.Errorf("%d branch(es) with unmerged commits were not deleted", kept)
+	}
+	return nil
+}
+
+// confirmDiscardCommits lists the commits that deleting a branch would make unreachable, and reports whether to
+// delete it anyway: with --discard-commits, or when confirmed in a terminal. A branch whose commits another
+// ref still reaches loses nothing, so it is deleted without asking.
+func confirmDiscardCommits(cmd *cobra.Command, deps *Deps, name string) (bool, error) {
+	commits, err := deps.Git.ListUnreachableCommits(deps.Ctx, name)
+	if err != nil {
+		return false, err
+	}
+	if len(commits) == 0 {
+		return true, nil
+	}
+	w := cmd.ErrOrStderr()
+	if _, err := fmt.Fprintf(w, "Deleting %s loses %d commit(s) that no other branch or tag has:\n", name, len(commits)); err != nil {
+		return false, err
+	}
+	for _, c := range commits {
+		if _, err := fmt.Fprintf(w, "  %s %s\n", shortSHASafe(c.SHA, 7), textutil.Truncate(singleLine(c.Subject), orphanSubjectMaxLen)); err != nil {
+			return false, err
+		}
+	}
+	if orphansDiscardCommitsFlag {
+		return true, nil
+	}
+	if !isTerminal(cmd.InOrStdin()) {
+		return false, nil
+	}
+	return confirm(cmd.InOrStdin(), w, fmt.Sprintf("Delete %s and these commits?", name))
+}
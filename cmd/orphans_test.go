@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/git/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newOrphansTestGit returns a repository with two orphaned branches, one with unmerged commits, next to
+// a branch with a worktree and a branch with an upstream.
+func newOrphansTestGit() *fake.Git {
+	return newTestGit().
+		AddBranch("old-experiment", git.NewCommit("ccc3333", "Try a cache", testNow.Add(-3*24*time.Hour), "alice")).
+		AddBranch("fix/login", git.NewCommit("ddd4444", "Fix login", testNow.Add(-2*time.Hour), "bob")).
+		AddBranch("feature/auth", git.NewCommit("eee5555", "Auth", testNow, "user")).
+		AddWorktree("/ws/wt-auth", "feature/auth").
+		AddBranch("feature/pushed", git.NewCommit("fff6666", "Pushed", testNow, "user")).
+		SetUpstream("feature/pushed", "origin/feature/pushed", 0, 0).
+		SetUnmerged("old-experiment")
+}
+
+func resetOrphansFlags(t *testing.T) {
+	t.Cleanup(func() {
+		orphansCreateFlag, orphansDeleteFlag, orphansDiscardCommitsFlag, orphansForceFlag, orphansYesFlag = false, false, false, false, false
+	})
+}
+
+func TestRunOrphans(t *testing.T) {
+	deps := newTestDeps(newOrphansTestGit())
+	cmd, out := newTestCommand()
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+
+	require.NoError(t, runOrphans(cmd, nil, deps))
+
+	assert.Equal(t, `BRANCH          COMMIT   SUBJECT      AUTHOR  COMMITTED
+fix/login       ddd4444  Fix login    bob     2h ago
+old-experiment  ccc3333  Try a cache  alice   3d ago
+`, out.String())
+	assert.Contains(t, stderr.String(), "grove orphans --create <branch>")
+}
+
+func TestRunOrphans_NotAnOrphan(t *testing.T) {
+	deps := newTestDeps(newOrphansTestGit())
+	cmd, _ := newTestCommand()
+
+	err := runOrphans(cmd, []string{"fix/login", "feature/auth"}, deps)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"feature/auth" is not an orphaned branch`)
+}
+
+func TestRunOrphans_Create(t *testing.T) {
+	resetOrphansFlags(t)
+	orphansCreateFlag = true
+	g := newOrphansTestGit()
+	deps := newTestDeps(g)
+	cmd, out := newTestCommand()
+
+	require.NoError(t, runOrphans(cmd, []string{"fix/login"}, deps))
+
+	assert.Equal(t, "/ws/wt-fix-login\n", out.String())
+	branches, err := g.ListLocalBranches(t.Context())
+	require.NoError(t, err)
+	for _, b := range branches {
+		if b.Name == "fix/login" {
+			assert.Equal(t, "/ws/wt-fix-login", b.WorktreeAbsolutePath)
+		}
+	}
+}
+
+func TestRunOrphans_Delete(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		discard      bool
+		force        bool
+		unreachable  bool // old-experiment's commits are on no other ref
+		wantErr      string
+		wantStderr   string
+		wantBranches []string
+	}{
+		{
+			name:         "keeps unmerged",
+			wantErr:      "1 branch(es) with unmerged commits were not deleted",
+			wantStderr:   "Kept old-experiment: it has unmerged commits; use --force to delete it anyway\nDeleted 1 branch(es)\n",
+			wantBranches: []string{"feature/auth", "feature/pushed", "main", "old-experiment"},
+		},
+		{
+			name:         "force",
+			force:        true,
+			wantStderr:   "Deleted 2 branch(es)\n",
+			wantBranches: []string{"feature/auth", "feature/pushed", "main"},
+		},
+		{
+			name:        "force lists the commits it would lose and keeps the branch",
+			force:       true,
+			unreachable: true,
+			wantErr:     "1 branch(es) with unmerged commits were not deleted",
+			wantStderr: "Deleting old-experiment loses 2 commit(s) that no other branch or tag has:\n" +
+				"  ccc3333 Try a cache\n" +
+				"  bbb2222 Start a cache\n" +
+				"Kept old-experiment: it has unmerged commits; use --discard-commits to delete it and its commits\n" +
+				"Deleted 1 branch(es)\n",
+			wantBranches: []string{"feature/auth", "feature/pushed", "main", "old-experiment"},
+		},
+		{
+			name:        "force with discard commits",
+			discard:     true,
+			force:       true,
+			unreachable: true,
+			wantStderr: "Deleting old-experiment loses 2 commit(s) that no other branch or tag has:\n" +
+				"  ccc3333 Try a cache\n" +
+				"  bbb2222 Start a cache\n" +
+				"Deleted 2 branch(es)\n",
+			wantBranches: []string{"feature/auth", "feature/pushed", "main"},
+		},
+		{
+			name:         "named",
+			args:         []string{"fix/login"},
+			wantStderr:   "Deleted 1 branch(es)\n",
+			wantBranches: []string{"feature/auth", "feature/pushed", "main", "old-experiment"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetOrphansFlags(t)
+			orphansDeleteFlag, orphansDiscardCommitsFlag, orphansForceFlag, orphansYesFlag = true, tt.discard, tt.force, true
+			g := newOrphansTestGit()
+			if tt.unreachable {
+				g.SetUnreachable("old-experiment",
+					git.NewCommit("ccc3333", "Try a cache", testNow, "alice"),
+					git.NewCommit("bbb2222", "Start a cache", testNow, "alice"))
+			}
+			deps := newTestDeps(g)
+			cmd, _ := newTestCommand()
+			cmd.SetIn(strings.NewReader("")) // not a terminal, so losing commits is never confirmed
+			var stderr bytes.Buffer
+			cmd.SetErr(&stderr)
+
+			err := runOrphans(cmd, tt.args, deps)
+
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantStderr, stderr.String())
+			branches, err := g.ListLocalBranches(t.Context())
+			require.NoError(t, err)
+			var names []string
+			for _, b := range branches {
+				names = append(names, b.Name)
+			}
+			assert.ElementsMatch(t, tt.wantBranches, names)
+		})
+	}
+}
+
+func TestRunOrphans_DeleteNotATerminal(t *testing.T) {
+	resetOrphansFlags(t)
+	orphansDeleteFlag = true
+	g := newOrphansTestGit()
+	deps := newTestDeps(g)
+	cmd, out := newTestCommand()
+	cmd.SetIn(strings.NewReader("y\n"))
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+
+	require.NoError(t, runOrphans(cmd, nil, deps))
+
+	assert.Equal(t, "fix/login\nold-experiment\n", out.String())
+	assert.Equal(t, "Run grove orphans --delete --yes to delete them\n", stderr.String())
+	exists, err := g.BranchExists(t.Context(), "fix/login", false)
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestRunOrphans_InvalidFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		create  bool
+		delete  bool
+		discard bool
+		force   bool
+		wantErr string
+	}{
+		{name: "create and delete", create: true, delete: true, wantErr: "--create cannot be combined with --delete"},
+		{name: "force without delete", force: true, wantErr: "--force requires --delete"},
+		{name: "discard commits without force", delete: true, discard: true, wantErr: "--discard-commits requires --force"},
+		{name: "create without branches", create: true, wantErr: "--create needs the branches to create worktrees for"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetOrphansFlags(t)
+			orphansCreateFlag, orphansDeleteFlag, orphansDiscardCommitsFlag, orphansForceFlag = tt.create, tt.delete, tt.discard, tt.force
+			cmd, _ := newTestCommand()
+
+			err := runOrphans(cmd, nil, newTestDeps(newOrphansTestGit()))
+
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jmcampanini/grove-cli/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var pinCmd = &cobra.Command{
+	Use:   "pin <name>",
+	Short: "Protect a worktree from automatic cleanup",
+	Long: `Pin marks a worktree so that grove never removes it automatically.
+
+Pinned worktrees are shown with a 📌 marker in grove list --fzf output.
+The worktree is resolved the same way as grove open.
+
+Example:
+  grove pin add-auth
+  grove unpin add-auth`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeFirstArg(requirements{NeedsRepo: true}, completeWorktreeNames),
+	RunE: withDeps(requirements{Mutating: true, NeedsRepo: true}, func(cmd *cobra.Command, args []string, deps *Deps) error {
+		return runSetPinned(cmd, args, deps, true)
+	}),
+}
+
+var unpinCmd = &cobra.Command{
+	Use:               "unpin <name>",
+	Short:             "Allow a pinned worktree to be cleaned up again",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeFirstArg(requirements{NeedsRepo: true}, completeWorktreeNames),
+	RunE: withDeps(requirements{Mutating: true, NeedsRepo: true}, func(cmd *cobra.Command, args []string, deps *Deps) error {
+		return runSetPinned(cmd, args, deps, false)
+	}),
+}
+
+func init() {
+	rootCmd.AddCommand(pinCmd)
+	rootCmd.AddCommand(unpinCmd)
+}
+
+func runSetPinned(cmd *cobra.Command, args []string, deps *Deps, pinned bool) error {
+	wt, err := resolveWorktree(deps, args[0])
+	if err != nil {
+		return err
+	}
+
+	err = updateWorktreeState(deps, wt.AbsolutePath, func(entry *state.Worktree) {
+		entry.Pinned = pinned
+	})
+	if err != nil {
+		return err
+	}
+
+	verb := "Pinned"
+	if !pinned {
+		verb = "Unpinned"
+	}
+	_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s %s\n", verb, wt.AbsolutePath)
+	return err
+}
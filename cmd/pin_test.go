@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSetPinned(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		pinned     bool
+		wantOut    string
+		wantPinned bool
+		wantErr    string
+	}{
+		{
+			name:       "pin",
+			target:     "add-auth",
+			pinned:     true,
+			wantOut:    "Pinned /ws/wt-add-auth\n",
+			wantPinned: true,
+		},
+		{
+			name:    "unpin",
+			target:  "add-auth",
+			wantOut: "Unpinned /ws/wt-add-auth\n",
+		},
+		{
+			name:    "unknown worktree",
+			target:  "nope",
+			pinned:  true,
+			wantErr: "no worktree matches",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGit().
+				AddBranch("feature/add-auth", git.NewCommit("bbb2222", "Work", testNow, "user")).
+				AddWorktree("/ws/wt-add-auth", "feature/add-auth")
+			deps := newTestDeps(g)
+
+			cmd, out := newTestCommand()
+			err := runSetPinned(cmd, []string{tt.target}, deps, tt.pinned)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOut, out.String())
+
+			st, err := deps.State.Load()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPinned, st.IsPinned("/ws/wt-add-auth"))
+		})
+	}
+}
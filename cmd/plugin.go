@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// pluginPrefix is the executable name prefix of external subcommands: grove-<name> runs as grove <name>.
+const pluginPrefix = "grove-"
+
+// Environment variables passed to plugins describing the current repository.
+// They are empty when grove is not run inside a git repository.
+const (
+	pluginEnvConfigPaths      = "GROVE_CONFIG_PATHS"       // config files that were loaded, joined with the OS path list separator
+	pluginEnvMainWorktreePath = "GROVE_MAIN_WORKTREE_PATH" // absolute path of the main worktree
+	pluginEnvRepoRoot         = "GROVE_REPO_ROOT"          // absolute path of the current worktree root
+	pluginEnvWorkspacePath    = "GROVE_WORKSPACE_PATH"     // directory containing all worktrees
+)
+
+// ExitError reports that a command exited with a specific status code that grove should exit with too.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// findPlugin returns the grove-<name> executable for args when args[0] is not a built-in command.
+func findPlugin(args []string) (path string, ok bool) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return "", false
+	}
+	name := args[0]
+	// cobra only registers help and completion when the root command executes
+	if name == "help" || name == "completion" {
+		return "", false
+	}
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return "", false
+		}
+	}
+
+	path, err := exec.LookPath(pluginPrefix + name)
+	if err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// runPlugin runs a plugin executable with the given arguments, attached to grove's stdio.
+// The plugin's exit status is returned as an *ExitError.
+func runPlugin(ctx context.Context, path string, args []string) error {
+	deps, err := newDeps(ctx, requirements{})
+	if err != nil {
+		return err
+	}
+
+	c := exec.Command(path, args...)
+	c.Env = append(os.Environ(), pluginEnv(deps)...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+
+	if err := c.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return &ExitError{Code: exitErr.ExitCode()}
+		}
+		return fmt.Errorf("failed to run plugin %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// pluginEnv returns the environment variables describing the current repository to plugins.
+func pluginEnv(deps *Deps) []string {
+	var workspacePath string
+	if deps.MainWorktreePath != "" {
+		workspacePath = filepath.Dir(deps.MainWorktreePath)
+	}
+
+	return []string{
+		pluginEnvConfigPaths + "=" + strings.Join(deps.ConfigPaths, string(os.PathListSeparator)),
+		pluginEnvMainWorktreePath + "=" + deps.MainWorktreePath,
+		pluginEnvRepoRoot + "=" + deps.WorktreeRoot,
+		pluginEnvWorkspacePath + "=" + workspacePath,
+	}
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePlugin writes an executable shell script named grove-<name> to dir.
+func writePlugin(t *testing.T, dir, name, script string) string {
+	t.Helper()
+	path := filepath.Join(dir, pluginPrefix+name)
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0o755))
+	return path
+}
+
+func TestFindPlugin(t *testing.T) {
+	dir := t.TempDir()
+	helloPath := writePlugin(t, dir, "hello", "exit 0")
+	writePlugin(t, dir, "list", "exit 0")
+	t.Setenv("PATH", dir)
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantPath string
+		wantOK   bool
+	}{
+		{name: "plugin on PATH", args: []string{"hello", "--flag"}, wantPath: helloPath, wantOK: true},
+		{name: "built-in command wins", args: []string{"list"}},
+		{name: "help command wins", args: []string{"help"}},
+		{name: "unknown command", args: []string{"missing"}},
+		{name: "leading flag", args: []string{"--verbose", "hello"}},
+		{name: "no args"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, ok := findPlugin(tt.args)
+
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantPath, path)
+		})
+	}
+}
+
+func TestPluginEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		deps *Deps
+		want []string
+	}{
+		{
+			name: "inside a repository",
+			deps: &Deps{
+				ConfigPaths:      []string{"/home/.config/grove/config.toml", "/ws/main/.grove.toml"},
+				MainWorktreePath: "/ws/main",
+				WorktreeRoot:     "/ws/feature",
+			},
+			want: []string{
+				"GROVE_CONFIG_PATHS=/home/.config/grove/config.toml" + string(os.PathListSeparator) + "/ws/main/.grove.toml",
+				"GROVE_MAIN_WORKTREE_PATH=/ws/main",
+				"GROVE_REPO_ROOT=/ws/feature",
+				"GROVE_WORKSPACE_PATH=/ws",
+			},
+		},
+		{
+			name: "outside a repository",
+			deps: &Deps{},
+			want: []string{
+				"GROVE_CONFIG_PATHS=",
+				"GROVE_MAIN_WORKTREE_PATH=",
+				"GROVE_REPO_ROOT=",
+				"GROVE_WORKSPACE_PATH=",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, pluginEnv(tt.deps))
+		})
+	}
+}
+
+func TestRunPlugin(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test that runs git in short mode")
+	}
+
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("GIT_CEILING_DIRECTORIES", dir)
+	out := filepath.Join(dir, "out")
+
+	tests := []struct {
+		name     string
+		script   string
+		args     []string
+		wantErr  error
+		wantFile string
+	}{
+		{
+			name:     "passes args and repository env",
+			script:   `echo "$@ root=$GROVE_REPO_ROOT" > ` + out,
+			args:     []string{"a", "b"},
+			wantFile: "a b root=\n",
+		},
+		{
+			name:    "propagates exit status",
+			script:  "exit 3",
+			wantErr: &ExitError{Code: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writePlugin(t, t.TempDir(), "test", tt.script)
+
+			err := runPlugin(t.Context(), path, tt.args)
+
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+			require.NoError(t, err)
+			content, err := os.ReadFile(out)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFile, string(content))
+		})
+	}
+}
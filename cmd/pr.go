@@ -0,0 +1,291 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	clog "github.com/charmbracelet/log"
+	"github.com/jmcampanini/grove-cli/internal/github"
+	"github.com/jmcampanini/grove-cli/internal/naming"
+	"github.com/jmcampanini/grove-cli/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var prCmd = &cobra.Command{
+	Use:   "pr",
+	Short: "Work with pull requests",
+	Long: `Work with GitHub pull requests using the gh CLI.
+
+Pull requests are checked out into worktrees named by the [pr] templates in the config.
+A pull request's local branch tracks its head branch on the remote, so git pull and git
+push work in its worktree, unless the pull request comes from a fork.`,
+}
+
+func init() {
+	rootCmd.AddCommand(prCmd)
+}
+
+// parsePRNumber parses a pull request number argument such as "123" or "#123".
+func parsePRNumber(arg string) (int, error) {
+	num, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(arg), "#"))
+	if err != nil || num <= 0 {
+		return 0, fmt.Errorf("invalid pull request number: %q", arg)
+	}
+	return num, nil
+}
+
+// prURLPattern matches a pull request URL inside arbitrary text.
+var prURLPattern = regexp.MustCompile(`https?://[^\s/]+/[^\s/]+/[^\s/]+/pull/\d+`)
+
+// prArgs accepts exactly one pull request argument, or none when it comes from the clipboard.
+func prArgs(fromClipboard *bool) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if *fromClipboard {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	}
+}
+
+// prArgument returns the pull request argument: args[0], or the reference found on the clipboard.
+func prArgument(deps *Deps, args []string, fromClipboard bool) (string, error) {
+	if !fromClipboard {
+		return args[0], nil
+	}
+	text, err := deps.Clipboard()
+	if err != nil {
+		return "", err
+	}
+	return extractPRReference(text)
+}
+
+// extractPRReference finds a pull request URL anywhere in text, or accepts text that is just a number.
+func extractPRReference(text string) (string, error) {
+	if url := prURLPattern.FindString(text); url != "" {
+		return url, nil
+	}
+	if _, err := parsePRNumber(text); err == nil {
+		return strings.TrimSpace(text), nil
+	}
+	return "", errors.New("clipboard does not contain a pull request number or URL")
+}
+
+// resolvePullRequest looks up the pull request an argument refers to: a number ("123" or "#123"),
+// a pull request URL, which must belong to the current repository, or the name of the PR's head branch.
+func resolvePullRequest(deps *Deps, arg string) (github.PullRequest, error) {
+	arg = strings.TrimSpace(arg)
+
+	if github.IsPullRequestURL(arg) {
+		urlRepo, prNum, err := github.ParsePullRequestURL(arg)
+		if err != nil {
+			return github.PullRequest{}, err
+		}
+		repo, err := deps.GitHub.GetRepository(deps.Ctx)
+		if err != nil {
+			return github.PullRequest{}, err
+		}
+		if !urlRepo.Equal(repo) {
+			return github.PullRequest{}, fmt.Errorf("pull request %s belongs to %s, not the current repository %s", arg, urlRepo.FullName(), repo.FullName())
+		}
+		return deps.GitHub.GetPullRequest(deps.Ctx, prNum)
+	}
+
+	if prNum, err := parsePRNumber(arg); err == nil {
+		return deps.GitHub.GetPullRequest(deps.Ctx, prNum)
+	}
+
+	if arg == "" {
+		return github.PullRequest{}, errors.New("pull request number, URL, or branch cannot be empty")
+	}
+	pr, err := deps.GitHub.GetPullRequestByBranch(deps.Ctx, arg)
+	if err != nil {
+		return github.PullRequest{}, err
+	}
+	if pr == nil {
+		return github.PullRequest{}, fmt.Errorf("no pull request found for branch %q", arg)
+	}
+	return *pr, nil
+}
+
+// createPRWorktree checks out a pull request into a worktree and returns the worktree path.
+// If grove already created a worktree for the PR, or the PR branch is checked out in a worktree,
+// that worktree's path is returned. An existing local branch that is behind the PR's head is
+// reset to it when update is set, and otherwise used as is with a warning.
+func createPRWorktree(deps *Deps, pr github.PullRequest, update bool) (string, error) {
+	namer, err := naming.NewPRWorktreeNamer(deps.Config.PR, deps.Config.Slugify)
+	if err != nil {
+		return "", fmt.Errorf("invalid config: %w", err)
+	}
+
+	data := naming.PRTemplateData{AuthorLogin: pr.AuthorLogin, BranchName: pr.BranchName, Number: pr.Number, Title: pr.Title}
+	branchName, err := namer.BranchName(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate branch name for pull request #%d: %w", pr.Number, err)
+	}
+	worktreeName, err := namer.WorktreeName(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate worktree name for pull request #%d: %w", pr.Number, err)
+	}
+
+	unlock, err := lockWorktrees(deps)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	worktrees, err := deps.Git.ListWorktrees(deps.Ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	st, err := loadWorktreeState(deps, worktrees)
+	if err != nil {
+		return "", err
+	}
+	if path, ok := st.FindPR(pr.Number); ok {
+		return path, nil
+	}
+	for _, wt := range worktrees {
+		if worktreeBranchName(wt) == branchName {
+			return wt.AbsolutePath, nil
+		}
+	}
+
+	parentDir, err := worktreeParentDir(deps)
+	if err != nil {
+		return "", err
+	}
+	worktreePath, err := newWorktreePath(deps, parentDir, worktreeName)
+	if err != nil {
+		return "", err
+	}
+
+	exists, err := deps.Git.BranchExists(deps.Ctx, branchName, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to check if branch exists: %w", err)
+	}
+	if exists {
+		exists, err = keepPRBranch(deps, pr, branchName, update)
+		if err != nil {
+			return "", err
+		}
+	}
+	if !exists {
+		remote, err := deps.Git.GetDefaultRemote(deps.Ctx, "origin")
+		if err != nil {
+			return "", fmt.Errorf("failed to get default remote: %w", err)
+		}
+		if err := deps.Git.FetchRemoteBranch(deps.Ctx, remote, fmt.Sprintf("pull/%d/head", pr.Number), branchName); err != nil {
+			return "", fmt.Errorf("failed to fetch pull request #%d: %w", pr.Number, err)
+		}
+		trackPRHeadBranch(deps, remote, pr, branchName)
+	}
+
+	if err := deps.Git.CreateWorktreeForExistingBranch(deps.Ctx, branchName, worktreePath); err != nil {
+		return "", fmt.Errorf("failed to create worktree: %w", err)
+	}
+	recordCreatedWorktree(deps, worktreePath, state.OriginPR, pr.Number)
+
+	return worktreePath, nil
+}
+
+// keepPRBranch reports whether the existing local branch for a pull request should be used as is. A branch
+// at the PR's head is kept. A branch behind the head is reset to it when update is set, and otherwise kept
+// with a warning. A branch with commits the PR does not have is never reset, so --update cannot drop them:
+// it is kept with a warning, or refused when update is set. A PR with no known head SHA keeps the branch.
+func keepPRBranch(deps *Deps, pr github.PullRequest, branchName string, update bool) (bool, error) {
+	if pr.HeadSHA == "" {
+		return true, nil
+	}
+	sha, err := deps.Git.ResolveRef(deps.Ctx, branchName)
+	if err != nil {
+		return false, err
+	}
+	if sha == pr.HeadSHA {
+		return true, nil
+	}
+	remote, err := deps.Git.GetDefaultRemote(deps.Ctx, "origin")
+	if err != nil {
+		return false, fmt.Errorf("failed to get default remote: %w", err)
+	}
+	head, err := deps.Git.FetchRef(deps.Ctx, remote, fmt.Sprintf("pull/%d/head", pr.Number))
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch pull request #%d: %w", pr.Number, err)
+	}
+	if head == "" {
+		return true, nil // dry run: nothing was fetched to compare the branch with
+	}
+	ahead, _, err := deps.Git.GetAheadBehind(deps.Ctx, branchName, head)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case ahead > 0 && update:
+		return false, fmt.Errorf("local branch %s has %d commit(s) that pull request #%d does not; push them or delete the branch before running grove pr create --update",
+			branchName, ahead, pr.Number)
+	case ahead > 0:
+		clog.Default().Warn("local branch has commits the pull request does not; using it as is",
+			"branch", branchName, "ahead", ahead, "local", shortSHASafe(sha, 7), "head", shortSHASafe(head, 7))
+		return true, nil
+	case !update:
+		clog.Default().Warn("local branch is behind the pull request's head; run grove pr create --update to reset it",
+			"branch", branchName, "local", shortSHASafe(sha, 7), "head", shortSHASafe(head, 7))
+		return true, nil
+	}
+	// the branch is not checked out anywhere and only lacks commits, so it is moved to the head in place
+	if err := deps.Git.UpdateRef(deps.Ctx, "refs/heads/"+branchName, head); err != nil {
+		return false, fmt.Errorf("failed to reset branch %s: %w", branchName, err)
+	}
+	return true, nil
+}
+
+// trackPRHeadBranch makes the pull request's local branch track its head branch on the remote, so that git pull
+// and git push work in its worktree. The head branch of a pull request from a fork is not on the remote, so it is
+// not tracked. The branch was already fetched at this point, so a failure is logged rather than returned.
+func trackPRHeadBranch(deps *Deps, remote string, pr github.PullRequest, branchName string) {
+	if pr.FromFork || pr.BranchName == "" {
+		return
+	}
+	trackingRef := "refs/remotes/" + remote + "/" + pr.BranchName
+	// forced, as a pull request's head branch is often rebased and force-pushed
+	if err := deps.Git.FetchRemoteBranch(deps.Ctx, remote, "+"+pr.BranchName, trackingRef); err != nil {
+		clog.Default().Warn("failed to fetch the pull request's head branch; not tracking it", "branch", pr.BranchName, "error", err)
+		return
+	}
+	upstream := remote + "/" + pr.BranchName
+	if err := deps.Git.SetBranchUpstream(deps.Ctx, branchName, upstream); err != nil {
+		clog.Default().Warn("failed to set upstream", "branch", branchName, "upstream", upstream, "error", err)
+	}
+}
+
+// prWorktreePaths maps pull request numbers to the worktrees checked out for them.
+// Worktrees grove recorded for a PR are matched by number; others are matched by the PR's head branch.
+func prWorktreePaths(deps *Deps, prs []github.PullRequest) (map[int]string, error) {
+	worktrees, err := deps.Git.ListWorktrees(deps.Ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	st, err := loadWorktreeState(deps, worktrees)
+	if err != nil {
+		return nil, err
+	}
+
+	byBranch := make(map[string]string, len(worktrees))
+	for _, wt := range worktrees {
+		if branch := worktreeBranchName(wt); branch != "" {
+			byBranch[branch] = wt.AbsolutePath
+		}
+	}
+
+	paths := make(map[int]string)
+	for _, pr := range prs {
+		if path, ok := st.FindPR(pr.Number); ok {
+			paths[pr.Number] = path
+		} else if path, ok := byBranch[pr.BranchName]; ok {
+			paths[pr.Number] = path
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jmcampanini/grove-cli/internal/github"
+	"github.com/jmcampanini/grove-cli/internal/picker"
+	"github.com/spf13/cobra"
+)
+
+var prCheckoutCmd = &cobra.Command{
+	Use:   "checkout",
+	Short: "Pick an open pull request and create its worktree",
+	Long: `Checkout lists open pull requests, lets you pick one, creates its worktree, and prints the path.
+
+The picker is chosen by the [ui] picker config: "auto" (the default) uses fzf when it is
+installed and a numbered prompt otherwise. This replaces the manual pipeline:
+  grove pr list --fzf | fzf --delimiter '\t' --with-nth 2 | cut -f1 | xargs grove pr create
+
+Example:
+  cd "$(grove pr checkout)"`,
+	Args: cobra.NoArgs,
+	RunE: withDeps(requirements{Mutating: true, NeedsProvider: true, NeedsRepo: true}, runPRCheckout),
+}
+
+func init() {
+	prCmd.AddCommand(prCheckoutCmd)
+}
+
+func runPRCheckout(cmd *cobra.Command, _ []string, deps *Deps) error {
+	prs, err := deps.GitHub.ListPullRequests(deps.Ctx, github.PRQuery{State: github.PRStateOpen}, deps.Config.PR.ListLimit)
+	if err != nil {
+		return fmt.Errorf("failed to list pull requests: %w", err)
+	}
+	if len(prs) == 0 {
+		return errors.New("no open pull requests found")
+	}
+
+	items := make([]picker.Item, len(prs))
+	for i, pr := range prs {
+		items[i] = picker.Item{Display: formatPRDisplay(pr), Key: strconv.Itoa(pr.Number)}
+	}
+
+	// the prompt picker writes to stderr so that stdout only contains the worktree path
+	p, err := picker.New(deps.Config.UI.Picker, "grove pr preview {1}", cmd.InOrStdin(), cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+
+	selected, err := p.Pick(items)
+	if err != nil {
+		return err
+	}
+
+	var pr github.PullRequest
+	for _, candidate := range prs {
+		if strconv.Itoa(candidate.Number) == selected.Key {
+			pr = candidate
+			break
+		}
+	}
+
+	worktreePath, err := createPRWorktree(deps, pr, false)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), worktreePath)
+	return err
+}
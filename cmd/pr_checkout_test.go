@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/config"
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/github"
+	"github.com/jmcampanini/grove-cli/internal/picker"
+	"github.com/jmcampanini/grove-cli/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunPRCheckout(t *testing.T) {
+	prs := []github.PullRequest{
+		{AuthorLogin: "alice", BranchName: "add-auth", Number: 10, Title: "Add auth"},
+		{AuthorLogin: "bob", BranchName: "fix-bug", Number: 11, Title: "Fix bug"},
+	}
+
+	tests := []struct {
+		name         string
+		input        string
+		branchExists bool
+		wantBranch   string
+		wantNumber   int
+		wantPath     string
+		wantErr      error
+	}{
+		{
+			name:       "fetches and creates worktree for selected PR",
+			input:      "2\n",
+			wantBranch: "fix-bug",
+			wantNumber: 11,
+			wantPath:   "/ws/pr-11",
+		},
+		{
+			name:         "reuses existing local branch",
+			input:        "1\n",
+			branchExists: true,
+			wantBranch:   "add-auth",
+			wantNumber:   10,
+			wantPath:     "/ws/pr-10",
+		},
+		{
+			name:    "cancelled selection",
+			input:   "\n",
+			wantErr: picker.ErrCancelled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGit().AddRemoteRef("origin", "pull/11/head", git.NewCommit("fff6666", "Fix bug", testNow, "bob"))
+			if tt.branchExists {
+				g.AddBranch("add-auth", git.NewCommit("eee5555", "Add auth", testNow, "alice"))
+			}
+			deps := newTestDeps(g)
+			deps.Config.UI.Picker = config.PickerPrompt
+			deps.GitHub = &stubGitHub{prs: prs}
+
+			cmd, out := newTestCommand()
+			cmd.SetIn(strings.NewReader(tt.input))
+			cmd.SetErr(&bytes.Buffer{})
+
+			err := runPRCheckout(cmd, nil, deps)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPath+"\n", out.String())
+			assertWorktreeOnBranch(t, g, tt.wantPath, tt.wantBranch)
+			assertWorktreeRecorded(t, deps, tt.wantPath, state.Worktree{CreatedAt: testNow, Origin: state.OriginPR, PRNumber: tt.wantNumber})
+		})
+	}
+}
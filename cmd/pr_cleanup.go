@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	clog "github.com/charmbracelet/log"
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/github"
+	"github.com/jmcampanini/grove-cli/internal/pathutil"
+	"github.com/jmcampanini/grove-cli/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var prCleanupDeleteRemoteFlag bool
+
+var prCleanupCmd = &cobra.Command{
+	Use:   "cleanup [<number|url|branch>]",
+	Short: "Remove the worktrees of merged pull requests",
+	Long: `Cleanup removes the worktree of a merged pull request and deletes its local branch.
+With no argument, every worktree grove created for a pull request is checked, and those
+whose pull request is merged are cleaned up.
+
+GitHub is asked whether the pull request is merged; cleanup refuses a pull request that is
+not. A worktree with modified or untracked files is not removed: commit or remove them first.
+The local branch is kept when it has commits that are not in the merged pull request.
+
+With --delete-remote, the pull request's head branch is also deleted from the remote, unless
+it comes from a fork.
+
+With --summary-file, each pull request worktree is an item with status "removed", "kept"
+when its pull request is not merged, or "failed".
+
+Example:
+  grove pr cleanup 123
+  grove pr cleanup --delete-remote`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeFirstArg(requirements{NeedsProvider: true, NeedsRepo: true}, completePRNumbers),
+	RunE:              withDeps(requirements{Mutating: true, NeedsProvider: true, NeedsRepo: true, Summary: true}, runPRCleanup),
+}
+
+func init() {
+	prCleanupCmd.Flags().BoolVar(&prCleanupDeleteRemoteFlag, "delete-remote", false, "Also delete the pull request's head branch from the remote")
+	prCmd.AddCommand(prCleanupCmd)
+}
+
+// prCleanupTarget is a worktree checked out for a merged pull request.
+type prCleanupTarget struct {
+	PR       github.PullRequest
+	Worktree git.Worktree
+}
+
+func runPRCleanup(cmd *cobra.Command, args []string, deps *Deps) error {
+	targets, failed, err := prCleanupTargets(deps, args)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 && failed == 0 {
+		_, err := fmt.Fprintln(cmd.ErrOrStderr(), "No worktrees of merged pull requests")
+		return err
+	}
+	remote, err := deps.Git.GetDefaultRemote(deps.Ctx, "origin")
+	if err != nil {
+		return fmt.Errorf("failed to get default remote: %w", err)
+	}
+
+	for _, t := range targets {
+		if err := cleanupPRWorktree(deps, remote, t); err != nil {
+			clog.Default().Error("failed to clean up pull request", "number", t.PR.Number, "path", t.Worktree.AbsolutePath, "error", err)
+			deps.Summary.Add(t.Worktree.AbsolutePath, "failed", err.Error())
+			failed++
+			continue
+		}
+		if dryRunFlag {
+			deps.Summary.Add(t.Worktree.AbsolutePath, "kept", "dry run")
+		} else {
+			deps.Summary.Add(t.Worktree.AbsolutePath, "removed", "")
+		}
+		if _, err := fmt.Fprintf(cmd.OutOrStdout(), "Cleaned up #%d\t%s\n", t.PR.Number, t.Worktree.AbsolutePath); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to clean up %d pull request worktree(s)", failed)
+	}
+	return nil
+}
+
+// prCleanupTargets returns the worktree of the merged pull request named by args, or with no args,
+// the worktrees grove created for pull requests that are now merged. Without args, pull requests that
+// cannot be looked up are logged and counted rather than returned as an error.
+func prCleanupTargets(deps *Deps, args []string) ([]prCleanupTarget, int, error) {
+	worktrees, err := deps.Git.ListWorktrees(deps.Ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	if len(args) == 1 {
+		pr, err := resolvePullRequest(deps, args[0])
+		if err != nil {
+			return nil, 0, err
+		}
+		if pr.State != github.PRStateMerged {
+			return nil, 0, fmt.Errorf("pull request #%d is not merged (%s)", pr.Number, pr.State)
+		}
+		paths, err := prWorktreePaths(deps, []github.PullRequest{pr})
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, wt := range worktrees {
+			if pathutil.Equal(wt.AbsolutePath, paths[pr.Number]) {
+				return []prCleanupTarget{{PR: pr, Worktree: wt}}, 0, nil
+			}
+		}
+		return nil, 0, fmt.Errorf("pull request #%d has no worktree", pr.Number)
+	}
+
+	st, err := loadWorktreeState(deps, worktrees)
+	if err != nil {
+		return nil, 0, err
+	}
+	var targets []prCleanupTarget
+	failed := 0
+	for _, wt := range worktrees {
+		n := st.Get(wt.AbsolutePath).PRNumber
+		if n == 0 {
+			continue
+		}
+		pr, err := deps.GitHub.GetPullRequest(deps.Ctx, n)
+		if err != nil {
+			clog.Default().Error("failed to get pull request", "number", n, "error", err)
+			deps.Summary.Add(wt.AbsolutePath, "failed", err.Error())
+			failed++
+			continue
+		}
+		if pr.State != github.PRStateMerged {
+			deps.Summary.Add(wt.AbsolutePath, "kept", "not merged")
+			continue
+		}
+		targets = append(targets, prCleanupTarget{PR: pr, Worktree: wt})
+	}
+	return targets, failed, nil
+}
+
+// cleanupPRWorktree removes a merged pull request's worktree and local branch and, with --delete-remote,
+// its head branch on the remote. Only failing to remove the worktree is an error; the branches are
+// best effort once the worktree is gone.
+func cleanupPRWorktree(deps *Deps, remote string, t prCleanupTarget) error {
+	path := t.Worktree.AbsolutePath
+	if t.Worktree.IsMain {
+		return errors.New("the main worktree cannot be cleaned up")
+	}
+	if pathutil.Equal(path, deps.WorktreeRoot) {
+		return errors.New("cannot clean up the current worktree; run grove pr cleanup from another worktree")
+	}
+
+	branch := worktreeBranchName(t.Worktree)
+	// a branch still at the merged head holds nothing the pull request does not, even after a squash merge
+	force := false
+	if branch != "" && t.PR.HeadSHA != "" {
+		sha, err := deps.Git.ResolveRef(deps.Ctx, branch)
+		force = err == nil && sha == t.PR.HeadSHA
+	}
+
+	if err := removePRWorktree(deps, path); err != nil {
+		return err
+	}
+
+	if branch != "" {
+		err := deps.Git.DeleteBranch(deps.Ctx, branch, force)
+		switch {
+		case errors.Is(err, git.ErrNotMerged):
+			clog.Default().Warn("kept branch with commits not in the pull request", "branch", branch)
+		case err != nil:
+			clog.Default().Warn("failed to delete branch", "branch", branch, "error", err)
+		}
+	}
+	if prCleanupDeleteRemoteFlag && !t.PR.FromFork && t.PR.BranchName != "" {
+		if err := deps.Git.DeleteRemoteBranch(deps.Ctx, remote, t.PR.BranchName); err != nil {
+			clog.Default().Warn("failed to delete remote branch", "remote", remote, "branch", t.PR.BranchName, "error", err)
+		}
+	}
+	return nil
+}
+
+// removePRWorktree removes the worktree at path and forgets grove's state for it. Only this holds the
+// worktree lock, so other grove commands are not kept waiting on gh lookups or pushes to the remote.
+func removePRWorktree(deps *Deps, path string) error {
+	unlock, err := lockWorktrees(deps)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := deps.Git.RemoveWorktree(deps.Ctx, path, false); err != nil {
+		return err
+	}
+	if !dryRunFlag {
+		if err := updateWorktreeState(deps, path, func(entry *state.Worktree) { *entry = state.Worktree{} }); err != nil {
+			clog.Default().WithPrefix("state").Warn("failed to forget worktree", "path", path, "error", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/git/fake"
+	"github.com/jmcampanini/grove-cli/internal/github"
+	"github.com/jmcampanini/grove-cli/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPRCleanupTestGit returns a repository with worktrees for pull request #11 (merged) and #12 (open).
+func newPRCleanupTestGit() *fake.Git {
+	merged := git.NewCommit("fff6666", "Fix bug", testNow, "bob")
+	return newTestGit().
+		AddBranch("fix-bug", merged).
+		AddWorktree("/ws/pr-11", "fix-bug").
+		AddRemoteRef("origin", "fix-bug", merged).
+		AddBranch("add-auth", git.NewCommit("eee5555", "Add auth", testNow, "alice")).
+		AddWorktree("/ws/pr-12", "add-auth")
+}
+
+func newPRCleanupTestDeps(t *testing.T, g *fake.Git) *Deps {
+	deps := newTestDeps(g)
+	deps.GitHub = &stubGitHub{prs: []github.PullRequest{
+		{BranchName: "fix-bug", HeadSHA: "fff6666", Number: 11, State: github.PRStateMerged},
+		{BranchName: "add-auth", HeadSHA: "eee5555", Number: 12, State: github.PRStateOpen},
+	}}
+	st := state.New()
+	st.Set("/ws/pr-11", state.Worktree{Origin: state.OriginPR, PRNumber: 11})
+	st.Set("/ws/pr-12", state.Worktree{Origin: state.OriginPR, PRNumber: 12})
+	require.NoError(t, deps.State.Save(st))
+	return deps
+}
+
+func TestRunPRCleanup(t *testing.T) {
+	tests := []struct {
+		name             string
+		args             []string
+		deleteRemote     bool
+		setup            func(g *fake.Git)
+		wantErr          string
+		wantBranches     []string
+		wantRemoteBranch bool
+	}{
+		{
+			name:             "all merged pull requests",
+			wantBranches:     []string{"add-auth", "main"},
+			wantRemoteBranch: true,
+		},
+		{
+			name:         "named pull request with --delete-remote",
+			args:         []string{"11"},
+			deleteRemote: true,
+			wantBranches: []string{"add-auth", "main"},
+		},
+		{
+			name: "branch with commits not in the pull request is kept",
+			setup: func(g *fake.Git) {
+				g.AddBranch("fix-bug", git.NewCommit("ddd4444", "Local work", testNow, "bob")).SetUnmerged("fix-bug")
+			},
+			wantBranches:     []string{"add-auth", "fix-bug", "main"},
+			wantRemoteBranch: true,
+		},
+		{
+			name:    "named pull request that is not merged",
+			args:    []string{"12"},
+			wantErr: "pull request #12 is not merged (OPEN)",
+		},
+		{
+			name:    "worktree with modified files",
+			setup:   func(g *fake.Git) { g.SetDirty("/ws/pr-11") },
+			wantErr: "failed to clean up 1 pull request worktree(s)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prCleanupDeleteRemoteFlag = tt.deleteRemote
+			t.Cleanup(func() { prCleanupDeleteRemoteFlag = false })
+			g := newPRCleanupTestGit()
+			if tt.setup != nil {
+				tt.setup(g)
+			}
+			deps := newPRCleanupTestDeps(t, g)
+			cmd, out := newTestCommand()
+			cmd.SetErr(&bytes.Buffer{})
+
+			err := runPRCleanup(cmd, tt.args, deps)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				worktrees, err := g.ListWorktrees(t.Context())
+				require.NoError(t, err)
+				assert.Len(t, worktrees, 3, "the worktrees are kept")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "Cleaned up #11\t/ws/pr-11\n", out.String())
+			worktrees, err := g.ListWorktrees(t.Context())
+			require.NoError(t, err)
+			paths := make([]string, 0, len(worktrees))
+			for _, wt := range worktrees {
+				paths = append(paths, wt.AbsolutePath)
+			}
+			assert.ElementsMatch(t, []string{"/ws/main", "/ws/pr-12"}, paths)
+			branches, err := g.ListLocalBranches(t.Context())
+			require.NoError(t, err)
+			names := make([]string, 0, len(branches))
+			for _, b := range branches {
+				names = append(names, b.Name)
+			}
+			assert.ElementsMatch(t, tt.wantBranches, names)
+			_, err = g.ResolveRef(t.Context(), "origin/fix-bug")
+			assert.Equal(t, tt.wantRemoteBranch, err == nil, "remote branch exists")
+			st, err := deps.State.Load()
+			require.NoError(t, err)
+			assert.NotContains(t, st.Worktrees, "/ws/pr-11")
+			assert.Equal(t, 12, st.Get("/ws/pr-12").PRNumber)
+		})
+	}
+}
+
+// lockCheckingGitHub fails the test if GitHub is asked about a pull request while the worktree lock is held.
+type lockCheckingGitHub struct {
+	*stubGitHub
+	lockFile string
+	t        *testing.T
+}
+
+func (l lockCheckingGitHub) GetPullRequest(ctx context.Context, prNum int) (github.PullRequest, error) {
+	assert.NoFileExists(l.t, l.lockFile, "the worktree lock is not held while GitHub is asked")
+	return l.stubGitHub.GetPullRequest(ctx, prNum)
+}
+
+func TestRunPRCleanup_Lock(t *testing.T) {
+	g := newPRCleanupTestGit()
+	deps := newPRCleanupTestDeps(t, g)
+	deps.LockDir = t.TempDir()
+	lockFile := filepath.Join(deps.LockDir, worktreeLockFile)
+	deps.GitHub = lockCheckingGitHub{stubGitHub: deps.GitHub.(*stubGitHub), lockFile: lockFile, t: t}
+	cmd, out := newTestCommand()
+	cmd.SetErr(&bytes.Buffer{})
+
+	require.NoError(t, runPRCleanup(cmd, nil, deps))
+
+	assert.Equal(t, "Cleaned up #11\t/ws/pr-11\n", out.String())
+	assert.NoFileExists(t, lockFile, "the lock is released")
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	prCreateFromClipboardFlag bool
+	prCreateUpdateFlag        bool
+)
+
+var prCreateCmd = &cobra.Command{
+	Use:   "create [<number|url|branch>]",
+	Short: "Create a worktree for a pull request",
+	Long: `Create fetches a pull request's head into a local branch and creates a worktree for it.
+
+The pull request can be given as a number, a URL (which must belong to the current
+repository), or the name of its head branch. With --from-clipboard, the first pull
+request URL or number on the clipboard is used.
+
+The branch and worktree names come from the [pr] branch_template and worktree_template config.
+If the branch is already checked out in a worktree, that worktree's path is printed instead.
+
+An existing local branch is used for the worktree. If it is behind the pull request's head
+commit, grove warns; use --update to reset the branch to the head first. A branch with
+commits that are not in the pull request is never reset: grove warns, and --update fails
+rather than drop them.
+
+Example:
+  grove pr create 123
+  grove pr create https://github.com/org/repo/pull/123
+  grove pr create fix/login-bug
+  grove pr create --from-clipboard
+  grove pr create 123 --update`,
+	Args:              prArgs(&prCreateFromClipboardFlag),
+	ValidArgsFunction: completeFirstArg(requirements{NeedsProvider: true, NeedsRepo: true}, completePRNumbers),
+	RunE:              withDeps(requirements{Mutating: true, NeedsProvider: true, NeedsRepo: true}, runPRCreate),
+}
+
+func init() {
+	prCreateCmd.Flags().BoolVar(&prCreateFromClipboardFlag, "from-clipboard", false, "Read the pull request number or URL from the clipboard")
+	prCreateCmd.Flags().BoolVar(&prCreateUpdateFlag, "update", false, "Reset an existing local branch to the pull request's head")
+	prCmd.AddCommand(prCreateCmd)
+}
+
+func runPRCreate(cmd *cobra.Command, args []string, deps *Deps) error {
+	arg, err := prArgument(deps, args, prCreateFromClipboardFlag)
+	if err != nil {
+		return err
+	}
+	pr, err := resolvePullRequest(deps, arg)
+	if err != nil {
+		return err
+	}
+
+	worktreePath, err := createPRWorktree(deps, pr, prCreateUpdateFlag)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), worktreePath)
+	return err
+}
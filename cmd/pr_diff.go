@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+)
+
+var (
+	prDiffNameOnlyFlag bool
+	prDiffPagerFlag    bool
+	prDiffStatFlag     bool
+)
+
+var prDiffCmd = &cobra.Command{
+	Use:   "diff <number|url|branch>",
+	Short: "Show a pull request's diff",
+	Long: `Diff prints the changes a pull request makes, as reported by GitHub.
+
+With --stat, prints a per-file summary of insertions and deletions instead.
+With --name-only, prints only the paths of the changed files.
+With --pager, pipes the output through git's pager (GIT_PAGER, core.pager, or PAGER).
+
+Example:
+  grove pr diff 123
+  grove pr diff 123 --stat
+  grove pr diff https://github.com/org/repo/pull/123 --pager`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeFirstArg(requirements{NeedsProvider: true, NeedsRepo: true}, completePRNumbers),
+	RunE:              withDeps(requirements{NeedsProvider: true, NeedsRepo: true}, runPRDiff),
+}
+
+func init() {
+	prDiffCmd.Flags().BoolVar(&prDiffNameOnlyFlag, "name-only", false, "Show only the names of changed files")
+	prDiffCmd.Flags().BoolVar(&prDiffStatFlag, "stat", false, "Show a per-file summary of changes")
+	prDiffCmd.Flags().BoolVar(&prDiffPagerFlag, "pager", false, "Pipe the output through git's pager")
+	prDiffCmd.MarkFlagsMutuallyExclusive("name-only", "stat")
+	prCmd.AddCommand(prDiffCmd)
+}
+
+func runPRDiff(cmd *cobra.Command, args []string, deps *Deps) error {
+	pr, err := resolvePullRequest(deps, args[0])
+	if err != nil {
+		return err
+	}
+
+	if !prDiffNameOnlyFlag && !prDiffStatFlag && !prDiffPagerFlag {
+		// a diff can be megabytes, so it is copied through as gh prints it
+		return deps.GitHub.WritePullRequestDiff(deps.Ctx, pr.Number, cmd.OutOrStdout())
+	}
+
+	var output string
+	if prDiffNameOnlyFlag {
+		files, err := deps.GitHub.ListPullRequestFiles(deps.Ctx, pr.Number)
+		if err != nil {
+			return err
+		}
+		output = strings.Join(files, "\n") + "\n"
+	} else {
+		patch, err := deps.GitHub.GetPullRequestDiff(deps.Ctx, pr.Number)
+		if err != nil {
+			return err
+		}
+		output = patch + "\n"
+		if prDiffStatFlag {
+			output = renderDiffStat(parseDiffStat(patch))
+		}
+	}
+
+	if prDiffPagerFlag {
+		pager, err := deps.Git.GetPager(deps.Ctx)
+		if err != nil {
+			return err
+		}
+		return runPager(pager, output, cmd.OutOrStdout())
+	}
+	_, err = fmt.Fprint(cmd.OutOrStdout(), output)
+	return err
+}
+
+// runPager writes content through a pager command line, run by the shell as git does.
+// A pager of "cat" or "" writes content to out directly. Like git, LESS defaults to FRX
+// so short output doesn't wait for a keypress.
+func runPager(pager, content string, out io.Writer) error {
+	if pager == "" || pager == "cat" {
+		_, err := fmt.Fprint(out, content)
+		return err
+	}
+
+	c := exec.Command("sh", "-c", pager)
+	c.Env = os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		c.Env = append(c.Env, "LESS=FRX")
+	}
+	c.Stdin = strings.NewReader(content)
+	c.Stdout = out
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("failed to run pager %q: %w", pager, err)
+	}
+	return nil
+}
+
+// maxExcerptLines caps a diff excerpt, so a single large hunk does not fill a preview pane.
+const maxExcerptLines = 100
+
+// excerptDiff returns the start of a unified diff: its first hunks with their file headers, at most
+// maxExcerptLines lines. The headers of a file whose hunks are all left out are dropped too.
+// truncated reports whether anything was left out.
+func excerptDiff(patch string, hunks int) (excerpt string, truncated bool) {
+	lines := strings.Split(strings.TrimRight(patch, "\n"), "\n")
+	seen, fileStart, fileShown := 0, 0, false
+	for i, line := range lines {
+		cut := -1
+		switch {
+		case i == maxExcerptLines:
+			cut = i
+		case strings.HasPrefix(line, "diff --git "):
+			fileStart, fileShown = i, false
+		case strings.HasPrefix(line, "@@"):
+			seen++
+			switch {
+			case seen <= hunks:
+				fileShown = true
+			case fileShown:
+				cut = i
+			default:
+				cut = fileStart
+			}
+		}
+		if cut >= 0 {
+			return strings.Join(lines[:cut], "\n") + "\n", true
+		}
+	}
+	return strings.Join(lines, "\n") + "\n", false
+}
+
+// colorizeDiff colors a unified diff as git does: file headers bold, hunk headers cyan,
+// added lines green, and removed lines red.
+func colorizeDiff(patch string) string {
+	bold := func(s string) string { return termenv.String(s).Bold().String() }
+	color := func(s, c string) string { return termenv.String(s).Foreground(termenv.ANSI.Color(c)).String() }
+
+	lines := strings.Split(patch, "\n")
+	inHunk := false
+	for i, line := range lines {
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "diff --git "):
+			inHunk = false
+			lines[i] = bold(line)
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+			lines[i] = color(line, "6")
+		case !inHunk:
+			lines[i] = bold(line)
+		case strings.HasPrefix(line, "+"):
+			lines[i] = color(line, "2")
+		case strings.HasPrefix(line, "-"):
+			lines[i] = color(line, "1")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fileDiffStat counts the lines a diff adds and removes in one file.
+type fileDiffStat struct {
+	Binary     bool
+	Deletions  int
+	Insertions int
+	Path       string
+}
+
+// parseDiffStat summarizes a unified diff (as from git diff or gh pr diff) per file, in diff order.
+func parseDiffStat(patch string) []fileDiffStat {
+	var stats []fileDiffStat
+	inHunk := false
+	for line := range strings.SplitSeq(patch, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			path := line[len("diff --git "):]
+			if _, after, ok := strings.Cut(path, " b/"); ok {
+				path = after
+			}
+			stats = append(stats, fileDiffStat{Path: path})
+			inHunk = false
+		case len(stats) == 0:
+			continue
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case !inHunk && strings.HasPrefix(line, "Binary files "):
+			stats[len(stats)-1].Binary = true
+		case inHunk && strings.HasPrefix(line, "+"):
+			stats[len(stats)-1].Insertions++
+		case inHunk && strings.HasPrefix(line, "-"):
+			stats[len(stats)-1].Deletions++
+		}
+	}
+	return stats
+}
+
+// maxStatBarWidth is the widest +/- bar renderDiffStat draws; larger changes are scaled down.
+const maxStatBarWidth = 50
+
+// renderDiffStat formats per-file stats like git diff --stat.
+func renderDiffStat(stats []fileDiffStat) string {
+	pathWidth, maxChanges, insertions, deletions := 0, 0, 0, 0
+	for _, s := range stats {
+		pathWidth = max(pathWidth, len(s.Path))
+		maxChanges = max(maxChanges, s.Insertions+s.Deletions)
+		insertions += s.Insertions
+		deletions += s.Deletions
+	}
+	countWidth := len(fmt.Sprint(maxChanges))
+
+	var b strings.Builder
+	for _, s := range stats {
+		if s.Binary {
+			fmt.Fprintf(&b, " %-*s | Bin\n", pathWidth, s.Path)
+			continue
+		}
+		plus, minus := s.Insertions, s.Deletions
+		if maxChanges > maxStatBarWidth {
+			plus = scaleStat(plus, maxChanges)
+			minus = scaleStat(minus, maxChanges)
+		}
+		fmt.Fprintf(&b, " %-*s | %*d %s%s\n", pathWidth, s.Path, countWidth, s.Insertions+s.Deletions,
+			strings.Repeat("+", plus), strings.Repeat("-", minus))
+	}
+	fmt.Fprintf(&b, " %d %s changed, %d %s(+), %d %s(-)\n",
+		len(stats), plural(len(stats), "file", "files"),
+		insertions, plural(insertions, "insertion", "insertions"),
+		deletions, plural(deletions, "deletion", "deletions"))
+	return b.String()
+}
+
+// scaleStat scales n to the bar width, keeping any nonzero count visible.
+func scaleStat(n, maxChanges int) int {
+	if n == 0 {
+		return 0
+	}
+	return max(1, n*maxStatBarWidth/maxChanges)
+}
+
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return singular
+	}
+	return pluralForm
+}
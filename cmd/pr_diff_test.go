@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPatch = `diff --git a/cmd/list.go b/cmd/list.go
+index 1111111..2222222 100644
+--- a/cmd/list.go
++++ b/cmd/list.go
+@@ -1,4 +1,5 @@
+ package cmd
+-import "fmt"
++import (
++	"fmt"
++)
+--- a/not-a-header
+diff --git a/logo.png b/logo.png
+index 3333333..4444444 100644
+Binary files a/logo.png and b/logo.png differ
+diff --git a/old.txt b/new.txt
+similarity index 90%
+rename from old.txt
+rename to new.txt
+@@ -1 +1 @@
+-old
++new`
+
+func TestParseDiffStat(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		want  []fileDiffStat
+	}{
+		{
+			name:  "modified, binary, and renamed files",
+			patch: testPatch,
+			want: []fileDiffStat{
+				{Deletions: 2, Insertions: 3, Path: "cmd/list.go"},
+				{Binary: true, Path: "logo.png"},
+				{Deletions: 1, Insertions: 1, Path: "new.txt"},
+			},
+		},
+		{name: "empty diff", patch: "", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseDiffStat(tt.patch))
+		})
+	}
+}
+
+func TestRenderDiffStat(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats []fileDiffStat
+		want  string
+	}{
+		{
+			name: "aligned columns",
+			stats: []fileDiffStat{
+				{Deletions: 2, Insertions: 3, Path: "cmd/list.go"},
+				{Binary: true, Path: "logo.png"},
+				{Insertions: 1, Path: "a.txt"},
+			},
+			want: " cmd/list.go | 5 +++--\n" +
+				" logo.png    | Bin\n" +
+				" a.txt       | 1 +\n" +
+				" 3 files changed, 4 insertions(+), 2 deletions(-)\n",
+		},
+		{
+			name:  "large changes are scaled",
+			stats: []fileDiffStat{{Deletions: 100, Insertions: 100, Path: "big.go"}, {Insertions: 1, Path: "small.go"}},
+			want: " big.go   | 200 " + strings.Repeat("+", 25) + strings.Repeat("-", 25) + "\n" +
+				" small.go |   1 +\n" +
+				" 2 files changed, 101 insertions(+), 100 deletions(-)\n",
+		},
+		{
+			name:  "singular summary",
+			stats: []fileDiffStat{{Deletions: 1, Insertions: 1, Path: "a.txt"}},
+			want:  " a.txt | 2 +-\n 1 file changed, 1 insertion(+), 1 deletion(-)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, renderDiffStat(tt.stats))
+		})
+	}
+}
+
+func TestRunPRDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		nameOnly bool
+		stat     bool
+		want     string
+	}{
+		{name: "full diff", want: testPatch + "\n"},
+		{name: "name only", nameOnly: true, want: "cmd/list.go\nlogo.png\nnew.txt\n"},
+		{name: "stat", stat: true, want: renderDiffStat(parseDiffStat(testPatch))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prDiffNameOnlyFlag, prDiffStatFlag = tt.nameOnly, tt.stat
+			t.Cleanup(func() { prDiffNameOnlyFlag, prDiffStatFlag = false, false })
+
+			deps := newTestDeps(newTestGit())
+			deps.GitHub = &stubGitHub{
+				diff:  testPatch,
+				files: []string{"cmd/list.go", "logo.png", "new.txt"},
+				prs:   []github.PullRequest{{Number: 12}},
+			}
+			cmd, out := newTestCommand()
+
+			err := runPRDiff(cmd, []string{"12"}, deps)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestRunPager(t *testing.T) {
+	tests := []struct {
+		name  string
+		pager string
+		want  string
+	}{
+		{name: "no pager", pager: "", want: "a\nb\n"},
+		{name: "cat", pager: "cat", want: "a\nb\n"},
+		{name: "shell command line", pager: "sed 's/^/> /'", want: "> a\n> b\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			require.NoError(t, runPager(tt.pager, "a\nb\n", &out))
+
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestExcerptDiff(t *testing.T) {
+	lines := strings.Split(testPatch, "\n")
+
+	tests := []struct {
+		name          string
+		patch         string
+		hunks         int
+		want          string
+		wantTruncated bool
+	}{
+		{name: "first hunk", patch: testPatch, hunks: 1, want: strings.Join(lines[:len(lines)-7], "\n") + "\n", wantTruncated: true},
+		{name: "every hunk", patch: testPatch, hunks: 2, want: testPatch + "\n"},
+		{name: "line cap", patch: strings.Repeat("+line\n", maxExcerptLines+5), hunks: 1, want: strings.Repeat("+line\n", maxExcerptLines), wantTruncated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, truncated := excerptDiff(tt.patch, tt.hunks)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantTruncated, truncated)
+		})
+	}
+}
+
+func TestColorizeDiff(t *testing.T) {
+	got := strings.Split(colorizeDiff(testPatch), "\n")
+
+	assert.Equal(t, "\x1b[1mdiff --git a/cmd/list.go b/cmd/list.go\x1b[0m", got[0])
+	assert.Equal(t, "\x1b[1m+++ b/cmd/list.go\x1b[0m", got[3])
+	assert.Equal(t, "\x1b[36m@@ -1,4 +1,5 @@\x1b[0m", got[4])
+	assert.Equal(t, " package cmd", got[5])
+	assert.Equal(t, "\x1b[31m-import \"fmt\"\x1b[0m", got[6])
+	assert.Equal(t, "\x1b[32m+import (\x1b[0m", got[7])
+	assert.Equal(t, "\x1b[31m--- a/not-a-header\x1b[0m", got[10], "a removed line inside a hunk is not a file header")
+}
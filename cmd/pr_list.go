@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jmcampanini/grove-cli/internal/config"
+	"github.com/jmcampanini/grove-cli/internal/github"
+	"github.com/jmcampanini/grove-cli/internal/porcelain"
+	"github.com/spf13/cobra"
+)
+
+var (
+	prListAssigneeFlag         string
+	prListAuthorFlag           string
+	prListClosedWithinFlag     int
+	prListFilterFlag           string
+	prListFzfFlag              bool
+	prListLabelFlag            []string
+	prListLimitFlag            int
+	prListMergedWithinFlag     int
+	prListMineFlag             bool
+	prListNoDefaultFiltersFlag bool
+	prListStateFlag            string
+	prListUpdatedWithinFlag    int
+	prListWebFlag              bool
+)
+
+// prListFilters maps --filter values to the review decision they select.
+var prListFilters = map[string]github.ReviewDecision{
+	"approved":          github.ReviewApproved,
+	"changes-requested": github.ReviewChangesRequested,
+	"review-required":   github.ReviewRequired,
+}
+
+var prListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List pull requests",
+	Long: `List pull requests for the current repository: open, non-draft ones by default.
+
+Use --state to list draft, merged, closed, or all pull requests instead, and narrow the
+results by date with --updated-within, --merged-within (with --state merged), or
+--closed-within (with --state closed), each a number of days. Use --filter to only list
+pull requests with a review decision: approved, changes-requested, or review-required.
+
+Filter by people and labels with --author, --assignee, --label (repeatable; all must match),
+or --mine for your own pull requests. The [pr] default_filters config adds GitHub search
+qualifiers to every query, e.g. default_filters = ["-author:app/dependabot"];
+--no-default-filters skips them.
+
+At most [pr] list_limit pull requests are listed (20 by default); --limit overrides it,
+and a limit of 0 pages through every matching pull request.
+
+By default, outputs a table. The CHECKS column summarizes CI status (✓ passing, ✗ failing,
+● pending, - none), the REVIEW column shows the review decision, and the WORKTREE column
+shows the local worktree for pull requests that are already checked out.
+With --fzf, outputs tab-separated format suitable for fzf:
+  <number>\t<display>
+With --porcelain, outputs one pull request per line in grove's versioned tab-separated
+format (see grove --help), with the columns:
+  number title author branch state checks review worktree updated url
+state, checks, and review are GitHub's values (e.g. OPEN, PASSING, APPROVED), empty when
+there are none, and updated is an RFC 3339 time.
+
+With --web, opens the repository's pull request list in your browser ($BROWSER, or the
+system's URL opener) instead, filtered by the same search.
+
+Example with fzf, where ctrl-o opens the highlighted pull request in the browser:
+  grove pr list --fzf | fzf --delimiter '\t' --with-nth 2 --accept-nth 1 \
+    --bind 'ctrl-o:execute-silent(grove pr preview --web {1})' | xargs grove pr create`,
+	Args: cobra.NoArgs,
+	RunE: withDeps(requirements{NeedsProvider: true, NeedsRepo: true, Porcelain: true}, runPRList),
+}
+
+func init() {
+	prListCmd.Flags().StringVar(&prListAssigneeFlag, "assignee", "", "Only list pull requests assigned to this user (@me for yourself)")
+	prListCmd.Flags().StringVar(&prListAuthorFlag, "author", "", "Only list pull requests by this user")
+	prListCmd.Flags().IntVar(&prListClosedWithinFlag, "closed-within", 0, "Only list pull requests closed within this many days (requires --state closed)")
+	prListCmd.Flags().StringVar(&prListFilterFlag, "filter", "", "Only list pull requests with this review decision: approved, changes-requested, review-required")
+	prListCmd.Flags().BoolVar(&prListFzfFlag, "fzf", false, "Output in fzf-compatible format")
+	prListCmd.Flags().StringSliceVar(&prListLabelFlag, "label", nil, "Only list pull requests with this label (repeatable)")
+	prListCmd.Flags().IntVar(&prListLimitFlag, "limit", 0, "Maximum number of pull requests to list, 0 for all (default from [pr] list_limit)")
+	prListCmd.Flags().IntVar(&prListMergedWithinFlag, "merged-within", 0, "Only list pull requests merged within this many days (requires --state merged)")
+	prListCmd.Flags().BoolVar(&prListMineFlag, "mine", false, "Only list your own pull requests (same as --author @me)")
+	prListCmd.Flags().BoolVar(&prListNoDefaultFiltersFlag, "no-default-filters", false, "Ignore the [pr] default_filters config")
+	prListCmd.Flags().StringVar(&prListStateFlag, "state", "open", "Pull request state: open, draft, merged, closed, or all")
+	prListCmd.Flags().IntVar(&prListUpdatedWithinFlag, "updated-within", 0, "Only list pull requests updated within this many days")
+	prListCmd.Flags().BoolVar(&prListWebFlag, "web", false, "Open the pull request list in the browser")
+	prListCmd.MarkFlagsMutuallyExclusive("author", "mine")
+	prListCmd.MarkFlagsMutuallyExclusive("fzf", "web")
+	prCmd.AddCommand(prListCmd)
+}
+
+// prListQuery builds the pull request query from the pr list flags and the [pr] config.
+func prListQuery(cfg config.PRConfig) (github.PRQuery, error) {
+	state, err := github.ParsePRState(prListStateFlag)
+	if err != nil {
+		return github.PRQuery{}, fmt.Errorf("invalid --state: %w", err)
+	}
+	query := github.PRQuery{
+		Assignee:          prListAssigneeFlag,
+		Author:            prListAuthorFlag,
+		ClosedWithinDays:  prListClosedWithinFlag,
+		Labels:            prListLabelFlag,
+		MergedWithinDays:  prListMergedWithinFlag,
+		State:             state,
+		UpdatedWithinDays: prListUpdatedWithinFlag,
+	}
+	if prListMineFlag {
+		query.Author = "@me"
+	}
+	if !prListNoDefaultFiltersFlag {
+		query.Qualifiers = cfg.DefaultFilters
+	}
+
+	windows := []struct {
+		days int
+		flag string
+	}{
+		{query.ClosedWithinDays, "closed-within"},
+		{query.MergedWithinDays, "merged-within"},
+		{query.UpdatedWithinDays, "updated-within"},
+	}
+	for _, w := range windows {
+		if w.days < 0 {
+			return github.PRQuery{}, fmt.Errorf("invalid --%s %d: must be a positive number of days", w.flag, w.days)
+		}
+	}
+	if query.MergedWithinDays > 0 && state != github.PRStateMerged {
+		return github.PRQuery{}, errors.New("--merged-within requires --state merged")
+	}
+	if query.ClosedWithinDays > 0 && state != github.PRStateClosed {
+		return github.PRQuery{}, errors.New("--closed-within requires --state closed")
+	}
+
+	if prListFilterFlag != "" {
+		review, ok := prListFilters[prListFilterFlag]
+		if !ok {
+			return github.PRQuery{}, fmt.Errorf("invalid --filter %q: must be approved, changes-requested, or review-required", prListFilterFlag)
+		}
+		query.Review = review
+	}
+	return query, nil
+}
+
+func runPRList(cmd *cobra.Command, _ []string, deps *Deps) error {
+	if porcelainFlag && prListFzfFlag {
+		return errors.New("--porcelain cannot be combined with --fzf")
+	}
+	query, err := prListQuery(deps.Config.PR)
+	if err != nil {
+		return err
+	}
+	query.Now = deps.Clock()
+
+	if prListWebFlag {
+		if porcelainFlag {
+			return errors.New("--porcelain cannot be combined with --web")
+		}
+		repo, err := deps.GitHub.GetRepository(deps.Ctx)
+		if err != nil {
+			return err
+		}
+		return openBrowser(deps, repo.PullRequestsURL(query.ToSearchQuery()))
+	}
+
+	limit := deps.Config.PR.ListLimit
+	if cmd.Flags().Changed("limit") {
+		if prListLimitFlag < 0 {
+			return errors.New("--limit cannot be negative")
+		}
+		limit = prListLimitFlag
+	}
+
+	prs, err := deps.GitHub.ListPullRequests(deps.Ctx, query, limit)
+	if err != nil {
+		return fmt.Errorf("failed to list pull requests: %w", err)
+	}
+
+	if porcelainFlag {
+		return writePRListPorcelain(cmd, deps, prs)
+	}
+
+	if prListFzfFlag {
+		for _, pr := range prs {
+			if _, err := fmt.Fprintln(cmd.OutOrStdout(), formatPRFzf(pr)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(prs) == 0 {
+		_, err := fmt.Fprintln(cmd.ErrOrStderr(), "No matching pull requests")
+		return err
+	}
+
+	worktrees, err := prWorktreePaths(deps, prs)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprint(cmd.OutOrStdout(), renderPRTable(prs, worktrees, deps.Clock(), useColor(cmd.OutOrStdout())))
+	return err
+}
+
+// writePRListPorcelain writes one --porcelain record per pull request.
+func writePRListPorcelain(cmd *cobra.Command, deps *Deps, prs []github.PullRequest) error {
+	worktrees, err := prWorktreePaths(deps, prs)
+	if err != nil {
+		return err
+	}
+	pw, err := porcelain.New(cmd.OutOrStdout(), "number", "title", "author", "branch", "state", "checks", "review", "worktree", "updated", "url")
+	if err != nil {
+		return err
+	}
+	for _, pr := range prs {
+		err := pw.Row(
+			strconv.Itoa(pr.Number),
+			pr.Title,
+			pr.AuthorLogin,
+			pr.BranchName,
+			string(pr.State),
+			string(pr.Checks),
+			string(pr.Review),
+			worktrees[pr.Number],
+			porcelain.Time(pr.UpdatedAt),
+			pr.URL,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
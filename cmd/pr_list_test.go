@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"io"
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/config"
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/github"
+	"github.com/jmcampanini/grove-cli/internal/porcelain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunPRList_Filter(t *testing.T) {
+	tests := []struct {
+		name       string
+		filter     string
+		wantReview github.ReviewDecision
+		wantErr    string
+	}{
+		{name: "no filter", wantReview: github.ReviewNone},
+		{name: "approved", filter: "approved", wantReview: github.ReviewApproved},
+		{name: "changes requested", filter: "changes-requested", wantReview: github.ReviewChangesRequested},
+		{name: "review required", filter: "review-required", wantReview: github.ReviewRequired},
+		{name: "invalid", filter: "merged", wantErr: `invalid --filter "merged"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prListFilterFlag, prListFzfFlag = tt.filter, true
+			t.Cleanup(func() { prListFilterFlag, prListFzfFlag = "", false })
+
+			gh := &stubGitHub{prs: []github.PullRequest{{BranchName: "b", Number: 1, Review: tt.wantReview, Title: "PR"}}}
+			deps := newTestDeps(newTestGit())
+			deps.GitHub = gh
+			cmd, out := newTestCommand()
+
+			err := runPRList(cmd, nil, deps)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantReview, gh.query.Review)
+			assert.Equal(t, github.PRStateOpen, gh.query.State)
+			assert.Equal(t, testNow, gh.query.Now)
+			assert.Equal(t, "1\t#1 PR b\n", out.String())
+		})
+	}
+}
+
+func TestPRListQuery(t *testing.T) {
+	tests := []struct {
+		name          string
+		state         string
+		closedWithin  int
+		mergedWithin  int
+		updatedWithin int
+		want          github.PRQuery
+		wantErr       string
+	}{
+		{name: "default", state: "open", want: github.PRQuery{State: github.PRStateOpen}},
+		{name: "merged within", state: "merged", mergedWithin: 30, want: github.PRQuery{MergedWithinDays: 30, State: github.PRStateMerged}},
+		{name: "closed within", state: "closed", closedWithin: 7, want: github.PRQuery{ClosedWithinDays: 7, State: github.PRStateClosed}},
+		{name: "all updated within", state: "all", updatedWithin: 14, want: github.PRQuery{State: github.PRStateAll, UpdatedWithinDays: 14}},
+		{name: "invalid state", state: "stale", wantErr: `invalid --state: invalid pull request state "stale"`},
+		{name: "merged within wrong state", state: "open", mergedWithin: 30, wantErr: "--merged-within requires --state merged"},
+		{name: "closed within wrong state", state: "merged", closedWithin: 7, wantErr: "--closed-within requires --state closed"},
+		{name: "negative window", state: "open", updatedWithin: -1, wantErr: "invalid --updated-within -1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prListStateFlag = tt.state
+			prListClosedWithinFlag, prListMergedWithinFlag, prListUpdatedWithinFlag = tt.closedWithin, tt.mergedWithin, tt.updatedWithin
+			t.Cleanup(func() {
+				prListStateFlag = "open"
+				prListClosedWithinFlag, prListMergedWithinFlag, prListUpdatedWithinFlag = 0, 0, 0
+			})
+
+			got, err := prListQuery(config.PRConfig{})
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPRListQuery_PeopleAndLabels(t *testing.T) {
+	defaults := config.PRConfig{DefaultFilters: []string{"-author:app/dependabot"}}
+
+	tests := []struct {
+		name             string
+		assignee         string
+		author           string
+		labels           []string
+		mine             bool
+		noDefaultFilters bool
+		want             github.PRQuery
+	}{
+		{
+			name: "default filters only",
+			want: github.PRQuery{Qualifiers: []string{"-author:app/dependabot"}, State: github.PRStateOpen},
+		},
+		{
+			name:     "author assignee and labels",
+			assignee: "@me",
+			author:   "octocat",
+			labels:   []string{"bug", "needs review"},
+			want: github.PRQuery{
+				Assignee:   "@me",
+				Author:     "octocat",
+				Labels:     []string{"bug", "needs review"},
+				Qualifiers: []string{"-author:app/dependabot"},
+				State:      github.PRStateOpen,
+			},
+		},
+		{
+			name:             "mine without default filters",
+			mine:             true,
+			noDefaultFilters: true,
+			want:             github.PRQuery{Author: "@me", State: github.PRStateOpen},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prListAssigneeFlag, prListAuthorFlag, prListLabelFlag = tt.assignee, tt.author, tt.labels
+			prListMineFlag, prListNoDefaultFiltersFlag = tt.mine, tt.noDefaultFilters
+			t.Cleanup(func() {
+				prListAssigneeFlag, prListAuthorFlag, prListLabelFlag = "", "", nil
+				prListMineFlag, prListNoDefaultFiltersFlag = false, false
+			})
+
+			got, err := prListQuery(defaults)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRunPRList_Limit(t *testing.T) {
+	tests := []struct {
+		name        string
+		configLimit int
+		flag        string // --limit value, empty when not given
+		wantLimit   int
+		wantErr     string
+	}{
+		{name: "config default", configLimit: 20, wantLimit: 20},
+		{name: "config all", configLimit: 0, wantLimit: 0},
+		{name: "flag overrides config", configLimit: 20, flag: "50", wantLimit: 50},
+		{name: "flag all", configLimit: 20, flag: "0", wantLimit: 0},
+		{name: "negative flag", configLimit: 20, flag: "-1", wantErr: "--limit cannot be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(func() { prListLimitFlag = 0 })
+			gh := &stubGitHub{}
+			deps := newTestDeps(newTestGit())
+			deps.Config.PR.ListLimit = tt.configLimit
+			deps.GitHub = gh
+			cmd, _ := newTestCommand()
+			cmd.SetErr(io.Discard)
+			cmd.Flags().IntVar(&prListLimitFlag, "limit", 0, "")
+			if tt.flag != "" {
+				require.NoError(t, cmd.Flags().Set("limit", tt.flag))
+			}
+
+			err := runPRList(cmd, nil, deps)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLimit, gh.limit)
+		})
+	}
+}
+
+func TestRunPRList_Porcelain(t *testing.T) {
+	porcelainFlag = true
+	t.Cleanup(func() { porcelainFlag = false })
+
+	g := newTestGit().
+		AddBranch("feature/auth", git.NewCommit("bbb2222", "Auth", testNow, "user")).
+		AddWorktree("/ws/wt-auth", "feature/auth")
+	deps := newTestDeps(g)
+	deps.GitHub = &stubGitHub{prs: []github.PullRequest{
+		{AuthorLogin: "alice", BranchName: "feature/auth", Checks: github.ChecksPassing, Number: 7, Review: github.ReviewApproved, State: github.PRStateOpen, Title: "Add\tauth", UpdatedAt: testNow, URL: "https://github.com/acme/widgets/pull/7"},
+		{BranchName: "fix", Number: 8, State: github.PRStateDraft, Title: "Fix"},
+	}}
+	cmd, out := newTestCommand()
+
+	require.NoError(t, runPRList(cmd, nil, deps))
+
+	assert.Equal(t, "#v1\tnumber\ttitle\tauthor\tbranch\tstate\tchecks\treview\tworktree\tupdated\turl\n"+
+		"7\tAdd\\tauth\talice\tfeature/auth\tOPEN\tPASSING\tAPPROVED\t/ws/wt-auth\t"+porcelain.Time(testNow)+"\thttps://github.com/acme/widgets/pull/7\n"+
+		"8\tFix\t\tfix\tDRAFT\t\t\t\t\t\n", out.String())
+}
+
+func TestRunPRList_Web(t *testing.T) {
+	t.Setenv("BROWSER", "xdg-open")
+	prListWebFlag, prListMineFlag, prListUpdatedWithinFlag = true, true, 7
+	t.Cleanup(func() { prListWebFlag, prListMineFlag, prListUpdatedWithinFlag = false, false, 0 })
+	gh := &stubGitHub{repo: github.Repository{Host: "github.com", Name: "widgets", Owner: "acme"}}
+	deps := newTestDeps(newTestGit())
+	deps.GitHub = gh
+	var calls [][]string
+	deps.Exec = recordExec(&calls)
+	cmd, out := newTestCommand()
+
+	require.NoError(t, runPRList(cmd, nil, deps))
+
+	assert.Equal(t, [][]string{{"xdg-open", "https://github.com/acme/widgets/pulls?q=is%3Apr+is%3Aopen+draft%3Afalse+author%3A%40me+updated%3A%3E%3D2024-05-25"}}, calls)
+	assert.Empty(t, out.String())
+	assert.Zero(t, gh.query, "pull requests are not listed")
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var prOpenWithFlag string
+
+var prOpenCmd = &cobra.Command{
+	Use:   "open <number|url|branch>",
+	Short: "Open a pull request's worktree, creating it if needed",
+	Long: `Open launches the configured open command (see grove open) for a pull request's worktree.
+
+If the pull request is not checked out yet, its worktree is created first, as with grove pr create.
+The pull request can be given as a number, URL, or head branch name.
+
+Example:
+  grove pr open 123
+  grove pr open 123 --with jetbrains`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeFirstArg(requirements{NeedsProvider: true, NeedsRepo: true}, completePRNumbers),
+	RunE:              withDeps(requirements{Mutating: true, NeedsProvider: true, NeedsRepo: true}, runPROpen),
+}
+
+func init() {
+	prOpenCmd.Flags().StringVar(&prOpenWithFlag, "with", "", "Preset or command template to open with (overrides [open] command)")
+	prCmd.AddCommand(prOpenCmd)
+}
+
+func runPROpen(_ *cobra.Command, args []string, deps *Deps) error {
+	pr, err := resolvePullRequest(deps, args[0])
+	if err != nil {
+		return err
+	}
+
+	worktreePath, err := createPRWorktree(deps, pr, false)
+	if err != nil {
+		return err
+	}
+
+	wt, err := resolveWorktree(deps, worktreePath)
+	if err != nil {
+		return fmt.Errorf("failed to find worktree for pull request #%d: %w", pr.Number, err)
+	}
+	return openWorktree(deps, wt, prOpenWithFlag)
+}
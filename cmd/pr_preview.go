@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	clog "github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	prPreviewFromClipboardFlag bool
+	prPreviewPlainFlag         bool
+	prPreviewURLFlag           bool
+	prPreviewWebFlag           bool
+	prPreviewWithDiffFlag      int
+)
+
+// defaultPreviewDiffHunks is the number of hunks --with-diff shows when no number is given.
+const defaultPreviewDiffHunks = 3
+
+var prPreviewCmd = &cobra.Command{
+	Use:   "preview [<number|url|branch>]",
+	Short: "Show details of a pull request",
+	Long: `Preview prints a pull request's metadata, description, and changed files.
+
+With --from-clipboard, the first pull request URL or number on the clipboard is used.
+
+The description is rendered as Markdown, wrapped to the terminal or fzf preview pane,
+and styled when colors are enabled (see grove --help). --plain prints it as written.
+
+With --with-diff, the first hunks of the diff follow (3 unless given, as in --with-diff=5),
+colored when the output is a terminal or an fzf preview pane. Run grove pr diff for the rest.
+
+With --web, the pull request is opened in your browser ($BROWSER, or the system's URL
+opener) instead, and with --url its URL is printed. Neither fetches the changed files.
+
+It is designed for fzf preview panes and key bindings:
+  grove pr list --fzf | fzf --delimiter '\t' --with-nth 2 --preview 'grove pr preview --with-diff {1}' \
+    --bind 'ctrl-o:execute-silent(grove pr preview --web {1})' \
+    --bind 'ctrl-y:execute-silent(grove pr preview --url {1} | pbcopy)'`,
+	Args:              prArgs(&prPreviewFromClipboardFlag),
+	ValidArgsFunction: completeFirstArg(requirements{NeedsProvider: true, NeedsRepo: true}, completePRNumbers),
+	RunE:              withDeps(requirements{NeedsProvider: true, NeedsRepo: true}, runPRPreview),
+}
+
+func init() {
+	prPreviewCmd.Flags().BoolVar(&prPreviewFromClipboardFlag, "from-clipboard", false, "Read the pull request number or URL from the clipboard")
+	prPreviewCmd.Flags().BoolVar(&prPreviewPlainFlag, "plain", false, "Print the description as written instead of rendering its Markdown")
+	prPreviewCmd.Flags().BoolVar(&prPreviewURLFlag, "url", false, "Print the pull request's URL instead of previewing it")
+	prPreviewCmd.Flags().BoolVar(&prPreviewWebFlag, "web", false, "Open the pull request in the browser instead of previewing it")
+	prPreviewCmd.Flags().IntVar(&prPreviewWithDiffFlag, "with-diff", 0, "Show the first hunks of the diff (3 if no number is given)")
+	prPreviewCmd.Flags().Lookup("with-diff").NoOptDefVal = strconv.Itoa(defaultPreviewDiffHunks)
+	prPreviewCmd.MarkFlagsMutuallyExclusive("url", "web")
+	prCmd.AddCommand(prPreviewCmd)
+}
+
+func runPRPreview(cmd *cobra.Command, args []string, deps *Deps) error {
+	arg, err := prArgument(deps, args, prPreviewFromClipboardFlag)
+	if err != nil {
+		return err
+	}
+	pr, err := resolvePullRequest(deps, arg)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case prPreviewURLFlag:
+		_, err := fmt.Fprintln(cmd.OutOrStdout(), pr.URL)
+		return err
+	case prPreviewWebFlag:
+		return openBrowser(deps, pr.URL)
+	}
+
+	files, err := deps.GitHub.ListPullRequestFiles(deps.Ctx, pr.Number)
+	if err != nil {
+		return err
+	}
+
+	var diff string
+	if prPreviewWithDiffFlag > 0 {
+		diff, err = previewDiff(deps, pr.Number, prPreviewWithDiffFlag, useColor(cmd.OutOrStdout()))
+		if err != nil {
+			return err
+		}
+	}
+
+	body := pr.Body
+	if !prPreviewPlainFlag && strings.TrimSpace(body) != "" {
+		out := cmd.OutOrStdout()
+		rendered, err := renderMarkdown(body, terminalWidth(out), useColor(out))
+		if err == nil {
+			body = rendered
+		} else {
+			clog.Default().Debug("showing the raw description", "error", err)
+		}
+	}
+
+	_, err = fmt.Fprint(cmd.OutOrStdout(), renderPRPreview(pr, body, files, diff, deps.Clock()))
+	return err
+}
+
+// previewDiff returns the first hunks of a pull request's diff, colored if color is set,
+// with a pointer to grove pr diff when the rest was cut.
+func previewDiff(deps *Deps, prNumber, hunks int, color bool) (string, error) {
+	patch, err := deps.GitHub.GetPullRequestDiff(deps.Ctx, prNumber)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(patch) == "" {
+		return "", nil
+	}
+	excerpt, truncated := excerptDiff(patch, hunks)
+	if color {
+		excerpt = colorizeDiff(excerpt)
+	}
+	if truncated {
+		excerpt += fmt.Sprintf("… run grove pr diff %d for the full diff\n", prNumber)
+	}
+	return excerpt, nil
+}
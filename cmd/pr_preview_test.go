@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunPRPreview_URLAndWeb(t *testing.T) {
+	const url = "https://github.com/acme/webapp/pull/42"
+
+	tests := []struct {
+		name      string
+		urlFlag   bool
+		webFlag   bool
+		wantCalls [][]string
+		wantOut   string
+	}{
+		{name: "url", urlFlag: true, wantOut: url + "\n"},
+		{name: "web", webFlag: true, wantCalls: [][]string{{"xdg-open", url}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BROWSER", "xdg-open")
+			prPreviewURLFlag = tt.urlFlag
+			prPreviewWebFlag = tt.webFlag
+			t.Cleanup(func() {
+				prPreviewURLFlag = false
+				prPreviewWebFlag = false
+			})
+			deps := newTestDeps(newTestGit())
+			deps.GitHub = &stubGitHub{prs: []github.PullRequest{{Number: 42, URL: url}}}
+			var calls [][]string
+			deps.Exec = recordExec(&calls)
+			cmd, out := newTestCommand()
+
+			require.NoError(t, runPRPreview(cmd, []string{"42"}, deps))
+
+			assert.Equal(t, tt.wantOut, out.String())
+			assert.Equal(t, tt.wantCalls, calls)
+		})
+	}
+}
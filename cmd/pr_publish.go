@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	clog "github.com/charmbracelet/log"
+	"github.com/jmcampanini/grove-cli/internal/github"
+	"github.com/spf13/cobra"
+)
+
+var (
+	prPublishBaseFlag  string
+	prPublishDraftFlag bool
+	prPublishTitleFlag string
+)
+
+var prPublishCmd = &cobra.Command{
+	Use:   "publish",
+	Short: "Push the current worktree's branch and open a pull request for it",
+	Long: `Publish pushes the current worktree's branch to the default remote, sets it as the branch's
+upstream, and opens a pull request for it with gh, printing the pull request's URL.
+
+The title is the subject of the branch's latest commit unless --title is given. The pull
+request merges into the repository's default branch unless --base is given. Use --draft to
+open it as a draft.
+
+If the branch already has an open pull request, publish only pushes the branch and prints
+that pull request's URL.
+
+Example:
+  grove pr publish
+  grove pr publish --draft --title "Add auth" --base release`,
+	Args: cobra.NoArgs,
+	RunE: withDeps(requirements{Mutating: true, NeedsProvider: true, NeedsRepo: true}, runPRPublish),
+}
+
+func init() {
+	prPublishCmd.Flags().StringVar(&prPublishBaseFlag, "base", "", "The branch to merge into (default: the repository's default branch)")
+	prPublishCmd.Flags().BoolVar(&prPublishDraftFlag, "draft", false, "Open the pull request as a draft")
+	prPublishCmd.Flags().StringVar(&prPublishTitleFlag, "title", "", "The pull request's title (default: the latest commit's subject)")
+	prCmd.AddCommand(prPublishCmd)
+}
+
+func runPRPublish(cmd *cobra.Command, _ []string, deps *Deps) error {
+	wt, err := resolveWorktree(deps, deps.WorktreeRoot)
+	if err != nil {
+		return err
+	}
+	branch := worktreeBranchName(wt)
+	if branch == "" || wt.BranchMissing {
+		return errors.New("the current worktree has no branch to publish; check out a branch first")
+	}
+
+	remote, err := deps.Git.GetDefaultRemote(deps.Ctx, "origin")
+	if err != nil {
+		return fmt.Errorf("failed to get default remote: %w", err)
+	}
+	if err := deps.Git.Push(deps.Ctx, remote, branch); err != nil {
+		return err
+	}
+
+	existing, err := deps.GitHub.GetPullRequestByBranch(deps.Ctx, branch)
+	if err != nil {
+		return err
+	}
+	if existing != nil && (existing.State == github.PRStateOpen || existing.State == github.PRStateDraft) {
+		if _, err := fmt.Fprintf(cmd.ErrOrStderr(), "Pushed %s; pull request #%d is already open\n", branch, existing.Number); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintln(cmd.OutOrStdout(), existing.URL)
+		return err
+	}
+
+	pr := github.NewPullRequest{
+		Base:  prPublishBaseFlag,
+		Draft: prPublishDraftFlag,
+		Head:  branch,
+		Title: prPublishTitleFlag,
+	}
+	if pr.Title == "" {
+		pr.Title = wt.Ref.Commit().Subject
+	}
+	if dryRunFlag {
+		clog.Default().Info("Would create pull request", "head", pr.Head, "base", pr.Base, "title", pr.Title, "draft", pr.Draft)
+		return nil
+	}
+	url, err := deps.GitHub.CreatePullRequest(deps.Ctx, pr)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), url)
+	return err
+}
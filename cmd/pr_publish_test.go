@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunPRPublish(t *testing.T) {
+	tests := []struct {
+		name        string
+		base        string
+		draft       bool
+		title       string
+		prs         []github.PullRequest
+		wantCreated []github.NewPullRequest
+		wantOutput  string
+	}{
+		{
+			name:        "title from the latest commit",
+			wantCreated: []github.NewPullRequest{{Head: "feature/auth", Title: "Add auth"}},
+			wantOutput:  "https://github.com/o/r/pull/101\n",
+		},
+		{
+			name:        "flags",
+			base:        "release",
+			draft:       true,
+			title:       "Auth for everyone",
+			wantCreated: []github.NewPullRequest{{Base: "release", Draft: true, Head: "feature/auth", Title: "Auth for everyone"}},
+			wantOutput:  "https://github.com/o/r/pull/101\n",
+		},
+		{
+			name:       "pull request already open",
+			prs:        []github.PullRequest{{BranchName: "feature/auth", Number: 7, State: github.PRStateOpen, URL: "https://github.com/o/r/pull/7"}},
+			wantOutput: "https://github.com/o/r/pull/7\n",
+		},
+		{
+			name:        "earlier pull request was merged",
+			prs:         []github.PullRequest{{BranchName: "feature/auth", Number: 7, State: github.PRStateMerged}},
+			wantCreated: []github.NewPullRequest{{Head: "feature/auth", Title: "Add auth"}},
+			wantOutput:  "https://github.com/o/r/pull/101\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prPublishBaseFlag, prPublishDraftFlag, prPublishTitleFlag = tt.base, tt.draft, tt.title
+			t.Cleanup(func() { prPublishBaseFlag, prPublishDraftFlag, prPublishTitleFlag = "", false, "" })
+			g := newTestGit().
+				AddBranch("feature/auth", git.NewCommit("bbb2222", "Add auth", testNow, "user")).
+				AddWorktree("/ws/auth", "feature/auth").
+				AddRemoteRef("origin", "main", git.NewCommit("abc1234def5678", "Initial", testNow, "user"))
+			deps := newTestDeps(g)
+			deps.WorktreeRoot = "/ws/auth"
+			gh := &stubGitHub{prs: tt.prs}
+			deps.GitHub = gh
+			cmd, out := newTestCommand()
+			cmd.SetErr(&bytes.Buffer{})
+
+			require.NoError(t, runPRPublish(cmd, nil, deps))
+
+			assert.Equal(t, tt.wantOutput, out.String())
+			assert.Equal(t, tt.wantCreated, gh.created)
+			sha, err := g.ResolveRef(t.Context(), "origin/feature/auth")
+			require.NoError(t, err)
+			assert.Equal(t, "bbb2222", sha, "the branch is pushed")
+		})
+	}
+}
+
+func TestRunPRPublish_DetachedHead(t *testing.T) {
+	g := newTestGit().AddDetachedWorktree("/ws/detached", git.NewCommit("bbb2222", "Work", testNow, "user"))
+	deps := newTestDeps(g)
+	deps.WorktreeRoot = "/ws/detached"
+	gh := &stubGitHub{}
+	deps.GitHub = gh
+	cmd, _ := newTestCommand()
+
+	err := runPRPublish(cmd, nil, deps)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no branch to publish")
+	assert.Empty(t, gh.created)
+}
@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/styles"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/jmcampanini/grove-cli/internal/github"
+	"github.com/jmcampanini/grove-cli/internal/textutil"
+	"github.com/muesli/termenv"
+)
+
+const (
+	prTitleMaxLen  = 60 // in terminal cells, see textutil.Width
+	prBranchMaxLen = 40
+)
+
+var tableHeaderStyle = lipgloss.NewStyle().Bold(true).Padding(0, 1)
+var tableCellStyle = lipgloss.NewStyle().Padding(0, 1)
+
+var prTableHeaders = []string{"#", "TITLE", "AUTHOR", "BRANCH", "CHECKS", "REVIEW", "WORKTREE", "UPDATED"}
+
+// renderPRTable renders pull requests as a table for terminal output: bordered and styled if styled is set,
+// and otherwise as plain columns aligned with spaces, for pipes and NO_COLOR.
+// worktrees maps PR numbers to the paths of their local worktrees.
+func renderPRTable(prs []github.PullRequest, worktrees map[int]string, now time.Time, styled bool) string {
+	rows := make([][]string, 0, len(prs))
+	for _, pr := range prs {
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", pr.Number),
+			textutil.Truncate(singleLine(pr.Title), prTitleMaxLen),
+			pr.AuthorLogin,
+			textutil.Truncate(pr.BranchName, prBranchMaxLen),
+			formatPRChecks(pr.Checks),
+			formatPRReview(pr.Review),
+			formatPRWorktree(worktrees[pr.Number]),
+			formatRelativeTime(pr.UpdatedAt, now),
+		})
+	}
+
+	return renderTable(prTableHeaders, rows, styled)
+}
+
+// renderTable renders headers and rows bordered and styled if styled is set, and otherwise as plain columns.
+func renderTable(headers []string, rows [][]string, styled bool) string {
+	if !styled {
+		return renderPlainTable(headers, rows)
+	}
+
+	t := table.New().
+		Border(lipgloss.NormalBorder()).
+		Headers(headers...).
+		Rows(rows...).
+		StyleFunc(func(row, _ int) lipgloss.Style {
+			if row == table.HeaderRow {
+				return tableHeaderStyle
+			}
+			return tableCellStyle
+		})
+
+	return t.String() + "\n"
+}
+
+// renderPlainTable renders headers and rows as columns separated by two spaces, without borders or styling.
+// Columns are aligned by display width, so rows with wide characters line up in a terminal.
+func renderPlainTable(headers []string, rows [][]string) string {
+	all := append([][]string{headers}, rows...)
+	widths := make([]int, len(headers))
+	for _, row := range all {
+		for i, cell := range row {
+			widths[i] = max(widths[i], textutil.Width(cell))
+		}
+	}
+
+	var b strings.Builder
+	for _, row := range all {
+		var line strings.Builder
+		for i, cell := range row {
+			if i == len(row)-1 {
+				line.WriteString(cell)
+				break
+			}
+			line.WriteString(textutil.PadRight(cell, widths[i]+2))
+		}
+		b.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+	}
+	return b.String()
+}
+
+// formatPRChecks returns a symbol for a PR's checks: ✓ passing, ✗ failing, ● pending, or "-" if there are none.
+func formatPRChecks(checks github.ChecksStatus) string {
+	switch checks {
+	case github.ChecksPassing:
+		return "✓"
+	case github.ChecksFailing:
+		return "✗"
+	case github.ChecksPending:
+		return "●"
+	default:
+		return "-"
+	}
+}
+
+// formatPRReview returns a short label for a PR's review decision, or "-" if there is none.
+func formatPRReview(review github.ReviewDecision) string {
+	switch review {
+	case github.ReviewApproved:
+		return "approved"
+	case github.ReviewChangesRequested:
+		return "changes requested"
+	case github.ReviewRequired:
+		return "review required"
+	default:
+		return "-"
+	}
+}
+
+// formatPRWorktree returns the directory name of a PR's worktree, or "-" if it has none.
+func formatPRWorktree(path string) string {
+	if path == "" {
+		return "-"
+	}
+	return filepath.Base(path)
+}
+
+// formatPRFzf formats a pull request as a "<number>\t<display>" line for fzf.
+func formatPRFzf(pr github.PullRequest) string {
+	return fmt.Sprintf("%d\t%s", pr.Number, formatPRDisplay(pr))
+}
+
+// formatPRDisplay returns a single-line description of a pull request.
+func formatPRDisplay(pr github.PullRequest) string {
+	display := fmt.Sprintf("#%d", pr.Number)
+	if pr.Checks != github.ChecksNone {
+		display += " " + formatPRChecks(pr.Checks)
+	}
+	display += " " + textutil.Truncate(singleLine(pr.Title), prTitleMaxLen)
+	if pr.AuthorLogin != "" {
+		display += " @" + pr.AuthorLogin
+	}
+	return display + " " + pr.BranchName
+}
+
+// renderPRPreview renders the details of a pull request for display in a preview pane, with body in place
+// of its description (see renderMarkdown), followed by diff (an excerpt of its changes) unless it is empty.
+func renderPRPreview(pr github.PullRequest, body string, files []string, diff string, now time.Time) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "#%d %s\n\n", pr.Number, singleLine(pr.Title))
+	fmt.Fprintf(&b, "State:   %s\n", pr.State)
+	if pr.Review != github.ReviewNone {
+		fmt.Fprintf(&b, "Review:  %s\n", formatPRReview(pr.Review))
+	}
+	fmt.Fprintf(&b, "Author:  %s\n", formatPRAuthor(pr))
+	fmt.Fprintf(&b, "Branch:  %s\n", pr.BranchName)
+	fmt.Fprintf(&b, "Changes: +%d -%d in %d files\n", pr.LinesAdded, pr.LinesDeleted, pr.FilesChanged)
+	fmt.Fprintf(&b, "Updated: %s\n", formatRelativeTime(pr.UpdatedAt, now))
+	fmt.Fprintf(&b, "URL:     %s\n", pr.URL)
+
+	if body := strings.TrimSpace(body); body != "" {
+		fmt.Fprintf(&b, "\n%s\n", body)
+	}
+
+	if len(files) > 0 {
+		b.WriteString("\nFiles:\n")
+		for _, f := range files {
+			fmt.Fprintf(&b, "  %s\n", f)
+		}
+	}
+
+	if diff != "" {
+		fmt.Fprintf(&b, "\nDiff:\n%s", diff)
+	}
+
+	return b.String()
+}
+
+// renderMarkdown renders Markdown for a terminal width columns wide: styled if color is set,
+// and otherwise with plain text decorations only. Trailing spaces are trimmed from each line.
+func renderMarkdown(markdown string, width int, color bool) (string, error) {
+	style := styles.NoTTYStyle
+	if color {
+		style = styles.DarkStyle
+	}
+	r, err := glamour.NewTermRenderer(
+		glamour.WithStandardStyle(style),
+		glamour.WithColorProfile(termenv.ANSI256),
+		glamour.WithWordWrap(width),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to create the Markdown renderer: %w", err)
+	}
+	out, err := r.Render(markdown)
+	if err != nil {
+		return "", fmt.Errorf("failed to render Markdown: %w", err)
+	}
+
+	lines := strings.Split(strings.Trim(out, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func formatPRAuthor(pr github.PullRequest) string {
+	switch {
+	case pr.AuthorLogin == "":
+		return "(unknown)"
+	case pr.AuthorName == "":
+		return "@" + pr.AuthorLogin
+	default:
+		return fmt.Sprintf("%s (@%s)", pr.AuthorName, pr.AuthorLogin)
+	}
+}
+
+// formatRelativeTime returns a short human readable age such as "5m ago" or "3d ago".
+func formatRelativeTime(t, now time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	case d < 365*24*time.Hour:
+		return fmt.Sprintf("%dmo ago", int(d.Hours()/(24*30)))
+	default:
+		return fmt.Sprintf("%dy ago", int(d.Hours()/(24*365)))
+	}
+}
+
+// singleLine collapses tabs and newlines so that s is safe for line and tab based output.
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmcampanini/grove-cli/internal/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatRelativeTime(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want string
+	}{
+		{name: "zero time", t: time.Time{}, want: "-"},
+		{name: "seconds", t: now.Add(-30 * time.Second), want: "just now"},
+		{name: "minutes", t: now.Add(-5 * time.Minute), want: "5m ago"},
+		{name: "hours", t: now.Add(-3 * time.Hour), want: "3h ago"},
+		{name: "days", t: now.Add(-50 * time.Hour), want: "2d ago"},
+		{name: "months", t: now.AddDate(0, -2, 0), want: "2mo ago"},
+		{name: "years", t: now.AddDate(-3, 0, 0), want: "3y ago"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatRelativeTime(tt.t, now))
+		})
+	}
+}
+
+func TestRenderPlainTable_WideCharacters(t *testing.T) {
+	got := renderPlainTable([]string{"#", "TITLE", "AUTHOR"}, [][]string{
+		{"1", "日本語のタイトル", "yuki"},
+		{"2", "Fix 🐛", "sam"},
+	})
+
+	assert.Equal(t, `#  TITLE             AUTHOR
+1  日本語のタイトル  yuki
+2  Fix 🐛            sam
+`, got)
+}
+
+func TestFormatPRFzf(t *testing.T) {
+	tests := []struct {
+		name string
+		pr   github.PullRequest
+		want string
+	}{
+		{
+			name: "with author",
+			pr:   github.PullRequest{AuthorLogin: "octocat", BranchName: "fix-bug", Number: 12, Title: "Fix bug"},
+			want: "12\t#12 Fix bug @octocat fix-bug",
+		},
+		{
+			name: "with checks",
+			pr:   github.PullRequest{AuthorLogin: "octocat", BranchName: "fix-bug", Checks: github.ChecksPending, Number: 12, Title: "Fix bug"},
+			want: "12\t#12 ● Fix bug @octocat fix-bug",
+		},
+		{
+			name: "deleted author",
+			pr:   github.PullRequest{BranchName: "orphan", Number: 3, Title: "Orphan"},
+			want: "3\t#3 Orphan orphan",
+		},
+		{
+			name: "long title is truncated",
+			pr:   github.PullRequest{AuthorLogin: "dev", BranchName: "b", Number: 5, Title: strings.Repeat("日本語", 12)},
+			want: "5\t#5 " + strings.Repeat("日本語", 9) + "日本… @dev b",
+		},
+		{
+			name: "title with tabs and newlines",
+			pr:   github.PullRequest{AuthorLogin: "dev", BranchName: "b", Number: 4, Title: "Multi\tline\ntitle"},
+			want: "4\t#4 Multi line title @dev b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatPRFzf(tt.pr))
+		})
+	}
+}
+
+func TestFormatPRChecks(t *testing.T) {
+	tests := []struct {
+		checks github.ChecksStatus
+		want   string
+	}{
+		{checks: github.ChecksPassing, want: "✓"},
+		{checks: github.ChecksFailing, want: "✗"},
+		{checks: github.ChecksPending, want: "●"},
+		{checks: github.ChecksNone, want: "-"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.checks), func(t *testing.T) {
+			assert.Equal(t, tt.want, formatPRChecks(tt.checks))
+		})
+	}
+}
+
+func TestRenderMarkdown_Color(t *testing.T) {
+	plain, err := renderMarkdown("# Title\n\nSome *emphasis*.", 40, false)
+	require.NoError(t, err)
+	assert.NotContains(t, plain, "\x1b[")
+
+	styled, err := renderMarkdown("# Title\n\nSome *emphasis*.", 40, true)
+	require.NoError(t, err)
+	assert.Contains(t, styled, "\x1b[")
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/github"
+	"github.com/jmcampanini/grove-cli/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var (
+	prSyncAllFlag    bool
+	prSyncRebaseFlag bool
+)
+
+var prSyncCmd = &cobra.Command{
+	Use:   "sync [<number|url|branch>|--all]",
+	Short: "Update pull request worktrees with the latest pushes",
+	Long: `Sync fetches a pull request's latest head and fast-forwards the branch in its worktree.
+With --all, every worktree grove created for a pull request is synced.
+
+If the local branch has commits the pull request does not (you committed in the worktree,
+or the pull request was force-pushed), the fast-forward fails; --rebase rebases the local
+branch onto the new head instead. A rebase that stops on conflicts is aborted, leaving the
+worktree as it was, and the conflicting files are reported. The conflict is recorded so
+grove conflicts --resolve can retry the rebase and open your merge tool once the batch is done.
+
+Exits with an error if any worktree could not be synced.
+
+Example:
+  grove pr sync 123
+  grove pr sync --all --rebase`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeFirstArg(requirements{NeedsProvider: true, NeedsRepo: true}, completePRNumbers),
+	RunE:              withDeps(requirements{EmitsEvents: true, Mutating: true, NeedsProvider: true, NeedsRepo: true, Notifies: true, Summary: true}, runPRSync),
+}
+
+func init() {
+	prSyncCmd.Flags().BoolVar(&prSyncAllFlag, "all", false, "Sync every pull request worktree")
+	prSyncCmd.Flags().BoolVar(&prSyncRebaseFlag, "rebase", false, "Rebase local commits onto the new head instead of fast-forwarding")
+	prCmd.AddCommand(prSyncCmd)
+}
+
+// prSyncTarget is a worktree checked out for a pull request.
+type prSyncTarget struct {
+	Number   int
+	Worktree git.Worktree
+}
+
+func runPRSync(cmd *cobra.Command, args []string, deps *Deps) error {
+	if prSyncAllFlag == (len(args) == 1) {
+		return errors.New("specify a pull request or --all")
+	}
+
+	targets, err := prSyncTargets(deps, args)
+	if err != nil {
+		return err
+	}
+	remote, err := deps.Git.GetDefaultRemote(deps.Ctx, "origin")
+	if err != nil {
+		return fmt.Errorf("failed to get default remote: %w", err)
+	}
+
+	if err := deps.Events.Started(len(targets)); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	failed := 0
+	for _, t := range targets {
+		status, detail := syncPRWorktree(deps, remote, t, prSyncRebaseFlag)
+		if status == checkFail {
+			failed++
+		} else {
+			setWorktreeConflict(deps, t.Worktree.AbsolutePath, state.Conflict{})
+		}
+		if err := reportItem(deps, t.Worktree.AbsolutePath, status.String(), detail); err != nil {
+			return err
+		}
+		if deps.Events == nil {
+			if _, err := fmt.Fprintf(w, "%s #%d\t%s\t%s\n", status.symbol(), t.Number, t.Worktree.AbsolutePath, detail); err != nil {
+				return err
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d pull request worktree(s) failed to sync", failed)
+	}
+	return nil
+}
+
+// prSyncTargets returns the worktree of the pull request named by args, or with --all,
+// every worktree grove recorded creating for a pull request.
+func prSyncTargets(deps *Deps, args []string) ([]prSyncTarget, error) {
+	worktrees, err := deps.Git.ListWorktrees(deps.Ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	if prSyncAllFlag {
+		st, err := loadWorktreeState(deps, worktrees)
+		if err != nil {
+			return nil, err
+		}
+		var targets []prSyncTarget
+		for _, wt := range worktrees {
+			if n := st.Get(wt.AbsolutePath).PRNumber; n > 0 {
+				targets = append(targets, prSyncTarget{Number: n, Worktree: wt})
+			}
+		}
+		if len(targets) == 0 {
+			return nil, errors.New("no pull request worktrees to sync; create one with grove pr create")
+		}
+		return targets, nil
+	}
+
+	pr, err := resolvePullRequest(deps, args[0])
+	if err != nil {
+		return nil, err
+	}
+	paths, err := prWorktreePaths(deps, []github.PullRequest{pr})
+	if err != nil {
+		return nil, err
+	}
+	for _, wt := range worktrees {
+		if wt.AbsolutePath == paths[pr.Number] {
+			return []prSyncTarget{{Number: pr.Number, Worktree: wt}}, nil
+		}
+	}
+	return nil, fmt.Errorf("pull request #%d has no worktree; create one with grove pr create %d", pr.Number, pr.Number)
+}
+
+// syncPRWorktree fetches the pull request's head and updates the worktree's branch to it.
+// It returns checkPass when the branch was updated or already current, and checkFail with the reason otherwise.
+func syncPRWorktree(deps *Deps, remote string, t prSyncTarget, rebase bool) (checkStatus, string) {
+	branch := worktreeBranchName(t.Worktree)
+	if branch == "" {
+		return checkFail, "detached HEAD; check out the pull request branch first"
+	}
+
+	head, err := deps.Git.FetchRef(deps.Ctx, remote, fmt.Sprintf("pull/%d/head", t.Number))
+	if err != nil {
+		return checkFail, err.Error()
+	}
+	if head == "" {
+		return checkPass, "would fetch and update " + branch // dry run
+	}
+	before, err := deps.Git.ResolveRef(deps.Ctx, branch)
+	if err != nil {
+		return checkFail, err.Error()
+	}
+	if before == head {
+		return checkPass, "already up to date"
+	}
+
+	if rebase {
+		err = deps.Git.Rebase(deps.Ctx, t.Worktree.AbsolutePath, head)
+	} else {
+		err = deps.Git.FastForward(deps.Ctx, t.Worktree.AbsolutePath, head)
+	}
+	var conflict *git.ConflictError
+	switch {
+	case errors.Is(err, git.ErrNotFastForward):
+		return checkFail, fmt.Sprintf("%s has local commits the pull request does not; rerun with --rebase", branch)
+	case errors.As(err, &conflict):
+		setWorktreeConflict(deps, t.Worktree.AbsolutePath, state.Conflict{At: deps.Clock().UTC(), Files: conflict.Files, Onto: head})
+		return checkFail, conflict.Error() + "; the rebase was aborted, run grove conflicts --resolve to retry it"
+	case err != nil:
+		return checkFail, err.Error()
+	case rebase:
+		return checkPass, "rebased onto " + shortSHASafe(head, 7)
+	default:
+		return checkPass, fmt.Sprintf("fast-forwarded %s..%s", shortSHASafe(before, 7), shortSHASafe(head, 7))
+	}
+}
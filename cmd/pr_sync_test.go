@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/git/fake"
+	"github.com/jmcampanini/grove-cli/internal/github"
+	"github.com/jmcampanini/grove-cli/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunPRSync(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		all        bool
+		rebase     bool
+		setup      func(g *fake.Git)
+		wantErr    string
+		wantOutput []string
+		wantHeads  map[string]string // branch -> SHA after syncing
+		// conflict recorded for /ws/pr-12 after syncing; a conflict from an earlier sync is
+		// recorded beforehand when priorConflict is set
+		priorConflict bool
+		wantConflict  state.Conflict
+	}{
+		{
+			name:       "fast-forward",
+			args:       []string{"12"},
+			wantOutput: []string{"✓ #12", "/ws/pr-12", "fast-forwarded aaa1111..ccc3333"},
+			wantHeads:  map[string]string{"pr-12": "ccc3333", "pr-13": "bbb2222"},
+		},
+		{
+			name:       "already up to date",
+			args:       []string{"13"},
+			wantOutput: []string{"✓ #13", "already up to date"},
+			wantHeads:  map[string]string{"pr-13": "bbb2222"},
+		},
+		{
+			name:       "all",
+			all:        true,
+			wantOutput: []string{"✓ #12", "fast-forwarded", "✓ #13", "already up to date"},
+			wantHeads:  map[string]string{"pr-12": "ccc3333", "pr-13": "bbb2222"},
+		},
+		{
+			name:       "diverged without rebase",
+			args:       []string{"12"},
+			setup:      func(g *fake.Git) { g.SetDiverged("/ws/pr-12") },
+			wantErr:    "1 pull request worktree(s) failed to sync",
+			wantOutput: []string{"✗ #12", "pr-12 has local commits the pull request does not; rerun with --rebase"},
+			wantHeads:  map[string]string{"pr-12": "aaa1111"},
+		},
+		{
+			name:       "diverged with rebase",
+			args:       []string{"12"},
+			rebase:     true,
+			setup:      func(g *fake.Git) { g.SetDiverged("/ws/pr-12") },
+			wantOutput: []string{"✓ #12", "rebased onto ccc3333"},
+			wantHeads:  map[string]string{"pr-12": "ccc3333"},
+		},
+		{
+			name:         "rebase conflicts",
+			args:         []string{"12"},
+			rebase:       true,
+			setup:        func(g *fake.Git) { g.SetRebaseConflicts("/ws/pr-12", "api.go", "api_test.go") },
+			wantErr:      "1 pull request worktree(s) failed to sync",
+			wantOutput:   []string{"✗ #12", "rebase stopped on conflicts in api.go, api_test.go; the rebase was aborted, run grove conflicts --resolve"},
+			wantHeads:    map[string]string{"pr-12": "aaa1111"},
+			wantConflict: state.Conflict{At: testNow.UTC(), Files: []string{"api.go", "api_test.go"}, Onto: "ccc3333"},
+		},
+		{
+			name:          "successful rebase forgets an earlier conflict",
+			args:          []string{"12"},
+			rebase:        true,
+			setup:         func(g *fake.Git) { g.SetDiverged("/ws/pr-12") },
+			priorConflict: true,
+			wantOutput:    []string{"✓ #12", "rebased onto ccc3333"},
+			wantHeads:     map[string]string{"pr-12": "ccc3333"},
+		},
+		{
+			name:    "pull request without a worktree",
+			args:    []string{"14"},
+			wantErr: "pull request #14 has no worktree; create one with grove pr create 14",
+		},
+		{
+			name:    "neither pull request nor --all",
+			wantErr: "specify a pull request or --all",
+		},
+		{
+			name:    "both pull request and --all",
+			args:    []string{"12"},
+			all:     true,
+			wantErr: "specify a pull request or --all",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prSyncAllFlag, prSyncRebaseFlag = tt.all, tt.rebase
+			t.Cleanup(func() { prSyncAllFlag, prSyncRebaseFlag = false, false })
+
+			g := newTestGit().
+				AddBranch("pr-12", git.NewCommit("aaa1111", "Old head", testNow, "user")).
+				AddBranch("pr-13", git.NewCommit("bbb2222", "Head", testNow, "user")).
+				AddWorktree("/ws/pr-12", "pr-12").
+				AddWorktree("/ws/pr-13", "pr-13").
+				AddRemoteRef("origin", "pull/12/head", git.NewCommit("ccc3333", "New push", testNow, "user")).
+				AddRemoteRef("origin", "pull/13/head", git.NewCommit("bbb2222", "Head", testNow, "user"))
+			if tt.setup != nil {
+				tt.setup(g)
+			}
+			deps := newTestDeps(g)
+			deps.GitHub = &stubGitHub{prs: []github.PullRequest{
+				{BranchName: "fix/a", Number: 12, Title: "A"},
+				{BranchName: "fix/b", Number: 13, Title: "B"},
+				{BranchName: "fix/c", Number: 14, Title: "C"},
+			}}
+			st := state.New()
+			pr12 := state.Worktree{Origin: state.OriginPR, PRNumber: 12}
+			if tt.priorConflict {
+				pr12.Conflict = state.Conflict{At: testNow, Files: []string{"api.go"}, Onto: "bbb0000"}
+			}
+			st.Set("/ws/pr-12", pr12)
+			st.Set("/ws/pr-13", state.Worktree{Origin: state.OriginPR, PRNumber: 13})
+			require.NoError(t, deps.State.Save(st))
+			cmd, out := newTestCommand()
+
+			err := runPRSync(cmd, tt.args, deps)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			for _, want := range tt.wantOutput {
+				assert.Contains(t, out.String(), want)
+			}
+			for branch, want := range tt.wantHeads {
+				sha, err := g.ResolveRef(t.Context(), branch)
+				require.NoError(t, err)
+				assert.Equal(t, want, sha, branch)
+			}
+			st, err = deps.State.Load()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantConflict, st.Get("/ws/pr-12").Conflict)
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/github"
+	"github.com/jmcampanini/grove-cli/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPRWorktreePaths(t *testing.T) {
+	commit := git.NewCommit("bbb2222", "Work", testNow, "user")
+	g := newTestGit().
+		AddBranch("renamed-locally", commit).
+		AddWorktree("/ws/review-7", "renamed-locally").
+		AddBranch("fix-bug", commit).
+		AddWorktree("/ws/wt-fix-bug", "fix-bug")
+	deps := newTestDeps(g)
+
+	st := state.New()
+	st.Set("/ws/review-7", state.Worktree{Origin: state.OriginPR, PRNumber: 7})
+	st.Set("/ws/pr-9", state.Worktree{Origin: state.OriginPR, PRNumber: 9}) // removed outside grove
+	require.NoError(t, deps.State.Save(st))
+
+	prs := []github.PullRequest{
+		{BranchName: "add-auth", Number: 7},
+		{BranchName: "fix-bug", Number: 8},
+		{BranchName: "gone", Number: 9},
+	}
+
+	got, err := prWorktreePaths(deps, prs)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[int]string{7: "/ws/review-7", 8: "/ws/wt-fix-bug"}, got)
+}
+
+func TestResolvePullRequest(t *testing.T) {
+	gh := &stubGitHub{
+		prs: []github.PullRequest{
+			{BranchName: "fix/login-bug", Number: 123},
+			{BranchName: "feature/search", Number: 7},
+		},
+		repo: github.Repository{Host: "github.com", Name: "repo", Owner: "org"},
+	}
+
+	tests := []struct {
+		name    string
+		arg     string
+		wantNum int
+		wantErr string
+	}{
+		{name: "number", arg: "123", wantNum: 123},
+		{name: "hash number", arg: "#7", wantNum: 7},
+		{name: "url", arg: "https://github.com/org/repo/pull/123", wantNum: 123},
+		{name: "url with different case", arg: "https://github.com/Org/Repo/pull/7/files", wantNum: 7},
+		{name: "head branch", arg: "feature/search", wantNum: 7},
+		{name: "url for another repository", arg: "https://github.com/fork/repo/pull/123", wantErr: "belongs to fork/repo, not the current repository org/repo"},
+		{name: "invalid url", arg: "https://github.com/org/repo/issues/1", wantErr: "invalid pull request URL"},
+		{name: "branch without pull request", arg: "nope", wantErr: `no pull request found for branch "nope"`},
+		{name: "empty", arg: " ", wantErr: "cannot be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := newTestDeps(newTestGit())
+			deps.GitHub = gh
+
+			got, err := resolvePullRequest(deps, tt.arg)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantNum, got.Number)
+		})
+	}
+}
+
+func TestPRArgument(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		clipboard     string
+		clipboardErr  error
+		fromClipboard bool
+		want          string
+		wantErr       string
+	}{
+		{name: "argument", args: []string{"42"}, want: "42"},
+		{name: "clipboard url", clipboard: "see https://github.com/org/repo/pull/123/files please", fromClipboard: true, want: "https://github.com/org/repo/pull/123"},
+		{name: "clipboard first url wins", clipboard: "https://github.com/org/repo/pull/1 https://github.com/org/repo/pull/2", fromClipboard: true, want: "https://github.com/org/repo/pull/1"},
+		{name: "clipboard number", clipboard: " #7\n", fromClipboard: true, want: "#7"},
+		{name: "clipboard without reference", clipboard: "hello world", fromClipboard: true, wantErr: "clipboard does not contain a pull request number or URL"},
+		{name: "clipboard issue url", clipboard: "https://github.com/org/repo/issues/1", fromClipboard: true, wantErr: "clipboard does not contain"},
+		{name: "clipboard error", clipboardErr: errors.New("no clipboard tool found"), fromClipboard: true, wantErr: "no clipboard tool found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := newTestDeps(newTestGit())
+			deps.Clipboard = func() (string, error) { return tt.clipboard, tt.clipboardErr }
+
+			got, err := prArgument(deps, tt.args, tt.fromClipboard)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCreatePRWorktree_TracksHeadBranch(t *testing.T) {
+	tests := []struct {
+		name         string
+		pr           github.PullRequest
+		wantUpstream string
+	}{
+		{
+			name:         "head branch on the remote",
+			pr:           github.PullRequest{BranchName: "fix-bug", Number: 11},
+			wantUpstream: "origin/fix-bug",
+		},
+		{
+			name: "pull request from a fork",
+			pr:   github.PullRequest{BranchName: "fix-bug", FromFork: true, Number: 11},
+		},
+		{
+			name: "head branch deleted from the remote",
+			pr:   github.PullRequest{BranchName: "gone", Number: 11},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commit := git.NewCommit("fff6666", "Fix bug", testNow, "bob")
+			g := newTestGit().
+				AddRemoteRef("origin", "pull/11/head", commit).
+				AddRemoteRef("origin", "fix-bug", commit)
+			deps := newTestDeps(g)
+
+			path, err := createPRWorktree(deps, tt.pr, false)
+
+			require.NoError(t, err)
+			assert.Equal(t, "/ws/pr-11", path)
+			branches, err := g.ListLocalBranches(t.Context())
+			require.NoError(t, err)
+			upstreams := map[string]string{}
+			for _, b := range branches {
+				upstreams[b.Name] = b.UpstreamName
+			}
+			require.Contains(t, upstreams, tt.pr.BranchName)
+			assert.Equal(t, tt.wantUpstream, upstreams[tt.pr.BranchName])
+		})
+	}
+}
+
+func TestCreatePRWorktree_ExistingBranch(t *testing.T) {
+	tests := []struct {
+		name    string
+		local   string
+		ahead   int
+		behind  int
+		update  bool
+		wantErr string
+		wantSHA string
+	}{
+		{name: "at the pull request's head", local: "fff6666", wantSHA: "fff6666"},
+		{name: "behind is used as is", local: "eee5555", behind: 2, wantSHA: "eee5555"},
+		{name: "behind with update is reset", local: "eee5555", behind: 2, update: true, wantSHA: "fff6666"},
+		{name: "ahead is used as is", local: "eee5555", ahead: 1, wantSHA: "eee5555"},
+		{name: "ahead with update is refused", local: "eee5555", ahead: 1, update: true, wantErr: "local branch fix-bug has 1 commit(s) that pull request #11 does not"},
+		{name: "diverged with update is refused", local: "eee5555", ahead: 1, behind: 2, update: true, wantErr: "push them or delete the branch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			head := git.NewCommit("fff6666", "Fix bug", testNow, "bob")
+			g := newTestGit().
+				AddBranch("fix-bug", git.NewCommit(tt.local, "Fix bug", testNow, "bob")).
+				AddRemoteRef("origin", "pull/11/head", head).
+				AddRemoteRef("origin", "fix-bug", head).
+				SetAheadBehind("fix-bug", "fff6666", tt.ahead, tt.behind)
+			deps := newTestDeps(g)
+			pr := github.PullRequest{BranchName: "fix-bug", HeadSHA: "fff6666", Number: 11}
+
+			path, err := createPRWorktree(deps, pr, tt.update)
+
+			sha, shaErr := g.ResolveRef(t.Context(), "fix-bug")
+			require.NoError(t, shaErr)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.Equal(t, tt.local, sha, "the branch is left alone")
+				return
+			}
+			require.NoError(t, err)
+			assertWorktreeOnBranch(t, g, path, "fix-bug")
+			assert.Equal(t, tt.wantSHA, sha)
+		})
+	}
+}
+
+func TestCreatePRWorktree_NestedName(t *testing.T) {
+	g := newTestGit().AddRemoteRef("origin", "pull/11/head", git.NewCommit("fff6666", "Fix bug", testNow, "bob"))
+	deps := newTestDeps(g)
+	deps.Config.PR.WorktreeTemplate = "reviews/{{.Number}}"
+
+	_, err := createPRWorktree(deps, github.PullRequest{BranchName: "fix-bug", Number: 11}, false)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `worktree name "reviews/11" is not a single directory name`)
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	clog "github.com/charmbracelet/log"
+	"github.com/jmcampanini/grove-cli/internal/pathutil"
+	"github.com/jmcampanini/grove-cli/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pruneIdleFlag string
+	pruneYesFlag  bool
+)
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune --idle <age>",
+	Short: "Remove worktrees you have not visited in a while",
+	Long: `Prune removes the managed worktrees that were not visited within --idle, such as 30d,
+2w, or 12h. A worktree is visited when grove open opens it or grove visit is run for it
+(see grove visit); a worktree grove created and never visited counts from its creation.
+
+The main worktree, the current worktree, pinned worktrees, foreign worktrees, and worktrees
+with no known visit are never pruned. Worktrees with modified or untracked files are not
+removed either: commit or remove those files first.
+
+Prune only removes the worktrees, never their branches, so their commits stay reachable
+and grove create or git worktree add can bring them back. Use grove archive to also remove
+a branch while keeping a copy.
+
+Grove asks before removing the worktrees when run in a terminal. Use --yes to remove them
+without asking; otherwise they are only listed.
+
+With --summary-file, each idle worktree is an item with status "removed", "kept" when it
+was only listed, or "failed".
+
+Example:
+  grove prune --idle 30d
+  grove prune --idle 2w --yes`,
+	Args: cobra.NoArgs,
+	RunE: withDeps(requirements{Mutating: true, NeedsRepo: true, Summary: true}, runPrune),
+}
+
+func init() {
+	pruneCmd.Flags().StringVar(&pruneIdleFlag, "idle", "", "Remove worktrees not visited within this age, e.g. 30d (required)")
+	pruneCmd.Flags().BoolVarP(&pruneYesFlag, "yes", "y", false, "Remove the idle worktrees without asking")
+	_ = pruneCmd.MarkFlagRequired("idle")
+	rootCmd.AddCommand(pruneCmd)
+}
+
+// idleWorktree is a worktree prune would remove, with when it was last visited.
+type idleWorktree struct {
+	Path    string
+	Visited time.Time
+}
+
+func runPrune(cmd *cobra.Command, _ []string, deps *Deps) error {
+	idle, err := parseAge(pruneIdleFlag)
+	if err != nil {
+		return fmt.Errorf("invalid --idle: %w", err)
+	}
+
+	unlock, err := lockWorktrees(deps)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	idleWorktrees, err := listIdleWorktrees(deps, deps.Clock().Add(-idle))
+	if err != nil {
+		return err
+	}
+	if len(idleWorktrees) == 0 {
+		_, err := fmt.Fprintf(cmd.ErrOrStderr(), "No worktrees idle for %s\n", pruneIdleFlag)
+		return err
+	}
+
+	now := deps.Clock()
+	for _, wt := range idleWorktrees {
+		if _, err := fmt.Fprintf(cmd.OutOrStdout(), "%s\tvisited %s\n", wt.Path, formatRelativeTime(wt.Visited, now)); err != nil {
+			return err
+		}
+	}
+
+	remove := pruneYesFlag
+	if !remove {
+		if !isTerminal(cmd.InOrStdin()) {
+			keepIdleWorktrees(deps, idleWorktrees, "not confirmed")
+			_, err := fmt.Fprintf(cmd.ErrOrStderr(), "Run grove prune --idle %s --yes to remove them\n", pruneIdleFlag)
+			return err
+		}
+		remove, err = confirm(cmd.InOrStdin(), cmd.ErrOrStderr(), fmt.Sprintf("Remove %d idle worktree(s)?", len(idleWorktrees)))
+		if err != nil || !remove {
+			keepIdleWorktrees(deps, idleWorktrees, "not confirmed")
+			return err
+		}
+	}
+
+	var removed, failed int
+	for _, wt := range idleWorktrees {
+		if err := deps.Git.RemoveWorktree(deps.Ctx, wt.Path, false); err != nil {
+			clog.Default().Error("failed to remove worktree", "path", wt.Path, "error", err)
+			deps.Summary.Add(wt.Path, "failed", err.Error())
+			failed++
+			continue
+		}
+		if dryRunFlag {
+			deps.Summary.Add(wt.Path, "kept", "dry run")
+			continue
+		}
+		if err := updateWorktreeState(deps, wt.Path, func(entry *state.Worktree) { *entry = state.Worktree{} }); err != nil {
+			clog.Default().WithPrefix("state").Warn("failed to forget worktree", "path", wt.Path, "error", err)
+		}
+		deps.Summary.Add(wt.Path, "removed", "")
+		removed++
+	}
+	if _, err := fmt.Fprintf(cmd.ErrOrStderr(), "Removed %d idle worktree(s)\n", removed); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to remove %d worktree(s)", failed)
+	}
+	return nil
+}
+
+// listIdleWorktrees returns the managed, unpinned linked worktrees last visited before cutoff, by path.
+// The current worktree and worktrees with no known visit are left out.
+func listIdleWorktrees(deps *Deps, cutoff time.Time) ([]idleWorktree, error) {
+	worktrees, err := deps.Git.ListWorktrees(deps.Ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	st, err := loadWorktreeState(deps, worktrees)
+	if err != nil {
+		return nil, err
+	}
+	var idle []idleWorktree
+	for _, wt := range worktrees {
+		entry := st.Get(wt.AbsolutePath)
+		visited := entry.LastVisited()
+		switch {
+		case wt.IsMain, wt.IsBare, entry.Pinned, visited.IsZero(), !visited.Before(cutoff):
+			continue
+		case pathutil.Equal(wt.AbsolutePath, deps.WorktreeRoot):
+			continue
+		case !worktreeManaged(wt, entry):
+			continue
+		}
+		idle = append(idle, idleWorktree{Path: wt.AbsolutePath, Visited: visited})
+	}
+	slices.SortFunc(idle, func(a, b idleWorktree) int { return strings.Compare(a.Path, b.Path) })
+	return idle, nil
+}
+
+// keepIdleWorktrees records in the summary that the idle worktrees were left in place.
+func keepIdleWorktrees(deps *Deps, idle []idleWorktree, reason string) {
+	for _, wt := range idle {
+		deps.Summary.Add(wt.Path, "kept", reason)
+	}
+}
+
+// parseAge parses an age such as "30d" or "2w", or any time.ParseDuration value such as "12h".
+func parseAge(s string) (time.Duration, error) {
+	var unit time.Duration
+	switch {
+	case strings.HasSuffix(s, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(s, "w"):
+		unit = 7 * 24 * time.Hour
+	default:
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return 0, fmt.Errorf("%q is not an age such as 30d, 2w, or 12h", s)
+		}
+		return d, nil
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%q is not an age such as 30d, 2w, or 12h", s)
+	}
+	return time.Duration(n) * unit, nil
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/git/fake"
+	"github.com/jmcampanini/grove-cli/internal/state"
+	"github.com/jmcampanini/grove-cli/internal/summary"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPruneTestDeps returns deps for a repository with worktrees of every kind prune must tell apart.
+func newPruneTestDeps(t *testing.T) (*fake.Git, *Deps) {
+	t.Helper()
+	commit := git.NewCommit("bbb2222", "Work", testNow, "user")
+	g := newTestGit()
+	for _, name := range []string{"old", "created", "recent", "pinned", "unknown", "scratch", "current"} {
+		g.AddBranch("feature/"+name, commit)
+	}
+	g.AddWorktree("/ws/wt-old", "feature/old").
+		AddWorktree("/ws/wt-created", "feature/created").
+		AddWorktree("/ws/wt-recent", "feature/recent").
+		AddWorktree("/ws/wt-pinned", "feature/pinned").
+		AddWorktree("/ws/wt-unknown", "feature/unknown").
+		AddWorktree("/ws/scratch", "feature/scratch").
+		AddWorktree("/ws/wt-current", "feature/current")
+	deps := newTestDeps(g)
+	deps.WorktreeRoot = "/ws/wt-current"
+
+	idle := testNow.Add(-45 * 24 * time.Hour)
+	st := state.New()
+	st.Set("/ws/main", state.Worktree{VisitedAt: idle})
+	st.Set("/ws/wt-old", state.Worktree{CreatedAt: idle.Add(-time.Hour), Origin: state.OriginCreate, VisitedAt: idle})
+	st.Set("/ws/wt-created", state.Worktree{CreatedAt: testNow.Add(-60 * 24 * time.Hour), Origin: state.OriginCreate})
+	st.Set("/ws/wt-recent", state.Worktree{CreatedAt: idle, Origin: state.OriginCreate, VisitedAt: testNow.Add(-2 * time.Hour)})
+	st.Set("/ws/wt-pinned", state.Worktree{Pinned: true, VisitedAt: idle})
+	st.Set("/ws/scratch", state.Worktree{VisitedAt: idle})
+	st.Set("/ws/wt-current", state.Worktree{VisitedAt: idle})
+	require.NoError(t, deps.State.Save(st))
+	return g, deps
+}
+
+func TestRunPrune(t *testing.T) {
+	pruneIdleFlag, pruneYesFlag = "30d", true
+	t.Cleanup(func() { pruneIdleFlag, pruneYesFlag = "", false })
+	g, deps := newPruneTestDeps(t)
+	deps.Summary = summary.NewRecorder()
+	cmd, out := newTestCommand()
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+
+	require.NoError(t, runPrune(cmd, nil, deps))
+
+	assert.Equal(t, "/ws/wt-created\tvisited 2mo ago\n/ws/wt-old\tvisited 1mo ago\n", out.String())
+	assert.Equal(t, "Removed 2 idle worktree(s)\n", stderr.String())
+	worktrees, err := g.ListWorktrees(t.Context())
+	require.NoError(t, err)
+	var paths []string
+	for _, wt := range worktrees {
+		paths = append(paths, wt.AbsolutePath)
+	}
+	assert.NotContains(t, paths, "/ws/wt-old")
+	assert.NotContains(t, paths, "/ws/wt-created")
+	assert.Len(t, paths, 6)
+	exists, err := g.BranchExists(t.Context(), "feature/old", false)
+	require.NoError(t, err)
+	assert.True(t, exists, "the branch is kept")
+	st, err := deps.State.Load()
+	require.NoError(t, err)
+	assert.NotContains(t, st.Worktrees, "/ws/wt-old")
+	assert.Equal(t, []summary.Item{{Name: "/ws/wt-created", Status: "removed"}, {Name: "/ws/wt-old", Status: "removed"}},
+		deps.Summary.Summary("grove prune", nil).Items)
+}
+
+func TestRunPrune_Dirty(t *testing.T) {
+	pruneIdleFlag, pruneYesFlag = "30d", true
+	t.Cleanup(func() { pruneIdleFlag, pruneYesFlag = "", false })
+	g, deps := newPruneTestDeps(t)
+	g.SetDirty("/ws/wt-old")
+	cmd, _ := newTestCommand()
+	cmd.SetErr(&bytes.Buffer{})
+
+	err := runPrune(cmd, nil, deps)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to remove 1 worktree(s)")
+	worktrees, err := g.ListWorktrees(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, worktrees, 7, "only the clean idle worktree is removed")
+}
+
+func TestRunPrune_NotConfirmed(t *testing.T) {
+	pruneIdleFlag = "4w"
+	t.Cleanup(func() { pruneIdleFlag = "" })
+	g, deps := newPruneTestDeps(t)
+	cmd, out := newTestCommand()
+	cmd.SetIn(strings.NewReader("y\n"))
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+
+	require.NoError(t, runPrune(cmd, nil, deps))
+
+	assert.Contains(t, out.String(), "/ws/wt-old")
+	assert.Equal(t, "Run grove prune --idle 4w --yes to remove them\n", stderr.String())
+	worktrees, err := g.ListWorktrees(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, worktrees, 8)
+}
+
+func TestRunPrune_NothingIdle(t *testing.T) {
+	pruneIdleFlag = "90d"
+	t.Cleanup(func() { pruneIdleFlag = "" })
+	_, deps := newPruneTestDeps(t)
+	cmd, out := newTestCommand()
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+
+	require.NoError(t, runPrune(cmd, nil, deps))
+
+	assert.Empty(t, out.String())
+	assert.Equal(t, "No worktrees idle for 90d\n", stderr.String())
+}
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "30d", want: 30 * 24 * time.Hour},
+		{in: "2w", want: 14 * 24 * time.Hour},
+		{in: "12h", want: 12 * time.Hour},
+		{in: "1h30m", want: 90 * time.Minute},
+		{in: "", wantErr: true},
+		{in: "d", wantErr: true},
+		{in: "-3d", wantErr: true},
+		{in: "0h", wantErr: true},
+		{in: "month", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseAge(tt.in)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
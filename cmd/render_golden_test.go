@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/github"
+	"github.com/jmcampanini/grove-cli/internal/state"
+	"github.com/stretchr/testify/require"
+)
+
+// goldenPRs is a fixed set of pull requests covering the rendering edge cases.
+func goldenPRs() []github.PullRequest {
+	return []github.PullRequest{
+		{
+			AuthorLogin:  "octocat",
+			AuthorName:   "The Octocat",
+			Body:         "Adds login and logout endpoints.\n\n- session cookies\n- CSRF protection",
+			BranchName:   "feature/add-user-auth",
+			Checks:       github.ChecksPassing,
+			FilesChanged: 3,
+			LinesAdded:   120,
+			LinesDeleted: 14,
+			Number:       42,
+			Review:       github.ReviewApproved,
+			State:        github.PRStateOpen,
+			Title:        "Add user authentication",
+			UpdatedAt:    testNow.Add(-2 * time.Hour),
+			URL:          "https://github.com/acme/webapp/pull/42",
+		},
+		{
+			AuthorLogin: "a-contributor-with-a-long-name",
+			BranchName:  "fix/an-extremely-long-branch-name-that-needs-truncation",
+			Checks:      github.ChecksFailing,
+			Number:      7,
+			Review:      github.ReviewChangesRequested,
+			State:       github.PRStateDraft,
+			Title:       "Fix the flaky integration tests that fail when the network is slow and the moon is full",
+			UpdatedAt:   testNow.AddDate(0, 0, -3),
+			URL:         "https://github.com/acme/webapp/pull/7",
+		},
+		{
+			BranchName: "orphan",
+			Number:     1234,
+			State:      github.PRStateOpen,
+			Title:      "Tabs\tand\nnewlines",
+			URL:        "https://github.com/acme/webapp/pull/1234",
+		},
+	}
+}
+
+func TestGolden_PRTable(t *testing.T) {
+	assertGolden(t, "pr_table", renderPRTable(goldenPRs(), map[int]string{42: "/ws/pr-42"}, testNow, true))
+}
+
+func TestGolden_PRTablePlain(t *testing.T) {
+	assertGolden(t, "pr_table_plain", renderPRTable(goldenPRs(), map[int]string{42: "/ws/pr-42"}, testNow, false))
+}
+
+func TestGolden_PRFzf(t *testing.T) {
+	var b strings.Builder
+	for _, pr := range goldenPRs() {
+		b.WriteString(formatPRFzf(pr) + "\n")
+	}
+	assertGolden(t, "pr_fzf", b.String())
+}
+
+func TestGolden_PRPreview(t *testing.T) {
+	prs := goldenPRs()
+	assertGolden(t, "pr_preview", renderPRPreview(prs[0], prs[0].Body, []string{"auth/login.go", "auth/session.go", "auth/login_test.go"}, "", testNow))
+	assertGolden(t, "pr_preview_minimal", renderPRPreview(prs[2], prs[2].Body, nil, "", testNow))
+
+	deps := newTestDeps(newTestGit())
+	deps.GitHub = &stubGitHub{diff: testPatch}
+	diff, err := previewDiff(deps, prs[0].Number, 1, false)
+	require.NoError(t, err)
+	assertGolden(t, "pr_preview_diff", renderPRPreview(prs[0], prs[0].Body, []string{"cmd/list.go", "logo.png", "new.txt"}, diff, testNow))
+}
+
+func TestGolden_Markdown(t *testing.T) {
+	const body = "## Summary\n\nAdds **login** and `logout` endpoints.\n\n- session cookies\n- CSRF protection\n\n" +
+		"```go\nfunc Login() error {\n\treturn nil\n}\n```\n\nSee [the docs](https://example.com/auth) for a much longer explanation than fits."
+
+	got, err := renderMarkdown(body, 60, false)
+
+	require.NoError(t, err)
+	assertGolden(t, "markdown", got+"\n")
+}
+
+func TestGolden_ListFzf(t *testing.T) {
+	release := git.NewCommit("9c1d2e3f4a5b", "Release", testNow, "user")
+	g := newTestGit().
+		AddBranch("feature/add-auth", git.NewCommit("def5678", "Auth", testNow, "user")).
+		AddWorktree("/ws/wt-add-auth", "feature/add-auth").
+		AddBranch("spike", git.NewCommit("aaa1111", "Spike", testNow, "user")).
+		AddWorktree("/ws/spike", "spike").
+		AddTag(git.NewTag("v1.0.0", release, "", "", "", time.Time{})).
+		AddDetachedWorktree("/ws/wt-v1", release).
+		AddDetachedWorktree("/ws/wt-hotfix", git.NewCommit("bbb2222ccc", "Hotfix", testNow, "user")).
+		AddBranch("fix-bug", git.NewCommit("ccc3333", "Fix", testNow, "user")).
+		AddWorktree("/ws/pr-11", "fix-bug")
+
+	fzfFlag = true
+	t.Cleanup(func() { fzfFlag = false })
+
+	deps := newTestDeps(g)
+	st := state.New()
+	st.Set("/ws/spike", state.Worktree{Pinned: true})
+	st.Set("/ws/pr-11", state.Worktree{Origin: state.OriginPR, PRNumber: 11})
+	if err := deps.State.Save(st); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, out := newTestCommand()
+	if err := runList(cmd, nil, deps); err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "list_fzf", out.String())
+}
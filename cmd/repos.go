@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	clog "github.com/charmbracelet/log"
+	"github.com/jmcampanini/grove-cli/internal/pathutil"
+)
+
+// allReposFlag runs list and sync over every repository in [workspace] repos instead of the current one.
+var allReposFlag bool
+
+// workspaceRepo is one repository from [workspace] repos, opened for a command.
+type workspaceRepo struct {
+	Deps *Deps
+	Name string // the repository directory's name, used to label its output
+	Path string // absolute path, with ~ expanded
+}
+
+// forEachRepo calls fn for every repository in [workspace] repos, in the configured order.
+// A repository that cannot be opened or whose fn fails is logged and skipped, so one broken
+// repository does not hide the others; the returned error counts the failures.
+func forEachRepo(deps *Deps, fn func(repo workspaceRepo) error) error {
+	paths := deps.Config.Workspace.Repos
+	if len(paths) == 0 {
+		return errors.New("no repositories configured; add them to [workspace] repos")
+	}
+	if deps.OpenRepo == nil {
+		return errors.New("--all-repos is not available in --demo mode")
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get user home directory: %w", err)
+	}
+
+	failed := 0
+	for _, p := range paths {
+		// an interrupt stops the remaining repositories rather than failing each of them in turn
+		if err := deps.Ctx.Err(); err != nil {
+			return err
+		}
+		repo := workspaceRepo{Path: filepath.Clean(pathutil.ExpandHome(p, homeDir))}
+		repo.Name = filepath.Base(repo.Path)
+
+		repo.Deps, err = deps.OpenRepo(repo.Path)
+		if err == nil {
+			repo.Deps.Summary = deps.Summary.ForRepo(repo.Name)
+			err = fn(repo)
+		}
+		if err != nil {
+			clog.Default().Error("repository failed", "repo", repo.Name, "path", repo.Path, "error", err)
+			deps.Summary.ForRepo(repo.Name).Add(repo.Path, "fail", err.Error())
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d repositories failed", failed, len(paths))
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/git/fake"
+	"github.com/jmcampanini/grove-cli/internal/summary"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestWorkspaceDeps returns Deps for running outside a repository, with [workspace] repos set to paths.
+// OpenRepo opens the fake repository whose main worktree is paths[i]+"/main"; other paths fail to open.
+func newTestWorkspaceDeps(paths []string, repos map[string]*fake.Git) *Deps {
+	deps := newTestDeps(newTestGit())
+	deps.MainWorktreePath, deps.WorktreeRoot = "", ""
+	deps.Config.Workspace.Repos = paths
+	deps.OpenRepo = func(path string) (*Deps, error) {
+		g, ok := repos[path]
+		if !ok {
+			return nil, errors.New("grove must be run inside a git repository")
+		}
+		repoDeps := newTestDeps(g)
+		repoDeps.Cwd, repoDeps.MainWorktreePath, repoDeps.WorktreeRoot = path+"/main", path+"/main", path+"/main"
+		return repoDeps, nil
+	}
+	return deps
+}
+
+// newTestRepoGit returns a fake repository whose main worktree is dir+"/main".
+func newTestRepoGit(dir string) *fake.Git {
+	return fake.New(dir+"/main", git.NewCommit("abc1234def5678", "Initial", testNow, "user"))
+}
+
+func TestForEachRepo(t *testing.T) {
+	tests := []struct {
+		name      string
+		paths     []string
+		wantNames []string
+		wantErr   string
+	}{
+		{
+			name:      "every repository in order",
+			paths:     []string{"/code/web", "/code/api/"},
+			wantNames: []string{"web", "api"},
+		},
+		{
+			name:      "failing repository is skipped",
+			paths:     []string{"/code/api", "/code/missing", "/code/web"},
+			wantNames: []string{"api", "web"},
+			wantErr:   "1 of 3 repositories failed",
+		},
+		{
+			name:    "no repositories",
+			wantErr: "no repositories configured; add them to [workspace] repos",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := newTestWorkspaceDeps(tt.paths, map[string]*fake.Git{
+				"/code/api": newTestRepoGit("/code/api"),
+				"/code/web": newTestRepoGit("/code/web"),
+			})
+
+			var names []string
+			err := forEachRepo(deps, func(repo workspaceRepo) error {
+				assert.Equal(t, repo.Path+"/main", repo.Deps.MainWorktreePath)
+				names = append(names, repo.Name)
+				return nil
+			})
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantNames, names)
+		})
+	}
+}
+
+func TestForEachRepo_Summary(t *testing.T) {
+	deps := newTestWorkspaceDeps([]string{"/code/api", "/code/missing"}, map[string]*fake.Git{
+		"/code/api": newTestRepoGit("/code/api"),
+	})
+	deps.Summary = summary.NewRecorder()
+
+	err := forEachRepo(deps, func(repo workspaceRepo) error {
+		repo.Deps.Summary.Add("origin", "pass", "fetched")
+		return nil
+	})
+
+	require.Error(t, err)
+	items := deps.Summary.Summary("grove sync", err).Items
+	require.Len(t, items, 2)
+	assert.Equal(t, summary.Item{Message: "fetched", Name: "origin", Repo: "api", Status: "pass"}, items[0])
+	assert.Equal(t, "missing", items[1].Repo)
+	assert.Equal(t, "/code/missing", items[1].Name)
+	assert.Equal(t, "fail", items[1].Status)
+}
+
+func TestForEachRepo_Interrupted(t *testing.T) {
+	deps := newTestWorkspaceDeps([]string{"/code/api", "/code/web"}, map[string]*fake.Git{
+		"/code/api": newTestRepoGit("/code/api"),
+		"/code/web": newTestRepoGit("/code/web"),
+	})
+	ctx, cancel := context.WithCancel(t.Context())
+	deps.Ctx = ctx
+
+	var names []string
+	err := forEachRepo(deps, func(repo workspaceRepo) error {
+		names = append(names, repo.Name)
+		cancel()
+		return nil
+	})
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"api"}, names)
+}
+
+func TestForEachRepo_Demo(t *testing.T) {
+	deps := newTestDeps(newTestGit())
+	deps.Config.Workspace.Repos = []string{"/code/api"}
+
+	err := forEachRepo(deps, func(workspaceRepo) error { return nil })
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--demo")
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/naming"
+	"github.com/jmcampanini/grove-cli/internal/pathutil"
+)
+
+// resolveWorktree finds the worktree identified by target.
+// An exact match on the path, directory name, display name (prefix stripped), or branch name wins;
+// otherwise a unique prefix of a display name or branch name is accepted.
+func resolveWorktree(deps *Deps, target string) (git.Worktree, error) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return git.Worktree{}, errors.New("worktree name cannot be empty")
+	}
+
+	worktrees, err := deps.Git.ListWorktrees(deps.Ctx)
+	if err != nil {
+		return git.Worktree{}, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	namer, err := newWorktreeNamer(deps)
+	if err != nil {
+		return git.Worktree{}, err
+	}
+
+	targetPath := target
+	if !filepath.IsAbs(targetPath) {
+		targetPath = filepath.Join(deps.Cwd, target)
+	}
+
+	var exact, prefix []git.Worktree
+	for _, wt := range worktrees {
+		keys := worktreeMatchKeys(wt, namer)
+		if slices.Contains(keys, target) || pathutil.Equal(wt.AbsolutePath, targetPath) {
+			exact = append(exact, wt)
+			continue
+		}
+		for _, key := range keys {
+			if strings.HasPrefix(key, target) {
+				prefix = append(prefix, wt)
+				break
+			}
+		}
+	}
+
+	matches := exact
+	if len(matches) == 0 {
+		matches = prefix
+	}
+
+	switch len(matches) {
+	case 0:
+		return git.Worktree{}, fmt.Errorf("no worktree matches %q; run 'grove list' to see worktrees", target)
+	case 1:
+		return matches[0], nil
+	default:
+		paths := make([]string, 0, len(matches))
+		for _, wt := range matches {
+			paths = append(paths, wt.AbsolutePath)
+		}
+		sort.Strings(paths)
+		return git.Worktree{}, fmt.Errorf("%q matches multiple worktrees:\n  %s", target, strings.Join(paths, "\n  "))
+	}
+}
+
+// worktreeMatchKeys returns the names a worktree can be referred to by.
+func worktreeMatchKeys(wt git.Worktree, namer *naming.WorktreeNamer) []string {
+	keys := []string{filepath.Base(wt.AbsolutePath), worktreeName(wt, namer)}
+	if branch := worktreeBranchName(wt); branch != "" {
+		keys = append(keys, branch)
+	}
+	return keys
+}
+
+// worktreeName returns the worktree directory name with the configured prefix stripped.
+func worktreeName(wt git.Worktree, namer *naming.WorktreeNamer) string {
+	basename := filepath.Base(wt.AbsolutePath)
+	if namer.HasPrefix(basename) {
+		return namer.ExtractFromAbsolutePath(wt.AbsolutePath)
+	}
+	return basename
+}
+
+// worktreeBranchName returns the branch checked out in the worktree, or "" if none.
+func worktreeBranchName(wt git.Worktree) string {
+	if wt.Ref == nil {
+		return ""
+	}
+	if branch, ok := wt.Ref.FullBranch(); ok {
+		return branch.Name
+	}
+	return ""
+}
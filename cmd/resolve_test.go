@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveWorktree(t *testing.T) {
+	commit := git.NewCommit("bbb2222", "Work", testNow, "user")
+	g := newTestGit().
+		AddBranch("feature/add-auth", commit).
+		AddBranch("feature/add-audit", commit).
+		AddBranch("fix/login", commit).
+		AddWorktree("/ws/wt-add-auth", "feature/add-auth").
+		AddWorktree("/ws/wt-add-audit", "feature/add-audit").
+		AddWorktree("/ws/login-hotfix", "fix/login")
+	deps := newTestDeps(g)
+
+	tests := []struct {
+		name     string
+		target   string
+		wantPath string
+		wantErr  string
+	}{
+		{name: "display name", target: "add-auth", wantPath: "/ws/wt-add-auth"},
+		{name: "directory name", target: "wt-add-audit", wantPath: "/ws/wt-add-audit"},
+		{name: "branch name", target: "fix/login", wantPath: "/ws/login-hotfix"},
+		{name: "main branch", target: "main", wantPath: "/ws/main"},
+		{name: "absolute path", target: "/ws/login-hotfix", wantPath: "/ws/login-hotfix"},
+		{name: "relative path", target: "../wt-add-auth", wantPath: "/ws/wt-add-auth"},
+		{name: "unique prefix", target: "login", wantPath: "/ws/login-hotfix"},
+		{name: "ambiguous prefix", target: "add-au", wantErr: "matches multiple worktrees"},
+		{name: "no match", target: "nope", wantErr: `no worktree matches "nope"`},
+		{name: "empty", target: " ", wantErr: "worktree name cannot be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wt, err := resolveWorktree(deps, tt.target)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPath, wt.AbsolutePath)
+		})
+	}
+}
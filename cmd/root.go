@@ -1,6 +1,18 @@
 package cmd
 
-import "github.com/spf13/cobra"
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jmcampanini/grove-cli/internal/timing"
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+)
 
 // Version is set at build time via ldflags.
 var Version = "n/a"
@@ -8,14 +20,173 @@ var Version = "n/a"
 var rootCmd = &cobra.Command{
 	Use:   "grove",
 	Short: "Git worktree workspace manager",
-	Long:  `Grove manages git worktrees in a workspace structure.`,
+	Long: `Grove manages git worktrees in a workspace structure.
+
+Run grove cookbook for copy-pasteable workflow recipes.
+
+Run grove completion <shell> for a shell completion script (see grove completion --help),
+or grove completion install to write it where your shell loads it.
+Worktree names, base refs, and open pull request numbers complete as you type.
+
+Scripts should use --porcelain where a command supports it rather than parsing tables. Its
+first line is "#v1" followed by the column names and every other line is one record, all
+tab-separated; tabs, newlines, and backslashes in values are escaped as \t, \n, and \\.
+Within a version, columns are only ever added at the end.
+
+Commands with --json print an envelope: {"data": ..., "warnings": [...], "meta": {"version",
+"generated_at"}}. data is the command's result; warnings lists non-fatal issues (such as
+config files with unknown keys) as {"code", "message", "subject"} objects, and is empty
+when there were none.
+
+Batch commands accept --summary-file <path>, which writes the same envelope when the command
+ends, even when it fails: data is {"command", "status", "error", "counts", "items"}, where
+status is "ok" or "error", items lists each unit of work as {"name", "status", "message",
+"repo"} in the order it finished, and counts tallies the items per status. CI jobs can
+archive the file instead of parsing grove's output.
+
+Long batch commands (grove sync, grove clean, grove pr sync) can tell you when they finish,
+for when you switch away while a big fetch runs. Once one has run for [notifications]
+long_op_seconds, it rings the terminal bell when done, or with method = "desktop" shows a
+desktop notification (notify-send on Linux, osascript on macOS):
+
+  [notifications]
+  long_op_seconds = 30
+  method = "desktop"
+
+Run with no arguments, grove prints this help, or runs [ui] default_command: a grove command
+line such as "list --fzf" or "pr list", or the name of a plugin:
+
+  [ui]
+  default_command = "pr list"
+
+Logs go to stderr: --log-level picks the least severe messages shown (debug logs every git and
+gh command, like --verbose), and --log-format json writes one JSON object per line. Set
+GROVE_LOG_FILE to append the logs to that file instead.
+
+To see where a slow command spends its time, add --timings: when the command ends, grove prints
+to stderr how many times each git and gh command ran and how long those runs took, in total
+and at most. Nothing is sent anywhere.
+
+Tables are drawn with borders and styling only on a terminal. When stdout is piped, NO_COLOR
+is set, or --no-color is given, they are printed as plain aligned text instead.
+
+Plugins: any grove-<name> executable on PATH runs as grove <name>. Plugins receive
+GROVE_REPO_ROOT, GROVE_MAIN_WORKTREE_PATH, GROVE_WORKSPACE_PATH, and GROVE_CONFIG_PATHS
+in their environment.`,
+	PersistentPreRunE: func(*cobra.Command, []string) error {
+		if err := configureLogging(); err != nil {
+			return err
+		}
+		if timingsFlag {
+			timings = timing.NewRecorder(time.Now())
+		}
+		if noColorFlag {
+			lipgloss.SetColorProfile(termenv.Ascii)
+			logger.SetColorProfile(termenv.Ascii)
+		}
+		return nil
+	},
 }
 
+var (
+	cwdFlag         string
+	dryRunFlag      bool
+	jsonEventsFlag  bool
+	noColorFlag     bool
+	porcelainFlag   bool
+	setFlags        []string
+	summaryFileFlag string
+	timingsFlag     bool
+	verboseFlag     bool
+)
+
+// timings records the git and gh commands run, with --timings; nil otherwise.
+var timings *timing.Recorder
+
 func init() {
 	rootCmd.Version = Version
+	// set here rather than in rootCmd, since running a plugin looks up rootCmd's commands
+	rootCmd.RunE = runRoot
+	rootCmd.PersistentFlags().BoolVar(&dryRunFlag, "dry-run", false, "Show what would change without modifying the repository")
+	rootCmd.PersistentFlags().BoolVarP(&verboseFlag, "verbose", "v", false, "Log every git and gh command (same as --log-level debug)")
+	rootCmd.PersistentFlags().StringVar(&logLevelFlag, "log-level", "info", "Log messages at this level and above: "+strings.Join(logLevels, ", "))
+	rootCmd.PersistentFlags().StringVar(&logFormatFlag, "log-format", "text", "Log as "+strings.Join(logFormats, " or "))
+	rootCmd.PersistentFlags().StringVar(&cwdFlag, "cwd", "", "Run as if grove was started in this directory")
+	rootCmd.PersistentFlags().BoolVar(&jsonEventsFlag, "json-events", false, "Print newline-delimited JSON progress events on stdout instead of text (supported by doctor)")
+	rootCmd.PersistentFlags().BoolVar(&noColorFlag, "no-color", false, "Disable colors and table borders (also set by NO_COLOR or when stdout is not a terminal)")
+	rootCmd.PersistentFlags().BoolVar(&porcelainFlag, "porcelain", false, "Print stable, versioned tab-separated output for scripts (supported by list and pr list)")
+	rootCmd.PersistentFlags().StringArrayVar(&setFlags, "set", nil, "Override a config key for this run, e.g. --set slugify.max_length=30 (repeatable)")
+	rootCmd.PersistentFlags().BoolVar(&timingsFlag, "timings", false, "Print how long each git and gh command took when done")
+	rootCmd.PersistentFlags().StringVar(&summaryFileFlag, "summary-file", "", "Write a JSON summary of the outcome to this file when done (supported by check, clean, pr cleanup, pr sync, prune, and sync)")
 }
 
-// Execute runs the root command.
+// Execute runs the root command, or a grove-<name> plugin when the first argument is not a grove command.
+// An interrupt (Ctrl-C) cancels the context passed to git and gh, so running commands stop and grove exits.
+// With --timings, the breakdown of git and gh commands is printed once the command ends, even if it failed.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	defer closeLogFile()
+
+	if path, ok := findPlugin(os.Args[1:]); ok {
+		return runPlugin(ctx, path, os.Args[2:])
+	}
+	addCompletionInstallCmd(rootCmd)
+	err := rootCmd.ExecuteContext(ctx)
+	if timings != nil {
+		if writeErr := timings.Write(os.Stderr, time.Now()); writeErr != nil && err == nil {
+			err = writeErr
+		}
+	}
+	return err
+}
+
+// runRoot runs a bare grove invocation. Its Deps are only used to read [ui] default_command, so an invalid
+// config falls back to the default, which prints the help; the default command builds its own Deps and
+// checks its own flags when it runs.
+func runRoot(cmd *cobra.Command, args []string) error {
+	deps, err := newDeps(commandContext(cmd), requirements{AllowInvalidConfig: true})
+	if err != nil {
+		return err
+	}
+	return runDefaultCommand(cmd, args, deps)
+}
+
+// runDefaultCommand runs [ui] default_command for a bare grove invocation, or prints the help when it is empty.
+func runDefaultCommand(cmd *cobra.Command, _ []string, deps *Deps) error {
+	fields := strings.Fields(deps.Config.UI.DefaultCommand)
+	if len(fields) == 0 {
+		return cmd.Help()
+	}
+	if path, ok := findPlugin(fields); ok {
+		return runPlugin(deps.Ctx, path, fields[1:])
+	}
+	sub, args, err := findDefaultCommand(cmd, fields)
+	if err != nil {
+		return err
+	}
+	if sub.RunE == nil {
+		return sub.Help()
+	}
+	// cobra only passes the context to the command it executes, which is root
+	sub.SetContext(cmd.Context())
+	return sub.RunE(sub, args)
+}
+
+// findDefaultCommand resolves the fields of [ui] default_command to a grove subcommand of root,
+// parsing its flags and checking its arguments the way cobra does when the command line is typed.
+func findDefaultCommand(root *cobra.Command, fields []string) (*cobra.Command, []string, error) {
+	line := strings.Join(fields, " ")
+	sub, rest, err := root.Find(fields)
+	if err != nil || sub == root {
+		return nil, nil, fmt.Errorf("[ui] default_command %q is not a grove command", line)
+	}
+	if err := sub.ParseFlags(rest); err != nil {
+		return nil, nil, fmt.Errorf("[ui] default_command %q: %w", line, err)
+	}
+	args := sub.Flags().Args()
+	if err := sub.ValidateArgs(args); err != nil {
+		return nil, nil, fmt.Errorf("[ui] default_command %q: %w", line, err)
+	}
+	return sub, args, nil
 }
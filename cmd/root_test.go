@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunDefaultCommand_Help(t *testing.T) {
+	deps := newTestDeps(newTestGit())
+	cmd := &cobra.Command{Use: "grove", Long: "Grove manages git worktrees."}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	require.NoError(t, runDefaultCommand(cmd, nil, deps))
+
+	assert.Contains(t, out.String(), "Grove manages git worktrees.")
+}
+
+func TestRunDefaultCommand_PassesContext(t *testing.T) {
+	type ctxKey struct{}
+	var got any
+	root := &cobra.Command{Use: "grove"}
+	root.AddCommand(&cobra.Command{Use: "list", RunE: func(cmd *cobra.Command, _ []string) error {
+		got = cmd.Context().Value(ctxKey{})
+		return nil
+	}})
+	root.SetContext(context.WithValue(t.Context(), ctxKey{}, "root"))
+	deps := newTestDeps(newTestGit())
+	deps.Config.UI.DefaultCommand = "list"
+
+	require.NoError(t, runDefaultCommand(root, nil, deps))
+
+	assert.Equal(t, "root", got)
+}
+
+func TestFindDefaultCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		fields   []string
+		wantCmd  *cobra.Command
+		wantArgs []string
+		wantErr  string
+	}{
+		{name: "command", fields: []string{"list"}, wantCmd: listCmd, wantArgs: []string{}},
+		{name: "command with flags", fields: []string{"list", "--fzf", "--sort", "visited"}, wantCmd: listCmd, wantArgs: []string{}},
+		{name: "subcommand", fields: []string{"pr", "list"}, wantCmd: prListCmd, wantArgs: []string{}},
+		{name: "command with arguments", fields: []string{"open", "main"}, wantCmd: openCmd, wantArgs: []string{"main"}},
+		{name: "unknown command", fields: []string{"switch"}, wantErr: `[ui] default_command "switch" is not a grove command`},
+		{name: "unknown flag", fields: []string{"list", "--bogus"}, wantErr: "unknown flag: --bogus"},
+		{name: "missing argument", fields: []string{"open"}, wantErr: `[ui] default_command "open": accepts 1 arg(s)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(func() { fzfFlag, listSortFlag = false, listSortPath })
+
+			cmd, args, err := findDefaultCommand(rootCmd, tt.fields)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Same(t, tt.wantCmd, cmd)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jmcampanini/grove-cli/internal/shell"
+	"github.com/spf13/cobra"
+)
+
+var shellInitCmd = &cobra.Command{
+	Use:   "shell-init <shell>",
+	Short: "Generate shell integration functions and completions",
+	Long: `Shell-init outputs shell integration for your shell:
+
+  grc <phrase>  runs grove create and changes into the new worktree
+  grs           picks a worktree with fzf, most recently visited first, and changes into it
+
+It also registers tab completion for grove. Add to your shell config:
+  Fish:  grove shell-init fish | source
+  Zsh:   eval "$(grove shell-init zsh)"
+  Bash:  eval "$(grove shell-init bash)"
+
+The zsh completion needs compinit to have run first.`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"fish", "zsh", "bash"},
+	RunE:      runShellInit,
+}
+
+func init() {
+	rootCmd.AddCommand(shellInitCmd)
+}
+
+func runShellInit(cmd *cobra.Command, args []string) error {
+	shellName := args[0]
+	gen := shell.NewFunctionGenerator()
+
+	var output string
+	switch shellName {
+	case "fish":
+		output = gen.GenerateFish()
+	case "zsh":
+		output = gen.GenerateZsh()
+	case "bash":
+		output = gen.GenerateBash()
+	default:
+		return fmt.Errorf("unsupported shell: %s (supported: fish, zsh, bash)", shellName)
+	}
+
+	_, err := fmt.Fprint(cmd.OutOrStdout(), output)
+	return err
+}
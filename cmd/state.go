@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+
+	clog "github.com/charmbracelet/log"
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/state"
+)
+
+// updateWorktreeState applies update to the state entry for a worktree path and saves it.
+func updateWorktreeState(deps *Deps, path string, update func(*state.Worktree)) error {
+	st, err := deps.State.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	entry := st.Get(path)
+	update(&entry)
+	st.Set(path, entry)
+	if err := deps.State.Save(st); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	return nil
+}
+
+// moveWorktreeState carries grove's state for a worktree over to its new path.
+func moveWorktreeState(deps *Deps, oldPath, newPath string) error {
+	st, err := deps.State.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	st.Move(oldPath, newPath)
+	if err := deps.State.Save(st); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	return nil
+}
+
+// recordCreatedWorktree remembers that grove created the worktree at path.
+// The worktree already exists at this point, so a state failure is logged rather than returned.
+func recordCreatedWorktree(deps *Deps, path string, origin state.Origin, prNumber int) {
+	err := updateWorktreeState(deps, path, func(entry *state.Worktree) {
+		entry.CreatedAt = deps.Clock().UTC()
+		entry.Origin = origin
+		entry.PRNumber = prNumber
+	})
+	if err != nil {
+		clog.Default().WithPrefix("state").Warn("failed to record worktree", "path", path, "error", err)
+	}
+}
+
+// recordVisit remembers that the worktree at path was visited now, for list --sort visited and prune --idle.
+// Visiting is a side effect of the command that records it, so a state failure is logged rather than returned.
+func recordVisit(deps *Deps, path string) {
+	err := updateWorktreeState(deps, path, func(entry *state.Worktree) {
+		entry.VisitedAt = deps.Clock().UTC()
+	})
+	if err != nil {
+		clog.Default().WithPrefix("state").Warn("failed to record visit", "path", path, "error", err)
+	}
+}
+
+// setWorktreeConflict records the unresolved rebase of the worktree at path, or forgets it when conflict is zero.
+// The worktree was already updated or restored at this point, so a state failure is logged rather than returned.
+func setWorktreeConflict(deps *Deps, path string, conflict state.Conflict) {
+	err := updateWorktreeState(deps, path, func(entry *state.Worktree) {
+		entry.Conflict = conflict
+	})
+	if err != nil {
+		clog.Default().WithPrefix("state").Warn("failed to record rebase conflict", "path", path, "error", err)
+	}
+}
+
+// worktreeManaged reports whether grove manages a linked worktree: grove recorded creating it.
+// A worktree's name says nothing, since one made by hand can follow grove's naming. The main
+// worktree is never managed.
+func worktreeManaged(wt git.Worktree, entry state.Worktree) bool {
+	return !wt.IsMain && entry.Managed()
+}
+
+// loadWorktreeState loads the state, dropping entries for worktrees that no longer exist.
+func loadWorktreeState(deps *Deps, worktrees []git.Worktree) (state.State, error) {
+	st, err := deps.State.Load()
+	if err != nil {
+		return state.State{}, fmt.Errorf("failed to load state: %w", err)
+	}
+	paths := make([]string, len(worktrees))
+	for i, wt := range worktrees {
+		paths[i] = wt.AbsolutePath
+	}
+	st.Prune(paths)
+	return st, nil
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorktreeManaged(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		isMain bool
+		entry  state.Worktree
+		want   bool
+	}{
+		{name: "main worktree", path: "/ws/main", isMain: true, entry: state.Worktree{Origin: state.OriginPR}, want: false},
+		{name: "recorded by grove", path: "/ws/pr-11", entry: state.Worktree{Origin: state.OriginPR}, want: true},
+		{name: "recorded by grove init", path: "/ws/main", entry: state.Worktree{Origin: state.OriginInit, Pinned: true}, want: true},
+		{name: "prefixed but created by hand", path: "/ws/wt-bug", want: false},
+		{name: "created by hand", path: "/ws/scratch", want: false},
+		{name: "pinned but created by hand", path: "/ws/scratch", entry: state.Worktree{Pinned: true}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := worktreeManaged(git.Worktree{AbsolutePath: tt.path, IsMain: tt.isMain}, tt.entry)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
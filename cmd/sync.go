@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Fetch every remote and prune what was deleted",
+	Long: `Sync refreshes the workspace. It fetches every remote concurrently, pruning the remote
+branches and tags that were deleted, then prunes the worktrees whose directories are gone
+(git worktree prune). When stderr is a terminal, git's progress is shown there as each
+remote is fetched, prefixed by the remote's name.
+
+It then summarizes what changed: new remote branches, remote branches that were deleted
+(with the local branches that tracked them), and the worktrees that were pruned.
+
+With --all-repos, grove syncs every repository configured in [workspace] repos, one
+after another under a heading with its name and path, and may be run from anywhere.
+
+With --summary-file, each remote is an item with status "pass" or "fail"; with --all-repos,
+items carry the repository's name, and a repository that could not be synced is one more
+"fail" item.
+
+Exits with an error if any remote could not be fetched.`,
+	Args: cobra.NoArgs,
+	RunE: withDeps(requirements{EmitsEvents: true, Mutating: true, Notifies: true, Summary: true}, runSync),
+}
+
+func init() {
+	syncCmd.Flags().BoolVar(&allReposFlag, "all-repos", false, "Sync every repository in [workspace] repos")
+	rootCmd.AddCommand(syncCmd)
+}
+
+// syncSummary describes what a sync changed.
+type syncSummary struct {
+	DeletedBranches []string // remote branches deleted on the remote, e.g. "origin/fix (tracked by fix)"
+	NewBranches     []string // remote branches that appeared, e.g. "origin/feature"
+	Pruned          []string // worktrees whose administrative files were pruned, with the reason
+}
+
+func runSync(cmd *cobra.Command, _ []string, deps *Deps) error {
+	if allReposFlag {
+		if deps.Events != nil {
+			return errors.New("--json-events cannot be combined with --all-repos")
+		}
+		first := true
+		return forEachRepo(deps, func(repo workspaceRepo) error {
+			heading := fmt.Sprintf("%s (%s)", repo.Name, repo.Path)
+			if !first {
+				heading = "\n" + heading
+			}
+			first = false
+			if _, err := fmt.Fprintln(cmd.OutOrStdout(), heading); err != nil {
+				return err
+			}
+			return syncRepo(cmd, repo.Deps)
+		})
+	}
+	if deps.MainWorktreePath == "" {
+		return errNotInRepo
+	}
+	return syncRepo(cmd, deps)
+}
+
+// syncRepo fetches the remotes of one repository, prunes its worktrees, and prints what changed.
+func syncRepo(cmd *cobra.Command, deps *Deps) error {
+	remotes, err := deps.Git.ListRemotes(deps.Ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list remotes: %w", err)
+	}
+	if len(remotes) == 0 {
+		return errors.New("no remotes to sync; add one with git remote add")
+	}
+	before, err := listRemoteBranchNames(deps, remotes)
+	if err != nil {
+		return err
+	}
+	localBranches, err := deps.Git.ListLocalBranches(deps.Ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list local branches: %w", err)
+	}
+	worktrees, err := deps.Git.ListWorktrees(deps.Ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	if err := deps.Events.Started(len(remotes)); err != nil {
+		return err
+	}
+
+	fetchErrs := fetchRemotes(deps, remotes, fetchProgress(cmd, deps))
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	failed := 0
+	for i, remote := range remotes {
+		status, detail := checkPass, "fetched"
+		if fetchErrs[i] != nil {
+			status, detail = checkFail, fetchErrs[i].Error()
+			failed++
+		}
+		if err := reportItem(deps, remote, status.String(), detail); err != nil {
+			return err
+		}
+		if deps.Events == nil {
+			if _, err := fmt.Fprintf(w, "%s %s\t%s\n", status.symbol(), remote, detail); err != nil {
+				return err
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	var summary syncSummary
+	for _, wt := range worktrees {
+		if wt.Prunable != "" && !wt.IsMain {
+			summary.Pruned = append(summary.Pruned, fmt.Sprintf("%s (%s)", wt.AbsolutePath, wt.Prunable))
+		}
+	}
+	if len(summary.Pruned) > 0 {
+		if err := deps.Git.PruneWorktrees(deps.Ctx); err != nil {
+			return err
+		}
+	}
+
+	after, err := listRemoteBranchNames(deps, remotes)
+	if err != nil {
+		return err
+	}
+	summary.NewBranches, summary.DeletedBranches = diffRemoteBranches(before, after, localBranches)
+
+	if deps.Events == nil {
+		if err := printSyncSummary(cmd.OutOrStdout(), summary); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d remote(s) failed to fetch", failed)
+	}
+	return nil
+}
+
+// fetchRemotes fetches every remote at once and returns each remote's error, in the order of remotes.
+// If progress is not nil, it is called with each line of progress git reports, one call at a time.
+func fetchRemotes(deps *Deps, remotes []string, progress func(remote, line string)) []error {
+	errs := make([]error, len(remotes))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i, remote := range remotes {
+		var remoteProgress func(string)
+		if progress != nil {
+			remoteProgress = func(line string) {
+				mu.Lock()
+				defer mu.Unlock()
+				progress(remote, line)
+			}
+		}
+		wg.Go(func() {
+			_, errs[i] = deps.Git.FetchRemote(deps.Ctx, remote, remoteProgress)
+		})
+	}
+	wg.Wait()
+	return errs
+}
+
+// fetchProgress returns a callback that shows fetch progress on stderr, prefixed by the remote, or nil
+// when stderr is not a terminal or events are printed instead, so scripts see only the summary.
+func fetchProgress(cmd *cobra.Command, deps *Deps) func(remote, line string) {
+	stderr, ok := cmd.ErrOrStderr().(*os.File)
+	if !ok || !isTerminal(stderr) || deps.Events != nil {
+		return nil
+	}
+	return func(remote, line string) {
+		_, _ = fmt.Fprintf(stderr, "%s: %s\n", remote, strings.TrimSpace(line))
+	}
+}
+
+// listRemoteBranchNames returns the remote-tracking branches of the remotes, e.g. "origin/main".
+func listRemoteBranchNames(deps *Deps, remotes []string) (map[string]bool, error) {
+	names := map[string]bool{}
+	for _, remote := range remotes {
+		branches, err := deps.Git.ListRemoteBranches(deps.Ctx, remote)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list branches of %s: %w", remote, err)
+		}
+		for _, b := range branches {
+			names[b.RemoteName+"/"+b.Name] = true
+		}
+	}
+	return names, nil
+}
+
+// diffRemoteBranches returns the remote branches added and removed between before and after, sorted.
+// A removed branch notes the local branches that tracked it, since their upstream is now gone.
+func diffRemoteBranches(before, after map[string]bool, localBranches []git.LocalBranch) (added, removed []string) {
+	for name := range after {
+		if !before[name] {
+			added = append(added, name)
+		}
+	}
+	for name := range before {
+		if after[name] {
+			continue
+		}
+		var trackedBy []string
+		for _, b := range localBranches {
+			if b.UpstreamName == name {
+				trackedBy = append(trackedBy, b.Name)
+			}
+		}
+		if len(trackedBy) > 0 {
+			slices.Sort(trackedBy)
+			name = fmt.Sprintf("%s (tracked by %s)", name, strings.Join(trackedBy, ", "))
+		}
+		removed = append(removed, name)
+	}
+	slices.Sort(added)
+	slices.Sort(removed)
+	return added, removed
+}
+
+func printSyncSummary(out io.Writer, summary syncSummary) error {
+	prunedHeading := "Pruned worktrees:"
+	if dryRunFlag {
+		prunedHeading = "Would prune worktrees:"
+	}
+	sections := []struct {
+		heading string
+		items   []string
+	}{
+		{"New branches:", summary.NewBranches},
+		{"Deleted on remote:", summary.DeletedBranches},
+		{prunedHeading, summary.Pruned},
+	}
+
+	changed := false
+	for _, s := range sections {
+		if len(s.items) == 0 {
+			continue
+		}
+		changed = true
+		if _, err := fmt.Fprintln(out, s.heading); err != nil {
+			return err
+		}
+		for _, item := range s.items {
+			if _, err := fmt.Fprintf(out, "  %s\n", item); err != nil {
+				return err
+			}
+		}
+	}
+	if !changed {
+		_, err := fmt.Fprintln(out, "Already up to date")
+		return err
+	}
+	return nil
+}
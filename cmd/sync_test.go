@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/git/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSync(t *testing.T) {
+	tests := []struct {
+		name          string
+		setup         func(g *fake.Git)
+		wantOutput    []string
+		wantNotOutput []string
+		wantWorktrees int
+	}{
+		{
+			name:          "nothing changed",
+			wantOutput:    []string{"✓ origin", "fetched", "Already up to date"},
+			wantWorktrees: 2,
+		},
+		{
+			name: "new and deleted branches",
+			setup: func(g *fake.Git) {
+				g.PushRemoteRef("origin", "feature/new", git.NewCommit("ddd4444", "New", testNow, "user")).
+					DeleteRemoteRef("origin", "fix/old")
+			},
+			wantOutput: []string{
+				"New branches:\n  origin/feature/new\n",
+				"Deleted on remote:\n  origin/fix/old (tracked by fix/old)\n",
+			},
+			wantNotOutput: []string{"Already up to date", "Pruned worktrees:"},
+			wantWorktrees: 2,
+		},
+		{
+			name:          "orphaned worktree",
+			setup:         func(g *fake.Git) { g.SetPrunable("/ws/fix-old", "gitdir file points to non-existent location") },
+			wantOutput:    []string{"Pruned worktrees:\n  /ws/fix-old (gitdir file points to non-existent location)\n"},
+			wantWorktrees: 1,
+		},
+		{
+			name: "every remote is fetched",
+			setup: func(g *fake.Git) {
+				g.AddRemoteRef("upstream", "main", git.NewCommit("abc1234def5678", "Initial", testNow, "user")).
+					PushRemoteRef("upstream", "release", git.NewCommit("eee5555", "Release", testNow, "user"))
+			},
+			wantOutput:    []string{"✓ origin", "✓ upstream", "New branches:\n  upstream/release\n"},
+			wantWorktrees: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGit().
+				AddBranch("fix/old", git.NewCommit("bbb2222", "Fix", testNow, "user")).
+				SetUpstream("fix/old", "origin/fix/old", 0, 0).
+				AddWorktree("/ws/fix-old", "fix/old").
+				AddRemoteRef("origin", "main", git.NewCommit("abc1234def5678", "Initial", testNow, "user")).
+				AddRemoteRef("origin", "fix/old", git.NewCommit("bbb2222", "Fix", testNow, "user"))
+			if tt.setup != nil {
+				tt.setup(g)
+			}
+			deps := newTestDeps(g)
+			cmd, out := newTestCommand()
+
+			err := runSync(cmd, nil, deps)
+
+			require.NoError(t, err)
+			for _, want := range tt.wantOutput {
+				assert.Contains(t, out.String(), want)
+			}
+			for _, notWant := range tt.wantNotOutput {
+				assert.NotContains(t, out.String(), notWant)
+			}
+			worktrees, err := g.ListWorktrees(t.Context())
+			require.NoError(t, err)
+			assert.Len(t, worktrees, tt.wantWorktrees)
+		})
+	}
+}
+
+func TestRunSync_NoRemotes(t *testing.T) {
+	cmd, _ := newTestCommand()
+
+	err := runSync(cmd, nil, newTestDeps(newTestGit()))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no remotes to sync")
+}
+
+func TestRunSync_AllRepos(t *testing.T) {
+	api := newTestRepoGit("/code/api").
+		AddRemoteRef("origin", "main", git.NewCommit("abc1234def5678", "Initial", testNow, "user")).
+		PushRemoteRef("origin", "feature/new", git.NewCommit("ddd4444", "New", testNow, "user"))
+	web := newTestRepoGit("/code/web").
+		AddRemoteRef("origin", "main", git.NewCommit("abc1234def5678", "Initial", testNow, "user"))
+	deps := newTestWorkspaceDeps([]string{"/code/api", "/code/web"}, map[string]*fake.Git{"/code/api": api, "/code/web": web})
+	allReposFlag = true
+	t.Cleanup(func() { allReposFlag = false })
+	cmd, out := newTestCommand()
+
+	err := runSync(cmd, nil, deps)
+
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "api (/code/api)\n✓ origin")
+	assert.Contains(t, out.String(), "New branches:\n  origin/feature/new\n\nweb (/code/web)\n✓ origin")
+	assert.Contains(t, out.String(), "Already up to date")
+}
+
+func TestRunSync_NotInRepo(t *testing.T) {
+	cmd, _ := newTestCommand()
+
+	err := runSync(cmd, nil, newTestWorkspaceDeps(nil, nil))
+
+	require.ErrorIs(t, err, errNotInRepo)
+}
+
+func TestFetchRemotes_Progress(t *testing.T) {
+	g := newTestGit().
+		AddRemoteRef("origin", "main", git.NewCommit("abc1234def5678", "Initial", testNow, "user")).
+		AddRemoteRef("upstream", "main", git.NewCommit("abc1234def5678", "Initial", testNow, "user")).
+		PushRemoteRef("origin", "feature/new", git.NewCommit("ddd4444", "New", testNow, "user")).
+		PushRemoteRef("upstream", "release", git.NewCommit("eee5555", "Release", testNow, "user"))
+	deps := newTestDeps(g)
+	var lines []string
+
+	errs := fetchRemotes(deps, []string{"origin", "upstream"}, func(remote, line string) {
+		lines = append(lines, remote+": "+line)
+	})
+
+	assert.Equal(t, []error{nil, nil}, errs)
+	assert.ElementsMatch(t, []string{
+		"origin:  * [new branch]      feature/new -> origin/feature/new",
+		"upstream:  * [new branch]      release -> upstream/release",
+	}, lines)
+}
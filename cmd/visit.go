@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"github.com/jmcampanini/grove-cli/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var visitCmd = &cobra.Command{
+	Use:   "visit [name]",
+	Short: "Record that you entered a worktree",
+	Long: `Visit records that a worktree was just visited, for grove list --sort visited and
+grove prune --idle. Without a name it records the current worktree; otherwise the worktree
+is resolved the same way as grove open. It prints nothing.
+
+grove open records its visits itself, and the grs shell function (see grove shell-init)
+runs grove visit after switching to a worktree. To record every visit, run it from your
+shell's directory change hook, for example in zsh:
+
+  grove_visit() { grove visit &> /dev/null }
+  chpwd_functions+=(grove_visit)
+
+Example:
+  grove visit
+  grove visit add-auth`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeFirstArg(requirements{NeedsRepo: true}, completeWorktreeNames),
+	RunE:              withDeps(requirements{NeedsRepo: true}, runVisit),
+}
+
+func init() {
+	rootCmd.AddCommand(visitCmd)
+}
+
+func runVisit(_ *cobra.Command, args []string, deps *Deps) error {
+	path := deps.WorktreeRoot
+	if len(args) == 1 {
+		wt, err := resolveWorktree(deps, args[0])
+		if err != nil {
+			return err
+		}
+		path = wt.AbsolutePath
+	}
+	return updateWorktreeState(deps, path, func(entry *state.Worktree) {
+		entry.VisitedAt = deps.Clock().UTC()
+	})
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunVisit(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantPath string
+		wantErr  string
+	}{
+		{
+			name:     "current worktree",
+			wantPath: "/ws/main",
+		},
+		{
+			name:     "named worktree",
+			args:     []string{"auth"},
+			wantPath: "/ws/wt-auth",
+		},
+		{
+			name:    "unknown worktree",
+			args:    []string{"missing"},
+			wantErr: `no worktree matches "missing"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGit().
+				AddBranch("feature/auth", git.NewCommit("bbb2222", "Auth", testNow, "user")).
+				AddWorktree("/ws/wt-auth", "feature/auth")
+			deps := newTestDeps(g)
+			cmd, out := newTestCommand()
+
+			err := runVisit(cmd, tt.args, deps)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Empty(t, out.String())
+			st, err := deps.State.Load()
+			require.NoError(t, err)
+			assert.Equal(t, testNow, st.Get(tt.wantPath).VisitedAt)
+		})
+	}
+}
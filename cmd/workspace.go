@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	clog "github.com/charmbracelet/log"
+	"github.com/jmcampanini/grove-cli/internal/config"
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/jmcampanini/grove-cli/internal/lock"
+	"github.com/jmcampanini/grove-cli/internal/naming"
+	"github.com/jmcampanini/grove-cli/internal/pathutil"
+)
+
+// worktreeLockFile is the lock file in the grove state directory held while a worktree is created.
+const worktreeLockFile = "worktrees.lock"
+
+// worktreeLockTimeout is how long grove waits for another grove process to finish creating a worktree.
+var worktreeLockTimeout = 10 * time.Second
+
+// lockWorktrees takes the lock held from checking that a new worktree's branch and path are free until the
+// worktree is created and recorded, so two grove processes cannot both claim the same path.
+// The returned func releases it; nothing is locked when deps.LockDir is empty.
+func lockWorktrees(deps *Deps) (func(), error) {
+	if deps.LockDir == "" {
+		return func() {}, nil
+	}
+	l, err := lock.Acquire(filepath.Join(deps.LockDir, worktreeLockFile), worktreeLockTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		if err := l.Release(); err != nil {
+			clog.Default().Warn("failed to release worktree lock", "error", err)
+		}
+	}, nil
+}
+
+// worktreeParentDir returns the directory new worktrees are created in: the [worktree] root when set,
+// otherwise the workspace (the main worktree's parent directory).
+func worktreeParentDir(deps *Deps) (string, error) {
+	root := deps.Config.Worktree.Root
+	if root == "" {
+		workspacePath, err := deps.Git.GetWorkspacePath(deps.Ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to get workspace path: %w", err)
+		}
+		return workspacePath, nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return naming.RenderWorktreeRoot(root, naming.WorktreeRootData{RepoName: repoName(deps)}, homeDir)
+}
+
+// maxWorktreeSuffix is the highest number [worktree] on_collision = "suffix" tries before giving up.
+const maxWorktreeSuffix = 99
+
+// newWorktreePath returns the path of a new worktree named name in parentDir. It fails up front, rather than
+// with git's errors, when name is not a single directory name or parentDir is inside an existing worktree.
+// A path is taken when it exists or git has a worktree registered there. A taken path fails unless
+// [worktree] on_collision is "suffix", which appends -2, -3, and so on (joined with the [slugify] separator)
+// until the path is free.
+func newWorktreePath(deps *Deps, parentDir, name string) (string, error) {
+	worktrees, err := deps.Git.ListWorktrees(deps.Ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	if err := checkWorktreeLocation(parentDir, name, worktrees); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(parentDir, name)
+	if !worktreePathTaken(path, worktrees) {
+		return path, nil
+	}
+	if deps.Config.Worktree.OnCollision != config.CollisionSuffix {
+		return "", fmt.Errorf(`worktree path %q already exists; to remove it: git worktree remove %s, or set [worktree] on_collision = "suffix"`, path, name)
+	}
+	for n := 2; n <= maxWorktreeSuffix; n++ {
+		candidate := fmt.Sprintf("%s%s%d", path, deps.Config.Slugify.Separator, n)
+		if !worktreePathTaken(candidate, worktrees) {
+			clog.Default().Info("worktree path is taken; numbering the new worktree", "taken", path, "path", candidate)
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("worktree path %q and its numbered alternatives up to %d are taken", path, maxWorktreeSuffix)
+}
+
+// checkWorktreeLocation checks that a worktree named name would be a direct child of parentDir, and that
+// parentDir is not inside an existing worktree, where the new worktree would be nested in another's files.
+func checkWorktreeLocation(parentDir, name string, worktrees []git.Worktree) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("worktree name %q is not a single directory name; check the [worktree] and [pr] templates and the [slugify] settings", name)
+	}
+	for _, wt := range worktrees {
+		if pathutil.Within(parentDir, wt.AbsolutePath) {
+			return fmt.Errorf("cannot create a worktree in %q, which is inside the worktree %q; set [worktree] root to a directory outside of every worktree", parentDir, wt.AbsolutePath)
+		}
+	}
+	return nil
+}
+
+func worktreePathTaken(path string, worktrees []git.Worktree) bool {
+	if _, err := os.Lstat(path); err == nil {
+		return true
+	}
+	for _, wt := range worktrees {
+		if pathutil.Equal(wt.AbsolutePath, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// repoName returns the repository's name from the default remote's URL,
+// or the main worktree's directory name when there is no remote. A bare repository's name drops
+// its .git suffix, and one hidden in its workspace, like .bare, takes the workspace's name.
+func repoName(deps *Deps) string {
+	if remote, err := deps.Git.GetDefaultRemote(deps.Ctx, "origin"); err == nil {
+		if url, err := deps.Git.GetRemoteURL(deps.Ctx, remote); err == nil {
+			if name := naming.RepoNameFromURL(url); name != "" {
+				return name
+			}
+		}
+	}
+	name := strings.TrimSuffix(filepath.Base(deps.MainWorktreePath), ".git")
+	if name == "" || strings.HasPrefix(name, ".") {
+		name = filepath.Base(filepath.Dir(deps.MainWorktreePath))
+	}
+	return name
+}
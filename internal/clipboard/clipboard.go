@@ -0,0 +1,55 @@
+// Package clipboard reads the system clipboard through the platform's command-line tools.
+package clipboard
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// command is a clipboard tool and the arguments that print the clipboard to stdout.
+type command struct {
+	args []string
+	name string
+}
+
+// commands lists the clipboard tools to try for an OS, in order of preference.
+func commands(goos string) []command {
+	switch goos {
+	case "darwin":
+		return []command{{name: "pbpaste"}}
+	case "windows":
+		return []command{{name: "powershell.exe", args: []string{"-NoProfile", "-Command", "Get-Clipboard"}}}
+	default:
+		return []command{
+			{name: "wl-paste", args: []string{"--no-newline"}},
+			{name: "xclip", args: []string{"-selection", "clipboard", "-out"}},
+			{name: "xsel", args: []string{"--clipboard", "--output"}},
+		}
+	}
+}
+
+// Read returns the text on the system clipboard, using the first available clipboard tool.
+func Read() (string, error) {
+	return read(commands(runtime.GOOS), exec.LookPath, func(name string, args ...string) ([]byte, error) {
+		return exec.Command(name, args...).Output()
+	})
+}
+
+func read(cmds []command, lookPath func(string) (string, error), output func(string, ...string) ([]byte, error)) (string, error) {
+	var names []string
+	for _, c := range cmds {
+		names = append(names, c.name)
+		path, err := lookPath(c.name)
+		if err != nil {
+			continue
+		}
+		out, err := output(path, c.args...)
+		if err != nil {
+			return "", fmt.Errorf("failed to read clipboard with %s: %w", c.name, err)
+		}
+		return strings.TrimSpace(string(out)), nil
+	}
+	return "", fmt.Errorf("no clipboard tool found; install one of: %s", strings.Join(names, ", "))
+}
@@ -0,0 +1,87 @@
+package clipboard
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommands(t *testing.T) {
+	tests := []struct {
+		goos  string
+		first string
+	}{
+		{goos: "darwin", first: "pbpaste"},
+		{goos: "windows", first: "powershell.exe"},
+		{goos: "linux", first: "wl-paste"},
+		{goos: "freebsd", first: "wl-paste"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			cmds := commands(tt.goos)
+			require.NotEmpty(t, cmds)
+			assert.Equal(t, tt.first, cmds[0].name)
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	cmds := []command{{name: "wl-paste"}, {name: "xclip", args: []string{"-out"}}}
+
+	tests := []struct {
+		name      string
+		installed map[string]bool
+		outputErr error
+		want      string
+		wantRun   string
+		wantErr   string
+	}{
+		{
+			name:      "first available tool",
+			installed: map[string]bool{"xclip": true},
+			want:      "https://github.com/org/repo/pull/1",
+			wantRun:   "/usr/bin/xclip",
+		},
+		{
+			name:      "no tool installed",
+			installed: map[string]bool{},
+			wantErr:   "no clipboard tool found; install one of: wl-paste, xclip",
+		},
+		{
+			name:      "tool fails",
+			installed: map[string]bool{"wl-paste": true},
+			outputErr: errors.New("no display"),
+			wantErr:   "failed to read clipboard with wl-paste: no display",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ran string
+			lookPath := func(name string) (string, error) {
+				if tt.installed[name] {
+					return "/usr/bin/" + name, nil
+				}
+				return "", errors.New("not found")
+			}
+			output := func(name string, _ ...string) ([]byte, error) {
+				ran = name
+				return []byte("  https://github.com/org/repo/pull/1\n"), tt.outputErr
+			}
+
+			got, err := read(cmds, lookPath, output)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantRun, ran)
+		})
+	}
+}
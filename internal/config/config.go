@@ -2,23 +2,48 @@ package config
 
 import (
 	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 )
 
 // Config represents the complete grove configuration.
 type Config struct {
-	Branch   BranchConfig   `toml:"branch"`
-	Git      GitConfig      `toml:"git"`
-	Slugify  SlugifyConfig  `toml:"slugify"`
-	Worktree WorktreeConfig `toml:"worktree"`
+	Branch        BranchConfig        `toml:"branch"`
+	Git           GitConfig           `toml:"git"`
+	GitHub        GitHubConfig        `toml:"github"`
+	List          ListConfig          `toml:"list"`
+	Notifications NotificationsConfig `toml:"notifications"`
+	Open          OpenConfig          `toml:"open"`
+	PR            PRConfig            `toml:"pr"`
+	Slugify       SlugifyConfig       `toml:"slugify"`
+	Status        StatusConfig        `toml:"status"`
+	UI            UIConfig            `toml:"ui"`
+	Workspace     WorkspaceConfig     `toml:"workspace"`
+	Worktree      WorktreeConfig      `toml:"worktree"`
 }
 
 // Validate checks that all config values are valid.
 // Returns an error describing the first invalid value found.
 func (c Config) Validate() error {
+	if strings.TrimSpace(c.Branch.Base) == "" {
+		return errors.New("branch.base cannot be empty")
+	}
 	if c.Git.Timeout < 0 {
 		return errors.New("git.timeout cannot be negative")
 	}
+	if c.Git.NetworkTimeout < 0 {
+		return errors.New("git.network_timeout cannot be negative")
+	}
+	if c.Git.Retries < 0 {
+		return errors.New("git.retries cannot be negative")
+	}
+	if c.GitHub.Retries < 0 {
+		return errors.New("github.retries cannot be negative")
+	}
 	if c.Slugify.HashLength < 0 {
 		return errors.New("slugify.hash_length cannot be negative")
 	}
@@ -28,34 +53,189 @@ func (c Config) Validate() error {
 	if c.Slugify.MaxLength > 0 && c.Slugify.HashLength > c.Slugify.MaxLength-2 {
 		return errors.New("slugify.hash_length must be at least 2 less than slugify.max_length")
 	}
+	if !slices.Contains(ValidSlugSeparators, c.Slugify.Separator) {
+		return fmt.Errorf("slugify.separator must be one of %s", strings.Join(ValidSlugSeparators, ", "))
+	}
+	for _, name := range c.Slugify.Reserved {
+		if strings.TrimSpace(name) == "" {
+			return errors.New("slugify.reserved cannot contain an empty name")
+		}
+	}
+	if strings.TrimSpace(c.PR.BranchTemplate) == "" {
+		return errors.New("pr.branch_template cannot be empty")
+	}
+	if c.PR.ListLimit < 0 {
+		return errors.New("pr.list_limit cannot be negative")
+	}
+	if strings.TrimSpace(c.PR.WorktreeTemplate) == "" {
+		return errors.New("pr.worktree_template cannot be empty")
+	}
+	for _, pattern := range c.List.Exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("list.exclude has an invalid pattern %q", pattern)
+		}
+	}
+	for _, repo := range c.Workspace.Repos {
+		if !filepath.IsAbs(repo) && repo != "~" && !strings.HasPrefix(repo, "~/") {
+			return fmt.Errorf("workspace.repos entry %q must be an absolute path or start with ~", repo)
+		}
+	}
+	if c.Notifications.LongOpSeconds < 0 {
+		return errors.New("notifications.long_op_seconds cannot be negative")
+	}
+	if !slices.Contains(ValidNotifyMethods, c.Notifications.Method) {
+		return fmt.Errorf("notifications.method must be one of %s", strings.Join(ValidNotifyMethods, ", "))
+	}
+	if !slices.Contains(ValidPickers, c.UI.Picker) {
+		return fmt.Errorf("ui.picker must be one of %s", strings.Join(ValidPickers, ", "))
+	}
+	if !slices.Contains(ValidCollisionModes, c.Worktree.OnCollision) {
+		return fmt.Errorf("worktree.on_collision must be one of %s", strings.Join(ValidCollisionModes, ", "))
+	}
 	return nil
 }
 
-// BranchConfig configures branch naming.
+// Special values of branch.base; any other value is a git ref such as "origin/develop".
+const (
+	BranchBaseDefault = "default" // the remote's default branch, fetched first
+	BranchBaseHead    = "head"    // the current HEAD
+)
+
+// BranchConfig configures branch naming and where new branches start.
 type BranchConfig struct {
+	Base      string `toml:"base"`       // BranchBaseHead, BranchBaseDefault, or a ref
 	NewPrefix string `toml:"new_prefix"` // e.g., "feature/"
+	// Template names new branches instead of new_prefix, using Go text/template syntax with the fields
+	// of naming.BranchTemplateData, e.g. "{{.User}}/{{.Date}}/{{.Slug}}". Empty uses new_prefix.
+	Template string `toml:"template"`
 }
 
 // GitConfig configures git command execution.
 type GitConfig struct {
-	Timeout time.Duration `toml:"timeout"` // Timeout for git commands (e.g., "5s")
+	// NetworkTimeout bounds git commands that talk to a remote (fetch, clone, push, ls-remote) and gh calls,
+	// which can take much longer than local operations on a big repository (e.g., "2m").
+	NetworkTimeout time.Duration `toml:"network_timeout"`
+	Retries        int           `toml:"retries"` // times a fetch failing for a transient reason is retried, with backoff
+	Timeout        time.Duration `toml:"timeout"` // Timeout for local git commands (e.g., "5s")
+}
+
+// GitHubConfig configures gh command execution.
+type GitHubConfig struct {
+	Retries int `toml:"retries"` // times a gh call failing for a transient reason is retried, with backoff
+}
+
+// ListConfig configures grove list.
+type ListConfig struct {
+	// Exclude holds glob patterns for worktrees hidden from grove list unless --all is given.
+	// A pattern without a slash matches the directory name; otherwise it matches the absolute path,
+	// where ** matches any number of directories. e.g. ["**/archive-*", "wt-tmp-*"]
+	Exclude []string `toml:"exclude"`
+}
+
+// Notification methods accepted by notifications.method.
+const (
+	NotifyBell    = "bell"    // ring the terminal bell
+	NotifyDesktop = "desktop" // show a desktop notification, falling back to the bell
+)
+
+// ValidNotifyMethods lists the accepted values for notifications.method.
+var ValidNotifyMethods = []string{NotifyBell, NotifyDesktop}
+
+// NotificationsConfig configures how grove tells you that a long operation finished.
+type NotificationsConfig struct {
+	// LongOpSeconds is how long grove sync, grove clean, or grove pr sync must run before grove
+	// notifies you that it finished; 0 never notifies.
+	LongOpSeconds int    `toml:"long_op_seconds"`
+	Method        string `toml:"method"` // one of ValidNotifyMethods
+}
+
+// OpenConfig configures how grove open launches a worktree.
+type OpenConfig struct {
+	// Command is a preset name (vscode, jetbrains, tmux) or a text/template command line
+	// with the fields of opener.TemplateData, e.g. "code {{.Path}}".
+	Command string `toml:"command"`
+}
+
+// PRConfig configures pull request listing and branch and worktree naming.
+// Templates use Go text/template syntax with the fields of naming.PRTemplateData and the
+// functions lower, slug, and trunc, e.g. "pr/{{.Number}}-{{slug .Title | trunc 30}}".
+type PRConfig struct {
+	BranchTemplate string `toml:"branch_template"` // e.g., "{{.BranchName}}"
+	// DefaultFilters are GitHub search qualifiers added to every grove pr list query,
+	// e.g. ["label:backend", "-author:app/dependabot"].
+	DefaultFilters   []string `toml:"default_filters"`
+	ListLimit        int      `toml:"list_limit"`        // maximum pull requests listed; 0 lists all
+	WorktreeTemplate string   `toml:"worktree_template"` // e.g., "pr-{{.Number}}"
 }
 
 // SlugifyConfig configures slug generation.
 type SlugifyConfig struct {
-	CollapseDashes     bool `toml:"collapse_dashes"`
-	HashLength         int  `toml:"hash_length"`
-	Lowercase          bool `toml:"lowercase"`
-	MaxLength          int  `toml:"max_length"`
-	ReplaceNonAlphanum bool `toml:"replace_non_alphanum"`
-	TrimDashes         bool `toml:"trim_dashes"`
+	CollapseDashes     bool     `toml:"collapse_dashes"`
+	HashLength         int      `toml:"hash_length"`
+	Lowercase          bool     `toml:"lowercase"`
+	MaxLength          int      `toml:"max_length"`
+	ReplaceNonAlphanum bool     `toml:"replace_non_alphanum"`
+	Reserved           []string `toml:"reserved"`  // names a slug never equals; the hash is appended instead
+	Separator          string   `toml:"separator"` // one of ValidSlugSeparators
+	TrimDashes         bool     `toml:"trim_dashes"`
+}
+
+// ValidSlugSeparators lists the accepted values for slugify.separator.
+var ValidSlugSeparators = []string{"-", "_", "."}
+
+// StatusConfig configures how grove reports where branches stand.
+type StatusConfig struct {
+	// Base is the ref grove list counts commits ahead and behind against, e.g. "origin/main".
+	// Empty counts them against each branch's upstream.
+	Base string `toml:"base"`
+}
+
+// Picker names accepted by ui.picker.
+const (
+	PickerAuto   = "auto"   // fzf when installed, otherwise the built-in prompt
+	PickerFzf    = "fzf"    // always use fzf
+	PickerPrompt = "prompt" // always use the built-in numbered prompt
+)
+
+// Collision modes accepted by worktree.on_collision, for when a new worktree's path is taken.
+const (
+	CollisionError  = "error"  // fail, naming the path in the way
+	CollisionSuffix = "suffix" // append -2, -3, and so on until the path is free
+)
+
+// ValidCollisionModes lists the accepted values for worktree.on_collision.
+var ValidCollisionModes = []string{CollisionError, CollisionSuffix}
+
+// ValidPickers lists the accepted values for ui.picker.
+var ValidPickers = []string{PickerAuto, PickerFzf, PickerPrompt}
+
+// UIConfig configures interactive behavior.
+type UIConfig struct {
+	// DefaultCommand is the grove command line that grove runs with no arguments, e.g. "list --fzf"
+	// or the name of a plugin. Empty prints the help.
+	DefaultCommand string `toml:"default_command"`
+	Picker         string `toml:"picker"` // one of ValidPickers
+}
+
+// WorkspaceConfig configures the repositories grove operates on with --all-repos.
+type WorkspaceConfig struct {
+	Repos []string `toml:"repos"` // repository paths, absolute or starting with ~, e.g. ["~/code/api", "~/code/web"]
 }
 
 // WorktreeConfig configures worktree naming.
 type WorktreeConfig struct {
-	NewPrefix string `toml:"new_prefix"` // e.g., "wt-"
+	NewPrefix   string `toml:"new_prefix"`   // e.g., "wt-"
+	OnCollision string `toml:"on_collision"` // one of ValidCollisionModes
+	// Root is the directory new worktrees are created in, e.g. "~/worktrees/{{.RepoName}}".
+	// It may start with ~ and use the fields of naming.WorktreeRootData.
+	// Empty creates worktrees next to the main worktree.
+	Root string `toml:"root"`
 	// StripBranchPrefix is a list of prefixes to strip from branch names.
 	// Only the first matching prefix is stripped (checked in list order).
 	// e.g., branch "feature/add-auth" with ["fix/", "feature/"] -> "add-auth"
 	StripBranchPrefix []string `toml:"strip_branch_prefix"`
+	// Template names new worktree directories instead of new_prefix and the slug, using Go text/template
+	// syntax with the fields of naming.WorktreeTemplateData, e.g. "{{.Prefix}}{{.Slug}}-{{.Short}}".
+	// Empty uses new_prefix followed by the slug.
+	Template string `toml:"template"`
 }
@@ -14,10 +14,18 @@ func TestDefaultConfig(t *testing.T) {
 	cfg := DefaultConfig()
 
 	// Branch defaults
+	assert.Equal(t, BranchBaseHead, cfg.Branch.Base)
 	assert.Equal(t, "feature/", cfg.Branch.NewPrefix)
 
 	// Git defaults
 	assert.Equal(t, 5*time.Second, cfg.Git.Timeout)
+	assert.Equal(t, time.Minute, cfg.Git.NetworkTimeout)
+	assert.Equal(t, 2, cfg.Git.Retries)
+	assert.Equal(t, 3, cfg.GitHub.Retries)
+
+	// PR defaults
+	assert.Equal(t, "{{.BranchName}}", cfg.PR.BranchTemplate)
+	assert.Equal(t, "pr-{{.Number}}", cfg.PR.WorktreeTemplate)
 
 	// Slugify defaults
 	assert.True(t, cfg.Slugify.CollapseDashes)
@@ -25,10 +33,27 @@ func TestDefaultConfig(t *testing.T) {
 	assert.True(t, cfg.Slugify.Lowercase)
 	assert.Equal(t, 50, cfg.Slugify.MaxLength)
 	assert.True(t, cfg.Slugify.ReplaceNonAlphanum)
+	assert.Empty(t, cfg.Slugify.Reserved)
+	assert.Equal(t, "-", cfg.Slugify.Separator)
 	assert.True(t, cfg.Slugify.TrimDashes)
 
+	// Notifications defaults
+	assert.Zero(t, cfg.Notifications.LongOpSeconds)
+	assert.Equal(t, NotifyBell, cfg.Notifications.Method)
+
+	// Open defaults
+	assert.Empty(t, cfg.Open.Command)
+
+	// Status defaults
+	assert.Empty(t, cfg.Status.Base)
+
+	// UI defaults
+	assert.Empty(t, cfg.UI.DefaultCommand)
+	assert.Equal(t, PickerAuto, cfg.UI.Picker)
+
 	// Worktree defaults
 	assert.Equal(t, "wt-", cfg.Worktree.NewPrefix)
+	assert.Equal(t, CollisionError, cfg.Worktree.OnCollision)
 	assert.Equal(t, []string{"feature/"}, cfg.Worktree.StripBranchPrefix)
 
 	// Default config should be valid
@@ -46,6 +71,34 @@ func TestConfig_Validate(t *testing.T) {
 			modify:  func(c *Config) {},
 			wantErr: "",
 		},
+		{
+			name: "empty branch base",
+			modify: func(c *Config) {
+				c.Branch.Base = " "
+			},
+			wantErr: "branch.base cannot be empty",
+		},
+		{
+			name: "invalid list exclude pattern",
+			modify: func(c *Config) {
+				c.List.Exclude = []string{"wt-[a"}
+			},
+			wantErr: `list.exclude has an invalid pattern "wt-[a"`,
+		},
+		{
+			name: "relative workspace repo",
+			modify: func(c *Config) {
+				c.Workspace.Repos = []string{"~/code/api", "/srv/web", "code/cli"}
+			},
+			wantErr: `workspace.repos entry "code/cli" must be an absolute path or start with ~`,
+		},
+		{
+			name: "negative pr list limit",
+			modify: func(c *Config) {
+				c.PR.ListLimit = -1
+			},
+			wantErr: "pr.list_limit cannot be negative",
+		},
 		{
 			name: "negative git timeout",
 			modify: func(c *Config) {
@@ -53,6 +106,27 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: "git.timeout cannot be negative",
 		},
+		{
+			name: "negative git network timeout",
+			modify: func(c *Config) {
+				c.Git.NetworkTimeout = -1 * time.Second
+			},
+			wantErr: "git.network_timeout cannot be negative",
+		},
+		{
+			name: "negative git retries",
+			modify: func(c *Config) {
+				c.Git.Retries = -1
+			},
+			wantErr: "git.retries cannot be negative",
+		},
+		{
+			name: "negative github retries",
+			modify: func(c *Config) {
+				c.GitHub.Retries = -1
+			},
+			wantErr: "github.retries cannot be negative",
+		},
 		{
 			name: "negative hash length",
 			modify: func(c *Config) {
@@ -112,6 +186,83 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: "slugify.hash_length must be at least 2 less than slugify.max_length",
 		},
+		{
+			name: "underscore separator is valid",
+			modify: func(c *Config) {
+				c.Slugify.Separator = "_"
+			},
+			wantErr: "",
+		},
+		{
+			name: "unknown separator",
+			modify: func(c *Config) {
+				c.Slugify.Separator = "/"
+			},
+			wantErr: "slugify.separator must be one of -, _, .",
+		},
+		{
+			name: "empty reserved name",
+			modify: func(c *Config) {
+				c.Slugify.Reserved = []string{"prod", " "}
+			},
+			wantErr: "slugify.reserved cannot contain an empty name",
+		},
+		{
+			name: "empty pr branch template",
+			modify: func(c *Config) {
+				c.PR.BranchTemplate = " "
+			},
+			wantErr: "pr.branch_template cannot be empty",
+		},
+		{
+			name: "empty pr worktree template",
+			modify: func(c *Config) {
+				c.PR.WorktreeTemplate = ""
+			},
+			wantErr: "pr.worktree_template cannot be empty",
+		},
+		{
+			name: "negative long operation threshold",
+			modify: func(c *Config) {
+				c.Notifications.LongOpSeconds = -1
+			},
+			wantErr: "notifications.long_op_seconds cannot be negative",
+		},
+		{
+			name: "unknown notification method",
+			modify: func(c *Config) {
+				c.Notifications.Method = "email"
+			},
+			wantErr: "notifications.method must be one of bell, desktop",
+		},
+		{
+			name: "prompt picker is valid",
+			modify: func(c *Config) {
+				c.UI.Picker = PickerPrompt
+			},
+			wantErr: "",
+		},
+		{
+			name: "unknown picker",
+			modify: func(c *Config) {
+				c.UI.Picker = "dmenu"
+			},
+			wantErr: "ui.picker must be one of auto, fzf, prompt",
+		},
+		{
+			name: "suffix on collision is valid",
+			modify: func(c *Config) {
+				c.Worktree.OnCollision = CollisionSuffix
+			},
+			wantErr: "",
+		},
+		{
+			name: "unknown collision mode",
+			modify: func(c *Config) {
+				c.Worktree.OnCollision = "hash"
+			},
+			wantErr: "worktree.on_collision must be one of error, suffix",
+		},
 		{
 			name: "hash length greater than max length is invalid",
 			modify: func(c *Config) {
@@ -263,6 +414,27 @@ func TestConfigPaths(t *testing.T) {
 	}
 }
 
+func TestConfigPaths_SymlinkedHome(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	realHome := filepath.Join(root, "real-home")
+	gitRoot := filepath.Join(realHome, "code", "project")
+	require.NoError(t, os.MkdirAll(gitRoot, 0o755))
+	linkedHome := filepath.Join(root, "home")
+	require.NoError(t, os.Symlink(realHome, linkedHome))
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	// git reports the resolved gitRoot while $HOME is the symlink
+	paths := ConfigPaths(gitRoot, gitRoot, gitRoot, linkedHome)
+
+	assert.Equal(t, []string{
+		filepath.Join(realHome, ".config", "grove", "grove.toml"),
+		filepath.Join(realHome, "grove.toml"),
+		filepath.Join(realHome, "code", "grove.toml"),
+		filepath.Join(gitRoot, "grove.toml"),
+	}, paths)
+}
+
 // fakeFileSystem is a test double for FileSystem
 type fakeFileSystem struct {
 	existingFiles map[string]bool
@@ -393,6 +565,11 @@ new_prefix = "high/"
 	assert.Equal(t, 100, result.Config.Slugify.MaxLength)
 	// Both paths should be in source paths
 	assert.Equal(t, []string{lowPriorityPath, highPriorityPath}, result.SourcePaths)
+	// Each key's source is the last file that set it
+	assert.Equal(t, highPriorityPath, result.Source("branch.new_prefix"))
+	assert.Equal(t, lowPriorityPath, result.Source("slugify.max_length"))
+	assert.Equal(t, SourceDefault, result.Source("git.timeout"))
+	assert.NotContains(t, result.Sources, "slugify", "tables are not keys")
 }
 
 func TestLoad_ZeroValueOverwrite(t *testing.T) {
@@ -497,6 +674,19 @@ func TestLoad_ReturnsSourcePaths(t *testing.T) {
 	assert.Equal(t, []string{path1, path2}, result.SourcePaths)
 }
 
+func TestLoad_UnknownKeys(t *testing.T) {
+	tmpDir := t.TempDir()
+	path1 := filepath.Join(tmpDir, "one.toml")
+	path2 := filepath.Join(tmpDir, "two.toml")
+	require.NoError(t, os.WriteFile(path1, []byte("[branch]\nnew_prefix = \"one/\"\nnew_prefx = \"typo/\"\n\n[colour]\nmode = \"on\""), 0644))
+	require.NoError(t, os.WriteFile(path2, []byte("[branch]\nnew_prefix = \"two/\""), 0644))
+
+	result, err := NewDefaultLoader().Load([]string{path1, path2})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{path1: {"branch.new_prefx", "colour", "colour.mode"}}, result.UnknownKeys)
+}
+
 func TestLoad_PathIsDirectory(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -541,3 +731,206 @@ func TestNewDefaultLoader(t *testing.T) {
 	assert.NotNil(t, loader)
 	assert.IsType(t, OSFileSystem{}, loader.fs)
 }
+
+func TestKeys(t *testing.T) {
+	keys := Keys()
+
+	assert.Contains(t, keys, "git.timeout")
+	assert.Contains(t, keys, "slugify.max_length")
+	assert.Contains(t, keys, "worktree.strip_branch_prefix")
+	assert.IsNonDecreasing(t, keys)
+}
+
+func TestConfig_Set(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		check   func(t *testing.T, c Config)
+		wantErr string
+	}{
+		{
+			name:  "string",
+			key:   "worktree.new_prefix",
+			value: "tmp-",
+			check: func(t *testing.T, c Config) { assert.Equal(t, "tmp-", c.Worktree.NewPrefix) },
+		},
+		{
+			name:  "int",
+			key:   "slugify.max_length",
+			value: "30",
+			check: func(t *testing.T, c Config) { assert.Equal(t, 30, c.Slugify.MaxLength) },
+		},
+		{
+			name:  "bool",
+			key:   "slugify.lowercase",
+			value: "false",
+			check: func(t *testing.T, c Config) { assert.False(t, c.Slugify.Lowercase) },
+		},
+		{
+			name:  "duration",
+			key:   "git.timeout",
+			value: "30s",
+			check: func(t *testing.T, c Config) { assert.Equal(t, 30*time.Second, c.Git.Timeout) },
+		},
+		{
+			name:  "list",
+			key:   "worktree.strip_branch_prefix",
+			value: "fix/, feature/,",
+			check: func(t *testing.T, c Config) {
+				assert.Equal(t, []string{"fix/", "feature/"}, c.Worktree.StripBranchPrefix)
+			},
+		},
+		{
+			name:  "empty list",
+			key:   "worktree.strip_branch_prefix",
+			value: "",
+			check: func(t *testing.T, c Config) { assert.Empty(t, c.Worktree.StripBranchPrefix) },
+		},
+		{
+			name:    "unknown key",
+			key:     "slugify.nope",
+			value:   "1",
+			wantErr: `unknown config key "slugify.nope"`,
+		},
+		{
+			name:    "section is not a key",
+			key:     "slugify",
+			value:   "1",
+			wantErr: `unknown config key "slugify"`,
+		},
+		{
+			name:    "invalid int",
+			key:     "slugify.max_length",
+			value:   "thirty",
+			wantErr: `slugify.max_length: invalid integer "thirty"`,
+		},
+		{
+			name:    "invalid duration",
+			key:     "git.timeout",
+			value:   "30",
+			wantErr: `git.timeout: invalid duration "30"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			err := cfg.Set(tt.key, tt.value)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestConfig_ApplyOverrides(t *testing.T) {
+	tests := []struct {
+		name        string
+		assignments []string
+		wantErr     string
+	}{
+		{
+			name:        "later assignments win",
+			assignments: []string{"slugify.max_length=20", "slugify.max_length=30"},
+		},
+		{
+			name:        "missing equals",
+			assignments: []string{"slugify.max_length"},
+			wantErr:     `invalid override "slugify.max_length"; expected key=value`,
+		},
+		{
+			name:        "result is validated",
+			assignments: []string{"slugify.max_length=-1"},
+			wantErr:     "slugify.max_length cannot be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			keys, err := cfg.ApplyOverrides(tt.assignments)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []string{"slugify.max_length", "slugify.max_length"}, keys)
+			assert.Equal(t, 30, cfg.Slugify.MaxLength)
+		})
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	assert.Equal(t, "GROVE_GIT_TIMEOUT", EnvName("git.timeout"))
+	assert.Equal(t, "GROVE_WORKTREE_NEW_PREFIX", EnvName("worktree.new_prefix"))
+}
+
+func TestLoad_EnvOverrides(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "grove.toml")
+	require.NoError(t, os.WriteFile(path, []byte("[git]\ntimeout = \"10s\"\n"), 0644))
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		check   func(t *testing.T, result LoadResult)
+		wantErr string
+	}{
+		{
+			name: "no env leaves file values",
+			check: func(t *testing.T, result LoadResult) {
+				assert.Equal(t, 10*time.Second, result.Config.Git.Timeout)
+				assert.Equal(t, path, result.Source("git.timeout"))
+			},
+		},
+		{
+			name: "env wins over file",
+			env:  map[string]string{"GROVE_GIT_TIMEOUT": "30s", "GROVE_WORKTREE_NEW_PREFIX": "tmp-"},
+			check: func(t *testing.T, result LoadResult) {
+				assert.Equal(t, 30*time.Second, result.Config.Git.Timeout)
+				assert.Equal(t, "tmp-", result.Config.Worktree.NewPrefix)
+				assert.Equal(t, "env GROVE_GIT_TIMEOUT", result.Source("git.timeout"))
+				assert.Equal(t, "env GROVE_WORKTREE_NEW_PREFIX", result.Source("worktree.new_prefix"))
+			},
+		},
+		{
+			name: "list values are comma-separated",
+			env:  map[string]string{"GROVE_WORKTREE_STRIP_BRANCH_PREFIX": "fix/,feature/"},
+			check: func(t *testing.T, result LoadResult) {
+				assert.Equal(t, []string{"fix/", "feature/"}, result.Config.Worktree.StripBranchPrefix)
+			},
+		},
+		{
+			name:    "unparseable value",
+			env:     map[string]string{"GROVE_SLUGIFY_MAX_LENGTH": "long"},
+			wantErr: `invalid GROVE_SLUGIFY_MAX_LENGTH: slugify.max_length: invalid integer "long"`,
+		},
+		{
+			name:    "env values are validated",
+			env:     map[string]string{"GROVE_UI_PICKER": "dmenu"},
+			wantErr: "invalid config: ui.picker must be one of auto, fzf, prompt",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loader := NewDefaultLoader().WithLookupEnv(func(name string) (string, bool) {
+				value, ok := tt.env[name]
+				return value, ok
+			})
+
+			result, err := loader.Load([]string{path})
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, result)
+		})
+	}
+}
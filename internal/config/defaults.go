@@ -6,10 +6,24 @@ import "time"
 func DefaultConfig() Config {
 	return Config{
 		Branch: BranchConfig{
+			Base:      BranchBaseHead,
 			NewPrefix: "feature/",
 		},
 		Git: GitConfig{
-			Timeout: 5 * time.Second,
+			NetworkTimeout: time.Minute,
+			Retries:        2,
+			Timeout:        5 * time.Second,
+		},
+		GitHub: GitHubConfig{
+			Retries: 3,
+		},
+		Notifications: NotificationsConfig{
+			Method: NotifyBell,
+		},
+		PR: PRConfig{
+			BranchTemplate:   "{{.BranchName}}",
+			ListLimit:        20,
+			WorktreeTemplate: "pr-{{.Number}}",
 		},
 		Slugify: SlugifyConfig{
 			CollapseDashes:     true,
@@ -17,10 +31,15 @@ func DefaultConfig() Config {
 			Lowercase:          true,
 			MaxLength:          50,
 			ReplaceNonAlphanum: true,
+			Separator:          "-",
 			TrimDashes:         true,
 		},
+		UI: UIConfig{
+			Picker: PickerAuto,
+		},
 		Worktree: WorktreeConfig{
 			NewPrefix:         "wt-",
+			OnCollision:       CollisionError,
 			StripBranchPrefix: []string{"feature/"},
 		},
 	}
@@ -3,6 +3,8 @@ package config
 import (
 	"os"
 	"path/filepath"
+
+	"github.com/jmcampanini/grove-cli/internal/pathutil"
 )
 
 const configFileName = "grove.toml"
@@ -20,7 +22,11 @@ const configFileName = "grove.toml"
 //
 // The worktreeRoot and gitRoot may be the same directory if running from the main worktree.
 // Empty strings for worktreeRoot or gitRoot are handled gracefully.
+// Directories are normalized first, so a symlinked home directory still bounds the walk up from gitRoot.
 func ConfigPaths(cwd, worktreeRoot, gitRoot, homeDir string) []string {
+	cwd, worktreeRoot = pathutil.Normalize(cwd), pathutil.Normalize(worktreeRoot)
+	gitRoot, homeDir = pathutil.Normalize(gitRoot), pathutil.Normalize(homeDir)
+
 	var paths []string
 	seen := make(map[string]bool)
 
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Keys returns every settable config key in dotted TOML form (e.g., "slugify.max_length"), sorted.
+func Keys() []string {
+	var keys []string
+	walkKeys(reflect.TypeOf(Config{}), "", func(key string, _ []int) {
+		keys = append(keys, key)
+	})
+	sort.Strings(keys)
+	return keys
+}
+
+// walkKeys calls fn with the dotted key and field index path of every leaf field in t.
+func walkKeys(t reflect.Type, prefix string, fn func(key string, index []int)) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("toml"), ",")[0]
+		if tag == "" || tag == "-" {
+			continue
+		}
+		key := tag
+		if prefix != "" {
+			key = prefix + "." + tag
+		}
+		if field.Type.Kind() == reflect.Struct && field.Type != durationType {
+			walkKeys(field.Type, key, func(k string, index []int) {
+				fn(k, append([]int{i}, index...))
+			})
+			continue
+		}
+		fn(key, []int{i})
+	}
+}
+
+// field returns the field for a dotted key; it is settable when c is addressable.
+func (c *Config) field(key string) (reflect.Value, error) {
+	var index []int
+	walkKeys(reflect.TypeOf(*c), "", func(k string, idx []int) {
+		if k == key {
+			index = idx
+		}
+	})
+	if index == nil {
+		return reflect.Value{}, fmt.Errorf("unknown config key %q", key)
+	}
+	return reflect.ValueOf(c).Elem().FieldByIndex(index), nil
+}
+
+// Set parses value according to the field's type and assigns it to the field named by a dotted key.
+// Durations use Go syntax ("30s"), booleans use strconv.ParseBool, and lists are comma-separated.
+// Set does not validate the resulting config; call Validate afterwards.
+func (c *Config) Set(key, value string) error {
+	field, err := c.field(key)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case field.Type() == durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%s: invalid duration %q", key, value)
+		}
+		field.SetInt(int64(d))
+	case field.Kind() == reflect.String:
+		field.SetString(value)
+	case field.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s: invalid boolean %q", key, value)
+		}
+		field.SetBool(b)
+	case field.Kind() == reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s: invalid integer %q", key, value)
+		}
+		field.SetInt(int64(n))
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+		items := []string{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("%s: unsupported type %s", key, field.Type())
+	}
+	return nil
+}
+
+// Get returns the value of the field named by a dotted key.
+func (c Config) Get(key string) (any, error) {
+	field, err := c.field(key)
+	if err != nil {
+		return nil, err
+	}
+	return field.Interface(), nil
+}
+
+// ApplyOverrides applies "key=value" assignments in order and validates the result.
+// Returns the keys that were set.
+func (c *Config) ApplyOverrides(assignments []string) ([]string, error) {
+	keys := make([]string, 0, len(assignments))
+	for _, assignment := range assignments {
+		key, value, ok := strings.Cut(assignment, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid override %q; expected key=value", assignment)
+		}
+		key = strings.TrimSpace(key)
+		if err := c.Set(key, value); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, c.Validate()
+}
@@ -3,60 +3,97 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/BurntSushi/toml"
 	"github.com/charmbracelet/log"
+	"github.com/jmcampanini/grove-cli/internal/fsutil"
 )
 
+// SourceDefault is the provenance of config keys that no file set.
+const SourceDefault = "default"
+
 // LoadResult contains the loaded config and metadata about the load.
 type LoadResult struct {
 	Config      Config
-	SourcePaths []string // paths that were successfully loaded, in order applied
+	SourcePaths []string            // paths that were successfully loaded, in order applied
+	Sources     map[string]string   // config key -> file path or EnvSource; absent keys are SourceDefault
+	UnknownKeys map[string][]string // file path -> keys it sets that grove does not know, for files with any
 }
 
-// FileSystem abstracts file system operations for testability.
+// Source returns where a config key's value came from: a file path, EnvSource, or SourceDefault.
+func (r LoadResult) Source(key string) string {
+	if source, ok := r.Sources[key]; ok {
+		return source
+	}
+	return SourceDefault
+}
+
+// FileSystem is the part of fsutil.FS the loader needs, so any fsutil.FS can be passed to NewLoader.
 type FileSystem interface {
 	// Exists returns true if the path exists and is a file (not a directory).
 	Exists(path string) bool
 }
 
 // OSFileSystem implements FileSystem using the real OS.
-type OSFileSystem struct{}
+type OSFileSystem = fsutil.OS
 
-// Exists returns true if the path exists and is a file (not a directory).
-func (OSFileSystem) Exists(path string) bool {
-	info, err := os.Stat(path)
-	if err != nil {
-		return false
-	}
-	return !info.IsDir()
+// EnvPrefix is the prefix of environment variables that override config keys.
+const EnvPrefix = "GROVE_"
+
+// EnvName returns the environment variable that overrides a config key,
+// e.g. "worktree.new_prefix" -> "GROVE_WORKTREE_NEW_PREFIX".
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// EnvSource returns the provenance recorded for a key set by an environment variable.
+func EnvSource(key string) string {
+	return "env " + EnvName(key)
 }
 
 // Loader handles configuration loading and merging.
 type Loader struct {
-	fs  FileSystem
-	log *log.Logger
+	fs        FileSystem
+	log       *log.Logger
+	lookupEnv func(string) (string, bool)
 }
 
 // NewLoader creates a new Loader with the given FileSystem.
+// Environment overrides are read from the process environment.
 func NewLoader(fs FileSystem) *Loader {
 	return &Loader{
-		fs:  fs,
-		log: log.Default().WithPrefix("config"),
+		fs:        fs,
+		log:       log.Default().WithPrefix("config"),
+		lookupEnv: os.LookupEnv,
 	}
 }
 
+// WithLookupEnv replaces the function used to read environment overrides.
+func (l *Loader) WithLookupEnv(lookupEnv func(string) (string, bool)) *Loader {
+	l.lookupEnv = lookupEnv
+	return l
+}
+
 // NewDefaultLoader creates a new Loader that uses the real OS file system.
 func NewDefaultLoader() *Loader {
 	return NewLoader(OSFileSystem{})
 }
 
-// Load reads and merges all config files in priority order.
+// Load reads and merges all config files in priority order, then applies environment overrides.
 // Paths should be ordered from lowest to highest priority.
+// Every config key can be overridden by the environment variable named by EnvName,
+// parsed the same way as Config.Set; environment variables win over all files.
 // Returns merged config with defaults as base, plus source paths for debugging.
 func (l *Loader) Load(paths []string) (LoadResult, error) {
 	cfg := DefaultConfig()
 	var sourcePaths []string
+	sources := make(map[string]string)
+	unknownKeys := make(map[string][]string)
+	knownKeys := make(map[string]bool)
+	for _, key := range Keys() {
+		knownKeys[key] = true
+	}
 
 	for _, path := range paths {
 		if !l.fs.Exists(path) {
@@ -70,11 +107,31 @@ func (l *Loader) Load(paths []string) (LoadResult, error) {
 
 		if undecoded := metadata.Undecoded(); len(undecoded) > 0 {
 			l.log.Warn("unknown config keys", "path", path, "keys", undecoded)
+			for _, key := range undecoded {
+				unknownKeys[path] = append(unknownKeys[path], key.String())
+			}
+		}
+
+		for _, key := range metadata.Keys() {
+			if knownKeys[key.String()] {
+				sources[key.String()] = path
+			}
 		}
 
 		sourcePaths = append(sourcePaths, path)
 	}
 
+	for _, key := range Keys() {
+		value, ok := l.lookupEnv(EnvName(key))
+		if !ok {
+			continue
+		}
+		if err := cfg.Set(key, value); err != nil {
+			return LoadResult{}, fmt.Errorf("invalid %s: %w", EnvName(key), err)
+		}
+		sources[key] = EnvSource(key)
+	}
+
 	if err := cfg.Validate(); err != nil {
 		return LoadResult{}, fmt.Errorf("invalid config: %w", err)
 	}
@@ -82,5 +139,7 @@ func (l *Loader) Load(paths []string) (LoadResult, error) {
 	return LoadResult{
 		Config:      cfg,
 		SourcePaths: sourcePaths,
+		Sources:     sources,
+		UnknownKeys: unknownKeys,
 	}, nil
 }
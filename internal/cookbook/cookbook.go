@@ -0,0 +1,81 @@
+package cookbook
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+//go:embed recipes/*.tmpl
+var recipeFS embed.FS
+
+// Data holds the repository details substituted into recipes so examples are copy-pasteable.
+type Data struct {
+	BranchPrefix      string // e.g., "feature/"
+	MainWorktreePath  string // absolute path of the main worktree
+	PRWorktreeExample string // worktree name for pull request 123
+	WorkspacePath     string // directory containing all worktrees
+	WorktreePrefix    string // e.g., "wt-"
+}
+
+// Recipe is a single cookbook topic.
+type Recipe struct {
+	Name  string // topic name used on the command line
+	Title string // first line of the recipe, without the leading "# "
+}
+
+// Recipes returns all recipes sorted by name.
+func Recipes() []Recipe {
+	entries, err := recipeFS.ReadDir("recipes")
+	if err != nil {
+		panic(fmt.Sprintf("cookbook: embedded recipes missing: %v", err))
+	}
+
+	recipes := make([]Recipe, 0, len(entries))
+	for _, entry := range entries {
+		content, err := recipeFS.ReadFile(path.Join("recipes", entry.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("cookbook: failed to read %s: %v", entry.Name(), err))
+		}
+		title, _, _ := strings.Cut(string(content), "\n")
+		recipes = append(recipes, Recipe{
+			Name:  strings.TrimSuffix(entry.Name(), ".tmpl"),
+			Title: strings.TrimPrefix(title, "# "),
+		})
+	}
+	sort.Slice(recipes, func(i, j int) bool { return recipes[i].Name < recipes[j].Name })
+	return recipes
+}
+
+// Names returns the topic names of all recipes.
+func Names() []string {
+	recipes := Recipes()
+	names := make([]string, len(recipes))
+	for i, r := range recipes {
+		names[i] = r.Name
+	}
+	return names
+}
+
+// Render renders the recipe for a topic with the given data.
+func Render(topic string, data Data) (string, error) {
+	content, err := recipeFS.ReadFile(path.Join("recipes", topic+".tmpl"))
+	if err != nil {
+		return "", fmt.Errorf("unknown cookbook topic %q (available: %s)", topic, strings.Join(Names(), ", "))
+	}
+
+	tmpl, err := template.New(topic).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse recipe %s: %w", topic, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render recipe %s: %w", topic, err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,44 @@
+package cookbook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipes(t *testing.T) {
+	recipes := Recipes()
+
+	assert.Equal(t, []string{"fzf-switcher", "pr-review", "sparse-worktrees"}, Names())
+	for _, r := range recipes {
+		assert.NotEmpty(t, r.Title, r.Name)
+		assert.NotContains(t, r.Title, "#", r.Name)
+	}
+}
+
+func TestRender(t *testing.T) {
+	data := Data{
+		BranchPrefix:      "feature/",
+		MainWorktreePath:  "/ws/main",
+		PRWorktreeExample: "review-123",
+		WorkspacePath:     "/ws",
+		WorktreePrefix:    "wt-",
+	}
+
+	for _, name := range Names() {
+		t.Run(name, func(t *testing.T) {
+			out, err := Render(name, data)
+			require.NoError(t, err)
+			assert.NotContains(t, out, "{{")
+			assert.Contains(t, out, "/ws")
+		})
+	}
+
+	out, err := Render("pr-review", data)
+	require.NoError(t, err)
+	assert.Contains(t, out, "git -C /ws/main worktree remove /ws/review-123")
+
+	_, err = Render("nope", data)
+	assert.EqualError(t, err, `unknown cookbook topic "nope" (available: fzf-switcher, pr-review, sparse-worktrees)`)
+}
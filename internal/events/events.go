@@ -0,0 +1,81 @@
+// Package events writes newline-delimited JSON progress events for wrappers such as GUIs and editor plugins.
+//
+// A command emits one Started event, one ItemCompleted event per unit of work, and one Finished event.
+// The event fields are a stable interface: new fields may be added, but existing ones keep their meaning.
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Type identifies the kind of event.
+type Type string
+
+const (
+	Started       Type = "started"
+	ItemCompleted Type = "item-completed"
+	Finished      Type = "finished"
+)
+
+// Event is a single line of the event stream.
+type Event struct {
+	Command string    `json:"command"`           // full command path, e.g. "grove doctor"
+	Error   string    `json:"error,omitempty"`   // Finished: why the command failed
+	Item    string    `json:"item,omitempty"`    // ItemCompleted: what was processed
+	Message string    `json:"message,omitempty"` // ItemCompleted: human-readable detail
+	Status  string    `json:"status,omitempty"`  // ItemCompleted: command-specific outcome; Finished: "ok" or "error"
+	Time    time.Time `json:"time"`
+	Total   int       `json:"total,omitempty"` // Started: number of items, when known
+	Type    Type      `json:"type"`
+}
+
+// Emitter writes events for one command. A nil *Emitter discards events, so commands can emit unconditionally.
+type Emitter struct {
+	command string
+	mu      sync.Mutex
+	now     func() time.Time
+	w       io.Writer
+}
+
+// New returns an Emitter that writes events for command to w, timestamped with now.
+func New(w io.Writer, command string, now func() time.Time) *Emitter {
+	return &Emitter{
+		command: command,
+		now:     now,
+		w:       w,
+	}
+}
+
+// Started reports that the command began processing total items (0 if unknown).
+func (e *Emitter) Started(total int) error {
+	return e.emit(Event{Total: total, Type: Started})
+}
+
+// ItemCompleted reports that one item finished with a command-specific status.
+func (e *Emitter) ItemCompleted(item, status, message string) error {
+	return e.emit(Event{Item: item, Message: message, Status: status, Type: ItemCompleted})
+}
+
+// Finished reports that the command ended, successfully if err is nil.
+func (e *Emitter) Finished(err error) error {
+	event := Event{Status: "ok", Type: Finished}
+	if err != nil {
+		event.Error = err.Error()
+		event.Status = "error"
+	}
+	return e.emit(event)
+}
+
+func (e *Emitter) emit(event Event) error {
+	if e == nil {
+		return nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	event.Command = e.command
+	event.Time = e.now()
+	return json.NewEncoder(e.w).Encode(event)
+}
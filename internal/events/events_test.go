@@ -0,0 +1,73 @@
+package events
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTime = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func TestEmitter(t *testing.T) {
+	tests := []struct {
+		name string
+		emit func(e *Emitter) error
+		want string
+	}{
+		{
+			name: "started",
+			emit: func(e *Emitter) error { return e.Started(3) },
+			want: `{"command":"grove doctor","time":"2024-06-01T12:00:00Z","total":3,"type":"started"}`,
+		},
+		{
+			name: "item completed",
+			emit: func(e *Emitter) error { return e.ItemCompleted("git", "pass", "git 2.45.0") },
+			want: `{"command":"grove doctor","item":"git","message":"git 2.45.0","status":"pass","time":"2024-06-01T12:00:00Z","type":"item-completed"}`,
+		},
+		{
+			name: "finished ok",
+			emit: func(e *Emitter) error { return e.Finished(nil) },
+			want: `{"command":"grove doctor","status":"ok","time":"2024-06-01T12:00:00Z","type":"finished"}`,
+		},
+		{
+			name: "finished with error",
+			emit: func(e *Emitter) error { return e.Finished(errors.New("1 check failed")) },
+			want: `{"command":"grove doctor","error":"1 check failed","status":"error","time":"2024-06-01T12:00:00Z","type":"finished"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			e := New(&out, "grove doctor", func() time.Time { return testTime })
+
+			require.NoError(t, tt.emit(e))
+
+			assert.Equal(t, tt.want+"\n", out.String())
+		})
+	}
+}
+
+func TestEmitter_OneEventPerLine(t *testing.T) {
+	var out bytes.Buffer
+	e := New(&out, "grove doctor", func() time.Time { return testTime })
+
+	require.NoError(t, e.Started(1))
+	require.NoError(t, e.ItemCompleted("git", "pass", "multi\nline"))
+	require.NoError(t, e.Finished(nil))
+
+	assert.Len(t, strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n"), 3)
+}
+
+func TestEmitter_Nil(t *testing.T) {
+	var e *Emitter
+
+	assert.NoError(t, e.Started(1))
+	assert.NoError(t, e.ItemCompleted("git", "pass", ""))
+	assert.NoError(t, e.Finished(nil))
+}
@@ -0,0 +1,126 @@
+// Package fsutil abstracts the file system operations grove performs, so code that touches files can be
+// tested against an in-memory file system.
+package fsutil
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// FS is the file system grove reads and writes through.
+type FS interface {
+	// Exists returns true if the path exists and is a file (not a directory).
+	Exists(path string) bool
+
+	// ReadFile returns the contents of a file, following symlinks.
+	// The error wraps fs.ErrNotExist if there is no file at path.
+	ReadFile(path string) ([]byte, error)
+
+	// WriteFile replaces the file at path with data. The file is written to a temporary file first and
+	// renamed into place, so a crash never leaves it truncated. The directory must exist.
+	WriteFile(path string, data []byte, perm fs.FileMode) error
+
+	// MkdirAll creates a directory and any missing parents. It succeeds if the directory already exists.
+	MkdirAll(path string, perm fs.FileMode) error
+
+	// Symlink creates newname as a symbolic link to oldname.
+	Symlink(oldname, newname string) error
+
+	// CopyTree copies the directory at src to dst, which must not exist yet. File modes are kept and
+	// symlinks are copied as symlinks rather than followed.
+	CopyTree(src, dst string) error
+}
+
+// OS implements FS using the real file system.
+type OS struct{}
+
+var _ FS = OS{}
+
+func (OS) Exists(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return !info.IsDir()
+}
+
+func (OS) ReadFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+func (OS) WriteFile(path string, data []byte, perm fs.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (OS) MkdirAll(path string, perm fs.FileMode) error {
+	return os.MkdirAll(path, perm)
+}
+
+func (OS) Symlink(oldname, newname string) error {
+	return os.Symlink(oldname, newname)
+}
+
+func (OS) CopyTree(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", src)
+	}
+	if _, err := os.Lstat(dst); err == nil {
+		return fmt.Errorf("%s: %w", dst, fs.ErrExist)
+	}
+
+	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case entry.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case entry.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case !entry.Type().IsRegular():
+			// sockets, devices, and pipes cannot be copied
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, info.Mode().Perm())
+	})
+}
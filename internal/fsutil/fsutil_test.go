@@ -0,0 +1,105 @@
+package fsutil
+
+import (
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// implementations returns each FS with an empty directory to work in, so both are held to the same behavior.
+func implementations(t *testing.T) map[string]func() (FS, string) {
+	return map[string]func() (FS, string){
+		"os": func() (FS, string) { return OS{}, t.TempDir() },
+		"memory": func() (FS, string) {
+			m := NewMemory()
+			require.NoError(t, m.MkdirAll("/work", 0o755))
+			return m, "/work"
+		},
+	}
+}
+
+func TestFS_ReadWrite(t *testing.T) {
+	for name, newFS := range implementations(t) {
+		t.Run(name, func(t *testing.T) {
+			fsys, dir := newFS()
+			path := filepath.Join(dir, "a", "state.json")
+
+			_, err := fsys.ReadFile(path)
+			require.ErrorIs(t, err, fs.ErrNotExist)
+			require.Error(t, fsys.WriteFile(path, []byte("x"), 0o644), "parent directory is missing")
+
+			require.NoError(t, fsys.MkdirAll(filepath.Dir(path), 0o755))
+			require.NoError(t, fsys.MkdirAll(filepath.Dir(path), 0o755))
+			require.NoError(t, fsys.WriteFile(path, []byte("old"), 0o644))
+			require.NoError(t, fsys.WriteFile(path, []byte("new"), 0o644))
+
+			data, err := fsys.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, "new", string(data))
+			assert.True(t, fsys.Exists(path))
+			assert.False(t, fsys.Exists(filepath.Dir(path)), "directories are not files")
+			assert.False(t, fsys.Exists(filepath.Join(dir, "missing")))
+		})
+	}
+}
+
+func TestFS_Symlink(t *testing.T) {
+	for name, newFS := range implementations(t) {
+		t.Run(name, func(t *testing.T) {
+			fsys, dir := newFS()
+			require.NoError(t, fsys.WriteFile(filepath.Join(dir, "target"), []byte("hi"), 0o644))
+
+			require.NoError(t, fsys.Symlink("target", filepath.Join(dir, "link")))
+
+			data, err := fsys.ReadFile(filepath.Join(dir, "link"))
+			require.NoError(t, err)
+			assert.Equal(t, "hi", string(data))
+			assert.True(t, fsys.Exists(filepath.Join(dir, "link")))
+			require.Error(t, fsys.Symlink("target", filepath.Join(dir, "link")), "link already exists")
+
+			require.NoError(t, fsys.Symlink("missing", filepath.Join(dir, "dangling")))
+			assert.False(t, fsys.Exists(filepath.Join(dir, "dangling")))
+		})
+	}
+}
+
+func TestFS_CopyTree(t *testing.T) {
+	for name, newFS := range implementations(t) {
+		t.Run(name, func(t *testing.T) {
+			fsys, dir := newFS()
+			src := filepath.Join(dir, "src")
+			require.NoError(t, fsys.MkdirAll(filepath.Join(src, "config", "nested"), 0o755))
+			require.NoError(t, fsys.WriteFile(filepath.Join(src, ".env"), []byte("KEY=1"), 0o600))
+			require.NoError(t, fsys.WriteFile(filepath.Join(src, "config", "nested", "app.toml"), []byte("a = 1"), 0o644))
+			require.NoError(t, fsys.Symlink(".env", filepath.Join(src, "env-link")))
+			dst := filepath.Join(dir, "dst")
+
+			require.NoError(t, fsys.CopyTree(src, dst))
+
+			data, err := fsys.ReadFile(filepath.Join(dst, "config", "nested", "app.toml"))
+			require.NoError(t, err)
+			assert.Equal(t, "a = 1", string(data))
+			data, err = fsys.ReadFile(filepath.Join(dst, "env-link"))
+			require.NoError(t, err)
+			assert.Equal(t, "KEY=1", string(data), "the link points at the copy")
+
+			require.NoError(t, fsys.WriteFile(filepath.Join(dst, ".env"), []byte("KEY=2"), 0o600))
+			data, err = fsys.ReadFile(filepath.Join(src, ".env"))
+			require.NoError(t, err)
+			assert.Equal(t, "KEY=1", string(data), "the copy is independent")
+
+			require.ErrorIs(t, fsys.CopyTree(src, dst), fs.ErrExist)
+			require.Error(t, fsys.CopyTree(filepath.Join(src, ".env"), filepath.Join(dir, "file-copy")))
+		})
+	}
+}
+
+func TestMemory_AddFile(t *testing.T) {
+	m := NewMemory().AddFile("/home/user/.config/grove/config.toml", "[git]\n")
+
+	assert.True(t, m.Exists("/home/user/.config/grove/config.toml"))
+	assert.False(t, m.Exists("/home/user/.config/grove"))
+}
@@ -0,0 +1,184 @@
+package fsutil
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// maxSymlinks is how many symlinks Memory follows while resolving a path before giving up, like ELOOP.
+const maxSymlinks = 40
+
+// errSymlinkLoop is returned by Memory when resolving a path follows too many symlinks.
+var errSymlinkLoop = errors.New("too many levels of symbolic links")
+
+// Memory is an in-memory FS for tests. Tests should use absolute paths; the root directory always exists.
+// Symlinks are followed only when they are the last element of a path.
+type Memory struct {
+	entries map[string]memEntry
+	mu      sync.Mutex
+}
+
+type memEntry struct {
+	data []byte
+	dir  bool
+	link string // symlink target, empty for files and directories
+	perm fs.FileMode
+}
+
+var _ FS = &Memory{}
+
+// NewMemory returns an empty in-memory file system.
+func NewMemory() *Memory {
+	return &Memory{entries: map[string]memEntry{string(filepath.Separator): {dir: true, perm: 0o755}}}
+}
+
+// AddFile creates a file and its missing parent directories, for setting up tests.
+func (m *Memory) AddFile(path string, data string) *Memory {
+	if err := m.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		panic(err)
+	}
+	if err := m.WriteFile(path, []byte(data), 0o644); err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func (m *Memory) Exists(path string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, _, err := m.resolve(path)
+	return err == nil && !entry.dir
+}
+
+func (m *Memory) ReadFile(path string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, _, err := m.resolve(path)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: path, Err: err}
+	}
+	if entry.dir {
+		return nil, &fs.PathError{Op: "read", Path: path, Err: fmt.Errorf("is a directory")}
+	}
+	return slices.Clone(entry.data), nil
+}
+
+func (m *Memory) WriteFile(path string, data []byte, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	path = filepath.Clean(path)
+	if err := m.checkParent(path); err != nil {
+		return &fs.PathError{Op: "open", Path: path, Err: err}
+	}
+	if entry, ok := m.entries[path]; ok && entry.dir {
+		return &fs.PathError{Op: "open", Path: path, Err: fmt.Errorf("is a directory")}
+	}
+	m.entries[path] = memEntry{data: slices.Clone(data), perm: perm}
+	return nil
+}
+
+func (m *Memory) MkdirAll(path string, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	path = filepath.Clean(path)
+	var missing []string
+	for p := path; ; p = filepath.Dir(p) {
+		if entry, ok := m.entries[p]; ok {
+			if !entry.dir {
+				return &fs.PathError{Op: "mkdir", Path: p, Err: fmt.Errorf("not a directory")}
+			}
+			break
+		}
+		missing = append(missing, p)
+	}
+	for _, p := range missing {
+		m.entries[p] = memEntry{dir: true, perm: perm}
+	}
+	return nil
+}
+
+func (m *Memory) Symlink(oldname, newname string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	newname = filepath.Clean(newname)
+	if err := m.checkParent(newname); err != nil {
+		return &fs.PathError{Op: "symlink", Path: newname, Err: err}
+	}
+	if _, ok := m.entries[newname]; ok {
+		return &fs.PathError{Op: "symlink", Path: newname, Err: fs.ErrExist}
+	}
+	m.entries[newname] = memEntry{link: oldname, perm: 0o777}
+	return nil
+}
+
+func (m *Memory) CopyTree(src, dst string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	root, src, err := m.resolve(src)
+	if err != nil {
+		return &fs.PathError{Op: "stat", Path: src, Err: err}
+	}
+	if !root.dir {
+		return fmt.Errorf("%s is not a directory", src)
+	}
+	dst = filepath.Clean(dst)
+	if _, ok := m.entries[dst]; ok {
+		return fmt.Errorf("%s: %w", dst, fs.ErrExist)
+	}
+	if err := m.checkParent(dst); err != nil {
+		return &fs.PathError{Op: "mkdir", Path: dst, Err: err}
+	}
+
+	prefix := src + string(filepath.Separator)
+	if src == string(filepath.Separator) {
+		prefix = src
+	}
+	copied := map[string]memEntry{dst: {dir: true, perm: root.perm}}
+	for path, entry := range m.entries {
+		if rel, ok := strings.CutPrefix(path, prefix); ok && rel != "" {
+			entry.data = slices.Clone(entry.data)
+			copied[filepath.Join(dst, rel)] = entry
+		}
+	}
+	for path, entry := range copied {
+		m.entries[path] = entry
+	}
+	return nil
+}
+
+// resolve returns the entry at path, following symlinks, and the resolved path.
+func (m *Memory) resolve(path string) (memEntry, string, error) {
+	path = filepath.Clean(path)
+	for range maxSymlinks {
+		entry, ok := m.entries[path]
+		if !ok {
+			return memEntry{}, path, fs.ErrNotExist
+		}
+		if entry.link == "" {
+			return entry, path, nil
+		}
+		target := entry.link
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(path), target)
+		}
+		path = filepath.Clean(target)
+	}
+	return memEntry{}, path, errSymlinkLoop
+}
+
+// checkParent returns an error unless the directory containing path exists.
+func (m *Memory) checkParent(path string) error {
+	parent, _, err := m.resolve(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	if !parent.dir {
+		return fmt.Errorf("not a directory")
+	}
+	return nil
+}
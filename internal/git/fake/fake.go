@@ -0,0 +1,1215 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+)
+
+// Git is an in-memory implementation of git.Git for tests and demos.
+// It models local branches, remote branches, tags, and worktrees without touching the filesystem.
+// Seed it with the Add* methods; mutating interface methods update the model.
+type Git struct {
+	bare           bool // the main worktree is a bare repository, see SetBare
+	branches       map[string]*branch
+	clones         map[string]string // bare dir -> URL cloned by CloneBare, see Cloned
+	currentPath    string
+	divergence     map[string][2]int     // "branch...base" -> ahead, behind, see SetAheadBehind
+	fetched        map[string]git.Commit // SHA -> commit fetched by FetchRef
+	mainPath       string
+	mu             sync.Mutex
+	pager          string
+	pending        map[string]map[string]*git.Commit // remote -> ref -> commit the next FetchRemote picks up, nil to delete
+	refs           map[string]git.Commit             // full ref name -> commit, for refs other than branches and tags
+	remoteDefaults map[string]string                 // default branch reported by the remote itself, see SetRemoteDefaultBranch
+	remoteHeads    map[string]string
+	remoteRefs     map[string]map[string]git.Commit
+	remoteURLs     map[string]string
+	tags           []git.Tag
+	userName       string
+	version        string
+	worktreeConfig git.WorktreeConfigState
+	worktrees      []*worktree
+}
+
+var _ git.Git = &Git{}
+
+type branch struct {
+	ahead       int
+	behind      int
+	commit      git.Commit
+	description string
+	gone        bool
+	name        string
+	unmerged    bool
+	unreachable []git.Commit // commits only this branch reaches, see SetUnreachable
+	upstream    string
+}
+
+type worktree struct {
+	branch        string // empty when detached
+	commit        git.Commit
+	conflicts     []string // files a rebase conflicts on, see SetRebaseConflicts
+	dirty         bool     // the worktree has modified or untracked files, see SetDirty
+	diverged      bool     // the branch has local commits, so FastForward fails
+	gitDir        string   // overrides the derived git dir when set
+	gitLinkBroken bool
+	lastActivity  time.Time
+	path          string
+	prunable      string
+	rebasing      bool     // a rebase stopped and waits to be continued, see SetRebaseInProgress
+	submodules    []string // uninitialized submodule paths
+	unmerged      []string // files with unresolved conflicts, see SetRebaseInProgress
+}
+
+// DefaultVersion is the git version reported by a new fake.
+const DefaultVersion = "2.45.0"
+
+// New creates a fake repository whose main worktree is at mainPath with branch "main" checked out.
+// The current directory is the main worktree.
+func New(mainPath string, initial git.Commit) *Git {
+	g := &Git{
+		branches:       map[string]*branch{},
+		clones:         map[string]string{},
+		currentPath:    mainPath,
+		divergence:     map[string][2]int{},
+		fetched:        map[string]git.Commit{},
+		mainPath:       mainPath,
+		pending:        map[string]map[string]*git.Commit{},
+		refs:           map[string]git.Commit{},
+		remoteDefaults: map[string]string{},
+		remoteHeads:    map[string]string{},
+		remoteRefs:     map[string]map[string]git.Commit{},
+		remoteURLs:     map[string]string{},
+		pager:          "cat",
+		userName:       "user",
+		version:        DefaultVersion,
+	}
+	g.branches["main"] = &branch{commit: initial, name: "main"}
+	g.worktrees = append(g.worktrees, &worktree{branch: "main", path: mainPath})
+	return g
+}
+
+// AddBranch adds a local branch pointing at commit.
+func (g *Git) AddBranch(name string, commit git.Commit) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.branches[name] = &branch{commit: commit, name: name}
+	return g
+}
+
+// SetUpstream sets the upstream tracking branch and ahead/behind counts for a local branch.
+func (g *Git) SetUpstream(branchName, upstream string, ahead, behind int) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if b, ok := g.branches[branchName]; ok {
+		b.ahead = ahead
+		b.behind = behind
+		b.upstream = upstream
+	}
+	return g
+}
+
+// SetAheadBehind sets the counts GetAheadBehind reports for a branch compared with baseRef; other pairs
+// of refs report zeros.
+func (g *Git) SetAheadBehind(branchName, baseRef string, ahead, behind int) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.divergence[branchName+"..."+baseRef] = [2]int{ahead, behind}
+	return g
+}
+
+// SetUpstreamGone marks a local branch's upstream as deleted on the remote, as `git fetch --prune` leaves it.
+func (g *Git) SetUpstreamGone(branchName string) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if b, ok := g.branches[branchName]; ok {
+		b.gone = true
+	}
+	return g
+}
+
+// AddTag adds a tag.
+func (g *Git) AddTag(tag git.Tag) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.tags = append(g.tags, tag)
+	return g
+}
+
+// AddWorktree adds a linked worktree at path with an existing branch checked out.
+func (g *Git) AddWorktree(path, branchName string) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.worktrees = append(g.worktrees, &worktree{branch: branchName, path: path})
+	return g
+}
+
+// AddDetachedWorktree adds a linked worktree at path with a detached HEAD at commit.
+func (g *Git) AddDetachedWorktree(path string, commit git.Commit) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.worktrees = append(g.worktrees, &worktree{commit: commit, path: path})
+	return g
+}
+
+// AddRemoteRef adds a ref (e.g., "main" or "pull/12/head") to a remote, creating the remote if needed.
+func (g *Git) AddRemoteRef(remoteName, ref string, commit git.Commit) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.remoteRefs[remoteName] == nil {
+		g.remoteRefs[remoteName] = map[string]git.Commit{}
+	}
+	g.remoteRefs[remoteName][ref] = commit
+	return g
+}
+
+// PushRemoteRef simulates someone pushing ref to the remote: the next FetchRemote adds or updates it.
+func (g *Git) PushRemoteRef(remoteName, ref string, commit git.Commit) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.setPending(remoteName, ref, &commit)
+	return g
+}
+
+// DeleteRemoteRef simulates someone deleting ref on the remote: the next FetchRemote prunes it.
+func (g *Git) DeleteRemoteRef(remoteName, ref string) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.setPending(remoteName, ref, nil)
+	return g
+}
+
+func (g *Git) setPending(remoteName, ref string, commit *git.Commit) {
+	if g.pending[remoteName] == nil {
+		g.pending[remoteName] = map[string]*git.Commit{}
+	}
+	g.pending[remoteName][ref] = commit
+}
+
+// SetRemoteURL sets the URL of a remote, creating the remote if needed.
+func (g *Git) SetRemoteURL(remoteName, url string) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.remoteRefs[remoteName] == nil {
+		g.remoteRefs[remoteName] = map[string]git.Commit{}
+	}
+	g.remoteURLs[remoteName] = url
+	return g
+}
+
+// SetRemoteHead sets the default branch reported for a remote.
+func (g *Git) SetRemoteHead(remoteName, branchName string) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.remoteHeads[remoteName] = branchName
+	return g
+}
+
+// SetRemoteDefaultBranch sets the default branch the remote reports when its HEAD is detected,
+// without setting the local remote HEAD.
+func (g *Git) SetRemoteDefaultBranch(remoteName, branchName string) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.remoteDefaults[remoteName] = branchName
+	return g
+}
+
+// SetBare makes the repository bare, as `git clone --bare` leaves it: mainPath is the repository itself,
+// e.g. /ws/.bare, which has no working tree and is listed in the main worktree's place. Make a linked
+// worktree current to run commands in.
+func (g *Git) SetBare() *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.bare = true
+	return g
+}
+
+// SetDirty marks the worktree at path as having modified or untracked files.
+func (g *Git) SetDirty(path string) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if wt := g.worktreeAt(path); wt != nil {
+		wt.dirty = true
+	}
+	return g
+}
+
+// SetPrunable marks the worktree at path as prunable for the given reason, as if its directory were deleted.
+func (g *Git) SetPrunable(path, reason string) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if wt := g.worktreeAt(path); wt != nil {
+		wt.prunable = reason
+	}
+	return g
+}
+
+// SetGitDir sets the git dir reported for the worktree at path, e.g. a temp directory for tests that inspect it.
+func (g *Git) SetGitDir(path, gitDir string) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if wt := g.worktreeAt(path); wt != nil {
+		wt.gitDir = gitDir
+	}
+	return g
+}
+
+// SetLastActivity sets the last activity time reported for the worktree at path.
+func (g *Git) SetLastActivity(path string, at time.Time) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if wt := g.worktreeAt(path); wt != nil {
+		wt.lastActivity = at
+	}
+	return g
+}
+
+// SetDiverged marks the branch of the worktree at path as having local commits, so FastForward fails
+// until the branch is rebased.
+func (g *Git) SetDiverged(path string) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if wt := g.worktreeAt(path); wt != nil {
+		wt.diverged = true
+	}
+	return g
+}
+
+// SetRebaseConflicts makes Rebase in the worktree at path stop on conflicts in files.
+func (g *Git) SetRebaseConflicts(path string, files ...string) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if wt := g.worktreeAt(path); wt != nil {
+		wt.conflicts = files
+	}
+	return g
+}
+
+// SetRebaseInProgress marks a rebase as stopped in the worktree at path, with unresolved conflicts in files,
+// or with inProgress false, as finished.
+func (g *Git) SetRebaseInProgress(path string, inProgress bool, files ...string) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if wt := g.worktreeAt(path); wt != nil {
+		wt.rebasing = inProgress
+		wt.unmerged = files
+	}
+	return g
+}
+
+// SetGitLinkBroken breaks the .git link of the worktree at path until RepairWorktree is called.
+func (g *Git) SetGitLinkBroken(path string) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if wt := g.worktreeAt(path); wt != nil {
+		wt.gitLinkBroken = true
+	}
+	return g
+}
+
+// SetUninitializedSubmodules sets the submodules of the worktree at path that are not initialized.
+func (g *Git) SetUninitializedSubmodules(path string, submodules ...string) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if wt := g.worktreeAt(path); wt != nil {
+		wt.submodules = submodules
+	}
+	return g
+}
+
+// DeleteBranchRef removes a branch's ref without touching the worktrees that have it checked out,
+// as `git update-ref -d` would.
+func (g *Git) DeleteBranchRef(name string) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.branches, name)
+	return g
+}
+
+// SetUnmerged marks a branch as having commits that are not merged, so DeleteBranch refuses it without force.
+func (g *Git) SetUnmerged(name string) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if b, ok := g.branches[name]; ok {
+		b.unmerged = true
+	}
+	return g
+}
+
+// SetUnreachable sets the commits ListUnreachableCommits reports for a branch, newest first.
+func (g *Git) SetUnreachable(name string, commits ...git.Commit) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if b, ok := g.branches[name]; ok {
+		b.unreachable = commits
+	}
+	return g
+}
+
+// SetPager sets the pager reported by GetPager.
+func (g *Git) SetPager(pager string) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.pager = pager
+	return g
+}
+
+// SetUserName sets the git user.name reported by GetUserName.
+func (g *Git) SetUserName(name string) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.userName = name
+	return g
+}
+
+// SetVersion sets the git version reported by GetVersion.
+func (g *Git) SetVersion(version string) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.version = version
+	return g
+}
+
+// SetWorktreeConfigState sets the state reported by GetWorktreeConfigState.
+func (g *Git) SetWorktreeConfigState(state git.WorktreeConfigState) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.worktreeConfig = state
+	return g
+}
+
+// SetCurrentPath changes the worktree the fake considers to be the current directory.
+func (g *Git) SetCurrentPath(path string) *Git {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.currentPath = path
+	return g
+}
+
+func (g *Git) currentWorktree() *worktree {
+	for _, wt := range g.worktrees {
+		if wt.path == g.currentPath || strings.HasPrefix(g.currentPath, wt.path+string(filepath.Separator)) {
+			return wt
+		}
+	}
+	return nil
+}
+
+// worktreeForBranch returns the worktree that has the branch checked out. A bare repository's HEAD is not a
+// checkout, so its branch can still be checked out in a linked worktree.
+func (g *Git) worktreeForBranch(name string) *worktree {
+	for _, wt := range g.worktrees {
+		if wt.branch == name && !(g.bare && wt.path == g.mainPath) {
+			return wt
+		}
+	}
+	return nil
+}
+
+func (g *Git) worktreeAt(path string) *worktree {
+	for _, wt := range g.worktrees {
+		if wt.path == path {
+			return wt
+		}
+	}
+	return nil
+}
+
+func (g *Git) localBranch(b *branch) git.LocalBranch {
+	var worktreePath string
+	if wt := g.worktreeForBranch(b.name); wt != nil {
+		worktreePath = wt.path
+	}
+	isCheckedOut := false
+	if wt := g.currentWorktree(); wt != nil && wt.branch == b.name {
+		isCheckedOut = true
+	}
+	lb := git.NewLocalBranch(b.name, b.upstream, worktreePath, isCheckedOut, b.ahead, b.behind, b.commit)
+	lb.Gone = b.gone
+	return lb
+}
+
+func (g *Git) GetCurrentBranch(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	wt := g.currentWorktree()
+	if wt == nil {
+		return "", fmt.Errorf("not a git repository: %s", g.currentPath)
+	}
+	if wt.branch == "" {
+		return "HEAD", nil
+	}
+	return wt.branch, nil
+}
+
+func (g *Git) GetCommonDir(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.bare {
+		return g.mainPath, nil
+	}
+	return filepath.Join(g.mainPath, ".git"), nil
+}
+
+func (g *Git) GetMainWorktreePath(ctx context.Context) (string, error) {
+	return g.mainPath, nil
+}
+
+func (g *Git) GetWorkspacePath(ctx context.Context) (string, error) {
+	return filepath.Dir(g.mainPath), nil
+}
+
+func (g *Git) GetWorktreeRoot(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if wt := g.currentWorktree(); wt != nil {
+		return wt.path, nil
+	}
+	return "", nil
+}
+
+func (g *Git) GetCommitSubject(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	wt := g.currentWorktree()
+	if wt == nil {
+		return "", fmt.Errorf("not a git repository: %s", g.currentPath)
+	}
+	if wt.branch == "" {
+		return wt.commit.Subject, nil
+	}
+	return g.branches[wt.branch].commit.Subject, nil
+}
+
+func (g *Git) GetPager(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.pager, nil
+}
+
+func (g *Git) GetUserName(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.userName, nil
+}
+
+func (g *Git) GetVersion(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.version, nil
+}
+
+func (g *Git) GetDefaultRemote(ctx context.Context, fallback string) (string, error) {
+	return fallback, nil
+}
+
+func (g *Git) GetRepoDefaultBranch(ctx context.Context, remoteName string) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.remoteRefs[remoteName]; !ok {
+		return "", fmt.Errorf("remote '%s' does not exist", remoteName)
+	}
+	return g.remoteHeads[remoteName], nil
+}
+
+func (g *Git) ResolveRepoDefaultBranch(ctx context.Context, remoteName string) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.remoteRefs[remoteName]; !ok {
+		return "", fmt.Errorf("remote '%s' does not exist", remoteName)
+	}
+	if g.remoteHeads[remoteName] == "" {
+		g.remoteHeads[remoteName] = g.remoteDefaults[remoteName]
+	}
+	return g.remoteHeads[remoteName], nil
+}
+
+func (g *Git) GetRemoteHEAD(ctx context.Context, remoteName string) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.remoteRefs[remoteName]; !ok {
+		return "", fmt.Errorf("remote '%s' does not exist", remoteName)
+	}
+	return g.remoteDefaults[remoteName], nil
+}
+
+func (g *Git) SetRepoDefaultBranch(ctx context.Context, remoteName, branchName string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	refs, ok := g.remoteRefs[remoteName]
+	if !ok {
+		return fmt.Errorf("remote '%s' does not exist", remoteName)
+	}
+	if _, ok := refs[branchName]; !ok {
+		return fmt.Errorf("not a valid ref: refs/remotes/%s/%s", remoteName, branchName)
+	}
+	g.remoteHeads[remoteName] = branchName
+	return nil
+}
+
+func (g *Git) ListLocalBranches(ctx context.Context) ([]git.LocalBranch, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	names := make([]string, 0, len(g.branches))
+	for name := range g.branches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	branches := make([]git.LocalBranch, 0, len(names))
+	for _, name := range names {
+		branches = append(branches, g.localBranch(g.branches[name]))
+	}
+	return branches, nil
+}
+
+func (g *Git) ListRemoteBranches(ctx context.Context, remoteName string) ([]git.RemoteBranch, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	refs := g.remoteRefs[remoteName]
+	names := make([]string, 0, len(refs))
+	for name := range refs {
+		// only branches are listed, not special refs such as pull/N/head
+		if !strings.HasPrefix(name, "pull/") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	branches := make([]git.RemoteBranch, 0, len(names))
+	for _, name := range names {
+		branches = append(branches, git.NewRemoteBranch(name, remoteName, refs[name]))
+	}
+	return branches, nil
+}
+
+func (g *Git) ListRemotes(ctx context.Context) ([]string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	remotes := make([]string, 0, len(g.remoteRefs))
+	for name := range g.remoteRefs {
+		remotes = append(remotes, name)
+	}
+	sort.Strings(remotes)
+	return remotes, nil
+}
+
+func (g *Git) GetRemoteURL(ctx context.Context, remoteName string) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.remoteRefs[remoteName]; !ok {
+		return "", fmt.Errorf("no such remote '%s'", remoteName)
+	}
+	return g.remoteURLs[remoteName], nil
+}
+
+func (g *Git) ListTags(ctx context.Context) ([]git.Tag, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]git.Tag{}, g.tags...), nil
+}
+
+func (g *Git) BranchExists(ctx context.Context, branchName string, caseInsensitive bool) (bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for name := range g.branches {
+		if name == branchName || (caseInsensitive && strings.EqualFold(name, branchName)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (g *Git) ResolveRef(ctx context.Context, ref string) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if ref == "" {
+		return "", fmt.Errorf("invalid ref %q", ref)
+	}
+	commit, err := g.resolve(ref)
+	if err != nil {
+		return "", fmt.Errorf("ref %q does not name a commit", ref)
+	}
+	return commit.SHA, nil
+}
+
+func (g *Git) GetAheadBehind(ctx context.Context, branchName, baseRef string) (int, int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, ref := range []string{branchName, baseRef} {
+		if _, err := g.resolve(ref); err != nil || ref == "" {
+			return 0, 0, fmt.Errorf("ref %q does not name a commit", ref)
+		}
+	}
+	counts := g.divergence[branchName+"..."+baseRef]
+	return counts[0], counts[1], nil
+}
+
+func (g *Git) ListUnreachableCommits(ctx context.Context, branchName string) ([]git.Commit, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	b, ok := g.branches[branchName]
+	if !ok {
+		return nil, fmt.Errorf("invalid reference: %s", branchName)
+	}
+	return slices.Clone(b.unreachable), nil
+}
+
+func (g *Git) ListWorktrees(ctx context.Context) ([]git.Worktree, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	worktrees := make([]git.Worktree, 0, len(g.worktrees))
+	for _, wt := range g.worktrees {
+		worktree := git.Worktree{AbsolutePath: wt.path, IsMain: wt.path == g.mainPath, Prunable: wt.prunable}
+		if worktree.IsMain && g.bare {
+			worktree.IsBare = true
+		} else if b, ok := g.branches[wt.branch]; ok {
+			lb := g.localBranch(b)
+			worktree.Ref = &lb
+		} else if wt.branch != "" {
+			lb := git.NewLocalBranch(wt.branch, "", wt.path, false, 0, 0, wt.commit)
+			worktree.BranchMissing = true
+			worktree.Ref = &lb
+		} else {
+			worktree.Ref = g.detachedRef(wt.commit)
+		}
+		worktrees = append(worktrees, worktree)
+	}
+	return worktrees, nil
+}
+
+// detachedRef returns the tag pointing at commit, or the commit itself, mirroring GitCli.
+func (g *Git) detachedRef(commit git.Commit) git.WorktreeRef {
+	for _, tag := range g.tags {
+		if tag.Commit().SHA == commit.SHA {
+			t := tag
+			return &t
+		}
+	}
+	return &commit
+}
+
+func (g *Git) GetWorktreeGitDir(ctx context.Context, worktreeAbsPath string) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	wt := g.worktreeAt(worktreeAbsPath)
+	if wt == nil || wt.gitLinkBroken {
+		return "", fmt.Errorf("not a git repository: %s", worktreeAbsPath)
+	}
+	switch {
+	case wt.gitDir != "":
+		return wt.gitDir, nil
+	case wt.path == g.mainPath:
+		return filepath.Join(g.mainPath, ".git"), nil
+	default:
+		return filepath.Join(g.mainPath, ".git", "worktrees", filepath.Base(wt.path)), nil
+	}
+}
+
+func (g *Git) GetLastActivity(ctx context.Context, worktreeAbsPath string) (time.Time, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	wt := g.worktreeAt(worktreeAbsPath)
+	if wt == nil || wt.gitLinkBroken {
+		return time.Time{}, fmt.Errorf("not a git repository: %s", worktreeAbsPath)
+	}
+	return wt.lastActivity, nil
+}
+
+func (g *Git) ListUninitializedSubmodules(ctx context.Context, worktreeAbsPath string) ([]string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	wt := g.worktreeAt(worktreeAbsPath)
+	if wt == nil {
+		return nil, fmt.Errorf("not a git repository: %s", worktreeAbsPath)
+	}
+	return slices.Clone(wt.submodules), nil
+}
+
+func (g *Git) IsRebaseInProgress(ctx context.Context, worktreeAbsPath string) (bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	wt := g.worktreeAt(worktreeAbsPath)
+	if wt == nil {
+		return false, fmt.Errorf("not a git repository: %s", worktreeAbsPath)
+	}
+	return wt.rebasing, nil
+}
+
+func (g *Git) IsDirty(ctx context.Context, worktreeAbsPath string) (bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	wt := g.worktreeAt(worktreeAbsPath)
+	if wt == nil {
+		return false, fmt.Errorf("not a git repository: %s", worktreeAbsPath)
+	}
+	return wt.dirty, nil
+}
+
+func (g *Git) ListConflictedFiles(ctx context.Context, worktreeAbsPath string) ([]string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	wt := g.worktreeAt(worktreeAbsPath)
+	if wt == nil {
+		return nil, fmt.Errorf("not a git repository: %s", worktreeAbsPath)
+	}
+	return append([]string{}, wt.unmerged...), nil
+}
+
+func (g *Git) CreateWorktreeForNewBranch(ctx context.Context, newBranchName, worktreeAbsPath string) error {
+	return g.CreateWorktreeForNewBranchFromRef(ctx, newBranchName, worktreeAbsPath, "")
+}
+
+func (g *Git) CreateWorktreeForNewBranchFromRef(ctx context.Context, newBranchName, worktreeAbsPath, baseRef string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, exists := g.branches[newBranchName]; exists {
+		return fmt.Errorf("a branch named '%s' already exists", newBranchName)
+	}
+	if g.worktreeAt(worktreeAbsPath) != nil {
+		return fmt.Errorf("'%s' already exists", worktreeAbsPath)
+	}
+
+	commit, err := g.resolve(baseRef)
+	if err != nil {
+		return err
+	}
+
+	g.branches[newBranchName] = &branch{commit: commit, name: newBranchName}
+	g.worktrees = append(g.worktrees, &worktree{branch: newBranchName, path: worktreeAbsPath})
+	return nil
+}
+
+// resolve returns the commit for a branch, remote branch ("origin/main"), or tag name.
+// An empty ref resolves to the current worktree's HEAD.
+func (g *Git) resolve(ref string) (git.Commit, error) {
+	if ref == "" || ref == "HEAD" {
+		wt := g.currentWorktree()
+		if wt == nil {
+			return git.Commit{}, fmt.Errorf("not a git repository: %s", g.currentPath)
+		}
+		if wt.branch == "" {
+			return wt.commit, nil
+		}
+		return g.branches[wt.branch].commit, nil
+	}
+	if b, ok := g.branches[ref]; ok {
+		return b.commit, nil
+	}
+	if commit, ok := g.refs[ref]; ok {
+		return commit, nil
+	}
+	if commit, ok := g.fetched[ref]; ok {
+		return commit, nil
+	}
+	if remote, name, ok := strings.Cut(ref, "/"); ok {
+		if commit, ok := g.remoteRefs[remote][name]; ok {
+			return commit, nil
+		}
+	}
+	for _, tag := range g.tags {
+		if tag.Name == ref {
+			return tag.Commit(), nil
+		}
+	}
+	if len(ref) >= 4 {
+		for _, b := range g.branches {
+			if strings.HasPrefix(b.commit.SHA, ref) {
+				return b.commit, nil
+			}
+		}
+	}
+	return git.Commit{}, fmt.Errorf("invalid reference: %s", ref)
+}
+
+func (g *Git) CreateWorktreeForExistingBranch(ctx context.Context, branchName, worktreeAbsPath string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, exists := g.branches[branchName]; !exists {
+		return fmt.Errorf("invalid reference: %s", branchName)
+	}
+	if wt := g.worktreeForBranch(branchName); wt != nil {
+		return fmt.Errorf("'%s' is already checked out at '%s'", branchName, wt.path)
+	}
+	if g.worktreeAt(worktreeAbsPath) != nil {
+		return fmt.Errorf("'%s' already exists", worktreeAbsPath)
+	}
+	g.worktrees = append(g.worktrees, &worktree{branch: branchName, path: worktreeAbsPath})
+	return nil
+}
+
+func (g *Git) FetchRef(ctx context.Context, remote, remoteRef string) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	commit, ok := g.remoteRefs[remote][remoteRef]
+	if !ok {
+		return "", fmt.Errorf("couldn't find remote ref %s", remoteRef)
+	}
+	g.fetched[commit.SHA] = commit
+	return commit.SHA, nil
+}
+
+func (g *Git) FastForward(ctx context.Context, worktreeAbsPath, ref string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	wt, b, commit, err := g.updateTarget(worktreeAbsPath, ref)
+	if err != nil {
+		return err
+	}
+	if wt.diverged {
+		return fmt.Errorf("failed to fast-forward %s to %s: %w", worktreeAbsPath, ref, git.ErrNotFastForward)
+	}
+	b.commit = commit
+	return nil
+}
+
+func (g *Git) Rebase(ctx context.Context, worktreeAbsPath, ref string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	wt, b, commit, err := g.updateTarget(worktreeAbsPath, ref)
+	if err != nil {
+		return err
+	}
+	if len(wt.conflicts) > 0 {
+		return &git.ConflictError{Files: slices.Clone(wt.conflicts)}
+	}
+	b.commit = commit
+	wt.diverged = false
+	return nil
+}
+
+// updateTarget returns the worktree at path, the branch checked out in it, and the commit ref names,
+// for FastForward and Rebase.
+func (g *Git) updateTarget(path, ref string) (*worktree, *branch, git.Commit, error) {
+	wt := g.worktreeAt(path)
+	if wt == nil {
+		return nil, nil, git.Commit{}, fmt.Errorf("not a git repository: %s", path)
+	}
+	b, ok := g.branches[wt.branch]
+	if !ok {
+		return nil, nil, git.Commit{}, fmt.Errorf("no branch checked out in %s", path)
+	}
+	commit, ok := g.fetched[ref]
+	if !ok {
+		resolved, err := g.resolve(ref)
+		if err != nil {
+			return nil, nil, git.Commit{}, err
+		}
+		commit = resolved
+	}
+	return wt, b, commit, nil
+}
+
+func (g *Git) FetchRemoteBranch(ctx context.Context, remote, remoteRef, localRef string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	remoteRef = strings.TrimPrefix(remoteRef, "+") // the fake never rejects an update as non-fast-forward
+	commit, ok := g.remoteRefs[remote][remoteRef]
+	if !ok {
+		return fmt.Errorf("couldn't find remote ref %s", remoteRef)
+	}
+
+	// a refspec destination under refs/remotes/ updates the remote-tracking ref, anything else is a local branch
+	if tracking, ok := strings.CutPrefix(localRef, "refs/remotes/"); ok {
+		if trackingRemote, name, ok := strings.Cut(tracking, "/"); ok && g.remoteRefs[trackingRemote] != nil {
+			g.remoteRefs[trackingRemote][name] = commit
+		}
+		return nil
+	}
+
+	name := strings.TrimPrefix(localRef, "refs/heads/")
+	if wt := g.worktreeForBranch(name); wt != nil {
+		return fmt.Errorf("refusing to fetch into branch '%s' checked out at '%s'", name, wt.path)
+	}
+	if b, exists := g.branches[name]; exists {
+		b.commit = commit
+		return nil
+	}
+	g.branches[name] = &branch{commit: commit, name: name}
+	return nil
+}
+
+func (g *Git) SyncTags(context.Context, string) error {
+	return nil
+}
+
+// FetchRemote applies the remote's pending ref changes. Progress gets one line per changed ref, like git's.
+func (g *Git) FetchRemote(ctx context.Context, remoteName string, progress func(line string)) (string, error) {
+	lines, err := g.fetchRemote(remoteName)
+	if err != nil {
+		return "", err
+	}
+	if progress != nil {
+		for _, line := range lines {
+			progress(line)
+		}
+	}
+	return "", nil
+}
+
+func (g *Git) fetchRemote(remoteName string) ([]string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	refs, ok := g.remoteRefs[remoteName]
+	if !ok {
+		return nil, fmt.Errorf("'%s' does not appear to be a git repository", remoteName)
+	}
+	var lines []string
+	for _, ref := range slices.Sorted(maps.Keys(g.pending[remoteName])) {
+		commit := g.pending[remoteName][ref]
+		if commit == nil {
+			delete(refs, ref)
+			lines = append(lines, fmt.Sprintf(" - [deleted]         (none) -> %s/%s", remoteName, ref))
+			continue
+		}
+		if old, ok := refs[ref]; ok {
+			lines = append(lines, fmt.Sprintf("   %s..%s  %s -> %s/%s", old.SHA, commit.SHA, ref, remoteName, ref))
+		} else {
+			lines = append(lines, fmt.Sprintf(" * [new branch]      %s -> %s/%s", ref, remoteName, ref))
+		}
+		refs[ref] = *commit
+	}
+	delete(g.pending, remoteName)
+	return lines, nil
+}
+
+// CloneBare records the clone, which Cloned reports; the fake's own repository is unchanged.
+func (g *Git) CloneBare(ctx context.Context, url, bareDir string, progress func(line string)) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.clones[bareDir]; ok {
+		return fmt.Errorf("failed to clone repository: destination path '%s' already exists", bareDir)
+	}
+	g.clones[bareDir] = url
+	if progress != nil {
+		progress("Receiving objects: 100% (3/3), done.")
+	}
+	return nil
+}
+
+// Cloned returns the URL CloneBare cloned into bareDir, if any.
+func (g *Git) Cloned(bareDir string) (url string, ok bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	url, ok = g.clones[bareDir]
+	return url, ok
+}
+
+func (g *Git) MoveWorktree(ctx context.Context, worktreeAbsPath, newAbsPath string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if worktreeAbsPath == g.mainPath {
+		return fmt.Errorf("'%s' is a main working tree", worktreeAbsPath)
+	}
+	wt := g.worktreeAt(worktreeAbsPath)
+	if wt == nil {
+		return fmt.Errorf("'%s' is not a working tree", worktreeAbsPath)
+	}
+	if g.worktreeAt(newAbsPath) != nil {
+		return fmt.Errorf("'%s' already exists", newAbsPath)
+	}
+	if g.currentPath == wt.path {
+		g.currentPath = newAbsPath
+	}
+	wt.path = newAbsPath
+	return nil
+}
+
+func (g *Git) RepairWorktree(ctx context.Context, worktreeAbsPath string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	wt := g.worktreeAt(worktreeAbsPath)
+	if wt == nil {
+		return fmt.Errorf("'%s' is not a working tree", worktreeAbsPath)
+	}
+	wt.gitLinkBroken = false
+	return nil
+}
+
+func (g *Git) RemoveWorktree(ctx context.Context, worktreeAbsPath string, force bool) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if worktreeAbsPath == g.mainPath {
+		return fmt.Errorf("'%s' is a main working tree", worktreeAbsPath)
+	}
+	wt := g.worktreeAt(worktreeAbsPath)
+	if wt == nil {
+		return fmt.Errorf("'%s' is not a working tree", worktreeAbsPath)
+	}
+	if wt.dirty && !force {
+		return fmt.Errorf("'%s' contains modified or untracked files, use --force to delete it", worktreeAbsPath)
+	}
+	g.worktrees = slices.DeleteFunc(g.worktrees, func(w *worktree) bool { return w == wt })
+	return nil
+}
+
+func (g *Git) PruneWorktrees(ctx context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	kept := g.worktrees[:0]
+	for _, wt := range g.worktrees {
+		if wt.prunable == "" {
+			kept = append(kept, wt)
+		}
+	}
+	g.worktrees = kept
+	return nil
+}
+
+func (g *Git) InitSubmodules(ctx context.Context, worktreeAbsPath string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	wt := g.worktreeAt(worktreeAbsPath)
+	if wt == nil {
+		return fmt.Errorf("not a git repository: %s", worktreeAbsPath)
+	}
+	wt.submodules = nil
+	return nil
+}
+
+func (g *Git) UpdateRef(ctx context.Context, ref, sha string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	commit, err := g.resolve(sha)
+	if err != nil {
+		return err
+	}
+	if b, ok := g.branches[strings.TrimPrefix(ref, "refs/heads/")]; ok && strings.HasPrefix(ref, "refs/heads/") {
+		b.commit = commit
+		return nil
+	}
+	g.refs[ref] = commit
+	return nil
+}
+
+func (g *Git) DeleteRef(ctx context.Context, ref string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.refs[ref]; !ok {
+		return fmt.Errorf("ref %s does not exist", ref)
+	}
+	delete(g.refs, ref)
+	return nil
+}
+
+func (g *Git) RenameBranch(ctx context.Context, oldName, newName string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	b, ok := g.branches[oldName]
+	if !ok {
+		return fmt.Errorf("no branch named '%s'", oldName)
+	}
+	if _, exists := g.branches[newName]; exists {
+		return fmt.Errorf("a branch named '%s' already exists", newName)
+	}
+	delete(g.branches, oldName)
+	b.name = newName
+	g.branches[newName] = b
+	for _, wt := range g.worktrees {
+		if wt.branch == oldName {
+			wt.branch = newName
+		}
+	}
+	return nil
+}
+
+func (g *Git) DeleteBranch(ctx context.Context, branchName string, force bool) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	b, ok := g.branches[branchName]
+	if !ok {
+		return fmt.Errorf("branch '%s' not found", branchName)
+	}
+	if wt := g.worktreeForBranch(branchName); wt != nil {
+		return fmt.Errorf("cannot delete branch '%s' used by worktree at '%s'", branchName, wt.path)
+	}
+	if b.unmerged && !force {
+		return fmt.Errorf("failed to delete %s: %w", branchName, git.ErrNotMerged)
+	}
+	delete(g.branches, branchName)
+	return nil
+}
+
+func (g *Git) DeleteRemoteBranch(ctx context.Context, remote, branchName string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.remoteRefs[remote][branchName]; !ok {
+		return fmt.Errorf("unable to delete '%s': remote ref does not exist", branchName)
+	}
+	delete(g.remoteRefs[remote], branchName)
+	return nil
+}
+
+func (g *Git) Push(ctx context.Context, remote, branchName string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	b, ok := g.branches[branchName]
+	if !ok {
+		return fmt.Errorf("src refspec %s does not match any", branchName)
+	}
+	if g.remoteRefs[remote] == nil {
+		return fmt.Errorf("'%s' does not appear to be a git repository", remote)
+	}
+	g.remoteRefs[remote][branchName] = b.commit
+	b.upstream = remote + "/" + branchName
+	b.gone = false
+	return nil
+}
+
+func (g *Git) GetBranchDescription(ctx context.Context, branchName string) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if b, ok := g.branches[branchName]; ok {
+		return b.description, nil
+	}
+	return "", nil
+}
+
+func (g *Git) SetBranchDescription(ctx context.Context, branchName, description string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	b, ok := g.branches[branchName]
+	if !ok {
+		return fmt.Errorf("no branch named '%s'", branchName)
+	}
+	b.description = description
+	return nil
+}
+
+func (g *Git) SetBranchUpstream(ctx context.Context, branchName, upstream string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	b, ok := g.branches[branchName]
+	if !ok {
+		return fmt.Errorf("no branch named '%s'", branchName)
+	}
+	remote, name, _ := strings.Cut(upstream, "/")
+	if _, ok := g.remoteRefs[remote][name]; !ok {
+		return fmt.Errorf("the requested upstream branch '%s' does not exist", upstream)
+	}
+	b.upstream = upstream
+	b.gone = false
+	return nil
+}
+
+func (g *Git) GetWorktreeConfigState(ctx context.Context) (git.WorktreeConfigState, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.worktreeConfig, nil
+}
+
+func (g *Git) EnableWorktreeConfig(ctx context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.worktreeConfig = git.WorktreeConfigState{Enabled: true}
+	return nil
+}
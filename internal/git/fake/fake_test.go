@@ -0,0 +1,840 @@
+package fake
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jmcampanini/grove-cli/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func newTestFake() *Git {
+	return New("/ws/main", git.NewCommit("aaa1111", "Initial", testTime, "user"))
+}
+
+func worktreePaths(worktrees []git.Worktree) []string {
+	paths := make([]string, len(worktrees))
+	for i, wt := range worktrees {
+		paths[i] = wt.AbsolutePath
+	}
+	return paths
+}
+
+func TestNew(t *testing.T) {
+	g := newTestFake()
+
+	mainPath, err := g.GetMainWorktreePath(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "/ws/main", mainPath)
+
+	workspace, err := g.GetWorkspacePath(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "/ws", workspace)
+
+	current, err := g.GetCurrentBranch(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "main", current)
+
+	subject, err := g.GetCommitSubject(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "Initial", subject)
+}
+
+func TestGetWorktreeRoot(t *testing.T) {
+	tests := []struct {
+		name        string
+		currentPath string
+		want        string
+	}{
+		{name: "main worktree", currentPath: "/ws/main", want: "/ws/main"},
+		{name: "subdirectory of linked worktree", currentPath: "/ws/wt-auth/pkg/api", want: "/ws/wt-auth"},
+		{name: "outside repository", currentPath: "/tmp", want: ""},
+		{name: "sibling with shared prefix", currentPath: "/ws/wt-auth2", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestFake().
+				AddBranch("feature/auth", git.NewCommit("bbb2222", "Auth", testTime, "user")).
+				AddWorktree("/ws/wt-auth", "feature/auth").
+				SetCurrentPath(tt.currentPath)
+
+			got, err := g.GetWorktreeRoot(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestListWorktrees(t *testing.T) {
+	tagCommit := git.NewCommit("ccc3333", "Release", testTime, "user")
+	g := newTestFake().
+		AddBranch("feature/auth", git.NewCommit("bbb2222", "Auth", testTime, "user")).
+		AddWorktree("/ws/wt-auth", "feature/auth").
+		AddTag(git.NewTag("v1.0.0", tagCommit, "", "", "", time.Time{})).
+		AddDetachedWorktree("/ws/wt-release", tagCommit).
+		AddDetachedWorktree("/ws/wt-detached", git.NewCommit("ddd4444", "Detached", testTime, "user"))
+
+	worktrees, err := g.ListWorktrees(t.Context())
+	require.NoError(t, err)
+	require.Len(t, worktrees, 4)
+
+	assert.Equal(t, []string{"/ws/main", "/ws/wt-auth", "/ws/wt-release", "/ws/wt-detached"}, worktreePaths(worktrees))
+	assert.Equal(t, git.WorktreeRefTypeBranch, worktrees[1].Ref.Type())
+	assert.Equal(t, git.WorktreeRefTypeTag, worktrees[2].Ref.Type())
+	assert.Equal(t, git.WorktreeRefTypeCommit, worktrees[3].Ref.Type())
+
+	branch, ok := worktrees[1].Ref.FullBranch()
+	require.True(t, ok)
+	assert.Equal(t, "feature/auth", branch.Name)
+	assert.Equal(t, "/ws/wt-auth", branch.WorktreeAbsolutePath)
+}
+
+func TestSetBare(t *testing.T) {
+	g := New("/ws/.bare", git.NewCommit("aaa1111", "Initial", testTime, "user")).
+		SetBare().
+		AddWorktree("/ws/main", "main").
+		SetCurrentPath("/ws/main")
+
+	commonDir, err := g.GetCommonDir(t.Context())
+	require.NoError(t, err)
+	workspace, err := g.GetWorkspacePath(t.Context())
+	require.NoError(t, err)
+	worktrees, err := g.ListWorktrees(t.Context())
+	require.NoError(t, err)
+
+	assert.Equal(t, "/ws/.bare", commonDir)
+	assert.Equal(t, "/ws", workspace)
+	assert.Equal(t, []string{"/ws/.bare", "/ws/main"}, worktreePaths(worktrees))
+	assert.True(t, worktrees[0].IsBare)
+	assert.True(t, worktrees[0].IsMain)
+	assert.Nil(t, worktrees[0].Ref)
+	assert.Equal(t, git.WorktreeRefTypeBranch, worktrees[1].Ref.Type())
+}
+
+func TestSetBare_CheckOutHEADBranch(t *testing.T) {
+	g := New("/ws/.bare", git.NewCommit("aaa1111", "Initial", testTime, "user")).SetBare()
+
+	// the bare repository's HEAD is not a checkout
+	require.NoError(t, g.CreateWorktreeForExistingBranch(t.Context(), "main", "/ws/main"))
+
+	err := g.CreateWorktreeForExistingBranch(t.Context(), "main", "/ws/other")
+	assert.ErrorContains(t, err, "already checked out at '/ws/main'")
+}
+
+func TestCreateWorktreeForNewBranchFromRef(t *testing.T) {
+	remoteCommit := git.NewCommit("eee5555", "Remote main", testTime, "user")
+
+	tests := []struct {
+		name       string
+		branch     string
+		baseRef    string
+		wantCommit string
+		wantErr    string
+	}{
+		{name: "from HEAD", branch: "feature/new", baseRef: "", wantCommit: "aaa1111"},
+		{name: "from remote branch", branch: "feature/new", baseRef: "origin/main", wantCommit: "eee5555"},
+		{name: "existing branch", branch: "main", wantErr: "already exists"},
+		{name: "unknown ref", branch: "feature/new", baseRef: "nope", wantErr: "invalid reference"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestFake().AddRemoteRef("origin", "main", remoteCommit)
+
+			err := g.CreateWorktreeForNewBranchFromRef(t.Context(), tt.branch, "/ws/wt-new", tt.baseRef)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			branches, err := g.ListLocalBranches(t.Context())
+			require.NoError(t, err)
+			for _, b := range branches {
+				if b.Name == tt.branch {
+					assert.Equal(t, tt.wantCommit, b.Commit().SHA)
+					assert.Equal(t, "/ws/wt-new", b.WorktreeAbsolutePath)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateWorktreeForExistingBranch(t *testing.T) {
+	g := newTestFake().AddBranch("feature/auth", git.NewCommit("bbb2222", "Auth", testTime, "user"))
+
+	require.NoError(t, g.CreateWorktreeForExistingBranch(t.Context(), "feature/auth", "/ws/wt-auth"))
+
+	err := g.CreateWorktreeForExistingBranch(t.Context(), "feature/auth", "/ws/wt-auth-2")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already checked out")
+
+	err = g.CreateWorktreeForExistingBranch(t.Context(), "missing", "/ws/wt-missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid reference")
+}
+
+func TestFetchRemoteBranch(t *testing.T) {
+	prCommit := git.NewCommit("fff6666", "PR head", testTime, "user")
+	g := newTestFake().AddRemoteRef("origin", "pull/12/head", prCommit)
+
+	require.NoError(t, g.FetchRemoteBranch(t.Context(), "origin", "pull/12/head", "pr-branch"))
+
+	exists, err := g.BranchExists(t.Context(), "pr-branch", false)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	require.NoError(t, g.FetchRemoteBranch(t.Context(), "origin", "+pull/12/head", "forced-branch"))
+	exists, err = g.BranchExists(t.Context(), "forced-branch", false)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	err = g.FetchRemoteBranch(t.Context(), "origin", "pull/99/head", "missing")
+	require.Error(t, err)
+
+	err = g.FetchRemoteBranch(t.Context(), "origin", "pull/12/head", "main")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "checked out")
+}
+
+func TestBranchExists_CaseInsensitive(t *testing.T) {
+	g := newTestFake().AddBranch("Feature/Auth", git.NewCommit("bbb2222", "Auth", testTime, "user"))
+
+	exists, err := g.BranchExists(t.Context(), "feature/auth", false)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	exists, err = g.BranchExists(t.Context(), "feature/auth", true)
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestRemotes(t *testing.T) {
+	g := newTestFake().
+		AddRemoteRef("upstream", "main", git.NewCommit("aaa1111", "Initial", testTime, "user")).
+		AddRemoteRef("origin", "main", git.NewCommit("aaa1111", "Initial", testTime, "user")).
+		AddRemoteRef("origin", "pull/1/head", git.NewCommit("bbb2222", "PR", testTime, "user")).
+		SetRemoteHead("origin", "main")
+
+	remotes, err := g.ListRemotes(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"origin", "upstream"}, remotes)
+
+	branches, err := g.ListRemoteBranches(t.Context(), "origin")
+	require.NoError(t, err)
+	require.Len(t, branches, 1)
+	assert.Equal(t, "origin/main", branches[0].FullName())
+
+	defaultBranch, err := g.GetRepoDefaultBranch(t.Context(), "origin")
+	require.NoError(t, err)
+	assert.Equal(t, "main", defaultBranch)
+
+	_, err = g.GetRepoDefaultBranch(t.Context(), "missing")
+	assert.Error(t, err)
+}
+
+func TestResolveRepoDefaultBranch(t *testing.T) {
+	g := newTestFake().
+		AddRemoteRef("origin", "main", git.NewCommit("aaa1111", "Initial", testTime, "user")).
+		AddRemoteRef("upstream", "main", git.NewCommit("aaa1111", "Initial", testTime, "user")).
+		SetRemoteDefaultBranch("origin", "main")
+
+	before, err := g.GetRepoDefaultBranch(t.Context(), "origin")
+	require.NoError(t, err)
+	assert.Empty(t, before)
+
+	resolved, err := g.ResolveRepoDefaultBranch(t.Context(), "origin")
+	require.NoError(t, err)
+	assert.Equal(t, "main", resolved)
+
+	after, err := g.GetRepoDefaultBranch(t.Context(), "origin")
+	require.NoError(t, err)
+	assert.Equal(t, "main", after, "detected remote HEAD should be stored")
+
+	unknown, err := g.ResolveRepoDefaultBranch(t.Context(), "upstream")
+	require.NoError(t, err)
+	assert.Empty(t, unknown)
+
+	_, err = g.ResolveRepoDefaultBranch(t.Context(), "missing")
+	assert.Error(t, err)
+}
+
+func TestRenamedDefaultBranch(t *testing.T) {
+	commit := git.NewCommit("aaa1111", "Initial", testTime, "user")
+	g := newTestFake().
+		AddBranch("feature/auth", commit).
+		AddRemoteRef("origin", "master", commit).
+		AddRemoteRef("origin", "main", commit).
+		SetRemoteHead("origin", "master").
+		SetRemoteDefaultBranch("origin", "main").
+		SetUpstream("feature/auth", "origin/master", 0, 0)
+
+	current, err := g.GetRemoteHEAD(t.Context(), "origin")
+	require.NoError(t, err)
+	assert.Equal(t, "main", current)
+
+	require.NoError(t, g.SetRepoDefaultBranch(t.Context(), "origin", "main"))
+	stored, err := g.GetRepoDefaultBranch(t.Context(), "origin")
+	require.NoError(t, err)
+	assert.Equal(t, "main", stored)
+
+	require.NoError(t, g.SetBranchUpstream(t.Context(), "feature/auth", "origin/main"))
+	branches, err := g.ListLocalBranches(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "origin/main", branches[0].UpstreamName)
+
+	assert.Error(t, g.SetRepoDefaultBranch(t.Context(), "origin", "trunk"))
+	assert.Error(t, g.SetBranchUpstream(t.Context(), "feature/auth", "origin/trunk"))
+	assert.Error(t, g.SetBranchUpstream(t.Context(), "missing", "origin/main"))
+	_, err = g.GetRemoteHEAD(t.Context(), "missing")
+	assert.Error(t, err)
+}
+
+func TestMoveWorktree(t *testing.T) {
+	g := newTestFake().
+		AddBranch("feature/auth", git.NewCommit("bbb2222", "Auth", testTime, "user")).
+		AddWorktree("/ws/wt-auth", "feature/auth")
+
+	require.NoError(t, g.MoveWorktree(t.Context(), "/ws/wt-auth", "/ws/wt-login"))
+
+	worktrees, err := g.ListWorktrees(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/ws/main", "/ws/wt-login"}, worktreePaths(worktrees))
+
+	err = g.MoveWorktree(t.Context(), "/ws/main", "/ws/elsewhere")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "main working tree")
+
+	err = g.MoveWorktree(t.Context(), "/ws/missing", "/ws/elsewhere")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not a working tree")
+}
+
+func TestRemoveWorktree(t *testing.T) {
+	g := newTestFake().
+		AddBranch("feature/auth", git.NewCommit("bbb2222", "Auth", testTime, "user")).
+		AddBranch("fix/login", git.NewCommit("ccc3333", "Fix", testTime, "user")).
+		AddWorktree("/ws/wt-auth", "feature/auth").
+		AddWorktree("/ws/wt-login", "fix/login").
+		SetDirty("/ws/wt-login")
+
+	require.NoError(t, g.RemoveWorktree(t.Context(), "/ws/wt-auth", false))
+
+	err := g.RemoveWorktree(t.Context(), "/ws/wt-login", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "modified or untracked files")
+	require.NoError(t, g.RemoveWorktree(t.Context(), "/ws/wt-login", true))
+
+	worktrees, err := g.ListWorktrees(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/ws/main"}, worktreePaths(worktrees))
+	exists, err := g.BranchExists(t.Context(), "feature/auth", false)
+	require.NoError(t, err)
+	assert.True(t, exists, "the branch is kept")
+
+	err = g.RemoveWorktree(t.Context(), "/ws/main", true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "main working tree")
+	assert.Error(t, g.RemoveWorktree(t.Context(), "/ws/missing", false))
+}
+
+func TestUpdateRef(t *testing.T) {
+	g := newTestFake().AddBranch("feature/auth", git.NewCommit("bbb2222", "Auth", testTime, "user"))
+
+	require.NoError(t, g.UpdateRef(t.Context(), "refs/grove/archive/feature/auth", "bbb2222"))
+	require.NoError(t, g.DeleteBranch(t.Context(), "feature/auth", true))
+
+	sha, err := g.ResolveRef(t.Context(), "refs/grove/archive/feature/auth")
+	require.NoError(t, err)
+	assert.Equal(t, "bbb2222", sha)
+
+	require.NoError(t, g.DeleteRef(t.Context(), "refs/grove/archive/feature/auth"))
+	_, err = g.ResolveRef(t.Context(), "refs/grove/archive/feature/auth")
+	assert.Error(t, err)
+	assert.Error(t, g.DeleteRef(t.Context(), "refs/grove/archive/feature/auth"))
+	assert.Error(t, g.UpdateRef(t.Context(), "refs/grove/archive/x", "missing"))
+
+	g.AddBranch("feature/login", git.NewCommit("ccc3333", "Login", testTime, "user"))
+	require.NoError(t, g.UpdateRef(t.Context(), "refs/heads/feature/login", "aaa1111"))
+	sha, err = g.ResolveRef(t.Context(), "feature/login")
+	require.NoError(t, err)
+	assert.Equal(t, "aaa1111", sha, "updating refs/heads/<name> moves the branch")
+}
+
+func TestRenameBranch(t *testing.T) {
+	g := newTestFake().
+		AddBranch("feature/auth", git.NewCommit("bbb2222", "Auth", testTime, "user")).
+		AddBranch("taken", git.NewCommit("ccc3333", "Taken", testTime, "user")).
+		AddWorktree("/ws/wt-auth", "feature/auth")
+
+	require.NoError(t, g.RenameBranch(t.Context(), "feature/auth", "feature/login"))
+
+	worktrees, err := g.ListWorktrees(t.Context())
+	require.NoError(t, err)
+	branch, ok := worktrees[1].Ref.FullBranch()
+	require.True(t, ok)
+	assert.Equal(t, "feature/login", branch.Name)
+
+	exists, err := g.BranchExists(t.Context(), "feature/auth", false)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	err = g.RenameBranch(t.Context(), "feature/login", "taken")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+}
+
+func TestDeleteBranch(t *testing.T) {
+	commit := git.NewCommit("bbb2222", "Work", testTime, "user")
+	g := newTestFake().
+		AddBranch("merged", commit).
+		AddBranch("unmerged", commit).
+		AddBranch("feature/auth", commit).
+		AddWorktree("/ws/wt-auth", "feature/auth").
+		SetUnmerged("unmerged")
+
+	require.NoError(t, g.DeleteBranch(t.Context(), "merged", false))
+	exists, err := g.BranchExists(t.Context(), "merged", false)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	err = g.DeleteBranch(t.Context(), "unmerged", false)
+	require.ErrorIs(t, err, git.ErrNotMerged)
+	require.NoError(t, g.DeleteBranch(t.Context(), "unmerged", true))
+
+	err = g.DeleteBranch(t.Context(), "feature/auth", true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "used by worktree")
+
+	err = g.DeleteBranch(t.Context(), "missing", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestDeleteRemoteBranch(t *testing.T) {
+	g := newTestFake().AddRemoteRef("origin", "feature/auth", git.NewCommit("bbb2222", "Auth", testTime, "user"))
+
+	require.NoError(t, g.DeleteRemoteBranch(t.Context(), "origin", "feature/auth"))
+
+	_, err := g.ResolveRef(t.Context(), "origin/feature/auth")
+	assert.Error(t, err)
+	err = g.DeleteRemoteBranch(t.Context(), "origin", "feature/auth")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "remote ref does not exist")
+}
+
+func TestPush(t *testing.T) {
+	g := newTestFake().
+		AddBranch("feature/auth", git.NewCommit("bbb2222", "Auth", testTime, "user")).
+		AddRemoteRef("origin", "main", git.NewCommit("abc1234", "Initial", testTime, "user"))
+
+	require.NoError(t, g.Push(t.Context(), "origin", "feature/auth"))
+
+	sha, err := g.ResolveRef(t.Context(), "origin/feature/auth")
+	require.NoError(t, err)
+	assert.Equal(t, "bbb2222", sha)
+	branches, err := g.ListLocalBranches(t.Context())
+	require.NoError(t, err)
+	for _, b := range branches {
+		if b.Name == "feature/auth" {
+			assert.Equal(t, "origin/feature/auth", b.UpstreamName)
+		}
+	}
+	assert.Error(t, g.Push(t.Context(), "origin", "missing"))
+	assert.Error(t, g.Push(t.Context(), "upstream", "feature/auth"))
+}
+
+func TestBranchDescription(t *testing.T) {
+	g := newTestFake().AddBranch("feature/auth", git.NewCommit("bbb2222", "Auth", testTime, "user"))
+
+	description, err := g.GetBranchDescription(t.Context(), "feature/auth")
+	require.NoError(t, err)
+	assert.Empty(t, description)
+
+	require.NoError(t, g.SetBranchDescription(t.Context(), "feature/auth", "Adds login.\n\nUses OAuth."))
+	description, err = g.GetBranchDescription(t.Context(), "feature/auth")
+	require.NoError(t, err)
+	assert.Equal(t, "Adds login.\n\nUses OAuth.", description)
+
+	err = g.SetBranchDescription(t.Context(), "missing", "text")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no branch named")
+}
+
+func TestWorktreeConfig(t *testing.T) {
+	g := newTestFake().SetWorktreeConfigState(git.WorktreeConfigState{SharedBare: true, SharedWorktree: "/ws/main"})
+
+	state, err := g.GetWorktreeConfigState(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, git.WorktreeConfigState{SharedBare: true, SharedWorktree: "/ws/main"}, state)
+
+	require.NoError(t, g.EnableWorktreeConfig(t.Context()))
+	state, err = g.GetWorktreeConfigState(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, git.WorktreeConfigState{Enabled: true}, state)
+}
+
+func TestGetVersion(t *testing.T) {
+	g := newTestFake()
+
+	version, err := g.GetVersion(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, DefaultVersion, version)
+
+	version, err = g.SetVersion("2.20.1").GetVersion(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "2.20.1", version)
+}
+
+func TestSetPrunable(t *testing.T) {
+	g := newTestFake().
+		AddBranch("feature/auth", git.NewCommit("bbb2222", "Auth", testTime, "user")).
+		AddWorktree("/ws/wt-auth", "feature/auth").
+		SetPrunable("/ws/wt-auth", "gitdir file points to non-existent location")
+
+	worktrees, err := g.ListWorktrees(t.Context())
+
+	require.NoError(t, err)
+	assert.Empty(t, worktrees[0].Prunable)
+	assert.Equal(t, "gitdir file points to non-existent location", worktrees[1].Prunable)
+}
+
+func TestSetUpstreamGone(t *testing.T) {
+	g := newTestFake().
+		AddBranch("feature/auth", git.NewCommit("bbb2222", "Auth", testTime, "user")).
+		SetUpstream("feature/auth", "origin/feature/auth", 0, 0).
+		SetUpstreamGone("feature/auth").
+		AddWorktree("/ws/wt-auth", "feature/auth")
+
+	worktrees, err := g.ListWorktrees(t.Context())
+
+	require.NoError(t, err)
+	require.Len(t, worktrees, 2)
+	main, _ := worktrees[0].Ref.FullBranch()
+	assert.False(t, main.Gone)
+	auth, _ := worktrees[1].Ref.FullBranch()
+	assert.True(t, auth.Gone)
+}
+
+func TestGetRemoteURL(t *testing.T) {
+	g := newTestFake().SetRemoteURL("origin", "git@github.com:acme/widgets.git")
+
+	url, err := g.GetRemoteURL(t.Context(), "origin")
+	require.NoError(t, err)
+	assert.Equal(t, "git@github.com:acme/widgets.git", url)
+
+	_, err = g.GetRemoteURL(t.Context(), "upstream")
+	assert.Error(t, err)
+}
+
+func TestPruneWorktrees(t *testing.T) {
+	g := newTestFake().
+		AddBranch("feature/auth", git.NewCommit("bbb2222", "Auth", testTime, "user")).
+		AddBranch("feature/api", git.NewCommit("ccc3333", "API", testTime, "user")).
+		AddWorktree("/ws/wt-auth", "feature/auth").
+		AddWorktree("/ws/wt-api", "feature/api").
+		SetPrunable("/ws/wt-auth", "gitdir file points to non-existent location")
+
+	require.NoError(t, g.PruneWorktrees(t.Context()))
+
+	worktrees, err := g.ListWorktrees(t.Context())
+	require.NoError(t, err)
+	require.Len(t, worktrees, 2)
+	assert.Equal(t, "/ws/wt-api", worktrees[1].AbsolutePath)
+}
+
+func TestFetchRemote_PendingRefs(t *testing.T) {
+	g := newTestFake().
+		AddRemoteRef("origin", "main", git.NewCommit("aaa1111", "Initial", testTime, "user")).
+		AddRemoteRef("origin", "old", git.NewCommit("bbb2222", "Old", testTime, "user")).
+		PushRemoteRef("origin", "new", git.NewCommit("ccc3333", "New", testTime, "user")).
+		DeleteRemoteRef("origin", "old")
+
+	// nothing changes until the remote is fetched
+	branches, err := g.ListRemoteBranches(t.Context(), "origin")
+	require.NoError(t, err)
+	assert.Len(t, branches, 2)
+
+	var progress []string
+	_, err = g.FetchRemote(t.Context(), "origin", func(line string) { progress = append(progress, line) })
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		" * [new branch]      new -> origin/new",
+		" - [deleted]         (none) -> origin/old",
+	}, progress)
+
+	branches, err = g.ListRemoteBranches(t.Context(), "origin")
+	require.NoError(t, err)
+	require.Len(t, branches, 2)
+	assert.Equal(t, "main", branches[0].Name)
+	assert.Equal(t, "new", branches[1].Name)
+
+	_, err = g.FetchRemote(t.Context(), "upstream", nil)
+	assert.Error(t, err)
+}
+
+func TestCloneBare(t *testing.T) {
+	g := newTestFake()
+
+	var progress []string
+	err := g.CloneBare(t.Context(), "git@github.com:o/r.git", "/ws/r/.bare", func(line string) { progress = append(progress, line) })
+	require.NoError(t, err)
+	assert.NotEmpty(t, progress)
+
+	url, ok := g.Cloned("/ws/r/.bare")
+	assert.True(t, ok)
+	assert.Equal(t, "git@github.com:o/r.git", url)
+	_, ok = g.Cloned("/ws/other/.bare")
+	assert.False(t, ok)
+
+	err = g.CloneBare(t.Context(), "git@github.com:o/r.git", "/ws/r/.bare", nil)
+	assert.ErrorContains(t, err, "already exists")
+}
+
+func TestResolveRef(t *testing.T) {
+	g := newTestFake().
+		AddBranch("feature/auth", git.NewCommit("bbb2222", "Auth", testTime, "user")).
+		AddRemoteRef("origin", "main", git.NewCommit("ccc3333", "Remote", testTime, "user")).
+		AddTag(git.NewTag("v1.0.0", git.NewCommit("ddd4444", "Release", testTime, "user"), "", "", "", testTime))
+
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{ref: "feature/auth", want: "bbb2222"},
+		{ref: "origin/main", want: "ccc3333"},
+		{ref: "v1.0.0", want: "ddd4444"},
+		{ref: "bbb2", want: "bbb2222"},
+		{ref: "missing", wantErr: true},
+		{ref: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := g.ResolveRef(t.Context(), tt.ref)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetPager(t *testing.T) {
+	g := newTestFake()
+
+	pager, err := g.GetPager(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "cat", pager)
+
+	pager, err = g.SetPager("less -R").GetPager(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "less -R", pager)
+}
+
+func TestGetUserName(t *testing.T) {
+	g := newTestFake()
+
+	name, err := g.GetUserName(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "user", name)
+
+	name, err = g.SetUserName("").GetUserName(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, name)
+}
+
+func TestGetAheadBehind(t *testing.T) {
+	g := newTestFake().
+		AddBranch("feature", git.NewCommit("bbb2222", "Feature", testTime, "user")).
+		AddRemoteRef("origin", "main", git.NewCommit("ccc3333", "Upstream", testTime, "user")).
+		SetAheadBehind("feature", "origin/main", 2, 5)
+
+	ahead, behind, err := g.GetAheadBehind(t.Context(), "feature", "origin/main")
+	require.NoError(t, err)
+	assert.Equal(t, 2, ahead)
+	assert.Equal(t, 5, behind)
+
+	ahead, behind, err = g.GetAheadBehind(t.Context(), "main", "origin/main")
+	require.NoError(t, err)
+	assert.Zero(t, ahead)
+	assert.Zero(t, behind)
+
+	_, _, err = g.GetAheadBehind(t.Context(), "feature", "origin/nope")
+	assert.Error(t, err)
+}
+
+func TestListUnreachableCommits(t *testing.T) {
+	local := git.NewCommit("bbb2222", "Local work", testTime, "user")
+	g := newTestFake().
+		AddBranch("feature", local).
+		SetUnreachable("feature", local)
+
+	commits, err := g.ListUnreachableCommits(t.Context(), "feature")
+	require.NoError(t, err)
+	assert.Equal(t, []git.Commit{local}, commits)
+
+	commits, err = g.ListUnreachableCommits(t.Context(), "main")
+	require.NoError(t, err)
+	assert.Empty(t, commits)
+
+	_, err = g.ListUnreachableCommits(t.Context(), "nope")
+	assert.Error(t, err)
+}
+
+func TestWorktreeHealth(t *testing.T) {
+	g := newTestFake().
+		AddBranch("feature", git.NewCommit("bbb2222", "Feature", testTime, "user")).
+		AddWorktree("/ws/feature", "feature").
+		SetGitLinkBroken("/ws/feature").
+		SetUninitializedSubmodules("/ws/feature", "vendor/lib")
+
+	gitDir, err := g.GetWorktreeGitDir(t.Context(), "/ws/main")
+	require.NoError(t, err)
+	assert.Equal(t, "/ws/main/.git", gitDir)
+
+	_, err = g.GetWorktreeGitDir(t.Context(), "/ws/feature")
+	assert.Error(t, err)
+	require.NoError(t, g.RepairWorktree(t.Context(), "/ws/feature"))
+	gitDir, err = g.GetWorktreeGitDir(t.Context(), "/ws/feature")
+	require.NoError(t, err)
+	assert.Equal(t, "/ws/main/.git/worktrees/feature", gitDir)
+
+	missing, err := g.ListUninitializedSubmodules(t.Context(), "/ws/feature")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vendor/lib"}, missing)
+	require.NoError(t, g.InitSubmodules(t.Context(), "/ws/feature"))
+	missing, err = g.ListUninitializedSubmodules(t.Context(), "/ws/feature")
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+}
+
+func TestDeleteBranchRef(t *testing.T) {
+	g := newTestFake().
+		AddBranch("feature", git.NewCommit("bbb2222", "Feature", testTime, "user")).
+		AddWorktree("/ws/feature", "feature").
+		DeleteBranchRef("feature")
+
+	worktrees, err := g.ListWorktrees(t.Context())
+
+	require.NoError(t, err)
+	require.Len(t, worktrees, 2)
+	assert.True(t, worktrees[1].BranchMissing)
+	branch, ok := worktrees[1].Ref.FullBranch()
+	require.True(t, ok)
+	assert.Equal(t, "feature", branch.Name)
+}
+
+func TestGetLastActivity(t *testing.T) {
+	g := newTestFake().
+		AddBranch("feature", git.NewCommit("bbb2222", "Feature", testTime, "user")).
+		AddWorktree("/ws/feature", "feature").
+		SetLastActivity("/ws/feature", testTime.Add(time.Hour))
+
+	latest, err := g.GetLastActivity(t.Context(), "/ws/feature")
+	require.NoError(t, err)
+	assert.Equal(t, testTime.Add(time.Hour), latest)
+
+	latest, err = g.GetLastActivity(t.Context(), "/ws/main")
+	require.NoError(t, err)
+	assert.True(t, latest.IsZero())
+
+	_, err = g.GetLastActivity(t.Context(), "/ws/missing")
+	assert.Error(t, err)
+}
+
+func TestSyncBranch(t *testing.T) {
+	newSyncFake := func() *Git {
+		return newTestFake().
+			AddBranch("pr-12", git.NewCommit("aaa1111", "Old", testTime, "user")).
+			AddWorktree("/ws/pr-12", "pr-12").
+			AddRemoteRef("origin", "pull/12/head", git.NewCommit("bbb2222", "New", testTime, "user"))
+	}
+
+	t.Run("fast-forward", func(t *testing.T) {
+		g := newSyncFake()
+		sha, err := g.FetchRef(t.Context(), "origin", "pull/12/head")
+		require.NoError(t, err)
+		assert.Equal(t, "bbb2222", sha)
+
+		require.NoError(t, g.FastForward(t.Context(), "/ws/pr-12", sha))
+
+		head, err := g.ResolveRef(t.Context(), "pr-12")
+		require.NoError(t, err)
+		assert.Equal(t, "bbb2222", head)
+	})
+
+	t.Run("diverged needs a rebase", func(t *testing.T) {
+		g := newSyncFake().SetDiverged("/ws/pr-12")
+		sha, err := g.FetchRef(t.Context(), "origin", "pull/12/head")
+		require.NoError(t, err)
+
+		err = g.FastForward(t.Context(), "/ws/pr-12", sha)
+		assert.ErrorIs(t, err, git.ErrNotFastForward)
+		require.NoError(t, g.Rebase(t.Context(), "/ws/pr-12", sha))
+		require.NoError(t, g.FastForward(t.Context(), "/ws/pr-12", sha))
+	})
+
+	t.Run("rebase conflicts", func(t *testing.T) {
+		g := newSyncFake().SetRebaseConflicts("/ws/pr-12", "a.go")
+		sha, err := g.FetchRef(t.Context(), "origin", "pull/12/head")
+		require.NoError(t, err)
+
+		err = g.Rebase(t.Context(), "/ws/pr-12", sha)
+
+		var conflict *git.ConflictError
+		require.ErrorAs(t, err, &conflict)
+		assert.Equal(t, []string{"a.go"}, conflict.Files)
+		head, err := g.ResolveRef(t.Context(), "pr-12")
+		require.NoError(t, err)
+		assert.Equal(t, "aaa1111", head)
+	})
+
+	t.Run("rebase in progress", func(t *testing.T) {
+		g := newSyncFake().SetRebaseInProgress("/ws/pr-12", true, "a.go")
+
+		inProgress, err := g.IsRebaseInProgress(t.Context(), "/ws/pr-12")
+		require.NoError(t, err)
+		assert.True(t, inProgress)
+		files, err := g.ListConflictedFiles(t.Context(), "/ws/pr-12")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a.go"}, files)
+
+		g.SetRebaseInProgress("/ws/pr-12", false)
+		inProgress, err = g.IsRebaseInProgress(t.Context(), "/ws/pr-12")
+		require.NoError(t, err)
+		assert.False(t, inProgress)
+		files, err = g.ListConflictedFiles(t.Context(), "/ws/pr-12")
+		require.NoError(t, err)
+		assert.Empty(t, files)
+	})
+
+	t.Run("dirty", func(t *testing.T) {
+		g := newSyncFake()
+
+		dirty, err := g.IsDirty(t.Context(), "/ws/pr-12")
+		require.NoError(t, err)
+		assert.False(t, dirty)
+
+		g.SetDirty("/ws/pr-12")
+		dirty, err = g.IsDirty(t.Context(), "/ws/pr-12")
+		require.NoError(t, err)
+		assert.True(t, dirty)
+		_, err = g.IsDirty(t.Context(), "/ws/missing")
+		assert.Error(t, err)
+	})
+
+	t.Run("missing remote ref", func(t *testing.T) {
+		_, err := newSyncFake().FetchRef(t.Context(), "origin", "pull/99/head")
+		assert.Error(t, err)
+	})
+}
@@ -1,6 +1,11 @@
 package git
 
-import "time"
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
 
 type WorktreeRefType int
 
@@ -18,8 +23,12 @@ type WorktreeRef interface {
 }
 
 type Worktree struct {
-	AbsolutePath string
-	Ref          WorktreeRef
+	AbsolutePath  string
+	BranchMissing bool   // the checked-out branch has no ref (unborn, or deleted behind git's back); Ref then holds only its name
+	IsBare        bool   // the entry is a bare repository with no working tree; Ref is nil
+	IsMain        bool   // the main worktree (or bare repository) that linked worktrees belong to
+	Prunable      string // why git would prune the worktree's administrative files (e.g. its directory is gone); empty otherwise
+	Ref           WorktreeRef
 }
 
 type Commit struct {
@@ -88,6 +97,7 @@ type LocalBranch struct {
 	Ahead                int // Commits ahead of upstream
 	Behind               int // Commits behind upstream
 	commit               Commit
+	Gone                 bool // The upstream is configured but its remote branch no longer exists
 	IsCheckedOut         bool
 	Name                 string // Short branch name (e.g., "main", not "refs/heads/main")
 	UpstreamName         string // Short upstream name (e.g., "origin/main"), empty if no upstream
@@ -130,33 +140,103 @@ func NewRemoteBranch(name, remoteName string, commit Commit) RemoteBranch {
 func (b RemoteBranch) Commit() Commit   { return b.commit }
 func (b RemoteBranch) FullName() string { return b.RemoteName + "/" + b.Name }
 
+// ErrNotFastForward is returned by FastForward when the branch has commits the target lacks.
+var ErrNotFastForward = errors.New("not possible to fast-forward")
+
+// ErrNotMerged is returned by DeleteBranch when the branch has commits that are not merged into HEAD
+// or its upstream, which deleting it would lose.
+var ErrNotMerged = errors.New("branch is not fully merged")
+
+// ConflictError is returned by Rebase when the rebase stopped on conflicts. The rebase is aborted,
+// leaving the worktree as it was.
+type ConflictError struct {
+	Files []string // paths with conflicts, relative to the worktree
+}
+
+func (e *ConflictError) Error() string {
+	return "rebase stopped on conflicts in " + strings.Join(e.Files, ", ")
+}
+
+// WorktreeConfigState describes how a repository's git config is split between its worktrees.
+type WorktreeConfigState struct {
+	// Enabled is true when extensions.worktreeConfig is on, so git config --worktree writes to a
+	// config.worktree file private to the current worktree instead of the config shared by all of them.
+	Enabled bool
+	// SharedBare is true when core.bare=true is set in the shared config.
+	SharedBare bool
+	// SharedWorktree is core.worktree as set in the shared config, "" if unset.
+	SharedWorktree string
+}
+
+// Git is grove's view of a git repository. Every method runs its git commands under ctx, each also
+// bounded by the client's timeout, so cancelling ctx (e.g., on Ctrl-C) stops them.
 type Git interface {
 
 	// GetCurrentBranch returns the current branch name.
 	// Returns "HEAD" if in detached HEAD state.
-	GetCurrentBranch() (string, error)
+	GetCurrentBranch(ctx context.Context) (string, error)
+
+	// GetCommonDir returns the absolute path to the git directory shared by all worktrees
+	// (the main worktree's .git directory).
+	GetCommonDir(ctx context.Context) (string, error)
 
 	// GetMainWorktreePath returns the absolute path to the main (primary) worktree.
 	// This is the worktree associated with the .git directory, not a linked worktree.
-	GetMainWorktreePath() (string, error)
+	// For a bare repository (core.bare), whose worktrees are all linked, it is the repository itself,
+	// e.g. repo.git or .bare, which git lists in the main worktree's place.
+	GetMainWorktreePath(ctx context.Context) (string, error)
 
-	// GetWorkspacePath returns the parent directory of the main worktree.
+	// GetWorkspacePath returns the parent directory of the main worktree, or of the bare repository.
 	// This is typically the directory containing all worktrees for a repository.
 	// Returns an error if the main worktree path cannot be determined.
-	GetWorkspacePath() (string, error)
+	GetWorkspacePath(ctx context.Context) (string, error)
 
 	// GetWorktreeRoot returns the absolute path to the root of the git tree.
 	// If not in a git repository, returns ("", nil).
 	// Returns an error only if the git command itself fails (e.g., git not installed).
 	// TODO: consolidate the naming scheme to use path and workspace and worktree consistently
-	GetWorktreeRoot() (string, error)
+	GetWorktreeRoot(ctx context.Context) (string, error)
 
 	// GetCommitSubject returns the first line of the commit message for HEAD.
-	GetCommitSubject() (string, error)
+	GetCommitSubject(ctx context.Context) (string, error)
+
+	// GetWorktreeGitDir returns the absolute path of the git directory used by the worktree at the given path
+	// (e.g., "<common dir>/worktrees/<name>" for a linked worktree).
+	// Returns an error if the worktree's .git link does not lead to a git directory.
+	GetWorktreeGitDir(ctx context.Context, worktreeAbsPath string) (string, error)
+
+	// GetLastActivity returns when the worktree at the given path was last worked in: the most recent
+	// modification time among its tracked files and its HEAD, index, and HEAD reflog (the last git action).
+	// Results are cached for the lifetime of the client.
+	GetLastActivity(ctx context.Context, worktreeAbsPath string) (time.Time, error)
+
+	// ListUninitializedSubmodules returns the paths of the submodules in the worktree that are not initialized.
+	ListUninitializedSubmodules(ctx context.Context, worktreeAbsPath string) ([]string, error)
+
+	// IsRebaseInProgress reports whether a rebase stopped in the worktree at the given path
+	// and is waiting to be continued or aborted.
+	IsRebaseInProgress(ctx context.Context, worktreeAbsPath string) (bool, error)
+
+	// IsDirty reports whether the worktree at the given path has modified, staged, or untracked files.
+	// Ignored files do not count.
+	IsDirty(ctx context.Context, worktreeAbsPath string) (bool, error)
+
+	// ListConflictedFiles returns the paths with unresolved merge conflicts in the worktree at the given path,
+	// relative to the worktree. Returns an empty list if there are none.
+	ListConflictedFiles(ctx context.Context, worktreeAbsPath string) ([]string, error)
+
+	// GetPager returns the pager command git uses: GIT_PAGER, core.pager, PAGER, or "less".
+	GetPager(ctx context.Context) (string, error)
+
+	// GetUserName returns the configured git user.name, or "" if it is not set.
+	GetUserName(ctx context.Context) (string, error)
+
+	// GetVersion returns the version of the installed git CLI (e.g., "2.43.0").
+	GetVersion(ctx context.Context) (string, error)
 
 	// GetDefaultRemote returns the default remote name.
 	// Returns the value of git config remote.pushDefault if set, otherwise returns the fallback parameter.
-	GetDefaultRemote(fallback string) (string, error)
+	GetDefaultRemote(ctx context.Context, fallback string) (string, error)
 
 	// GetRepoDefaultBranch returns the default branch name by querying the remote's HEAD reference.
 	// Returns the branch name (e.g., "main") if the remote HEAD is configured.
@@ -166,60 +246,197 @@ type Git interface {
 	//   - Not in a git repository
 	//   - Git command fails (e.g., git not installed)
 	// This works in both regular repositories and worktrees.
-	GetRepoDefaultBranch(remoteName string) (string, error)
+	GetRepoDefaultBranch(ctx context.Context, remoteName string) (string, error)
+
+	// ResolveRepoDefaultBranch returns the default branch like GetRepoDefaultBranch, but when the remote HEAD
+	// is not set it runs `git remote set-head <remote> --auto` once to detect it from the remote.
+	// Results are cached for the lifetime of the client.
+	// Returns ("", nil) if the remote HEAD still cannot be determined (e.g., in dry-run mode).
+	// Will mutate the current git state.
+	ResolveRepoDefaultBranch(ctx context.Context, remoteName string) (string, error)
+
+	// GetRemoteHEAD asks the remote which branch its HEAD points at, as git ls-remote --symref does.
+	// Unlike GetRepoDefaultBranch, this contacts the remote, so it sees a default branch renamed since the
+	// last fetch. Returns ("", nil) if the remote does not report a HEAD branch.
+	GetRemoteHEAD(ctx context.Context, remoteName string) (string, error)
+
+	// SetRepoDefaultBranch points the local remote HEAD (refs/remotes/<remote>/HEAD) at branchName,
+	// as git remote set-head does. The branch must have been fetched.
+	// Will mutate the current git state.
+	SetRepoDefaultBranch(ctx context.Context, remoteName, branchName string) error
 
 	// ListLocalBranches returns detailed information about all local branches.
 	// This includes the branch name, commit SHA, worktree path (if checked out), upstream tracking, and commit subject.
-	ListLocalBranches() ([]LocalBranch, error)
+	ListLocalBranches(ctx context.Context) ([]LocalBranch, error)
 
 	// ListRemoteBranches returns detailed information about all branches on the specified remote.
 	// Uses local refs (requires prior fetch to be current).
-	ListRemoteBranches(remoteName string) ([]RemoteBranch, error)
+	ListRemoteBranches(ctx context.Context, remoteName string) ([]RemoteBranch, error)
 
 	// ListRemotes returns the names of all configured remotes.
-	ListRemotes() ([]string, error)
+	ListRemotes(ctx context.Context) ([]string, error)
+
+	// GetRemoteURL returns the fetch URL of the remote (e.g., "git@github.com:acme/widgets.git").
+	// Returns an error if the remote does not exist.
+	GetRemoteURL(ctx context.Context, remoteName string) (string, error)
 
 	// ListTags returns all local annotated and lightweight tags with their metadata.
 	// Does NOT sync from remote - call SyncTags() first if needed.
 	// Returns both annotated and lightweight tags.
-	ListTags() ([]Tag, error)
+	ListTags(ctx context.Context) ([]Tag, error)
 
 	// BranchExists checks if a branch with the given name already exists.
-	BranchExists(branchName string, caseInsensitive bool) (bool, error)
+	BranchExists(ctx context.Context, branchName string, caseInsensitive bool) (bool, error)
+
+	// ResolveRef returns the full SHA of the commit a ref points to.
+	// The ref may be a branch, remote branch (e.g., "origin/main"), tag, or SHA.
+	// Returns an error if the ref does not name a commit.
+	ResolveRef(ctx context.Context, ref string) (string, error)
+
+	// GetAheadBehind returns how many commits branchName has that baseRef lacks (ahead) and how many
+	// baseRef has that branchName lacks (behind). baseRef may be any ref, e.g. "origin/main".
+	// Returns an error if either does not name a commit.
+	GetAheadBehind(ctx context.Context, branchName, baseRef string) (ahead, behind int, err error)
+
+	// ListUnreachableCommits returns the commits on a local branch that no other branch, tag, or remote
+	// branch reaches, newest first: the commits that would become unreachable if the branch were deleted.
+	// Returns an error if the branch does not exist.
+	ListUnreachableCommits(ctx context.Context, branchName string) ([]Commit, error)
 
 	// ListWorktrees returns detailed information about all worktrees in the repository.
 	// This includes the path, associated branch (if any), HEAD commit, and various flags.
-	ListWorktrees() ([]Worktree, error)
+	// Worktrees whose directories are missing are included with Prunable set.
+	ListWorktrees(ctx context.Context) ([]Worktree, error)
 
 	// CreateWorktreeForNewBranch atomically creates a new branch and worktree.
 	// The branch is created starting from the current HEAD and the worktree is created at the given absolute path.
 	// Will mutate the current git state.
 	// TODO: consolidate with CreateWorktreeForNewBranchFromRef
-	CreateWorktreeForNewBranch(newBranchName, worktreeAbsPath string) error
+	CreateWorktreeForNewBranch(ctx context.Context, newBranchName, worktreeAbsPath string) error
 
 	// CreateWorktreeForNewBranchFromRef atomically creates a new branch and worktree.
 	// The branch is created starting from the specified baseRef (or HEAD if empty).
 	// The worktree is created at the given absolute path.
 	// Will mutate the current git state.
 	// TODO: consolidate with CreateWorktreeForNewBranch
-	CreateWorktreeForNewBranchFromRef(newBranchName, worktreeAbsPath, baseRef string) error
+	CreateWorktreeForNewBranchFromRef(ctx context.Context, newBranchName, worktreeAbsPath, baseRef string) error
 
 	// CreateWorktreeForExistingBranch creates a worktree for an existing branch.
 	// The worktree is created at the given absolute path and checks out the specified branch.
 	// Will mutate the current git state.
 	// TODO: consider merging this with the other CreateWorktree- method
-	CreateWorktreeForExistingBranch(branchName, worktreeAbsPath string) error
+	CreateWorktreeForExistingBranch(ctx context.Context, branchName, worktreeAbsPath string) error
 
 	// FetchRemoteBranch fetches a remote reference and stores it as a local branch.
+	// A remoteRef starting with "+" force-updates localRef even when the remote reference was rewritten,
+	// as remote-tracking refs under refs/remotes/ always should be.
 	// Will mutate the current git state.
-	FetchRemoteBranch(remote, remoteRef, localRef string) error
+	FetchRemoteBranch(ctx context.Context, remote, remoteRef, localRef string) error
 
 	// SyncTags fetches and prunes tags from the remote.
 	// If remoteName is empty, uses GetDefaultRemote("origin").
 	// Will mutate the current git state.
-	SyncTags(remoteName string) error
+	SyncTags(ctx context.Context, remoteName string) error
+
+	// FetchRef fetches a single ref from a remote (e.g., "pull/123/head") without storing it in a local ref,
+	// and returns the SHA of the fetched commit.
+	// Returns ("", nil) in dry-run mode, where nothing is fetched.
+	// Will mutate the current git state.
+	FetchRef(ctx context.Context, remote, remoteRef string) (string, error)
+
+	// FastForward fast-forwards the branch checked out in the worktree at the given path to ref.
+	// Returns an error wrapping ErrNotFastForward if the branch has commits ref does not.
+	// Will mutate the current git state.
+	FastForward(ctx context.Context, worktreeAbsPath, ref string) error
+
+	// Rebase rebases the branch checked out in the worktree at the given path onto ref.
+	// If the rebase stops on conflicts it is aborted and a *ConflictError is returned.
+	// Will mutate the current git state.
+	Rebase(ctx context.Context, worktreeAbsPath, ref string) error
+
+	// CloneBare clones url into bareDir as a bare repository whose origin fetches like a normal clone's, into
+	// remote-tracking branches (a bare clone otherwise fetches nothing but its local branches). Fetch origin
+	// afterwards to create them. Runs from the current directory, which need not be in a repository.
+	// If progress is not nil, it is called with each line of progress git reports, as the clone runs.
+	// Will mutate the filesystem.
+	CloneBare(ctx context.Context, url, bareDir string, progress func(line string)) error
 
 	// FetchRemote fetches from a remote with full sync (prune refs, prune tags, fetch tags).
+	// If progress is not nil, it is called with each line of progress git reports, as the fetch runs.
+	// Will mutate the current git state.
+	FetchRemote(ctx context.Context, remoteName string, progress func(line string)) (output string, err error)
+
+	// MoveWorktree moves a linked worktree to a new absolute path, keeping its branch checked out.
+	// The main worktree cannot be moved.
+	// Will mutate the current git state.
+	MoveWorktree(ctx context.Context, worktreeAbsPath, newAbsPath string) error
+
+	// RepairWorktree repairs the links between a worktree and the repository (git worktree repair),
+	// e.g. after the worktree or the main worktree was moved without git.
+	// Will mutate the current git state.
+	RepairWorktree(ctx context.Context, worktreeAbsPath string) error
+
+	// RemoveWorktree removes a linked worktree and its directory (git worktree remove). The branch is kept.
+	// Unless force is set, it fails when the worktree has modified or untracked files.
+	// The main worktree cannot be removed.
+	// Will mutate the current git state.
+	RemoveWorktree(ctx context.Context, worktreeAbsPath string, force bool) error
+
+	// PruneWorktrees removes the administrative files of worktrees whose directories are gone (git worktree prune).
+	// Will mutate the current git state.
+	PruneWorktrees(ctx context.Context) error
+
+	// InitSubmodules initializes and checks out the submodules of the worktree at the given path.
+	// Will mutate the current git state.
+	InitSubmodules(ctx context.Context, worktreeAbsPath string) error
+
+	// UpdateRef points a full ref name (e.g., "refs/grove/archive/fix") at a commit SHA, creating the ref if needed.
+	// Will mutate the current git state.
+	UpdateRef(ctx context.Context, ref, sha string) error
+
+	// DeleteRef deletes a full ref name. Deleting a ref that does not exist is an error.
+	// Will mutate the current git state.
+	DeleteRef(ctx context.Context, ref string) error
+
+	// RenameBranch renames a local branch, updating any worktree that has it checked out.
+	// Fails if a branch named newName already exists.
+	// Will mutate the current git state.
+	RenameBranch(ctx context.Context, oldName, newName string) error
+
+	// DeleteBranch deletes a local branch. Unless force is set, it fails with an error wrapping ErrNotMerged
+	// when the branch has commits that are not merged, as git branch -d does.
+	// Fails if the branch is checked out in a worktree.
+	// Will mutate the current git state.
+	DeleteBranch(ctx context.Context, branchName string, force bool) error
+
+	// DeleteRemoteBranch deletes a branch on a remote (git push <remote> --delete <branch>), along with its
+	// remote-tracking ref. Fails if the remote has no such branch.
+	// Will mutate the current git state.
+	DeleteRemoteBranch(ctx context.Context, remote, branchName string) error
+
+	// Push pushes a local branch to the branch of the same name on a remote and makes it the branch's upstream,
+	// as git push --set-upstream does.
+	// Will mutate the current git state.
+	Push(ctx context.Context, remote, branchName string) error
+
+	// GetBranchDescription returns the description of a local branch (git config branch.<name>.description).
+	// Returns ("", nil) if the branch has no description.
+	GetBranchDescription(ctx context.Context, branchName string) (string, error)
+
+	// SetBranchDescription sets the description of a local branch, as git branch --edit-description does.
+	// Will mutate the current git state.
+	SetBranchDescription(ctx context.Context, branchName, description string) error
+
+	// SetBranchUpstream sets the upstream of a local branch (e.g., "origin/main"), as git branch --set-upstream-to does.
+	// Will mutate the current git state.
+	SetBranchUpstream(ctx context.Context, branchName, upstream string) error
+
+	// GetWorktreeConfigState reports whether extensions.worktreeConfig is enabled and which
+	// worktree-specific settings are in the config shared by all worktrees.
+	GetWorktreeConfigState(ctx context.Context) (WorktreeConfigState, error)
+
+	// EnableWorktreeConfig turns on extensions.worktreeConfig, first moving core.worktree and core.bare=true
+	// from the shared config into the main worktree's config.worktree, as git-worktree(1) recommends.
 	// Will mutate the current git state.
-	FetchRemote(remoteName string) (output string, err error)
+	EnableWorktreeConfig(ctx context.Context) error
 }
@@ -3,95 +3,238 @@ package git
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	clog "github.com/charmbracelet/log"
+	"github.com/jmcampanini/grove-cli/internal/lineutil"
+	"github.com/jmcampanini/grove-cli/internal/pathutil"
+	"github.com/jmcampanini/grove-cli/internal/retry"
+	"github.com/jmcampanini/grove-cli/internal/timing"
 )
 
 // GitCli provides high-level git operations by executing real git commands via the git CLI.
 type GitCli struct {
-	dryRun     bool
-	log        *clog.Logger
-	timeout    time.Duration
-	workingDir string
+	activity        map[string]time.Time // worktree path -> last activity, filled by GetLastActivity
+	defaultBranches map[string]string    // remote name -> default branch, filled by ResolveRepoDefaultBranch
+	dryRun          bool
+	log             *clog.Logger
+	mu              sync.Mutex
+	networkTimeout  time.Duration // bounds commands that talk to a remote, see networkCommands
+	retries         int           // times a failed fetch is retried
+	timeout         time.Duration // bounds every other command
+	timings         *timing.Recorder
+	workingDir      string
 }
 
 var _ Git = &GitCli{}
 
 // New creates a new GitCli instance that executes git commands in the specified working directory.
-func New(dryRun bool, workingDir string, timeout time.Duration) Git {
+// Commands that talk to a remote are bounded by networkTimeout and the rest by timeout; fetches that fail
+// for a transient reason, such as a timeout or dropped connection, are retried up to retries times with
+// backoff. Commands are logged to log, prefixed with "git", and how long each took is recorded in timings,
+// which may be nil.
+func New(log *clog.Logger, timings *timing.Recorder, dryRun bool, workingDir string, timeout, networkTimeout time.Duration, retries int) Git {
 	return &GitCli{
-		dryRun:     dryRun,
-		log:        clog.Default().WithPrefix("git"),
-		timeout:    timeout,
-		workingDir: workingDir,
+		activity:        map[string]time.Time{},
+		defaultBranches: map[string]string{},
+		dryRun:          dryRun,
+		log:             log.WithPrefix("git"),
+		networkTimeout:  networkTimeout,
+		retries:         retries,
+		timeout:         timeout,
+		timings:         timings,
+		workingDir:      workingDir,
 	}
 }
 
-func (g *GitCli) executeGitCommand(args ...string) (string, error) {
+// gitEnv returns the environment git commands run with: the user's, overridden so that output parses
+// the same everywhere and grove never gets in the way of the user's own git:
+//   - GIT_OPTIONAL_LOCKS=0: reads such as status skip the optional index lock, so a background
+//     grove list cannot make an interactive git command fail with "index.lock exists"
+//   - GIT_PAGER=cat: output is never piped through a pager
+//   - GIT_TERMINAL_PROMPT=0: credential prompts fail instead of hanging
+//   - LC_ALL=C: messages are untranslated, since grove parses them (e.g. "[ahead 1]" and error text)
+func gitEnv() []string {
+	return append(os.Environ(), "GIT_OPTIONAL_LOCKS=0", "GIT_PAGER=cat", "GIT_TERMINAL_PROMPT=0", "LC_ALL=C")
+}
+
+// networkCommands are the git subcommands that talk to a remote, bounded by the network timeout.
+var networkCommands = map[string]bool{"clone": true, "fetch": true, "ls-remote": true, "push": true}
+
+// retryableCommands are the git subcommands retried when they fail for a transient reason, such as a timeout
+// or a dropped connection. They talk to a remote, where such failures are common, and repeating them is safe.
+var retryableCommands = map[string]bool{"fetch": true}
+
+func (g *GitCli) executeGitCommand(ctx context.Context, args ...string) (string, error) {
+	return g.executeGitCommandWithProgress(ctx, nil, args...)
+}
+
+// executeGitCommandWithProgress runs git like executeGitCommand, passing each line git writes to stderr,
+// where it reports progress, to progress as it arrives. A nil progress ignores them.
+func (g *GitCli) executeGitCommandWithProgress(ctx context.Context, progress func(line string), args ...string) (string, error) {
+	timeout := g.commandTimeout(args)
+	if len(args) > 0 && retryableCommands[args[0]] {
+		return retry.Do(ctx, g.retries, g.log, "git "+strings.Join(args, " "), retry.Transient, func() (string, error) {
+			return g.runGitCommand(ctx, timeout, progress, nil, args...)
+		})
+	}
+	return g.runGitCommand(ctx, timeout, progress, nil, args...)
+}
+
+// expectedExit reports whether git exiting with code, having written stderr, answers the question the
+// command asked rather than failing. Such exits are still returned as errors, but only logged at Debug.
+type expectedExit func(code int, stderr string) bool
+
+// exitsWith1 expects exit status 1, with which show-ref --verify and config --get report that the ref or key
+// does not exist.
+func exitsWith1(code int, _ string) bool {
+	return code == 1
+}
+
+// unknownRevision expects exit status 128 with git reporting that a revision does not exist, as rev-parse does
+// for a missing ref.
+func unknownRevision(code int, stderr string) bool {
+	return code == 128 && strings.Contains(stderr, "unknown revision")
+}
+
+// noSuchRemote expects exit status 2 with git reporting that a remote is not configured, as remote get-url does.
+func noSuchRemote(code int, stderr string) bool {
+	return code == 2 && strings.Contains(stderr, "No such remote")
+}
+
+// outsideRepository expects git to report that it is not run in a repository, as grove init and other
+// commands that do not need one are.
+func outsideRepository(_ int, stderr string) bool {
+	return strings.Contains(stderr, "not a git repo")
+}
+
+// isExitCode reports whether err is git exiting with code.
+func isExitCode(err error, code int) bool {
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr) && exitErr.ExitCode() == code
+}
+
+// probeGitCommand runs a local git command like executeGitCommand, for commands whose exits that expected
+// accepts are answers, such as a missing ref or config key; those are logged at Debug instead of Warn.
+func (g *GitCli) probeGitCommand(ctx context.Context, expected expectedExit, args ...string) (string, error) {
+	return g.runGitCommand(ctx, g.commandTimeout(args), nil, expected, args...)
+}
+
+// commandTimeout returns the deadline for a git command: the network timeout for commands that talk to
+// a remote, the local timeout otherwise.
+func (g *GitCli) commandTimeout(args []string) time.Duration {
+	if len(args) > 0 && networkCommands[args[0]] {
+		return g.networkTimeout
+	}
+	return g.timeout
+}
+
+// runGitCommand runs git once, bounded by timeout, returning its trimmed stdout.
+// Lines git writes to stderr are passed to progress, if not nil, as they arrive. Exits that expected, if not
+// nil, accepts are logged at Debug rather than Warn.
+func (g *GitCli) runGitCommand(ctx context.Context, timeout time.Duration, progress func(line string), expected expectedExit, args ...string) (string, error) {
+	var stdout bytes.Buffer
+	if err := g.streamGitCommand(ctx, timeout, &stdout, progress, expected, args...); err != nil {
+		return "", err
+	}
+	output := strings.TrimSpace(stdout.String())
+	g.log.Debug("Git command succeeded", "args", args, "output", output)
+	return output, nil
+}
+
+// streamGitCommand runs git once, bounded by timeout, copying its stdout to w as it is produced instead of
+// holding it in memory, so large output costs nothing to pass on. Lines git writes to stderr are passed to
+// progress, if not nil, as they arrive; stderr is also kept for the error. Exits that expected, if not nil,
+// accepts are logged at Debug rather than Warn, and are not recorded as failures in the timings.
+func (g *GitCli) streamGitCommand(ctx context.Context, timeout time.Duration, w io.Writer, progress func(line string), expected expectedExit, args ...string) error {
 	g.log.Debug("Executing git command", "cmd", "git", "args", args, "workingDir", g.workingDir)
 
-	ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, "git", args...)
 	cmd.Dir = g.workingDir
-	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	cmd.Env = gitEnv()
 
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
+	var stderr bytes.Buffer
+	cmd.Stdout = w
 	cmd.Stderr = &stderr
+	if progress != nil {
+		lines := lineutil.NewWriter(progress)
+		defer lines.Flush()
+		cmd.Stderr = io.MultiWriter(&stderr, lines)
+	}
 
-	if err := cmd.Run(); err != nil {
+	start := time.Now()
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	answered := expected != nil && errors.As(err, &exitErr) && expected(exitErr.ExitCode(), stderr.String())
+	if answered {
+		g.timings.Record("git", args, time.Since(start), nil)
+	} else {
+		g.timings.Record("git", args, time.Since(start), err)
+	}
+	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
-			g.log.Warn("git command timed out", "args", args, "timeout", g.timeout, "error", err)
-			return "", fmt.Errorf("git %s timed out after %s", strings.Join(args, " "), g.timeout)
+			g.log.Warn("git command timed out", "args", args, "timeout", timeout, "error", err)
+			return fmt.Errorf("git %s timed out after %s", strings.Join(args, " "), timeout)
+		}
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return fmt.Errorf("git %s interrupted: %w", strings.Join(args, " "), ctx.Err())
+		}
+		if answered {
+			g.log.Debug("Git command exited", "args", args, "stderr", stderr.String(), "error", err)
+		} else {
+			g.log.Warn("Git command failed", "args", args, "stderr", stderr.String(), "error", err)
 		}
-		g.log.Warn("Git command failed", "args", args, "stderr", stderr.String(), "error", err)
-		return "", fmt.Errorf("git %s failed: %w: %s", strings.Join(args, " "), err, stderr.String())
+		return fmt.Errorf("git %s failed: %w: %s", strings.Join(args, " "), err, stderr.String())
 	}
-
-	output := strings.TrimSpace(stdout.String())
-	g.log.Debug("Git command succeeded", "args", args, "output", output)
-	return output, nil
+	return nil
 }
 
 // executeMutatingCommand runs a git command that modifies state, unless in dry-run mode.
-func (g *GitCli) executeMutatingCommand(errContext string, args ...string) error {
+func (g *GitCli) executeMutatingCommand(ctx context.Context, errContext string, args ...string) error {
 	if g.dryRun {
 		g.log.Info("Would execute git command", "cmd", "git", "args", args)
 		return nil
 	}
-	if _, err := g.executeGitCommand(args...); err != nil {
+	if _, err := g.executeGitCommand(ctx, args...); err != nil {
 		return fmt.Errorf("%s: %w", errContext, err)
 	}
 	return nil
 }
 
 // executeMutatingCommandWithOutput runs a git command that modifies state and returns its output.
-func (g *GitCli) executeMutatingCommandWithOutput(errContext string, args ...string) (string, error) {
+// Lines git writes to stderr are passed to progress, if not nil, as they arrive.
+func (g *GitCli) executeMutatingCommandWithOutput(ctx context.Context, errContext string, progress func(line string), args ...string) (string, error) {
 	if g.dryRun {
 		g.log.Info("Would execute git command", "cmd", "git", "args", args)
 		return fmt.Sprintf("Would execute: git %s", strings.Join(args, " ")), nil
 	}
-	output, err := g.executeGitCommand(args...)
+	output, err := g.executeGitCommandWithProgress(ctx, progress, args...)
 	if err != nil {
 		return output, fmt.Errorf("%s: %w", errContext, err)
 	}
 	return output, nil
 }
 
-func (g *GitCli) GetMainWorktreePath() (string, error) {
-	commonDir, err := g.executeGitCommand("rev-parse", "--git-common-dir")
+func (g *GitCli) GetCommonDir(ctx context.Context) (string, error) {
+	commonDir, err := g.executeGitCommand(ctx, "rev-parse", "--git-common-dir")
 	if err != nil {
 		return "", fmt.Errorf("failed to get git common dir: %w", err)
 	}
 
+	commonDir = pathutil.FromGit(commonDir)
 	absCommonDir := commonDir
 	if !filepath.IsAbs(commonDir) {
 		absCommonDir = filepath.Join(g.workingDir, commonDir)
@@ -102,23 +245,51 @@ func (g *GitCli) GetMainWorktreePath() (string, error) {
 		return "", fmt.Errorf("failed to get absolute path: %w", err)
 	}
 
-	absCommonDir = filepath.Clean(absCommonDir)
-	mainWorktree := filepath.Dir(absCommonDir)
+	// resolve symlinks so paths derived from the common dir match the paths git reports for worktrees
+	return pathutil.Normalize(absCommonDir), nil
+}
+
+func (g *GitCli) GetMainWorktreePath(ctx context.Context) (string, error) {
+	commonDir, err := g.GetCommonDir(ctx)
+	if err != nil {
+		return "", err
+	}
 
-	g.log.Debug("Resolved main worktree path", "commonDir", commonDir, "mainWorktree", mainWorktree)
+	bare, err := g.isBareRepository(ctx, commonDir)
+	if err != nil {
+		return "", err
+	}
+
+	// a bare repository has no main worktree: git lists the repository itself in its place
+	mainWorktree := filepath.Dir(commonDir)
+	if bare {
+		mainWorktree = commonDir
+	}
+
+	g.log.Debug("Resolved main worktree path", "commonDir", commonDir, "bare", bare, "mainWorktree", mainWorktree)
 	return mainWorktree, nil
 }
 
-func (g *GitCli) GetWorkspacePath() (string, error) {
-	mainWorktreePath, err := g.GetMainWorktreePath()
+// isBareRepository reports whether the repository at commonDir is bare (core.bare). It asks from inside
+// commonDir, as in a linked worktree git reports the worktree, which is never bare.
+func (g *GitCli) isBareRepository(ctx context.Context, commonDir string) (bool, error) {
+	output, err := g.executeGitCommand(ctx, "-C", commonDir, "rev-parse", "--is-bare-repository")
+	if err != nil {
+		return false, fmt.Errorf("failed to check whether the repository is bare: %w", err)
+	}
+	return output == "true", nil
+}
+
+func (g *GitCli) GetWorkspacePath(ctx context.Context) (string, error) {
+	mainWorktreePath, err := g.GetMainWorktreePath(ctx)
 	if err != nil {
 		return "", fmt.Errorf("failed to get main worktree path: %w", err)
 	}
 	return filepath.Dir(mainWorktreePath), nil
 }
 
-func (g *GitCli) GetWorktreeRoot() (string, error) {
-	output, err := g.executeGitCommand("rev-parse", "--show-toplevel")
+func (g *GitCli) GetWorktreeRoot(ctx context.Context) (string, error) {
+	output, err := g.probeGitCommand(ctx, outsideRepository, "rev-parse", "--show-toplevel")
 	if err != nil {
 		if strings.Contains(err.Error(), "not a git repo") {
 			// Not in a git repo - this is a valid state, not an error
@@ -126,27 +297,72 @@ func (g *GitCli) GetWorktreeRoot() (string, error) {
 		}
 		return "", fmt.Errorf("git command failed: %w", err)
 	}
-	return output, nil
+	return pathutil.FromGit(output), nil
 }
 
-func (g *GitCli) GetCurrentBranch() (string, error) {
-	output, err := g.executeGitCommand("rev-parse", "--abbrev-ref", "HEAD")
+func (g *GitCli) GetCurrentBranch(ctx context.Context) (string, error) {
+	output, err := g.executeGitCommand(ctx, "rev-parse", "--abbrev-ref", "HEAD")
 	if err != nil {
 		return "", fmt.Errorf("failed to get current branch: %w", err)
 	}
 	return output, nil
 }
 
-func (g *GitCli) GetCommitSubject() (string, error) {
-	output, err := g.executeGitCommand("log", "-1", "--format=%s")
+func (g *GitCli) GetCommitSubject(ctx context.Context) (string, error) {
+	output, err := g.executeGitCommand(ctx, "log", "-1", "--format=%s")
 	if err != nil {
 		return "", fmt.Errorf("failed to get commit subject: %w", err)
 	}
 	return output, nil
 }
 
-func (g *GitCli) GetDefaultRemote(fallback string) (string, error) {
-	output, err := g.executeGitCommand("config", "--get", "remote.pushDefault")
+// GetPager resolves the pager the way git does. `git var GIT_PAGER` can't be used because it
+// reports "cat" whenever its own stdout is not a terminal, which is always the case here.
+func (g *GitCli) GetPager(ctx context.Context) (string, error) {
+	if pager, ok := os.LookupEnv("GIT_PAGER"); ok {
+		return pager, nil
+	}
+	if pager, err := g.probeGitCommand(ctx, exitsWith1, "config", "--get", "core.pager"); err == nil && pager != "" {
+		return pager, nil
+	}
+	if pager, ok := os.LookupEnv("PAGER"); ok {
+		return pager, nil
+	}
+	return "less", nil
+}
+
+func (g *GitCli) GetUserName(ctx context.Context) (string, error) {
+	output, err := g.probeGitCommand(ctx, exitsWith1, "config", "--get", "user.name")
+	if isExitCode(err, 1) {
+		// git config exits with 1 when the key is not set
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get user.name: %w", err)
+	}
+	return output, nil
+}
+
+func (g *GitCli) GetVersion(ctx context.Context) (string, error) {
+	output, err := g.executeGitCommand(ctx, "version")
+	if err != nil {
+		return "", fmt.Errorf("failed to get git version: %w", err)
+	}
+	return parseVersion(output)
+}
+
+// parseVersion extracts the version number from `git version` output,
+// e.g. "git version 2.39.3 (Apple Git-146)" -> "2.39.3".
+func parseVersion(output string) (string, error) {
+	fields := strings.Fields(output)
+	if len(fields) < 3 || fields[0] != "git" || fields[1] != "version" {
+		return "", fmt.Errorf("unexpected git version output: %q", output)
+	}
+	return fields[2], nil
+}
+
+func (g *GitCli) GetDefaultRemote(ctx context.Context, fallback string) (string, error) {
+	output, err := g.probeGitCommand(ctx, exitsWith1, "config", "--get", "remote.pushDefault")
 	if err == nil && output != "" {
 		g.log.Debug("Found remote.pushDefault", "remote", output)
 		return output, nil
@@ -157,29 +373,29 @@ func (g *GitCli) GetDefaultRemote(fallback string) (string, error) {
 }
 
 // remoteExists checks if a remote with the given name is configured.
-func (g *GitCli) remoteExists(remoteName string) (bool, error) {
-	_, err := g.executeGitCommand("remote", "get-url", remoteName)
+func (g *GitCli) remoteExists(ctx context.Context, remoteName string) (bool, error) {
+	_, err := g.probeGitCommand(ctx, noSuchRemote, "remote", "get-url", remoteName)
 	if err == nil {
 		return true, nil
 	}
 
-	if strings.Contains(err.Error(), "No such remote") {
+	if isExitCode(err, 2) && strings.Contains(err.Error(), "No such remote") {
 		return false, nil
 	}
 
 	return false, err
 }
 
-func (g *GitCli) GetRepoDefaultBranch(remoteName string) (string, error) {
-	if exists, err := g.remoteExists(remoteName); err != nil {
+func (g *GitCli) GetRepoDefaultBranch(ctx context.Context, remoteName string) (string, error) {
+	if exists, err := g.remoteExists(ctx, remoteName); err != nil {
 		return "", fmt.Errorf("failed to check remote existence: %w", err)
 	} else if !exists {
 		return "", fmt.Errorf("remote '%s' does not exist", remoteName)
 	}
 
-	output, err := g.executeGitCommand("rev-parse", "--abbrev-ref", remoteName+"/HEAD")
+	output, err := g.probeGitCommand(ctx, unknownRevision, "rev-parse", "--abbrev-ref", remoteName+"/HEAD")
 	if err != nil {
-		if strings.Contains(err.Error(), "unknown revision") {
+		if isExitCode(err, 128) && strings.Contains(err.Error(), "unknown revision") {
 			g.log.Debug("Remote HEAD not configured", "remoteName", remoteName)
 			return "", nil
 		}
@@ -190,18 +406,90 @@ func (g *GitCli) GetRepoDefaultBranch(remoteName string) (string, error) {
 	return branchName, nil
 }
 
-func (g *GitCli) ListLocalBranches() ([]LocalBranch, error) {
-	format := `branch %(refname:short)
-checkedOut %(if)%(HEAD)%(then)true%(else)false%(end)
-commit %(objectname:short)
-%(if)%(upstream:short)%(then)upstream %(upstream:short)
-%(end)%(if)%(upstream:track)%(then)track %(upstream:track)
-%(end)committedOn %(committerdate:iso-strict)
-committedBy %(committername)
-subject %(contents:subject)
-worktreepath %(worktreepath)
-`
-	output, err := g.executeGitCommand("for-each-ref", "--format="+format, "refs/heads/")
+func (g *GitCli) ResolveRepoDefaultBranch(ctx context.Context, remoteName string) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if branchName, ok := g.defaultBranches[remoteName]; ok {
+		return branchName, nil
+	}
+
+	branchName, err := g.GetRepoDefaultBranch(ctx, remoteName)
+	if err != nil {
+		return "", err
+	}
+	if branchName == "" {
+		g.log.Info("Detecting remote HEAD", "remote", remoteName)
+		if err := g.executeMutatingCommand(ctx, "failed to detect remote HEAD", "remote", "set-head", remoteName, "--auto"); err != nil {
+			return "", err
+		}
+		if branchName, err = g.GetRepoDefaultBranch(ctx, remoteName); err != nil {
+			return "", err
+		}
+	}
+
+	g.defaultBranches[remoteName] = branchName
+	return branchName, nil
+}
+
+func (g *GitCli) GetRemoteHEAD(ctx context.Context, remoteName string) (string, error) {
+	output, err := g.executeGitCommand(ctx, "ls-remote", "--symref", remoteName, "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to query remote HEAD: %w", err)
+	}
+
+	// the symref line looks like "ref: refs/heads/main\tHEAD"
+	for line := range strings.Lines(output) {
+		ref, name, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		if !ok || name != "HEAD" {
+			continue
+		}
+		if branchName, ok := strings.CutPrefix(ref, "ref: refs/heads/"); ok {
+			return branchName, nil
+		}
+	}
+	g.log.Debug("Remote does not report a HEAD branch", "remoteName", remoteName)
+	return "", nil
+}
+
+func (g *GitCli) SetRepoDefaultBranch(ctx context.Context, remoteName, branchName string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.log.Info("Setting remote HEAD", "remote", remoteName, "branch", branchName)
+	if err := g.executeMutatingCommand(ctx, "failed to set remote HEAD", "remote", "set-head", remoteName, branchName); err != nil {
+		return err
+	}
+	delete(g.defaultBranches, remoteName)
+	return nil
+}
+
+// Field positions of a record in ListLocalBranches' for-each-ref format.
+const (
+	branchFieldName = iota
+	branchFieldHEAD
+	branchFieldSHA
+	branchFieldUpstream
+	branchFieldTrack
+	branchFieldCommittedOn
+	branchFieldCommittedBy
+	branchFieldSubject
+	branchFieldWorktreePath
+	branchFieldCount
+)
+
+var branchFormat = [branchFieldCount]string{
+	branchFieldName:         "%(refname:short)",
+	branchFieldHEAD:         "%(HEAD)",
+	branchFieldSHA:          "%(objectname)",
+	branchFieldUpstream:     "%(upstream:short)",
+	branchFieldTrack:        "%(upstream:track)",
+	branchFieldCommittedOn:  "%(committerdate:iso-strict)",
+	branchFieldCommittedBy:  "%(committername)",
+	branchFieldSubject:      "%(contents:subject)",
+	branchFieldWorktreePath: "%(worktreepath)",
+}
+
+func (g *GitCli) ListLocalBranches(ctx context.Context) ([]LocalBranch, error) {
+	output, err := g.executeGitCommand(ctx, "for-each-ref", "--format="+forEachRefFormat(branchFormat[:]), "refs/heads/")
 	if err != nil {
 		return nil, fmt.Errorf("failed to list branches: %w", err)
 	}
@@ -213,14 +501,35 @@ worktreepath %(worktreepath)
 	return parseBranchesFromFormat(output), nil
 }
 
-// parseBranchesFromFormat parses the output of `git for-each-ref` with a custom format
+// forEachRefFormat joins for-each-ref placeholders into a format whose fields each end with a NUL.
+// NUL cannot appear in a ref name, a subject, or any other field, so unlike line prefixes, no field
+// value can be mistaken for another field or record.
+func forEachRefFormat(fields []string) string {
+	return strings.Join(fields, "%00") + "%00"
+}
+
+// splitRecords splits for-each-ref output in a forEachRefFormat into records of n fields each.
+// Git ends each record with a newline, so every record after the first starts with one, which is dropped.
+// A trailing partial record is ignored.
+func splitRecords(output string, n int) [][]string {
+	fields := strings.Split(output, "\x00")
+	records := make([][]string, 0, len(fields)/n)
+	for i := 0; i+n <= len(fields); i += n {
+		record := fields[i : i+n : i+n]
+		record[0] = strings.TrimPrefix(record[0], "\n")
+		records = append(records, record)
+	}
+	return records
+}
+
+// parseBranchesFromFormat parses the output of `git for-each-ref` in branchFormat
 // and returns a slice of LocalBranch structs with all metadata.
 func parseBranchesFromFormat(output string) []LocalBranch {
-	blocks := splitIntoBlocks(output)
-	branches := make([]LocalBranch, 0, len(blocks))
+	records := splitRecords(output, branchFieldCount)
+	branches := make([]LocalBranch, 0, len(records))
 
-	for _, block := range blocks {
-		branch := parseBranchBlock(block)
+	for _, record := range records {
+		branch := parseBranchRecord(record)
 		if branch.Name != "" {
 			branches = append(branches, branch)
 		}
@@ -256,76 +565,108 @@ func splitIntoBlocks(output string) [][]string {
 	return blocks
 }
 
-func parseBranchBlock(lines []string) LocalBranch {
-	fields := parseLineFields(lines)
+// parseBranchRecord parses one record of branchFormat fields, or returns an empty branch if it has too few.
+func parseBranchRecord(fields []string) LocalBranch {
+	if len(fields) < branchFieldCount {
+		return LocalBranch{}
+	}
 
-	name := fields["branch"]
-	isCheckedOut := fields["checkedOut"] == "true"
-	sha := fields["commit"]
-	upstreamName := fields["upstream"]
-	ahead, behind := parseTrackInfo(fields["track"])
-	committedOn := parseISO8601Date(fields["committedOn"])
-	committedBy := fields["committedBy"]
-	subject := fields["subject"]
-	worktreeAbsolutePath := fields["worktreepath"]
+	name := fields[branchFieldName]
+	isCheckedOut := fields[branchFieldHEAD] == "*"
+	sha := fields[branchFieldSHA]
+	upstreamName := fields[branchFieldUpstream]
+	ahead, behind, gone := parseTrackInfo(fields[branchFieldTrack])
+	committedOn := parseISO8601Date(fields[branchFieldCommittedOn])
+	committedBy := fields[branchFieldCommittedBy]
+	subject := fields[branchFieldSubject]
+	worktreeAbsolutePath := pathutil.FromGit(fields[branchFieldWorktreePath])
 
 	commit := NewCommit(sha, subject, committedOn, committedBy)
-	return NewLocalBranch(name, upstreamName, worktreeAbsolutePath, isCheckedOut, ahead, behind, commit)
+	branch := NewLocalBranch(name, upstreamName, worktreeAbsolutePath, isCheckedOut, ahead, behind, commit)
+	branch.Gone = gone
+	return branch
 }
 
-// parseTrackInfo parses the upstream track info string like "[ahead 3, behind 2]"
-func parseTrackInfo(track string) (ahead, behind int) {
-	if track == "" || track == "[gone]" {
-		return 0, 0
-	}
-	if n, _ := fmt.Sscanf(track, "[ahead %d, behind %d]", &ahead, &behind); n == 2 {
-		return ahead, behind
-	}
-	if n, _ := fmt.Sscanf(track, "[ahead %d]", &ahead); n == 1 {
-		return ahead, 0
-	}
-	if n, _ := fmt.Sscanf(track, "[behind %d]", &behind); n == 1 {
-		return 0, behind
+// parseTrackInfo parses the upstream track info from %(upstream:track), e.g. "[ahead 3, behind 2]" or "[gone]".
+// The brackets are optional and the parts may come in any order; unrecognized parts are ignored.
+// Git translates these words, so commands run with LC_ALL=C (see gitEnv).
+func parseTrackInfo(track string) (ahead, behind int, gone bool) {
+	track = strings.TrimSpace(track)
+	track = strings.TrimSuffix(strings.TrimPrefix(track, "["), "]")
+	for part := range strings.SplitSeq(track, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), " ")
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		switch {
+		case key == "gone":
+			gone = true
+		case err != nil || n < 0:
+			continue
+		case key == "ahead":
+			ahead = n
+		case key == "behind":
+			behind = n
+		}
 	}
-	return 0, 0
+	return ahead, behind, gone
 }
 
-// getCommitBySHA retrieves full commit information for a given SHA.
-// Used when parsing worktrees with detached HEAD.
-func (g *GitCli) getCommitBySHA(sha string) (Commit, error) {
-	// Format: subject<NUL>committer date ISO<NUL>committer name
-	// NUL separators handle multi-line subjects more robustly than line-based parsing
-	format := "%s%x00%cI%x00%cn"
-	output, err := g.executeGitCommand("log", "-1", "--format="+format, sha)
+// getCommitsBySHA retrieves full commit information for the given full SHAs with one git call, by SHA.
+// Used when parsing worktrees with detached HEAD, so that many of them do not cost a git call each.
+func (g *GitCli) getCommitsBySHA(ctx context.Context, shas []string) (map[string]Commit, error) {
+	if len(shas) == 0 {
+		return map[string]Commit{}, nil
+	}
+	// Format: SHA<NUL>subject<NUL>committer date ISO<NUL>committer name, one commit per line.
+	// %s joins a multi-line subject into one line, and NUL separators cannot appear in any field.
+	args := append([]string{"log", "--no-walk=unsorted", "--format=%H%x00%s%x00%cI%x00%cn"}, shas...)
+	output, err := g.executeGitCommand(ctx, append(args, "--")...)
 	if err != nil {
-		return Commit{}, fmt.Errorf("failed to get commit info for %s: %w", sha, err)
+		return nil, fmt.Errorf("failed to get commit info for %s: %w", strings.Join(shas, ", "), err)
 	}
+	return g.parseCommitLog(output)
+}
 
-	parts := strings.Split(output, "\x00")
-	if len(parts) != 3 {
-		return Commit{}, fmt.Errorf("unexpected commit format for %s: got %d parts", sha, len(parts))
+// parseCommitLog parses the output of getCommitsBySHA's git log into commits by SHA.
+func (g *GitCli) parseCommitLog(output string) (map[string]Commit, error) {
+	commits := map[string]Commit{}
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+		parts := strings.Split(line, "\x00")
+		if len(parts) != 4 {
+			return nil, fmt.Errorf("unexpected commit format %q: got %d parts", line, len(parts))
+		}
+		sha, subject, date, committedBy := parts[0], parts[1], parts[2], parts[3]
+		committedOn, err := time.Parse(time.RFC3339, date)
+		if err != nil {
+			g.log.Debug("failed to parse commit date", "sha", sha, "date", date, "error", err)
+		}
+		commits[sha] = NewCommit(sha, subject, committedOn, committedBy)
 	}
+	return commits, nil
+}
 
-	subject := parts[0]
-	committedOn := time.Time{}
-	if parsed, err := time.Parse(time.RFC3339, parts[1]); err == nil {
-		committedOn = parsed
-	} else {
-		g.log.Debug("failed to parse commit date", "sha", sha, "date", parts[1], "error", err)
-	}
-	committedBy := parts[2]
+// Field positions of a record in ListRemoteBranches' for-each-ref format.
+const (
+	remoteBranchFieldRef = iota
+	remoteBranchFieldSHA
+	remoteBranchFieldCommittedOn
+	remoteBranchFieldCommittedBy
+	remoteBranchFieldSubject
+	remoteBranchFieldCount
+)
 
-	return NewCommit(sha, subject, committedOn, committedBy), nil
+var remoteBranchFormat = [remoteBranchFieldCount]string{
+	remoteBranchFieldRef:         "%(refname:short)",
+	remoteBranchFieldSHA:         "%(objectname)",
+	remoteBranchFieldCommittedOn: "%(committerdate:iso-strict)",
+	remoteBranchFieldCommittedBy: "%(committername)",
+	remoteBranchFieldSubject:     "%(contents:subject)",
 }
 
-func (g *GitCli) ListRemoteBranches(remoteName string) ([]RemoteBranch, error) {
-	format := `ref %(refname:short)
-commit %(objectname:short)
-committedOn %(committerdate:iso-strict)
-committedBy %(committername)
-subject %(contents:subject)
-`
-	output, err := g.executeGitCommand("for-each-ref", "--format="+format, "refs/remotes/"+remoteName+"/")
+func (g *GitCli) ListRemoteBranches(ctx context.Context, remoteName string) ([]RemoteBranch, error) {
+	output, err := g.executeGitCommand(ctx, "for-each-ref", "--format="+forEachRefFormat(remoteBranchFormat[:]), "refs/remotes/"+remoteName+"/")
 	if err != nil {
 		return nil, fmt.Errorf("failed to list remote branches: %w", err)
 	}
@@ -338,11 +679,11 @@ subject %(contents:subject)
 }
 
 func parseRemoteBranchesFromFormat(output string) []RemoteBranch {
-	blocks := splitIntoBlocks(output)
-	branches := make([]RemoteBranch, 0, len(blocks))
+	records := splitRecords(output, remoteBranchFieldCount)
+	branches := make([]RemoteBranch, 0, len(records))
 
-	for _, block := range blocks {
-		branch := parseRemoteBranchBlock(block)
+	for _, record := range records {
+		branch := parseRemoteBranchRecord(record)
 		// Skip symbolic HEAD ref (e.g., origin/HEAD -> origin/main)
 		if branch.Name != "" && branch.Name != "HEAD" {
 			branches = append(branches, branch)
@@ -352,29 +693,32 @@ func parseRemoteBranchesFromFormat(output string) []RemoteBranch {
 	return branches
 }
 
-func parseRemoteBranchBlock(lines []string) RemoteBranch {
-	fields := parseLineFields(lines)
+// parseRemoteBranchRecord parses one record of remoteBranchFormat fields, or returns an empty branch if it has too few.
+func parseRemoteBranchRecord(fields []string) RemoteBranch {
+	if len(fields) < remoteBranchFieldCount {
+		return RemoteBranch{}
+	}
 
 	// ref is "origin/main", split on first "/" to get remote and branch name
 	var name, remoteName string
-	if ref := fields["ref"]; ref != "" {
+	if ref := fields[remoteBranchFieldRef]; ref != "" {
 		if idx := strings.Index(ref, "/"); idx != -1 {
 			remoteName = ref[:idx]
 			name = ref[idx+1:]
 		}
 	}
 
-	sha := fields["commit"]
-	committedOn := parseISO8601Date(fields["committedOn"])
-	committedBy := fields["committedBy"]
-	subject := fields["subject"]
+	sha := fields[remoteBranchFieldSHA]
+	committedOn := parseISO8601Date(fields[remoteBranchFieldCommittedOn])
+	committedBy := fields[remoteBranchFieldCommittedBy]
+	subject := fields[remoteBranchFieldSubject]
 
 	commit := NewCommit(sha, subject, committedOn, committedBy)
 	return NewRemoteBranch(name, remoteName, commit)
 }
 
-func (g *GitCli) ListRemotes() ([]string, error) {
-	output, err := g.executeGitCommand("remote")
+func (g *GitCli) ListRemotes(ctx context.Context) ([]string, error) {
+	output, err := g.executeGitCommand(ctx, "remote")
 	if err != nil {
 		return nil, fmt.Errorf("failed to list remotes: %w", err)
 	}
@@ -393,10 +737,18 @@ func (g *GitCli) ListRemotes() ([]string, error) {
 	return remotes, nil
 }
 
-func (g *GitCli) SyncTags(remoteName string) error {
+func (g *GitCli) GetRemoteURL(ctx context.Context, remoteName string) (string, error) {
+	output, err := g.executeGitCommand(ctx, "remote", "get-url", remoteName)
+	if err != nil {
+		return "", fmt.Errorf("failed to get URL of remote %s: %w", remoteName, err)
+	}
+	return output, nil
+}
+
+func (g *GitCli) SyncTags(ctx context.Context, remoteName string) error {
 	if remoteName == "" {
 		var err error
-		remoteName, err = g.GetDefaultRemote("origin")
+		remoteName, err = g.GetDefaultRemote(ctx, "origin")
 		if err != nil {
 			return fmt.Errorf("failed to get default remote: %w", err)
 		}
@@ -404,24 +756,44 @@ func (g *GitCli) SyncTags(remoteName string) error {
 
 	g.log.Info("Syncing tags from remote", "remote", remoteName)
 	args := []string{"fetch", remoteName, "--prune", "--prune-tags", "--tags"}
-	return g.executeMutatingCommand("failed to sync tags from remote", args...)
-}
-
-func (g *GitCli) ListTags() ([]Tag, error) {
-	format := `name %(refname:short)
-objecttype %(objecttype)
-objectsha %(objectname)
-derefsha %(*objectname)
-taggername %(taggername)
-taggeremail %(taggeremail)
-taggedon %(taggerdate:iso-strict)
-message %(contents:subject)
-committedby %(*committername)
-committedon %(*committerdate:iso-strict)
-committerdate %(committerdate:iso-strict)
-commitsubject %(*subject)
-`
-	output, err := g.executeGitCommand("for-each-ref", "--format="+format, "refs/tags/")
+	return g.executeMutatingCommand(ctx, "failed to sync tags from remote", args...)
+}
+
+// Field positions of a record in ListTags' for-each-ref format. The starred placeholders are those of the
+// commit an annotated tag points to, and are empty for lightweight tags.
+const (
+	tagFieldName = iota
+	tagFieldObjectType
+	tagFieldObjectSHA
+	tagFieldDerefSHA
+	tagFieldTaggerName
+	tagFieldTaggerEmail
+	tagFieldTaggedOn
+	tagFieldMessage
+	tagFieldCommittedBy
+	tagFieldCommittedOn
+	tagFieldCommitterDate
+	tagFieldCommitSubject
+	tagFieldCount
+)
+
+var tagFormat = [tagFieldCount]string{
+	tagFieldName:          "%(refname:short)",
+	tagFieldObjectType:    "%(objecttype)",
+	tagFieldObjectSHA:     "%(objectname)",
+	tagFieldDerefSHA:      "%(*objectname)",
+	tagFieldTaggerName:    "%(taggername)",
+	tagFieldTaggerEmail:   "%(taggeremail)",
+	tagFieldTaggedOn:      "%(taggerdate:iso-strict)",
+	tagFieldMessage:       "%(contents:subject)",
+	tagFieldCommittedBy:   "%(*committername)",
+	tagFieldCommittedOn:   "%(*committerdate:iso-strict)",
+	tagFieldCommitterDate: "%(committerdate:iso-strict)",
+	tagFieldCommitSubject: "%(*subject)",
+}
+
+func (g *GitCli) ListTags(ctx context.Context) ([]Tag, error) {
+	output, err := g.executeGitCommand(ctx, "for-each-ref", "--format="+forEachRefFormat(tagFormat[:]), "refs/tags/")
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tags: %w", err)
 	}
@@ -442,11 +814,11 @@ func parseISO8601Date(dateStr string) time.Time {
 }
 
 func parseTagsFromFormat(output string) []Tag {
-	blocks := splitIntoBlocks(output)
-	tags := make([]Tag, 0, len(blocks))
+	records := splitRecords(output, tagFieldCount)
+	tags := make([]Tag, 0, len(records))
 
-	for _, block := range blocks {
-		tag := parseTagBlock(block)
+	for _, record := range records {
+		tag := parseTagRecord(record)
 		if tag.Name != "" {
 			tags = append(tags, tag)
 		}
@@ -469,21 +841,24 @@ func parseLineFields(lines []string) map[string]string {
 	return fields
 }
 
-func parseTagBlock(lines []string) Tag {
-	fields := parseLineFields(lines)
+// parseTagRecord parses one record of tagFormat fields, or returns an empty tag if it has too few.
+func parseTagRecord(fields []string) Tag {
+	if len(fields) < tagFieldCount {
+		return Tag{}
+	}
 
-	name := fields["name"]
-	objectType := fields["objecttype"]
-	objectSHA := fields["objectsha"]
-	derefSHA := fields["derefsha"]
-	taggerName := fields["taggername"]
-	taggerEmail := fields["taggeremail"]
-	taggedOn := parseISO8601Date(fields["taggedon"])
-	message := fields["message"]
-	committedBy := fields["committedby"]
-	committedOn := parseISO8601Date(fields["committedon"])
-	committerDate := parseISO8601Date(fields["committerdate"])
-	commitSubject := fields["commitsubject"]
+	name := fields[tagFieldName]
+	objectType := fields[tagFieldObjectType]
+	objectSHA := fields[tagFieldObjectSHA]
+	derefSHA := fields[tagFieldDerefSHA]
+	taggerName := fields[tagFieldTaggerName]
+	taggerEmail := fields[tagFieldTaggerEmail]
+	taggedOn := parseISO8601Date(fields[tagFieldTaggedOn])
+	message := fields[tagFieldMessage]
+	committedBy := fields[tagFieldCommittedBy]
+	committedOn := parseISO8601Date(fields[tagFieldCommittedOn])
+	committerDate := parseISO8601Date(fields[tagFieldCommitterDate])
+	commitSubject := fields[tagFieldCommitSubject]
 
 	// Fallback: use committerDate if committedOn is zero (lightweight tags)
 	if committedOn.IsZero() && !committerDate.IsZero() {
@@ -515,34 +890,116 @@ func parseTagBlock(lines []string) Tag {
 	return NewTag(name, commit, actualMessage, taggerName, taggerEmail, taggedOn)
 }
 
-func (g *GitCli) BranchExists(branchName string, caseInsensitive bool) (bool, error) {
-	branches, err := g.ListLocalBranches()
-	if err != nil {
-		return false, err
-	}
-
-	for _, branch := range branches {
-		if caseInsensitive {
-			if strings.EqualFold(branch.Name, branchName) {
-				return true, nil
-			}
-		} else {
-			if branch.Name == branchName {
+func (g *GitCli) BranchExists(ctx context.Context, branchName string, caseInsensitive bool) (bool, error) {
+	if caseInsensitive {
+		// refs can only be matched case-insensitively by listing them; only their names are read
+		output, err := g.executeGitCommand(ctx, "for-each-ref", "--format=%(refname)", "refs/heads/")
+		if err != nil {
+			return false, fmt.Errorf("failed to list branch names: %w", err)
+		}
+		for _, ref := range strings.Split(output, "\n") {
+			if strings.EqualFold(strings.TrimPrefix(ref, "refs/heads/"), branchName) {
 				return true, nil
 			}
 		}
+		return false, nil
 	}
 
-	return false, nil
+	_, err := g.probeGitCommand(ctx, exitsWith1, "show-ref", "--verify", "--quiet", "refs/heads/"+branchName)
+	if isExitCode(err, 1) {
+		// show-ref exits with 1 when the ref does not exist
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check branch %s: %w", branchName, err)
+	}
+	return true, nil
 }
 
-func (g *GitCli) ListWorktrees() ([]Worktree, error) {
-	branches, err := g.ListLocalBranches()
+func (g *GitCli) ResolveRef(ctx context.Context, ref string) (string, error) {
+	if ref == "" || strings.HasPrefix(ref, "-") {
+		return "", fmt.Errorf("invalid ref %q", ref)
+	}
+	output, err := g.executeGitCommand(ctx, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("ref %q does not name a commit", ref)
+	}
+	return output, nil
+}
+
+func (g *GitCli) GetAheadBehind(ctx context.Context, branchName, baseRef string) (int, int, error) {
+	if branchName == "" || baseRef == "" || strings.HasPrefix(branchName, "-") || strings.HasPrefix(baseRef, "-") {
+		return 0, 0, fmt.Errorf("invalid refs %q and %q", branchName, baseRef)
+	}
+	output, err := g.executeGitCommand(ctx, "rev-list", "--left-right", "--count", branchName+"..."+baseRef, "--")
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to compare %s with %s: %w", branchName, baseRef, err)
+	}
+	return parseAheadBehind(output)
+}
+
+// Field positions of a record in ListUnreachableCommits' log format.
+const (
+	commitFieldSHA = iota
+	commitFieldCommittedOn
+	commitFieldCommittedBy
+	commitFieldSubject
+	commitFieldCount
+)
+
+var commitFormat = [commitFieldCount]string{
+	commitFieldSHA:         "%H",
+	commitFieldCommittedOn: "%cI",
+	commitFieldCommittedBy: "%cn",
+	commitFieldSubject:     "%s",
+}
+
+func (g *GitCli) ListUnreachableCommits(ctx context.Context, branchName string) ([]Commit, error) {
+	if branchName == "" || strings.HasPrefix(branchName, "-") {
+		return nil, fmt.Errorf("invalid branch name %q", branchName)
+	}
+	// log spells NUL %x00, where for-each-ref spells it %00
+	format := strings.Join(commitFormat[:], "%x00") + "%x00"
+	// --exclude applies to the --branches that follows it, and takes the name without refs/heads/, so every
+	// ref but the branch itself is subtracted; branch names cannot contain glob characters
+	output, err := g.executeGitCommand(ctx, "log", "--format="+format, "refs/heads/"+branchName,
+		"--not", "--exclude="+branchName, "--branches", "--tags", "--remotes", "--")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the commits only %s reaches: %w", branchName, err)
+	}
+	records := splitRecords(output, commitFieldCount)
+	commits := make([]Commit, 0, len(records))
+	for _, record := range records {
+		commits = append(commits, NewCommit(record[commitFieldSHA], record[commitFieldSubject],
+			parseISO8601Date(record[commitFieldCommittedOn]), record[commitFieldCommittedBy]))
+	}
+	return commits, nil
+}
+
+// parseAheadBehind parses the output of git rev-list --left-right --count, e.g. "2\t5".
+func parseAheadBehind(output string) (int, int, error) {
+	fields := strings.Fields(output)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output %q", output)
+	}
+	ahead, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected rev-list output %q", output)
+	}
+	behind, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected rev-list output %q", output)
+	}
+	return ahead, behind, nil
+}
+
+func (g *GitCli) ListWorktrees(ctx context.Context) ([]Worktree, error) {
+	branches, err := g.ListLocalBranches(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list branches for worktree lookup: %w", err)
 	}
 
-	tags, err := g.ListTags()
+	tags, err := g.ListTags(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tags for worktree lookup: %w", err)
 	}
@@ -557,100 +1014,468 @@ func (g *GitCli) ListWorktrees() ([]Worktree, error) {
 		tagMap[t.Commit().SHA] = t
 	}
 
-	output, err := g.executeGitCommand("worktree", "list", "--porcelain")
+	output, err := g.executeGitCommand(ctx, "worktree", "list", "--porcelain")
 	if err != nil {
 		return nil, fmt.Errorf("failed to list worktrees: %w", err)
 	}
 
-	return g.parseWorktreesFromPorcelain(output, branchMap, tagMap)
+	return g.parseWorktreesFromPorcelain(ctx, output, branchMap, tagMap)
 }
 
-// parseWorktreesFromPorcelain parses the output of `git worktree list --porcelain`
-func (g *GitCli) parseWorktreesFromPorcelain(output string, branchMap map[string]LocalBranch, tagMap map[string]Tag) ([]Worktree, error) {
+// parseWorktreesFromPorcelain parses the output of `git worktree list --porcelain`.
+// The commits of detached worktrees that are not at a tag are looked up together, with one git call.
+func (g *GitCli) parseWorktreesFromPorcelain(ctx context.Context, output string, branchMap map[string]LocalBranch, tagMap map[string]Tag) ([]Worktree, error) {
 	blocks := splitIntoBlocks(output)
 	worktrees := make([]Worktree, 0, len(blocks))
 
-	for _, block := range blocks {
-		worktree, err := g.parseWorktreeBlock(block, branchMap, tagMap)
-		if err != nil {
-			return nil, err
+	var detached []string
+	for i, block := range blocks {
+		worktree := g.parseWorktreeBlock(block, branchMap, tagMap)
+		// git always lists the main worktree first
+		worktree.IsMain = i == 0
+		if worktree.AbsolutePath == "" {
+			continue
 		}
-		if worktree.AbsolutePath != "" {
-			worktrees = append(worktrees, worktree)
+		if worktree.Ref != nil && worktree.Ref.Type() == WorktreeRefTypeCommit && !slices.Contains(detached, worktree.Ref.Commit().SHA) {
+			detached = append(detached, worktree.Ref.Commit().SHA)
 		}
+		worktrees = append(worktrees, worktree)
+	}
+	if len(detached) == 0 {
+		return worktrees, nil
 	}
 
+	commits, err := g.getCommitsBySHA(ctx, detached)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits for detached worktrees: %w", err)
+	}
+	for i, wt := range worktrees {
+		if wt.Ref == nil || wt.Ref.Type() != WorktreeRefTypeCommit {
+			continue
+		}
+		if commit, ok := commits[wt.Ref.Commit().SHA]; ok {
+			worktrees[i].Ref = &commit
+		}
+	}
 	return worktrees, nil
 }
 
-func (g *GitCli) parseWorktreeBlock(lines []string, branchMap map[string]LocalBranch, tagMap map[string]Tag) (Worktree, error) {
+// parseWorktreeBlock parses one worktree of `git worktree list --porcelain`. A detached worktree that is not
+// at a tag gets a commit with only its SHA; parseWorktreesFromPorcelain looks up the rest.
+func (g *GitCli) parseWorktreeBlock(lines []string, branchMap map[string]LocalBranch, tagMap map[string]Tag) Worktree {
 	fields := parseLineFields(lines)
 
-	absolutePath := fields["worktree"]
+	absolutePath := pathutil.FromGit(fields["worktree"])
 	sha := fields["HEAD"]
 
 	// Bare worktrees don't have a ref, skip
 	if _, isBare := fields["bare"]; isBare {
-		return Worktree{AbsolutePath: absolutePath}, nil
+		return Worktree{AbsolutePath: absolutePath, IsBare: true}
 	}
 
 	branchName := strings.TrimPrefix(fields["branch"], "refs/heads/")
 	_, detached := fields["detached"]
 
-	worktree := Worktree{AbsolutePath: absolutePath}
+	worktree := Worktree{AbsolutePath: absolutePath, Prunable: fields["prunable"]}
 
 	if branchName != "" {
 		branch, ok := branchMap[branchName]
 		if !ok {
-			return Worktree{}, fmt.Errorf("worktree branch '%s' at sha '%s' not found in local map", branchName, sha)
+			g.log.Debug("Worktree branch has no ref", "branch", branchName, "path", absolutePath)
+			branch = NewLocalBranch(branchName, "", absolutePath, false, 0, 0, Commit{SHA: sha})
+			worktree.BranchMissing = true
 		}
 		worktree.Ref = &branch
 	} else if detached {
 		if tag, ok := tagMap[sha]; ok {
 			worktree.Ref = &tag
 		} else {
-			// TODO: figure out a way to get the commit information without needing a reference to g GitCli
-			commit, err := g.getCommitBySHA(sha)
-			if err != nil {
-				return Worktree{}, fmt.Errorf("failed to get commit for detached worktree: %w", err)
-			}
+			commit := Commit{SHA: sha}
 			worktree.Ref = &commit
 		}
 	}
 
-	return worktree, nil
+	return worktree
 }
 
-func (g *GitCli) CreateWorktreeForNewBranch(newBranchName, worktreeAbsPath string) error {
+func (g *GitCli) GetWorktreeGitDir(ctx context.Context, worktreeAbsPath string) (string, error) {
+	gitDir, err := g.executeGitCommand(ctx, "-C", worktreeAbsPath, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", fmt.Errorf("failed to get git dir of worktree: %w", err)
+	}
+	return pathutil.FromGit(gitDir), nil
+}
+
+func (g *GitCli) GetLastActivity(ctx context.Context, worktreeAbsPath string) (time.Time, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if latest, ok := g.activity[worktreeAbsPath]; ok {
+		return latest, nil
+	}
+
+	gitDir, err := g.GetWorktreeGitDir(ctx, worktreeAbsPath)
+	if err != nil {
+		return time.Time{}, err
+	}
+	output, err := g.executeGitCommand(ctx, "-C", worktreeAbsPath, "ls-files", "-z")
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to list tracked files: %w", err)
+	}
+
+	paths := []string{
+		filepath.Join(gitDir, "HEAD"),
+		filepath.Join(gitDir, "index"),
+		filepath.Join(gitDir, "logs", "HEAD"),
+	}
+	for _, file := range strings.Split(output, "\x00") {
+		if file != "" {
+			paths = append(paths, filepath.Join(worktreeAbsPath, file))
+		}
+	}
+
+	var latest time.Time
+	for _, path := range paths {
+		info, err := os.Lstat(path)
+		if err != nil {
+			continue // deleted tracked files and fresh worktrees without a reflog have nothing to report
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	g.activity[worktreeAbsPath] = latest
+	return latest, nil
+}
+
+func (g *GitCli) ListUninitializedSubmodules(ctx context.Context, worktreeAbsPath string) ([]string, error) {
+	output, err := g.executeGitCommand(ctx, "-C", worktreeAbsPath, "submodule", "status")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get submodule status: %w", err)
+	}
+	return parseUninitializedSubmodules(output), nil
+}
+
+func (g *GitCli) IsRebaseInProgress(ctx context.Context, worktreeAbsPath string) (bool, error) {
+	gitDir, err := g.GetWorktreeGitDir(ctx, worktreeAbsPath)
+	if err != nil {
+		return false, err
+	}
+	// rebase-merge is used by the default merge backend, rebase-apply by the apply backend
+	for _, dir := range []string{"rebase-merge", "rebase-apply"} {
+		if _, err := os.Stat(filepath.Join(gitDir, dir)); err == nil {
+			return true, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return false, fmt.Errorf("failed to check for a rebase in progress: %w", err)
+		}
+	}
+	return false, nil
+}
+
+func (g *GitCli) IsDirty(ctx context.Context, worktreeAbsPath string) (bool, error) {
+	output, err := g.executeGitCommand(ctx, "-C", worktreeAbsPath, "status", "--porcelain", "--untracked-files=normal")
+	if err != nil {
+		return false, fmt.Errorf("failed to get worktree status: %w", err)
+	}
+	return output != "", nil
+}
+
+func (g *GitCli) ListConflictedFiles(ctx context.Context, worktreeAbsPath string) ([]string, error) {
+	output, err := g.executeGitCommand(ctx, "-C", worktreeAbsPath, "diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conflicted files: %w", err)
+	}
+	if output == "" {
+		return []string{}, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+// parseUninitializedSubmodules returns the paths of the submodules that `git submodule status` marks with "-".
+// Each line is "<flag><sha> <path>[ (<describe>)]", where the flag is " ", "-", "+", or "U".
+func parseUninitializedSubmodules(output string) []string {
+	var paths []string
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.HasPrefix(line, "-") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) >= 2 {
+			paths = append(paths, fields[1])
+		}
+	}
+	return paths
+}
+
+func (g *GitCli) CreateWorktreeForNewBranch(ctx context.Context, newBranchName, worktreeAbsPath string) error {
 	g.log.Info("Creating worktree for new branch", "branch", newBranchName, "path", worktreeAbsPath)
 	args := []string{"worktree", "add", "-b", newBranchName, worktreeAbsPath}
-	return g.executeMutatingCommand("failed to create worktree for new branch", args...)
+	return g.executeMutatingCommand(ctx, "failed to create worktree for new branch", args...)
 }
 
-func (g *GitCli) CreateWorktreeForNewBranchFromRef(newBranchName, worktreeAbsPath, baseRef string) error {
+func (g *GitCli) CreateWorktreeForNewBranchFromRef(ctx context.Context, newBranchName, worktreeAbsPath, baseRef string) error {
 	g.log.Info("Creating worktree for new branch from ref", "branch", newBranchName, "path", worktreeAbsPath, "baseRef", baseRef)
 	args := []string{"worktree", "add", "-b", newBranchName, worktreeAbsPath}
 	if baseRef != "" {
 		args = append(args, baseRef)
 	}
-	return g.executeMutatingCommand("failed to create worktree for new branch from ref", args...)
+	return g.executeMutatingCommand(ctx, "failed to create worktree for new branch from ref", args...)
 }
 
-func (g *GitCli) CreateWorktreeForExistingBranch(branchName, worktreeAbsPath string) error {
+func (g *GitCli) CreateWorktreeForExistingBranch(ctx context.Context, branchName, worktreeAbsPath string) error {
 	g.log.Info("Creating worktree for existing branch", "branch", branchName, "path", worktreeAbsPath)
 	args := []string{"worktree", "add", worktreeAbsPath, branchName}
-	return g.executeMutatingCommand("failed to create worktree for existing branch", args...)
+	return g.executeMutatingCommand(ctx, "failed to create worktree for existing branch", args...)
 }
 
-func (g *GitCli) FetchRemoteBranch(remote, remoteRef, localRef string) error {
+func (g *GitCli) FetchRemoteBranch(ctx context.Context, remote, remoteRef, localRef string) error {
 	g.log.Info("Fetching remote branch", "remote", remote, "remoteRef", remoteRef, "localRef", localRef)
 	refSpec := remoteRef + ":" + localRef
 	args := []string{"fetch", remote, refSpec}
-	return g.executeMutatingCommand("failed to fetch remote branch", args...)
+	return g.executeMutatingCommand(ctx, "failed to fetch remote branch", args...)
+}
+
+func (g *GitCli) FetchRef(ctx context.Context, remote, remoteRef string) (string, error) {
+	g.log.Info("Fetching ref", "remote", remote, "ref", remoteRef)
+	if err := g.executeMutatingCommand(ctx, "failed to fetch ref", "fetch", remote, remoteRef); err != nil {
+		return "", err
+	}
+	if g.dryRun {
+		return "", nil
+	}
+	sha, err := g.executeGitCommand(ctx, "rev-parse", "FETCH_HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve fetched ref: %w", err)
+	}
+	return sha, nil
+}
+
+func (g *GitCli) FastForward(ctx context.Context, worktreeAbsPath, ref string) error {
+	g.log.Info("Fast-forwarding worktree", "path", worktreeAbsPath, "ref", ref)
+	err := g.executeMutatingCommand(ctx, "failed to fast-forward", "-C", worktreeAbsPath, "merge", "--ff-only", ref)
+	if err != nil && strings.Contains(err.Error(), "Not possible to fast-forward") {
+		return fmt.Errorf("failed to fast-forward %s to %s: %w", worktreeAbsPath, ref, ErrNotFastForward)
+	}
+	return err
+}
+
+func (g *GitCli) Rebase(ctx context.Context, worktreeAbsPath, ref string) error {
+	g.log.Info("Rebasing worktree", "path", worktreeAbsPath, "ref", ref)
+	err := g.executeMutatingCommand(ctx, "failed to rebase", "-C", worktreeAbsPath, "rebase", ref)
+	if err == nil {
+		return nil
+	}
+
+	// a rebase that refused to start (e.g., uncommitted changes) leaves nothing to abort
+	conflicts, diffErr := g.ListConflictedFiles(ctx, worktreeAbsPath)
+	if diffErr != nil || len(conflicts) == 0 {
+		return err
+	}
+	if err := g.executeMutatingCommand(ctx, "failed to abort rebase", "-C", worktreeAbsPath, "rebase", "--abort"); err != nil {
+		return err
+	}
+	return &ConflictError{Files: conflicts}
 }
 
-func (g *GitCli) FetchRemote(remoteName string) (string, error) {
+func (g *GitCli) FetchRemote(ctx context.Context, remoteName string, progress func(line string)) (string, error) {
 	g.log.Info("Fetching from remote", "remote", remoteName)
 	args := []string{"fetch", remoteName, "--prune", "--prune-tags", "--tags"}
-	return g.executeMutatingCommandWithOutput("failed to fetch from remote", args...)
+	if progress != nil {
+		// git only reports progress to a terminal unless asked
+		args = append(args, "--progress")
+	}
+	return g.executeMutatingCommandWithOutput(ctx, "failed to fetch from remote", progress, args...)
+}
+
+func (g *GitCli) CloneBare(ctx context.Context, url, bareDir string, progress func(line string)) error {
+	g.log.Info("Cloning bare repository", "url", url, "dir", bareDir)
+	args := []string{"clone", "--bare"}
+	if progress != nil {
+		args = append(args, "--progress")
+	}
+	if _, err := g.executeMutatingCommandWithOutput(ctx, "failed to clone repository", progress, append(args, "--", url, bareDir)...); err != nil {
+		return err
+	}
+	return g.executeMutatingCommand(ctx, "failed to configure origin's fetch refspec",
+		"-C", bareDir, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*")
+}
+
+func (g *GitCli) MoveWorktree(ctx context.Context, worktreeAbsPath, newAbsPath string) error {
+	g.log.Info("Moving worktree", "path", worktreeAbsPath, "newPath", newAbsPath)
+	args := []string{"worktree", "move", worktreeAbsPath, newAbsPath}
+	return g.executeMutatingCommand(ctx, "failed to move worktree", args...)
+}
+
+func (g *GitCli) RepairWorktree(ctx context.Context, worktreeAbsPath string) error {
+	g.log.Info("Repairing worktree", "path", worktreeAbsPath)
+	args := []string{"worktree", "repair", worktreeAbsPath}
+	return g.executeMutatingCommand(ctx, "failed to repair worktree", args...)
+}
+
+func (g *GitCli) RemoveWorktree(ctx context.Context, worktreeAbsPath string, force bool) error {
+	g.log.Info("Removing worktree", "path", worktreeAbsPath, "force", force)
+	args := []string{"worktree", "remove", worktreeAbsPath}
+	if force {
+		args = append(args, "--force")
+	}
+	return g.executeMutatingCommand(ctx, "failed to remove worktree", args...)
+}
+
+func (g *GitCli) PruneWorktrees(ctx context.Context) error {
+	g.log.Info("Pruning worktrees")
+	return g.executeMutatingCommand(ctx, "failed to prune worktrees", "worktree", "prune")
+}
+
+func (g *GitCli) InitSubmodules(ctx context.Context, worktreeAbsPath string) error {
+	g.log.Info("Initializing submodules", "path", worktreeAbsPath)
+	args := []string{"-C", worktreeAbsPath, "submodule", "update", "--init"}
+	return g.executeMutatingCommand(ctx, "failed to initialize submodules", args...)
+}
+
+func (g *GitCli) UpdateRef(ctx context.Context, ref, sha string) error {
+	g.log.Info("Updating ref", "ref", ref, "sha", sha)
+	return g.executeMutatingCommand(ctx, "failed to update ref", "update-ref", ref, sha)
+}
+
+func (g *GitCli) DeleteRef(ctx context.Context, ref string) error {
+	g.log.Info("Deleting ref", "ref", ref)
+	// with no old value given, update-ref -d succeeds for a missing ref, so check it exists first
+	_, err := g.probeGitCommand(ctx, exitsWith1, "show-ref", "--verify", "--quiet", ref)
+	if isExitCode(err, 1) {
+		return fmt.Errorf("failed to delete ref: %s does not exist", ref)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete ref: %w", err)
+	}
+	return g.executeMutatingCommand(ctx, "failed to delete ref", "update-ref", "-d", ref)
+}
+
+func (g *GitCli) RenameBranch(ctx context.Context, oldName, newName string) error {
+	g.log.Info("Renaming branch", "branch", oldName, "newName", newName)
+	args := []string{"branch", "-m", oldName, newName}
+	return g.executeMutatingCommand(ctx, "failed to rename branch", args...)
+}
+
+func (g *GitCli) DeleteBranch(ctx context.Context, branchName string, force bool) error {
+	g.log.Info("Deleting branch", "branch", branchName, "force", force)
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+	err := g.executeMutatingCommand(ctx, "failed to delete branch", "branch", flag, branchName)
+	if err != nil && strings.Contains(err.Error(), "is not fully merged") {
+		return fmt.Errorf("failed to delete %s: %w", branchName, ErrNotMerged)
+	}
+	return err
+}
+
+func (g *GitCli) DeleteRemoteBranch(ctx context.Context, remote, branchName string) error {
+	g.log.Info("Deleting remote branch", "remote", remote, "branch", branchName)
+	return g.executeMutatingCommand(ctx, "failed to delete remote branch", "push", remote, "--delete", branchName)
+}
+
+func (g *GitCli) Push(ctx context.Context, remote, branchName string) error {
+	g.log.Info("Pushing branch", "remote", remote, "branch", branchName)
+	return g.executeMutatingCommand(ctx, "failed to push branch", "push", "--set-upstream", remote, "refs/heads/"+branchName+":refs/heads/"+branchName)
+}
+
+func (g *GitCli) GetBranchDescription(ctx context.Context, branchName string) (string, error) {
+	output, err := g.probeGitCommand(ctx, exitsWith1, "config", "--get", "branch."+branchName+".description")
+	if isExitCode(err, 1) {
+		// git config exits with 1 when the key is not set
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get branch description: %w", err)
+	}
+	return output, nil
+}
+
+func (g *GitCli) SetBranchDescription(ctx context.Context, branchName, description string) error {
+	g.log.Info("Setting branch description", "branch", branchName)
+	args := []string{"config", "branch." + branchName + ".description", description}
+	return g.executeMutatingCommand(ctx, "failed to set branch description", args...)
+}
+
+func (g *GitCli) SetBranchUpstream(ctx context.Context, branchName, upstream string) error {
+	g.log.Info("Setting branch upstream", "branch", branchName, "upstream", upstream)
+	args := []string{"branch", "--set-upstream-to=" + upstream, branchName}
+	return g.executeMutatingCommand(ctx, "failed to set branch upstream", args...)
+}
+
+func (g *GitCli) GetWorktreeConfigState(ctx context.Context) (WorktreeConfigState, error) {
+	commonDir, err := g.GetCommonDir(ctx)
+	if err != nil {
+		return WorktreeConfigState{}, err
+	}
+	shared := filepath.Join(commonDir, "config")
+
+	var state WorktreeConfigState
+	enabled, err := g.getFileConfig(ctx, shared, "--bool", "extensions.worktreeConfig")
+	if err != nil {
+		return WorktreeConfigState{}, err
+	}
+	bare, err := g.getFileConfig(ctx, shared, "--bool", "core.bare")
+	if err != nil {
+		return WorktreeConfigState{}, err
+	}
+	state.SharedWorktree, err = g.getFileConfig(ctx, shared, "core.worktree")
+	if err != nil {
+		return WorktreeConfigState{}, err
+	}
+	state.Enabled = enabled == "true"
+	state.SharedBare = bare == "true"
+	return state, nil
+}
+
+func (g *GitCli) EnableWorktreeConfig(ctx context.Context) error {
+	state, err := g.GetWorktreeConfigState(ctx)
+	if err != nil {
+		return err
+	}
+	commonDir, err := g.GetCommonDir(ctx)
+	if err != nil {
+		return err
+	}
+	shared := filepath.Join(commonDir, "config")
+	private := filepath.Join(commonDir, "config.worktree")
+
+	g.log.Info("Enabling extensions.worktreeConfig", "config", shared)
+	// the settings are moved before the extension is turned on, so a failure never leaves every
+	// worktree reading them from the shared config while per-worktree config is honored
+	if state.SharedWorktree != "" {
+		if err := g.moveFileConfig(ctx, shared, private, "core.worktree", state.SharedWorktree); err != nil {
+			return err
+		}
+	}
+	if state.SharedBare {
+		if err := g.moveFileConfig(ctx, shared, private, "core.bare", "true"); err != nil {
+			return err
+		}
+	}
+	return g.executeMutatingCommand(ctx, "failed to enable extensions.worktreeConfig",
+		"config", "--file", shared, "extensions.worktreeConfig", "true")
+}
+
+// getFileConfig returns a key from the given config file only, ignoring the user's global config.
+// Options such as --bool go before the key. Returns "" if the key is not set.
+func (g *GitCli) getFileConfig(ctx context.Context, file string, args ...string) (string, error) {
+	output, err := g.probeGitCommand(ctx, exitsWith1, append([]string{"config", "--file", file, "--get"}, args...)...)
+	if isExitCode(err, 1) {
+		// git config exits with 1 when the key is not set
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", args[len(args)-1], err)
+	}
+	return output, nil
+}
+
+// moveFileConfig sets key to value in the config file to, then removes it from the config file from.
+func (g *GitCli) moveFileConfig(ctx context.Context, from, to, key, value string) error {
+	errContext := "failed to move " + key + " to " + to
+	if err := g.executeMutatingCommand(ctx, errContext, "config", "--file", to, key, value); err != nil {
+		return err
+	}
+	return g.executeMutatingCommand(ctx, errContext, "config", "--file", from, "--unset", key)
 }
@@ -0,0 +1,165 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// benchRefCount is the number of refs in the synthetic inputs, sized like a large monorepo.
+const benchRefCount = 10_000
+
+// syntheticBranchOutput returns `git for-each-ref` output in ListLocalBranches' format for n branches.
+func syntheticBranchOutput(n int) string {
+	var sb strings.Builder
+	for i := range n {
+		head, upstream, track, worktreePath := " ", "", "", ""
+		if i == 0 {
+			head = "*"
+		}
+		if i%2 == 0 {
+			upstream = fmt.Sprintf("origin/feature/branch-%d", i)
+			track = fmt.Sprintf("[ahead %d, behind %d]", i%5, i%3)
+		}
+		if i%100 == 0 {
+			worktreePath = fmt.Sprintf("/ws/wt-branch-%d", i)
+		}
+		fmt.Fprintf(&sb, "feature/branch-%d\x00%s\x00%07x\x00%s\x00%s\x00", i, head, i, upstream, track)
+		sb.WriteString("2024-01-15T10:30:00-08:00\x00Test User\x00")
+		fmt.Fprintf(&sb, "Commit subject for branch %d\x00%s\x00\n", i, worktreePath)
+	}
+	return sb.String()
+}
+
+// syntheticTagOutput returns `git for-each-ref` output in ListTags' format for n tags,
+// alternating annotated and lightweight tags.
+func syntheticTagOutput(n int) string {
+	var sb strings.Builder
+	for i := range n {
+		fmt.Fprintf(&sb, "v1.%d.0\x00", i)
+		if i%2 == 0 {
+			fmt.Fprintf(&sb, "tag\x00%040x\x00%040x\x00", i+1_000_000, i)
+			sb.WriteString("Release Bot\x00<release@example.com>\x002024-01-15T10:30:00-08:00\x00")
+			fmt.Fprintf(&sb, "Release v1.%d.0\x00", i)
+			sb.WriteString("Test User\x002024-01-14T10:30:00-08:00\x00\x00")
+			fmt.Fprintf(&sb, "Prepare v1.%d.0\x00\n", i)
+		} else {
+			fmt.Fprintf(&sb, "commit\x00%040x\x00\x00\x00\x00\x00", i)
+			fmt.Fprintf(&sb, "Prepare v1.%d.0\x00", i)
+			sb.WriteString("\x00\x002024-01-14T10:30:00-08:00\x00\x00\n")
+		}
+	}
+	return sb.String()
+}
+
+// syntheticWorktreeOutput returns `git worktree list --porcelain` output for n worktrees on branches
+// feature/branch-<i>, and the branch map ListWorktrees would build. Detached worktrees are left out
+// because parsing them shells out to git log for their commit.
+func syntheticWorktreeOutput(n int) (string, map[string]LocalBranch) {
+	var sb strings.Builder
+	branchMap := make(map[string]LocalBranch, n)
+	for i := range n {
+		fmt.Fprintf(&sb, "worktree /ws/wt-branch-%d\n", i)
+		fmt.Fprintf(&sb, "HEAD %040x\n", i)
+		name := fmt.Sprintf("feature/branch-%d", i)
+		fmt.Fprintf(&sb, "branch refs/heads/%s\n", name)
+		branchMap[name] = NewLocalBranch(name, "", fmt.Sprintf("/ws/wt-branch-%d", i), false, 0, 0, Commit{SHA: fmt.Sprintf("%040x", i)})
+		sb.WriteString("\n")
+	}
+	return sb.String(), branchMap
+}
+
+func BenchmarkSplitIntoBlocks(b *testing.B) {
+	output, _ := syntheticWorktreeOutput(benchRefCount)
+	b.SetBytes(int64(len(output)))
+	b.ReportAllocs()
+	for b.Loop() {
+		splitIntoBlocks(output)
+	}
+}
+
+func BenchmarkParseBranchesFromFormat(b *testing.B) {
+	output := syntheticBranchOutput(benchRefCount)
+	b.SetBytes(int64(len(output)))
+	b.ReportAllocs()
+	for b.Loop() {
+		parseBranchesFromFormat(output)
+	}
+}
+
+func BenchmarkParseTagsFromFormat(b *testing.B) {
+	output := syntheticTagOutput(benchRefCount)
+	b.SetBytes(int64(len(output)))
+	b.ReportAllocs()
+	for b.Loop() {
+		parseTagsFromFormat(output)
+	}
+}
+
+func BenchmarkParseWorktreesFromPorcelain(b *testing.B) {
+	g := newTestGitCli()
+	output, branchMap := syntheticWorktreeOutput(benchRefCount)
+	tagMap := map[string]Tag{}
+	b.SetBytes(int64(len(output)))
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := g.parseWorktreesFromPorcelain(b.Context(), output, branchMap, tagMap); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// TestParsingAllocationBudget fails when a parser allocates more per ref than its budget,
+// so regressions in the hot paths of ListLocalBranches, ListTags, and ListWorktrees show up in CI.
+// Budgets leave headroom over the measured allocations; tighten them when parsing gets cheaper.
+func TestParsingAllocationBudget(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping allocation budget test in short mode")
+	}
+
+	g := newTestGitCli()
+	branchOutput := syntheticBranchOutput(benchRefCount)
+	tagOutput := syntheticTagOutput(benchRefCount)
+	worktreeOutput, branchMap := syntheticWorktreeOutput(benchRefCount)
+
+	tests := []struct {
+		name         string
+		budgetPerRef float64
+		parse        func()
+	}{
+		{
+			name:         "splitIntoBlocks",
+			budgetPerRef: 4,
+			parse:        func() { splitIntoBlocks(worktreeOutput) },
+		},
+		{
+			name:         "parseBranchesFromFormat",
+			budgetPerRef: 2,
+			parse:        func() { parseBranchesFromFormat(branchOutput) },
+		},
+		{
+			name:         "parseTagsFromFormat",
+			budgetPerRef: 3,
+			parse:        func() { parseTagsFromFormat(tagOutput) },
+		},
+		{
+			name:         "parseWorktreesFromPorcelain",
+			budgetPerRef: 8,
+			parse: func() {
+				if _, err := g.parseWorktreesFromPorcelain(t.Context(), worktreeOutput, branchMap, map[string]Tag{}); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			perRef := testing.AllocsPerRun(3, tt.parse) / benchRefCount
+			t.Logf("%.2f allocations per ref", perRef)
+			if perRef > tt.budgetPerRef {
+				t.Errorf("%s allocates %.2f times per ref, over the budget of %.0f", tt.name, perRef, tt.budgetPerRef)
+			}
+		})
+	}
+}
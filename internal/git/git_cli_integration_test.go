@@ -1,11 +1,17 @@
 package git
 
 import (
+	"bytes"
+	"errors"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	clog "github.com/charmbracelet/log"
+	"github.com/jmcampanini/grove-cli/internal/timing"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -22,7 +28,7 @@ func TestGetWorktreeRoot_Integration(t *testing.T) {
 	repo := newTestRepo(t)
 	repo.commit("initial commit")
 
-	root, err := repo.Git.GetWorktreeRoot()
+	root, err := repo.Git.GetWorktreeRoot(t.Context())
 
 	require.NoError(t, err)
 	assert.Equal(t, repo.path(), root)
@@ -40,8 +46,8 @@ func TestGetWorktreeRoot_Integration_FromSubdirectory(t *testing.T) {
 	subdir := filepath.Join(repo.path(), "subdir", "nested")
 	require.NoError(t, os.MkdirAll(subdir, 0755))
 
-	subdirGit := New(false, subdir, testTimeout).(*GitCli)
-	root, err := subdirGit.GetWorktreeRoot()
+	subdirGit := New(clog.Default(), nil, false, subdir, testTimeout, testTimeout, 0).(*GitCli)
+	root, err := subdirGit.GetWorktreeRoot(t.Context())
 
 	require.NoError(t, err)
 	assert.Equal(t, repo.path(), root)
@@ -54,15 +60,39 @@ func TestGetWorktreeRoot_Integration_OutsideRepo(t *testing.T) {
 
 	// Use temp dir that's not a git repo
 	tmpDir := t.TempDir()
-	outsideGit := New(false, tmpDir, testTimeout).(*GitCli)
+	outsideGit := New(clog.Default(), nil, false, tmpDir, testTimeout, testTimeout, 0).(*GitCli)
 
-	root, err := outsideGit.GetWorktreeRoot()
+	root, err := outsideGit.GetWorktreeRoot(t.Context())
 
 	// Should return empty string, no error (not in a git repo is valid)
 	require.NoError(t, err)
 	assert.Empty(t, root)
 }
 
+func TestNew_Integration_Timings(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	timings := timing.NewRecorder(time.Now())
+	g := New(clog.Default(), timings, false, repo.path(), testTimeout, testTimeout, 0)
+
+	_, err := g.GetCurrentBranch(t.Context())
+	require.NoError(t, err)
+	_, err = g.ResolveRef(t.Context(), "no-such-branch")
+	require.Error(t, err)
+
+	calls := timings.Calls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, "git rev-parse", calls[0].Name())
+	assert.Equal(t, []string{"rev-parse", "--abbrev-ref", "HEAD"}, calls[0].Args)
+	assert.Positive(t, calls[0].Duration)
+	assert.False(t, calls[0].Failed)
+	assert.True(t, calls[1].Failed)
+}
+
 // =============================================================================
 // GetCurrentBranch tests
 // =============================================================================
@@ -75,7 +105,7 @@ func TestGetCurrentBranch_Integration(t *testing.T) {
 	repo := newTestRepo(t)
 	repo.commit("initial commit")
 
-	branch, err := repo.Git.GetCurrentBranch()
+	branch, err := repo.Git.GetCurrentBranch(t.Context())
 
 	require.NoError(t, err)
 	assert.Equal(t, "main", branch)
@@ -91,7 +121,7 @@ func TestGetCurrentBranch_Integration_OnFeatureBranch(t *testing.T) {
 	repo.createBranch("feature-x")
 	repo.checkout("feature-x")
 
-	branch, err := repo.Git.GetCurrentBranch()
+	branch, err := repo.Git.GetCurrentBranch(t.Context())
 
 	require.NoError(t, err)
 	assert.Equal(t, "feature-x", branch)
@@ -106,7 +136,7 @@ func TestGetCurrentBranch_Integration_DetachedHEAD(t *testing.T) {
 	sha := repo.commit("initial commit")
 	repo.checkoutDetached(sha)
 
-	branch, err := repo.Git.GetCurrentBranch()
+	branch, err := repo.Git.GetCurrentBranch(t.Context())
 
 	require.NoError(t, err)
 	assert.Equal(t, "HEAD", branch)
@@ -124,7 +154,7 @@ func TestGetCommitSubject_Integration(t *testing.T) {
 	repo := newTestRepo(t)
 	repo.commit("This is my commit message")
 
-	subject, err := repo.Git.GetCommitSubject()
+	subject, err := repo.Git.GetCommitSubject(t.Context())
 
 	require.NoError(t, err)
 	assert.Equal(t, "This is my commit message", subject)
@@ -142,7 +172,7 @@ func TestGetCommitSubject_Integration_MultiLineMessage(t *testing.T) {
 	runGit(t, repo.path(), "add", "-A")
 	runGit(t, repo.path(), "commit", "-m", "First line\n\nBody paragraph")
 
-	subject, err := repo.Git.GetCommitSubject()
+	subject, err := repo.Git.GetCommitSubject(t.Context())
 
 	require.NoError(t, err)
 	assert.Equal(t, "First line", subject)
@@ -160,7 +190,7 @@ func TestGetMainWorktreePath_Integration(t *testing.T) {
 	repo := newTestRepo(t)
 	repo.commit("initial commit")
 
-	path, err := repo.Git.GetMainWorktreePath()
+	path, err := repo.Git.GetMainWorktreePath(t.Context())
 
 	require.NoError(t, err)
 	// Compare resolved paths to handle symlinks (e.g., /var -> /private/var on macOS)
@@ -181,15 +211,144 @@ func TestGetMainWorktreePath_Integration_FromLinkedWorktree(t *testing.T) {
 	repo.createWorktree(worktreePath, "feature")
 
 	// Create GitCli pointing to the linked worktree
-	linkedGit := New(false, worktreePath, testTimeout).(*GitCli)
+	linkedGit := New(clog.Default(), nil, false, worktreePath, testTimeout, testTimeout, 0).(*GitCli)
 
-	mainPath, err := linkedGit.GetMainWorktreePath()
+	mainPath, err := linkedGit.GetMainWorktreePath(t.Context())
 
 	require.NoError(t, err)
 	// Compare resolved paths to handle symlinks (e.g., /var -> /private/var on macOS)
 	assert.Equal(t, repo.path(), resolvePath(t, mainPath))
 }
 
+func TestGetMainWorktreePath_Integration_SymlinkedWorkingDir(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	link := filepath.Join(t.TempDir(), "link")
+	symlinkOrSkip(t, repo.rootDir, link)
+	linkedGit := New(clog.Default(), nil, false, link, testTimeout, testTimeout, 0)
+
+	mainPath, err := linkedGit.GetMainWorktreePath(t.Context())
+	require.NoError(t, err)
+	worktrees, err := linkedGit.ListWorktrees(t.Context())
+	require.NoError(t, err)
+
+	// No resolvePath here: the main worktree path must match the path git reports without help
+	require.Len(t, worktrees, 1)
+	assert.Equal(t, worktrees[0].AbsolutePath, mainPath)
+	assert.Equal(t, repo.path(), mainPath)
+}
+
+// newBareWorkspace clones a repository bare into <workspace>/.bare and adds a linked worktree for main beside it,
+// returning the workspace and a GitCli in the worktree.
+func newBareWorkspace(t *testing.T) (string, *GitCli) {
+	t.Helper()
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	workspace := filepath.Join(resolvePath(t, t.TempDir()), "project")
+	runGit(t, repo.rootDir, "clone", "--bare", repo.rootDir, filepath.Join(workspace, ".bare"))
+	runGit(t, filepath.Join(workspace, ".bare"), "worktree", "add", filepath.Join(workspace, "main"), "main")
+	return workspace, New(clog.Default(), nil, false, filepath.Join(workspace, "main"), testTimeout, testTimeout, 0).(*GitCli)
+}
+
+func TestGetMainWorktreePath_Integration_BareRepository(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	workspace, linkedGit := newBareWorkspace(t)
+
+	mainPath, err := linkedGit.GetMainWorktreePath(t.Context())
+	require.NoError(t, err)
+	workspacePath, err := linkedGit.GetWorkspacePath(t.Context())
+	require.NoError(t, err)
+	worktrees, err := linkedGit.ListWorktrees(t.Context())
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(workspace, ".bare"), mainPath)
+	assert.Equal(t, workspace, workspacePath)
+	require.Len(t, worktrees, 2)
+	assert.Equal(t, mainPath, worktrees[0].AbsolutePath, "the bare repository is listed in the main worktree's place")
+	assert.True(t, worktrees[0].IsBare)
+	assert.True(t, worktrees[0].IsMain)
+	assert.Equal(t, filepath.Join(workspace, "main"), worktrees[1].AbsolutePath)
+}
+
+func TestGetMainWorktreePath_Integration_BareRepositoryWithWorktreeConfig(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	workspace, linkedGit := newBareWorkspace(t)
+	// grove doctor --fix moves core.bare out of the shared config when turning on extensions.worktreeConfig
+	bareDir := filepath.Join(workspace, ".bare")
+	runGit(t, bareDir, "config", "extensions.worktreeConfig", "true")
+	runGit(t, bareDir, "config", "--unset", "core.bare")
+	runGit(t, bareDir, "config", "--worktree", "core.bare", "true")
+
+	mainPath, err := linkedGit.GetMainWorktreePath(t.Context())
+
+	require.NoError(t, err)
+	assert.Equal(t, bareDir, mainPath)
+}
+
+func TestPaths_Integration_PlatformForm(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	repo.createBranch("feature")
+	worktreePath := filepath.Join(resolvePath(t, t.TempDir()), "feature")
+	repo.createWorktree(worktreePath, "feature")
+	linkedGit := New(clog.Default(), nil, false, worktreePath, testTimeout, testTimeout, 0)
+
+	// every path git prints must be in the form filepath builds, e.g. with backslashes on Windows
+	root, err := linkedGit.GetWorktreeRoot(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, worktreePath, root)
+	gitDir, err := linkedGit.GetWorktreeGitDir(t.Context(), worktreePath)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(repo.path(), ".git", "worktrees", "feature"), gitDir)
+	worktrees, err := linkedGit.ListWorktrees(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []string{repo.path(), worktreePath}, worktreePaths(worktrees))
+	branches, err := linkedGit.ListLocalBranches(t.Context())
+	require.NoError(t, err)
+	for _, b := range branches {
+		if b.Name == "feature" {
+			assert.Equal(t, worktreePath, b.WorktreeAbsolutePath)
+		}
+	}
+}
+
+// =============================================================================
+// GetCommonDir tests
+// =============================================================================
+
+func TestGetCommonDir_Integration_FromLinkedWorktree(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	repo.createBranch("feature")
+
+	worktreePath := filepath.Join(t.TempDir(), "feature-worktree")
+	repo.createWorktree(worktreePath, "feature")
+	linkedGit := New(clog.Default(), nil, false, worktreePath, testTimeout, testTimeout, 0).(*GitCli)
+
+	commonDir, err := linkedGit.GetCommonDir(t.Context())
+
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(repo.path(), ".git"), resolvePath(t, commonDir))
+}
+
 // =============================================================================
 // GetWorkspacePath tests
 // =============================================================================
@@ -202,7 +361,7 @@ func TestGetWorkspacePath_Integration(t *testing.T) {
 	repo := newTestRepo(t)
 	repo.commit("initial commit")
 
-	workspacePath, err := repo.Git.GetWorkspacePath()
+	workspacePath, err := repo.Git.GetWorkspacePath(t.Context())
 
 	require.NoError(t, err)
 	// Workspace path is the parent of the main worktree
@@ -224,9 +383,9 @@ func TestGetWorkspacePath_Integration_FromLinkedWorktree(t *testing.T) {
 	repo.createWorktree(worktreePath, "feature")
 
 	// Create GitCli pointing to the linked worktree
-	linkedGit := New(false, worktreePath, testTimeout).(*GitCli)
+	linkedGit := New(clog.Default(), nil, false, worktreePath, testTimeout, testTimeout, 0).(*GitCli)
 
-	workspacePath, err := linkedGit.GetWorkspacePath()
+	workspacePath, err := linkedGit.GetWorkspacePath(t.Context())
 
 	require.NoError(t, err)
 	// Workspace path should still be the parent of the main worktree
@@ -246,8 +405,8 @@ func TestGetWorkspacePath_Integration_FromSubdirectory(t *testing.T) {
 	subdir := filepath.Join(repo.path(), "subdir", "nested")
 	require.NoError(t, os.MkdirAll(subdir, 0755))
 
-	subdirGit := New(false, subdir, testTimeout).(*GitCli)
-	workspacePath, err := subdirGit.GetWorkspacePath()
+	subdirGit := New(clog.Default(), nil, false, subdir, testTimeout, testTimeout, 0).(*GitCli)
+	workspacePath, err := subdirGit.GetWorkspacePath(t.Context())
 
 	require.NoError(t, err)
 	// Workspace path should be the parent of the main worktree
@@ -269,13 +428,36 @@ func TestListLocalBranches_Integration(t *testing.T) {
 	repo.createBranch("feature-a")
 	repo.createBranch("feature-b")
 
-	branches, err := repo.Git.ListLocalBranches()
+	branches, err := repo.Git.ListLocalBranches(t.Context())
 
 	require.NoError(t, err)
 	assert.Len(t, branches, 3)
 	assert.ElementsMatch(t, []string{"main", "feature-a", "feature-b"}, branchNames(branches))
 }
 
+func TestListWorktrees_Integration_FullSHAs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	sha := strings.TrimSpace(runGit(t, repo.rootDir, "rev-parse", "HEAD"))
+	runGit(t, repo.rootDir, "tag", "v1.0.0")
+	repo.createBranch("feature")
+	repo.createWorktree(filepath.Join(t.TempDir(), "feature"), "feature")
+	runGit(t, repo.rootDir, "worktree", "add", "--detach", filepath.Join(t.TempDir(), "detached"), "main")
+	runGit(t, repo.rootDir, "worktree", "add", "--detach", filepath.Join(t.TempDir(), "tag"), "v1.0.0")
+
+	worktrees, err := repo.Git.ListWorktrees(t.Context())
+
+	require.NoError(t, err)
+	require.Len(t, worktrees, 4)
+	for _, wt := range worktrees {
+		assert.Equal(t, sha, wt.Ref.Commit().SHA, "every ref type reports the full SHA: %s", wt.AbsolutePath)
+	}
+}
+
 func TestListLocalBranches_Integration_EmptyRepo(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
@@ -287,8 +469,8 @@ func TestListLocalBranches_Integration_EmptyRepo(t *testing.T) {
 	runGit(t, dir, "config", "user.email", "test@example.com")
 	runGit(t, dir, "config", "user.name", "Test User")
 
-	git := New(false, dir, testTimeout).(*GitCli)
-	branches, err := git.ListLocalBranches()
+	git := New(clog.Default(), nil, false, dir, testTimeout, testTimeout, 0).(*GitCli)
+	branches, err := git.ListLocalBranches(t.Context())
 
 	require.NoError(t, err)
 	assert.Empty(t, branches)
@@ -306,7 +488,7 @@ func TestListLocalBranches_Integration_WithTracking(t *testing.T) {
 	// Make a local commit to be ahead
 	repo.commit("local commit")
 
-	branches, err := repo.Git.ListLocalBranches()
+	branches, err := repo.Git.ListLocalBranches(t.Context())
 
 	require.NoError(t, err)
 	require.Len(t, branches, 1)
@@ -318,6 +500,35 @@ func TestListLocalBranches_Integration_WithTracking(t *testing.T) {
 	assert.Equal(t, 0, main.Behind)
 }
 
+func TestListWorktrees_Integration_UpstreamGone(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	remoteDir := repo.addRemote("origin")
+	repo.createBranch("feature")
+	runGit(t, repo.rootDir, "push", "-u", "origin", "feature")
+	worktreePath := filepath.Join(t.TempDir(), "feature")
+	repo.createWorktree(worktreePath, "feature")
+	// the branch is deleted on the remote, e.g. after its pull request was merged
+	runGit(t, remoteDir, "branch", "-D", "feature")
+	runGit(t, repo.rootDir, "fetch", "--prune", "origin")
+
+	worktrees, err := repo.Git.ListWorktrees(t.Context())
+
+	require.NoError(t, err)
+	require.Len(t, worktrees, 2)
+	main, ok := worktrees[0].Ref.FullBranch()
+	require.True(t, ok)
+	assert.False(t, main.Gone)
+	feature, ok := worktrees[1].Ref.FullBranch()
+	require.True(t, ok)
+	assert.Equal(t, "origin/feature", feature.UpstreamName)
+	assert.True(t, feature.Gone)
+}
+
 func TestListLocalBranches_Integration_CheckedOutBranch(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
@@ -327,7 +538,7 @@ func TestListLocalBranches_Integration_CheckedOutBranch(t *testing.T) {
 	repo.commit("initial commit")
 	repo.createBranch("feature")
 
-	branches, err := repo.Git.ListLocalBranches()
+	branches, err := repo.Git.ListLocalBranches(t.Context())
 
 	require.NoError(t, err)
 	require.Len(t, branches, 2)
@@ -356,13 +567,38 @@ func TestListLocalBranches_Integration_BranchWithSlash(t *testing.T) {
 	repo.createBranch("feature/my-feature")
 	repo.createBranch("bugfix/issue-123")
 
-	branches, err := repo.Git.ListLocalBranches()
+	branches, err := repo.Git.ListLocalBranches(t.Context())
 
 	require.NoError(t, err)
 	assert.Len(t, branches, 3)
 	assert.ElementsMatch(t, []string{"main", "feature/my-feature", "bugfix/issue-123"}, branchNames(branches))
 }
 
+func TestListLocalBranches_Integration_SubjectWithMarkers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	repo.createBranch("feature")
+	repo.checkout("feature")
+	// a subject that starts like a field, over several lines of the message's first paragraph
+	repo.commit("branch evil\nworktreepath /tmp\ncommittedBy Mallory")
+
+	branches, err := repo.Git.ListLocalBranches(t.Context())
+
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"main", "feature"}, branchNames(branches))
+	for _, b := range branches {
+		if b.Name == "feature" {
+			assert.True(t, strings.HasPrefix(b.Commit().Subject, "branch evil"), "subject %q", b.Commit().Subject)
+			assert.Equal(t, "Test User", b.Commit().CommittedBy)
+			assert.Equal(t, repo.path(), b.WorktreeAbsolutePath)
+		}
+	}
+}
+
 // =============================================================================
 // ListRemotes tests
 // =============================================================================
@@ -376,7 +612,7 @@ func TestListRemotes_Integration(t *testing.T) {
 	repo.commit("initial commit")
 	repo.addRemote("origin")
 
-	remotes, err := repo.Git.ListRemotes()
+	remotes, err := repo.Git.ListRemotes(t.Context())
 
 	require.NoError(t, err)
 	assert.Equal(t, []string{"origin"}, remotes)
@@ -390,7 +626,7 @@ func TestListRemotes_Integration_NoRemotes(t *testing.T) {
 	repo := newTestRepo(t)
 	repo.commit("initial commit")
 
-	remotes, err := repo.Git.ListRemotes()
+	remotes, err := repo.Git.ListRemotes(t.Context())
 
 	require.NoError(t, err)
 	assert.Empty(t, remotes)
@@ -410,13 +646,30 @@ func TestListRemotes_Integration_MultipleRemotes(t *testing.T) {
 	runGit(t, repo.path(), "clone", "--bare", repo.path(), upstreamDir)
 	runGit(t, repo.path(), "remote", "add", "upstream", upstreamDir)
 
-	remotes, err := repo.Git.ListRemotes()
+	remotes, err := repo.Git.ListRemotes(t.Context())
 
 	require.NoError(t, err)
 	assert.Len(t, remotes, 2)
 	assert.ElementsMatch(t, []string{"origin", "upstream"}, remotes)
 }
 
+func TestGetRemoteURL_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	remoteDir := repo.addRemote("origin")
+
+	url, err := repo.Git.GetRemoteURL(t.Context(), "origin")
+	require.NoError(t, err)
+	assert.Equal(t, remoteDir, url)
+
+	_, err = repo.Git.GetRemoteURL(t.Context(), "missing")
+	assert.Error(t, err)
+}
+
 // =============================================================================
 // ListRemoteBranches tests
 // =============================================================================
@@ -430,7 +683,7 @@ func TestListRemoteBranches_Integration(t *testing.T) {
 	repo.commit("initial commit")
 	repo.addRemote("origin")
 
-	branches, err := repo.Git.ListRemoteBranches("origin")
+	branches, err := repo.Git.ListRemoteBranches(t.Context(), "origin")
 
 	require.NoError(t, err)
 	// Should have origin/main (origin/HEAD is filtered out)
@@ -452,7 +705,7 @@ func TestListTags_Integration(t *testing.T) {
 	repo.createAnnotatedTag("v1.0.0", "Release 1.0.0")
 	repo.createLightweightTag("v0.1.0")
 
-	tags, err := repo.Git.ListTags()
+	tags, err := repo.Git.ListTags(t.Context())
 
 	require.NoError(t, err)
 	assert.Len(t, tags, 2)
@@ -467,7 +720,7 @@ func TestListTags_Integration_NoTags(t *testing.T) {
 	repo := newTestRepo(t)
 	repo.commit("initial commit")
 
-	tags, err := repo.Git.ListTags()
+	tags, err := repo.Git.ListTags(t.Context())
 
 	require.NoError(t, err)
 	assert.Empty(t, tags)
@@ -483,7 +736,7 @@ func TestListTags_Integration_AnnotatedVsLightweight(t *testing.T) {
 	repo.createAnnotatedTag("v1.0.0", "Annotated release")
 	repo.createLightweightTag("v0.1.0")
 
-	tags, err := repo.Git.ListTags()
+	tags, err := repo.Git.ListTags(t.Context())
 
 	require.NoError(t, err)
 	require.Len(t, tags, 2)
@@ -520,15 +773,156 @@ func TestBranchExists_Integration(t *testing.T) {
 	repo.commit("initial commit")
 	repo.createBranch("feature")
 
-	exists, err := repo.Git.BranchExists("feature", false)
+	exists, err := repo.Git.BranchExists(t.Context(), "feature", false)
 	require.NoError(t, err)
 	assert.True(t, exists)
 
-	exists, err = repo.Git.BranchExists("nonexistent", false)
+	exists, err = repo.Git.BranchExists(t.Context(), "nonexistent", false)
 	require.NoError(t, err)
 	assert.False(t, exists)
 }
 
+func TestProbeGitCommand_Integration_ExpectedExitsDoNotWarn(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	tests := []struct {
+		name     string
+		probe    func(g *GitCli) error
+		wantWarn bool
+	}{
+		{
+			name: "missing branch",
+			probe: func(g *GitCli) error {
+				_, err := g.BranchExists(t.Context(), "no-such-branch", false)
+				return err
+			},
+		},
+		{
+			name: "missing branch description",
+			probe: func(g *GitCli) error {
+				_, err := g.GetBranchDescription(t.Context(), "main")
+				return err
+			},
+		},
+		{
+			name: "missing worktree config",
+			probe: func(g *GitCli) error {
+				_, err := g.GetWorktreeConfigState(t.Context())
+				return err
+			},
+		},
+		{
+			name: "missing pager config",
+			probe: func(g *GitCli) error {
+				t.Setenv("GIT_PAGER", "") // restores the original value after the test
+				require.NoError(t, os.Unsetenv("GIT_PAGER"))
+				_, err := g.GetPager(t.Context())
+				return err
+			},
+		},
+		{
+			name: "missing push default",
+			probe: func(g *GitCli) error {
+				remote, err := g.GetDefaultRemote(t.Context(), "origin")
+				if remote != "origin" {
+					return errors.New("expected the fallback remote")
+				}
+				return err
+			},
+		},
+		{
+			name: "missing remote",
+			probe: func(g *GitCli) error {
+				_, err := g.GetRepoDefaultBranch(t.Context(), "no-such-remote")
+				if err == nil {
+					return errors.New("expected a missing remote to fail")
+				}
+				return nil
+			},
+		},
+		{
+			name: "missing remote HEAD",
+			probe: func(g *GitCli) error {
+				runGit(t, g.workingDir, "remote", "add", "origin", t.TempDir())
+				branch, err := g.GetRepoDefaultBranch(t.Context(), "origin")
+				if branch != "" {
+					return errors.New("expected no default branch without a remote HEAD")
+				}
+				return err
+			},
+		},
+		{
+			name: "missing ref to delete",
+			probe: func(g *GitCli) error {
+				if err := g.DeleteRef(t.Context(), "refs/heads/no-such-branch"); err == nil {
+					return errors.New("expected deleting a missing ref to fail")
+				}
+				return nil
+			},
+		},
+		{
+			name: "outside a repository",
+			probe: func(g *GitCli) error {
+				g.workingDir = t.TempDir()
+				root, err := g.GetWorktreeRoot(t.Context())
+				if root != "" {
+					return errors.New("expected no worktree root outside a repository")
+				}
+				return err
+			},
+		},
+		{
+			name: "failure that was not expected",
+			probe: func(g *GitCli) error {
+				_, err := g.executeGitCommand(t.Context(), "show-ref", "--verify", "--quiet", "refs/heads/no-such-branch")
+				if err == nil {
+					return errors.New("expected show-ref to fail")
+				}
+				return nil
+			},
+			wantWarn: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepo(t)
+			repo.commit("initial commit")
+			var logs bytes.Buffer
+			g := New(clog.NewWithOptions(&logs, clog.Options{Level: clog.WarnLevel}), nil, false, repo.path(), testTimeout, testTimeout, 0).(*GitCli)
+
+			require.NoError(t, tt.probe(g))
+
+			if tt.wantWarn {
+				assert.Contains(t, logs.String(), "Git command failed")
+			} else {
+				assert.Empty(t, logs.String())
+			}
+		})
+	}
+}
+
+func TestBranchExists_Integration_OnlyBranches(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	repo.createLightweightTag("v1")
+	repo.createBranch("feature/auth")
+
+	for _, name := range []string{"v1", "feature", "auth", "main~1"} {
+		for _, caseInsensitive := range []bool{false, true} {
+			exists, err := repo.Git.BranchExists(t.Context(), name, caseInsensitive)
+			require.NoError(t, err)
+			assert.False(t, exists, "%s (case-insensitive: %v)", name, caseInsensitive)
+		}
+	}
+}
+
 func TestBranchExists_Integration_CaseInsensitive(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
@@ -539,16 +933,16 @@ func TestBranchExists_Integration_CaseInsensitive(t *testing.T) {
 	repo.createBranch("Feature")
 
 	// Case sensitive - should not match
-	exists, err := repo.Git.BranchExists("feature", false)
+	exists, err := repo.Git.BranchExists(t.Context(), "feature", false)
 	require.NoError(t, err)
 	assert.False(t, exists)
 
 	// Case insensitive - should match
-	exists, err = repo.Git.BranchExists("feature", true)
+	exists, err = repo.Git.BranchExists(t.Context(), "feature", true)
 	require.NoError(t, err)
 	assert.True(t, exists)
 
-	exists, err = repo.Git.BranchExists("FEATURE", true)
+	exists, err = repo.Git.BranchExists(t.Context(), "FEATURE", true)
 	require.NoError(t, err)
 	assert.True(t, exists)
 }
@@ -565,7 +959,7 @@ func TestListWorktrees_Integration(t *testing.T) {
 	repo := newTestRepo(t)
 	repo.commit("initial commit")
 
-	worktrees, err := repo.Git.ListWorktrees()
+	worktrees, err := repo.Git.ListWorktrees(t.Context())
 
 	require.NoError(t, err)
 	assert.Len(t, worktrees, 1)
@@ -587,7 +981,7 @@ func TestListWorktrees_Integration_WithLinkedWorktrees(t *testing.T) {
 	repo.createWorktree(worktreeA, "feature-a")
 	repo.createWorktree(worktreeB, "feature-b")
 
-	worktrees, err := repo.Git.ListWorktrees()
+	worktrees, err := repo.Git.ListWorktrees(t.Context())
 
 	require.NoError(t, err)
 	assert.Len(t, worktrees, 3)
@@ -596,6 +990,35 @@ func TestListWorktrees_Integration_WithLinkedWorktrees(t *testing.T) {
 	assert.Contains(t, paths, repo.path())
 	assert.Contains(t, paths, resolvePath(t, worktreeA))
 	assert.Contains(t, paths, resolvePath(t, worktreeB))
+
+	for _, wt := range worktrees {
+		assert.Equal(t, wt.AbsolutePath == repo.path(), wt.IsMain, wt.AbsolutePath)
+		assert.False(t, wt.IsBare)
+	}
+}
+
+func TestListWorktrees_Integration_Bare(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	repo.createBranch("feature")
+	bareDir := filepath.Join(t.TempDir(), "repo.git")
+	runGit(t, repo.path(), "clone", "--bare", repo.path(), bareDir)
+	worktreePath := filepath.Join(t.TempDir(), "feature")
+	runGit(t, bareDir, "worktree", "add", worktreePath, "feature")
+
+	worktrees, err := New(clog.Default(), nil, false, bareDir, testTimeout, testTimeout, 0).ListWorktrees(t.Context())
+
+	require.NoError(t, err)
+	require.Len(t, worktrees, 2)
+	assert.True(t, worktrees[0].IsBare)
+	assert.True(t, worktrees[0].IsMain)
+	assert.Nil(t, worktrees[0].Ref)
+	assert.False(t, worktrees[1].IsBare)
+	assert.False(t, worktrees[1].IsMain)
 }
 
 func TestListWorktrees_Integration_DetachedHEAD(t *testing.T) {
@@ -611,7 +1034,7 @@ func TestListWorktrees_Integration_DetachedHEAD(t *testing.T) {
 	worktreePath := filepath.Join(t.TempDir(), "detached-worktree")
 	runGit(t, repo.path(), "worktree", "add", "--detach", worktreePath, sha)
 
-	worktrees, err := repo.Git.ListWorktrees()
+	worktrees, err := repo.Git.ListWorktrees(t.Context())
 
 	require.NoError(t, err)
 	assert.Len(t, worktrees, 2)
@@ -632,6 +1055,41 @@ func TestListWorktrees_Integration_DetachedHEAD(t *testing.T) {
 	assert.Equal(t, WorktreeRefTypeCommit, detachedWorktree.Ref.Type())
 }
 
+func TestListWorktrees_Integration_ManyDetachedHEADs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	first := repo.commit("first commit")
+	second := repo.commit("second commit")
+
+	dir := t.TempDir()
+	subjects := map[string]string{}
+	for name, sha := range map[string]string{"a": first, "b": second, "c": first} {
+		path := filepath.Join(dir, name)
+		runGit(t, repo.path(), "worktree", "add", "--detach", path, sha)
+		subjects[resolvePath(t, path)] = map[string]string{first: "first commit", second: "second commit"}[sha]
+	}
+
+	worktrees, err := repo.Git.ListWorktrees(t.Context())
+
+	require.NoError(t, err)
+	assert.Len(t, worktrees, 4)
+	for _, wt := range worktrees {
+		want, ok := subjects[wt.AbsolutePath]
+		if !ok {
+			continue
+		}
+		require.Equal(t, WorktreeRefTypeCommit, wt.Ref.Type())
+		assert.Equal(t, want, wt.Ref.Commit().Subject, wt.AbsolutePath)
+		assert.Equal(t, "Test User", wt.Ref.Commit().CommittedBy)
+		assert.False(t, wt.Ref.Commit().CommittedOn.IsZero())
+		delete(subjects, wt.AbsolutePath)
+	}
+	assert.Empty(t, subjects, "every detached worktree is listed")
+}
+
 func TestListWorktrees_Integration_DetachedHEAD_WithAnnotatedTag(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
@@ -644,7 +1102,7 @@ func TestListWorktrees_Integration_DetachedHEAD_WithAnnotatedTag(t *testing.T) {
 	worktreePath := filepath.Join(t.TempDir(), "tag-worktree")
 	runGit(t, repo.path(), "worktree", "add", "--detach", worktreePath, "v1.0.0")
 
-	worktrees, err := repo.Git.ListWorktrees()
+	worktrees, err := repo.Git.ListWorktrees(t.Context())
 
 	require.NoError(t, err)
 	assert.Len(t, worktrees, 2)
@@ -679,7 +1137,7 @@ func TestListWorktrees_Integration_DetachedHEAD_WithLightweightTag(t *testing.T)
 	worktreePath := filepath.Join(t.TempDir(), "lightweight-tag-worktree")
 	runGit(t, repo.path(), "worktree", "add", "--detach", worktreePath, "v0.1.0")
 
-	worktrees, err := repo.Git.ListWorktrees()
+	worktrees, err := repo.Git.ListWorktrees(t.Context())
 
 	require.NoError(t, err)
 	assert.Len(t, worktrees, 2)
@@ -716,7 +1174,7 @@ func TestGetDefaultRemote_Integration_WithPushDefault(t *testing.T) {
 	repo.addRemote("origin")
 	repo.setConfig("remote.pushDefault", "origin")
 
-	remote, err := repo.Git.GetDefaultRemote("fallback")
+	remote, err := repo.Git.GetDefaultRemote(t.Context(), "fallback")
 
 	require.NoError(t, err)
 	assert.Equal(t, "origin", remote)
@@ -730,7 +1188,7 @@ func TestGetDefaultRemote_Integration_Fallback(t *testing.T) {
 	repo := newTestRepo(t)
 	repo.commit("initial commit")
 
-	remote, err := repo.Git.GetDefaultRemote("my-fallback")
+	remote, err := repo.Git.GetDefaultRemote(t.Context(), "my-fallback")
 
 	require.NoError(t, err)
 	assert.Equal(t, "my-fallback", remote)
@@ -752,7 +1210,7 @@ func TestGetRepoDefaultBranch_Integration(t *testing.T) {
 	// Set the remote HEAD
 	runGit(t, repo.path(), "remote", "set-head", "origin", "main")
 
-	branch, err := repo.Git.GetRepoDefaultBranch("origin")
+	branch, err := repo.Git.GetRepoDefaultBranch(t.Context(), "origin")
 
 	require.NoError(t, err)
 	assert.Equal(t, "main", branch)
@@ -771,7 +1229,7 @@ func TestGetRepoDefaultBranch_Integration_RemoteExistsWithoutHead(t *testing.T)
 	// (remote HEAD is only set after fetch or explicit set-head)
 	runGit(t, repo.path(), "remote", "set-head", "origin", "-d")
 
-	branch, err := repo.Git.GetRepoDefaultBranch("origin")
+	branch, err := repo.Git.GetRepoDefaultBranch(t.Context(), "origin")
 
 	require.NoError(t, err)
 	assert.Empty(t, branch)
@@ -786,13 +1244,112 @@ func TestGetRepoDefaultBranch_Integration_RemoteDoesNotExist(t *testing.T) {
 	repo.commit("initial commit")
 
 	// No remote configured - should return an error
-	branch, err := repo.Git.GetRepoDefaultBranch("nonexistent")
+	branch, err := repo.Git.GetRepoDefaultBranch(t.Context(), "nonexistent")
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "does not exist")
 	assert.Empty(t, branch)
 }
 
+// =============================================================================
+// ResolveRepoDefaultBranch tests
+// =============================================================================
+
+func TestResolveRepoDefaultBranch_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	tests := []struct {
+		name       string
+		dryRun     bool
+		want       string
+		wantStored string
+	}{
+		{name: "detects remote HEAD", want: "main", wantStored: "main"},
+		{name: "dry run does not detect", dryRun: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepo(t)
+			repo.commit("initial commit")
+			repo.addRemote("origin")
+			runGit(t, repo.path(), "remote", "set-head", "origin", "-d")
+
+			g := New(clog.Default(), nil, tt.dryRun, repo.path(), testTimeout, testTimeout, 0)
+			branch, err := g.ResolveRepoDefaultBranch(t.Context(), "origin")
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, branch)
+			stored, err := repo.Git.GetRepoDefaultBranch(t.Context(), "origin")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStored, stored)
+		})
+	}
+}
+
+func TestResolveRepoDefaultBranch_Integration_Cached(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	repo.addRemote("origin")
+	runGit(t, repo.path(), "remote", "set-head", "origin", "-d")
+
+	first, err := repo.Git.ResolveRepoDefaultBranch(t.Context(), "origin")
+	require.NoError(t, err)
+	runGit(t, repo.path(), "remote", "set-head", "origin", "-d")
+	second, err := repo.Git.ResolveRepoDefaultBranch(t.Context(), "origin")
+	require.NoError(t, err)
+
+	assert.Equal(t, "main", first)
+	assert.Equal(t, first, second, "second call should use the cached result")
+}
+
+// =============================================================================
+// Renamed default branch tests
+// =============================================================================
+
+func TestRenamedDefaultBranch_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	remoteDir := repo.addRemote("origin")
+	runGit(t, repo.path(), "remote", "set-head", "origin", "main")
+	runGit(t, remoteDir, "branch", "-m", "main", "trunk")
+
+	stored, err := repo.Git.GetRepoDefaultBranch(t.Context(), "origin")
+	require.NoError(t, err)
+	assert.Equal(t, "main", stored, "the local remote HEAD is not updated until set")
+
+	current, err := repo.Git.GetRemoteHEAD(t.Context(), "origin")
+	require.NoError(t, err)
+	assert.Equal(t, "trunk", current)
+
+	_, err = repo.Git.FetchRemote(t.Context(), "origin", nil)
+	require.NoError(t, err)
+	require.NoError(t, repo.Git.SetRepoDefaultBranch(t.Context(), "origin", "trunk"))
+	require.NoError(t, repo.Git.SetBranchUpstream(t.Context(), "main", "origin/trunk"))
+
+	stored, err = repo.Git.GetRepoDefaultBranch(t.Context(), "origin")
+	require.NoError(t, err)
+	assert.Equal(t, "trunk", stored)
+	branches, err := repo.Git.ListLocalBranches(t.Context())
+	require.NoError(t, err)
+	require.Len(t, branches, 1)
+	assert.Equal(t, "origin/trunk", branches[0].UpstreamName)
+
+	err = repo.Git.SetBranchUpstream(t.Context(), "main", "origin/missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to set branch upstream")
+}
+
 // =============================================================================
 // CreateWorktreeForNewBranch tests
 // =============================================================================
@@ -806,7 +1363,7 @@ func TestCreateWorktreeForNewBranch_Integration(t *testing.T) {
 	repo.commit("initial commit")
 
 	worktreePath := filepath.Join(t.TempDir(), "new-feature")
-	err := repo.Git.CreateWorktreeForNewBranch("new-feature", worktreePath)
+	err := repo.Git.CreateWorktreeForNewBranch(t.Context(), "new-feature", worktreePath)
 
 	require.NoError(t, err)
 
@@ -815,7 +1372,7 @@ func TestCreateWorktreeForNewBranch_Integration(t *testing.T) {
 	require.NoError(t, err)
 
 	// Verify branch exists
-	exists, err := repo.Git.BranchExists("new-feature", false)
+	exists, err := repo.Git.BranchExists(t.Context(), "new-feature", false)
 	require.NoError(t, err)
 	assert.True(t, exists)
 }
@@ -829,7 +1386,7 @@ func TestCreateWorktreeForNewBranch_Integration_DryRun(t *testing.T) {
 	repo.commit("initial commit")
 
 	worktreePath := filepath.Join(t.TempDir(), "dry-run-feature")
-	err := repo.Git.CreateWorktreeForNewBranch("dry-run-feature", worktreePath)
+	err := repo.Git.CreateWorktreeForNewBranch(t.Context(), "dry-run-feature", worktreePath)
 
 	require.NoError(t, err)
 
@@ -839,8 +1396,8 @@ func TestCreateWorktreeForNewBranch_Integration_DryRun(t *testing.T) {
 
 	// Branch should NOT exist
 	// Need a non-dry-run git to check
-	realGit := New(false, repo.path(), testTimeout).(*GitCli)
-	exists, err := realGit.BranchExists("dry-run-feature", false)
+	realGit := New(clog.Default(), nil, false, repo.path(), testTimeout, testTimeout, 0).(*GitCli)
+	exists, err := realGit.BranchExists(t.Context(), "dry-run-feature", false)
 	require.NoError(t, err)
 	assert.False(t, exists)
 }
@@ -859,7 +1416,7 @@ func TestCreateWorktreeForNewBranchFromRef_Integration(t *testing.T) {
 	repo.commit("second commit")
 
 	worktreePath := filepath.Join(t.TempDir(), "from-ref-feature")
-	err := repo.Git.CreateWorktreeForNewBranchFromRef("from-ref-feature", worktreePath, firstSHA)
+	err := repo.Git.CreateWorktreeForNewBranchFromRef(t.Context(), "from-ref-feature", worktreePath, firstSHA)
 
 	require.NoError(t, err)
 
@@ -868,7 +1425,7 @@ func TestCreateWorktreeForNewBranchFromRef_Integration(t *testing.T) {
 	require.NoError(t, err)
 
 	// Verify the branch is at the first commit
-	worktreeGit := New(false, worktreePath, testTimeout).(*GitCli)
+	worktreeGit := New(clog.Default(), nil, false, worktreePath, testTimeout, testTimeout, 0).(*GitCli)
 	currentSHA := strings.TrimSpace(runGit(t, worktreePath, "rev-parse", "--short", "HEAD"))
 	assert.Equal(t, firstSHA, currentSHA)
 
@@ -885,7 +1442,7 @@ func TestCreateWorktreeForNewBranchFromRef_Integration_EmptyRef(t *testing.T) {
 	headSHA := repo.commit("second commit")
 
 	worktreePath := filepath.Join(t.TempDir(), "empty-ref-feature")
-	err := repo.Git.CreateWorktreeForNewBranchFromRef("empty-ref-feature", worktreePath, "")
+	err := repo.Git.CreateWorktreeForNewBranchFromRef(t.Context(), "empty-ref-feature", worktreePath, "")
 
 	require.NoError(t, err)
 
@@ -908,7 +1465,7 @@ func TestCreateWorktreeForExistingBranch_Integration(t *testing.T) {
 	repo.createBranch("existing-branch")
 
 	worktreePath := filepath.Join(t.TempDir(), "existing-branch-worktree")
-	err := repo.Git.CreateWorktreeForExistingBranch("existing-branch", worktreePath)
+	err := repo.Git.CreateWorktreeForExistingBranch(t.Context(), "existing-branch", worktreePath)
 
 	require.NoError(t, err)
 
@@ -917,8 +1474,8 @@ func TestCreateWorktreeForExistingBranch_Integration(t *testing.T) {
 	require.NoError(t, err)
 
 	// Verify it's on the correct branch
-	worktreeGit := New(false, worktreePath, testTimeout).(*GitCli)
-	branch, err := worktreeGit.GetCurrentBranch()
+	worktreeGit := New(clog.Default(), nil, false, worktreePath, testTimeout, testTimeout, 0).(*GitCli)
+	branch, err := worktreeGit.GetCurrentBranch(t.Context())
 	require.NoError(t, err)
 	assert.Equal(t, "existing-branch", branch)
 }
@@ -939,21 +1496,17 @@ func TestFetchRemoteBranch_Integration(t *testing.T) {
 	// Create a new branch in the remote
 	runGit(t, remoteDir, "branch", "remote-feature")
 
-	err := repo.Git.FetchRemoteBranch("origin", "remote-feature", "refs/heads/fetched-feature")
+	err := repo.Git.FetchRemoteBranch(t.Context(), "origin", "remote-feature", "refs/heads/fetched-feature")
 
 	require.NoError(t, err)
 
 	// Verify the local branch exists
-	exists, err := repo.Git.BranchExists("fetched-feature", false)
+	exists, err := repo.Git.BranchExists(t.Context(), "fetched-feature", false)
 	require.NoError(t, err)
 	assert.True(t, exists)
 }
 
-// =============================================================================
-// FetchRemote tests
-// =============================================================================
-
-func TestFetchRemote_Integration(t *testing.T) {
+func TestFetchRemoteBranch_Integration_MissingRefNotRetried(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
 	}
@@ -961,29 +1514,311 @@ func TestFetchRemote_Integration(t *testing.T) {
 	repo := newTestRepo(t)
 	repo.commit("initial commit")
 	repo.addRemote("origin")
+	timings := timing.NewRecorder(time.Now())
+	g := New(clog.Default(), timings, false, repo.path(), testTimeout, testTimeout, 3)
 
-	output, err := repo.Git.FetchRemote("origin")
+	err := g.FetchRemoteBranch(t.Context(), "origin", "no-such-branch", "refs/remotes/origin/no-such-branch")
+	require.Error(t, err)
 
-	require.NoError(t, err)
-	// Output may be empty if nothing new to fetch, that's OK
-	_ = output
+	calls := timings.Calls()
+	require.Len(t, calls, 1, "a missing ref fails the same way every time")
+	assert.Equal(t, "git fetch", calls[0].Name())
 }
 
-func TestFetchRemote_Integration_DryRun(t *testing.T) {
+func TestFetchRemoteBranch_Integration_Rewritten(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
 	}
 
-	repo := newTestRepoWithDryRun(t)
+	repo := newTestRepo(t)
 	repo.commit("initial commit")
-	// Don't actually add remote - in dry run it won't matter
-
-	output, err := repo.Git.FetchRemote("origin")
-
+	remoteDir := repo.addRemote("origin")
+	runGit(t, remoteDir, "branch", "feature")
+	require.NoError(t, repo.Git.FetchRemoteBranch(t.Context(), "origin", "feature", "refs/remotes/origin/feature"))
+
+	// force-push a commit that does not descend from the fetched one
+	rewritten := strings.TrimSpace(runGit(t, remoteDir, "-c", "user.name=Test User", "-c", "user.email=test@example.com",
+		"commit-tree", "feature^{tree}", "-m", "rewritten"))
+	runGit(t, remoteDir, "update-ref", "refs/heads/feature", rewritten)
+
+	err := repo.Git.FetchRemoteBranch(t.Context(), "origin", "feature", "refs/remotes/origin/feature")
+	require.Error(t, err, "a rewritten branch is not a fast-forward")
+	require.NoError(t, repo.Git.FetchRemoteBranch(t.Context(), "origin", "+feature", "refs/remotes/origin/feature"))
+	sha, err := repo.Git.ResolveRef(t.Context(), "origin/feature")
+	require.NoError(t, err)
+	assert.Equal(t, rewritten, sha)
+}
+
+// =============================================================================
+// FetchRef, FastForward, and Rebase tests
+// =============================================================================
+
+func TestFetchRef_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	remoteDir := repo.addRemote("origin")
+	remoteSHA := strings.TrimSpace(runGit(t, remoteDir, "rev-parse", "main"))
+
+	sha, err := repo.Git.FetchRef(t.Context(), "origin", "main")
+
+	require.NoError(t, err)
+	assert.Equal(t, remoteSHA, sha)
+}
+
+func TestFetchRef_Integration_DryRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepoWithDryRun(t)
+	repo.commit("initial commit")
+	repo.addRemote("origin")
+
+	sha, err := repo.Git.FetchRef(t.Context(), "origin", "main")
+
+	require.NoError(t, err)
+	assert.Empty(t, sha)
+}
+
+// newSyncTestRepo returns a repo whose "feature" branch is checked out in a linked worktree one commit
+// behind main, and the worktree path.
+func newSyncTestRepo(t *testing.T) (*testRepo, string) {
+	t.Helper()
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	repo.createBranch("feature")
+	repo.commit("second commit")
+	worktreePath := filepath.Join(t.TempDir(), "feature")
+	repo.createWorktree(worktreePath, "feature")
+	return repo, worktreePath
+}
+
+func TestFastForward_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo, worktreePath := newSyncTestRepo(t)
+
+	err := repo.Git.FastForward(t.Context(), worktreePath, "main")
+
+	require.NoError(t, err)
+	assert.Equal(t, repo.shortSHA("main"), repo.shortSHA("feature"))
+}
+
+func TestFastForward_Integration_Diverged(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo, worktreePath := newSyncTestRepo(t)
+	appendToFile(t, filepath.Join(worktreePath, "local.txt"), "local\n")
+	runGit(t, worktreePath, "add", "-A")
+	runGit(t, worktreePath, "commit", "-m", "local commit")
+	before := repo.shortSHA("feature")
+
+	err := repo.Git.FastForward(t.Context(), worktreePath, "main")
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrNotFastForward)
+	assert.Equal(t, before, repo.shortSHA("feature"))
+}
+
+func TestRebase_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo, worktreePath := newSyncTestRepo(t)
+	appendToFile(t, filepath.Join(worktreePath, "local.txt"), "local\n")
+	runGit(t, worktreePath, "add", "-A")
+	runGit(t, worktreePath, "commit", "-m", "local commit")
+
+	err := repo.Git.Rebase(t.Context(), worktreePath, "main")
+
+	require.NoError(t, err)
+	assert.Equal(t, repo.shortSHA("main"), repo.shortSHA("feature~1"))
+}
+
+func TestRebase_Integration_Conflict(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo, worktreePath := newSyncTestRepo(t)
+	// main's second commit appended to file.txt; append something else on feature
+	appendToFile(t, filepath.Join(worktreePath, "file.txt"), "conflicting line\n")
+	runGit(t, worktreePath, "commit", "-am", "conflicting commit")
+	before := repo.shortSHA("feature")
+
+	err := repo.Git.Rebase(t.Context(), worktreePath, "main")
+
+	var conflict *ConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, []string{"file.txt"}, conflict.Files)
+	// the rebase was aborted, leaving the branch where it was
+	assert.Equal(t, before, repo.shortSHA("feature"))
+	status := runGit(t, worktreePath, "status")
+	assert.NotContains(t, status, "rebase in progress")
+}
+
+func TestRebaseInProgress_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo, worktreePath := newSyncTestRepo(t)
+	appendToFile(t, filepath.Join(worktreePath, "file.txt"), "conflicting line\n")
+	runGit(t, worktreePath, "commit", "-am", "conflicting commit")
+
+	inProgress, err := repo.Git.IsRebaseInProgress(t.Context(), worktreePath)
+	require.NoError(t, err)
+	assert.False(t, inProgress)
+	files, err := repo.Git.ListConflictedFiles(t.Context(), worktreePath)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	// stop a rebase on its conflict without grove aborting it
+	cmd := exec.Command("git", "rebase", "main")
+	cmd.Dir = worktreePath
+	require.Error(t, cmd.Run())
+
+	inProgress, err = repo.Git.IsRebaseInProgress(t.Context(), worktreePath)
+	require.NoError(t, err)
+	assert.True(t, inProgress)
+	files, err = repo.Git.ListConflictedFiles(t.Context(), worktreePath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"file.txt"}, files)
+}
+
+func TestIsDirty_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo, worktreePath := newSyncTestRepo(t)
+	dirty, err := repo.Git.IsDirty(t.Context(), worktreePath)
+	require.NoError(t, err)
+	assert.False(t, dirty)
+
+	appendToFile(t, filepath.Join(worktreePath, ".gitignore"), "build/\n")
+	runGit(t, worktreePath, "add", ".gitignore")
+	runGit(t, worktreePath, "commit", "-m", "ignore build")
+	require.NoError(t, os.MkdirAll(filepath.Join(worktreePath, "build"), 0o755))
+	appendToFile(t, filepath.Join(worktreePath, "build", "out.bin"), "ignored")
+	dirty, err = repo.Git.IsDirty(t.Context(), worktreePath)
+	require.NoError(t, err)
+	assert.False(t, dirty, "ignored files do not count")
+
+	appendToFile(t, filepath.Join(worktreePath, "notes.txt"), "untracked")
+	dirty, err = repo.Git.IsDirty(t.Context(), worktreePath)
+	require.NoError(t, err)
+	assert.True(t, dirty)
+}
+
+// =============================================================================
+// FetchRemote tests
+// =============================================================================
+
+func TestFetchRemote_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	repo.addRemote("origin")
+
+	output, err := repo.Git.FetchRemote(t.Context(), "origin", nil)
+
+	require.NoError(t, err)
+	// Output may be empty if nothing new to fetch, that's OK
+	_ = output
+}
+
+func TestFetchRemote_Integration_DryRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepoWithDryRun(t)
+	repo.commit("initial commit")
+	// Don't actually add remote - in dry run it won't matter
+
+	output, err := repo.Git.FetchRemote(t.Context(), "origin", nil)
+
 	require.NoError(t, err)
 	assert.Contains(t, output, "Would execute")
 }
 
+func TestFetchRemote_Integration_Progress(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	remoteDir := repo.addRemote("origin")
+	runGit(t, remoteDir, "branch", "feature", "main")
+
+	var progress []string
+	_, err := repo.Git.FetchRemote(t.Context(), "origin", func(line string) { progress = append(progress, line) })
+
+	require.NoError(t, err)
+	assert.Contains(t, strings.Join(progress, "\n"), "[new branch]      feature    -> origin/feature")
+}
+
+// =============================================================================
+// CloneBare tests
+// =============================================================================
+
+func TestCloneBare_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	source := newTestRepo(t)
+	source.commit("initial commit")
+	runGit(t, source.rootDir, "branch", "feature", "main")
+	bareDir := filepath.Join(t.TempDir(), ".bare")
+
+	var progress []string
+	err := source.Git.CloneBare(t.Context(), source.rootDir, bareDir, func(line string) { progress = append(progress, line) })
+	require.NoError(t, err)
+
+	bare := New(clog.Default(), nil, false, bareDir, testTimeout, testTimeout, 0)
+	_, err = bare.FetchRemote(t.Context(), "origin", nil)
+	require.NoError(t, err)
+	branches, err := bare.ListRemoteBranches(t.Context(), "origin")
+	require.NoError(t, err)
+	names := make([]string, 0, len(branches))
+	for _, b := range branches {
+		names = append(names, b.Name)
+	}
+	assert.ElementsMatch(t, []string{"feature", "main"}, names)
+	current, err := bare.GetCurrentBranch(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "main", current)
+}
+
+func TestCloneBare_Integration_DryRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepoWithDryRun(t)
+	bareDir := filepath.Join(t.TempDir(), ".bare")
+
+	err := repo.Git.CloneBare(t.Context(), repo.rootDir, bareDir, nil)
+
+	require.NoError(t, err)
+	assert.NoDirExists(t, bareDir)
+}
+
 // =============================================================================
 // SyncTags tests
 // =============================================================================
@@ -1000,12 +1835,12 @@ func TestSyncTags_Integration(t *testing.T) {
 	// Create a tag in the remote
 	runGit(t, remoteDir, "tag", "v1.0.0")
 
-	err := repo.Git.SyncTags("origin")
+	err := repo.Git.SyncTags(t.Context(), "origin")
 
 	require.NoError(t, err)
 
 	// Verify the tag was fetched
-	tags, err := repo.Git.ListTags()
+	tags, err := repo.Git.ListTags(t.Context())
 	require.NoError(t, err)
 	assert.Contains(t, tagNames(tags), "v1.0.0")
 }
@@ -1021,7 +1856,7 @@ func TestSyncTags_Integration_EmptyRemoteName(t *testing.T) {
 	repo.setConfig("remote.pushDefault", "origin")
 
 	// Should use GetDefaultRemote fallback
-	err := repo.Git.SyncTags("")
+	err := repo.Git.SyncTags(t.Context(), "")
 
 	require.NoError(t, err)
 }
@@ -1040,11 +1875,655 @@ func TestSyncTags_Integration_RemoteOnlyTag(t *testing.T) {
 	remoteCommit := strings.TrimSpace(runGit(t, remoteDir, "-c", "user.name=Test User", "-c", "user.email=test@example.com", "commit-tree", tree, "-m", "remote-only commit"))
 	runGit(t, remoteDir, "tag", "v-remote-only", remoteCommit)
 
-	err := repo.Git.SyncTags("origin")
+	err := repo.Git.SyncTags(t.Context(), "origin")
 
 	require.NoError(t, err)
 
-	tags, err := repo.Git.ListTags()
+	tags, err := repo.Git.ListTags(t.Context())
 	require.NoError(t, err)
 	assert.Contains(t, tagNames(tags), "v-remote-only")
 }
+
+// =============================================================================
+// Worktree health tests
+// =============================================================================
+
+func TestListWorktrees_Integration_BranchMissing(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	repo.createBranch("feature")
+	worktreePath := filepath.Join(t.TempDir(), "feature")
+	repo.createWorktree(worktreePath, "feature")
+	runGit(t, repo.path(), "update-ref", "-d", "refs/heads/feature")
+
+	worktrees, err := repo.Git.ListWorktrees(t.Context())
+
+	require.NoError(t, err)
+	require.Len(t, worktrees, 2)
+	assert.False(t, worktrees[0].BranchMissing)
+	assert.True(t, worktrees[1].BranchMissing)
+	branch, ok := worktrees[1].Ref.FullBranch()
+	require.True(t, ok)
+	assert.Equal(t, "feature", branch.Name)
+}
+
+func TestGetWorktreeGitDir_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	repo.createBranch("feature")
+	worktreePath := filepath.Join(t.TempDir(), "feature")
+	repo.createWorktree(worktreePath, "feature")
+	commonDir, err := repo.Git.GetCommonDir(t.Context())
+	require.NoError(t, err)
+
+	mainGitDir, err := repo.Git.GetWorktreeGitDir(t.Context(), repo.path())
+	require.NoError(t, err)
+	linkedGitDir, err := repo.Git.GetWorktreeGitDir(t.Context(), worktreePath)
+	require.NoError(t, err)
+
+	assert.Equal(t, resolvePath(t, commonDir), resolvePath(t, mainGitDir))
+	assert.Equal(t, resolvePath(t, filepath.Join(commonDir, "worktrees", "feature")), resolvePath(t, linkedGitDir))
+}
+
+func TestGetLastActivity_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	gitDir, err := repo.Git.GetWorktreeGitDir(t.Context(), repo.path())
+	require.NoError(t, err)
+	old := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, path := range []string{
+		filepath.Join(gitDir, "HEAD"),
+		filepath.Join(gitDir, "index"),
+		filepath.Join(gitDir, "logs", "HEAD"),
+		filepath.Join(repo.path(), "file.txt"),
+	} {
+		require.NoError(t, os.Chtimes(path, old, old))
+	}
+	edited := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, os.Chtimes(filepath.Join(repo.path(), "file.txt"), edited, edited))
+	// untracked files do not count
+	untracked := filepath.Join(repo.path(), "scratch.txt")
+	require.NoError(t, os.WriteFile(untracked, nil, 0o644))
+	require.NoError(t, os.Chtimes(untracked, edited.Add(time.Hour), edited.Add(time.Hour)))
+
+	latest, err := repo.Git.GetLastActivity(t.Context(), repo.path())
+
+	require.NoError(t, err)
+	assert.True(t, edited.Equal(latest), "got %s", latest)
+
+	// cached for the lifetime of the client
+	require.NoError(t, os.Chtimes(filepath.Join(repo.path(), "file.txt"), old, old))
+	cached, err := repo.Git.GetLastActivity(t.Context(), repo.path())
+	require.NoError(t, err)
+	assert.True(t, edited.Equal(cached), "got %s", cached)
+}
+
+func TestRepairWorktree_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	repo.createBranch("feature")
+	worktreePath := filepath.Join(t.TempDir(), "feature")
+	repo.createWorktree(worktreePath, "feature")
+	// simulate the main repository having moved: the worktree still points at its old location
+	require.NoError(t, os.WriteFile(filepath.Join(worktreePath, ".git"), []byte("gitdir: /moved/.git/worktrees/feature\n"), 0o644))
+
+	_, err := repo.Git.GetWorktreeGitDir(t.Context(), worktreePath)
+	require.Error(t, err)
+
+	require.NoError(t, repo.Git.RepairWorktree(t.Context(), worktreePath))
+
+	_, err = repo.Git.GetWorktreeGitDir(t.Context(), worktreePath)
+	assert.NoError(t, err)
+}
+
+func TestPruneWorktrees_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	repo.createBranch("feature")
+	worktreePath := filepath.Join(t.TempDir(), "feature")
+	repo.createWorktree(worktreePath, "feature")
+	require.NoError(t, os.RemoveAll(worktreePath))
+
+	require.NoError(t, repo.Git.PruneWorktrees(t.Context()))
+
+	worktrees, err := repo.Git.ListWorktrees(t.Context())
+	require.NoError(t, err)
+	require.Len(t, worktrees, 1)
+	assert.True(t, worktrees[0].IsMain)
+}
+
+func TestSubmodules_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sub := newTestRepo(t)
+	sub.commit("submodule commit")
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	// submodules cloned from local paths need the file transport, which git disables by default
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+	runGit(t, repo.path(), "submodule", "add", sub.path(), "vendor/sub")
+	repo.commit("add submodule")
+	repo.createBranch("feature")
+	worktreePath := filepath.Join(t.TempDir(), "feature")
+	repo.createWorktree(worktreePath, "feature")
+
+	mainMissing, err := repo.Git.ListUninitializedSubmodules(t.Context(), repo.path())
+	require.NoError(t, err)
+	assert.Empty(t, mainMissing)
+
+	missing, err := repo.Git.ListUninitializedSubmodules(t.Context(), worktreePath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vendor/sub"}, missing)
+
+	require.NoError(t, repo.Git.InitSubmodules(t.Context(), worktreePath))
+
+	missing, err = repo.Git.ListUninitializedSubmodules(t.Context(), worktreePath)
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+}
+
+// =============================================================================
+// MoveWorktree tests
+// =============================================================================
+
+func TestMoveWorktree_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	repo.createBranch("feature")
+
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "old-worktree")
+	newPath := filepath.Join(dir, "new-worktree")
+	repo.createWorktree(oldPath, "feature")
+
+	err := repo.Git.MoveWorktree(t.Context(), oldPath, newPath)
+
+	require.NoError(t, err)
+	_, err = os.Stat(oldPath)
+	assert.True(t, os.IsNotExist(err))
+
+	worktreeGit := New(clog.Default(), nil, false, newPath, testTimeout, testTimeout, 0).(*GitCli)
+	branch, err := worktreeGit.GetCurrentBranch(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "feature", branch)
+}
+
+func TestMoveWorktree_Integration_DryRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepoWithDryRun(t)
+	repo.commit("initial commit")
+	repo.createBranch("feature")
+
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "old-worktree")
+	repo.createWorktree(oldPath, "feature")
+
+	err := repo.Git.MoveWorktree(t.Context(), oldPath, filepath.Join(dir, "new-worktree"))
+
+	require.NoError(t, err)
+	_, err = os.Stat(oldPath)
+	assert.NoError(t, err)
+}
+
+// =============================================================================
+// RemoveWorktree tests
+// =============================================================================
+
+func TestRemoveWorktree_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	repo.createBranch("feature")
+	wtPath := filepath.Join(t.TempDir(), "wt-feature")
+	repo.createWorktree(wtPath, "feature")
+	require.NoError(t, os.WriteFile(filepath.Join(wtPath, "untracked.txt"), []byte("work"), 0o644))
+
+	err := repo.Git.RemoveWorktree(t.Context(), wtPath, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to remove worktree")
+
+	require.NoError(t, repo.Git.RemoveWorktree(t.Context(), wtPath, true))
+	assert.NoDirExists(t, wtPath)
+	worktrees, err := repo.Git.ListWorktrees(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, worktrees, 1)
+	branches, err := repo.Git.ListLocalBranches(t.Context())
+	require.NoError(t, err)
+	assert.Contains(t, branchNames(branches), "feature", "the branch is kept")
+}
+
+// =============================================================================
+// UpdateRef tests
+// =============================================================================
+
+func TestUpdateRef_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	sha := repo.commit("initial commit")
+	full := strings.TrimSpace(runGit(t, repo.path(), "rev-parse", sha))
+
+	require.NoError(t, repo.Git.UpdateRef(t.Context(), "refs/grove/archive/feature/auth", full))
+
+	resolved, err := repo.Git.ResolveRef(t.Context(), "refs/grove/archive/feature/auth")
+	require.NoError(t, err)
+	assert.Equal(t, full, resolved)
+
+	require.NoError(t, repo.Git.DeleteRef(t.Context(), "refs/grove/archive/feature/auth"))
+	_, err = repo.Git.ResolveRef(t.Context(), "refs/grove/archive/feature/auth")
+	require.Error(t, err)
+
+	err = repo.Git.DeleteRef(t.Context(), "refs/grove/archive/feature/auth")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not exist")
+}
+
+// =============================================================================
+// RenameBranch tests
+// =============================================================================
+
+func TestRenameBranch_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	repo.createBranch("old-name")
+
+	err := repo.Git.RenameBranch(t.Context(), "old-name", "new-name")
+
+	require.NoError(t, err)
+	branches, err := repo.Git.ListLocalBranches(t.Context())
+	require.NoError(t, err)
+	assert.Contains(t, branchNames(branches), "new-name")
+	assert.NotContains(t, branchNames(branches), "old-name")
+}
+
+func TestRenameBranch_Integration_Existing(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	repo.createBranch("old-name")
+	repo.createBranch("taken")
+
+	err := repo.Git.RenameBranch(t.Context(), "old-name", "taken")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to rename branch")
+}
+
+// =============================================================================
+// DeleteBranch tests
+// =============================================================================
+
+func TestDeleteBranch_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	repo.createBranch("merged")
+	repo.createBranch("unmerged")
+	repo.checkout("unmerged")
+	repo.commit("local work")
+	repo.checkout("main")
+
+	require.NoError(t, repo.Git.DeleteBranch(t.Context(), "merged", false))
+
+	err := repo.Git.DeleteBranch(t.Context(), "unmerged", false)
+	require.ErrorIs(t, err, ErrNotMerged)
+
+	require.NoError(t, repo.Git.DeleteBranch(t.Context(), "unmerged", true))
+	branches, err := repo.Git.ListLocalBranches(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"main"}, branchNames(branches))
+}
+
+func TestDeleteRemoteBranch_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	remoteDir := repo.addRemote("origin")
+	runGit(t, remoteDir, "branch", "feature/auth")
+	runGit(t, repo.path(), "fetch", "origin")
+
+	require.NoError(t, repo.Git.DeleteRemoteBranch(t.Context(), "origin", "feature/auth"))
+
+	remoteBranches := runGit(t, remoteDir, "branch", "--list", "feature/auth")
+	assert.Empty(t, strings.TrimSpace(remoteBranches))
+	_, err := repo.Git.ResolveRef(t.Context(), "origin/feature/auth")
+	assert.Error(t, err, "the remote-tracking ref is deleted")
+
+	err = repo.Git.DeleteRemoteBranch(t.Context(), "origin", "feature/auth")
+	assert.Error(t, err)
+}
+
+func TestPush_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	remoteDir := repo.addRemote("origin")
+	repo.createBranch("feature/auth")
+
+	require.NoError(t, repo.Git.Push(t.Context(), "origin", "feature/auth"))
+
+	remoteSHA := strings.TrimSpace(runGit(t, remoteDir, "rev-parse", "feature/auth"))
+	assert.Equal(t, strings.TrimSpace(runGit(t, repo.path(), "rev-parse", "feature/auth")), remoteSHA)
+	upstream := strings.TrimSpace(runGit(t, repo.path(), "rev-parse", "--abbrev-ref", "feature/auth@{upstream}"))
+	assert.Equal(t, "origin/feature/auth", upstream)
+}
+
+// =============================================================================
+// BranchDescription tests
+// =============================================================================
+
+func TestBranchDescription_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	repo.createBranch("feature/auth")
+
+	description, err := repo.Git.GetBranchDescription(t.Context(), "feature/auth")
+	require.NoError(t, err)
+	assert.Empty(t, description)
+
+	require.NoError(t, repo.Git.SetBranchDescription(t.Context(), "feature/auth", "Adds login.\n\nUses OAuth."))
+
+	description, err = repo.Git.GetBranchDescription(t.Context(), "feature/auth")
+	require.NoError(t, err)
+	assert.Equal(t, "Adds login.\n\nUses OAuth.", description)
+}
+
+func TestWorktreeConfig_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	repo.setConfig("core.worktree", repo.path())
+
+	state, err := repo.Git.GetWorktreeConfigState(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, WorktreeConfigState{SharedWorktree: repo.path()}, state)
+
+	require.NoError(t, repo.Git.EnableWorktreeConfig(t.Context()))
+
+	state, err = repo.Git.GetWorktreeConfigState(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, WorktreeConfigState{Enabled: true}, state)
+	assert.Equal(t, repo.path(), strings.TrimSpace(runGit(t, repo.rootDir, "config", "--worktree", "--get", "core.worktree")))
+	assert.Equal(t, repo.path(), strings.TrimSpace(runGit(t, repo.rootDir, "rev-parse", "--show-toplevel")))
+}
+
+func TestEnableWorktreeConfig_DryRun_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepoWithDryRun(t)
+	repo.commit("initial commit")
+
+	require.NoError(t, repo.Git.EnableWorktreeConfig(t.Context()))
+
+	state, err := repo.Git.GetWorktreeConfigState(t.Context())
+	require.NoError(t, err)
+	assert.False(t, state.Enabled)
+}
+
+// =============================================================================
+// GetVersion tests
+// =============================================================================
+
+func TestGetVersion_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+
+	version, err := repo.Git.GetVersion(t.Context())
+
+	require.NoError(t, err)
+	assert.Regexp(t, `^\d+\.\d+`, version)
+}
+
+// =============================================================================
+// GetPager tests
+// =============================================================================
+
+func TestGetPager_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.setConfig("core.pager", "less -S")
+	t.Setenv("PAGER", "more")
+
+	tests := []struct {
+		name     string
+		gitPager string
+		want     string
+	}{
+		{name: "GIT_PAGER wins", gitPager: "bat", want: "bat"},
+		{name: "core.pager before PAGER", want: "less -S"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GIT_PAGER", tt.gitPager) // restores the original value after the test
+			if tt.gitPager == "" {
+				require.NoError(t, os.Unsetenv("GIT_PAGER"))
+			}
+
+			pager, err := repo.Git.GetPager(t.Context())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, pager)
+		})
+	}
+}
+
+// =============================================================================
+// GetUserName tests
+// =============================================================================
+
+func TestGetUserName_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+
+	name, err := repo.Git.GetUserName(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "Test User", name)
+
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	runGit(t, repo.path(), "config", "--unset", "user.name")
+	name, err = repo.Git.GetUserName(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, name)
+}
+
+// =============================================================================
+// Prunable worktree tests
+// =============================================================================
+
+func TestListWorktrees_Integration_Prunable(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	repo.createBranch("feature")
+
+	worktreePath := filepath.Join(t.TempDir(), "feature")
+	repo.createWorktree(worktreePath, "feature")
+	require.NoError(t, os.RemoveAll(worktreePath))
+
+	worktrees, err := repo.Git.ListWorktrees(t.Context())
+
+	require.NoError(t, err)
+	require.Len(t, worktrees, 2)
+	assert.Empty(t, worktrees[0].Prunable)
+	assert.NotEmpty(t, worktrees[1].Prunable)
+}
+
+// =============================================================================
+// ResolveRef tests
+// =============================================================================
+
+func TestGetAheadBehind_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	repo.createBranch("feature")
+	repo.commit("main 1")
+	repo.commit("main 2")
+	repo.checkout("feature")
+	repo.commit("feature 1")
+
+	ahead, behind, err := repo.Git.GetAheadBehind(t.Context(), "feature", "main")
+	require.NoError(t, err)
+	assert.Equal(t, 1, ahead)
+	assert.Equal(t, 2, behind)
+
+	ahead, behind, err = repo.Git.GetAheadBehind(t.Context(), "main", "main")
+	require.NoError(t, err)
+	assert.Zero(t, ahead)
+	assert.Zero(t, behind)
+
+	_, _, err = repo.Git.GetAheadBehind(t.Context(), "feature", "origin/main")
+	assert.Error(t, err)
+}
+
+func TestListUnreachableCommits_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	repo.commit("initial commit")
+	repo.createBranch("feature")
+	repo.createBranch("tagged")
+	repo.checkout("feature")
+	repo.commit("feature 1")
+	repo.commit("feature 2")
+	repo.checkout("tagged")
+	repo.commit("tagged 1")
+	repo.createLightweightTag("v1")
+	repo.checkout("main")
+
+	commits, err := repo.Git.ListUnreachableCommits(t.Context(), "feature")
+	require.NoError(t, err)
+	subjects := make([]string, 0, len(commits))
+	for _, c := range commits {
+		subjects = append(subjects, c.Subject)
+	}
+	assert.Equal(t, []string{"feature 2", "feature 1"}, subjects, "newest first")
+	assert.Equal(t, strings.TrimSpace(runGit(t, repo.rootDir, "rev-parse", "feature")), commits[0].SHA)
+	assert.False(t, commits[0].CommittedOn.IsZero())
+
+	commits, err = repo.Git.ListUnreachableCommits(t.Context(), "tagged")
+	require.NoError(t, err)
+	assert.Empty(t, commits, "a tag still reaches the commit")
+
+	_, err = repo.Git.ListUnreachableCommits(t.Context(), "nope")
+	assert.Error(t, err)
+}
+
+func TestResolveRef_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := newTestRepo(t)
+	sha := repo.commit("initial commit")
+	repo.createBranch("feature")
+	repo.createAnnotatedTag("v1.0.0", "Release")
+	fullSHA := strings.TrimSpace(runGit(t, repo.rootDir, "rev-parse", "HEAD"))
+
+	tests := []struct {
+		name    string
+		ref     string
+		wantErr bool
+	}{
+		{name: "branch", ref: "feature"},
+		{name: "annotated tag peels to commit", ref: "v1.0.0"},
+		{name: "short sha", ref: sha},
+		{name: "HEAD", ref: "HEAD"},
+		{name: "missing ref", ref: "nope", wantErr: true},
+		{name: "option-like ref", ref: "--all", wantErr: true},
+		{name: "empty", ref: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.Git.ResolveRef(t.Context(), tt.ref)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, fullSHA, got)
+		})
+	}
+}
@@ -1,10 +1,14 @@
 package git
 
 import (
+	"context"
+	"os/exec"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // =============================================================================
@@ -92,6 +96,7 @@ func TestParseTrackInfo(t *testing.T) {
 		input      string
 		wantAhead  int
 		wantBehind int
+		wantGone   bool
 	}{
 		{
 			name:       "empty string",
@@ -104,6 +109,7 @@ func TestParseTrackInfo(t *testing.T) {
 			input:      "[gone]",
 			wantAhead:  0,
 			wantBehind: 0,
+			wantGone:   true,
 		},
 		{
 			name:       "ahead only",
@@ -142,8 +148,32 @@ func TestParseTrackInfo(t *testing.T) {
 			wantBehind: 0,
 		},
 		{
-			name:       "malformed brackets",
-			input:      "ahead 5",
+			name:       "without brackets",
+			input:      "ahead 5, behind 1",
+			wantAhead:  5,
+			wantBehind: 1,
+		},
+		{
+			name:       "any order",
+			input:      "[behind 4, ahead 2]",
+			wantAhead:  2,
+			wantBehind: 4,
+		},
+		{
+			name:       "extra whitespace",
+			input:      " [ahead  7 ,  behind 1] ",
+			wantAhead:  7,
+			wantBehind: 1,
+		},
+		{
+			name:       "translated words are ignored",
+			input:      "[vor 2, hinter 1]",
+			wantAhead:  0,
+			wantBehind: 0,
+		},
+		{
+			name:       "non-numeric count",
+			input:      "[ahead many]",
 			wantAhead:  0,
 			wantBehind: 0,
 		},
@@ -151,9 +181,59 @@ func TestParseTrackInfo(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ahead, behind := parseTrackInfo(tt.input)
+			ahead, behind, gone := parseTrackInfo(tt.input)
 			assert.Equal(t, tt.wantAhead, ahead, "ahead mismatch")
 			assert.Equal(t, tt.wantBehind, behind, "behind mismatch")
+			assert.Equal(t, tt.wantGone, gone, "gone mismatch")
+		})
+	}
+}
+
+func TestGitEnv(t *testing.T) {
+	t.Setenv("GIT_OPTIONAL_LOCKS", "1")
+	t.Setenv("GIT_PAGER", "less")
+	t.Setenv("GIT_TERMINAL_PROMPT", "1")
+	t.Setenv("LC_ALL", "de_DE.UTF-8")
+
+	cmd := exec.Command("sh", "-c", `printf '%s %s %s %s' "$GIT_OPTIONAL_LOCKS" "$GIT_PAGER" "$GIT_TERMINAL_PROMPT" "$LC_ALL"`)
+	cmd.Env = gitEnv()
+	out, err := cmd.Output()
+
+	require.NoError(t, err)
+	// the last value of a duplicated variable wins
+	assert.Equal(t, "0 cat 0 C", string(out))
+}
+
+func TestExecuteGitCommand_Canceled(t *testing.T) {
+	g := newTestGitCli()
+	g.workingDir = t.TempDir()
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	_, err := g.executeGitCommand(ctx, "version")
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Contains(t, err.Error(), "git version interrupted")
+}
+
+func TestCommandTimeout(t *testing.T) {
+	g := newTestGitCli()
+	g.timeout, g.networkTimeout = 5*time.Second, time.Minute
+
+	tests := []struct {
+		args []string
+		want time.Duration
+	}{
+		{args: []string{"fetch", "origin"}, want: time.Minute},
+		{args: []string{"ls-remote", "origin"}, want: time.Minute},
+		{args: []string{"rev-parse", "HEAD"}, want: 5 * time.Second},
+		{args: []string{"-C", "/ws/wt", "status"}, want: 5 * time.Second},
+		{want: 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			assert.Equal(t, tt.want, g.commandTimeout(tt.args))
 		})
 	}
 }
@@ -205,27 +285,28 @@ func TestParseISO8601Date(t *testing.T) {
 }
 
 // =============================================================================
-// parseBranchBlock tests
+// parseBranchRecord tests
 // =============================================================================
 
-func TestParseBranchBlock(t *testing.T) {
+func TestParseBranchRecord(t *testing.T) {
 	tests := []struct {
-		name  string
-		input []string
-		want  LocalBranch
+		name     string
+		input    []string
+		want     LocalBranch
+		wantGone bool
 	}{
 		{
 			name: "complete branch with all fields",
 			input: []string{
-				"branch main",
-				"checkedOut true",
-				"commit abc1234",
-				"upstream origin/main",
-				"track [ahead 2, behind 1]",
-				"committedOn 2024-01-15T10:30:00Z",
-				"committedBy John Doe",
-				"subject Initial commit",
-				"worktreepath /home/user/project",
+				"main",
+				"*",
+				"abc1234",
+				"origin/main",
+				"[ahead 2, behind 1]",
+				"2024-01-15T10:30:00Z",
+				"John Doe",
+				"Initial commit",
+				"/home/user/project",
 			},
 			want: NewLocalBranch(
 				"main",
@@ -240,13 +321,15 @@ func TestParseBranchBlock(t *testing.T) {
 		{
 			name: "branch without upstream",
 			input: []string{
-				"branch feature-x",
-				"checkedOut false",
-				"commit def5678",
-				"committedOn 2024-02-20T14:00:00Z",
-				"committedBy Jane Smith",
-				"subject Add new feature",
-				"worktreepath ",
+				"feature-x",
+				" ",
+				"def5678",
+				"",
+				"",
+				"2024-02-20T14:00:00Z",
+				"Jane Smith",
+				"Add new feature",
+				"",
 			},
 			want: NewLocalBranch(
 				"feature-x",
@@ -261,15 +344,15 @@ func TestParseBranchBlock(t *testing.T) {
 		{
 			name: "branch with gone upstream",
 			input: []string{
-				"branch stale-branch",
-				"checkedOut false",
-				"commit ghi9012",
-				"upstream origin/deleted-branch",
-				"track [gone]",
-				"committedOn 2024-03-01T09:00:00Z",
-				"committedBy Developer",
-				"subject Some work",
-				"worktreepath ",
+				"stale-branch",
+				" ",
+				"ghi9012",
+				"origin/deleted-branch",
+				"[gone]",
+				"2024-03-01T09:00:00Z",
+				"Developer",
+				"Some work",
+				"",
 			},
 			want: NewLocalBranch(
 				"stale-branch",
@@ -280,19 +363,20 @@ func TestParseBranchBlock(t *testing.T) {
 				0,
 				NewCommit("ghi9012", "Some work", time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC), "Developer"),
 			),
+			wantGone: true,
 		},
 		{
 			name: "branch with only ahead",
 			input: []string{
-				"branch dev",
-				"checkedOut true",
-				"commit jkl3456",
-				"upstream origin/dev",
-				"track [ahead 5]",
-				"committedOn 2024-04-10T11:30:00Z",
-				"committedBy Alice",
-				"subject WIP",
-				"worktreepath /workspace/dev",
+				"dev",
+				"*",
+				"jkl3456",
+				"origin/dev",
+				"[ahead 5]",
+				"2024-04-10T11:30:00Z",
+				"Alice",
+				"WIP",
+				"/workspace/dev",
 			},
 			want: NewLocalBranch(
 				"dev",
@@ -307,13 +391,15 @@ func TestParseBranchBlock(t *testing.T) {
 		{
 			name: "branch with invalid date",
 			input: []string{
-				"branch broken",
-				"checkedOut false",
-				"commit xyz000",
-				"committedOn invalid-date",
-				"committedBy Unknown",
-				"subject Test",
-				"worktreepath ",
+				"broken",
+				" ",
+				"xyz000",
+				"",
+				"",
+				"invalid-date",
+				"Unknown",
+				"Test",
+				"",
 			},
 			want: NewLocalBranch(
 				"broken",
@@ -333,13 +419,15 @@ func TestParseBranchBlock(t *testing.T) {
 		{
 			name: "branch with slash in name",
 			input: []string{
-				"branch feature/my-feature",
-				"checkedOut false",
-				"commit abc123",
-				"committedOn 2024-01-01T00:00:00Z",
-				"committedBy Dev",
-				"subject Feature work",
-				"worktreepath ",
+				"feature/my-feature",
+				" ",
+				"abc123",
+				"",
+				"",
+				"2024-01-01T00:00:00Z",
+				"Dev",
+				"Feature work",
+				"",
 			},
 			want: NewLocalBranch(
 				"feature/my-feature",
@@ -355,13 +443,14 @@ func TestParseBranchBlock(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := parseBranchBlock(tt.input)
+			got := parseBranchRecord(tt.input)
 			assert.Equal(t, tt.want.Name, got.Name)
 			assert.Equal(t, tt.want.UpstreamName, got.UpstreamName)
 			assert.Equal(t, tt.want.WorktreeAbsolutePath, got.WorktreeAbsolutePath)
 			assert.Equal(t, tt.want.IsCheckedOut, got.IsCheckedOut)
 			assert.Equal(t, tt.want.Ahead, got.Ahead)
 			assert.Equal(t, tt.want.Behind, got.Behind)
+			assert.Equal(t, tt.wantGone, got.Gone)
 			assert.Equal(t, tt.want.Commit().SHA, got.Commit().SHA)
 			assert.Equal(t, tt.want.Commit().Subject, got.Commit().Subject)
 			assert.Equal(t, tt.want.Commit().CommittedBy, got.Commit().CommittedBy)
@@ -389,46 +478,40 @@ func TestParseBranchesFromFormat(t *testing.T) {
 		},
 		{
 			name: "single branch",
-			input: `branch main
-checkedOut true
-commit abc1234
-committedOn 2024-01-15T10:30:00Z
-committedBy John
-subject Initial
-worktreepath /home/user
-`,
+			input: forEachRefOutput(
+				[]string{"main", "*", "abc1234", "", "", "2024-01-15T10:30:00Z", "John", "Initial", "/home/user"},
+			),
 			wantNames:  []string{"main"},
 			wantLength: 1,
 		},
 		{
 			name: "multiple branches",
-			input: `branch main
-checkedOut true
-commit abc1234
-committedOn 2024-01-15T10:30:00Z
-committedBy John
-subject Initial
-worktreepath /home/user
-
-branch feature
-checkedOut false
-commit def5678
-committedOn 2024-01-16T11:00:00Z
-committedBy Jane
-subject Feature
-worktreepath
-
-branch develop
-checkedOut false
-commit ghi9012
-committedOn 2024-01-17T12:00:00Z
-committedBy Bob
-subject Develop
-worktreepath
-`,
+			input: forEachRefOutput(
+				[]string{"main", "*", "abc1234", "", "", "2024-01-15T10:30:00Z", "John", "Initial", "/home/user"},
+				[]string{"feature", " ", "def5678", "", "", "2024-01-16T11:00:00Z", "Jane", "Feature", ""},
+				[]string{"develop", " ", "ghi9012", "", "", "2024-01-17T12:00:00Z", "Bob", "Develop", ""},
+			),
 			wantNames:  []string{"main", "feature", "develop"},
 			wantLength: 3,
 		},
+		{
+			name: "subjects that look like fields and records",
+			input: forEachRefOutput(
+				[]string{"main", "*", "abc1234", "", "", "2024-01-15T10:30:00Z", "John", "branch evil", "/home/user"},
+				[]string{"feature", " ", "def5678", "", "", "2024-01-16T11:00:00Z", "Jane", "Fix\n\nbranch evil\nworktreepath /tmp", ""},
+			),
+			wantNames:  []string{"main", "feature"},
+			wantLength: 2,
+		},
+		{
+			name: "trailing partial record",
+			input: forEachRefOutput(
+				[]string{"main", "*", "abc1234", "", "", "2024-01-15T10:30:00Z", "John", "Initial", "/home/user"},
+				[]string{"feature", " ", "def5678"},
+			),
+			wantNames:  []string{"main"},
+			wantLength: 1,
+		},
 	}
 
 	for _, tt := range tests {
@@ -440,11 +523,26 @@ worktreepath
 	}
 }
 
+func TestParseBranchesFromFormat_SubjectWithMarkers(t *testing.T) {
+	subject := "branch evil\nworktreepath /tmp\n\nbranch other"
+	input := forEachRefOutput(
+		[]string{"main", "*", "abc1234", "origin/main", "[ahead 1]", "2024-01-15T10:30:00Z", "John", subject, "/home/user"},
+	)
+
+	got := parseBranchesFromFormat(input)
+
+	require.Len(t, got, 1)
+	assert.Equal(t, "main", got[0].Name)
+	assert.Equal(t, subject, got[0].Commit().Subject)
+	assert.Equal(t, "/home/user", got[0].WorktreeAbsolutePath)
+	assert.Equal(t, 1, got[0].Ahead)
+}
+
 // =============================================================================
-// parseRemoteBranchBlock tests
+// parseRemoteBranchRecord tests
 // =============================================================================
 
-func TestParseRemoteBranchBlock(t *testing.T) {
+func TestParseRemoteBranchRecord(t *testing.T) {
 	tests := []struct {
 		name  string
 		input []string
@@ -453,11 +551,11 @@ func TestParseRemoteBranchBlock(t *testing.T) {
 		{
 			name: "standard remote branch",
 			input: []string{
-				"ref origin/main",
-				"commit abc1234",
-				"committedOn 2024-01-15T10:30:00Z",
-				"committedBy John Doe",
-				"subject Initial commit",
+				"origin/main",
+				"abc1234",
+				"2024-01-15T10:30:00Z",
+				"John Doe",
+				"Initial commit",
 			},
 			want: NewRemoteBranch(
 				"main",
@@ -468,11 +566,11 @@ func TestParseRemoteBranchBlock(t *testing.T) {
 		{
 			name: "remote branch with slash in name",
 			input: []string{
-				"ref origin/feature/awesome",
-				"commit def5678",
-				"committedOn 2024-02-20T14:00:00Z",
-				"committedBy Jane Smith",
-				"subject Add feature",
+				"origin/feature/awesome",
+				"def5678",
+				"2024-02-20T14:00:00Z",
+				"Jane Smith",
+				"Add feature",
 			},
 			want: NewRemoteBranch(
 				"feature/awesome",
@@ -483,11 +581,11 @@ func TestParseRemoteBranchBlock(t *testing.T) {
 		{
 			name: "upstream remote",
 			input: []string{
-				"ref upstream/develop",
-				"commit ghi9012",
-				"committedOn 2024-03-01T09:00:00Z",
-				"committedBy Developer",
-				"subject Work in progress",
+				"upstream/develop",
+				"ghi9012",
+				"2024-03-01T09:00:00Z",
+				"Developer",
+				"Work in progress",
 			},
 			want: NewRemoteBranch(
 				"develop",
@@ -498,11 +596,11 @@ func TestParseRemoteBranchBlock(t *testing.T) {
 		{
 			name: "ref without slash",
 			input: []string{
-				"ref invalid",
-				"commit xyz000",
-				"committedOn 2024-01-01T00:00:00Z",
-				"committedBy Unknown",
-				"subject Test",
+				"invalid",
+				"xyz000",
+				"2024-01-01T00:00:00Z",
+				"Unknown",
+				"Test",
 			},
 			want: NewRemoteBranch(
 				"",
@@ -519,7 +617,7 @@ func TestParseRemoteBranchBlock(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := parseRemoteBranchBlock(tt.input)
+			got := parseRemoteBranchRecord(tt.input)
 			assert.Equal(t, tt.want.Name, got.Name)
 			assert.Equal(t, tt.want.RemoteName, got.RemoteName)
 			assert.Equal(t, tt.want.Commit().SHA, got.Commit().SHA)
@@ -548,52 +646,28 @@ func TestParseRemoteBranchesFromFormat(t *testing.T) {
 		},
 		{
 			name: "single remote branch",
-			input: `ref origin/main
-commit abc1234
-committedOn 2024-01-15T10:30:00Z
-committedBy John
-subject Initial
-`,
+			input: forEachRefOutput(
+				[]string{"origin/main", "abc1234", "2024-01-15T10:30:00Z", "John", "Initial"},
+			),
 			wantNames:  []string{"main"},
 			wantLength: 1,
 		},
 		{
 			name: "multiple remote branches",
-			input: `ref origin/main
-commit abc1234
-committedOn 2024-01-15T10:30:00Z
-committedBy John
-subject Initial
-
-ref origin/develop
-commit def5678
-committedOn 2024-01-16T11:00:00Z
-committedBy Jane
-subject Develop
-
-ref origin/feature/test
-commit ghi9012
-committedOn 2024-01-17T12:00:00Z
-committedBy Bob
-subject Feature
-`,
+			input: forEachRefOutput(
+				[]string{"origin/main", "abc1234", "2024-01-15T10:30:00Z", "John", "Initial"},
+				[]string{"origin/develop", "def5678", "2024-01-16T11:00:00Z", "Jane", "Develop"},
+				[]string{"origin/feature/test", "ghi9012", "2024-01-17T12:00:00Z", "Bob", "Feature"},
+			),
 			wantNames:  []string{"main", "develop", "feature/test"},
 			wantLength: 3,
 		},
 		{
 			name: "filters out symbolic HEAD ref",
-			input: `ref origin/HEAD
-commit abc1234
-committedOn 2024-01-15T10:30:00Z
-committedBy John
-subject Initial
-
-ref origin/main
-commit abc1234
-committedOn 2024-01-15T10:30:00Z
-committedBy John
-subject Initial
-`,
+			input: forEachRefOutput(
+				[]string{"origin/HEAD", "abc1234", "2024-01-15T10:30:00Z", "John", "Initial"},
+				[]string{"origin/main", "abc1234", "2024-01-15T10:30:00Z", "John", "Initial"},
+			),
 			wantNames:  []string{"main"},
 			wantLength: 1,
 		},
@@ -609,10 +683,10 @@ subject Initial
 }
 
 // =============================================================================
-// parseTagBlock tests
+// parseTagRecord tests
 // =============================================================================
 
-func TestParseTagBlock(t *testing.T) {
+func TestParseTagRecord(t *testing.T) {
 	tests := []struct {
 		name  string
 		input []string
@@ -621,18 +695,18 @@ func TestParseTagBlock(t *testing.T) {
 		{
 			name: "annotated tag with all fields",
 			input: []string{
-				"name v1.0.0",
-				"objecttype tag",
-				"objectsha tag123",
-				"derefsha abc1234",
-				"taggername John Doe",
-				"taggeremail john@example.com",
-				"taggedon 2024-01-15T10:30:00Z",
-				"message Release version 1.0.0",
-				"committedby Jane Smith",
-				"committedon 2024-01-14T09:00:00Z",
-				"committerdate ",
-				"commitsubject Initial commit",
+				"v1.0.0",
+				"tag",
+				"tag123",
+				"abc1234",
+				"John Doe",
+				"john@example.com",
+				"2024-01-15T10:30:00Z",
+				"Release version 1.0.0",
+				"Jane Smith",
+				"2024-01-14T09:00:00Z",
+				"",
+				"Initial commit",
 			},
 			want: NewTag(
 				"v1.0.0",
@@ -646,18 +720,18 @@ func TestParseTagBlock(t *testing.T) {
 		{
 			name: "lightweight tag",
 			input: []string{
-				"name v0.1.0",
-				"objecttype commit",
-				"objectsha def5678",
-				"derefsha ",
-				"taggername ",
-				"taggeremail ",
-				"taggedon ",
-				"message ",
-				"committedby ",
-				"committedon ",
-				"committerdate 2024-01-14T09:00:00Z",
-				"commitsubject ",
+				"v0.1.0",
+				"commit",
+				"def5678",
+				"",
+				"",
+				"",
+				"",
+				"",
+				"",
+				"",
+				"2024-01-14T09:00:00Z",
+				"",
 			},
 			want: NewTag(
 				"v0.1.0",
@@ -671,18 +745,18 @@ func TestParseTagBlock(t *testing.T) {
 		{
 			name: "annotated tag without commit subject fallback",
 			input: []string{
-				"name v2.0.0",
-				"objecttype tag",
-				"objectsha tag456",
-				"derefsha ghi9012",
-				"taggername Alice",
-				"taggeremail alice@example.com",
-				"taggedon 2024-06-01T12:00:00Z",
-				"message Major release",
-				"committedby Bob",
-				"committedon 2024-05-30T08:00:00Z",
-				"committerdate ",
-				"commitsubject Big feature",
+				"v2.0.0",
+				"tag",
+				"tag456",
+				"ghi9012",
+				"Alice",
+				"alice@example.com",
+				"2024-06-01T12:00:00Z",
+				"Major release",
+				"Bob",
+				"2024-05-30T08:00:00Z",
+				"",
+				"Big feature",
 			},
 			want: NewTag(
 				"v2.0.0",
@@ -702,7 +776,7 @@ func TestParseTagBlock(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := parseTagBlock(tt.input)
+			got := parseTagRecord(tt.input)
 			assert.Equal(t, tt.want.Name, got.Name)
 			assert.Equal(t, tt.want.Message, got.Message)
 			assert.Equal(t, tt.want.TaggerName, got.TaggerName)
@@ -735,62 +809,30 @@ func TestParseTagsFromFormat(t *testing.T) {
 		},
 		{
 			name: "single tag",
-			input: `name v1.0.0
-objecttype tag
-objectsha tag123
-derefsha abc1234
-taggername John
-taggeremail john@example.com
-taggedon 2024-01-15T10:30:00Z
-message Release
-committedby Jane
-committedon 2024-01-14T09:00:00Z
-commitsubject Initial
-`,
+			input: forEachRefOutput(
+				[]string{"v1.0.0", "tag", "tag123", "abc1234", "John", "john@example.com", "2024-01-15T10:30:00Z", "Release", "Jane", "2024-01-14T09:00:00Z", "", "Initial"},
+			),
 			wantNames:  []string{"v1.0.0"},
 			wantLength: 1,
 		},
 		{
 			name: "mixed annotated and lightweight tags",
-			input: `name v1.0.0
-objecttype tag
-objectsha tag123
-derefsha abc1234
-taggername John
-taggeremail john@example.com
-taggedon 2024-01-15T10:30:00Z
-message Release 1.0
-committedby Jane
-committedon 2024-01-14T09:00:00Z
-commitsubject Initial
-
-name v0.1.0
-objecttype commit
-objectsha def5678
-derefsha
-taggername
-taggeremail
-taggedon
-message
-committedby
-committedon
-commitsubject
-
-name v2.0.0-beta
-objecttype tag
-objectsha tag789
-derefsha ghi9012
-taggername Alice
-taggeremail alice@example.com
-taggedon 2024-06-01T12:00:00Z
-message Beta release
-committedby Bob
-committedon 2024-05-30T08:00:00Z
-commitsubject Beta feature
-`,
+			input: forEachRefOutput(
+				[]string{"v1.0.0", "tag", "tag123", "abc1234", "John", "john@example.com", "2024-01-15T10:30:00Z", "Release 1.0", "Jane", "2024-01-14T09:00:00Z", "", "Initial"},
+				[]string{"v0.1.0", "commit", "def5678", "", "", "", "", "", "", "", "", ""},
+				[]string{"v2.0.0-beta", "tag", "tag789", "ghi9012", "Alice", "alice@example.com", "2024-06-01T12:00:00Z", "Beta release", "Bob", "2024-05-30T08:00:00Z", "", "Beta feature"},
+			),
 			wantNames:  []string{"v1.0.0", "v0.1.0", "v2.0.0-beta"},
 			wantLength: 3,
 		},
+		{
+			name: "messages that look like fields and records",
+			input: forEachRefOutput(
+				[]string{"v1.0.0", "tag", "tag123", "abc1234", "John", "john@example.com", "2024-01-15T10:30:00Z", "name v9\n\nname v10", "Jane", "2024-01-14T09:00:00Z", "", "objecttype commit"},
+			),
+			wantNames:  []string{"v1.0.0"},
+			wantLength: 1,
+		},
 	}
 
 	for _, tt := range tests {
@@ -829,12 +871,15 @@ func TestParseWorktreeBlock(t *testing.T) {
 	}
 
 	tests := []struct {
-		name      string
-		input     []string
-		branchMap map[string]LocalBranch
-		tagMap    map[string]Tag
-		wantPath  string
-		wantErr   bool
+		name         string
+		input        []string
+		branchMap    map[string]LocalBranch
+		tagMap       map[string]Tag
+		wantPath     string
+		wantPrunable string
+		wantMissing  bool
+		wantBare     bool
+		wantCommit   string
 	}{
 		{
 			name: "worktree with branch",
@@ -846,7 +891,6 @@ func TestParseWorktreeBlock(t *testing.T) {
 			branchMap: branchMap,
 			tagMap:    tagMap,
 			wantPath:  "/home/user/project",
-			wantErr:   false,
 		},
 		{
 			name: "worktree with different branch",
@@ -858,7 +902,6 @@ func TestParseWorktreeBlock(t *testing.T) {
 			branchMap: branchMap,
 			tagMap:    tagMap,
 			wantPath:  "/home/user/feature",
-			wantErr:   false,
 		},
 		{
 			name: "detached HEAD worktree with tag",
@@ -870,7 +913,31 @@ func TestParseWorktreeBlock(t *testing.T) {
 			branchMap: branchMap,
 			tagMap:    tagMap,
 			wantPath:  "/home/user/release",
-			wantErr:   false,
+		},
+		{
+			name: "detached HEAD worktree without tag",
+			input: []string{
+				"worktree /home/user/bisect",
+				"HEAD 3456789012345678901234567890abcdef123456",
+				"detached",
+			},
+			branchMap:  branchMap,
+			tagMap:     tagMap,
+			wantPath:   "/home/user/bisect",
+			wantCommit: "3456789012345678901234567890abcdef123456",
+		},
+		{
+			name: "prunable worktree",
+			input: []string{
+				"worktree /home/user/feature",
+				"HEAD def5678901234567890abcdef1234567890abcde",
+				"branch refs/heads/feature",
+				"prunable gitdir file points to non-existent location",
+			},
+			branchMap:    branchMap,
+			tagMap:       tagMap,
+			wantPath:     "/home/user/feature",
+			wantPrunable: "gitdir file points to non-existent location",
 		},
 		{
 			name: "bare worktree",
@@ -881,7 +948,7 @@ func TestParseWorktreeBlock(t *testing.T) {
 			branchMap: branchMap,
 			tagMap:    tagMap,
 			wantPath:  "/home/user/bare.git",
-			wantErr:   false,
+			wantBare:  true,
 		},
 		{
 			name: "worktree with unknown branch",
@@ -890,10 +957,10 @@ func TestParseWorktreeBlock(t *testing.T) {
 				"HEAD 0000567890abcdef1234567890abcdef12345678",
 				"branch refs/heads/unknown",
 			},
-			branchMap: branchMap,
-			tagMap:    tagMap,
-			wantPath:  "",
-			wantErr:   true,
+			branchMap:   branchMap,
+			tagMap:      tagMap,
+			wantPath:    "/home/user/unknown",
+			wantMissing: true,
 		},
 		{
 			name:      "empty input",
@@ -901,19 +968,132 @@ func TestParseWorktreeBlock(t *testing.T) {
 			branchMap: branchMap,
 			tagMap:    tagMap,
 			wantPath:  "",
-			wantErr:   false,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := g.parseWorktreeBlock(tt.input, tt.branchMap, tt.tagMap)
+			got := g.parseWorktreeBlock(tt.input, tt.branchMap, tt.tagMap)
+			assert.Equal(t, tt.wantPath, got.AbsolutePath)
+			assert.Equal(t, tt.wantPrunable, got.Prunable)
+			assert.Equal(t, tt.wantMissing, got.BranchMissing)
+			assert.Equal(t, tt.wantBare, got.IsBare)
+			if tt.wantCommit != "" {
+				require.NotNil(t, got.Ref)
+				assert.Equal(t, WorktreeRefTypeCommit, got.Ref.Type())
+				assert.Equal(t, tt.wantCommit, got.Ref.Commit().SHA)
+			}
+		})
+	}
+}
+
+// =============================================================================
+
+func TestParseCommitLog(t *testing.T) {
+	g := newTestGitCli()
+	output := "abc123\x00Fix the build\x002024-06-01T12:00:00Z\x00Jane\n" +
+		"def456\x00Release 1.0\x00not a date\x00Bob\n"
+
+	commits, err := g.parseCommitLog(output)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Commit{
+		"abc123": NewCommit("abc123", "Fix the build", time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC), "Jane"),
+		"def456": NewCommit("def456", "Release 1.0", time.Time{}, "Bob"),
+	}, commits)
+
+	_, err = g.parseCommitLog("abc123\x00Fix the build")
+	assert.Error(t, err)
+}
+
+// =============================================================================
+// parseUninitializedSubmodules tests
+// =============================================================================
+
+func TestParseUninitializedSubmodules(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{name: "no submodules", output: "", want: nil},
+		{
+			name:   "all initialized",
+			output: "3f2a1b0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a vendor/lib (v1.2.0)",
+			want:   nil,
+		},
+		{
+			name: "mixed",
+			output: "3f2a1b0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a vendor/lib (v1.2.0)\n" +
+				"-0a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b docs/theme\n" +
+				"+1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c tools (heads/main)\n" +
+				"-2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d third_party/proto",
+			want: []string{"docs/theme", "third_party/proto"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseUninitializedSubmodules(tt.output))
+		})
+	}
+}
+
+func TestParseAheadBehind(t *testing.T) {
+	tests := []struct {
+		name       string
+		output     string
+		wantAhead  int
+		wantBehind int
+		wantErr    bool
+	}{
+		{name: "diverged", output: "3\t12", wantAhead: 3, wantBehind: 12},
+		{name: "even", output: "0\t0"},
+		{name: "one count", output: "3", wantErr: true},
+		{name: "not a number", output: "3\tx", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ahead, behind, err := parseAheadBehind(tt.output)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAhead, ahead)
+			assert.Equal(t, tt.wantBehind, behind)
+		})
+	}
+}
+
+// =============================================================================
+// parseVersion tests
+// =============================================================================
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    string
+		wantErr bool
+	}{
+		{name: "linux", output: "git version 2.43.0", want: "2.43.0"},
+		{name: "apple", output: "git version 2.39.3 (Apple Git-146)", want: "2.39.3"},
+		{name: "windows", output: "git version 2.41.0.windows.1", want: "2.41.0.windows.1"},
+		{name: "unexpected output", output: "hub version 2.14.2", wantErr: true},
+		{name: "empty", output: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseVersion(tt.output)
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
 			}
 			assert.NoError(t, err)
-			assert.Equal(t, tt.wantPath, got.AbsolutePath)
+			assert.Equal(t, tt.want, got)
 		})
 	}
 }
@@ -45,7 +45,7 @@ func newTestRepo(t *testing.T) *testRepo {
 	runGit(t, dir, "config", "user.name", "Test User")
 
 	return &testRepo{
-		Git:     New(false, dir, testTimeout).(*GitCli),
+		Git:     New(clog.Default(), nil, false, dir, testTimeout, testTimeout, 0).(*GitCli),
 		rootDir: dir,
 		t:       t,
 	}
@@ -62,7 +62,7 @@ func newTestRepoWithDryRun(t *testing.T) *testRepo {
 	runGit(t, dir, "config", "user.name", "Test User")
 
 	return &testRepo{
-		Git:     New(true, dir, testTimeout).(*GitCli),
+		Git:     New(clog.Default(), nil, true, dir, testTimeout, testTimeout, 0).(*GitCli),
 		rootDir: dir,
 		t:       t,
 	}
@@ -171,6 +171,20 @@ func appendToFile(t *testing.T, path, content string) {
 	require.NoError(t, err)
 }
 
+// forEachRefOutput returns `git for-each-ref` output in a forEachRefFormat for the given records,
+// trimmed the way executeGitCommand trims it.
+func forEachRefOutput(records ...[]string) string {
+	var sb strings.Builder
+	for _, record := range records {
+		for _, field := range record {
+			sb.WriteString(field)
+			sb.WriteString("\x00")
+		}
+		sb.WriteString("\n")
+	}
+	return strings.TrimSpace(sb.String())
+}
+
 // branchNames extracts branch names from a slice of LocalBranch.
 func branchNames(branches []LocalBranch) []string {
 	names := make([]string, len(branches))
@@ -207,6 +221,15 @@ func worktreePaths(worktrees []Worktree) []string {
 	return paths
 }
 
+// symlinkOrSkip creates a symlink, skipping the test where the platform does not let the user create one
+// (e.g. Windows without developer mode).
+func symlinkOrSkip(t *testing.T, oldname, newname string) {
+	t.Helper()
+	if err := os.Symlink(oldname, newname); err != nil {
+		t.Skipf("cannot create symlinks here: %v", err)
+	}
+}
+
 // resolvePath resolves symlinks in a path (useful for macOS /var -> /private/var).
 func resolvePath(t *testing.T, path string) string {
 	t.Helper()
@@ -1,14 +1,42 @@
 package github
 
+import (
+	"context"
+	"io"
+)
+
+// GitHub is grove's view of the repository's pull requests. Every method runs its gh commands under ctx,
+// each also bounded by the client's timeout.
 type GitHub interface {
+	// AuthStatus returns an error if gh is not logged in to the repository's host.
+	AuthStatus(ctx context.Context) error
+
+	// CreatePullRequest opens a pull request for a branch already pushed to the remote and returns its URL.
+	// Unlike the read methods, a failed call is not retried, since a retry could open it twice.
+	CreatePullRequest(ctx context.Context, pr NewPullRequest) (string, error)
+
 	// GetPullRequest returns a single pull request by number.
-	GetPullRequest(prNum int) (PullRequest, error)
+	GetPullRequest(ctx context.Context, prNum int) (PullRequest, error)
 
 	// GetPullRequestByBranch returns the pull request for the given branch name.
 	// Returns nil if no pull request exists for the branch.
-	GetPullRequestByBranch(branchName string) (*PullRequest, error)
+	GetPullRequestByBranch(ctx context.Context, branchName string) (*PullRequest, error)
+
+	// GetPullRequestDiff returns the unified diff of a pull request's changes, without color.
+	GetPullRequestDiff(ctx context.Context, prNum int) (string, error)
+
+	// GetRepository returns the GitHub repository of the current git repository.
+	GetRepository(ctx context.Context) (Repository, error)
+
+	// ListPullRequestFiles returns the paths of the files changed by a pull request.
+	ListPullRequestFiles(ctx context.Context, prNum int) ([]string, error)
 
 	// ListPullRequests returns a list of pull requests matching the given query.
 	// Use DefaultPRLimit for the limit parameter to get the standard number of results.
-	ListPullRequests(query PRQuery, limit int) ([]PullRequest, error)
+	// A limit of 0 returns every matching pull request, paging through the results.
+	ListPullRequests(ctx context.Context, query PRQuery, limit int) ([]PullRequest, error)
+
+	// WritePullRequestDiff writes the unified diff of a pull request's changes to w, without color, as it
+	// arrives instead of holding it in memory. Unlike the other methods, a failed call is not retried.
+	WritePullRequestDiff(ctx context.Context, prNum int, w io.Writer) error
 }
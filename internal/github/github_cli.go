@@ -4,72 +4,147 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
 	"os/exec"
+	"regexp"
 	"strings"
 	"time"
 
 	clog "github.com/charmbracelet/log"
+	"github.com/jmcampanini/grove-cli/internal/retry"
+	"github.com/jmcampanini/grove-cli/internal/timing"
 )
 
 // DefaultPRLimit is the maximum number of pull requests returned by ListPullRequests.
 const DefaultPRLimit = 20
 
+// prSearchPageSize is the number of pull requests fetched per GraphQL search request (the API maximum).
+const prSearchPageSize = 100
+
 // GitHubCli provides GitHub operations by executing the gh CLI.
 type GitHubCli struct {
 	log        *clog.Logger
+	retries    int // times a failed gh call is retried
 	timeout    time.Duration
+	timings    *timing.Recorder
 	workingDir string
 }
 
 var _ GitHub = &GitHubCli{}
 
 // New creates a new GitHubCli instance that executes gh commands
-// in the specified working directory.
-func New(workingDir string, timeout time.Duration) GitHub {
+// in the specified working directory. Calls that fail for a transient reason, such as a timeout or a server
+// error, are retried up to retries times with backoff.
+// Calls are logged to log, prefixed with "github", and how long each took is recorded in timings, which may be nil.
+func New(log *clog.Logger, timings *timing.Recorder, workingDir string, timeout time.Duration, retries int) GitHub {
 	return &GitHubCli{
-		log:        clog.Default().WithPrefix("github"),
+		log:        log.WithPrefix("github"),
+		retries:    retries,
 		timeout:    timeout,
+		timings:    timings,
 		workingDir: workingDir,
 	}
 }
 
-func (g *GitHubCli) executeGhCommand(args ...string) (string, error) {
+// Available returns an error if the gh CLI cannot be found on PATH.
+func Available() error {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return errors.New("this command requires the GitHub CLI (gh); install it from https://cli.github.com")
+	}
+	return nil
+}
+
+// executeGhCommand runs gh, retrying when it fails for a transient reason. Only gh calls that read go through
+// it, so a retry never repeats a change.
+func (g *GitHubCli) executeGhCommand(ctx context.Context, args ...string) (string, error) {
+	return retry.Do(ctx, g.retries, g.log, "gh "+strings.Join(args, " "), transientGhError, func() (string, error) {
+		return g.runGhCommand(ctx, args...)
+	})
+}
+
+// retryableHTTPStatus matches the HTTP statuses gh reports for a server error or rate limit, such as
+// "HTTP 502: Bad Gateway".
+var retryableHTTPStatus = regexp.MustCompile(`\bHTTP (5\d\d|429)\b`)
+
+// transientGhError reports whether gh failed for a reason that may not happen again: a timeout, a connection,
+// DNS, or TLS failure, a server error, or a rate limit. A missing repository or pull request is not retried.
+func transientGhError(err error) bool {
+	return retry.Transient(err) || (err != nil && retryableHTTPStatus.MatchString(err.Error()))
+}
+
+// runGhCommand runs gh once, returning its trimmed stdout.
+func (g *GitHubCli) runGhCommand(ctx context.Context, args ...string) (string, error) {
+	var stdout bytes.Buffer
+	if err := g.streamGhCommand(ctx, &stdout, args...); err != nil {
+		return "", err
+	}
+	output := strings.TrimSpace(stdout.String())
+	g.log.Debug("gh command succeeded", "args", args, "outputLen", len(output))
+	return output, nil
+}
+
+// streamGhCommand runs gh once, copying its stdout to w as it is produced instead of holding it in memory.
+// It is never retried, since w may already have part of the output.
+func (g *GitHubCli) streamGhCommand(ctx context.Context, w io.Writer, args ...string) error {
 	g.log.Debug("Executing gh command", "cmd", "gh", "args", args, "workingDir", g.workingDir)
 
-	ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+	ctx, cancel := context.WithTimeout(ctx, g.timeout)
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, "gh", args...)
 	cmd.Dir = g.workingDir
 	cmd.Env = append(os.Environ(), "GH_PROMPT_DISABLED=1")
 
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
+	var stderr bytes.Buffer
+	cmd.Stdout = w
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	start := time.Now()
+	err := cmd.Run()
+	g.timings.Record("gh", args, time.Since(start), err)
+	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			g.log.Warn("gh command timed out", "args", args, "timeout", g.timeout, "error", err)
-			return "", fmt.Errorf("gh %s timed out after %s", strings.Join(args, " "), g.timeout)
+			return fmt.Errorf("gh %s timed out after %s", strings.Join(args, " "), g.timeout)
+		}
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return fmt.Errorf("gh %s interrupted: %w", strings.Join(args, " "), ctx.Err())
 		}
 		g.log.Warn("gh command failed", "args", args, "stderr", stderr.String(), "error", err)
-		return "", fmt.Errorf("gh %s failed: %w: %s", strings.Join(args, " "), err, stderr.String())
+		return fmt.Errorf("gh %s failed: %w: %s", strings.Join(args, " "), err, stderr.String())
 	}
+	return nil
+}
 
-	output := strings.TrimSpace(stdout.String())
-	g.log.Debug("gh command succeeded", "args", args, "outputLen", len(output))
-	return output, nil
+func (g *GitHubCli) AuthStatus(ctx context.Context) error {
+	// not retried: a missing login is not transient
+	if _, err := g.runGhCommand(ctx, "auth", "status"); err != nil {
+		return fmt.Errorf("gh is not authenticated: %w", err)
+	}
+	return nil
+}
+
+func (g *GitHubCli) CreatePullRequest(ctx context.Context, pr NewPullRequest) (string, error) {
+	output, err := g.runGhCommand(ctx, pr.args()...)
+	if err != nil {
+		return "", fmt.Errorf("failed to create pull request for %s: %w", pr.Head, err)
+	}
+	// gh prints the URL of the new pull request last
+	lines := strings.Split(output, "\n")
+	return strings.TrimSpace(lines[len(lines)-1]), nil
 }
 
-func (g *GitHubCli) GetPullRequest(prNum int) (PullRequest, error) {
+func (g *GitHubCli) GetPullRequest(ctx context.Context, prNum int) (PullRequest, error) {
 	args := []string{
 		"pr", "view", fmt.Sprintf("%d", prNum),
 		"--json", prJsonFields,
 	}
 
-	output, err := g.executeGhCommand(args...)
+	output, err := g.executeGhCommand(ctx, args...)
 	if err != nil {
 		return PullRequest{}, fmt.Errorf("failed to get pull request #%d: %w", prNum, err)
 	}
@@ -82,7 +157,7 @@ func (g *GitHubCli) GetPullRequest(prNum int) (PullRequest, error) {
 	return pr, nil
 }
 
-func (g *GitHubCli) GetPullRequestByBranch(branchName string) (*PullRequest, error) {
+func (g *GitHubCli) GetPullRequestByBranch(ctx context.Context, branchName string) (*PullRequest, error) {
 	args := []string{
 		"pr", "list",
 		"--head", branchName,
@@ -91,7 +166,7 @@ func (g *GitHubCli) GetPullRequestByBranch(branchName string) (*PullRequest, err
 		"--limit", "1",
 	}
 
-	output, err := g.executeGhCommand(args...)
+	output, err := g.executeGhCommand(ctx, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pull request for branch %s: %w", branchName, err)
 	}
@@ -108,17 +183,78 @@ func (g *GitHubCli) GetPullRequestByBranch(branchName string) (*PullRequest, err
 	return &prs[0], nil
 }
 
-func (g *GitHubCli) ListPullRequests(query PRQuery, limit int) ([]PullRequest, error) {
+func (g *GitHubCli) GetPullRequestDiff(ctx context.Context, prNum int) (string, error) {
+	output, err := g.executeGhCommand(ctx, "pr", "diff", fmt.Sprintf("%d", prNum), "--color", "never")
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff for pull request #%d: %w", prNum, err)
+	}
+	return output, nil
+}
+
+func (g *GitHubCli) WritePullRequestDiff(ctx context.Context, prNum int, w io.Writer) error {
+	if err := g.streamGhCommand(ctx, w, "pr", "diff", fmt.Sprintf("%d", prNum), "--color", "never"); err != nil {
+		return fmt.Errorf("failed to get diff for pull request #%d: %w", prNum, err)
+	}
+	return nil
+}
+
+func (g *GitHubCli) GetRepository(ctx context.Context) (Repository, error) {
+	output, err := g.executeGhCommand(ctx, "repo", "view", "--json", "name,owner,url")
+	if err != nil {
+		return Repository{}, fmt.Errorf("failed to get repository: %w", err)
+	}
+	return parseRepository(output)
+}
+
+// parseRepository parses the output of `gh repo view --json name,owner,url`.
+func parseRepository(output string) (Repository, error) {
+	var view struct {
+		Name  string `json:"name"`
+		Owner struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal([]byte(output), &view); err != nil {
+		return Repository{}, fmt.Errorf("failed to parse repository: %w", err)
+	}
+	u, err := url.Parse(view.URL)
+	if err != nil {
+		return Repository{}, fmt.Errorf("failed to parse repository URL %q: %w", view.URL, err)
+	}
+	return Repository{Host: u.Host, Name: view.Name, Owner: view.Owner.Login}, nil
+}
+
+func (g *GitHubCli) ListPullRequestFiles(ctx context.Context, prNum int) ([]string, error) {
+	output, err := g.executeGhCommand(ctx, "pr", "diff", fmt.Sprintf("%d", prNum), "--name-only")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files for pull request #%d: %w", prNum, err)
+	}
+
+	if output == "" {
+		return []string{}, nil
+	}
+
+	return strings.Split(output, "\n"), nil
+}
+
+func (g *GitHubCli) ListPullRequests(ctx context.Context, query PRQuery, limit int) ([]PullRequest, error) {
+	if limit <= 0 {
+		return g.listAllPullRequests(ctx, query)
+	}
+
 	searchQuery := query.ToSearchQuery()
 
 	args := []string{
 		"pr", "list",
 		"--search", searchQuery,
+		"--state", "all", // the search query selects the state
+
 		"--json", prJsonFields,
 		"--limit", fmt.Sprintf("%d", limit),
 	}
 
-	output, err := g.executeGhCommand(args...)
+	output, err := g.executeGhCommand(ctx, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list pull requests: %w", err)
 	}
@@ -130,3 +266,144 @@ func (g *GitHubCli) ListPullRequests(query PRQuery, limit int) ([]PullRequest, e
 
 	return prs, nil
 }
+
+// listAllPullRequests pages through the GraphQL search API, one gh call per page, until every
+// pull request matching the query is returned. GitHub caps search results at 1000.
+func (g *GitHubCli) listAllPullRequests(ctx context.Context, query PRQuery) ([]PullRequest, error) {
+	repo, err := g.GetRepository(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+	searchQuery := fmt.Sprintf("repo:%s/%s %s", repo.Owner, repo.Name, query.ToSearchQuery())
+
+	prs := []PullRequest{}
+	cursor := ""
+	for {
+		args := []string{
+			"api", "graphql",
+			"--hostname", repo.Host,
+			"-f", "query=" + prSearchGraphQL,
+			"-f", "q=" + searchQuery,
+			"-F", fmt.Sprintf("perPage=%d", prSearchPageSize),
+		}
+		if cursor != "" {
+			args = append(args, "-f", "endCursor="+cursor)
+		}
+
+		output, err := g.executeGhCommand(ctx, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pull requests: %w", err)
+		}
+		page, err := parsePRSearchPage(output)
+		if err != nil {
+			return nil, err
+		}
+		prs = append(prs, page.PullRequests...)
+		if !page.HasNextPage || page.EndCursor == "" {
+			return prs, nil
+		}
+		cursor = page.EndCursor
+	}
+}
+
+// prSearchGraphQL selects the fields of prJsonFields for one page of pull request search results.
+const prSearchGraphQL = `query($q: String!, $perPage: Int!, $endCursor: String) {
+  search(query: $q, type: ISSUE, first: $perPage, after: $endCursor) {
+    nodes {
+      ... on PullRequest {
+        additions
+        author { login ... on User { name } }
+        body
+        changedFiles
+        createdAt
+        deletions
+        headRefName
+        headRefOid
+        isCrossRepository
+        isDraft
+        number
+        reviewDecision
+        state
+        title
+        updatedAt
+        url
+        commits(last: 1) {
+          nodes {
+            commit {
+              statusCheckRollup {
+                contexts(first: 100) {
+                  nodes {
+                    __typename
+                    ... on CheckRun { conclusion status }
+                    ... on StatusContext { state }
+                  }
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+    pageInfo { endCursor hasNextPage }
+  }
+}`
+
+// prSearchPage is one page of pull request search results.
+type prSearchPage struct {
+	EndCursor    string
+	HasNextPage  bool
+	PullRequests []PullRequest
+}
+
+// prSearchNode is a pull request in a GraphQL search response. It has the same fields as gh's --json
+// output except for the check rollup, which GraphQL nests under the last commit.
+type prSearchNode struct {
+	rawPR
+	Commits struct {
+		Nodes []struct {
+			Commit struct {
+				StatusCheckRollup struct {
+					Contexts struct {
+						Nodes []checkRollupItem `json:"nodes"`
+					} `json:"contexts"`
+				} `json:"statusCheckRollup"`
+			} `json:"commit"`
+		} `json:"nodes"`
+	} `json:"commits"`
+}
+
+// parsePRSearchPage parses the output of `gh api graphql` for prSearchGraphQL.
+func parsePRSearchPage(output string) (prSearchPage, error) {
+	var response struct {
+		Data struct {
+			Search struct {
+				Nodes    []prSearchNode `json:"nodes"`
+				PageInfo struct {
+					EndCursor   string `json:"endCursor"`
+					HasNextPage bool   `json:"hasNextPage"`
+				} `json:"pageInfo"`
+			} `json:"search"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(output), &response); err != nil {
+		return prSearchPage{}, fmt.Errorf("failed to parse pull requests: %w", err)
+	}
+
+	search := response.Data.Search
+	page := prSearchPage{EndCursor: search.PageInfo.EndCursor, HasNextPage: search.PageInfo.HasNextPage}
+	for _, node := range search.Nodes {
+		if node.Number == 0 {
+			continue // not a pull request
+		}
+		raw := node.rawPR
+		if commits := node.Commits.Nodes; len(commits) > 0 {
+			raw.Rollup = commits[0].Commit.StatusCheckRollup.Contexts.Nodes
+		}
+		pr, err := raw.pullRequest()
+		if err != nil {
+			return prSearchPage{}, fmt.Errorf("failed to parse pull request #%d: %w", node.Number, err)
+		}
+		page.PullRequests = append(page.PullRequests, pr)
+	}
+	return page, nil
+}
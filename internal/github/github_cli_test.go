@@ -1,10 +1,13 @@
 package github
 
 import (
+	"errors"
 	"os/exec"
 	"testing"
 	"time"
 
+	clog "github.com/charmbracelet/log"
+	"github.com/jmcampanini/grove-cli/internal/retry"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -12,7 +15,7 @@ import (
 const testTimeout = 30 * time.Second
 
 func TestNew(t *testing.T) {
-	gh := New("/some/path", 60*time.Second)
+	gh := New(clog.Default(), nil, "/some/path", 60*time.Second, 3)
 
 	require.NotNil(t, gh)
 
@@ -25,6 +28,110 @@ func TestDefaultPRLimit(t *testing.T) {
 	assert.Equal(t, 20, DefaultPRLimit)
 }
 
+func TestParseRepository(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    Repository
+		wantErr bool
+	}{
+		{
+			name:   "github.com",
+			output: `{"name":"grove-cli","owner":{"id":"MDQ6","login":"jmcampanini"},"url":"https://github.com/jmcampanini/grove-cli"}`,
+			want:   Repository{Host: "github.com", Name: "grove-cli", Owner: "jmcampanini"},
+		},
+		{
+			name:   "enterprise",
+			output: `{"name":"svc","owner":{"login":"team"},"url":"https://git.example.com/team/svc"}`,
+			want:   Repository{Host: "git.example.com", Name: "svc", Owner: "team"},
+		},
+		{name: "invalid json", output: `not json`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRepository(tt.output)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParsePRSearchPage(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    prSearchPage
+		wantErr string
+	}{
+		{
+			name: "page with more results",
+			output: `{"data":{"search":{"nodes":[{
+				"additions":10,"author":{"login":"octocat","name":"Mona"},"body":"Adds auth","changedFiles":2,
+				"createdAt":"2024-01-15T10:30:00Z","deletions":3,"headRefName":"feature/auth","headRefOid":"4f2c9a1",
+				"isCrossRepository":true,"isDraft":false,"number":42,"reviewDecision":"APPROVED","state":"OPEN","title":"Add auth",
+				"updatedAt":"2024-01-16T10:30:00Z","url":"https://github.com/o/r/pull/42",
+				"commits":{"nodes":[{"commit":{"statusCheckRollup":{"contexts":{"nodes":[
+					{"__typename":"CheckRun","conclusion":"FAILURE","status":"COMPLETED"}
+				]}}}}]}
+			}],"pageInfo":{"endCursor":"Y3Vyc29yOjE=","hasNextPage":true}}}}`,
+			want: prSearchPage{
+				EndCursor:   "Y3Vyc29yOjE=",
+				HasNextPage: true,
+				PullRequests: []PullRequest{{
+					AuthorLogin:  "octocat",
+					AuthorName:   "Mona",
+					Body:         "Adds auth",
+					BranchName:   "feature/auth",
+					Checks:       ChecksFailing,
+					CreatedAt:    time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+					FilesChanged: 2,
+					FromFork:     true,
+					HeadSHA:      "4f2c9a1",
+					LinesAdded:   10,
+					LinesDeleted: 3,
+					Number:       42,
+					Review:       ReviewApproved,
+					State:        PRStateOpen,
+					Title:        "Add auth",
+					UpdatedAt:    time.Date(2024, 1, 16, 10, 30, 0, 0, time.UTC),
+					URL:          "https://github.com/o/r/pull/42",
+				}},
+			},
+		},
+		{
+			name: "last page without checks",
+			output: `{"data":{"search":{"nodes":[
+				{"number":7,"state":"OPEN","isDraft":true,"title":"WIP","headRefName":"wip","commits":{"nodes":[{"commit":{"statusCheckRollup":null}}]}},
+				{}
+			],"pageInfo":{"endCursor":"Y3Vyc29yOjI=","hasNextPage":false}}}}`,
+			want: prSearchPage{
+				EndCursor:    "Y3Vyc29yOjI=",
+				PullRequests: []PullRequest{{BranchName: "wip", Number: 7, State: PRStateDraft, Title: "WIP"}},
+			},
+		},
+		{name: "unknown state", output: `{"data":{"search":{"nodes":[{"number":7,"state":"LOCKED"}]}}}`, wantErr: "failed to parse pull request #7"},
+		{name: "invalid json", output: `not json`, wantErr: "failed to parse pull requests"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePRSearchPage(tt.output)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 // skipIfGhNotAvailable skips the test if gh CLI is not installed or not authenticated.
 func skipIfGhNotAvailable(t *testing.T) {
 	t.Helper()
@@ -53,6 +160,40 @@ func skipIfNotInGitRepo(t *testing.T) {
 // Integration tests - these require gh to be installed and authenticated,
 // and require running in a git repository with a GitHub remote.
 
+func TestTransientGhError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "server error", err: errors.New("gh pr view 12 failed: exit status 1: HTTP 502: Bad Gateway (https://api.github.com/graphql)"), want: true},
+		{name: "rate limited", err: errors.New("gh api graphql failed: exit status 1: HTTP 429: Too Many Requests"), want: true},
+		{name: "offline", err: errors.New("gh pr list failed: exit status 1: error connecting to api.github.com"), want: true},
+		{name: "timed out", err: errors.New("gh pr list timed out after 30s"), want: true},
+		{name: "not found", err: errors.New("gh api repos/o/r failed: exit status 1: HTTP 404: Not Found"), want: false},
+		{name: "no pull request", err: errors.New("gh pr view 99 failed: exit status 1: GraphQL: Could not resolve to a PullRequest with the number of 99."), want: false},
+		{name: "unauthorized", err: errors.New("gh api user failed: exit status 1: HTTP 401: Bad credentials"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, transientGhError(tt.err))
+		})
+	}
+}
+
+func TestTransientGhError_NotFoundNotRetried(t *testing.T) {
+	attempts := 0
+
+	_, err := retry.Do(t.Context(), 3, clog.Default(), "gh pr view 99", transientGhError, func() (string, error) {
+		attempts++
+		return "", errors.New("gh pr view 99 failed: exit status 1: HTTP 404: Not Found")
+	})
+
+	require.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
+
 func TestGitHubCli_GetPullRequest_Integration(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test in short mode")
@@ -72,10 +213,10 @@ func TestGitHubCli_GetPullRequestByBranch_Integration(t *testing.T) {
 	skipIfGhNotAvailable(t)
 	skipIfNotInGitRepo(t)
 
-	gh := New(".", testTimeout)
+	gh := New(clog.Default(), nil, ".", testTimeout, 0)
 
 	// Test with a branch that likely doesn't have a PR
-	pr, err := gh.GetPullRequestByBranch("nonexistent-branch-12345")
+	pr, err := gh.GetPullRequestByBranch(t.Context(), "nonexistent-branch-12345")
 	require.NoError(t, err)
 	assert.Nil(t, pr, "expected nil for nonexistent branch")
 }
@@ -87,10 +228,10 @@ func TestGitHubCli_ListPullRequests_Integration(t *testing.T) {
 	skipIfGhNotAvailable(t)
 	skipIfNotInGitRepo(t)
 
-	gh := New(".", testTimeout)
+	gh := New(clog.Default(), nil, ".", testTimeout, 0)
 
 	// List open PRs (may return empty list, which is fine)
-	prs, err := gh.ListPullRequests(PRQuery{State: PRStateOpen}, DefaultPRLimit)
+	prs, err := gh.ListPullRequests(t.Context(), PRQuery{State: PRStateOpen}, DefaultPRLimit)
 	require.NoError(t, err)
 	assert.NotNil(t, prs, "expected non-nil slice (even if empty)")
 
@@ -14,6 +14,7 @@ const (
 	PRStateClosed PRState = "CLOSED"
 	PRStateMerged PRState = "MERGED"
 	PRStateDraft  PRState = "DRAFT" // Virtual state: GitHub returns OPEN + isDraft=true
+	PRStateAll    PRState = "ALL"   // Virtual state for queries: matches pull requests in any state
 )
 
 func (s PRState) String() string {
@@ -22,20 +23,119 @@ func (s PRState) String() string {
 
 func (s PRState) IsValid() bool {
 	switch s {
-	case PRStateOpen, PRStateClosed, PRStateMerged, PRStateDraft:
+	case PRStateOpen, PRStateClosed, PRStateMerged, PRStateDraft, PRStateAll:
 		return true
 	}
 	return false
 }
 
+// ParsePRState parses a case-insensitive state name such as "merged" or "all".
+func ParsePRState(s string) (PRState, error) {
+	state := PRState(strings.ToUpper(strings.TrimSpace(s)))
+	if !state.IsValid() {
+		return "", fmt.Errorf("invalid pull request state %q: must be open, draft, merged, closed, or all", s)
+	}
+	return state, nil
+}
+
+// ReviewDecision is the review state GitHub computes for a pull request from its reviews and branch protection.
+type ReviewDecision string
+
+const (
+	ReviewNone             ReviewDecision = ""                  // No review decision (e.g., no required reviews)
+	ReviewApproved         ReviewDecision = "APPROVED"          // Approved by the required reviewers
+	ReviewChangesRequested ReviewDecision = "CHANGES_REQUESTED" // A reviewer requested changes
+	ReviewRequired         ReviewDecision = "REVIEW_REQUIRED"   // Waiting on a required review
+)
+
+// searchQualifier returns the GitHub search qualifier matching the review decision, or "" for ReviewNone.
+func (d ReviewDecision) searchQualifier() string {
+	switch d {
+	case ReviewApproved:
+		return "review:approved"
+	case ReviewChangesRequested:
+		return "review:changes_requested"
+	case ReviewRequired:
+		return "review:required"
+	}
+	return ""
+}
+
+// ChecksStatus summarizes the CI checks and commit statuses reported for a pull request's head commit.
+type ChecksStatus string
+
+const (
+	ChecksNone    ChecksStatus = ""        // No checks reported
+	ChecksFailing ChecksStatus = "FAILING" // At least one check failed
+	ChecksPassing ChecksStatus = "PASSING" // All checks completed successfully
+	ChecksPending ChecksStatus = "PENDING" // No failures, but at least one check has not completed
+)
+
+// checkRollupItem is an entry of gh's statusCheckRollup: either a CheckRun or a StatusContext.
+type checkRollupItem struct {
+	Conclusion string `json:"conclusion"` // CheckRun: SUCCESS, FAILURE, NEUTRAL, SKIPPED, CANCELLED, TIMED_OUT, ...
+	State      string `json:"state"`      // StatusContext: SUCCESS, FAILURE, ERROR, PENDING, EXPECTED
+	Status     string `json:"status"`     // CheckRun: COMPLETED, IN_PROGRESS, QUEUED, ...
+	Type       string `json:"__typename"`
+}
+
+// summarizeChecks reduces a status check rollup to a single status; any failure wins over pending checks.
+func summarizeChecks(items []checkRollupItem) ChecksStatus {
+	if len(items) == 0 {
+		return ChecksNone
+	}
+	pending := false
+	for _, item := range items {
+		switch checkItemStatus(item) {
+		case ChecksFailing:
+			return ChecksFailing
+		case ChecksPending:
+			pending = true
+		}
+	}
+	if pending {
+		return ChecksPending
+	}
+	return ChecksPassing
+}
+
+func checkItemStatus(item checkRollupItem) ChecksStatus {
+	if item.Type == "StatusContext" {
+		switch item.State {
+		case "SUCCESS":
+			return ChecksPassing
+		case "FAILURE", "ERROR":
+			return ChecksFailing
+		default:
+			return ChecksPending
+		}
+	}
+
+	if item.Status != "COMPLETED" {
+		return ChecksPending
+	}
+	switch item.Conclusion {
+	case "SUCCESS", "NEUTRAL", "SKIPPED":
+		return ChecksPassing
+	default:
+		return ChecksFailing
+	}
+}
+
 // PRQuery specifies filters for listing pull requests.
 // TODO: add a ignore-users field, and thread it through from config
 // TODO: add default updated within days from config
 type PRQuery struct {
-	ClosedWithinDays  int     // 0 = no filter, uses closed:>= in search
-	MergedWithinDays  int     // 0 = no filter, uses merged:>= in search
-	State             PRState // Defaults to PRStateOpen if empty
-	UpdatedWithinDays int     // 0 = no filter, uses updated:>= in search
+	Assignee          string         // "" = no filter, "@me" for the current user; uses assignee: in search
+	Author            string         // "" = no filter, "@me" for the current user; uses author: in search
+	ClosedWithinDays  int            // 0 = no filter, uses closed:>= in search
+	Labels            []string       // every label must match; uses label: in search
+	MergedWithinDays  int            // 0 = no filter, uses merged:>= in search
+	Now               time.Time      // the time the date filters count back from; zero uses the current time
+	Qualifiers        []string       // raw search qualifiers appended as-is, e.g. "-author:app/dependabot"
+	Review            ReviewDecision // ReviewNone = no filter, uses review: in search
+	State             PRState        // Defaults to PRStateOpen if empty
+	UpdatedWithinDays int            // 0 = no filter, uses updated:>= in search
 }
 
 // ToSearchQuery converts the query to a GitHub search string for use with `gh pr list --search`.
@@ -45,6 +145,10 @@ func (q PRQuery) ToSearchQuery() string {
 	if state == "" {
 		state = PRStateOpen
 	}
+	now := q.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
 
 	var parts []string
 
@@ -56,79 +160,151 @@ func (q PRQuery) ToSearchQuery() string {
 	case PRStateClosed:
 		parts = append(parts, "is:pr", "is:closed", "is:unmerged")
 		if q.ClosedWithinDays > 0 {
-			cutoff := time.Now().AddDate(0, 0, -q.ClosedWithinDays)
+			cutoff := now.AddDate(0, 0, -q.ClosedWithinDays)
 			parts = append(parts, fmt.Sprintf("closed:>=%s", cutoff.Format("2006-01-02")))
 		}
 	case PRStateMerged:
 		parts = append(parts, "is:pr", "is:merged")
 		if q.MergedWithinDays > 0 {
-			cutoff := time.Now().AddDate(0, 0, -q.MergedWithinDays)
+			cutoff := now.AddDate(0, 0, -q.MergedWithinDays)
 			parts = append(parts, fmt.Sprintf("merged:>=%s", cutoff.Format("2006-01-02")))
 		}
+	case PRStateAll:
+		parts = append(parts, "is:pr")
+	}
+
+	if q.Author != "" {
+		parts = append(parts, "author:"+q.Author)
+	}
+	if q.Assignee != "" {
+		parts = append(parts, "assignee:"+q.Assignee)
+	}
+	for _, label := range q.Labels {
+		parts = append(parts, "label:"+quoteSearchValue(label))
+	}
+
+	if qualifier := q.Review.searchQualifier(); qualifier != "" {
+		parts = append(parts, qualifier)
 	}
 
 	if q.UpdatedWithinDays > 0 {
-		cutoff := time.Now().AddDate(0, 0, -q.UpdatedWithinDays)
+		cutoff := now.AddDate(0, 0, -q.UpdatedWithinDays)
 		parts = append(parts, fmt.Sprintf("updated:>=%s", cutoff.Format("2006-01-02")))
 	}
 
+	parts = append(parts, q.Qualifiers...)
+
 	return strings.Join(parts, " ")
 }
 
+// quoteSearchValue quotes a search qualifier value that contains spaces, e.g. a label like "needs review".
+func quoteSearchValue(value string) string {
+	if strings.ContainsAny(value, " \t") {
+		return `"` + value + `"`
+	}
+	return value
+}
+
 type PullRequest struct {
 	AuthorLogin  string // May be empty if author's account was deleted
 	AuthorName   string // May be empty if author's account was deleted
 	Body         string
 	BranchName   string
+	Checks       ChecksStatus
 	CreatedAt    time.Time
 	FilesChanged int
+	FromFork     bool   // The head branch is in another repository, so it is not on this repository's remote
+	HeadSHA      string // The full SHA of the head branch's commit
 	LinesAdded   int
 	LinesDeleted int
 	Number       int
+	Review       ReviewDecision
 	State        PRState
 	Title        string
 	UpdatedAt    time.Time
 	URL          string
 }
 
-const prJsonFields = "additions,author,body,changedFiles,createdAt,deletions,headRefName,isDraft,number,state,title,updatedAt,url"
+// NewPullRequest describes a pull request to open with CreatePullRequest.
+type NewPullRequest struct {
+	Base  string // The branch to merge into; "" uses the repository's default branch
+	Body  string
+	Draft bool
+	Head  string // The branch with the changes, already pushed to the remote
+	Title string
+}
+
+// args returns the gh arguments that open the pull request.
+func (pr NewPullRequest) args() []string {
+	args := []string{"pr", "create", "--head", pr.Head, "--title", pr.Title, "--body", pr.Body}
+	if pr.Base != "" {
+		args = append(args, "--base", pr.Base)
+	}
+	if pr.Draft {
+		args = append(args, "--draft")
+	}
+	return args
+}
+
+const prJsonFields = "additions,author,body,changedFiles,createdAt,deletions,headRefName,headRefOid,isCrossRepository,isDraft,number,reviewDecision,state,statusCheckRollup,title,updatedAt,url"
+
+// rawPR is a pull request as gh reports it with --json prJsonFields.
+type rawPR struct {
+	Additions    int               `json:"additions"`
+	Body         string            `json:"body"`
+	ChangedFiles int               `json:"changedFiles"`
+	CreatedAt    time.Time         `json:"createdAt"`
+	Deletions    int               `json:"deletions"`
+	HeadRefName  string            `json:"headRefName"`
+	HeadRefOid   string            `json:"headRefOid"`
+	IsCrossRepo  bool              `json:"isCrossRepository"`
+	IsDraft      bool              `json:"isDraft"`
+	Number       int               `json:"number"`
+	Review       string            `json:"reviewDecision"`
+	State        string            `json:"state"`
+	Rollup       []checkRollupItem `json:"statusCheckRollup"`
+	Title        string            `json:"title"`
+	UpdatedAt    time.Time         `json:"updatedAt"`
+	URL          string            `json:"url"`
+	Author       struct {
+		Login string `json:"login"`
+		Name  string `json:"name"`
+	} `json:"author"`
+}
 
 func (pr *PullRequest) UnmarshalJSON(data []byte) error {
-	type rawPR struct {
-		Additions    int       `json:"additions"`
-		Body         string    `json:"body"`
-		ChangedFiles int       `json:"changedFiles"`
-		CreatedAt    time.Time `json:"createdAt"`
-		Deletions    int       `json:"deletions"`
-		HeadRefName  string    `json:"headRefName"`
-		IsDraft      bool      `json:"isDraft"`
-		Number       int       `json:"number"`
-		State        string    `json:"state"`
-		Title        string    `json:"title"`
-		UpdatedAt    time.Time `json:"updatedAt"`
-		URL          string    `json:"url"`
-		Author       struct {
-			Login string `json:"login"`
-			Name  string `json:"name"`
-		} `json:"author"`
-	}
 	var raw rawPR
 	if err := json.Unmarshal(data, &raw); err != nil {
 		return err
 	}
+	parsed, err := raw.pullRequest()
+	if err != nil {
+		return err
+	}
+	*pr = parsed
+	return nil
+}
 
-	pr.AuthorLogin = raw.Author.Login
-	pr.AuthorName = raw.Author.Name
-	pr.Body = raw.Body
-	pr.BranchName = raw.HeadRefName
-	pr.CreatedAt = raw.CreatedAt
-	pr.FilesChanged = raw.ChangedFiles
-	pr.LinesAdded = raw.Additions
-	pr.LinesDeleted = raw.Deletions
-	pr.Number = raw.Number
-	pr.Title = raw.Title
-	pr.UpdatedAt = raw.UpdatedAt
-	pr.URL = raw.URL
+// pullRequest converts the gh representation to a PullRequest.
+func (raw rawPR) pullRequest() (PullRequest, error) {
+	pr := PullRequest{
+		AuthorLogin:  raw.Author.Login,
+		AuthorName:   raw.Author.Name,
+		Body:         raw.Body,
+		BranchName:   raw.HeadRefName,
+		Checks:       summarizeChecks(raw.Rollup),
+		CreatedAt:    raw.CreatedAt,
+		FilesChanged: raw.ChangedFiles,
+		FromFork:     raw.IsCrossRepo,
+		HeadSHA:      raw.HeadRefOid,
+		LinesAdded:   raw.Additions,
+		LinesDeleted: raw.Deletions,
+		Number:       raw.Number,
+		Review:       ReviewDecision(raw.Review),
+		Title:        raw.Title,
+		UpdatedAt:    raw.UpdatedAt,
+		URL:          raw.URL,
+	}
 
 	if raw.IsDraft && raw.State == "OPEN" {
 		pr.State = PRStateDraft
@@ -141,9 +317,9 @@ func (pr *PullRequest) UnmarshalJSON(data []byte) error {
 		case "MERGED":
 			pr.State = PRStateMerged
 		default:
-			return fmt.Errorf("unknown PR state: %s", raw.State)
+			return PullRequest{}, fmt.Errorf("unknown PR state: %s", raw.State)
 		}
 	}
 
-	return nil
+	return pr, nil
 }
@@ -41,6 +41,7 @@ func TestPRState_IsValid(t *testing.T) {
 		{name: "closed is valid", state: PRStateClosed, want: true},
 		{name: "merged is valid", state: PRStateMerged, want: true},
 		{name: "draft is valid", state: PRStateDraft, want: true},
+		{name: "all is valid", state: PRStateAll, want: true},
 		{name: "empty is invalid", state: PRState(""), want: false},
 		{name: "unknown is invalid", state: PRState("UNKNOWN"), want: false},
 		{name: "lowercase open is invalid", state: PRState("open"), want: false},
@@ -53,12 +54,37 @@ func TestPRState_IsValid(t *testing.T) {
 	}
 }
 
-func TestPRQuery_ToSearchQuery(t *testing.T) {
-	// Helper to get date string for N days ago
-	daysAgo := func(n int) string {
-		return time.Now().AddDate(0, 0, -n).Format("2006-01-02")
+func TestParsePRState(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    PRState
+		wantErr string
+	}{
+		{input: "open", want: PRStateOpen},
+		{input: "Merged", want: PRStateMerged},
+		{input: "draft", want: PRStateDraft},
+		{input: "closed", want: PRStateClosed},
+		{input: "all", want: PRStateAll},
+		{input: "", wantErr: `invalid pull request state "": must be open, draft, merged, closed, or all`},
+		{input: "approved", wantErr: `invalid pull request state "approved": must be open, draft, merged, closed, or all`},
 	}
 
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParsePRState(tt.input)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPRQuery_ToSearchQuery(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name           string
 		query          PRQuery
@@ -87,8 +113,8 @@ func TestPRQuery_ToSearchQuery(t *testing.T) {
 		},
 		{
 			name:         "closed state with date filter",
-			query:        PRQuery{State: PRStateClosed, ClosedWithinDays: 7},
-			wantContains: []string{"is:pr", "is:closed", "is:unmerged", "closed:>=" + daysAgo(7)},
+			query:        PRQuery{Now: now, State: PRStateClosed, ClosedWithinDays: 7},
+			wantContains: []string{"is:pr", "is:closed", "is:unmerged", "closed:>=2024-06-08"},
 		},
 		{
 			name:         "merged state without date filter",
@@ -97,13 +123,44 @@ func TestPRQuery_ToSearchQuery(t *testing.T) {
 		},
 		{
 			name:         "merged state with date filter",
-			query:        PRQuery{State: PRStateMerged, MergedWithinDays: 30},
-			wantContains: []string{"is:pr", "is:merged", "merged:>=" + daysAgo(30)},
+			query:        PRQuery{MergedWithinDays: 30, Now: now, State: PRStateMerged},
+			wantContains: []string{"is:pr", "is:merged", "merged:>=2024-05-16"},
 		},
 		{
 			name:         "open state with updated filter",
-			query:        PRQuery{State: PRStateOpen, UpdatedWithinDays: 14},
-			wantContains: []string{"is:pr", "is:open", "draft:false", "updated:>=" + daysAgo(14)},
+			query:        PRQuery{Now: now, State: PRStateOpen, UpdatedWithinDays: 14},
+			wantContains: []string{"is:pr", "is:open", "draft:false", "updated:>=2024-06-01"},
+		},
+		{
+			name:           "all states",
+			query:          PRQuery{Now: now, State: PRStateAll, UpdatedWithinDays: 7},
+			wantContains:   []string{"is:pr", "updated:>=2024-06-08"},
+			wantNotContain: []string{"is:open", "is:closed", "is:merged", "draft:"},
+		},
+		{
+			name:         "date filters count back from the current time by default",
+			query:        PRQuery{UpdatedWithinDays: 7},
+			wantContains: []string{"updated:>=" + time.Now().AddDate(0, 0, -7).Format("2006-01-02")},
+		},
+		{
+			name: "people, labels, and qualifiers",
+			query: PRQuery{
+				Assignee:   "@me",
+				Author:     "octocat",
+				Labels:     []string{"bug", "needs review"},
+				Qualifiers: []string{"-author:app/dependabot"},
+			},
+			wantContains: []string{"author:octocat", "assignee:@me", "label:bug", `label:"needs review"`, "-author:app/dependabot"},
+		},
+		{
+			name:         "review filter",
+			query:        PRQuery{Review: ReviewChangesRequested},
+			wantContains: []string{"is:open", "review:changes_requested"},
+		},
+		{
+			name:           "no review filter",
+			query:          PRQuery{State: PRStateOpen},
+			wantNotContain: []string{"review:"},
 		},
 		{
 			name:           "closed date filter ignored for open state",
@@ -177,6 +234,11 @@ func TestPullRequest_UnmarshalJSON(t *testing.T) {
 				URL:          "https://github.com/owner/repo/pull/123",
 			},
 		},
+		{
+			name:  "PR from a fork",
+			input: `{"headRefName": "main", "headRefOid": "9e1b7c2", "isCrossRepository": true, "number": 9, "state": "OPEN"}`,
+			want:  PullRequest{BranchName: "main", FromFork: true, HeadSHA: "9e1b7c2", Number: 9, State: PRStateOpen},
+		},
 		{
 			name: "draft PR becomes PRStateDraft",
 			input: `{
@@ -303,6 +365,37 @@ func TestPullRequest_UnmarshalJSON(t *testing.T) {
 				URL:          "https://github.com/owner/repo/pull/999",
 			},
 		},
+		{
+			name: "status check rollup",
+			input: `{
+				"author": {"login": "dev"},
+				"createdAt": "2024-01-01T00:00:00Z",
+				"headRefName": "ci-branch",
+				"isDraft": false,
+				"number": 5,
+				"reviewDecision": "APPROVED",
+				"state": "OPEN",
+				"statusCheckRollup": [
+					{"__typename": "CheckRun", "name": "build", "status": "COMPLETED", "conclusion": "SUCCESS"},
+					{"__typename": "StatusContext", "context": "ci/lint", "state": "PENDING"}
+				],
+				"title": "CI PR",
+				"updatedAt": "2024-01-01T00:00:00Z",
+				"url": "https://github.com/owner/repo/pull/5"
+			}`,
+			want: PullRequest{
+				AuthorLogin: "dev",
+				BranchName:  "ci-branch",
+				Checks:      ChecksPending,
+				CreatedAt:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				Number:      5,
+				Review:      ReviewApproved,
+				State:       PRStateOpen,
+				Title:       "CI PR",
+				UpdatedAt:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				URL:         "https://github.com/owner/repo/pull/5",
+			},
+		},
 		{
 			name: "null author",
 			input: `{
@@ -377,3 +470,57 @@ func TestPullRequest_UnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestNewPullRequest_Args(t *testing.T) {
+	tests := []struct {
+		name string
+		pr   NewPullRequest
+		want []string
+	}{
+		{
+			name: "default base",
+			pr:   NewPullRequest{Head: "feature/auth", Title: "Add auth"},
+			want: []string{"pr", "create", "--head", "feature/auth", "--title", "Add auth", "--body", ""},
+		},
+		{
+			name: "draft against a base",
+			pr:   NewPullRequest{Base: "release", Body: "Details", Draft: true, Head: "fix", Title: "Fix"},
+			want: []string{"pr", "create", "--head", "fix", "--title", "Fix", "--body", "Details", "--base", "release", "--draft"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.pr.args())
+		})
+	}
+}
+
+func TestSummarizeChecks(t *testing.T) {
+	success := checkRollupItem{Conclusion: "SUCCESS", Status: "COMPLETED", Type: "CheckRun"}
+	skipped := checkRollupItem{Conclusion: "SKIPPED", Status: "COMPLETED", Type: "CheckRun"}
+	failure := checkRollupItem{Conclusion: "FAILURE", Status: "COMPLETED", Type: "CheckRun"}
+	running := checkRollupItem{Status: "IN_PROGRESS", Type: "CheckRun"}
+	statusOK := checkRollupItem{State: "SUCCESS", Type: "StatusContext"}
+	statusError := checkRollupItem{State: "ERROR", Type: "StatusContext"}
+	statusPending := checkRollupItem{State: "PENDING", Type: "StatusContext"}
+
+	tests := []struct {
+		name  string
+		items []checkRollupItem
+		want  ChecksStatus
+	}{
+		{name: "no checks", want: ChecksNone},
+		{name: "all passing", items: []checkRollupItem{success, skipped, statusOK}, want: ChecksPassing},
+		{name: "check run in progress", items: []checkRollupItem{success, running}, want: ChecksPending},
+		{name: "status context pending", items: []checkRollupItem{statusPending}, want: ChecksPending},
+		{name: "failure wins over pending", items: []checkRollupItem{running, failure}, want: ChecksFailing},
+		{name: "status context error", items: []checkRollupItem{success, statusError}, want: ChecksFailing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, summarizeChecks(tt.items))
+		})
+	}
+}
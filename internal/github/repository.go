@@ -0,0 +1,62 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Repository identifies a GitHub repository.
+type Repository struct {
+	Host  string // e.g. "github.com", or a GitHub Enterprise host
+	Name  string
+	Owner string
+}
+
+// FullName returns the repository as "owner/name".
+func (r Repository) FullName() string {
+	return r.Owner + "/" + r.Name
+}
+
+// PullRequestsURL returns the repository's pull request list page on the web, filtered by a search query
+// such as PRQuery.ToSearchQuery returns.
+func (r Repository) PullRequestsURL(searchQuery string) string {
+	u := url.URL{Scheme: "https", Host: r.Host, Path: "/" + r.FullName() + "/pulls"}
+	if searchQuery != "" {
+		u.RawQuery = url.Values{"q": {searchQuery}}.Encode()
+	}
+	return u.String()
+}
+
+// Equal reports whether r and other are the same repository; GitHub names are case-insensitive.
+func (r Repository) Equal(other Repository) bool {
+	return strings.EqualFold(r.Host, other.Host) &&
+		strings.EqualFold(r.Owner, other.Owner) &&
+		strings.EqualFold(r.Name, other.Name)
+}
+
+// IsPullRequestURL reports whether s looks like a URL rather than a number or branch name.
+func IsPullRequestURL(s string) bool {
+	return strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "http://")
+}
+
+// ParsePullRequestURL parses a pull request URL such as https://github.com/org/repo/pull/123
+// (optionally followed by /files, /commits, a query, or a fragment) into its repository and number.
+func ParsePullRequestURL(rawURL string) (Repository, int, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return Repository{}, 0, fmt.Errorf("invalid pull request URL %q", rawURL)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 4 || parts[2] != "pull" {
+		return Repository{}, 0, fmt.Errorf("invalid pull request URL %q; expected https://<host>/<owner>/<repo>/pull/<number>", rawURL)
+	}
+	num, err := strconv.Atoi(parts[3])
+	if err != nil || num <= 0 {
+		return Repository{}, 0, fmt.Errorf("invalid pull request number in URL %q", rawURL)
+	}
+
+	return Repository{Host: u.Host, Name: parts[1], Owner: parts[0]}, num, nil
+}
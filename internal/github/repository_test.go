@@ -0,0 +1,86 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePullRequestURL(t *testing.T) {
+	repo := Repository{Host: "github.com", Name: "repo", Owner: "org"}
+
+	tests := []struct {
+		name     string
+		url      string
+		wantRepo Repository
+		wantNum  int
+		wantErr  string
+	}{
+		{name: "plain", url: "https://github.com/org/repo/pull/123", wantRepo: repo, wantNum: 123},
+		{name: "files tab", url: "https://github.com/org/repo/pull/123/files", wantRepo: repo, wantNum: 123},
+		{name: "fragment and trailing slash", url: "https://github.com/org/repo/pull/123/#discussion_r1", wantRepo: repo, wantNum: 123},
+		{name: "query", url: "https://github.com/org/repo/pull/7?notification_referrer_id=x", wantRepo: repo, wantNum: 7},
+		{
+			name:     "enterprise host",
+			url:      "https://git.example.com/team/svc/pull/9",
+			wantRepo: Repository{Host: "git.example.com", Name: "svc", Owner: "team"},
+			wantNum:  9,
+		},
+		{name: "issue URL", url: "https://github.com/org/repo/issues/123", wantErr: "expected https://<host>/<owner>/<repo>/pull/<number>"},
+		{name: "repo URL", url: "https://github.com/org/repo", wantErr: "expected https://"},
+		{name: "non-numeric", url: "https://github.com/org/repo/pull/abc", wantErr: "invalid pull request number"},
+		{name: "no host", url: "https:///org/repo/pull/1", wantErr: "invalid pull request URL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRepo, gotNum, err := ParsePullRequestURL(tt.url)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRepo, gotRepo)
+			assert.Equal(t, tt.wantNum, gotNum)
+		})
+	}
+}
+
+func TestRepository_PullRequestsURL(t *testing.T) {
+	repo := Repository{Host: "github.com", Name: "repo", Owner: "org"}
+
+	assert.Equal(t, "https://github.com/org/repo/pulls", repo.PullRequestsURL(""))
+	assert.Equal(t,
+		"https://github.com/org/repo/pulls?q=is%3Apr+is%3Aopen+label%3A%22needs+review%22+author%3A%40me",
+		repo.PullRequestsURL(`is:pr is:open label:"needs review" author:@me`))
+}
+
+func TestRepository_Equal(t *testing.T) {
+	repo := Repository{Host: "github.com", Name: "grove-cli", Owner: "jmcampanini"}
+
+	tests := []struct {
+		name  string
+		other Repository
+		want  bool
+	}{
+		{name: "same", other: repo, want: true},
+		{name: "different case", other: Repository{Host: "GitHub.com", Name: "Grove-CLI", Owner: "JMCampanini"}, want: true},
+		{name: "fork", other: Repository{Host: "github.com", Name: "grove-cli", Owner: "someone"}, want: false},
+		{name: "other host", other: Repository{Host: "git.example.com", Name: "grove-cli", Owner: "jmcampanini"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, repo.Equal(tt.other))
+		})
+	}
+}
+
+func TestIsPullRequestURL(t *testing.T) {
+	assert.True(t, IsPullRequestURL("https://github.com/org/repo/pull/1"))
+	assert.True(t, IsPullRequestURL("http://ghe.local/org/repo/pull/1"))
+	assert.False(t, IsPullRequestURL("123"))
+	assert.False(t, IsPullRequestURL("feature/login"))
+}
@@ -0,0 +1,50 @@
+// Package jsonout writes grove's --json output.
+//
+// Every JSON document is an envelope: the command's result under "data", the non-fatal issues met while
+// producing it under "warnings" (always an array, empty when there were none), and "meta" with the format
+// version and when the document was generated. Within a version, fields are only ever added; removing or
+// changing the meaning of a field bumps Version.
+package jsonout
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// Version is the envelope format version written in meta.version.
+const Version = 1
+
+// Envelope is the top-level JSON document.
+type Envelope struct {
+	Data     any       `json:"data"`
+	Meta     Meta      `json:"meta"`
+	Warnings []Warning `json:"warnings"`
+}
+
+// Meta describes the document itself.
+type Meta struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Version     int       `json:"version"`
+}
+
+// Warning is a non-fatal issue, such as a config file with unknown keys or a repository that was skipped.
+type Warning struct {
+	Code    string `json:"code"`              // stable, machine-readable kind, e.g. "unknown-config-keys"
+	Message string `json:"message"`           // human-readable detail
+	Subject string `json:"subject,omitempty"` // what the warning is about, e.g. a file or worktree path
+}
+
+// Write writes data and warnings to w as an indented envelope generated at now.
+func Write(w io.Writer, data any, warnings []Warning, now time.Time) error {
+	if warnings == nil {
+		warnings = []Warning{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(Envelope{
+		Data:     data,
+		Meta:     Meta{GeneratedAt: now.UTC(), Version: Version},
+		Warnings: warnings,
+	})
+}
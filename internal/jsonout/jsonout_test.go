@@ -0,0 +1,73 @@
+package jsonout
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	now := time.Date(2024, 6, 1, 14, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	tests := []struct {
+		name     string
+		data     any
+		warnings []Warning
+		want     string
+	}{
+		{
+			name: "no warnings",
+			data: []string{"a"},
+			want: `{
+  "data": [
+    "a"
+  ],
+  "meta": {
+    "generated_at": "2024-06-01T12:00:00Z",
+    "version": 1
+  },
+  "warnings": []
+}
+`,
+		},
+		{
+			name:     "warnings",
+			data:     map[string]int{"count": 0},
+			warnings: []Warning{{Code: "unknown-config-keys", Message: "unknown config keys: ui.colour", Subject: "/home/user/grove.toml"}, {Code: "skipped", Message: "no subject"}},
+			want: `{
+  "data": {
+    "count": 0
+  },
+  "meta": {
+    "generated_at": "2024-06-01T12:00:00Z",
+    "version": 1
+  },
+  "warnings": [
+    {
+      "code": "unknown-config-keys",
+      "message": "unknown config keys: ui.colour",
+      "subject": "/home/user/grove.toml"
+    },
+    {
+      "code": "skipped",
+      "message": "no subject"
+    }
+  ]
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			require.NoError(t, Write(&out, tt.data, tt.warnings, now))
+
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
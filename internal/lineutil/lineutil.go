@@ -0,0 +1,57 @@
+// Package lineutil turns streamed command output into lines, so callers can show it as it arrives.
+package lineutil
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+)
+
+// Writer is an io.Writer that passes each complete line written to it to a callback, without the newline.
+// Text before a carriage return in a line is dropped, since terminals draw it over: git and gh redraw
+// progress such as "Receiving objects: 40%" that way, and only the final state is kept.
+// Blank lines are skipped.
+type Writer struct {
+	fn      func(line string)
+	mu      sync.Mutex
+	pending bytes.Buffer
+}
+
+// NewWriter returns a Writer that calls fn for each line.
+func NewWriter(fn func(line string)) *Writer {
+	return &Writer{fn: fn}
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending.Write(p)
+	for {
+		line, err := w.pending.ReadString('\n')
+		if err != nil {
+			// keep the partial line for the next write
+			w.pending.Reset()
+			w.pending.WriteString(line)
+			return len(p), nil
+		}
+		w.emit(line)
+	}
+}
+
+// Flush passes on the last line if it did not end in a newline.
+func (w *Writer) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.emit(w.pending.String())
+	w.pending.Reset()
+}
+
+func (w *Writer) emit(line string) {
+	line = strings.TrimRight(line, "\r\n")
+	if i := strings.LastIndexByte(line, '\r'); i >= 0 {
+		line = line[i+1:]
+	}
+	if strings.TrimSpace(line) != "" {
+		w.fn(line)
+	}
+}
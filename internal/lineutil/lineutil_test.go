@@ -0,0 +1,57 @@
+package lineutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes []string
+		want   []string
+	}{
+		{
+			name:   "complete lines",
+			writes: []string{"From origin\n * [new branch] main -> origin/main\n"},
+			want:   []string{"From origin", " * [new branch] main -> origin/main"},
+		},
+		{
+			name:   "lines split across writes",
+			writes: []string{"Fro", "m origin\nUnpa", "cking\n"},
+			want:   []string{"From origin", "Unpacking"},
+		},
+		{
+			name:   "progress redrawn with carriage returns",
+			writes: []string{"Receiving objects:  50% (1/2)\rReceiving objects: 100% (2/2)\r", "Receiving objects: 100% (2/2), done.\n"},
+			want:   []string{"Receiving objects: 100% (2/2), done."},
+		},
+		{
+			name:   "blank lines are skipped",
+			writes: []string{"\n  \r\nend\n"},
+			want:   []string{"end"},
+		},
+		{
+			name:   "unterminated last line is flushed",
+			writes: []string{"one\ntwo"},
+			want:   []string{"one", "two"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			w := NewWriter(func(line string) { got = append(got, line) })
+
+			for _, s := range tt.writes {
+				n, err := w.Write([]byte(s))
+				assert.NoError(t, err)
+				assert.Equal(t, len(s), n)
+			}
+			w.Flush()
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+// Package lock provides an exclusive lock between grove processes, held as a file that exists only while
+// the lock is taken, like git's index.lock.
+package lock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrHeld is returned by Acquire when another process still holds the lock once the timeout passes.
+var ErrHeld = errors.New("another grove operation is in progress")
+
+// pollInterval is how often Acquire retries while the lock is held.
+const pollInterval = 50 * time.Millisecond
+
+// Lock is a held lock; release it with Release.
+type Lock struct {
+	path string
+}
+
+// Acquire creates the lock file at path, creating its directory if needed, and waits up to timeout
+// while another process holds it. The file records the holder's process ID for the error message.
+// A lock left behind by a crashed process is never taken over; the error names the file to remove.
+func Acquire(path string, timeout time.Duration) (*Lock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			_, writeErr := fmt.Fprintln(f, os.Getpid())
+			if closeErr := f.Close(); writeErr == nil {
+				writeErr = closeErr
+			}
+			if writeErr != nil {
+				_ = os.Remove(path)
+				return nil, fmt.Errorf("failed to write lock file: %w", writeErr)
+			}
+			return &Lock{path: path}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to create lock file: %w", err)
+		}
+		if !time.Now().Before(deadline) {
+			return nil, heldError(path)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// Release removes the lock file. Releasing a lock twice is a no-op.
+func (l *Lock) Release() error {
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to release lock: %w", err)
+	}
+	return nil
+}
+
+// heldError describes who holds the lock at path, wrapping ErrHeld.
+func heldError(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("%w; if no grove is running, remove %s", ErrHeld, path)
+	}
+	holder := strings.TrimSpace(string(data))
+	if _, err := strconv.Atoi(holder); err != nil {
+		return fmt.Errorf("%w; if no grove is running, remove %s", ErrHeld, path)
+	}
+	return fmt.Errorf("%w (process %s); if it is no longer running, remove %s", ErrHeld, holder, path)
+}
@@ -0,0 +1,81 @@
+package lock
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquire(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "grove", "worktrees.lock")
+
+	l, err := Acquire(path, time.Second)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(os.Getpid())+"\n", string(data))
+
+	require.NoError(t, l.Release())
+	assert.NoFileExists(t, path)
+	require.NoError(t, l.Release())
+}
+
+func TestAcquire_Held(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "worktrees.lock")
+	require.NoError(t, os.WriteFile(path, []byte("4242\n"), 0o644))
+
+	_, err := Acquire(path, 0)
+
+	require.ErrorIs(t, err, ErrHeld)
+	assert.Equal(t, "another grove operation is in progress (process 4242); if it is no longer running, remove "+path, err.Error())
+}
+
+func TestAcquire_WaitsForRelease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "worktrees.lock")
+	held, err := Acquire(path, 0)
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(2 * pollInterval)
+		_ = held.Release()
+	}()
+	l, err := Acquire(path, 10*time.Second)
+
+	require.NoError(t, err)
+	require.NoError(t, l.Release())
+}
+
+func TestAcquire_Exclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "worktrees.lock")
+	var wg sync.WaitGroup
+	inside, maxInside := 0, 0
+	var mu sync.Mutex
+
+	for range 5 {
+		wg.Go(func() {
+			l, err := Acquire(path, 10*time.Second)
+			if !assert.NoError(t, err) {
+				return
+			}
+			mu.Lock()
+			inside++
+			maxInside = max(maxInside, inside)
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			inside--
+			mu.Unlock()
+			assert.NoError(t, l.Release())
+		})
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, maxInside)
+}
@@ -1,33 +1,93 @@
 package naming
 
-import "github.com/jmcampanini/grove-cli/internal/config"
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/jmcampanini/grove-cli/internal/config"
+)
+
+// BranchTemplateData holds the fields available to the [branch] template.
+type BranchTemplateData struct {
+	Date string // The current date, e.g. "2024-06-01"
+	Slug string // The phrase, slugified with the [slugify] rules
+	User string // The git user.name as a slug, e.g. "jane-doe"; a template using it needs user.name set
+}
 
 // BranchNameGenerator creates branch names from user input.
 type BranchNameGenerator struct {
 	prefix      string
 	slugifyOpts SlugifyOptions
+	tmpl        *template.Template // nil names branches with the prefix and slug
+	usesUser    bool               // whether the template's output depends on .User
+}
+
+// NewBranchNameGenerator creates a generator from config. A [branch] template is parsed and validated
+// against sample data, so that unknown fields are reported up front instead of when a branch is created.
+func NewBranchNameGenerator(branchCfg config.BranchConfig, slugCfg config.SlugifyConfig) (*BranchNameGenerator, error) {
+	g := &BranchNameGenerator{
+		prefix:      branchCfg.NewPrefix,
+		slugifyOpts: slugifyOptions(slugCfg),
+	}
+	if strings.TrimSpace(branchCfg.Template) == "" {
+		return g, nil
+	}
+
+	tmpl, err := template.New("branch.template").Option("missingkey=error").Funcs(templateFuncs(g.slugifyOpts)).Parse(branchCfg.Template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid branch.template: %w", err)
+	}
+	sample := BranchTemplateData{Date: "2024-01-01", Slug: "sample-phrase", User: "sample-user"}
+	name, err := renderBranchTemplate(tmpl, sample)
+	if err != nil {
+		return nil, fmt.Errorf("invalid branch.template: %w", err)
+	}
+	sample.User = ""
+	noUser, err := renderBranchTemplate(tmpl, sample)
+	g.tmpl = tmpl
+	g.usesUser = err != nil || noUser != name
+	return g, nil
 }
 
-// NewBranchNameGenerator creates a generator from config.
-func NewBranchNameGenerator(branchCfg config.BranchConfig, slugCfg config.SlugifyConfig) *BranchNameGenerator {
-	return &BranchNameGenerator{
-		prefix: branchCfg.NewPrefix,
-		slugifyOpts: SlugifyOptions{
-			CollapseDashes:     slugCfg.CollapseDashes,
-			HashLength:         slugCfg.HashLength,
-			Lowercase:          slugCfg.Lowercase,
-			MaxLength:          slugCfg.MaxLength,
-			ReplaceNonAlphaNum: slugCfg.ReplaceNonAlphanum,
-			TrimDashes:         slugCfg.TrimDashes,
-		},
+func renderBranchTemplate(tmpl *template.Template, data BranchTemplateData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
 	}
+	result := strings.TrimSpace(buf.String())
+	if result == "" {
+		return "", fmt.Errorf("%s rendered an empty name", tmpl.Name())
+	}
+	return result, nil
+}
+
+// UsesUser reports whether branch names come from a [branch] template that uses the git user name.
+func (g *BranchNameGenerator) UsesUser() bool {
+	return g.usesUser
 }
 
-// Generate creates a branch name from a phrase.
-func (g *BranchNameGenerator) Generate(phrase string) string {
+// Generate creates a branch name from a phrase, or "" when the phrase has nothing to slugify.
+// The user name and date are only used by a [branch] template. It fails when the template uses .User
+// and userName has nothing to slugify, or when the template cannot be rendered for the phrase.
+func (g *BranchNameGenerator) Generate(phrase, userName string, now time.Time) (string, error) {
 	slug := Slugify(phrase, g.slugifyOpts)
 	if slug == "" {
-		return ""
+		return "", nil
+	}
+	if g.tmpl == nil {
+		return g.prefix + slug, nil
+	}
+	data := BranchTemplateData{Date: now.Format(time.DateOnly), Slug: slug, User: slugText(userName, g.slugifyOpts)}
+	if g.usesUser && data.User == "" {
+		return "", errors.New(`branch.template uses .User, but git user.name is not set; set it with git config user.name "Your Name"`)
+	}
+	name, err := renderBranchTemplate(g.tmpl, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to render branch.template: %w", err)
 	}
-	return g.prefix + slug
+	return name, nil
 }
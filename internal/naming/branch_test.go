@@ -2,9 +2,11 @@ package naming
 
 import (
 	"testing"
+	"time"
 
 	"github.com/jmcampanini/grove-cli/internal/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBranchNameGenerator_Generate(t *testing.T) {
@@ -13,7 +15,9 @@ func TestBranchNameGenerator_Generate(t *testing.T) {
 		branchCfg  config.BranchConfig
 		slugifyCfg config.SlugifyConfig
 		phrase     string
+		userName   string
 		want       string
+		wantErr    string
 	}{
 		{
 			name:       "simple phrase with feature prefix",
@@ -99,17 +103,120 @@ func TestBranchNameGenerator_Generate(t *testing.T) {
 			phrase:     "my-feature-name",
 			want:       "feature/my-feature-name",
 		},
+		{
+			name:       "underscore separator",
+			branchCfg:  config.BranchConfig{NewPrefix: "feature/"},
+			slugifyCfg: underscoreSlugifyConfig(),
+			phrase:     "add user-auth",
+			want:       "feature/add_user_auth",
+		},
+		{
+			name:       "reserved name",
+			branchCfg:  config.BranchConfig{NewPrefix: ""},
+			slugifyCfg: underscoreSlugifyConfig(),
+			phrase:     "Release",
+			want:       "release_" + computeHash("Release", 4),
+		},
+		{
+			name:       "template with user and date",
+			branchCfg:  config.BranchConfig{NewPrefix: "feature/", Template: "{{.User}}/{{.Date}}/{{.Slug}}"},
+			slugifyCfg: defaultSlugifyConfig(),
+			phrase:     "Add user auth",
+			userName:   "Jane Doe",
+			want:       "jane-doe/2024-06-01/add-user-auth",
+		},
+		{
+			name:       "template with template functions",
+			branchCfg:  config.BranchConfig{Template: "{{.User | trunc 4}}/{{.Slug}}"},
+			slugifyCfg: defaultSlugifyConfig(),
+			phrase:     "fix login",
+			userName:   "jmcampanini",
+			want:       "jmca/fix-login",
+		},
+		{
+			name:       "template with underscore separator",
+			branchCfg:  config.BranchConfig{Template: "{{.User}}/{{slug .Slug | trunc 7}}"},
+			slugifyCfg: underscoreSlugifyConfig(),
+			phrase:     "fix the login",
+			userName:   "Jane Doe",
+			want:       "jane_doe/fix_the",
+		},
+		{
+			name:       "template with nothing to slugify returns empty",
+			branchCfg:  config.BranchConfig{Template: "{{.User}}/{{.Slug}}"},
+			slugifyCfg: defaultSlugifyConfig(),
+			phrase:     "!!!",
+			userName:   "jane",
+			want:       "",
+		},
+		{
+			name:       "template without user needs no user name",
+			branchCfg:  config.BranchConfig{Template: "{{.Date}}/{{.Slug}}"},
+			slugifyCfg: defaultSlugifyConfig(),
+			phrase:     "fix login",
+			want:       "2024-06-01/fix-login",
+		},
+		{
+			name:       "template with user needs a user name",
+			branchCfg:  config.BranchConfig{Template: "{{.User}}/{{.Slug}}"},
+			slugifyCfg: defaultSlugifyConfig(),
+			phrase:     "fix login",
+			wantErr:    "branch.template uses .User, but git user.name is not set",
+		},
+		{
+			name:       "user name with nothing to slugify",
+			branchCfg:  config.BranchConfig{Template: "{{.User}}/{{.Slug}}"},
+			slugifyCfg: defaultSlugifyConfig(),
+			phrase:     "fix login",
+			userName:   "!!!",
+			wantErr:    "git user.name is not set",
+		},
+		{
+			name:       "render error is reported",
+			branchCfg:  config.BranchConfig{Template: "{{slice .Slug 0 12}}"},
+			slugifyCfg: defaultSlugifyConfig(),
+			phrase:     "fix",
+			wantErr:    "failed to render branch.template",
+		},
 	}
 
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gen := NewBranchNameGenerator(tt.branchCfg, tt.slugifyCfg)
-			got := gen.Generate(tt.phrase)
+			gen, err := NewBranchNameGenerator(tt.branchCfg, tt.slugifyCfg)
+			require.NoError(t, err)
+			got, err := gen.Generate(tt.phrase, tt.userName, now)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
 			assert.Equal(t, tt.want, got)
 		})
 	}
 }
 
+func TestNewBranchNameGenerator_InvalidTemplates(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  string
+	}{
+		{name: "syntax error", template: "{{.Slug", wantErr: "invalid branch.template"},
+		{name: "unknown field", template: "{{.Author}}/{{.Slug}}", wantErr: "invalid branch.template"},
+		{name: "renders empty", template: "{{if false}}x{{end}}", wantErr: "rendered an empty name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewBranchNameGenerator(config.BranchConfig{Template: tt.template}, defaultSlugifyConfig())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
 func TestNewBranchNameGenerator(t *testing.T) {
 	branchCfg := config.BranchConfig{NewPrefix: "test/"}
 	slugCfg := config.SlugifyConfig{
@@ -121,8 +228,10 @@ func TestNewBranchNameGenerator(t *testing.T) {
 		TrimDashes:         false,
 	}
 
-	gen := NewBranchNameGenerator(branchCfg, slugCfg)
+	gen, err := NewBranchNameGenerator(branchCfg, slugCfg)
 
+	require.NoError(t, err)
+	assert.False(t, gen.UsesUser())
 	assert.Equal(t, "test/", gen.prefix)
 	assert.Equal(t, 6, gen.slugifyOpts.HashLength)
 	assert.False(t, gen.slugifyOpts.Lowercase)
@@ -137,6 +246,14 @@ func defaultSlugifyConfig() config.SlugifyConfig {
 		Lowercase:          true,
 		MaxLength:          50,
 		ReplaceNonAlphanum: true,
+		Separator:          "-",
 		TrimDashes:         true,
 	}
 }
+
+func underscoreSlugifyConfig() config.SlugifyConfig {
+	cfg := defaultSlugifyConfig()
+	cfg.Reserved = []string{"prod", "release"}
+	cfg.Separator = "_"
+	return cfg
+}
@@ -0,0 +1,113 @@
+package naming
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/jmcampanini/grove-cli/internal/config"
+)
+
+// PRTemplateData holds the fields available to pull request branch and worktree templates.
+type PRTemplateData struct {
+	AuthorLogin string // May be empty if the author's account was deleted
+	BranchName  string // Head branch name of the pull request
+	Number      int
+	Title       string
+}
+
+// templateFuncs returns the functions available to branch, worktree, and pull request templates,
+// e.g. "pr/{{.Number}}-{{slug .Title}}". slug and trunc follow the [slugify] separator and reserved names.
+func templateFuncs(opts SlugifyOptions) template.FuncMap {
+	sep := opts.Separator
+	if sep == "" {
+		sep = defaultSeparator
+	}
+	return template.FuncMap{
+		"lower": strings.ToLower,
+		"slug":  func(s string) string { return slugText(s, opts) },
+		"trunc": func(n int, s string) string { return trunc(n, s, sep) },
+	}
+}
+
+// slugText turns text such as a pull request title into lowercase words joined by the separator in opts.
+// Unlike a slugified phrase, it is not truncated; templates use trunc for that.
+func slugText(s string, opts SlugifyOptions) string {
+	return Slugify(s, SlugifyOptions{
+		CollapseDashes:     true,
+		HashLength:         opts.HashLength,
+		Lowercase:          true,
+		Reserved:           opts.Reserved,
+		ReplaceNonAlphaNum: true,
+		Separator:          opts.Separator,
+		TrimDashes:         true,
+	})
+}
+
+// trunc keeps the first n characters of s, dropping separators left at the end, e.g. {{slug .Title | trunc 30}}.
+func trunc(n int, s, sep string) string {
+	runes := []rune(s)
+	if n < 0 || len(runes) <= n {
+		return s
+	}
+	return strings.TrimRight(string(runes[:n]), sep)
+}
+
+// PRWorktreeNamer renders branch and worktree names for pull requests from templates.
+type PRWorktreeNamer struct {
+	branchTmpl   *template.Template
+	worktreeTmpl *template.Template
+}
+
+// NewPRWorktreeNamer parses the configured templates and validates them against sample data,
+// so that unknown fields are reported up front instead of when a PR is checked out.
+func NewPRWorktreeNamer(prCfg config.PRConfig, slugCfg config.SlugifyConfig) (*PRWorktreeNamer, error) {
+	funcs := templateFuncs(slugifyOptions(slugCfg))
+	branchTmpl, err := parsePRTemplate("pr.branch_template", prCfg.BranchTemplate, funcs)
+	if err != nil {
+		return nil, err
+	}
+	worktreeTmpl, err := parsePRTemplate("pr.worktree_template", prCfg.WorktreeTemplate, funcs)
+	if err != nil {
+		return nil, err
+	}
+	return &PRWorktreeNamer{
+		branchTmpl:   branchTmpl,
+		worktreeTmpl: worktreeTmpl,
+	}, nil
+}
+
+func parsePRTemplate(name, text string, funcs template.FuncMap) (*template.Template, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	sample := PRTemplateData{AuthorLogin: "octocat", BranchName: "sample-branch", Number: 1, Title: "Sample title"}
+	if _, err := renderPRTemplate(tmpl, sample); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+func renderPRTemplate(tmpl *template.Template, data PRTemplateData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	result := strings.TrimSpace(buf.String())
+	if result == "" {
+		return "", fmt.Errorf("%s rendered an empty name", tmpl.Name())
+	}
+	return result, nil
+}
+
+// BranchName renders the local branch name for a pull request.
+func (n *PRWorktreeNamer) BranchName(data PRTemplateData) (string, error) {
+	return renderPRTemplate(n.branchTmpl, data)
+}
+
+// WorktreeName renders the worktree directory name for a pull request.
+func (n *PRWorktreeNamer) WorktreeName(data PRTemplateData) (string, error) {
+	return renderPRTemplate(n.worktreeTmpl, data)
+}
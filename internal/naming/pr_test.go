@@ -0,0 +1,132 @@
+package naming
+
+import (
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPRWorktreeNamer(t *testing.T) {
+	tests := []struct {
+		name         string
+		prCfg        config.PRConfig
+		slugCfg      config.SlugifyConfig
+		data         PRTemplateData
+		wantBranch   string
+		wantWorktree string
+	}{
+		{
+			name:         "default templates",
+			prCfg:        config.DefaultConfig().PR,
+			data:         PRTemplateData{BranchName: "feature/login", Number: 42},
+			wantBranch:   "feature/login",
+			wantWorktree: "pr-42",
+		},
+		{
+			name: "custom templates",
+			prCfg: config.PRConfig{
+				BranchTemplate:   "pr/{{.Number}}/{{.BranchName}}",
+				WorktreeTemplate: "review-{{.Number}}",
+			},
+			data:         PRTemplateData{BranchName: "fix-bug", Number: 7},
+			wantBranch:   "pr/7/fix-bug",
+			wantWorktree: "review-7",
+		},
+		{
+			name: "title and author",
+			prCfg: config.PRConfig{
+				BranchTemplate:   "pr/{{.Number}}-{{slug .Title}}",
+				WorktreeTemplate: "{{lower .AuthorLogin}}-{{slug .Title | trunc 14}}",
+			},
+			data:         PRTemplateData{AuthorLogin: "Octocat", BranchName: "fix", Number: 7, Title: "Fix: the login bug!"},
+			wantBranch:   "pr/7-fix-the-login-bug",
+			wantWorktree: "octocat-fix-the-login",
+		},
+		{
+			name: "underscore separator",
+			prCfg: config.PRConfig{
+				BranchTemplate:   "pr/{{slug .Title}}",
+				WorktreeTemplate: "{{slug .AuthorLogin}}",
+			},
+			slugCfg:      underscoreSlugifyConfig(),
+			data:         PRTemplateData{AuthorLogin: "prod", BranchName: "fix", Number: 7, Title: "Fix: the login bug!"},
+			wantBranch:   "pr/fix_the_login_bug",
+			wantWorktree: "prod_" + computeHash("prod", 4),
+		},
+		{
+			name: "surrounding whitespace trimmed",
+			prCfg: config.PRConfig{
+				BranchTemplate:   " {{.BranchName}} ",
+				WorktreeTemplate: "pr-{{.Number}}\n",
+			},
+			data:         PRTemplateData{BranchName: "main", Number: 1},
+			wantBranch:   "main",
+			wantWorktree: "pr-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slugCfg := tt.slugCfg
+			if slugCfg.Separator == "" {
+				slugCfg = defaultSlugifyConfig()
+			}
+			namer, err := NewPRWorktreeNamer(tt.prCfg, slugCfg)
+			require.NoError(t, err)
+
+			branch, err := namer.BranchName(tt.data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantBranch, branch)
+
+			worktree, err := namer.WorktreeName(tt.data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantWorktree, worktree)
+		})
+	}
+}
+
+func TestNewPRWorktreeNamer_InvalidTemplates(t *testing.T) {
+	tests := []struct {
+		name    string
+		prCfg   config.PRConfig
+		wantErr string
+	}{
+		{
+			name:    "syntax error",
+			prCfg:   config.PRConfig{BranchTemplate: "{{.BranchName", WorktreeTemplate: "pr-{{.Number}}"},
+			wantErr: "invalid pr.branch_template",
+		},
+		{
+			name:    "unknown field",
+			prCfg:   config.PRConfig{BranchTemplate: "{{.BranchName}}", WorktreeTemplate: "pr-{{.Author}}"},
+			wantErr: "invalid pr.worktree_template",
+		},
+		{
+			name:    "unknown function",
+			prCfg:   config.PRConfig{BranchTemplate: "{{title .Title}}", WorktreeTemplate: "pr-{{.Number}}"},
+			wantErr: "invalid pr.branch_template",
+		},
+		{
+			name:    "renders empty",
+			prCfg:   config.PRConfig{BranchTemplate: "{{if false}}x{{end}}", WorktreeTemplate: "pr-{{.Number}}"},
+			wantErr: "rendered an empty name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPRWorktreeNamer(tt.prCfg, config.DefaultConfig().Slugify)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestTrunc(t *testing.T) {
+	assert.Equal(t, "add-user", trunc(9, "add-user-auth", "-"))
+	assert.Equal(t, "short", trunc(10, "short", "-"))
+	assert.Equal(t, "héll", trunc(4, "héllo", "-"))
+	assert.Equal(t, "add_user", trunc(9, "add_user_auth", "_"))
+}
@@ -0,0 +1,46 @@
+package naming
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/jmcampanini/grove-cli/internal/pathutil"
+)
+
+// WorktreeRootData holds the fields available to the worktree.root template.
+type WorktreeRootData struct {
+	RepoName string // e.g., "widgets" for git@github.com:acme/widgets.git
+}
+
+// RenderWorktreeRoot renders the worktree.root template into the absolute directory new worktrees are created in.
+// A leading "~" is expanded to homeDir. The result must be an absolute path.
+func RenderWorktreeRoot(root string, data WorktreeRootData, homeDir string) (string, error) {
+	tmpl, err := template.New("worktree.root").Option("missingkey=error").Parse(root)
+	if err != nil {
+		return "", fmt.Errorf("invalid worktree.root: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("invalid worktree.root: %w", err)
+	}
+
+	dir := pathutil.ExpandHome(strings.TrimSpace(buf.String()), homeDir)
+	if !filepath.IsAbs(dir) {
+		return "", fmt.Errorf("worktree.root must be an absolute path or start with ~, got %q", dir)
+	}
+	return filepath.Clean(dir), nil
+}
+
+// RepoNameFromURL returns the repository name from a remote URL, e.g. "widgets" for
+// "git@github.com:acme/widgets.git", "https://github.com/acme/widgets", or "/srv/git/widgets.git".
+// Returns "" if the URL has no name.
+func RepoNameFromURL(url string) string {
+	url = strings.TrimRight(url, "/")
+	if i := strings.LastIndexAny(url, "/:"); i >= 0 {
+		url = url[i+1:]
+	}
+	return strings.TrimSuffix(url, ".git")
+}
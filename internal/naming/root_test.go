@@ -0,0 +1,62 @@
+package naming
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderWorktreeRoot(t *testing.T) {
+	tests := []struct {
+		name    string
+		root    string
+		want    string
+		wantErr string
+	}{
+		{name: "absolute path", root: "/srv/worktrees", want: "/srv/worktrees"},
+		{name: "repo name", root: "/srv/worktrees/{{.RepoName}}", want: "/srv/worktrees/widgets"},
+		{name: "home directory", root: "~/worktrees/{{.RepoName}}", want: "/home/me/worktrees/widgets"},
+		{name: "home directory alone", root: "~", want: "/home/me"},
+		{name: "cleaned", root: "/srv//worktrees/", want: "/srv/worktrees"},
+		{name: "relative path", root: "worktrees", wantErr: `worktree.root must be an absolute path or start with ~, got "worktrees"`},
+		{name: "other user's home", root: "~bob/worktrees", wantErr: "must be an absolute path"},
+		{name: "unknown field", root: "/srv/{{.Owner}}", wantErr: "invalid worktree.root"},
+		{name: "syntax error", root: "/srv/{{.RepoName", wantErr: "invalid worktree.root"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderWorktreeRoot(tt.root, WorktreeRootData{RepoName: "widgets"}, "/home/me")
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRepoNameFromURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "git@github.com:acme/widgets.git", want: "widgets"},
+		{url: "https://github.com/acme/widgets", want: "widgets"},
+		{url: "https://github.com/acme/widgets.git/", want: "widgets"},
+		{url: "ssh://git@example.com:2222/acme/widgets.git", want: "widgets"},
+		{url: "/srv/git/widgets.git", want: "widgets"},
+		{url: "widgets", want: "widgets"},
+		{url: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			assert.Equal(t, tt.want, RepoNameFromURL(tt.url))
+		})
+	}
+}
@@ -4,8 +4,16 @@ import (
 	"hash/fnv"
 	"regexp"
 	"strings"
+
+	"github.com/jmcampanini/grove-cli/internal/config"
 )
 
+// defaultSeparator joins words when SlugifyOptions.Separator is empty.
+const defaultSeparator = "-"
+
+// reservedHashLength is the hash suffix length for reserved names when HashLength is 0.
+const reservedHashLength = 4
+
 // SlugifyOptions configures the behavior of the Slugify function.
 type SlugifyOptions struct {
 	// MaxLength limits the output length. 0 means no limit.
@@ -19,20 +27,38 @@ type SlugifyOptions struct {
 	// Lowercase converts the entire string to lowercase.
 	Lowercase bool
 
-	// ReplaceNonAlphaNum replaces all non-alphanumeric characters with dashes.
+	// ReplaceNonAlphaNum replaces all non-alphanumeric characters with the separator.
 	ReplaceNonAlphaNum bool
 
-	// CollapseDashes collapses consecutive dashes into a single dash.
+	// CollapseDashes collapses consecutive separators into one.
 	CollapseDashes bool
 
-	// TrimDashes removes leading and trailing dashes from the result.
+	// TrimDashes removes leading and trailing separators from the result.
 	TrimDashes bool
+
+	// Separator joins words and the hash suffix. Empty means "-".
+	Separator string
+
+	// Reserved lists names the result must never equal, compared case-insensitively.
+	// A result that would gets the hash suffix appended.
+	Reserved []string
 }
 
-var (
-	nonAlphaNumRegex     = regexp.MustCompile(`[^a-zA-Z0-9]+`)
-	consecutiveDashRegex = regexp.MustCompile(`-{2,}`)
-)
+var nonAlphaNumRegex = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// slugifyOptions converts the [slugify] config into options for Slugify.
+func slugifyOptions(cfg config.SlugifyConfig) SlugifyOptions {
+	return SlugifyOptions{
+		CollapseDashes:     cfg.CollapseDashes,
+		HashLength:         cfg.HashLength,
+		Lowercase:          cfg.Lowercase,
+		MaxLength:          cfg.MaxLength,
+		Reserved:           cfg.Reserved,
+		ReplaceNonAlphaNum: cfg.ReplaceNonAlphanum,
+		Separator:          cfg.Separator,
+		TrimDashes:         cfg.TrimDashes,
+	}
+}
 
 // Slugify transforms a string into a slug based on the provided options.
 func Slugify(input string, opts SlugifyOptions) string {
@@ -40,6 +66,10 @@ func Slugify(input string, opts SlugifyOptions) string {
 		return ""
 	}
 
+	sep := opts.Separator
+	if sep == "" {
+		sep = defaultSeparator
+	}
 	result := input
 
 	if opts.Lowercase {
@@ -47,15 +77,17 @@ func Slugify(input string, opts SlugifyOptions) string {
 	}
 
 	if opts.ReplaceNonAlphaNum {
-		result = nonAlphaNumRegex.ReplaceAllString(result, "-")
+		result = nonAlphaNumRegex.ReplaceAllLiteralString(result, sep)
 	}
 
 	if opts.CollapseDashes {
-		result = consecutiveDashRegex.ReplaceAllString(result, "-")
+		for strings.Contains(result, sep+sep) {
+			result = strings.ReplaceAll(result, sep+sep, sep)
+		}
 	}
 
 	if opts.TrimDashes {
-		result = strings.Trim(result, "-")
+		result = strings.Trim(result, sep)
 	}
 
 	if result == "" {
@@ -64,12 +96,34 @@ func Slugify(input string, opts SlugifyOptions) string {
 
 	if opts.MaxLength > 0 && len(result) > opts.MaxLength {
 		hash := computeHash(input, opts.HashLength)
-		result = truncateWithHash(result, hash, opts.MaxLength, opts.HashLength)
+		result = truncateWithHash(result, hash, opts.MaxLength, opts.HashLength, sep)
+	}
+
+	if isReserved(result, opts.Reserved) {
+		hashLength := opts.HashLength
+		if hashLength <= 0 {
+			hashLength = reservedHashLength
+		}
+		hash := computeHash(input, hashLength)
+		if opts.MaxLength > 0 && len(result)+len(sep)+len(hash) > opts.MaxLength {
+			result = truncateWithHash(result, hash, opts.MaxLength, hashLength, sep)
+		} else {
+			result += sep + hash
+		}
 	}
 
 	return result
 }
 
+func isReserved(name string, reserved []string) bool {
+	for _, r := range reserved {
+		if strings.EqualFold(name, r) {
+			return true
+		}
+	}
+	return false
+}
+
 // computeHash generates a base36 hash of the input string.
 func computeHash(input string, length int) string {
 	if length <= 0 {
@@ -93,8 +147,8 @@ func computeHash(input string, length int) string {
 }
 
 // truncateWithHash truncates the result and appends a hash suffix.
-func truncateWithHash(result, hash string, maxLength, hashLength int) string {
-	prefixLength := maxLength - hashLength - 1 // include dash separator
+func truncateWithHash(result, hash string, maxLength, hashLength int, sep string) string {
+	prefixLength := maxLength - hashLength - len(sep)
 	if prefixLength < 1 {
 		if len(hash) > maxLength {
 			return hash[:maxLength]
@@ -107,7 +161,7 @@ func truncateWithHash(result, hash string, maxLength, hashLength int) string {
 		prefix = prefix[:prefixLength]
 	}
 
-	prefix = strings.TrimRight(prefix, "-")
+	prefix = strings.TrimRight(prefix, sep)
 	if prefix == "" {
 		if len(hash) > maxLength {
 			return hash[:maxLength]
@@ -115,5 +169,5 @@ func truncateWithHash(result, hash string, maxLength, hashLength int) string {
 		return hash
 	}
 
-	return prefix + "-" + hash
+	return prefix + sep + hash
 }
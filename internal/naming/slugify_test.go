@@ -410,3 +410,101 @@ func TestComputeHash(t *testing.T) {
 		}
 	}
 }
+
+func TestSlugify_Separator(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  SlugifyOptions
+		want  string
+	}{
+		{
+			name:  "underscore",
+			input: "--Add  user/auth--",
+			opts:  SlugifyOptions{Lowercase: true, ReplaceNonAlphaNum: true, CollapseDashes: true, TrimDashes: true, Separator: "_"},
+			want:  "add_user_auth",
+		},
+		{
+			name:  "dashes are replaced too",
+			input: "add-user-auth",
+			opts:  SlugifyOptions{ReplaceNonAlphaNum: true, Separator: "_"},
+			want:  "add_user_auth",
+		},
+		{
+			name:  "empty separator means dash",
+			input: "add user auth",
+			opts:  SlugifyOptions{ReplaceNonAlphaNum: true},
+			want:  "add-user-auth",
+		},
+		{
+			name:  "hash joined with the separator",
+			input: "add user authentication",
+			opts:  SlugifyOptions{ReplaceNonAlphaNum: true, MaxLength: 12, HashLength: 4, Separator: "_"},
+			want:  "add_use_" + computeHash("add user authentication", 4),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Slugify(tt.input, tt.opts)
+			if result != tt.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.input, result, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlugify_Reserved(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  SlugifyOptions
+		want  string
+	}{
+		{
+			name:  "reserved name gets the hash",
+			input: "Prod",
+			opts:  SlugifyOptions{Lowercase: true, HashLength: 6, Reserved: []string{"prod", "release"}},
+			want:  "prod-" + computeHash("Prod", 6),
+		},
+		{
+			name:  "compared case-insensitively",
+			input: "PROD",
+			opts:  SlugifyOptions{HashLength: 4, Reserved: []string{"prod"}},
+			want:  "PROD-" + computeHash("PROD", 4),
+		},
+		{
+			name:  "joined with the separator",
+			input: "release",
+			opts:  SlugifyOptions{HashLength: 4, Reserved: []string{"release"}, Separator: "_"},
+			want:  "release_" + computeHash("release", 4),
+		},
+		{
+			name:  "default hash length when hashing is off",
+			input: "prod",
+			opts:  SlugifyOptions{Reserved: []string{"prod"}},
+			want:  "prod-" + computeHash("prod", reservedHashLength),
+		},
+		{
+			name:  "kept within the max length",
+			input: "release",
+			opts:  SlugifyOptions{MaxLength: 9, HashLength: 4, Reserved: []string{"release"}},
+			want:  "rele-" + computeHash("release", 4),
+		},
+		{
+			name:  "only whole names are reserved",
+			input: "prod fix",
+			opts:  SlugifyOptions{ReplaceNonAlphaNum: true, HashLength: 4, Reserved: []string{"prod"}},
+			want:  "prod-fix",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Slugify(tt.input, tt.opts)
+			if result != tt.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.input, result, tt.want)
+			}
+		})
+	}
+}
@@ -1,36 +1,74 @@
 package naming
 
 import (
+	"bytes"
+	"fmt"
 	"path/filepath"
 	"strings"
+	"text/template"
 
 	"github.com/jmcampanini/grove-cli/internal/config"
 )
 
+// WorktreeTemplateData holds the fields available to the [worktree] template.
+type WorktreeTemplateData struct {
+	BranchName string // The full branch name, e.g. "feature/add-auth"
+	Prefix     string // The [worktree] new_prefix
+	Short      string // A short hash of the branch name, to keep similar names apart
+	Slug       string // The branch name without its stripped prefix, slugified with the [slugify] rules
+}
+
+// worktreeShortHashLength is the length of WorktreeTemplateData.Short.
+const worktreeShortHashLength = 6
+
 // WorktreeNamer handles worktree directory name operations.
 type WorktreeNamer struct {
 	prefix            string
 	slugifyOpts       SlugifyOptions
 	stripBranchPrefix []string
+	tmpl              *template.Template // nil names worktrees with the prefix and slug
 }
 
-// NewWorktreeNamer creates a namer from config.
-func NewWorktreeNamer(worktreeCfg config.WorktreeConfig, slugCfg config.SlugifyConfig) *WorktreeNamer {
-	return &WorktreeNamer{
+// NewWorktreeNamer creates a namer from config. A [worktree] template is parsed and validated
+// against sample data, so that unknown fields are reported up front instead of when a worktree is named.
+func NewWorktreeNamer(worktreeCfg config.WorktreeConfig, slugCfg config.SlugifyConfig) (*WorktreeNamer, error) {
+	n := &WorktreeNamer{
 		prefix:            worktreeCfg.NewPrefix,
 		stripBranchPrefix: worktreeCfg.StripBranchPrefix,
-		slugifyOpts: SlugifyOptions{
-			CollapseDashes:     slugCfg.CollapseDashes,
-			HashLength:         slugCfg.HashLength,
-			Lowercase:          slugCfg.Lowercase,
-			MaxLength:          slugCfg.MaxLength,
-			ReplaceNonAlphaNum: slugCfg.ReplaceNonAlphanum,
-			TrimDashes:         slugCfg.TrimDashes,
-		},
+		slugifyOpts:       slugifyOptions(slugCfg),
+	}
+	if strings.TrimSpace(worktreeCfg.Template) == "" {
+		return n, nil
+	}
+
+	tmpl, err := template.New("worktree.template").Option("missingkey=error").Funcs(templateFuncs(n.slugifyOpts)).Parse(worktreeCfg.Template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid worktree.template: %w", err)
+	}
+	sample := WorktreeTemplateData{BranchName: "feature/sample", Prefix: n.prefix, Short: "a1b2c3", Slug: "sample"}
+	if _, err := renderWorktreeTemplate(tmpl, sample); err != nil {
+		return nil, fmt.Errorf("invalid worktree.template: %w", err)
+	}
+	n.tmpl = tmpl
+	return n, nil
+}
+
+func renderWorktreeTemplate(tmpl *template.Template, data WorktreeTemplateData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	result := strings.TrimSpace(buf.String())
+	if result == "" {
+		return "", fmt.Errorf("%s rendered an empty name", tmpl.Name())
+	}
+	if strings.ContainsAny(result, `/\`) {
+		return "", fmt.Errorf("%s rendered %q, which is not a single directory name", tmpl.Name(), result)
 	}
+	return result, nil
 }
 
-// Generate creates a worktree name from a branch name.
+// Generate creates a worktree name from a branch name, or "" when the branch name has nothing to slugify.
 func (n *WorktreeNamer) Generate(branchName string) string {
 	if branchName == "" {
 		return ""
@@ -49,7 +87,20 @@ func (n *WorktreeNamer) Generate(branchName string) string {
 		return ""
 	}
 
-	return n.prefix + slug
+	if n.tmpl == nil {
+		return n.prefix + slug
+	}
+	data := WorktreeTemplateData{
+		BranchName: branchName,
+		Prefix:     n.prefix,
+		Short:      computeHash(branchName, worktreeShortHashLength),
+		Slug:       slug,
+	}
+	result, err := renderWorktreeTemplate(n.tmpl, data)
+	if err != nil {
+		return ""
+	}
+	return result
 }
 
 // ExtractFromAbsolutePath returns the display name from an absolute worktree path.
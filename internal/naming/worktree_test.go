@@ -5,6 +5,7 @@ import (
 
 	"github.com/jmcampanini/grove-cli/internal/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWorktreeNamer_Generate(t *testing.T) {
@@ -145,11 +146,33 @@ func TestWorktreeNamer_Generate(t *testing.T) {
 			branchName: "feature/jcamp/add-auth",
 			want:       "wt-add-auth",
 		},
+		{
+			name: "template with prefix, slug, and short hash",
+			worktreeCfg: config.WorktreeConfig{
+				NewPrefix:         "wt-",
+				StripBranchPrefix: []string{"feature/"},
+				Template:          "{{.Prefix}}{{.Slug}}-{{.Short}}",
+			},
+			slugifyCfg: defaultSlugifyConfig(),
+			branchName: "feature/add-auth",
+			want:       "wt-add-auth-ey2krb",
+		},
+		{
+			name: "template with the full branch name",
+			worktreeCfg: config.WorktreeConfig{
+				StripBranchPrefix: []string{"feature/"},
+				Template:          "{{slug .BranchName}}",
+			},
+			slugifyCfg: defaultSlugifyConfig(),
+			branchName: "feature/Add-Auth",
+			want:       "feature-add-auth",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			namer := NewWorktreeNamer(tt.worktreeCfg, tt.slugifyCfg)
+			namer, err := NewWorktreeNamer(tt.worktreeCfg, tt.slugifyCfg)
+			require.NoError(t, err)
 			got := namer.Generate(tt.branchName)
 			assert.Equal(t, tt.want, got)
 		})
@@ -170,8 +193,9 @@ func TestNewWorktreeNamer(t *testing.T) {
 		TrimDashes:         false,
 	}
 
-	namer := NewWorktreeNamer(worktreeCfg, slugCfg)
+	namer, err := NewWorktreeNamer(worktreeCfg, slugCfg)
 
+	require.NoError(t, err)
 	assert.Equal(t, "test-", namer.prefix)
 	assert.Equal(t, []string{"a/", "b/"}, namer.stripBranchPrefix)
 	assert.Equal(t, 8, namer.slugifyOpts.HashLength)
@@ -179,6 +203,27 @@ func TestNewWorktreeNamer(t *testing.T) {
 	assert.Equal(t, 75, namer.slugifyOpts.MaxLength)
 }
 
+func TestNewWorktreeNamer_InvalidTemplates(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  string
+	}{
+		{name: "syntax error", template: "{{.Slug", wantErr: "invalid worktree.template"},
+		{name: "unknown field", template: "{{.Number}}-{{.Slug}}", wantErr: "invalid worktree.template"},
+		{name: "renders empty", template: "{{if false}}x{{end}}", wantErr: "rendered an empty name"},
+		{name: "renders a path", template: "{{.Prefix}}/{{.Slug}}", wantErr: "not a single directory name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewWorktreeNamer(config.WorktreeConfig{Template: tt.template}, defaultSlugifyConfig())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
 func TestWorktreeNamer_ExtractFromAbsolutePath(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -246,7 +291,8 @@ func TestWorktreeNamer_ExtractFromAbsolutePath(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			namer := NewWorktreeNamer(tt.worktreeCfg, defaultSlugifyConfig())
+			namer, err := NewWorktreeNamer(tt.worktreeCfg, defaultSlugifyConfig())
+			require.NoError(t, err)
 			got := namer.ExtractFromAbsolutePath(tt.absPath)
 			assert.Equal(t, tt.want, got)
 		})
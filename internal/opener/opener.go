@@ -0,0 +1,67 @@
+package opener
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// TemplateData holds the fields available to open command templates.
+type TemplateData struct {
+	Branch string // Branch checked out in the worktree, empty when detached
+	Name   string // Worktree display name
+	Path   string // Absolute worktree path
+}
+
+// Presets maps built-in preset names to command templates.
+var Presets = map[string]string{
+	"jetbrains": "idea {{.Path}}",
+	"tmux":      "tmux new-window -c {{.Path}} -n {{.Name}}",
+	"vscode":    "code {{.Path}}",
+}
+
+// PresetNames returns the sorted names of the built-in presets.
+func PresetNames() []string {
+	names := make([]string, 0, len(Presets))
+	for name := range Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BuildCommand expands a command (a preset name or a template) into program arguments.
+// The command is split on whitespace before each argument is rendered, so paths containing
+// spaces stay a single argument without any shell quoting.
+func BuildCommand(command string, data TemplateData) ([]string, error) {
+	command = strings.TrimSpace(command)
+	if command == "" {
+		return nil, fmt.Errorf("no open command configured; set [open] command to a template or one of: %s",
+			strings.Join(PresetNames(), ", "))
+	}
+	if preset, ok := Presets[command]; ok {
+		command = preset
+	}
+
+	fields := strings.Fields(command)
+	args := make([]string, 0, len(fields))
+	for _, field := range fields {
+		tmpl, err := template.New("open.command").Option("missingkey=error").Parse(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid open.command: %w", err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("invalid open.command: %w", err)
+		}
+		args = append(args, buf.String())
+	}
+
+	if args[0] == "" {
+		return nil, errors.New("invalid open.command: program name is empty")
+	}
+	return args, nil
+}
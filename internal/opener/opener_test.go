@@ -0,0 +1,72 @@
+package opener
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCommand(t *testing.T) {
+	data := TemplateData{Branch: "feature/auth", Name: "auth", Path: "/ws/my projects/wt-auth"}
+
+	tests := []struct {
+		name    string
+		command string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:    "vscode preset",
+			command: "vscode",
+			want:    []string{"code", "/ws/my projects/wt-auth"},
+		},
+		{
+			name:    "jetbrains preset",
+			command: "jetbrains",
+			want:    []string{"idea", "/ws/my projects/wt-auth"},
+		},
+		{
+			name:    "tmux preset",
+			command: "tmux",
+			want:    []string{"tmux", "new-window", "-c", "/ws/my projects/wt-auth", "-n", "auth"},
+		},
+		{
+			name:    "custom template",
+			command: "zed --branch={{.Branch}} {{.Path}}",
+			want:    []string{"zed", "--branch=feature/auth", "/ws/my projects/wt-auth"},
+		},
+		{
+			name:    "empty command",
+			command: "  ",
+			wantErr: "no open command configured",
+		},
+		{
+			name:    "unknown field",
+			command: "code {{.Dir}}",
+			wantErr: "invalid open.command",
+		},
+		{
+			name:    "syntax error",
+			command: "code {{.Path",
+			wantErr: "invalid open.command",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildCommand(tt.command, data)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPresetNames(t *testing.T) {
+	assert.Equal(t, []string{"jetbrains", "tmux", "vscode"}, PresetNames())
+}
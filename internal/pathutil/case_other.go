@@ -0,0 +1,8 @@
+//go:build !windows
+
+package pathutil
+
+// foldCase returns path unchanged; case matters on the file systems grove assumes outside Windows.
+func foldCase(path string) string {
+	return path
+}
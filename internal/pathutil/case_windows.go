@@ -0,0 +1,10 @@
+//go:build windows
+
+package pathutil
+
+import "strings"
+
+// foldCase lowercases path, since Windows file systems ignore case.
+func foldCase(path string) string {
+	return strings.ToLower(path)
+}
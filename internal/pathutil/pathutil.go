@@ -0,0 +1,78 @@
+package pathutil
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Normalize returns the absolute, cleaned form of path with symlinks resolved, so that two spellings of the same
+// directory compare equal (e.g., macOS /var/folders/... and /private/var/folders/..., or a symlinked home directory).
+// Git reports worktree paths with symlinks resolved, while the shell's working directory and $HOME may not be.
+// Trailing components that do not exist yet are kept as-is under their nearest existing, resolved ancestor.
+// Returns "" for an empty path.
+func Normalize(path string) string {
+	if path == "" {
+		return ""
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+
+	var missing []string
+	for dir := abs; ; {
+		resolved, err := filepath.EvalSymlinks(dir)
+		if err == nil {
+			return filepath.Join(append([]string{resolved}, missing...)...)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return abs
+		}
+		missing = append([]string{filepath.Base(dir)}, missing...)
+		dir = parent
+	}
+}
+
+// Equal reports whether a and b name the same location once normalized.
+// On Windows, whose file systems ignore case, paths differing only in case are equal.
+func Equal(a, b string) bool {
+	return a == b || Canonical(a) == Canonical(b)
+}
+
+// Canonical returns a form of path that is the same for every spelling of the location, for use as a map key:
+// the normalized path, lowercased on Windows. It is for comparing paths, not for showing or opening them.
+func Canonical(path string) string {
+	return foldCase(Normalize(path))
+}
+
+// Within reports whether path is dir or a location inside it, once both are normalized.
+func Within(path, dir string) bool {
+	if path == "" || dir == "" {
+		return false
+	}
+	rel, err := filepath.Rel(Canonical(dir), Canonical(path))
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// FromGit converts a path printed by git to the platform's form. Git for Windows prints forward slashes
+// (e.g. "C:/Users/me/repo"), which must become backslashes to compare equal to paths built with
+// filepath.Join. Returns "" for an empty path.
+func FromGit(path string) string {
+	if path == "" {
+		return ""
+	}
+	return filepath.Clean(filepath.FromSlash(path))
+}
+
+// ExpandHome replaces a leading "~" in path with homeDir, e.g. "~/code/api" -> "<homeDir>/code/api".
+// On Windows, "~\code\api" is expanded too. Other paths, including "~user/...", are returned unchanged.
+func ExpandHome(path, homeDir string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return filepath.Join(homeDir, path[1:])
+	}
+	return path
+}
@@ -0,0 +1,141 @@
+package pathutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSymlinkedWorkspace creates <tmp>/real/ws/main and a <tmp>/link symlink to <tmp>/real,
+// and returns the resolved and linked spellings of the workspace directory.
+func newSymlinkedWorkspace(t *testing.T) (resolved, linked string) {
+	t.Helper()
+	// TempDir itself may sit behind a symlink (e.g., /var -> /private/var on macOS)
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	resolved = filepath.Join(root, "real", "ws")
+	require.NoError(t, os.MkdirAll(filepath.Join(resolved, "main"), 0o755))
+	symlinkOrSkip(t, filepath.Join(root, "real"), filepath.Join(root, "link"))
+	return resolved, filepath.Join(root, "link", "ws")
+}
+
+// symlinkOrSkip creates a symlink, skipping the test where the platform does not let the user create one
+// (e.g. Windows without developer mode).
+func symlinkOrSkip(t *testing.T, oldname, newname string) {
+	t.Helper()
+	if err := os.Symlink(oldname, newname); err != nil {
+		t.Skipf("cannot create symlinks here: %v", err)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	resolved, linked := newSymlinkedWorkspace(t)
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{name: "empty", path: "", want: ""},
+		{name: "resolved path", path: filepath.Join(resolved, "main"), want: filepath.Join(resolved, "main")},
+		{name: "symlinked path", path: filepath.Join(linked, "main"), want: filepath.Join(resolved, "main")},
+		{name: "unclean path", path: linked + "/./main/../main/", want: filepath.Join(resolved, "main")},
+		{name: "missing child of symlinked path", path: filepath.Join(linked, "wt-new", "sub"), want: filepath.Join(resolved, "wt-new", "sub")},
+		{name: "nonexistent path", path: "/grove-does-not-exist/ws/main", want: "/grove-does-not-exist/ws/main"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Normalize(tt.path))
+		})
+	}
+}
+
+func TestEqual(t *testing.T) {
+	resolved, linked := newSymlinkedWorkspace(t)
+
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want bool
+	}{
+		{name: "identical", a: "/ws/main", b: "/ws/main", want: true},
+		{name: "symlinked and resolved", a: filepath.Join(linked, "main"), b: filepath.Join(resolved, "main"), want: true},
+		{name: "symlinked workspace dirs", a: linked, b: resolved + "/", want: true},
+		{name: "different worktrees", a: filepath.Join(linked, "main"), b: filepath.Join(resolved, "feature"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Equal(tt.a, tt.b))
+		})
+	}
+}
+
+func TestWithin(t *testing.T) {
+	resolved, linked := newSymlinkedWorkspace(t)
+
+	tests := []struct {
+		name string
+		path string
+		dir  string
+		want bool
+	}{
+		{name: "same directory", path: "/ws/main", dir: "/ws/main", want: true},
+		{name: "child", path: "/ws/main/trees", dir: "/ws/main", want: true},
+		{name: "nested", path: "/ws/main/a/b", dir: "/ws/main/", want: true},
+		{name: "sibling", path: "/ws/feature", dir: "/ws/main", want: false},
+		{name: "shared name prefix", path: "/ws/main-2", dir: "/ws/main", want: false},
+		{name: "parent", path: "/ws", dir: "/ws/main", want: false},
+		{name: "dot-dot directory name", path: "/ws/main/..trees", dir: "/ws/main", want: true},
+		{name: "symlinked path", path: filepath.Join(linked, "main", "trees"), dir: filepath.Join(resolved, "main"), want: true},
+		{name: "empty", path: "", dir: "/ws/main", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Within(tt.path, tt.dir))
+		})
+	}
+}
+
+func TestFromGit(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "", want: ""},
+		{path: "/ws/main", want: filepath.FromSlash("/ws/main")},
+		{path: "/ws/main/", want: filepath.FromSlash("/ws/main")},
+		{path: "/ws/./main", want: filepath.FromSlash("/ws/main")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, FromGit(tt.path))
+		})
+	}
+}
+
+func TestExpandHome(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "~", want: "/home/me"},
+		{path: "~/code/api", want: "/home/me/code/api"},
+		{path: "/srv/code", want: "/srv/code"},
+		{path: "~bob/code", want: "~bob/code"},
+		{path: "code/~", want: "code/~"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExpandHome(tt.path, "/home/me"))
+		})
+	}
+}
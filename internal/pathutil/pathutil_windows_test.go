@@ -0,0 +1,51 @@
+//go:build windows
+
+package pathutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromGit_Windows(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "C:/Users/me/repo", want: `C:\Users\me\repo`},
+		{path: "C:/Users/me/repo/.git/worktrees/feature", want: `C:\Users\me\repo\.git\worktrees\feature`},
+		{path: `C:\Users\me\repo\`, want: `C:\Users\me\repo`},
+		{path: "//server/share/repo", want: `\\server\share\repo`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, FromGit(tt.path))
+		})
+	}
+}
+
+func TestEqual_Windows(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want bool
+	}{
+		{name: "case differs", a: `C:\Users\Me\Code\repo`, b: `c:\users\me\code\REPO`, want: true},
+		{name: "separators differ", a: `C:\Users\me\repo`, b: "C:/Users/me/repo", want: true},
+		{name: "different drives", a: `C:\repo`, b: `D:\repo`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Equal(tt.a, tt.b))
+		})
+	}
+}
+
+func TestExpandHome_Windows(t *testing.T) {
+	assert.Equal(t, `C:\Users\me\code\api`, ExpandHome(`~\code\api`, `C:\Users\me`))
+	assert.Equal(t, `C:\Users\me\code\api`, ExpandHome("~/code/api", `C:\Users\me`))
+}
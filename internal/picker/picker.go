@@ -0,0 +1,144 @@
+package picker
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/jmcampanini/grove-cli/internal/config"
+)
+
+// ErrCancelled is returned when the user exits the picker without selecting an item.
+var ErrCancelled = errors.New("selection cancelled")
+
+// Item is a single selectable entry.
+type Item struct {
+	Display string // Human readable text shown to the user
+	Key     string // Value returned to the caller, must not contain tabs or newlines
+}
+
+// Picker lets the user choose one item from a list.
+type Picker interface {
+	// Pick returns the selected item, or ErrCancelled if nothing was selected.
+	Pick(items []Item) (Item, error)
+}
+
+// New returns the picker configured by kind (one of config.ValidPickers).
+// The auto kind uses fzf when it is installed and falls back to the prompt picker otherwise.
+// preview is an optional fzf preview command where {1} is replaced with the item key.
+func New(kind, preview string, in io.Reader, out io.Writer) (Picker, error) {
+	switch kind {
+	case config.PickerFzf:
+		if _, err := exec.LookPath("fzf"); err != nil {
+			return nil, errors.New("ui.picker is set to fzf but fzf is not installed")
+		}
+		return &FzfPicker{Preview: preview}, nil
+	case config.PickerPrompt:
+		return &PromptPicker{In: in, Out: out}, nil
+	case config.PickerAuto, "":
+		if _, err := exec.LookPath("fzf"); err == nil {
+			return &FzfPicker{Preview: preview}, nil
+		}
+		return &PromptPicker{In: in, Out: out}, nil
+	default:
+		return nil, fmt.Errorf("unknown picker: %s", kind)
+	}
+}
+
+// FzfPicker selects an item by running fzf.
+type FzfPicker struct {
+	Preview string
+}
+
+var _ Picker = &FzfPicker{}
+
+func (p *FzfPicker) Pick(items []Item) (Item, error) {
+	if len(items) == 0 {
+		return Item{}, ErrCancelled
+	}
+
+	var input bytes.Buffer
+	for _, item := range items {
+		input.WriteString(item.Key + "\t" + item.Display + "\n")
+	}
+
+	args := []string{"--delimiter", "\t", "--with-nth", "2"}
+	if p.Preview != "" {
+		args = append(args, "--preview", p.Preview)
+	}
+
+	cmd := exec.Command("fzf", args...)
+	cmd.Stdin = &input
+	cmd.Stderr = os.Stderr
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		// fzf exits with 1 when there is no match and 130 when interrupted
+		if errors.As(err, &exitErr) && (exitErr.ExitCode() == 1 || exitErr.ExitCode() == 130) {
+			return Item{}, ErrCancelled
+		}
+		return Item{}, fmt.Errorf("fzf failed: %w", err)
+	}
+
+	key, _, _ := strings.Cut(strings.TrimSpace(stdout.String()), "\t")
+	for _, item := range items {
+		if item.Key == key {
+			return item, nil
+		}
+	}
+	return Item{}, ErrCancelled
+}
+
+// PromptPicker selects an item from a numbered list read from In.
+type PromptPicker struct {
+	In  io.Reader
+	Out io.Writer
+}
+
+var _ Picker = &PromptPicker{}
+
+func (p *PromptPicker) Pick(items []Item) (Item, error) {
+	if len(items) == 0 {
+		return Item{}, ErrCancelled
+	}
+
+	for i, item := range items {
+		if _, err := fmt.Fprintf(p.Out, "%3d) %s\n", i+1, item.Display); err != nil {
+			return Item{}, err
+		}
+	}
+
+	reader := bufio.NewReader(p.In)
+	for {
+		if _, err := fmt.Fprintf(p.Out, "Select [1-%d]: ", len(items)); err != nil {
+			return Item{}, err
+		}
+
+		line, readErr := reader.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if answer == "" || answer == "q" {
+			if readErr != nil && !errors.Is(readErr, io.EOF) {
+				return Item{}, fmt.Errorf("failed to read selection: %w", readErr)
+			}
+			return Item{}, ErrCancelled
+		}
+
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(items) {
+			return items[n-1], nil
+		}
+		if _, err := fmt.Fprintf(p.Out, "invalid selection %q\n", answer); err != nil {
+			return Item{}, err
+		}
+		if readErr != nil {
+			return Item{}, ErrCancelled
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package picker
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jmcampanini/grove-cli/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromptPicker_Pick(t *testing.T) {
+	items := []Item{
+		{Display: "#1 First", Key: "1"},
+		{Display: "#2 Second", Key: "2"},
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    Item
+		wantErr error
+	}{
+		{name: "first item", input: "1\n", want: items[0]},
+		{name: "second item without newline", input: "2", want: items[1]},
+		{name: "retry after invalid input", input: "9\nabc\n2\n", want: items[1]},
+		{name: "empty input cancels", input: "\n", wantErr: ErrCancelled},
+		{name: "q cancels", input: "q\n", wantErr: ErrCancelled},
+		{name: "eof cancels", input: "", wantErr: ErrCancelled},
+		{name: "invalid input at eof cancels", input: "9", wantErr: ErrCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			p := &PromptPicker{In: strings.NewReader(tt.input), Out: &out}
+
+			got, err := p.Pick(items)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Contains(t, out.String(), "  1) #1 First\n")
+			assert.Contains(t, out.String(), "Select [1-2]: ")
+		})
+	}
+}
+
+func TestPromptPicker_NoItems(t *testing.T) {
+	p := &PromptPicker{In: strings.NewReader("1\n"), Out: &bytes.Buffer{}}
+	_, err := p.Pick(nil)
+	assert.ErrorIs(t, err, ErrCancelled)
+}
+
+func TestNew(t *testing.T) {
+	p, err := New(config.PickerPrompt, "", strings.NewReader(""), &bytes.Buffer{})
+	require.NoError(t, err)
+	assert.IsType(t, &PromptPicker{}, p)
+
+	_, err = New("dmenu", "", strings.NewReader(""), &bytes.Buffer{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,62 @@
+// Package porcelain writes grove's machine-readable --porcelain output.
+//
+// The format is line-based and versioned. The first line is a header: "#v<Version>" followed by the
+// column names, separated by tabs. Every following line is one record with one tab-separated value
+// per column. Tabs, newlines, and backslashes inside values are escaped as \t, \n, and \\; empty
+// values are written as empty strings. Within a version, new columns are only ever appended, and
+// existing columns keep their position and meaning; anything else bumps Version.
+package porcelain
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version is the format version written in the header line.
+const Version = 1
+
+// Writer writes records with a fixed set of columns.
+type Writer struct {
+	columns int
+	w       io.Writer
+}
+
+// New writes the header line for columns to w and returns a Writer for the records.
+func New(w io.Writer, columns ...string) (*Writer, error) {
+	header := append([]string{"#v" + strconv.Itoa(Version)}, columns...)
+	if _, err := fmt.Fprintln(w, strings.Join(header, "\t")); err != nil {
+		return nil, err
+	}
+	return &Writer{columns: len(columns), w: w}, nil
+}
+
+// Row writes one record. It must have exactly one value per column.
+func (p *Writer) Row(values ...string) error {
+	if len(values) != p.columns {
+		return fmt.Errorf("porcelain row has %d values, want %d", len(values), p.columns)
+	}
+	escaped := make([]string, len(values))
+	for i, v := range values {
+		escaped[i] = escaper.Replace(v)
+	}
+	_, err := fmt.Fprintln(p.w, strings.Join(escaped, "\t"))
+	return err
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`)
+
+// Bool formats a boolean value as "true" or "false".
+func Bool(b bool) string {
+	return strconv.FormatBool(b)
+}
+
+// Time formats a time value as RFC 3339 in UTC, or "" for the zero time.
+func Time(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,63 @@
+package porcelain
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name string
+		rows [][]string
+		want string
+	}{
+		{
+			name: "header only",
+			want: "#v1\tpath\tbranch\n",
+		},
+		{
+			name: "rows",
+			rows: [][]string{{"/ws/main", "main"}, {"/ws/wt-auth", ""}},
+			want: "#v1\tpath\tbranch\n/ws/main\tmain\n/ws/wt-auth\t\n",
+		},
+		{
+			name: "escaped values",
+			rows: [][]string{{"a\tb", "line1\nline2 C:\\x"}},
+			want: "#v1\tpath\tbranch\na\\tb\tline1\\nline2 C:\\\\x\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			w, err := New(&out, "path", "branch")
+			require.NoError(t, err)
+
+			for _, row := range tt.rows {
+				require.NoError(t, w.Row(row...))
+			}
+
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestWriter_WrongColumnCount(t *testing.T) {
+	var out bytes.Buffer
+	w, err := New(&out, "path", "branch")
+	require.NoError(t, err)
+
+	err = w.Row("/ws/main")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "has 1 values, want 2")
+}
+
+func TestTime(t *testing.T) {
+	assert.Equal(t, "", Time(time.Time{}))
+	assert.Equal(t, "2024-06-01T10:00:00Z", Time(time.Date(2024, 6, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))))
+}
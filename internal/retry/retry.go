@@ -0,0 +1,76 @@
+// Package retry repeats operations that fail for transient reasons, such as a flaky network or VPN.
+package retry
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	clog "github.com/charmbracelet/log"
+)
+
+// BaseDelay is the wait before the first retry; each further retry waits twice as long as the one before.
+var BaseDelay = 500 * time.Millisecond
+
+// transientMessages are lowercased fragments of the messages git, gh, and the libraries under them report
+// timeouts, connection, DNS, and TLS failures with.
+var transientMessages = []string{
+	// timeouts
+	"timed out",
+	"timeout",
+	// connection
+	"connection refused",
+	"connection reset",
+	"connection closed",
+	"broken pipe",
+	"error connecting to",
+	"network is unreachable",
+	// DNS
+	"could not resolve host",
+	"name or service not known",
+	"no such host",
+	"temporary failure in name resolution",
+	// TLS
+	"gnutls",
+	"ssl_",
+	"tls handshake",
+}
+
+// Transient reports whether err is a timeout, or a connection, DNS, or TLS failure, which may not happen again.
+// Any other failure, such as a missing ref or repository, would only fail the same way when retried.
+func Transient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, fragment := range transientMessages {
+		if strings.Contains(msg, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// Do calls fn and, while it fails with an error retryable accepts, calls it again up to retries more times
+// with exponential backoff, logging each retry with name. Only idempotent operations may be retried.
+// Once ctx is done, as on interrupt, Do stops and returns the last error.
+func Do(ctx context.Context, retries int, log *clog.Logger, name string, retryable func(error) bool, fn func() (string, error)) (string, error) {
+	delay := BaseDelay
+	for attempt := 1; ; attempt++ {
+		output, err := fn()
+		if err == nil || attempt > retries || ctx.Err() != nil || !retryable(err) {
+			return output, err
+		}
+		log.Warn("Retrying after failure", "cmd", name, "retry", attempt, "of", retries, "delay", delay, "error", err)
+		select {
+		case <-ctx.Done():
+			return output, err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
@@ -0,0 +1,106 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	clog "github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDo(t *testing.T) {
+	BaseDelay = time.Millisecond
+	t.Cleanup(func() { BaseDelay = 500 * time.Millisecond })
+
+	tests := []struct {
+		name         string
+		retries      int
+		failures     int
+		wantAttempts int
+		wantErr      bool
+	}{
+		{name: "succeeds first time", retries: 2, wantAttempts: 1},
+		{name: "succeeds on retry", retries: 2, failures: 2, wantAttempts: 3},
+		{name: "gives up", retries: 2, failures: 5, wantAttempts: 3, wantErr: true},
+		{name: "no retries", retries: 0, failures: 1, wantAttempts: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			output, err := Do(t.Context(), tt.retries, clog.Default(), "git fetch origin", Transient, func() (string, error) {
+				attempts++
+				if attempts <= tt.failures {
+					return "", errors.New("connection reset")
+				}
+				return "ok", nil
+			})
+
+			assert.Equal(t, tt.wantAttempts, attempts)
+			if tt.wantErr {
+				require.EqualError(t, err, "connection reset")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "ok", output)
+		})
+	}
+}
+
+func TestDo_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	attempts := 0
+
+	_, err := Do(ctx, 3, clog.Default(), "gh pr list", Transient, func() (string, error) {
+		attempts++
+		cancel()
+		return "", errors.New("connection reset")
+	})
+
+	require.EqualError(t, err, "connection reset")
+	assert.Equal(t, 1, attempts)
+}
+
+func TestDo_NotRetryable(t *testing.T) {
+	attempts := 0
+
+	_, err := Do(t.Context(), 3, clog.Default(), "git fetch origin", Transient, func() (string, error) {
+		attempts++
+		return "", errors.New("fatal: couldn't find remote ref feature: not found")
+	})
+
+	require.EqualError(t, err, "fatal: couldn't find remote ref feature: not found")
+	assert.Equal(t, 1, attempts)
+}
+
+func TestTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "deadline exceeded", err: fmt.Errorf("fetch: %w", context.DeadlineExceeded), want: true},
+		{name: "timed out", err: errors.New("git fetch origin timed out after 30s"), want: true},
+		{name: "connection refused", err: errors.New("ssh: connect to host github.com port 22: Connection refused"), want: true},
+		{name: "connection reset", err: errors.New("read tcp: connection reset by peer"), want: true},
+		{name: "gh offline", err: errors.New("error connecting to api.github.com"), want: true},
+		{name: "dns git", err: errors.New("fatal: unable to access 'https://github.com/o/r/': Could not resolve host: github.com"), want: true},
+		{name: "dns gh", err: errors.New("dial tcp: lookup api.github.com: no such host"), want: true},
+		{name: "tls", err: errors.New("net/http: TLS handshake timeout"), want: true},
+		{name: "gnutls", err: errors.New("gnutls_handshake() failed: The TLS connection was non-properly terminated."), want: true},
+		{name: "not found", err: errors.New("fatal: couldn't find remote ref feature"), want: false},
+		{name: "graphql not found", err: errors.New("GraphQL: Could not resolve to a PullRequest with the number of 99."), want: false},
+		{name: "forbidden", err: errors.New("fatal: unable to access 'https://github.com/o/r/': The requested URL returned error: 403"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Transient(tt.err))
+		})
+	}
+}
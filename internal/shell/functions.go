@@ -4,6 +4,15 @@ import (
 	_ "embed"
 )
 
+//go:embed scripts/completion.fish
+var completionFishScript string
+
+//go:embed scripts/completion.bash
+var completionBashScript string
+
+//go:embed scripts/completion.zsh
+var completionZshScript string
+
 //go:embed scripts/grc.fish
 var grcFishScript string
 
@@ -30,17 +39,17 @@ func NewFunctionGenerator() *FunctionGenerator {
 	return &FunctionGenerator{}
 }
 
-// GenerateFish returns all fish shell functions.
+// GenerateFish returns all fish shell functions and the grove completion registration.
 func (g *FunctionGenerator) GenerateFish() string {
-	return grcFishScript + "\n" + grsFishScript
+	return grcFishScript + "\n" + grsFishScript + "\n" + completionFishScript
 }
 
-// GenerateZsh returns all zsh shell functions.
+// GenerateZsh returns all zsh shell functions and the grove completion registration.
 func (g *FunctionGenerator) GenerateZsh() string {
-	return grcZshScript + "\n" + grsZshScript
+	return grcZshScript + "\n" + grsZshScript + "\n" + completionZshScript
 }
 
-// GenerateBash returns all bash shell functions.
+// GenerateBash returns all bash shell functions and the grove completion registration.
 func (g *FunctionGenerator) GenerateBash() string {
-	return grcBashScript + "\n" + grsBashScript
+	return grcBashScript + "\n" + grsBashScript + "\n" + completionBashScript
 }
@@ -70,6 +70,47 @@ func TestFunctionGenerator_BashZshSyntax(t *testing.T) {
 	}
 }
 
+func TestFunctionGenerator_Completion(t *testing.T) {
+	gen := NewFunctionGenerator()
+
+	tests := []struct {
+		name     string
+		generate func() string
+		want     string
+	}{
+		{"fish", gen.GenerateFish, "grove completion fish | source"},
+		{"bash", gen.GenerateBash, "source <(grove completion bash)"},
+		{"zsh", gen.GenerateZsh, "source <(grove completion zsh)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Contains(t, tt.generate(), tt.want)
+		})
+	}
+}
+
+func TestFunctionGenerator_SwitchRecordsVisit(t *testing.T) {
+	gen := NewFunctionGenerator()
+
+	tests := []struct {
+		name     string
+		generate func() string
+	}{
+		{"fish", gen.GenerateFish},
+		{"bash", gen.GenerateBash},
+		{"zsh", gen.GenerateZsh},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := tt.generate()
+			assert.Contains(t, output, "grove list --fzf --sort visited")
+			assert.Contains(t, output, `grove visit "$output"`)
+		})
+	}
+}
+
 func TestFunctionGenerator_NoEmptyOutput(t *testing.T) {
 	gen := NewFunctionGenerator()
 
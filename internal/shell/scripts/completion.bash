@@ -0,0 +1 @@
+source <(grove completion bash)
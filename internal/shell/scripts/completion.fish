@@ -0,0 +1 @@
+grove completion fish | source
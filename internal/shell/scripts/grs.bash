@@ -1,11 +1,12 @@
 grs() {
     local output
-    output=$(grove list --fzf | fzf --delimiter '\t' --with-nth 2 | cut -f1)
+    output=$(grove list --fzf --sort visited | fzf --delimiter '\t' --with-nth 2 | cut -f1)
     if [ -n "$output" ]; then
         if command -v z &> /dev/null; then
             z "$output"
         else
             cd "$output"
         fi
+        grove visit "$output" &> /dev/null
     fi
 }
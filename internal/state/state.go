@@ -0,0 +1,314 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jmcampanini/grove-cli/internal/fsutil"
+)
+
+// CurrentVersion is the schema version written by this build of grove.
+//
+// Version history:
+//
+//	1: pinned flag per worktree
+//	2: origin, PR number, and creation time per worktree
+//	3: rebase conflict per worktree
+//	4: archived worktrees
+//	5: last visit per worktree
+const CurrentVersion = 5
+
+// FileName is the name of the state file inside the grove state directory.
+const FileName = "state.json"
+
+// State is grove's per-repository bookkeeping about worktrees.
+type State struct {
+	Archives  map[string]Archive  `json:"archives,omitempty"` // keyed by branch name
+	Version   int                 `json:"version"`
+	Worktrees map[string]Worktree `json:"worktrees"` // keyed by absolute worktree path
+}
+
+// Origin records which grove command created a worktree.
+type Origin string
+
+// Origins written by grove commands.
+const (
+	OriginCreate Origin = "create" // grove create
+	OriginInit   Origin = "init"   // grove init, for the default branch's worktree
+	OriginPR     Origin = "pr"     // grove pr create, checkout, or open
+)
+
+// Conflict records a rebase grove aborted because it stopped on conflicts, so grove conflicts can retry it.
+type Conflict struct {
+	At    time.Time `json:"at"`
+	Files []string  `json:"files"` // paths with conflicts, relative to the worktree
+	Onto  string    `json:"onto"`  // SHA of the commit the branch was being rebased onto
+}
+
+// IsZero reports whether no conflict is recorded.
+func (c Conflict) IsZero() bool {
+	return c.Onto == ""
+}
+
+// Worktree holds what grove knows about a single worktree.
+type Worktree struct {
+	Conflict  Conflict  `json:"conflict,omitzero"` // set while a rebase of the worktree's branch is unresolved
+	CreatedAt time.Time `json:"created_at,omitzero"`
+	Origin    Origin    `json:"origin,omitempty"`    // empty for worktrees grove did not create
+	Pinned    bool      `json:"pinned,omitempty"`    // pinned worktrees are never removed automatically
+	PRNumber  int       `json:"pr_number,omitempty"` // pull request the worktree was created from
+	VisitedAt time.Time `json:"visited_at,omitzero"` // last time grove saw the worktree entered or opened
+}
+
+// Archive records a worktree removed by grove archive, so grove archive restore can bring it back.
+// The branch's commits are kept reachable by a ref grove created, not by the state file.
+type Archive struct {
+	ArchivedAt time.Time `json:"archived_at"`
+	Path       string    `json:"path"`               // where the worktree was, and is restored to
+	SHA        string    `json:"sha"`                // commit the branch pointed at
+	Upstream   string    `json:"upstream,omitempty"` // e.g. "origin/fix", empty if the branch tracked nothing
+	Worktree   Worktree  `json:"worktree,omitzero"`  // the worktree's entry when it was archived
+}
+
+// IsZero reports whether the entry holds no information.
+func (w Worktree) IsZero() bool {
+	return w.Conflict.IsZero() && w.CreatedAt.IsZero() && w.Origin == "" && !w.Pinned && w.PRNumber == 0 &&
+		w.VisitedAt.IsZero()
+}
+
+// LastVisited returns when the worktree was last visited, falling back to when grove created it,
+// or the zero time if neither is known.
+func (w Worktree) LastVisited() time.Time {
+	if !w.VisitedAt.IsZero() {
+		return w.VisitedAt
+	}
+	return w.CreatedAt
+}
+
+// Managed reports whether grove created the worktree.
+func (w Worktree) Managed() bool {
+	return w.Origin != ""
+}
+
+// New returns an empty state at the current schema version.
+func New() State {
+	return State{Version: CurrentVersion, Worktrees: map[string]Worktree{}}
+}
+
+// Get returns the entry for a worktree path, or the zero Worktree if there is none.
+func (s State) Get(path string) Worktree {
+	return s.Worktrees[path]
+}
+
+// Set stores the entry for a worktree path, dropping it entirely when it is the zero value.
+func (s *State) Set(path string, wt Worktree) {
+	if s.Worktrees == nil {
+		s.Worktrees = map[string]Worktree{}
+	}
+	if wt.IsZero() {
+		delete(s.Worktrees, path)
+		return
+	}
+	s.Worktrees[path] = wt
+}
+
+// Move re-keys a worktree's entry after its directory moved.
+func (s *State) Move(oldPath, newPath string) {
+	wt, ok := s.Worktrees[oldPath]
+	if !ok {
+		return
+	}
+	delete(s.Worktrees, oldPath)
+	s.Set(newPath, wt)
+}
+
+// IsPinned reports whether the worktree at path is pinned.
+func (s State) IsPinned(path string) bool {
+	return s.Get(path).Pinned
+}
+
+// FindPR returns the path of a worktree created from the given pull request, if any.
+func (s State) FindPR(prNumber int) (string, bool) {
+	for path, wt := range s.Worktrees {
+		if wt.PRNumber == prNumber {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// SetArchive records the archive of a branch, replacing any previous one.
+func (s *State) SetArchive(branchName string, a Archive) {
+	if s.Archives == nil {
+		s.Archives = map[string]Archive{}
+	}
+	s.Archives[branchName] = a
+}
+
+// RemoveArchive forgets the archive of a branch.
+func (s *State) RemoveArchive(branchName string) {
+	delete(s.Archives, branchName)
+	if len(s.Archives) == 0 {
+		s.Archives = nil
+	}
+}
+
+// Prune drops entries whose worktree path is not in paths, and reports whether any were dropped.
+func (s *State) Prune(paths []string) bool {
+	keep := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		keep[p] = true
+	}
+	pruned := false
+	for path := range s.Worktrees {
+		if !keep[path] {
+			delete(s.Worktrees, path)
+			pruned = true
+		}
+	}
+	return pruned
+}
+
+// migrations upgrade a state from version N (the key) to version N+1.
+var migrations = map[int]func(*State){
+	// version 0 files predate the version field; their shape matches version 1
+	0: func(*State) {},
+	// version 2 only adds optional fields, so version 1 entries are valid as-is
+	1: func(*State) {},
+	// version 3 only adds an optional field, so version 2 entries are valid as-is
+	2: func(*State) {},
+	// version 4 only adds the optional archives, so version 3 states are valid as-is
+	3: func(*State) {},
+	// version 5 only adds an optional field, so version 4 entries are valid as-is
+	4: func(*State) {},
+}
+
+// Migrate upgrades a state to CurrentVersion.
+// Returns an error if the state was written by a newer grove.
+func Migrate(s State) (State, error) {
+	if s.Version > CurrentVersion {
+		return State{}, fmt.Errorf("state has version %d; this grove supports up to version %d", s.Version, CurrentVersion)
+	}
+	if s.Worktrees == nil {
+		s.Worktrees = map[string]Worktree{}
+	}
+	for s.Version < CurrentVersion {
+		migrations[s.Version](&s)
+		s.Version++
+	}
+	return s, nil
+}
+
+// Store loads and saves State.
+type Store interface {
+	// Load returns the stored state, or an empty state if nothing has been saved yet.
+	Load() (State, error)
+
+	// Save replaces the stored state.
+	Save(State) error
+}
+
+// FileStore persists State as JSON in a file.
+type FileStore struct {
+	fs   fsutil.FS
+	path string
+}
+
+var _ Store = &FileStore{}
+
+// NewFileStore creates a store backed by FileName inside dir on the real file system.
+// The directory is created on the first Save.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{fs: fsutil.OS{}, path: filepath.Join(dir, FileName)}
+}
+
+// WithFS replaces the file system the store reads and writes.
+func (f *FileStore) WithFS(fs fsutil.FS) *FileStore {
+	f.fs = fs
+	return f
+}
+
+// Path returns the state file path.
+func (f *FileStore) Path() string {
+	return f.path
+}
+
+func (f *FileStore) Load() (State, error) {
+	data, err := f.fs.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return New(), nil
+	}
+	if err != nil {
+		return State{}, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return State{}, fmt.Errorf("failed to parse state file %s: %w", f.path, err)
+	}
+	s, err = Migrate(s)
+	if err != nil {
+		return State{}, fmt.Errorf("failed to load state file %s: %w", f.path, err)
+	}
+	return s, nil
+}
+
+func (f *FileStore) Save(s State) error {
+	s.Version = CurrentVersion
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	if err := f.fs.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	if err := f.fs.WriteFile(f.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	return nil
+}
+
+// MemoryStore keeps State in memory, for tests and --demo.
+type MemoryStore struct {
+	mu    sync.Mutex
+	state State
+}
+
+var _ Store = &MemoryStore{}
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{state: New()}
+}
+
+func (m *MemoryStore) Load() (State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return clone(m.state), nil
+}
+
+func (m *MemoryStore) Save(s State) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s.Version = CurrentVersion
+	m.state = clone(s)
+	return nil
+}
+
+// clone copies the maps so callers cannot mutate stored state without saving.
+func clone(s State) State {
+	c := State{Version: s.Version, Worktrees: make(map[string]Worktree, len(s.Worktrees))}
+	for path, wt := range s.Worktrees {
+		c.Worktrees[path] = wt
+	}
+	for name, a := range s.Archives {
+		c.SetArchive(name, a)
+	}
+	return c
+}
@@ -0,0 +1,253 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmcampanini/grove-cli/internal/fsutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestState_SetAndMove(t *testing.T) {
+	s := New()
+
+	s.Set("/ws/wt-a", Worktree{Pinned: true})
+	assert.True(t, s.IsPinned("/ws/wt-a"))
+
+	s.Move("/ws/wt-a", "/ws/wt-b")
+	assert.False(t, s.IsPinned("/ws/wt-a"))
+	assert.True(t, s.IsPinned("/ws/wt-b"))
+
+	s.Set("/ws/wt-b", Worktree{})
+	assert.Empty(t, s.Worktrees)
+
+	s.Set("/ws/wt-c", Worktree{Conflict: Conflict{Files: []string{"a.go"}, Onto: "bbb2222"}})
+	assert.Contains(t, s.Worktrees, "/ws/wt-c")
+	s.Set("/ws/wt-c", Worktree{})
+	assert.Empty(t, s.Worktrees)
+
+	s.Move("/ws/missing", "/ws/other")
+	assert.Empty(t, s.Worktrees)
+}
+
+func TestState_FindPRAndPrune(t *testing.T) {
+	s := New()
+	s.Set("/ws/pr-7", Worktree{Origin: OriginPR, PRNumber: 7})
+	s.Set("/ws/wt-a", Worktree{Origin: OriginCreate})
+
+	path, ok := s.FindPR(7)
+	assert.True(t, ok)
+	assert.Equal(t, "/ws/pr-7", path)
+
+	_, ok = s.FindPR(8)
+	assert.False(t, ok)
+
+	assert.True(t, s.Prune([]string{"/ws/wt-a"}))
+	assert.False(t, s.Prune([]string{"/ws/wt-a"}))
+	assert.Equal(t, []string{"/ws/wt-a"}, keys(s.Worktrees))
+}
+
+func TestWorktree_LastVisited(t *testing.T) {
+	created := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	visited := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+
+	assert.True(t, Worktree{}.LastVisited().IsZero())
+	assert.Equal(t, created, Worktree{CreatedAt: created}.LastVisited())
+	assert.Equal(t, visited, Worktree{CreatedAt: created, VisitedAt: visited}.LastVisited())
+	assert.False(t, Worktree{VisitedAt: visited}.IsZero())
+}
+
+func TestState_Archives(t *testing.T) {
+	s := New()
+
+	s.SetArchive("fix", Archive{Path: "/ws/wt-fix", SHA: "ccc3333"})
+	assert.Equal(t, "/ws/wt-fix", s.Archives["fix"].Path)
+
+	s.RemoveArchive("fix")
+	assert.Nil(t, s.Archives)
+	s.RemoveArchive("missing")
+}
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      State
+		want    State
+		wantErr string
+	}{
+		{
+			name: "current version is unchanged",
+			in:   State{Version: CurrentVersion, Worktrees: map[string]Worktree{"/ws/a": {Pinned: true}}},
+			want: State{Version: CurrentVersion, Worktrees: map[string]Worktree{"/ws/a": {Pinned: true}}},
+		},
+		{
+			name: "old version is upgraded",
+			in:   State{Version: 1, Worktrees: map[string]Worktree{"/ws/a": {Pinned: true}}},
+			want: State{Version: CurrentVersion, Worktrees: map[string]Worktree{"/ws/a": {Pinned: true}}},
+		},
+		{
+			name:    "newer version is rejected",
+			in:      State{Version: CurrentVersion + 1},
+			wantErr: "this grove supports up to version",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Migrate(tt.in)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func keys(m map[string]Worktree) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
+
+func TestFileStore(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    State
+		wantErr string
+	}{
+		{
+			name: "missing file",
+			want: New(),
+		},
+		{
+			name:    "existing file",
+			content: `{"version": 5, "worktrees": {"/ws/wt-a": {"origin": "pr", "pr_number": 7, "created_at": "2024-06-01T12:00:00Z", "visited_at": "2024-06-04T12:00:00Z", "conflict": {"at": "2024-06-02T12:00:00Z", "files": ["a.go"], "onto": "bbb2222"}}}, "archives": {"fix": {"archived_at": "2024-06-03T12:00:00Z", "path": "/ws/wt-fix", "sha": "ccc3333", "upstream": "origin/fix", "worktree": {"pinned": true}}}}`,
+			want: State{Version: 5, Archives: map[string]Archive{
+				"fix": {
+					ArchivedAt: time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC),
+					Path:       "/ws/wt-fix",
+					SHA:        "ccc3333",
+					Upstream:   "origin/fix",
+					Worktree:   Worktree{Pinned: true},
+				},
+			}, Worktrees: map[string]Worktree{
+				"/ws/wt-a": {
+					Conflict:  Conflict{At: time.Date(2024, 6, 2, 12, 0, 0, 0, time.UTC), Files: []string{"a.go"}, Onto: "bbb2222"},
+					CreatedAt: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+					Origin:    OriginPR,
+					PRNumber:  7,
+					VisitedAt: time.Date(2024, 6, 4, 12, 0, 0, 0, time.UTC),
+				},
+			}},
+		},
+		{
+			name:    "version 1 file is migrated",
+			content: `{"version": 1, "worktrees": {"/ws/wt-a": {"pinned": true}}}`,
+			want:    State{Version: 5, Worktrees: map[string]Worktree{"/ws/wt-a": {Pinned: true}}},
+		},
+		{
+			name:    "version 2 file is migrated",
+			content: `{"version": 2, "worktrees": {"/ws/wt-a": {"origin": "pr", "pr_number": 7}}}`,
+			want:    State{Version: 5, Worktrees: map[string]Worktree{"/ws/wt-a": {Origin: OriginPR, PRNumber: 7}}},
+		},
+		{
+			name:    "version 3 file is migrated",
+			content: `{"version": 3, "worktrees": {"/ws/wt-a": {"conflict": {"files": ["a.go"], "onto": "bbb2222"}}}}`,
+			want:    State{Version: 5, Worktrees: map[string]Worktree{"/ws/wt-a": {Conflict: Conflict{Files: []string{"a.go"}, Onto: "bbb2222"}}}},
+		},
+		{
+			name:    "version 4 file is migrated",
+			content: `{"version": 4, "worktrees": {"/ws/wt-a": {"pinned": true}}, "archives": {"fix": {"path": "/ws/wt-fix", "sha": "ccc3333"}}}`,
+			want: State{Version: 5, Archives: map[string]Archive{"fix": {Path: "/ws/wt-fix", SHA: "ccc3333"}},
+				Worktrees: map[string]Worktree{"/ws/wt-a": {Pinned: true}}},
+		},
+		{
+			name:    "unversioned file is migrated",
+			content: `{"worktrees": null}`,
+			want:    New(),
+		},
+		{
+			name:    "newer version",
+			content: `{"version": 99, "worktrees": {}}`,
+			wantErr: "has version 99",
+		},
+		{
+			name:    "corrupt file",
+			content: `{not json`,
+			wantErr: "failed to parse state file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := fsutil.NewMemory()
+			store := NewFileStore("/repo/.git/grove").WithFS(fs)
+			if tt.content != "" {
+				fs.AddFile(store.Path(), tt.content)
+			}
+
+			got, err := store.Load()
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFileStore_SaveRoundTrip(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "grove"))
+
+	s := New()
+	s.Set("/ws/wt-a", Worktree{Pinned: true})
+	require.NoError(t, store.Save(s))
+
+	got, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, s, got)
+
+	entries, err := os.ReadDir(filepath.Dir(store.Path()))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temp files should be cleaned up")
+}
+
+func TestFileStore_SaveCreatesDirectory(t *testing.T) {
+	fs := fsutil.NewMemory()
+	store := NewFileStore("/repo/.git/grove").WithFS(fs)
+
+	require.NoError(t, store.Save(New()))
+
+	data, err := fs.ReadFile("/repo/.git/grove/state.json")
+	require.NoError(t, err)
+	assert.Equal(t, "{\n  \"version\": 5,\n  \"worktrees\": {}\n}\n", string(data))
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+
+	s, err := store.Load()
+	require.NoError(t, err)
+	s.Set("/ws/wt-a", Worktree{Pinned: true})
+
+	unsaved, err := store.Load()
+	require.NoError(t, err)
+	assert.False(t, unsaved.IsPinned("/ws/wt-a"))
+
+	require.NoError(t, store.Save(s))
+	saved, err := store.Load()
+	require.NoError(t, err)
+	assert.True(t, saved.IsPinned("/ws/wt-a"))
+}
@@ -0,0 +1,98 @@
+// Package summary records the outcome of a batch command for --summary-file, so CI jobs can archive and
+// inspect what grove did without parsing its text output.
+//
+// Like the events stream, the summary fields are a stable interface: new fields may be added, but existing
+// ones keep their meaning.
+package summary
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jmcampanini/grove-cli/internal/jsonout"
+)
+
+// Item is one unit of work a command processed, such as a remote fetched or a worktree checked.
+type Item struct {
+	Message string `json:"message,omitempty"` // human-readable detail
+	Name    string `json:"name"`
+	Repo    string `json:"repo,omitempty"` // the repository the item belongs to, with --all-repos
+	Status  string `json:"status"`         // command-specific outcome, as in item-completed events
+}
+
+// Summary is the data of a summary file.
+type Summary struct {
+	Command string         `json:"command"` // full command path, e.g. "grove sync"
+	Counts  map[string]int `json:"counts"`  // number of items per status
+	Error   string         `json:"error,omitempty"`
+	Items   []Item         `json:"items"`  // in the order they completed
+	Status  string         `json:"status"` // "ok" or "error", as the command's exit status
+}
+
+// Recorder collects items for a summary. A nil *Recorder discards them, so commands can record unconditionally.
+type Recorder struct {
+	items *items
+	repo  string
+}
+
+type items struct {
+	list []Item
+	mu   sync.Mutex
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{items: &items{}}
+}
+
+// ForRepo returns a Recorder that adds to the same summary, tagging items with the repository name.
+func (r *Recorder) ForRepo(repo string) *Recorder {
+	if r == nil {
+		return nil
+	}
+	return &Recorder{items: r.items, repo: repo}
+}
+
+// Add records that one item finished with a command-specific status.
+func (r *Recorder) Add(name, status, message string) {
+	if r == nil {
+		return
+	}
+	r.items.mu.Lock()
+	defer r.items.mu.Unlock()
+	r.items.list = append(r.items.list, Item{Message: message, Name: name, Repo: r.repo, Status: status})
+}
+
+// Summary returns the summary of command, which failed with err unless it is nil.
+func (r *Recorder) Summary(command string, err error) Summary {
+	s := Summary{Command: command, Counts: map[string]int{}, Items: []Item{}, Status: "ok"}
+	if err != nil {
+		s.Error = err.Error()
+		s.Status = "error"
+	}
+	if r == nil {
+		return s
+	}
+	r.items.mu.Lock()
+	defer r.items.mu.Unlock()
+	s.Items = append(s.Items, r.items.list...)
+	for _, item := range s.Items {
+		s.Counts[item.Status]++
+	}
+	return s
+}
+
+// WriteFile writes s to path in the JSON envelope, replacing any existing file.
+func WriteFile(path string, s Summary, warnings []jsonout.Warning, now time.Time) error {
+	var buf bytes.Buffer
+	if err := jsonout.Write(&buf, s, warnings, now); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write summary file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package summary
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTime = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder()
+	r.Add("origin", "pass", "fetched")
+	r.ForRepo("api").Add("upstream", "fail", "timed out")
+	r.Add("fork", "pass", "fetched")
+
+	got := r.Summary("grove sync", errors.New("1 of 2 repositories failed"))
+
+	assert.Equal(t, Summary{
+		Command: "grove sync",
+		Counts:  map[string]int{"fail": 1, "pass": 2},
+		Error:   "1 of 2 repositories failed",
+		Items: []Item{
+			{Message: "fetched", Name: "origin", Status: "pass"},
+			{Message: "timed out", Name: "upstream", Repo: "api", Status: "fail"},
+			{Message: "fetched", Name: "fork", Status: "pass"},
+		},
+		Status: "error",
+	}, got)
+}
+
+func TestRecorder_Nil(t *testing.T) {
+	var r *Recorder
+	r.Add("origin", "pass", "")
+	r.ForRepo("api").Add("origin", "pass", "")
+
+	assert.Equal(t, Summary{Command: "grove clean", Counts: map[string]int{}, Items: []Item{}, Status: "ok"}, r.Summary("grove clean", nil))
+}
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary.json")
+	require.NoError(t, os.WriteFile(path, []byte("stale"), 0o644))
+	r := NewRecorder()
+	r.Add("/ws/wt-old", "removed", "")
+
+	require.NoError(t, WriteFile(path, r.Summary("grove clean", nil), nil, testTime))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"data": {
+			"command": "grove clean",
+			"counts": {"removed": 1},
+			"items": [{"name": "/ws/wt-old", "status": "removed"}],
+			"status": "ok"
+		},
+		"meta": {"generated_at": "2024-06-01T12:00:00Z", "version": 1},
+		"warnings": []
+	}`, string(data))
+}
@@ -0,0 +1,39 @@
+// Package textutil measures and fits text by the terminal cells it takes up rather than its bytes or runes,
+// so wide characters such as CJK and emoji neither get cut in half nor misalign columns.
+package textutil
+
+import (
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// ellipsis marks truncated text.
+const ellipsis = "…"
+
+// Width returns the number of terminal cells s takes up. East Asian ambiguous-width characters count as
+// wide when the locale is CJK (or RUNEWIDTH_EASTASIAN=1), as terminals in those locales draw them.
+func Width(s string) int {
+	return runewidth.StringWidth(s)
+}
+
+// Truncate shortens s to at most maxWidth cells, ending with "…" when truncated. It cuts between
+// grapheme clusters, so a character is never split; the result may be a cell narrower than maxWidth
+// when a wide character does not fit.
+func Truncate(s string, maxWidth int) string {
+	if Width(s) <= maxWidth {
+		return s
+	}
+	if maxWidth <= Width(ellipsis) {
+		return runewidth.Truncate(s, maxWidth, "")
+	}
+	return runewidth.Truncate(s, maxWidth, ellipsis)
+}
+
+// PadRight appends spaces to s until it is width cells wide. Text already that wide is returned as-is.
+func PadRight(s string, width int) string {
+	if pad := width - Width(s); pad > 0 {
+		return s + strings.Repeat(" ", pad)
+	}
+	return s
+}
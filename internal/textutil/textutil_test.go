@@ -0,0 +1,60 @@
+package textutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{s: "", want: 0},
+		{s: "abc", want: 3},
+		{s: "héllo", want: 5},
+		{s: "日本語", want: 6},
+		{s: "fix 🐛", want: 6},
+		{s: "👩‍💻", want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			assert.Equal(t, tt.want, Width(tt.s))
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		maxWidth int
+		want     string
+	}{
+		{name: "shorter than max", s: "abc", maxWidth: 5, want: "abc"},
+		{name: "exactly max", s: "abcde", maxWidth: 5, want: "abcde"},
+		{name: "truncated with ellipsis", s: "abcdefgh", maxWidth: 5, want: "abcd…"},
+		{name: "multibyte runes", s: "héllo wörld", maxWidth: 6, want: "héllo…"},
+		{name: "wide characters", s: "日本語のタイトル", maxWidth: 7, want: "日本語…"},
+		{name: "wide character that does not fit", s: "日本語のタイトル", maxWidth: 8, want: "日本語…"},
+		{name: "emoji is not split", s: "👩‍💻👩‍💻👩‍💻", maxWidth: 4, want: "👩‍💻…"},
+		{name: "combining marks stay attached", s: "éééé", maxWidth: 3, want: "éé…"},
+		{name: "max of one", s: "abc", maxWidth: 1, want: "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Truncate(tt.s, tt.maxWidth)
+			assert.Equal(t, tt.want, got)
+			assert.LessOrEqual(t, Width(got), tt.maxWidth)
+		})
+	}
+}
+
+func TestPadRight(t *testing.T) {
+	assert.Equal(t, "ab  ", PadRight("ab", 4))
+	assert.Equal(t, "日本  ", PadRight("日本", 6))
+	assert.Equal(t, "abcdef", PadRight("abcdef", 4))
+}
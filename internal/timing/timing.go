@@ -0,0 +1,142 @@
+// Package timing records how long the git and gh commands grove runs take, for --timings, so a slow
+// command can be traced to the subprocesses it waits on. Nothing is sent anywhere; the report is printed.
+package timing
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Call is one run of a git or gh command.
+type Call struct {
+	Args     []string
+	Duration time.Duration
+	Failed   bool
+	Program  string // "git" or "gh"
+}
+
+// Name returns the name calls are grouped under in the report: the program and its subcommand, e.g.
+// "git for-each-ref" or "gh pr list". Git's options before the subcommand, such as -C <dir>, are skipped.
+func (c Call) Name() string {
+	words := 1
+	if c.Program == "gh" {
+		words = 2 // gh's subcommands are nested, e.g. "pr list" or "api graphql"
+	}
+	name := []string{c.Program}
+	for i := 0; i < len(c.Args) && len(name) <= words; i++ {
+		arg := c.Args[i]
+		switch {
+		case arg == "-C" || arg == "-c":
+			i++ // the option's value
+		case strings.HasPrefix(arg, "-"):
+			// an option without a value, e.g. --no-pager
+		default:
+			name = append(name, arg)
+		}
+	}
+	return strings.Join(name, " ")
+}
+
+// Recorder collects the calls made while a command runs. A nil *Recorder discards them, so clients can record
+// unconditionally.
+type Recorder struct {
+	calls []Call
+	mu    sync.Mutex
+	start time.Time
+}
+
+// NewRecorder returns an empty Recorder for a command that started at start.
+func NewRecorder(start time.Time) *Recorder {
+	return &Recorder{start: start}
+}
+
+// Record records that program ran with args for d, failing if err is not nil.
+func (r *Recorder) Record(program string, args []string, d time.Duration, err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Args: slices.Clone(args), Duration: d, Failed: err != nil, Program: program})
+}
+
+// Calls returns the calls recorded so far, in the order they finished.
+func (r *Recorder) Calls() []Call {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.calls)
+}
+
+// group is the report line for the calls with one name.
+type group struct {
+	calls   int
+	failed  int
+	name    string
+	slowest time.Duration
+	total   time.Duration
+}
+
+// Write prints the breakdown of the recorded calls to w: one line per command name, slowest in total first,
+// under a line comparing the time spent in subprocesses to the time since the command started. Commands
+// that run concurrently can take longer in total than the command itself.
+func (r *Recorder) Write(w io.Writer, now time.Time) error {
+	calls := r.Calls()
+	var elapsed time.Duration
+	if r != nil {
+		elapsed = now.Sub(r.start)
+	}
+	var total time.Duration
+	byName := map[string]*group{}
+	for _, c := range calls {
+		total += c.Duration
+		g, ok := byName[c.Name()]
+		if !ok {
+			g = &group{name: c.Name()}
+			byName[g.name] = g
+		}
+		g.calls++
+		g.total += c.Duration
+		g.slowest = max(g.slowest, c.Duration)
+		if c.Failed {
+			g.failed++
+		}
+	}
+	groups := make([]*group, 0, len(byName))
+	for _, g := range byName {
+		groups = append(groups, g)
+	}
+	slices.SortFunc(groups, func(a, b *group) int {
+		return cmp.Or(cmp.Compare(b.total, a.total), cmp.Compare(a.name, b.name))
+	})
+
+	if _, err := fmt.Fprintf(w, "Timings: %d git/gh commands took %s, in %s\n", len(calls), formatDuration(total), formatDuration(elapsed)); err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "COMMAND\tCALLS\tFAILED\tTOTAL\tSLOWEST"); err != nil {
+		return err
+	}
+	for _, g := range groups {
+		if _, err := fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\n", g.name, g.calls, g.failed, formatDuration(g.total), formatDuration(g.slowest)); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// formatDuration rounds d to the millisecond, e.g. "1.204s" or "35ms".
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
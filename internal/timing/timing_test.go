@@ -0,0 +1,67 @@
+package timing
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTime = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func TestCall_Name(t *testing.T) {
+	tests := []struct {
+		name string
+		call Call
+		want string
+	}{
+		{name: "git subcommand", call: Call{Program: "git", Args: []string{"for-each-ref", "--format=%(refname)"}}, want: "git for-each-ref"},
+		{name: "git options before the subcommand", call: Call{Program: "git", Args: []string{"-C", "/ws/.bare", "-c", "core.quotepath=off", "--no-pager", "config", "remote.origin.fetch"}}, want: "git config"},
+		{name: "gh nested subcommand", call: Call{Program: "gh", Args: []string{"pr", "view", "12", "--json", "number"}}, want: "gh pr view"},
+		{name: "gh api", call: Call{Program: "gh", Args: []string{"api", "graphql", "-f", "query=..."}}, want: "gh api graphql"},
+		{name: "no arguments", call: Call{Program: "git"}, want: "git"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.call.Name())
+		})
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder(testTime)
+	args := []string{"fetch", "origin"}
+	r.Record("git", args, 1200*time.Millisecond, errors.New("timed out"))
+	args[1] = "upstream" // the recorder keeps its own copy
+	r.Record("git", []string{"rev-parse", "HEAD"}, 4*time.Millisecond, nil)
+	r.Record("gh", []string{"pr", "list", "--json", "number"}, 2*time.Second, nil)
+	r.Record("git", []string{"fetch", "origin"}, 800*time.Millisecond, nil)
+	r.Record("git", []string{"rev-parse", "--show-toplevel"}, 3*time.Millisecond, nil)
+
+	calls := r.Calls()
+	require.Len(t, calls, 5)
+	assert.Equal(t, Call{Args: []string{"fetch", "origin"}, Duration: 1200 * time.Millisecond, Failed: true, Program: "git"}, calls[0])
+
+	var buf bytes.Buffer
+	require.NoError(t, r.Write(&buf, testTime.Add(3*time.Second)))
+	assert.Equal(t, `Timings: 5 git/gh commands took 4.007s, in 3s
+COMMAND        CALLS  FAILED  TOTAL  SLOWEST
+gh pr list     1      0       2s     2s
+git fetch      2      1       2s     1.2s
+git rev-parse  2      0       7ms    4ms
+`, buf.String())
+}
+
+func TestRecorder_Nil(t *testing.T) {
+	var r *Recorder
+	r.Record("git", []string{"status"}, time.Second, nil)
+
+	assert.Empty(t, r.Calls())
+	var buf bytes.Buffer
+	require.NoError(t, r.Write(&buf, testTime))
+	assert.Equal(t, "Timings: 0 git/gh commands took 0s, in 0s\n", buf.String())
+}